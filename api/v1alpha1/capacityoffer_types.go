@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CapacityOfferPhase is where an offer is in its lifecycle
+// +kubebuilder:validation:Enum=Open;Claimed;Settled
+type CapacityOfferPhase string
+
+const (
+	// CapacityOfferOpen offers may be claimed by other teams
+	CapacityOfferOpen CapacityOfferPhase = "Open"
+	// CapacityOfferClaimed offers are held by a claiming team
+	CapacityOfferClaimed CapacityOfferPhase = "Claimed"
+	// CapacityOfferSettled offers were released or expired after a claim, and carry the
+	// internal transfer charged for it
+	CapacityOfferSettled CapacityOfferPhase = "Settled"
+)
+
+// CapacityOfferSpec is reserved capacity a team makes available to other teams at an
+// internal price. The team is the offer's namespace.
+type CapacityOfferSpec struct {
+	// Workload is the WorkloadOptimizer in the offer's namespace whose capacity is offered
+	// +required
+	Workload string `json:"workload"`
+
+	// NodeName is the node the offered capacity is on
+	// +required
+	NodeName string `json:"nodeName"`
+
+	// CPU is the offered CPU
+	// +optional
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory is the offered memory
+	// +optional
+	Memory string `json:"memory,omitempty"`
+
+	// GPU is the number of offered GPUs
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GPU int32 `json:"gpu,omitempty"`
+
+	// NPU is the number of offered NPUs
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NPU int32 `json:"npu,omitempty"`
+
+	// PricePerHour is the internal price the claiming team pays the offering team, in the
+	// reporting currency
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	PricePerHour float64 `json:"pricePerHour"`
+
+	// ExpiresAt is when the offer, and any claim on it, ends; it never expires when unset
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// CapacityOfferStatus is the claim on an offer and, once settled, its transfer
+type CapacityOfferStatus struct {
+	// Phase is Open until a team claims the offer and Settled once the claim ended
+	// +optional
+	Phase CapacityOfferPhase `json:"phase,omitempty"`
+
+	// ClaimedBy is the namespace of the team holding the claim
+	// +optional
+	ClaimedBy string `json:"claimedBy,omitempty"`
+
+	// ClaimedAt is when the claim was made
+	// +optional
+	ClaimedAt *metav1.Time `json:"claimedAt,omitempty"`
+
+	// SettledAt is when the claim ended
+	// +optional
+	SettledAt *metav1.Time `json:"settledAt,omitempty"`
+
+	// Hours is how long the claim was held
+	// +optional
+	Hours float64 `json:"hours,omitempty"`
+
+	// Amount is the internal transfer from the claiming team to the offering team, in the
+	// reporting currency
+	// +optional
+	Amount float64 `json:"amount,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Workload",type=string,JSONPath=`.spec.workload`
+// +kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+// +kubebuilder:printcolumn:name="Price",type=number,JSONPath=`.spec.pricePerHour`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Claimed By",type=string,JSONPath=`.status.claimedBy`
+
+// CapacityOffer is the Schema for the capacityoffers API, reserved capacity a team trades
+// with other teams on the capacity marketplace
+type CapacityOffer struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the offered capacity
+	// +required
+	Spec CapacityOfferSpec `json:"spec"`
+
+	// status holds the claim on the offer
+	// +optional
+	Status CapacityOfferStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// CapacityOfferList contains a list of CapacityOffer
+type CapacityOfferList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CapacityOffer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CapacityOffer{}, &CapacityOfferList{})
+}
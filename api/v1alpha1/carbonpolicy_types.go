@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CarbonPolicySpec defines the desired state of CarbonPolicy
+type CarbonPolicySpec struct {
+	// CarbonBudget is the emissions the covered workloads may emit per calendar month in g CO2e
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	CarbonBudget float64 `json:"carbonBudget"`
+
+	// MaxCarbonPerHour is the highest combined emission rate of the covered workloads in
+	// g CO2e per hour
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxCarbonPerHour *float64 `json:"maxCarbonPerHour,omitempty"`
+
+	// NamespaceSelector selects the namespaces the policy covers; all when unset
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// WorkloadSelector selects the WorkloadOptimizers the policy covers; all when unset
+	// +optional
+	WorkloadSelector *metav1.LabelSelector `json:"workloadSelector,omitempty"`
+}
+
+// CarbonPolicyStatus defines the observed state of CarbonPolicy
+type CarbonPolicyStatus struct {
+	// Phase is Active, or Violated while the projected emissions exceed the budget or the
+	// emission rate exceeds its cap
+	// +kubebuilder:validation:Enum=Active;Violated
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// PeriodStart is the start of the current month
+	// +optional
+	PeriodStart *metav1.Time `json:"periodStart,omitempty"`
+
+	// PeriodEnd is the end of the current month
+	// +optional
+	PeriodEnd *metav1.Time `json:"periodEnd,omitempty"`
+
+	// CarbonRate is the current combined emission rate of the covered workloads in g CO2e per hour
+	// +optional
+	CarbonRate *float64 `json:"carbonRate,omitempty"`
+
+	// Emitted is the emissions of the covered workloads so far this month in g CO2e
+	// +optional
+	Emitted *float64 `json:"emitted,omitempty"`
+
+	// ProjectedEmissions is Emitted plus the current emission rate over the rest of the
+	// month, in g CO2e
+	// +optional
+	ProjectedEmissions *float64 `json:"projectedEmissions,omitempty"`
+
+	// RemainingBudget is the budget less Emitted, in g CO2e
+	// +optional
+	RemainingBudget *float64 `json:"remainingBudget,omitempty"`
+
+	// BudgetUtilization is the percentage of the budget emitted (0-100)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	BudgetUtilization *float64 `json:"budgetUtilization,omitempty"`
+
+	// Namespaces accounts the emissions of each covered namespace, highest first
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	Namespaces []NamespaceEmissions `json:"namespaces,omitempty"`
+
+	// LastUpdated is when the status was last updated
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// conditions represent the current state of the CarbonPolicy resource
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NamespaceEmissions is the emissions of the covered workloads in a namespace
+type NamespaceEmissions struct {
+	// Namespace is the namespace
+	Namespace string `json:"namespace"`
+
+	// CarbonRate is the current emission rate in g CO2e per hour
+	CarbonRate float64 `json:"carbonRate"`
+
+	// Emitted is the emissions so far this month in g CO2e
+	Emitted float64 `json:"emitted"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// CarbonPolicy is the Schema for the carbonpolicies API
+type CarbonPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of CarbonPolicy
+	// +required
+	Spec CarbonPolicySpec `json:"spec"`
+
+	// status defines the observed state of CarbonPolicy
+	// +optional
+	Status CarbonPolicyStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// CarbonPolicyList contains a list of CarbonPolicy
+type CarbonPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CarbonPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CarbonPolicy{}, &CarbonPolicyList{})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChargebackHour is the realized usage of a workload in one hour
+type ChargebackHour struct {
+	// Hour is the hour of the day, from 0 to 23 UTC
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	// +required
+	Hour int32 `json:"hour"`
+
+	// Cost is the realized cost in the record's currency
+	// +optional
+	Cost float64 `json:"cost,omitempty"`
+
+	// EnergyKWh is the realized energy in kWh
+	// +optional
+	EnergyKWh float64 `json:"energyKWh,omitempty"`
+}
+
+// ChargebackRecordSpec is the realized usage of a workload over one UTC day
+type ChargebackRecordSpec struct {
+	// Workload is the name of the WorkloadOptimizer in the record's namespace
+	// +required
+	Workload string `json:"workload"`
+
+	// Day is the start of the UTC day the usage accrued in
+	// +required
+	Day metav1.Time `json:"day"`
+
+	// Currency is the ISO 4217 code of the reporting currency the costs are in
+	// +required
+	Currency string `json:"currency"`
+
+	// Labels are the workload's labels over those of its namespace when usage last accrued
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Hours are the hours of the day with usage
+	// +optional
+	Hours []ChargebackHour `json:"hours,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Workload",type=string,JSONPath=`.spec.workload`
+// +kubebuilder:printcolumn:name="Day",type=date,JSONPath=`.spec.day`
+// +kubebuilder:printcolumn:name="Currency",type=string,JSONPath=`.spec.currency`
+
+// ChargebackRecord is the Schema for the chargebackrecords API, the persisted hourly usage
+// of a workload's day that chargeback reports are built from
+type ChargebackRecord struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec holds the recorded usage
+	// +required
+	Spec ChargebackRecordSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChargebackRecordList contains a list of ChargebackRecord
+type ChargebackRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChargebackRecord `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChargebackRecord{}, &ChargebackRecordList{})
+}
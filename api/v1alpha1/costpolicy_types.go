@@ -22,17 +22,22 @@ import (
 
 // CostPolicySpec defines the desired state of CostPolicy
 type CostPolicySpec struct {
-	// BudgetLimit defines the total budget limit in USD
+	// BudgetLimit defines the total budget limit in the operator's reporting currency
 	// +kubebuilder:validation:Minimum=0
 	// +required
 	BudgetLimit float64 `json:"budgetLimit"`
 
-	// MonthlyBudget defines the monthly budget limit in USD
+	// MonthlyBudget defines the monthly budget limit in the operator's reporting currency
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	MonthlyBudget *float64 `json:"monthlyBudget,omitempty"`
 
-	// CostPerHourLimit defines the maximum cost per hour in USD
+	// BudgetPeriod makes the budget recur each week or month, optionally rolling unused
+	// budget over; without it the budget is monthly
+	// +optional
+	BudgetPeriod *BudgetPeriod `json:"budgetPeriod,omitempty"`
+
+	// CostPerHourLimit defines the maximum cost per hour in the operator's reporting currency
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	CostPerHourLimit *float64 `json:"costPerHourLimit,omitempty"`
@@ -56,6 +61,72 @@ type CostPolicySpec struct {
 	// WorkloadSelector defines which workloads this policy applies to
 	// +optional
 	WorkloadSelector *metav1.LabelSelector `json:"workloadSelector,omitempty"`
+
+	// OptimizationInterval is how often covered workloads that set no interval of their own
+	// are re-optimized, as a duration such as "15m" or "once"
+	// +kubebuilder:validation:Pattern=`^(once|([0-9]+(\.[0-9]+)?(ms|s|m|h))+)$`
+	// +optional
+	OptimizationInterval string `json:"optimizationInterval,omitempty"`
+
+	// AutoApply applies recommendations for covered workloads without review once they are
+	// confident enough
+	// +optional
+	AutoApply *AutoApplyPolicy `json:"autoApply,omitempty"`
+}
+
+// Recommendation kinds a CostPolicy may auto-apply
+const (
+	// AutoApplyRightsizing sets the workload's resources to the rightsizing recommendation
+	AutoApplyRightsizing = "Rightsizing"
+	// AutoApplyReplicas scales the workload's controllers to the replica count predicted to
+	// meet its SLA
+	AutoApplyReplicas = "Replicas"
+	// AutoApplyQoS has the pod webhook give new pods the request and limit shape recommended
+	// for the workload's type
+	AutoApplyQoS = "QoS"
+	// AutoApplyCPUFrequency has node agents switch nodes running only such workloads to
+	// the CPU frequency governor recommended for their types
+	AutoApplyCPUFrequency = "CPUFrequency"
+)
+
+// AutoApplyPolicy selects the recommendations applied without review
+type AutoApplyPolicy struct {
+	// MinConfidence is the confidence, from 0 to 1, a recommendation needs to be applied
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +required
+	MinConfidence float64 `json:"minConfidence"`
+
+	// Types limits auto-apply to these kinds of recommendation; empty applies all of them
+	// +kubebuilder:validation:items:Enum=Rightsizing;Replicas;QoS;CPUFrequency
+	// +optional
+	Types []string `json:"types,omitempty"`
+}
+
+// Budget periods of a CostPolicy
+const (
+	BudgetPeriodMonthly = "Monthly"
+	BudgetPeriodWeekly  = "Weekly"
+)
+
+// BudgetPeriod is the recurring period of a CostPolicy's budget. The budget of a monthly
+// period is MonthlyBudget, or BudgetLimit when it is not set; that of a weekly period is
+// BudgetLimit.
+type BudgetPeriod struct {
+	// Period is how long each budget lasts; weeks start on Monday
+	// +kubebuilder:validation:Enum=Monthly;Weekly
+	// +kubebuilder:default=Monthly
+	// +optional
+	Period string `json:"period,omitempty"`
+
+	// Rollover carries the budget left unused at the end of a period into the next one
+	// +optional
+	Rollover bool `json:"rollover,omitempty"`
+
+	// MaxRollover caps the budget carried into a period, in the operator's reporting currency
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRollover *float64 `json:"maxRollover,omitempty"`
 }
 
 // SpotInstancePolicy defines the policy for spot instances
@@ -129,14 +200,52 @@ type CostPolicyStatus struct {
 	// +optional
 	Phase string `json:"phase,omitempty"`
 
-	// CurrentSpend represents the current spend in USD
+	// Currency is the ISO 4217 code of the currency the status's spend is in
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
+	// CurrentSpend represents the current spend in Currency
 	// +optional
 	CurrentSpend *float64 `json:"currentSpend,omitempty"`
 
-	// MonthlySpend represents the current monthly spend in USD
+	// MonthlySpend represents the current monthly spend in Currency
 	// +optional
 	MonthlySpend *float64 `json:"monthlySpend,omitempty"`
 
+	// PeriodStart is when the current budget period started
+	// +optional
+	PeriodStart *metav1.Time `json:"periodStart,omitempty"`
+
+	// PeriodEnd is when the current budget period ends and the budget resets
+	// +optional
+	PeriodEnd *metav1.Time `json:"periodEnd,omitempty"`
+
+	// ConsumedBudget is the spend of the current budget period in Currency
+	// +optional
+	ConsumedBudget *float64 `json:"consumedBudget,omitempty"`
+
+	// RemainingBudget is the budget of the current period, including any rollover, less
+	// ConsumedBudget in Currency; it is negative once the budget is overspent
+	// +optional
+	RemainingBudget *float64 `json:"remainingBudget,omitempty"`
+
+	// RolloverBudget is the budget carried into the current period from the previous one
+	// in Currency
+	// +optional
+	RolloverBudget *float64 `json:"rolloverBudget,omitempty"`
+
+	// TotalSavings is what optimization actions saved the covered workloads in the current
+	// budget period in Currency
+	// +optional
+	TotalSavings *float64 `json:"totalSavings,omitempty"`
+
+	// Savings splits TotalSavings by optimization action
+	// +listType=map
+	// +listMapKey=action
+	// +kubebuilder:validation:MaxItems=4
+	// +optional
+	Savings []PeriodSavings `json:"savings,omitempty"`
+
 	// BudgetUtilization represents the budget utilization percentage (0-100)
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=100
@@ -151,6 +260,21 @@ type CostPolicyStatus struct {
 	// +optional
 	Violations *int32 `json:"violations,omitempty"`
 
+	// ProjectedMonthlyCost is the month-to-date spend plus the forecast spend for the
+	// rest of the month in Currency
+	// +optional
+	ProjectedMonthlyCost *float64 `json:"projectedMonthlyCost,omitempty"`
+
+	// NamespaceForecasts projects the spend of each namespace the policy covers
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	NamespaceForecasts []SpendForecast `json:"namespaceForecasts,omitempty"`
+
+	// WorkloadForecasts projects the spend of the workloads with the highest projections
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	WorkloadForecasts []SpendForecast `json:"workloadForecasts,omitempty"`
+
 	// conditions represent the current state of the CostPolicy resource
 	// +listType=map
 	// +listMapKey=type
@@ -158,6 +282,31 @@ type CostPolicyStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// PeriodSavings is what an optimization action saved in the current budget period
+type PeriodSavings struct {
+	// Action is Rightsizing, Consolidation, Spot or IdleScaleDown
+	Action string `json:"action"`
+
+	// Savings is the cost saved in the policy's Currency
+	Savings float64 `json:"savings"`
+}
+
+// SpendForecast projects the spend of a namespace or workload for the current month
+type SpendForecast struct {
+	// Namespace of the spend
+	Namespace string `json:"namespace"`
+
+	// Workload is the WorkloadOptimizer name; empty for a namespace forecast
+	// +optional
+	Workload string `json:"workload,omitempty"`
+
+	// MonthToDateSpend is the spend observed so far this month
+	MonthToDateSpend float64 `json:"monthToDateSpend"`
+
+	// ProjectedMonthlyCost is the month-to-date spend plus the forecast for the rest of the month
+	ProjectedMonthlyCost float64 `json:"projectedMonthlyCost"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
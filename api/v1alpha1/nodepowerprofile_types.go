@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodePowerProfileSpec defines the desired state of NodePowerProfile
+type NodePowerProfileSpec struct {
+	// PoolName identifies the node pool described by this profile
+	// +required
+	PoolName string `json:"poolName"`
+
+	// Location indicates whether the pool runs in a public cloud or on-premises
+	// +kubebuilder:validation:Enum=cloud;on-prem
+	// +kubebuilder:default=cloud
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// NodeSelector selects the nodes that belong to this pool
+	// +required
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// EnergyMix describes where the pool's electricity comes from
+	// +required
+	EnergyMix EnergyMix `json:"energyMix"`
+
+	// PUE is the power usage effectiveness of the pool's datacenter, the ratio of facility power
+	// to IT power. Power and carbon reported for workloads on the pool include this overhead
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PUE *float64 `json:"pue,omitempty"`
+}
+
+// EnergyMix describes the share of each energy source supplying a node pool
+type EnergyMix struct {
+	// GridPercentage is the share of energy drawn from the utility grid (0-100)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	GridPercentage float64 `json:"gridPercentage,omitempty"`
+
+	// GridRenewablePercentage is the renewable share of the grid supply itself (0-100)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	GridRenewablePercentage float64 `json:"gridRenewablePercentage,omitempty"`
+
+	// OnSiteSolarPercentage is the share of energy produced by on-site solar (0-100)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	OnSiteSolarPercentage float64 `json:"onSiteSolarPercentage,omitempty"`
+
+	// PPAPercentage is the share of energy covered by renewable power purchase agreements (0-100)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	PPAPercentage float64 `json:"ppaPercentage,omitempty"`
+
+	// GridCarbonIntensity is the carbon intensity of non-renewable grid energy in g CO2 per kWh
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=500
+	// +optional
+	GridCarbonIntensity float64 `json:"gridCarbonIntensity,omitempty"`
+}
+
+// NodePowerProfileStatus defines the observed state of NodePowerProfile
+type NodePowerProfileStatus struct {
+	// MatchedNodes is the number of nodes currently selected by this profile
+	// +optional
+	MatchedNodes *int32 `json:"matchedNodes,omitempty"`
+
+	// RenewableFraction is the effective renewable share of the pool's energy (0.0-1.0)
+	// +optional
+	RenewableFraction *float64 `json:"renewableFraction,omitempty"`
+
+	// LastUpdated represents the last time the status was updated
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// conditions represent the current state of the NodePowerProfile resource
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// NodePowerProfile is the Schema for the nodepowerprofiles API
+type NodePowerProfile struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of NodePowerProfile
+	// +required
+	Spec NodePowerProfileSpec `json:"spec"`
+
+	// status defines the observed state of NodePowerProfile
+	// +optional
+	Status NodePowerProfileStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodePowerProfileList contains a list of NodePowerProfile
+type NodePowerProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodePowerProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodePowerProfile{}, &NodePowerProfileList{})
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Optimization plan phases
+const (
+	// PlanPhasePending plans wait for approval
+	PlanPhasePending = "Pending"
+	// PlanPhaseApplying plans are moving pods one step at a time
+	PlanPhaseApplying = "Applying"
+	// PlanPhaseApplied plans moved every pod
+	PlanPhaseApplied = "Applied"
+	// PlanPhaseAborted plans were stopped and their cordons lifted because a step failed;
+	// pods already moved are not moved back
+	PlanPhaseAborted = "Aborted"
+	// PlanPhaseRollingBack plans whose realized metrics regressed are moving the pods of
+	// their steps back to the nodes they left, last step first
+	PlanPhaseRollingBack = "RollingBack"
+	// PlanPhaseRolledBack plans moved the pods of every step back
+	PlanPhaseRolledBack = "RolledBack"
+)
+
+// PlanMove moves a pod off a node the plan drains
+type PlanMove struct {
+	// Namespace of the pod
+	Namespace string `json:"namespace"`
+
+	// Pod is the name of the pod to move
+	Pod string `json:"pod"`
+
+	// FromNode is the node the pod runs on
+	FromNode string `json:"fromNode"`
+
+	// ToNode is the node the plan expects the pod's replacement to land on
+	ToNode string `json:"toNode"`
+}
+
+// PlanStep is a step of moves a plan applied, which a rollback moves back
+type PlanStep struct {
+	// Namespace of the moved pods
+	Namespace string `json:"namespace"`
+
+	// OwnerUID is the controller of the moved pods; empty when they had none, so nothing
+	// replaced them
+	// +optional
+	OwnerUID types.UID `json:"ownerUID,omitempty"`
+
+	// FromNodes are the nodes the pods were moved off
+	FromNodes []string `json:"fromNodes"`
+
+	// Moves is how many pods the step moved
+	Moves int32 `json:"moves"`
+
+	// StartedAt is when the pods were evicted; their replacements were created after
+	StartedAt metav1.Time `json:"startedAt"`
+}
+
+// OptimizationPlanSpec lists the moves of a consolidation and what they are expected to save
+type OptimizationPlanSpec struct {
+	// Approved lets the plan be applied; plans are never applied before
+	// +optional
+	Approved bool `json:"approved,omitempty"`
+
+	// DrainNodes are the nodes the plan empties; they are cordoned while it is applied
+	// +required
+	DrainNodes []string `json:"drainNodes"`
+
+	// Moves are the pod migrations, in the order they are applied
+	// +optional
+	Moves []PlanMove `json:"moves,omitempty"`
+
+	// Currency is the ISO 4217 code of the currency the savings are in
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
+	// ExpectedHourlySavings is the estimated cost per hour of the drained nodes in Currency
+	// +optional
+	ExpectedHourlySavings float64 `json:"expectedHourlySavings,omitempty"`
+
+	// ExpectedMonthlySavings is ExpectedHourlySavings over 30 days in Currency
+	// +optional
+	ExpectedMonthlySavings float64 `json:"expectedMonthlySavings,omitempty"`
+
+	// ExpectedPowerSavings is the estimated base power draw of the drained nodes in Watts
+	// +optional
+	ExpectedPowerSavings float64 `json:"expectedPowerSavings,omitempty"`
+
+	// Confidence is how far the plan can be trusted, from 0 to 1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	Confidence float64 `json:"confidence,omitempty"`
+
+	// MaxScoreRegression is how far the average optimization score of the workloads with
+	// moved pods may fall below its value before the first move; a larger drop aborts the
+	// plan. Defaults to 0.1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	MaxScoreRegression *float64 `json:"maxScoreRegression,omitempty"`
+
+	// MaxCostIncrease is the fraction by which the current cost of the workloads with moved
+	// pods may rise above its value before the first move; a larger rise aborts the
+	// plan. Defaults to 0.1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxCostIncrease *float64 `json:"maxCostIncrease,omitempty"`
+
+	// MaxLatencyIncrease is the fraction by which the highest predicted p99 latency of those
+	// workloads may rise above its value before the first move. Defaults to 0.2
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxLatencyIncrease *float64 `json:"maxLatencyIncrease,omitempty"`
+
+	// CanaryFraction is the share of a workload's replicas moved in one step. Consecutive
+	// moves of pods with the same controller are grouped into steps of this size, and each
+	// step is evaluated before the next starts. Defaults to 0.25
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	CanaryFraction *float64 `json:"canaryFraction,omitempty"`
+
+	// StepTimeout is how long the pods of a step may take to be replaced by running pods,
+	// and their workloads to be evaluated again, before the plan moves on or is aborted.
+	// Defaults to 5m
+	// +optional
+	StepTimeout *metav1.Duration `json:"stepTimeout,omitempty"`
+
+	// GeneratedAt is when the plan was computed
+	// +required
+	GeneratedAt metav1.Time `json:"generatedAt"`
+}
+
+// OptimizationPlanStatus is the progress of an approved plan
+type OptimizationPlanStatus struct {
+	// Phase is Pending, Applying, Applied, Aborted, RollingBack or RolledBack
+	// +kubebuilder:validation:Enum=Pending;Applying;Applied;Aborted;RollingBack;RolledBack
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// CompletedSteps is how many moves have been applied
+	// +optional
+	CompletedSteps int32 `json:"completedSteps,omitempty"`
+
+	// StepMoves is how many moves, from CompletedSteps on, the step in progress applies
+	// +optional
+	StepMoves int32 `json:"stepMoves,omitempty"`
+
+	// StepStartedAt is when the pods of the step in progress, or of the step being rolled
+	// back, were evicted
+	// +optional
+	StepStartedAt *metav1.Time `json:"stepStartedAt,omitempty"`
+
+	// StepReplacedAt is when every pod of the step in progress was replaced; the step
+	// completes once its workloads were evaluated after that
+	// +optional
+	StepReplacedAt *metav1.Time `json:"stepReplacedAt,omitempty"`
+
+	// StepOwnerUID is the controller of the pods of the step in progress, whose
+	// replacements the step waits for
+	// +optional
+	StepOwnerUID types.UID `json:"stepOwnerUID,omitempty"`
+
+	// Steps are the steps applied, and the one in progress when the plan started rolling
+	// back, in order
+	// +optional
+	Steps []PlanStep `json:"steps,omitempty"`
+
+	// RolledBackSteps is how many steps, from the last, were moved back
+	// +optional
+	RolledBackSteps int32 `json:"rolledBackSteps,omitempty"`
+
+	// RollbackStartedAt is when the realized metrics regressed and the plan started rolling back
+	// +optional
+	RollbackStartedAt *metav1.Time `json:"rollbackStartedAt,omitempty"`
+
+	// Workloads are the WorkloadOptimizers, as namespace/name, whose pods the plan moves
+	// +optional
+	Workloads []string `json:"workloads,omitempty"`
+
+	// CordonedNodes are the drained nodes the plan cordoned; aborting or rolling back the plan
+	// uncordons them
+	// +optional
+	CordonedNodes []string `json:"cordonedNodes,omitempty"`
+
+	// BaselineScore is the average optimization score of the workloads with moved pods
+	// before the first move
+	// +optional
+	BaselineScore *float64 `json:"baselineScore,omitempty"`
+
+	// RealizedScore is their average optimization score after the latest step
+	// +optional
+	RealizedScore *float64 `json:"realizedScore,omitempty"`
+
+	// BaselineCost is the summed current cost of those workloads before the first move
+	// +optional
+	BaselineCost *float64 `json:"baselineCost,omitempty"`
+
+	// RealizedCost is their summed current cost after the latest step
+	// +optional
+	RealizedCost *float64 `json:"realizedCost,omitempty"`
+
+	// BaselineLatencyP99 is the highest predicted p99 latency of those workloads before
+	// the first move
+	// +optional
+	BaselineLatencyP99 *metav1.Duration `json:"baselineLatencyP99,omitempty"`
+
+	// RealizedLatencyP99 is their highest predicted p99 latency after the latest step
+	// +optional
+	RealizedLatencyP99 *metav1.Duration `json:"realizedLatencyP99,omitempty"`
+
+	// Message explains the latest transition, such as the reason the plan was aborted or
+	// rolled back
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartedAt is when the plan began to be applied
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is when the plan was applied, aborted or rolled back
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Approved",type=boolean,JSONPath=`.spec.approved`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Steps",type=integer,JSONPath=`.status.completedSteps`
+// +kubebuilder:printcolumn:name="Monthly Savings",type=number,JSONPath=`.spec.expectedMonthlySavings`
+// +kubebuilder:printcolumn:name="Confidence",type=number,JSONPath=`.spec.confidence`
+
+// OptimizationPlan is the Schema for the optimizationplans API, a set of pod moves the
+// operator proposes and applies step by step once approved
+type OptimizationPlan struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec holds the proposed moves; it cannot change once the plan is approved
+	// +kubebuilder:validation:XValidation:rule="!has(oldSelf.approved) || !oldSelf.approved || self == oldSelf",message="spec is immutable once the plan is approved"
+	// +required
+	Spec OptimizationPlanSpec `json:"spec"`
+
+	// status tracks how far the plan was applied
+	// +optional
+	Status OptimizationPlanStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// OptimizationPlanList contains a list of OptimizationPlan
+type OptimizationPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OptimizationPlan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OptimizationPlan{}, &OptimizationPlanList{})
+}
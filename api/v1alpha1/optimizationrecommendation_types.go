@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Recommendation types
+const (
+	// RecommendationTypeRightsizing recommends resource requests from observed usage
+	RecommendationTypeRightsizing = "Rightsizing"
+	// RecommendationTypeRecurrence recommends declaring the run schedule detected from a
+	// workload's arrivals
+	RecommendationTypeRecurrence = "Recurrence"
+)
+
+// RecommendedResources is a set of per-pod resource requests
+type RecommendedResources struct {
+	// CPU request, in the WorkloadOptimizer resources format
+	CPU string `json:"cpu"`
+
+	// Memory request, in the WorkloadOptimizer resources format
+	Memory string `json:"memory"`
+
+	// GPU count
+	// +optional
+	GPU int32 `json:"gpu,omitempty"`
+}
+
+// OptimizationRecommendationSpec describes a change users may apply to a WorkloadOptimizer
+type OptimizationRecommendationSpec struct {
+	// WorkloadOptimizer is the name of the WorkloadOptimizer in the same namespace
+	// +required
+	WorkloadOptimizer string `json:"workloadOptimizer"`
+
+	// Type is the kind of recommendation
+	// +kubebuilder:validation:Enum=Rightsizing;Recurrence
+	// +required
+	Type string `json:"type"`
+
+	// Current is the resources the workload requests today, for Rightsizing recommendations
+	// +optional
+	Current RecommendedResources `json:"current,omitzero"`
+
+	// Recommended is the resources the workload should request, for Rightsizing recommendations
+	// +optional
+	Recommended RecommendedResources `json:"recommended,omitzero"`
+
+	// Recurrence is the run schedule detected from the workload's arrivals, for Recurrence
+	// recommendations
+	// +optional
+	Recurrence *RecurrenceSpec `json:"recurrence,omitempty"`
+
+	// Window is the usage or arrival history the recommendation is based on, such as 168h0m0s
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// EstimatedMonthlySavings is the monthly cost difference per pod in Currency; negative
+	// when the workload needs more than it requests
+	// +optional
+	EstimatedMonthlySavings float64 `json:"estimatedMonthlySavings,omitempty"`
+
+	// Currency is the ISO 4217 code of the currency the savings are in
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
+	// Confidence is how far the history the recommendation is based on can be trusted, from
+	// 0 to 1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	Confidence float64 `json:"confidence,omitempty"`
+
+	// GeneratedAt is when the recommendation was computed
+	// +required
+	GeneratedAt metav1.Time `json:"generatedAt"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Workload",type=string,JSONPath=`.spec.workloadOptimizer`
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="CPU",type=string,JSONPath=`.spec.recommended.cpu`
+// +kubebuilder:printcolumn:name="Memory",type=string,JSONPath=`.spec.recommended.memory`
+// +kubebuilder:printcolumn:name="GPU",type=integer,JSONPath=`.spec.recommended.gpu`
+// +kubebuilder:printcolumn:name="Monthly Savings",type=number,JSONPath=`.spec.estimatedMonthlySavings`
+// +kubebuilder:printcolumn:name="Confidence",type=number,JSONPath=`.spec.confidence`
+
+// OptimizationRecommendation is the Schema for the optimizationrecommendations API, a
+// suggested change to a WorkloadOptimizer that users review and apply themselves
+type OptimizationRecommendation struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec holds the recommendation
+	// +required
+	Spec OptimizationRecommendationSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// OptimizationRecommendationList contains a list of OptimizationRecommendation
+type OptimizationRecommendationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OptimizationRecommendation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OptimizationRecommendation{}, &OptimizationRecommendationList{})
+}
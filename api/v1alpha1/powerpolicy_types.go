@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -51,8 +52,127 @@ type PowerPolicySpec struct {
 	// WorkloadSelector defines which workloads this policy applies to
 	// +optional
 	WorkloadSelector *metav1.LabelSelector `json:"workloadSelector,omitempty"`
+
+	// Enforcement throttles or pauses the lowest-priority covered workloads while their combined
+	// power exceeds MaxPowerUsage; without it the policy only reports violations
+	// +optional
+	Enforcement *PowerEnforcement `json:"enforcement,omitempty"`
+
+	// StandbyReservations keep headroom free on selected nodes that only the covered workloads
+	// may use, so latency-critical bursts are placed without waiting for the cluster to scale up
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	StandbyReservations []StandbyReservation `json:"standbyReservations,omitempty"`
+}
+
+// StandbyReservation keeps headroom free on each node it selects
+type StandbyReservation struct {
+	// Name identifies the reservation within the policy
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// NodeSelector picks the nodes that keep the headroom; empty selects every node
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Headroom is kept free on each selected node, such as nvidia.com/gpu: 2
+	// +required
+	Headroom corev1.ResourceList `json:"headroom"`
+}
+
+// Power enforcement actions and the states of the workloads they were applied to
+const (
+	// PowerEnforcementThrottle scales workloads down to one replica, pausing those already at one
+	PowerEnforcementThrottle = "throttle"
+	// PowerEnforcementPause scales workloads to zero replicas and suspends their Jobs
+	PowerEnforcementPause = "pause"
+
+	// EnforcedThrottled workloads were scaled down to one replica
+	EnforcedThrottled = "Throttled"
+	// EnforcedPaused workloads were scaled to zero
+	EnforcedPaused = "Paused"
+)
+
+// PowerEnforcement defines how a PowerPolicy brings its workloads back under MaxPowerUsage
+type PowerEnforcement struct {
+	// Action taken on the lowest-priority workloads until power is under the cap
+	// +kubebuilder:validation:Enum=throttle;pause
+	// +kubebuilder:default=pause
+	// +optional
+	Action string `json:"action,omitempty"`
+
+	// ExemptPriority exempts workloads at or above this priority from enforcement
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ExemptPriority *int32 `json:"exemptPriority,omitempty"`
+
+	// NodePowerCap caps the power of the nodes running covered workloads through the node
+	// agent once no workload is left to throttle or pause
+	// +optional
+	NodePowerCap *NodePowerCap `json:"nodePowerCap,omitempty"`
+}
+
+// NodePowerCap defines how far a PowerPolicy may lower node power limits
+type NodePowerCap struct {
+	// MinPercent is the lowest cap, in percent of the nodes' default CPU package and GPU
+	// power limits
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=99
+	// +kubebuilder:default=50
+	// +optional
+	MinPercent int32 `json:"minPercent,omitempty"`
+}
+
+// NodePowerCapStatus is the power cap a PowerPolicy requested on nodes
+type NodePowerCapStatus struct {
+	// Percent of the nodes' default power limits requested
+	Percent int32 `json:"percent"`
+
+	// Nodes the cap was requested on
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+
+	// Since is when the cap was last changed
+	Since metav1.Time `json:"since"`
+}
+
+// EnforcedWorkload is a workload a PowerPolicy throttled or paused
+type EnforcedWorkload struct {
+	// Namespace of the WorkloadOptimizer
+	Namespace string `json:"namespace"`
+
+	// Name of the WorkloadOptimizer
+	Name string `json:"name"`
+
+	// State is Throttled or Paused
+	// +kubebuilder:validation:Enum=Throttled;Paused
+	State string `json:"state"`
+
+	// Power is the workload's power in Watts before enforcement
+	Power float64 `json:"power"`
+
+	// Since is when the workload was throttled or paused
+	Since metav1.Time `json:"since"`
+
+	// ScaledTargets are the controllers scaled down, with the replicas to restore
+	// +optional
+	ScaledTargets []ScaledTarget `json:"scaledTargets,omitempty"`
 }
 
+const (
+	// GreenEnergyPreferenceRenewable starts flexible workloads when on-site renewable
+	// generation is forecast to cover the policy's workloads
+	GreenEnergyPreferenceRenewable = "renewable"
+	// GreenEnergyPreferenceLowCarbon prefers low-carbon energy without holding workloads
+	GreenEnergyPreferenceLowCarbon = "low-carbon"
+	// GreenEnergyPreferenceAny accepts any energy source
+	GreenEnergyPreferenceAny = "any"
+)
+
 // GreenEnergyPolicy defines the policy for green energy usage
 type GreenEnergyPolicy struct {
 	// Enabled indicates whether green energy preference is enabled
@@ -69,6 +189,23 @@ type GreenEnergyPolicy struct {
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	CarbonFootprintTarget *float64 `json:"carbonFootprintTarget,omitempty"`
+
+	// SelectPoolsByRenewableFraction restricts green workloads to node pools whose
+	// NodePowerProfile renewable fraction meets MinGreenEnergyPercentage
+	// +optional
+	SelectPoolsByRenewableFraction bool `json:"selectPoolsByRenewableFraction,omitempty"`
+
+	// Preference is the energy source preferred; renewable holds new batch and training
+	// workloads until forecast on-site generation covers MinGreenEnergyPercentage of the
+	// power of the covered workloads
+	// +kubebuilder:validation:Enum=renewable;low-carbon;any
+	// +kubebuilder:default=any
+	// +optional
+	Preference string `json:"preference,omitempty"`
+
+	// MaxDelay is how long the renewable preference holds a workload at most; defaults to 12h
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
 }
 
 // PowerSchedulingPolicy defines the policy for power-aware scheduling
@@ -147,6 +284,15 @@ type PowerPolicyStatus struct {
 	// +optional
 	Violations *int32 `json:"violations,omitempty"`
 
+	// Enforced are the workloads throttled or paused to keep power under MaxPowerUsage
+	// +optional
+	Enforced []EnforcedWorkload `json:"enforced,omitempty"`
+
+	// NodePowerCap is the power cap requested on nodes once throttling and pausing were
+	// not enough
+	// +optional
+	NodePowerCap *NodePowerCapStatus `json:"nodePowerCap,omitempty"`
+
 	// conditions represent the current state of the PowerPolicy resource
 	// +listType=map
 	// +listMapKey=type
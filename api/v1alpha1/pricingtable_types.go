@@ -0,0 +1,284 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultGPUModelLabel is the node label GPU feature discovery publishes the GPU model in
+const DefaultGPUModelLabel = "nvidia.com/gpu.product"
+
+// PricingTableSpec defines the hourly rates cluster resources are priced at, in place of the
+// operator's defaults. Unset rates keep their defaults.
+type PricingTableSpec struct {
+	// Per-resource rates of CPU, memory, GPUs and NPUs
+	ResourceCostPolicy `json:",inline"`
+
+	// BaseCostPerHour is the fixed infrastructure cost added to every workload per hour in USD
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BaseCostPerHour *float64 `json:"baseCostPerHour,omitempty"`
+
+	// GPUModelLabel is the node label naming the GPU model GPUModels are matched against
+	// +kubebuilder:default="nvidia.com/gpu.product"
+	// +optional
+	GPUModelLabel string `json:"gpuModelLabel,omitempty"`
+
+	// GPUModels prices the GPUs of nodes with a given model in place of gpuCostPerHour
+	// +listType=map
+	// +listMapKey=model
+	// +optional
+	GPUModels []GPUModelPrice `json:"gpuModels,omitempty"`
+
+	// NodePrices prices whole nodes by their labels, such as the amortized cost of a server
+	// class. Replicas on a matching node pay their share of its price instead of the
+	// per-resource rates; the first matching entry applies.
+	// +optional
+	NodePrices []NodeLabelPrice `json:"nodePrices,omitempty"`
+
+	// ElectricityTariff adds the cost of the energy workloads draw
+	// +optional
+	ElectricityTariff *ElectricityTariff `json:"electricityTariff,omitempty"`
+
+	// Commitments price the nodes covered by reserved instances, savings plans and committed
+	// use discounts at their effective rate rather than on-demand. A node is covered by the
+	// first commitment selecting it that has capacity left, oldest nodes first.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Commitments []Commitment `json:"commitments,omitempty"`
+
+	// Storage prices persistent volumes by capacity
+	// +optional
+	Storage *StoragePricing `json:"storage,omitempty"`
+
+	// Network prices internet egress traffic
+	// +optional
+	Network *NetworkPricing `json:"network,omitempty"`
+}
+
+// StoragePricing is the monthly rate of persistent volume capacity
+type StoragePricing struct {
+	// CostPerGiBMonth is the cost of one GiB for a month in USD, for storage classes not
+	// listed in StorageClasses
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CostPerGiBMonth *float64 `json:"costPerGiBMonth,omitempty"`
+
+	// StorageClasses prices the volumes of a storage class
+	// +listType=map
+	// +listMapKey=storageClassName
+	// +optional
+	StorageClasses []StorageClassPrice `json:"storageClasses,omitempty"`
+}
+
+// StorageClassPrice is the monthly rate of the volumes of a storage class
+type StorageClassPrice struct {
+	// StorageClassName is the name of the storage class
+	// +required
+	StorageClassName string `json:"storageClassName"`
+
+	// CostPerGiBMonth is the cost of one GiB for a month in USD
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	CostPerGiBMonth float64 `json:"costPerGiBMonth"`
+}
+
+// NetworkPricing is the rate of traffic leaving the cloud provider's network
+type NetworkPricing struct {
+	// EgressCostPerGB is the cost of one GB sent to the internet in USD
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	EgressCostPerGB *float64 `json:"egressCostPerGB,omitempty"`
+}
+
+// GPUModelPrice is the hourly rate of one GPU of a model
+type GPUModelPrice struct {
+	// Model is the value of the GPU model label, such as NVIDIA-A100-SXM4-80GB
+	// +required
+	Model string `json:"model"`
+
+	// CostPerHour is the cost of one GPU per hour in USD
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	CostPerHour float64 `json:"costPerHour"`
+}
+
+// NodeLabelPrice is the hourly price of the nodes with the given labels, set directly or
+// amortized from what the hardware cost
+type NodeLabelPrice struct {
+	// NodeSelector selects the nodes priced by this entry
+	// +required
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// CostPerHour is the cost of one node per hour in USD; ignored when Amortization is set
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CostPerHour float64 `json:"costPerHour,omitempty"`
+
+	// Amortization prices owned hardware from its purchase price spread over its lifetime
+	// +optional
+	Amortization *HardwareAmortization `json:"amortization,omitempty"`
+}
+
+// HardwareAmortization spreads the capital cost of an owned node over the hours it is used.
+// Its hourly rate is the purchase price less the residual value, divided by the lifetime in
+// hours times the expected utilization, plus the operating cost.
+type HardwareAmortization struct {
+	// PurchasePrice is what one node cost in USD, including its share of racks and network
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	PurchasePrice float64 `json:"purchasePrice"`
+
+	// LifetimeMonths is how long the node is depreciated over
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	LifetimeMonths int32 `json:"lifetimeMonths"`
+
+	// ResidualValue is what the node is expected to be worth at the end of its lifetime in USD
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ResidualValue *float64 `json:"residualValue,omitempty"`
+
+	// Utilization is the expected share of its lifetime the node runs workloads, so idle
+	// hours are paid for by the used ones; defaults to 1.0
+	// +kubebuilder:validation:Minimum=0.01
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	Utilization *float64 `json:"utilization,omitempty"`
+
+	// OperatingCostPerHour is the cost of running one node other than its energy, such as
+	// support, space and cooling, in USD
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	OperatingCostPerHour *float64 `json:"operatingCostPerHour,omitempty"`
+}
+
+// ElectricityTariff is the price of the energy drawn by workloads
+type ElectricityTariff struct {
+	// CostPerKWh is the price of one kilowatt-hour in USD
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	CostPerKWh float64 `json:"costPerKWh"`
+}
+
+// CommitmentType is the kind of a pricing commitment
+// +kubebuilder:validation:Enum=ReservedInstance;SavingsPlan;CommittedUse
+type CommitmentType string
+
+const (
+	// CommitmentReservedInstance covers a number of instances at a reserved rate
+	CommitmentReservedInstance CommitmentType = "ReservedInstance"
+	// CommitmentSavingsPlan covers an hourly spend at a discount
+	CommitmentSavingsPlan CommitmentType = "SavingsPlan"
+	// CommitmentCommittedUse covers a number of nodes at a discount
+	CommitmentCommittedUse CommitmentType = "CommittedUse"
+)
+
+// Commitment is a reserved instance, savings plan or committed use discount covering nodes.
+// A covered node is priced at EffectiveCostPerHour if set, otherwise at its on-demand price
+// less DiscountPercent.
+type Commitment struct {
+	// Name identifies the commitment
+	// +required
+	Name string `json:"name"`
+
+	// Type is the kind of commitment
+	// +required
+	Type CommitmentType `json:"type"`
+
+	// NodeSelector selects the nodes the commitment can cover, such as by instance type and
+	// region; empty selects every node
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Nodes is how many nodes a reserved instance or committed use discount covers; unset
+	// covers every selected node
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Nodes *int32 `json:"nodes,omitempty"`
+
+	// HourlyCommitment is the discounted spend per hour in USD a savings plan covers; spend
+	// beyond it is charged on-demand
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	HourlyCommitment *float64 `json:"hourlyCommitment,omitempty"`
+
+	// DiscountPercent is the discount off a covered node's on-demand price
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	DiscountPercent *float64 `json:"discountPercent,omitempty"`
+
+	// EffectiveCostPerHour is the price of a covered node per hour in USD, such as a reserved
+	// instance's rate with its upfront payment amortized
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	EffectiveCostPerHour *float64 `json:"effectiveCostPerHour,omitempty"`
+}
+
+// PricingTableStatus defines the observed state of PricingTable
+type PricingTableStatus struct {
+	// Active reports whether this is the table the operator prices resources with
+	// +optional
+	Active bool `json:"active,omitempty"`
+
+	// ObservedGeneration is the generation of the spec last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastUpdated represents the last time the status was updated
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Active",type="boolean",JSONPath=".status.active"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PricingTable is the Schema for the pricingtables API
+type PricingTable struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of PricingTable
+	// +required
+	Spec PricingTableSpec `json:"spec"`
+
+	// status defines the observed state of PricingTable
+	// +optional
+	Status PricingTableStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// PricingTableList contains a list of PricingTable
+type PricingTableList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PricingTable `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PricingTable{}, &PricingTableList{})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SchedulingRecordSpec records a single scheduling decision
+type SchedulingRecordSpec struct {
+	// Timestamp is when the decision was made
+	// +required
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// WorkloadID identifies the scheduled workload
+	// +required
+	WorkloadID string `json:"workloadID"`
+
+	// WorkloadNamespace is the namespace of the scheduled workload
+	// +optional
+	WorkloadNamespace string `json:"workloadNamespace,omitempty"`
+
+	// NodeName is the selected node, empty when scheduling failed
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Algorithm is the scheduling algorithm that produced the decision
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// Stage is the position of Algorithm in the policy's fallback chain
+	// +optional
+	Stage int32 `json:"stage,omitempty"`
+
+	// Score is the decision score (0.0-1.0)
+	// +optional
+	Score float64 `json:"score,omitempty"`
+
+	// EstimatedCost is the estimated cost per hour on the selected node
+	// +optional
+	EstimatedCost float64 `json:"estimatedCost,omitempty"`
+
+	// EstimatedPower is the estimated power draw in watts on the selected node
+	// +optional
+	EstimatedPower float64 `json:"estimatedPower,omitempty"`
+
+	// DurationMilliseconds is how long scheduling took
+	// +optional
+	DurationMilliseconds int64 `json:"durationMilliseconds,omitempty"`
+
+	// Success indicates whether a node was selected
+	Success bool `json:"success"`
+
+	// Reason explains the outcome
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Workload",type=string,JSONPath=`.spec.workloadID`
+// +kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+// +kubebuilder:printcolumn:name="Algorithm",type=string,JSONPath=`.spec.algorithm`
+// +kubebuilder:printcolumn:name="Time",type=date,JSONPath=`.spec.timestamp`
+
+// SchedulingRecord is the Schema for the schedulingrecords API, a durable entry of the
+// scheduler's decision history
+type SchedulingRecord struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec holds the recorded decision
+	// +required
+	Spec SchedulingRecordSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// SchedulingRecordList contains a list of SchedulingRecord
+type SchedulingRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SchedulingRecord `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SchedulingRecord{}, &SchedulingRecordList{})
+}
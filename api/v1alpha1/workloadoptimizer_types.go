@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -56,6 +57,182 @@ type WorkloadOptimizerSpec struct {
 	// AutoScaling defines auto-scaling configuration
 	// +optional
 	AutoScaling *AutoScalingSpec `json:"autoScaling,omitempty"`
+
+	// Images lists the container images the workload runs, so nodes that already cache
+	// them can be preferred
+	// +optional
+	Images []string `json:"images,omitempty"`
+
+	// Recurrence declares that the workload runs on a fixed schedule so placement can be
+	// computed ahead of each run
+	// +optional
+	Recurrence *RecurrenceSpec `json:"recurrence,omitempty"`
+
+	// DoNotDisturb exempts the workload from descheduling and migration until the lease
+	// expires, e.g. for its final training epochs
+	// +optional
+	DoNotDisturb *DoNotDisturbLease `json:"doNotDisturb,omitempty"`
+
+	// ConstraintRelaxation opts into best-effort placement: nodes whose estimated cost, power
+	// or carbon exceeds the caps are avoided, and when none fits the caps are widened in order
+	// +optional
+	ConstraintRelaxation *ConstraintRelaxation `json:"constraintRelaxation,omitempty"`
+
+	// OptimizationInterval is how often the workload is re-optimized, as a duration such as
+	// "15m", or "once" to optimize it only when its spec changes. Defaults to the interval of
+	// the first matching CostPolicy, then to 5m
+	// +kubebuilder:validation:Pattern=`^(once|([0-9]+(\.[0-9]+)?(ms|s|m|h))+)$`
+	// +optional
+	OptimizationInterval string `json:"optimizationInterval,omitempty"`
+
+	// SLA sets latency and throughput targets for inference workloads; placements and
+	// replica counts predicted to miss them are avoided even when cheaper
+	// +optional
+	SLA *SLATargets `json:"sla,omitempty"`
+
+	// Deadline is when a batch or training workload must complete. In deadline scheduling
+	// mode pending pods are ordered earliest deadline first, and faster but pricier nodes
+	// are preferred when the deadline is at risk
+	// +optional
+	Deadline *DeadlineSpec `json:"deadline,omitempty"`
+
+	// Egress is the traffic the workload sends to the internet, charged at the pricing
+	// table's egress rate
+	// +optional
+	Egress *EgressSpec `json:"egress,omitempty"`
+
+	// TimeShift holds a batch or training workload while the grid's carbon intensity or
+	// electricity price is above a threshold, running it in time to meet its deadline
+	// +optional
+	TimeShift *TimeShiftSpec `json:"timeShift,omitempty"`
+}
+
+const (
+	// TimeShiftSignalCarbonIntensity waits out carbon intensity, in g CO2 per kWh
+	TimeShiftSignalCarbonIntensity = "CarbonIntensity"
+	// TimeShiftSignalElectricityPrice waits out the electricity price, in USD per kWh
+	TimeShiftSignalElectricityPrice = "ElectricityPrice"
+
+	// TimeShiftStateRunning lets the workload run
+	TimeShiftStateRunning = "Running"
+	// TimeShiftStateWaiting holds the workload's new pods
+	TimeShiftStateWaiting = "Waiting"
+	// TimeShiftStatePaused holds new pods and suspends the workload's controllers
+	TimeShiftStatePaused = "Paused"
+)
+
+// TimeShiftSpec is when a flexible workload may run
+type TimeShiftSpec struct {
+	// Signal is what the workload waits out
+	// +kubebuilder:validation:Enum=CarbonIntensity;ElectricityPrice
+	// +kubebuilder:default=CarbonIntensity
+	// +optional
+	Signal string `json:"signal,omitempty"`
+
+	// Threshold is the signal value at or below which the workload runs, in g CO2 per kWh or
+	// USD per kWh
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	Threshold float64 `json:"threshold"`
+
+	// Pause suspends the workload's Jobs, or scales its other controllers to zero, when the
+	// signal rises above the threshold while it runs. Otherwise only pods not yet started wait.
+	// +optional
+	Pause bool `json:"pause,omitempty"`
+
+	// SafetyMargin is how long before the latest start that meets the deadline the workload
+	// runs regardless of the signal; defaults to 1h
+	// +optional
+	SafetyMargin *metav1.Duration `json:"safetyMargin,omitempty"`
+
+	// MinRunTime is how long the workload runs once released before the signal may hold or
+	// pause it again, so a signal hovering around the threshold does not flap it; defaults to 30m
+	// +optional
+	MinRunTime *metav1.Duration `json:"minRunTime,omitempty"`
+}
+
+// EgressSpec is the traffic a workload sends out of the cloud provider's network
+type EgressSpec struct {
+	// GBPerHour is the traffic across all replicas. When unset it is the workload's observed
+	// transmit rate less the transfer declared to its data endpoints
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GBPerHour *float64 `json:"gbPerHour,omitempty"`
+}
+
+// DeadlineSpec is when a workload must complete and how long it runs
+type DeadlineSpec struct {
+	// CompleteBy is when the workload must complete
+	// +required
+	CompleteBy metav1.Time `json:"completeBy"`
+
+	// ExpectedDuration is how long the workload runs on a node of relative speed 1.0; nodes
+	// are only weighed against the deadline when it is set
+	// +optional
+	ExpectedDuration *metav1.Duration `json:"expectedDuration,omitempty"`
+}
+
+// SLATargets are the service level targets of an inference workload
+type SLATargets struct {
+	// LatencyP99 is the highest acceptable 99th percentile request latency
+	// +optional
+	LatencyP99 *metav1.Duration `json:"latencyP99,omitempty"`
+
+	// Throughput is the request rate, in requests per second across all replicas, the
+	// workload must sustain
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Throughput *float64 `json:"throughput,omitempty"`
+}
+
+// ConstraintRelaxation lists the steps that widen cost, power and carbon caps when no node fits
+type ConstraintRelaxation struct {
+	// Steps are applied cumulatively, in order, until a node fits
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=10
+	// +required
+	Steps []RelaxationStep `json:"steps"`
+}
+
+// RelaxationStep widens one cap by a share of its original value
+type RelaxationStep struct {
+	// Constraint is the cap to widen
+	// +kubebuilder:validation:Enum=cost;power;carbon
+	// +required
+	Constraint string `json:"constraint"`
+
+	// Percent is how much to widen the cap, as a percentage of its original value
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +required
+	Percent int32 `json:"percent"`
+}
+
+// DoNotDisturbLease is a time-bound exemption from descheduling
+type DoNotDisturbLease struct {
+	// Until is when the lease expires
+	// +required
+	Until metav1.Time `json:"until"`
+
+	// Reason explains the exemption in decision explanations and audit logs
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// RecurrenceSpec defines a fixed-interval run schedule
+type RecurrenceSpec struct {
+	// StartTime is the time of the first run; later runs follow every Interval
+	// +required
+	StartTime metav1.Time `json:"startTime"`
+
+	// Interval is the time between runs
+	// +required
+	Interval metav1.Duration `json:"interval"`
+
+	// LeadTime is how long before each run the placement is precomputed (default 5m)
+	// +optional
+	LeadTime *metav1.Duration `json:"leadTime,omitempty"`
 }
 
 // ResourceRequirements defines the resource requirements for a workload
@@ -76,18 +253,69 @@ type ResourceRequirements struct {
 	// +optional
 	GPU int32 `json:"gpu,omitempty"`
 
+	// GPUFraction requests a share of a single physical GPU, such as 0.25, placed on a
+	// device other fractional workloads may share; mutually exclusive with GPU and MIGProfile
+	// +kubebuilder:validation:Pattern=`^0?\.[0-9]{1,3}$`
+	// +optional
+	GPUFraction string `json:"gpuFraction,omitempty"`
+
+	// MIGProfile requests one MIG instance of the given profile, such as 1g.5gb;
+	// mutually exclusive with GPU and GPUFraction
+	// +kubebuilder:validation:Pattern=^[1-7]g\.[0-9]+gb$
+	// +optional
+	MIGProfile string `json:"migProfile,omitempty"`
+
+	// GPUMemory is the GPU memory the workload needs on each GPU it uses. Workloads
+	// sharing a GPU are only placed together while their memory fits the card.
+	// +kubebuilder:validation:Pattern=^[0-9]+(E|P|T|G|M|K|Ei|Pi|Ti|Gi|Mi|Ki)?$
+	// +optional
+	GPUMemory string `json:"gpuMemory,omitempty"`
+
 	// NPU resource requirement
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=16
 	// +optional
 	NPU int32 `json:"npu,omitempty"`
+
+	// Accelerators requests accelerators registered with the operator by logical type,
+	// such as tpu or fpga, in addition to GPUs and NPUs
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	Accelerators []AcceleratorRequest `json:"accelerators,omitempty"`
+
+	// Storage is the persistent volume capacity the workload claims, priced until its
+	// claims are bound
+	// +kubebuilder:validation:Pattern=^[0-9]+(E|P|T|G|M|K|Ei|Pi|Ti|Gi|Mi|Ki)?$
+	// +optional
+	Storage string `json:"storage,omitempty"`
+
+	// StorageClassName is the storage class Storage is priced at; defaults to the
+	// pricing table's default storage rate
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+}
+
+// AcceleratorRequest asks for a number of devices of a registered accelerator type
+type AcceleratorRequest struct {
+	// Type is the logical accelerator type in the operator's accelerator registry
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +required
+	Type string `json:"type"`
+
+	// Count is the number of devices
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +required
+	Count int32 `json:"count"`
 }
 
 // CostConstraints defines cost-related constraints and policies
 type CostConstraints struct {
-	// MaxCostPerHour defines the maximum cost per hour in USD
+	// MaxCostPerHour defines the maximum cost per hour in the operator's reporting currency,
+	// up to the equivalent of 10,000 USD
 	// +kubebuilder:validation:Minimum=0
-	// +kubebuilder:validation:Maximum=10000
 	// +required
 	MaxCostPerHour float64 `json:"maxCostPerHour"`
 
@@ -95,9 +323,9 @@ type CostConstraints struct {
 	// +optional
 	PreferSpot bool `json:"preferSpot,omitempty"`
 
-	// BudgetLimit defines the total budget limit in USD
+	// BudgetLimit defines the total budget limit in the operator's reporting currency, up to
+	// the equivalent of 1,000,000 USD
 	// +kubebuilder:validation:Minimum=0
-	// +kubebuilder:validation:Maximum=1000000
 	// +optional
 	BudgetLimit *float64 `json:"budgetLimit,omitempty"`
 }
@@ -113,6 +341,16 @@ type PowerConstraints struct {
 	// PreferGreen indicates whether to prefer green energy sources
 	// +optional
 	PreferGreen bool `json:"preferGreen,omitempty"`
+
+	// MaxCarbonPerHour is the highest acceptable emission rate in g CO2e per hour
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxCarbonPerHour *float64 `json:"maxCarbonPerHour,omitempty"`
+
+	// CarbonBudget is the emissions allowed per calendar month in g CO2e
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CarbonBudget *float64 `json:"carbonBudget,omitempty"`
 }
 
 // PlacementPolicy defines node placement and affinity rules
@@ -124,6 +362,47 @@ type PlacementPolicy struct {
 	// Affinity defines node affinity rules
 	// +optional
 	Affinity []AffinityRule `json:"affinity,omitempty"`
+
+	// PodAffinity co-locates the workload with running pods matching its terms
+	// +optional
+	PodAffinity *corev1.PodAffinity `json:"podAffinity,omitempty"`
+
+	// PodAntiAffinity keeps the workload away from running pods matching its terms
+	// +optional
+	PodAntiAffinity *corev1.PodAntiAffinity `json:"podAntiAffinity,omitempty"`
+
+	// Tolerations allow the workload onto nodes with matching taints
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// DataEndpoints are services or datasets in known zones the workload exchanges data with;
+	// cost-optimized placement charges the data transfer to nodes in other zones
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	DataEndpoints []DataEndpoint `json:"dataEndpoints,omitempty"`
+}
+
+// DataEndpoint is a service or dataset the workload transfers data to or from
+type DataEndpoint struct {
+	// Name identifies the service or dataset
+	// +required
+	Name string `json:"name"`
+
+	// Zone is the topology.kubernetes.io/zone the endpoint is in
+	// +required
+	Zone string `json:"zone"`
+
+	// Region is the topology.kubernetes.io/region the endpoint is in; when empty, transfer
+	// from any other zone is priced as cross-zone
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// TransferGBPerHour is the data exchanged with the endpoint in GB per hour
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	TransferGBPerHour float64 `json:"transferGBPerHour"`
 }
 
 // AffinityRule defines a single affinity rule
@@ -186,14 +465,27 @@ type WorkloadOptimizerStatus struct {
 	// +optional
 	Phase string `json:"phase,omitempty"`
 
-	// CurrentCost represents the current cost per hour in USD
+	// CurrentCost represents the current cost per hour in Currency
 	// +optional
 	CurrentCost *float64 `json:"currentCost,omitempty"`
 
+	// Currency is the ISO 4217 code of the currency the status's costs are in
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
 	// CurrentPower represents the current power usage in Watts
 	// +optional
 	CurrentPower *float64 `json:"currentPower,omitempty"`
 
+	// FacilityPower is CurrentPower including the datacenter overhead given by the PUE of the
+	// workload's node pool
+	// +optional
+	FacilityPower *float64 `json:"facilityPower,omitempty"`
+
+	// GPUPower is the power in Watts the GPUs of the running pods were measured drawing
+	// +optional
+	GPUPower *float64 `json:"gpuPower,omitempty"`
+
 	// AssignedNode represents the currently assigned node
 	// +optional
 	AssignedNode *string `json:"assignedNode,omitempty"`
@@ -204,10 +496,29 @@ type WorkloadOptimizerStatus struct {
 	// +optional
 	OptimizationScore *float64 `json:"optimizationScore,omitempty"`
 
+	// ScoreBreakdown shows the components of the optimization score and the weights used
+	// +optional
+	ScoreBreakdown *ScoreBreakdown `json:"scoreBreakdown,omitempty"`
+
+	// CostBreakdown splits CurrentCost into compute, storage and network cost
+	// +optional
+	CostBreakdown *CostBreakdown `json:"costBreakdown,omitempty"`
+
+	// PodCosts is the cost of each running pod of the workload, the first 100 by name
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	PodCosts []PodCost `json:"podCosts,omitempty"`
+
 	// LastOptimizationTime represents the last time optimization was performed
 	// +optional
 	LastOptimizationTime *metav1.Time `json:"lastOptimizationTime,omitempty"`
 
+	// ObservedGeneration is the spec generation the last optimization was based on
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Replicas represents the current number of replicas
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
@@ -216,6 +527,88 @@ type WorkloadOptimizerStatus struct {
 	// +optional
 	ReadyReplicas *int32 `json:"readyReplicas,omitempty"`
 
+	// RenewableEnergyShare represents the realized renewable share of the workload's energy (0.0-1.0)
+	// +optional
+	RenewableEnergyShare *float64 `json:"renewableEnergyShare,omitempty"`
+
+	// CarbonFootprint represents the current carbon footprint in kg CO2 per hour
+	// +optional
+	CarbonFootprint *float64 `json:"carbonFootprint,omitempty"`
+
+	// Emissions accounts the workload's emissions in the current calendar month
+	// +optional
+	Emissions *EmissionsAccount `json:"emissions,omitempty"`
+
+	// PrecomputedPlacement is the placement computed ahead of the next recurring run
+	// +optional
+	PrecomputedPlacement *PrecomputedPlacement `json:"precomputedPlacement,omitempty"`
+
+	// PlacementAnalysis explains why the workload could not be placed, when placement analysis is enabled
+	// +optional
+	PlacementAnalysis *PlacementAnalysis `json:"placementAnalysis,omitempty"`
+
+	// RelaxedConstraints lists the caps widened to place the workload, empty when every cap held
+	// +optional
+	RelaxedConstraints []RelaxedConstraint `json:"relaxedConstraints,omitempty"`
+
+	// CandidateNodes lists the highest scoring nodes of the last placement, best first,
+	// so the choice can be understood without the scheduler logs
+	// +kubebuilder:validation:MaxItems=5
+	// +optional
+	CandidateNodes []CandidateNode `json:"candidateNodes,omitempty"`
+
+	// ParetoFront lists non-dominated placement and replica options across cost, power and
+	// performance, when Pareto optimization is enabled
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	ParetoFront []ParetoOption `json:"paretoFront,omitempty"`
+
+	// Simulation is the what-if evaluation of a workload annotated kcloud.io/dry-run=true
+	// +optional
+	Simulation *Simulation `json:"simulation,omitempty"`
+
+	// Idle is set while the workload's pods have stayed near zero utilization for the idle period
+	// +optional
+	Idle *IdleDetection `json:"idle,omitempty"`
+
+	// Rightsizing holds resource requests recommended from the workload's observed usage
+	// +optional
+	Rightsizing *Rightsizing `json:"rightsizing,omitempty"`
+
+	// Savings is the estimated cost per hour saved by each optimization action in effect
+	// on the workload
+	// +listType=map
+	// +listMapKey=action
+	// +kubebuilder:validation:MaxItems=3
+	// +optional
+	Savings []ActionSavings `json:"savings,omitempty"`
+
+	// GPUPacking is set while measured GPU utilization lets new pods share a GPU instead of holding a whole card
+	// +optional
+	GPUPacking *GPUPacking `json:"gpuPacking,omitempty"`
+
+	// SLA is the predicted latency and capacity of the optimized placement, for workloads with SLA targets
+	// +optional
+	SLA *SLAPrediction `json:"sla,omitempty"`
+
+	// ArrivalPattern records when the workload's runs started and the daily or weekly
+	// pattern detected in them
+	// +optional
+	ArrivalPattern *ArrivalPattern `json:"arrivalPattern,omitempty"`
+
+	// QoS is the request and limit shape recommended for the workload's pods by its type
+	// +optional
+	QoS *QoSRecommendation `json:"qos,omitempty"`
+
+	// CPUFrequency is the CPU frequency governor recommended for the workload's type, with
+	// its estimated power savings and performance impact
+	// +optional
+	CPUFrequency *CPUFrequencyRecommendation `json:"cpuFrequency,omitempty"`
+
+	// TimeShift is whether a time-shifted workload runs or waits for its signal to drop
+	// +optional
+	TimeShift *TimeShiftStatus `json:"timeShift,omitempty"`
+
 	// conditions represent the current state of the WorkloadOptimizer resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
@@ -233,6 +626,505 @@ type WorkloadOptimizerStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// PrecomputedPlacement records the node chosen ahead of a recurring run
+type PrecomputedPlacement struct {
+	// RunTime is the scheduled run the placement was computed for
+	RunTime metav1.Time `json:"runTime"`
+
+	// NodeName is the node reserved for the run
+	NodeName string `json:"nodeName"`
+
+	// Score is the scheduling score of the node when the placement was computed
+	// +optional
+	Score float64 `json:"score,omitempty"`
+
+	// EstimatedCost is the projected cost per hour on the reserved node
+	// +optional
+	EstimatedCost float64 `json:"estimatedCost,omitempty"`
+
+	// ValidUntil is when the reservation lapses if the run has not been submitted
+	ValidUntil metav1.Time `json:"validUntil"`
+}
+
+// CandidateNode records how a node scored when the workload was placed
+type CandidateNode struct {
+	// Node is the candidate node name
+	Node string `json:"node"`
+
+	// Score is the node's overall scheduling score
+	Score float64 `json:"score"`
+
+	// Selected is true for the node the workload was placed on
+	// +optional
+	Selected bool `json:"selected,omitempty"`
+
+	// EstimatedCost is the estimated cost per hour on the node
+	// +optional
+	EstimatedCost float64 `json:"estimatedCost,omitempty"`
+
+	// EstimatedPower is the estimated power usage in Watts on the node
+	// +optional
+	EstimatedPower float64 `json:"estimatedPower,omitempty"`
+
+	// Scores maps each scoring criterion, such as resource, cost, power and placement,
+	// to its weighted share of Score
+	// +optional
+	Scores map[string]float64 `json:"scores,omitempty"`
+}
+
+// ParetoOption is a placement and replica count no other option beats on every objective
+type ParetoOption struct {
+	// Node is the node the replicas would run on
+	Node string `json:"node"`
+
+	// Replicas is the number of replicas
+	Replicas int32 `json:"replicas"`
+
+	// EstimatedCost is the estimated cost per hour across the replicas
+	EstimatedCost float64 `json:"estimatedCost"`
+
+	// EstimatedPower is the estimated power usage in Watts across the replicas
+	EstimatedPower float64 `json:"estimatedPower"`
+
+	// Performance is the number of replicas the node can run at their full request
+	Performance float64 `json:"performance"`
+
+	// Score is the weighted value the option was ranked by (0.0-1.0)
+	Score float64 `json:"score"`
+
+	// Selected is true for the option the workload was optimized to
+	// +optional
+	Selected bool `json:"selected,omitempty"`
+}
+
+// Simulation reports how a dry-run workload would fare on the current cluster
+type Simulation struct {
+	// Feasible is true when some node can run the workload
+	Feasible bool `json:"feasible"`
+
+	// NodeName is the node the workload would likely be placed on
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Reason explains the placement, or why no node can run the workload
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// EstimatedCost is the estimated cost per hour
+	EstimatedCost float64 `json:"estimatedCost"`
+
+	// EstimatedPower is the estimated power usage in Watts
+	EstimatedPower float64 `json:"estimatedPower"`
+
+	// CarbonFootprint is the estimated carbon footprint in kg CO2 per hour on the likely node
+	CarbonFootprint float64 `json:"carbonFootprint"`
+
+	// Replicas is the recommended number of replicas
+	Replicas int32 `json:"replicas"`
+
+	// SimulatedAt is when the simulation ran
+	SimulatedAt metav1.Time `json:"simulatedAt"`
+}
+
+// Optimization actions whose savings are tracked
+const (
+	// SavingsActionRightsizing is an applied rightsizing recommendation
+	SavingsActionRightsizing = "Rightsizing"
+	// SavingsActionConsolidation is an applied OptimizationPlan that moved the workload's pods
+	SavingsActionConsolidation = "Consolidation"
+	// SavingsActionSpot is placement on spot capacity
+	SavingsActionSpot = "Spot"
+	// SavingsActionIdleScaleDown is an idle workload scaled to zero
+	SavingsActionIdleScaleDown = "IdleScaleDown"
+)
+
+// ActionSavings is the estimated cost per hour an optimization action in effect saves
+type ActionSavings struct {
+	// Action is Rightsizing, Spot or IdleScaleDown
+	// +kubebuilder:validation:Enum=Rightsizing;Spot;IdleScaleDown
+	Action string `json:"action"`
+
+	// HourlySavings is the estimated cost per hour saved in Currency; negative when the
+	// action raised the cost, such as rightsizing up
+	HourlySavings float64 `json:"hourlySavings"`
+
+	// Since is when the action took effect
+	Since metav1.Time `json:"since"`
+}
+
+// IdleDetection records a workload found idle and what was done about it
+type IdleDetection struct {
+	// Since is when the workload was first found idle
+	Since metav1.Time `json:"since"`
+
+	// CPUUsage is the P99 CPU usage of the busiest pod over the idle period, in cores
+	CPUUsage float64 `json:"cpuUsage"`
+
+	// GPUUsage is the P99 GPU utilization of the busiest pod over the idle period, in whole GPUs
+	// +optional
+	GPUUsage float64 `json:"gpuUsage,omitempty"`
+
+	// EstimatedHourlySavings is the workload's current cost per hour, saved while it is scaled to zero
+	EstimatedHourlySavings float64 `json:"estimatedHourlySavings"`
+
+	// EstimatedMonthlySavings is EstimatedHourlySavings over 30 days
+	EstimatedMonthlySavings float64 `json:"estimatedMonthlySavings"`
+
+	// Action is Recommended, or ScaledToZero when the operator scaled the workload down
+	// +kubebuilder:validation:Enum=Recommended;ScaledToZero
+	Action string `json:"action"`
+
+	// ScaledTargets are the controllers scaled to zero or suspended, with their previous replicas
+	// +optional
+	ScaledTargets []ScaledTarget `json:"scaledTargets,omitempty"`
+}
+
+// TimeShiftStatus is the state of a time-shifted workload
+type TimeShiftStatus struct {
+	// State is Running, Waiting while new pods are held, or Paused while the workload's
+	// controllers are suspended too
+	// +kubebuilder:validation:Enum=Running;Waiting;Paused
+	State string `json:"state"`
+
+	// Since is when the workload entered the state
+	Since metav1.Time `json:"since"`
+
+	// Reason explains the state
+	Reason string `json:"reason"`
+
+	// Signal is the current value of the signal, or the on-site renewable generation in kW
+	// for a PowerPolicy's renewable preference; unset when none is known
+	// +optional
+	Signal *float64 `json:"signal,omitempty"`
+
+	// Policy is the PowerPolicy whose renewable preference holds the workload; empty for
+	// spec.timeShift
+	// +optional
+	Policy string `json:"policy,omitempty"`
+
+	// LatestStart is when the workload runs regardless of the signal, to meet its deadline or
+	// once a renewable preference has held it for its maxDelay
+	// +optional
+	LatestStart *metav1.Time `json:"latestStart,omitempty"`
+
+	// NextWindow is when the forecast next has the signal at or below the threshold, or
+	// renewable generation covering the demand
+	// +optional
+	NextWindow *metav1.Time `json:"nextWindow,omitempty"`
+
+	// PausedTargets are the controllers suspended or scaled to zero while paused, with their
+	// previous replicas
+	// +optional
+	PausedTargets []ScaledTarget `json:"pausedTargets,omitempty"`
+}
+
+// EmissionsAccount is the emissions of a workload accrued over a calendar month
+type EmissionsAccount struct {
+	// PeriodStart is the start of the month
+	PeriodStart metav1.Time `json:"periodStart"`
+
+	// LastAccrued is when emissions were last added
+	LastAccrued metav1.Time `json:"lastAccrued"`
+
+	// Emitted is the emissions so far this month in g CO2e
+	Emitted float64 `json:"emitted"`
+
+	// Projected is Emitted plus the current emission rate over the rest of the month, in g CO2e
+	Projected float64 `json:"projected"`
+
+	// BudgetUtilization is the percentage of the carbon budget emitted, when one is set
+	// +optional
+	BudgetUtilization *float64 `json:"budgetUtilization,omitempty"`
+}
+
+// ScaledTarget is a pod controller scaled to zero or suspended
+type ScaledTarget struct {
+	// Kind is Deployment, StatefulSet, ReplicaSet or Job
+	Kind string `json:"kind"`
+
+	// Name of the controller in the workload's namespace
+	Name string `json:"name"`
+
+	// Replicas is the replica count before scaling; unset for suspended Jobs
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// CostBreakdown is the hourly cost of a workload by component, in the status's Currency
+type CostBreakdown struct {
+	// Compute is the cost of the CPU, memory and accelerators the workload runs on
+	Compute float64 `json:"compute"`
+
+	// GPU is the part of Compute paid for GPUs, at the rate of the node's GPU model
+	// +optional
+	GPU float64 `json:"gpu,omitempty"`
+
+	// Storage is the cost of the workload's persistent volumes
+	Storage float64 `json:"storage"`
+
+	// Network is the cost of the workload's cross-zone, cross-region and internet traffic
+	Network float64 `json:"network"`
+}
+
+// PodCost is the hourly cost of one of the workload's pods, in the status's Currency
+type PodCost struct {
+	// Name is the name of the pod
+	Name string `json:"name"`
+
+	// Node is the node the pod runs on
+	// +optional
+	Node string `json:"node,omitempty"`
+
+	// CostPerHour is the pod's estimated compute cost per hour, or what it was charged when
+	// Realized
+	CostPerHour float64 `json:"costPerHour"`
+
+	// Realized is true when CostPerHour is what the pod was charged, such as by OpenCost
+	// +optional
+	Realized bool `json:"realized,omitempty"`
+}
+
+// ScoreBreakdown explains the optimization score: the weighted average of the cost, power
+// and utilization components less the constraint penalty
+type ScoreBreakdown struct {
+	// Cost is 1.0 within spec.costConstraints.maxCostPerHour and falls as it is exceeded
+	Cost float64 `json:"cost"`
+
+	// Power is 1.0 within spec.powerConstraints.maxPowerUsage and falls as it is exceeded
+	Power float64 `json:"power"`
+
+	// Utilization is the share of the CPU and memory requests used at P95, 1.0 before usage is measured
+	Utilization float64 `json:"utilization"`
+
+	// ConstraintPenalty is subtracted from the weighted average for each constraint exceeded
+	ConstraintPenalty float64 `json:"constraintPenalty"`
+
+	// Weights are the weights of the cost, power and utilization components
+	Weights ScoreWeights `json:"weights"`
+}
+
+// ScoreWeights weigh the components of the optimization score
+type ScoreWeights struct {
+	Cost        float64 `json:"cost"`
+	Power       float64 `json:"power"`
+	Utilization float64 `json:"utilization"`
+}
+
+// Rightsizing records per-pod requests recommended from P95 usage over a window
+type Rightsizing struct {
+	// CPUP95 is the P95 CPU usage of the busiest pod, in cores
+	CPUP95 float64 `json:"cpuP95"`
+
+	// MemoryP95 is the P95 working set memory of the busiest pod, in GiB
+	MemoryP95 float64 `json:"memoryP95"`
+
+	// GPUP95 is the P95 GPU utilization of the busiest pod, in whole GPUs
+	// +optional
+	GPUP95 float64 `json:"gpuP95,omitempty"`
+
+	// Recommended is the per-pod requests, P95 usage plus headroom
+	Recommended RecommendedResources `json:"recommended"`
+
+	// EstimatedMonthlySavings is the monthly cost difference per pod
+	EstimatedMonthlySavings float64 `json:"estimatedMonthlySavings"`
+
+	// Window is the usage history the recommendation is based on
+	Window string `json:"window"`
+
+	// Confidence is how far the usage history can be trusted, from 0 to 1, by how much of
+	// the window it covers and how much CPU usage varied
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	Confidence float64 `json:"confidence"`
+
+	// ComputedAt is when usage was last queried
+	ComputedAt metav1.Time `json:"computedAt"`
+}
+
+// SLAPrediction is how the workload is expected to serve its SLA targets
+type SLAPrediction struct {
+	// NodeClass is the instance type, or accelerator kind, the prediction is for
+	NodeClass string `json:"nodeClass"`
+
+	// Replicas is the replica count the prediction is for
+	Replicas int32 `json:"replicas"`
+
+	// LatencyP99 is the predicted 99th percentile latency, unset when the replicas saturate
+	// +optional
+	LatencyP99 *metav1.Duration `json:"latencyP99,omitempty"`
+
+	// Capacity is the predicted request rate the replicas sustain, in requests per second
+	Capacity float64 `json:"capacity"`
+
+	// Met reports whether the targets are predicted to be met
+	Met bool `json:"met"`
+
+	// Confidence is how far the replica count can be trusted, from 0 to 1, by how many
+	// request rates were observed and how much they varied; zero before any is observed
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	Confidence float64 `json:"confidence,omitempty"`
+
+	// Message explains a predicted violation
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// QoSRecommendation is the request and limit shape recommended for a workload's containers
+type QoSRecommendation struct {
+	// Class is the Kubernetes QoS class the shape gives the pods
+	// +kubebuilder:validation:Enum=Guaranteed;Burstable
+	Class string `json:"class"`
+
+	// Requests are the recommended CPU and memory requests of each container
+	Requests RecommendedResources `json:"requests"`
+
+	// Limits are the recommended CPU and memory limits of each container
+	Limits RecommendedResources `json:"limits"`
+
+	// CPUOvercommit is the CPU limit over the CPU request
+	CPUOvercommit float64 `json:"cpuOvercommit"`
+
+	// MemoryOvercommit is the memory limit over the memory request
+	MemoryOvercommit float64 `json:"memoryOvercommit"`
+
+	// Confidence is how far the requests can be trusted, from 0 to 1: 1 for Guaranteed
+	// shapes, otherwise the confidence of the rightsizing usage they keep room for
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	Confidence float64 `json:"confidence"`
+
+	// Applied is true while the pod webhook gives new pods this shape, because the
+	// workload's CostPolicy auto-applies QoS recommendations at this confidence
+	// +optional
+	Applied bool `json:"applied,omitempty"`
+
+	// Reason explains the shape
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// CPUFrequencyRecommendation is a lower CPU frequency governor recommended for the nodes
+// running a throughput-oriented workload
+type CPUFrequencyRecommendation struct {
+	// Governor is the Linux cpufreq governor recommended, such as powersave
+	Governor string `json:"governor"`
+
+	// FrequencyScale is the share of the maximum CPU frequency the governor settles at
+	FrequencyScale float64 `json:"frequencyScale"`
+
+	// EstimatedPowerSavings is the power in Watts the governor is estimated to save
+	EstimatedPowerSavings float64 `json:"estimatedPowerSavings"`
+
+	// PerformanceImpact is the estimated share by which the workload's runtime grows, such
+	// as 0.25 for runs 25% longer
+	PerformanceImpact float64 `json:"performanceImpact"`
+
+	// Confidence is how far the performance impact can be trusted, from 0 to 1: that of
+	// the rightsizing usage it is estimated from
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	Confidence float64 `json:"confidence"`
+
+	// Applied is true while the workload's CostPolicy auto-applies CPU frequency
+	// recommendations at this confidence, so node agents switch the governor of nodes
+	// running only such workloads
+	// +optional
+	Applied bool `json:"applied,omitempty"`
+
+	// Reason explains the recommendation
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ArrivalPattern is the recent run starts of a workload and the recurring pattern in them
+type ArrivalPattern struct {
+	// Arrivals are the starts of the most recent runs, oldest first
+	// +kubebuilder:validation:MaxItems=30
+	Arrivals []metav1.Time `json:"arrivals"`
+
+	// Period is daily or weekly once a pattern is detected
+	// +kubebuilder:validation:Enum=daily;weekly
+	// +optional
+	Period string `json:"period,omitempty"`
+
+	// NextArrival is the predicted start of the next run
+	// +optional
+	NextArrival *metav1.Time `json:"nextArrival,omitempty"`
+
+	// Jitter is how far the matching runs strayed from the predicted start at most
+	// +optional
+	Jitter *metav1.Duration `json:"jitter,omitempty"`
+
+	// Occurrences is how many of the arrivals match the pattern
+	// +optional
+	Occurrences int32 `json:"occurrences,omitempty"`
+}
+
+// GPUPacking records a low-utilization workload packed onto a time-shared GPU
+type GPUPacking struct {
+	// MeasuredUtilization is the peak GPU utilization (0.0-1.0) that led to the packing decision
+	MeasuredUtilization float64 `json:"measuredUtilization"`
+
+	// Fraction is the GPU share new pods request instead of a whole GPU
+	Fraction string `json:"fraction"`
+
+	// Since is when the workload was packed
+	Since metav1.Time `json:"since"`
+}
+
+// RelaxedConstraint records a cap that was widened to place the workload
+type RelaxedConstraint struct {
+	// Constraint is cost, power or carbon
+	Constraint string `json:"constraint"`
+
+	// Limit is the cap declared in the spec
+	Limit float64 `json:"limit"`
+
+	// RelaxedLimit is the widened cap the placement satisfies
+	RelaxedLimit float64 `json:"relaxedLimit"`
+}
+
+// PlacementAnalysis describes how far the workload's members are from fitting the cluster
+type PlacementAnalysis struct {
+	// AnalyzedAt is when the analysis was computed
+	AnalyzedAt metav1.Time `json:"analyzedAt"`
+
+	// Members is the number of members that must be placed together
+	Members int32 `json:"members"`
+
+	// FittingMembers is how many members currently fit on the eligible nodes
+	FittingMembers int32 `json:"fittingMembers"`
+
+	// EligibleNodes is the number of nodes that pass every non-resource constraint
+	EligibleNodes int32 `json:"eligibleNodes"`
+
+	// Bottleneck is the resource that limits placement the most
+	// +optional
+	Bottleneck string `json:"bottleneck,omitempty"`
+
+	// Relaxation is the smallest single-resource change that would let every member fit
+	// +optional
+	Relaxation *ResourceRelaxation `json:"relaxation,omitempty"`
+
+	// Message summarizes the analysis for users
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ResourceRelaxation is a reduced per-member request that would make placement feasible
+type ResourceRelaxation struct {
+	// Resource is the resource to reduce
+	Resource string `json:"resource"`
+
+	// Requested is the current per-member request
+	Requested string `json:"requested"`
+
+	// Suggested is the largest per-member request that fits
+	Suggested string `json:"suggested"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced,categories=all
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command kcloud-journal verifies and compacts decision journals written by the operator.
+//
+//	kcloud-journal verify <archive>... <journal>
+//	kcloud-journal compact -retention 2160h <journal>
+//	kcloud-journal head <journal>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/journal"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "verify":
+		err = verify(os.Args[2:])
+	case "compact":
+		err = compact(os.Args[2:])
+	case "head":
+		err = head(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: kcloud-journal verify <archive>... <journal>")
+	fmt.Fprintln(os.Stderr, "       kcloud-journal compact [-retention duration] <journal>")
+	fmt.Fprintln(os.Stderr, "       kcloud-journal head <journal>")
+}
+
+// verify checks the hash chain across the given files, oldest archive first
+func verify(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("verify requires at least one journal file")
+	}
+
+	report, err := journal.VerifyFiles(args...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("OK: %d entries (seq %d-%d), %d checkpoints\n",
+		report.Entries, report.FirstSeq, report.LastSeq, report.Checkpoints)
+	if report.AnchorHash != "" {
+		fmt.Printf("anchor: %s\n", report.AnchorHash)
+	}
+	fmt.Printf("head:   %s\n", report.HeadHash)
+	return nil
+}
+
+// compact archives entries older than the retention window
+func compact(args []string) error {
+	fs := flag.NewFlagSet("compact", flag.ExitOnError)
+	retention := fs.Duration("retention", 90*24*time.Hour, "Keep entries newer than this in the live journal")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("compact requires exactly one journal file")
+	}
+
+	j, err := journal.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+
+	result, err := j.Compact(time.Now().Add(-*retention))
+	if err != nil {
+		return err
+	}
+
+	if result.Compacted == 0 {
+		fmt.Println("nothing to compact")
+		return nil
+	}
+	fmt.Printf("compacted %d entries into %s, %d remaining\n", result.Compacted, result.Archive, result.Remaining)
+	return nil
+}
+
+// head prints the latest sequence number and hash for external anchoring
+func head(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("head requires exactly one journal file")
+	}
+
+	report, err := journal.VerifyFile(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d %s\n", report.LastSeq, report.HeadHash)
+	return nil
+}
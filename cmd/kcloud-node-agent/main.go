@@ -0,0 +1,562 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command kcloud-node-agent runs on every GPU node and publishes the node's GPU
+// compute mode as a label the scheduler uses to avoid sharing exclusive-process devices,
+// and the GPU utilization of the pods running on the node for GPU packing. With --rapl it
+// also publishes the node's CPU package power from RAPL counters, for power-aware scheduling
+// on hosts without a BMC or Kepler. With --power-capping it applies the power cap the
+// operator requests on the node to the CPU packages (with --rapl) and GPUs (with --gpu), and
+// with --cpu-governor it switches the CPU frequency governor the operator requests.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+// raplChangeThreshold is the relative change in CPU package power that is published before
+// the heartbeat is due
+const raplChangeThreshold = 0.05
+
+func main() {
+	var nodeName, nvidiaSMI, procRoot, powercapRoot, cpufreqRoot string
+	var interval time.Duration
+	var gpu, rapl, powerCapping, cpuGovernor bool
+	flag.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "Name of the node this agent runs on")
+	flag.StringVar(&nvidiaSMI, "nvidia-smi", "nvidia-smi", "Path to the nvidia-smi binary")
+	flag.StringVar(&procRoot, "proc", "/proc", "Host proc filesystem used to map GPU processes to pods")
+	flag.DurationVar(&interval, "interval", time.Minute, "How often to refresh the GPU compute mode and utilization")
+	flag.BoolVar(&gpu, "gpu", true, "Publish the GPU compute mode and pod GPU utilization")
+	flag.BoolVar(&rapl, "rapl", false, "Publish the CPU package power estimated from RAPL counters")
+	flag.StringVar(&powercapRoot, "powercap", "/sys/class/powercap", "Host powercap directory RAPL counters are read from")
+	flag.BoolVar(&powerCapping, "power-capping", false,
+		"Apply the power cap requested by the operator to the CPU packages and GPUs, needs write access to them")
+	flag.BoolVar(&cpuGovernor, "cpu-governor", false,
+		"Switch the CPU frequency governor to the one requested by the operator, needs write access to cpufreq")
+	flag.StringVar(&cpufreqRoot, "cpufreq", "/sys/devices/system/cpu", "Host CPU directory cpufreq governors are set in")
+	opts := zap.Options{}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	logger := ctrl.Log.WithName("node-agent")
+
+	if nodeName == "" {
+		logger.Error(nil, "node name is required, set --node-name or NODE_NAME")
+		os.Exit(1)
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{})
+	if err != nil {
+		logger.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+
+	ctx := log.IntoContext(ctrl.SetupSignalHandler(), logger)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var packagePower *raplSampler
+	if rapl {
+		packagePower = &raplSampler{root: powercapRoot}
+	}
+	var capper *powerCapper
+	if powerCapping {
+		capper = newPowerCapper(rapl, gpu, powercapRoot, nvidiaSMI)
+	}
+	var governor *governorSwitcher
+	if cpuGovernor {
+		governor = &governorSwitcher{root: cpufreqRoot}
+	}
+	for {
+		if gpu {
+			if err := publishComputeMode(ctx, c, nodeName, nvidiaSMI); err != nil {
+				logger.Error(err, "failed to publish GPU compute mode", "node", nodeName)
+			}
+			if err := publishPodUtilization(ctx, c, nodeName, nvidiaSMI, procRoot); err != nil {
+				logger.Error(err, "failed to publish pod GPU utilization", "node", nodeName)
+			}
+		}
+		if packagePower != nil {
+			if err := packagePower.publish(ctx, c, nodeName); err != nil {
+				logger.Error(err, "failed to publish CPU package power", "node", nodeName)
+			}
+		}
+		if capper != nil {
+			if err := capper.apply(ctx, c, nodeName); err != nil {
+				logger.Error(err, "failed to apply power cap", "node", nodeName)
+			}
+		}
+		if governor != nil {
+			if err := governor.apply(ctx, c, nodeName); err != nil {
+				logger.Error(err, "failed to switch CPU governor", "node", nodeName)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishComputeMode reads the GPU compute mode and labels the node when it changed
+func publishComputeMode(ctx context.Context, c client.Client, nodeName, nvidiaSMI string) error {
+	output, err := exec.CommandContext(ctx, nvidiaSMI, "--query-gpu=compute_mode", "--format=csv,noheader").Output()
+	if err != nil {
+		return fmt.Errorf("failed to query GPU compute mode: %w", err)
+	}
+	mode := scheduler.ParseComputeMode(string(output))
+
+	var node corev1.Node
+	if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+	if node.Labels[scheduler.GPUComputeModeLabel] == mode {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	node.Labels[scheduler.GPUComputeModeLabel] = mode
+	if err := c.Patch(ctx, &node, patch); err != nil {
+		return fmt.Errorf("failed to label node: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Published GPU compute mode", "node", nodeName, "mode", mode)
+	return nil
+}
+
+// publishPodUtilization samples the GPU utilization of each process, attributes it to the
+// pod owning the process and annotates the node when the per-pod utilization changed
+func publishPodUtilization(ctx context.Context, c client.Client, nodeName, nvidiaSMI, procRoot string) error {
+	output, err := exec.CommandContext(ctx, nvidiaSMI, "pmon", "-c", "1", "-s", "u").Output()
+	if err != nil {
+		return fmt.Errorf("failed to query GPU process utilization: %w", err)
+	}
+
+	utilization := map[string]float64{}
+	for pid, percent := range scheduler.ParseProcessUtilization(string(output)) {
+		cgroup, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "cgroup"))
+		if err != nil {
+			// The process exited since the sample
+			continue
+		}
+		if uid := scheduler.PodUIDFromCgroup(string(cgroup)); uid != "" {
+			utilization[uid] += percent
+		}
+	}
+	// encoding/json sorts map keys, so unchanged utilization encodes identically
+	encoded, err := json.Marshal(utilization)
+	if err != nil {
+		return fmt.Errorf("failed to encode pod GPU utilization: %w", err)
+	}
+
+	var node corev1.Node
+	if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+	if node.Annotations[scheduler.GPUUtilizationAnnotation] == string(encoded) {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[scheduler.GPUUtilizationAnnotation] = string(encoded)
+	if err := c.Patch(ctx, &node, patch); err != nil {
+		return fmt.Errorf("failed to annotate node: %w", err)
+	}
+
+	log.FromContext(ctx).V(1).Info("Published pod GPU utilization", "node", nodeName, "pods", len(utilization))
+	return nil
+}
+
+// raplSampler estimates CPU package power from the change in RAPL counters between samples
+type raplSampler struct {
+	root     string
+	previous *optimizer.RAPLCounter
+}
+
+// publish samples the RAPL counters and annotates the node with the power drawn since the
+// previous sample, when it changed noticeably or the heartbeat is due
+func (s *raplSampler) publish(ctx context.Context, c client.Client, nodeName string) error {
+	now := time.Now()
+	counter, err := optimizer.ReadRAPLCounter(s.root, now)
+	if err != nil {
+		return err
+	}
+	previous := s.previous
+	s.previous = &counter
+	if previous == nil {
+		// Power needs two samples
+		return nil
+	}
+	watts, ok := optimizer.RAPLPackagePower(*previous, counter)
+	if !ok {
+		return nil
+	}
+
+	var node corev1.Node
+	if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+	published, err := strconv.ParseFloat(node.Annotations[optimizer.CPUPackagePowerAnnotation], 64)
+	changed := err != nil || math.Abs(watts-published) > raplChangeThreshold*math.Max(published, 1)
+	publishedAt, err := time.Parse(time.RFC3339, node.Annotations[optimizer.CPUPackagePowerTimeAnnotation])
+	if !changed && err == nil && now.Sub(publishedAt) < optimizer.RAPLHeartbeat {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[optimizer.CPUPackagePowerAnnotation] = strconv.FormatFloat(math.Round(watts), 'f', 0, 64)
+	node.Annotations[optimizer.CPUPackagePowerTimeAnnotation] = now.UTC().Format(time.RFC3339)
+	if err := c.Patch(ctx, &node, patch); err != nil {
+		return fmt.Errorf("failed to annotate node: %w", err)
+	}
+
+	log.FromContext(ctx).V(1).Info("Published CPU package power", "node", nodeName, "watts", watts)
+	return nil
+}
+
+// powerCapper applies the power cap requested on the node to its CPU packages and GPUs.
+// Before the first cap it records the limits that were set in a node annotation, and it
+// restores exactly those once no cap is requested. A node that never had a cap requested is
+// left alone.
+type powerCapper struct {
+	domains []powerDomain
+	// applied is the cap in percent last applied to each domain. It is reapplied once after
+	// start, since the agent cannot tell who set the current limits.
+	applied map[string]int32
+}
+
+// newPowerCapper caps the CPU packages under the powercap root and the GPUs nvidia-smi manages
+func newPowerCapper(cpu, gpu bool, root, nvidiaSMI string) *powerCapper {
+	p := &powerCapper{applied: map[string]int32{}}
+	if cpu {
+		p.domains = append(p.domains, raplDomain{root: root})
+	}
+	if gpu {
+		p.domains = append(p.domains, gpuDomain{nvidiaSMI: nvidiaSMI})
+	}
+	return p
+}
+
+// powerDomain is a kind of device whose power limits the agent caps
+type powerDomain interface {
+	// name identifies the domain in logs
+	name() string
+	// annotations returns the node annotations the original limits and the applied cap are
+	// recorded in
+	annotations() (original, applied string)
+	// read returns the current limits, encoded for the node annotation
+	read(ctx context.Context) (string, error)
+	// cap sets the limits to the percent of their defaults
+	cap(ctx context.Context, percent int32) error
+	// restore sets the limits read before the first cap back
+	restore(ctx context.Context, original string) error
+}
+
+// apply brings every domain to the cap requested on the node
+func (p *powerCapper) apply(ctx context.Context, c client.Client, nodeName string) error {
+	var node corev1.Node
+	if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+	percent, _ := optimizer.ParsePowerCap(node.Annotations[optimizer.PowerCapAnnotation])
+	var errs []error
+	for _, domain := range p.domains {
+		if err := p.applyDomain(ctx, c, &node, domain, percent); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", domain.name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyDomain records the domain's original limits before the first cap, caps it when the
+// requested cap changed and restores the original limits once none is requested, reporting
+// the applied cap on the node
+func (p *powerCapper) applyDomain(ctx context.Context, c client.Client, node *corev1.Node, domain powerDomain,
+	percent int32) error {
+	originalKey, appliedKey := domain.annotations()
+	original, recorded := node.Annotations[originalKey]
+
+	if percent == 0 {
+		if !recorded {
+			return nil
+		}
+		if err := domain.restore(ctx, original); err != nil {
+			return err
+		}
+		delete(p.applied, domain.name())
+		log.FromContext(ctx).Info("Restored original power limits", "node", node.Name, "domain", domain.name())
+		patch := client.MergeFrom(node.DeepCopy())
+		delete(node.Annotations, originalKey)
+		delete(node.Annotations, appliedKey)
+		if err := c.Patch(ctx, node, patch); err != nil {
+			return fmt.Errorf("failed to annotate node: %w", err)
+		}
+		return nil
+	}
+
+	if !recorded {
+		// The original limits are stored on the node before any is changed, so they survive
+		// agent restarts while the cap is in place
+		limits, err := domain.read(ctx)
+		if err != nil {
+			return err
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[originalKey] = limits
+		if err := c.Patch(ctx, node, patch); err != nil {
+			return fmt.Errorf("failed to record original power limits: %w", err)
+		}
+	}
+
+	if applied, ok := p.applied[domain.name()]; !ok || applied != percent {
+		if err := domain.cap(ctx, percent); err != nil {
+			return err
+		}
+		p.applied[domain.name()] = percent
+		log.FromContext(ctx).Info("Applied power cap", "node", node.Name, "domain", domain.name(), "percent", percent)
+	}
+
+	value := strconv.Itoa(int(percent))
+	if node.Annotations[appliedKey] == value {
+		return nil
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Annotations[appliedKey] = value
+	if err := c.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("failed to annotate node: %w", err)
+	}
+	return nil
+}
+
+// raplDomain caps the node's CPU packages through their RAPL power limits
+type raplDomain struct {
+	root string
+}
+
+func (d raplDomain) name() string { return "cpu" }
+
+func (d raplDomain) annotations() (string, string) {
+	return optimizer.OriginalCPUPowerLimitsAnnotation, optimizer.CPUPowerCapAppliedAnnotation
+}
+
+func (d raplDomain) read(context.Context) (string, error) {
+	limits, err := optimizer.ReadRAPLPowerLimits(d.root)
+	if err != nil {
+		return "", fmt.Errorf("failed to read CPU package power limits: %w", err)
+	}
+	encoded, err := json.Marshal(limits)
+	return string(encoded), err
+}
+
+func (d raplDomain) cap(_ context.Context, percent int32) error {
+	if err := optimizer.ApplyRAPLPowerCap(d.root, percent); err != nil {
+		return fmt.Errorf("failed to cap CPU package power: %w", err)
+	}
+	return nil
+}
+
+func (d raplDomain) restore(_ context.Context, original string) error {
+	var limits map[string]uint64
+	if err := json.Unmarshal([]byte(original), &limits); err != nil {
+		return fmt.Errorf("invalid original CPU package power limits: %w", err)
+	}
+	if err := optimizer.RestoreRAPLPowerLimits(d.root, limits); err != nil {
+		return fmt.Errorf("failed to restore CPU package power limits: %w", err)
+	}
+	return nil
+}
+
+// gpuDomain caps the node's GPUs through nvidia-smi
+type gpuDomain struct {
+	nvidiaSMI string
+}
+
+func (d gpuDomain) name() string { return "gpu" }
+
+func (d gpuDomain) annotations() (string, string) {
+	return optimizer.OriginalGPUPowerLimitsAnnotation, optimizer.GPUPowerCapAppliedAnnotation
+}
+
+// limits queries the power limit range and current limit of each GPU
+func (d gpuDomain) limits(ctx context.Context) ([]optimizer.GPUPowerLimit, error) {
+	output, err := exec.CommandContext(ctx, d.nvidiaSMI,
+		"--query-gpu=index,power.default_limit,power.min_limit,power.limit", "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GPU power limits: %w", err)
+	}
+	return optimizer.ParseGPUPowerLimits(string(output))
+}
+
+func (d gpuDomain) read(ctx context.Context) (string, error) {
+	limits, err := d.limits(ctx)
+	if err != nil {
+		return "", err
+	}
+	current := make(map[int]float64, len(limits))
+	for _, limit := range limits {
+		current[limit.Index] = limit.Current
+	}
+	encoded, err := json.Marshal(current)
+	return string(encoded), err
+}
+
+// cap sets the power limit of each GPU to the percent of its default limit
+func (d gpuDomain) cap(ctx context.Context, percent int32) error {
+	limits, err := d.limits(ctx)
+	if err != nil {
+		return err
+	}
+	for _, limit := range limits {
+		if err := d.setLimit(ctx, limit.Index, limit.Capped(percent)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d gpuDomain) restore(ctx context.Context, original string) error {
+	var limits map[int]float64
+	if err := json.Unmarshal([]byte(original), &limits); err != nil {
+		return fmt.Errorf("invalid original GPU power limits: %w", err)
+	}
+	for index, watts := range limits {
+		if err := d.setLimit(ctx, index, watts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setLimit sets the power limit of one GPU
+func (d gpuDomain) setLimit(ctx context.Context, index int, watts float64) error {
+	limit := strconv.FormatFloat(math.Round(watts), 'f', 0, 64)
+	if err := exec.CommandContext(ctx, d.nvidiaSMI, "-i", strconv.Itoa(index), "-pl", limit).Run(); err != nil {
+		return fmt.Errorf("failed to set power limit of GPU %d: %w", index, err)
+	}
+	return nil
+}
+
+// governorSwitcher switches the CPU frequency governor to the one requested on the node.
+// Before the first switch it records each CPU's governor in a node annotation, and it
+// restores exactly those once no governor is requested. A node that never had a governor
+// requested is left alone.
+type governorSwitcher struct {
+	root string
+	// applied is the governor last set, empty before the first one after start
+	applied string
+}
+
+// apply records the original governors, sets the requested one when it changed, restores
+// the originals once none is requested and reports the requested one on the node
+func (g *governorSwitcher) apply(ctx context.Context, c client.Client, nodeName string) error {
+	var node corev1.Node
+	if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+	requested := node.Annotations[optimizer.CPUGovernorAnnotation]
+	original, recorded := node.Annotations[optimizer.OriginalCPUGovernorsAnnotation]
+
+	if requested == "" {
+		if !recorded {
+			return nil
+		}
+		var governors map[string]string
+		if err := json.Unmarshal([]byte(original), &governors); err != nil {
+			return fmt.Errorf("invalid original CPU governors: %w", err)
+		}
+		if err := optimizer.RestoreCPUGovernors(g.root, governors); err != nil {
+			return err
+		}
+		g.applied = ""
+		log.FromContext(ctx).Info("Restored original CPU governors", "node", nodeName)
+		patch := client.MergeFrom(node.DeepCopy())
+		delete(node.Annotations, optimizer.OriginalCPUGovernorsAnnotation)
+		delete(node.Annotations, optimizer.CPUGovernorAppliedAnnotation)
+		if err := c.Patch(ctx, &node, patch); err != nil {
+			return fmt.Errorf("failed to annotate node: %w", err)
+		}
+		return nil
+	}
+
+	if !recorded {
+		// The original governors are stored on the node before any is changed, so they
+		// survive agent restarts while the request is in place
+		governors, err := optimizer.ReadCPUGovernors(g.root)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(governors)
+		if err != nil {
+			return fmt.Errorf("failed to encode CPU governors: %w", err)
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Annotations[optimizer.OriginalCPUGovernorsAnnotation] = string(encoded)
+		if err := c.Patch(ctx, &node, patch); err != nil {
+			return fmt.Errorf("failed to record original CPU governors: %w", err)
+		}
+	}
+
+	if requested != g.applied {
+		if err := optimizer.ApplyCPUGovernor(g.root, requested); err != nil {
+			return err
+		}
+		g.applied = requested
+		log.FromContext(ctx).Info("Switched CPU governor", "node", nodeName, "governor", requested)
+	}
+
+	if node.Annotations[optimizer.CPUGovernorAppliedAnnotation] == requested {
+		return nil
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Annotations[optimizer.CPUGovernorAppliedAnnotation] = requested
+	if err := c.Patch(ctx, &node, patch); err != nil {
+		return fmt.Errorf("failed to annotate node: %w", err)
+	}
+	return nil
+}
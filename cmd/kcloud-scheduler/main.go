@@ -0,0 +1,41 @@
+//go:build kcloudscheduler
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command kcloud-scheduler runs kube-scheduler with the kcloud cost/power plugin compiled in.
+// It is built separately from the operator because it depends on k8s.io/kubernetes:
+//
+//	go build -tags kcloudscheduler ./cmd/kcloud-scheduler
+//
+// Run it with config/scheduler/kcloud-scheduler-config.yaml to get a "kcloud-scheduler" profile.
+package main
+
+import (
+	"os"
+
+	"k8s.io/component-base/cli"
+	"k8s.io/kubernetes/cmd/kube-scheduler/app"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+func main() {
+	command := app.NewSchedulerCommand(
+		app.WithPlugin(scheduler.PluginName, newCostPowerPlugin),
+	)
+	os.Exit(cli.Run(command))
+}
@@ -0,0 +1,202 @@
+//go:build kcloudscheduler
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+// workloadStateKey is the cycle state key the pod's workload is kept under
+const workloadStateKey fwk.StateKey = scheduler.PluginName + "/workload"
+
+// workloadState is the pod's workload, resolved once in PreFilter for the rest of the cycle
+type workloadState struct {
+	wo *kcloudv1alpha1.WorkloadOptimizer
+}
+
+// Clone shares the workload, which later extension points only read
+func (s *workloadState) Clone() fwk.StateData {
+	return s
+}
+
+// costPowerPlugin exposes the operator's node filter and score logic as scheduler-framework plugins
+type costPowerPlugin struct {
+	plugin *scheduler.NodePlugin
+}
+
+var _ framework.PreFilterPlugin = &costPowerPlugin{}
+var _ framework.FilterPlugin = &costPowerPlugin{}
+var _ framework.ScorePlugin = &costPowerPlugin{}
+var _ framework.PreBindPlugin = &costPowerPlugin{}
+var _ framework.QueueSortPlugin = &costPowerPlugin{}
+
+// newCostPowerPlugin is the framework.PluginFactory for the kcloud plugin. Workloads, pods
+// and nodes are read from informers, so filtering and scoring every node does not call the
+// API server.
+func newCostPowerPlugin(ctx context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add client-go scheme: %w", err)
+	}
+	if err := kcloudv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add kcloud scheme: %w", err)
+	}
+
+	informers, err := cache.New(handle.KubeConfig(), cache.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create informer cache: %w", err)
+	}
+	if err := scheduler.IndexPodsByNode(ctx, informers); err != nil {
+		return nil, fmt.Errorf("failed to index pods by node: %w", err)
+	}
+	for _, obj := range []client.Object{&kcloudv1alpha1.WorkloadOptimizer{}, &corev1.Node{}} {
+		if _, err := informers.GetInformer(ctx, obj); err != nil {
+			return nil, fmt.Errorf("failed to create informer: %w", err)
+		}
+	}
+	go func() {
+		if err := informers.Start(ctx); err != nil {
+			klog.FromContext(ctx).Error(err, "Informer cache stopped")
+		}
+	}()
+	if !informers.WaitForCacheSync(ctx) {
+		return nil, fmt.Errorf("failed to sync informer cache")
+	}
+
+	c, err := client.New(handle.KubeConfig(), client.Options{Scheme: scheme, Cache: &client.CacheOptions{Reader: informers}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return &costPowerPlugin{
+		plugin: scheduler.NewNodePlugin(scheduler.NewScheduler(), c),
+	}, nil
+}
+
+// Name returns the plugin name used in scheduler profiles
+func (p *costPowerPlugin) Name() string {
+	return scheduler.PluginName
+}
+
+// PreFilter resolves the pod's workload once for the scheduling cycle
+func (p *costPowerPlugin) PreFilter(ctx context.Context, state fwk.CycleState, pod *corev1.Pod,
+	_ []fwk.NodeInfo) (*framework.PreFilterResult, *fwk.Status) {
+	wo, err := p.plugin.ResolveWorkload(ctx, pod)
+	if err != nil {
+		return nil, fwk.AsStatus(err)
+	}
+	state.Write(workloadStateKey, &workloadState{wo: wo})
+	return nil, nil
+}
+
+// PreFilterExtensions returns nil since the workload does not depend on other pods
+func (p *costPowerPlugin) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// workload returns the pod's workload resolved in PreFilter
+func workload(state fwk.CycleState) (*kcloudv1alpha1.WorkloadOptimizer, error) {
+	data, err := state.Read(workloadStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("workload was not resolved in PreFilter: %w", err)
+	}
+	return data.(*workloadState).wo, nil
+}
+
+// Filter rejects nodes that cannot satisfy the pod's workload requirements
+func (p *costPowerPlugin) Filter(ctx context.Context, state fwk.CycleState, pod *corev1.Pod, nodeInfo fwk.NodeInfo) *fwk.Status {
+	node := nodeInfo.Node()
+	if node == nil {
+		return fwk.NewStatus(fwk.Error, "node not found")
+	}
+	wo, err := workload(state)
+	if err != nil {
+		return fwk.AsStatus(err)
+	}
+
+	feasible, reason, err := p.plugin.Filter(ctx, wo, pod, node)
+	if err != nil {
+		return fwk.AsStatus(err)
+	}
+	if !feasible {
+		return fwk.NewStatus(fwk.Unschedulable, reason)
+	}
+	return nil
+}
+
+// Score ranks nodes with the same cost and power scoring the operator uses
+func (p *costPowerPlugin) Score(ctx context.Context, state fwk.CycleState, pod *corev1.Pod, nodeInfo fwk.NodeInfo) (int64, *fwk.Status) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return 0, fwk.NewStatus(fwk.Error, "node not found")
+	}
+	wo, err := workload(state)
+	if err != nil {
+		return 0, fwk.AsStatus(err)
+	}
+
+	score, err := p.plugin.Score(ctx, wo, pod, node, fwk.MaxNodeScore)
+	if err != nil {
+		return 0, fwk.AsStatus(err)
+	}
+	return score, nil
+}
+
+// Less orders the pending queue earliest deadline first within each priority
+func (p *costPowerPlugin) Less(a, b *framework.QueuedPodInfo) bool {
+	return scheduler.DeadlineLess(a.Pod, a.Timestamp, b.Pod, b.Timestamp)
+}
+
+// ScoreExtensions returns nil since scores are already on the framework scale
+func (p *costPowerPlugin) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// PreBindPreFlight skips PreBind for pods that do not share a GPU
+func (p *costPowerPlugin) PreBindPreFlight(_ context.Context, _ fwk.CycleState, pod *corev1.Pod, _ string) *fwk.Status {
+	if pod.Annotations[scheduler.GPUFractionAnnotation] == "" {
+		return fwk.NewStatus(fwk.Skip)
+	}
+	return nil
+}
+
+// PreBind pins a pod requesting a GPU fraction to a physical GPU on its node
+func (p *costPowerPlugin) PreBind(ctx context.Context, state fwk.CycleState, pod *corev1.Pod, nodeName string) *fwk.Status {
+	wo, err := workload(state)
+	if err != nil {
+		return fwk.AsStatus(err)
+	}
+	if err := p.plugin.AssignGPU(ctx, wo, pod, nodeName); err != nil {
+		return fwk.AsStatus(err)
+	}
+	return nil
+}
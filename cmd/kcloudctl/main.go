@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command kcloudctl operates the kcloud workload operator.
+//
+//	kcloudctl preflight upgrade -crds <dir-or-file>... [-kubeconfig path] [-o text|json]
+//
+// Run the kcloudctl binary of the release being upgraded to, so that its compiled-in
+// history schema version and the CRDs passed with -crds describe that release.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/preflight"
+)
+
+// errChecksFailed signals a completed run with failing checks
+var errChecksFailed = errors.New("pre-flight checks failed")
+
+func main() {
+	if len(os.Args) < 3 || os.Args[1] != "preflight" || os.Args[2] != "upgrade" {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := preflightUpgrade(os.Args[3:]); err != nil {
+		if !errors.Is(err, errChecksFailed) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: kcloudctl preflight upgrade -crds <dir-or-file>[,...] [-kubeconfig path] [-o text|json]")
+}
+
+// stringList collects a repeatable, comma-separated flag
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, strings.Split(value, ",")...)
+	return nil
+}
+
+// preflightUpgrade checks the cluster against the new release and prints a report
+func preflightUpgrade(args []string) error {
+	fs := flag.NewFlagSet("preflight upgrade", flag.ExitOnError)
+	var crdPaths stringList
+	fs.Var(&crdPaths, "crds", "CRD manifests of the new release, as files or directories (repeatable)")
+	kubeconfig := fs.String("kubeconfig", "", "Path to a kubeconfig; defaults to KUBECONFIG or in-cluster config")
+	output := fs.String("o", "text", "Output format: text or json")
+	timeout := fs.Duration("timeout", 2*time.Minute, "Time limit for all checks")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(crdPaths) == 0 {
+		return fmt.Errorf("-crds is required")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %s", *output)
+	}
+
+	crds, err := preflight.LoadCRDs(crdPaths...)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClient(*kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	report := preflight.NewUpgradeChecker(c, crds).Run(ctx)
+
+	if *output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else if err := report.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	if !report.Passed() {
+		return errChecksFailed
+	}
+	return nil
+}
+
+// newClient creates a client that understands core, CRD and kcloud types
+func newClient(kubeconfig string) (client.Client, error) {
+	var config *rest.Config
+	var err error
+	if kubeconfig != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		config, err = ctrl.GetConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	utilruntime.Must(kcloudv1alpha1.AddToScheme(scheme))
+
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return c, nil
+}
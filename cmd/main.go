@@ -17,28 +17,46 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/flowcontrol"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
 	"github.com/KETI-Cloud-Platform/k8s-workload-operator/internal/controller"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/adaptive"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/allocation"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/apiserver"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/chargeback"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/costreport"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/forecast"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/journal"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
 	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/metrics"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/notify"
 	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/retention"
 	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
 	kcloudwebhook "github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/webhook"
 	// +kubebuilder:scaffold:imports
@@ -63,12 +81,100 @@ func main() {
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
+	var apiAddr string
+	var apiCertPath, apiCertName, apiCertKey, apiClientCAFile string
+	var enableSchedulerExtender bool
+	var enablePurgeAPI bool
+	var enableCapacityMarketplace bool
+	var journalPath string
+	var journalRetention time.Duration
+	var adaptiveThrottling bool
+	var scoreWeights string
+	var paretoWeights string
+	var optimizationScoreWeights string
+	var usageDeviationPercent float64
+	var usageBaselineWindow time.Duration
+	var historyNamespace string
+	var historyRetention scheduler.HistoryRetention
+	var gpuPackingThreshold, gpuPackingHeadroom float64
+	var gpuPackingWindow time.Duration
+	var prometheusURL string
+	var pricingTable string
+	var currencyCode, exchangeRates, exchangeRateURL string
+	var exchangeRateRefresh time.Duration
+	var nodePricingProvider, nodePricingRegion string
+	var openCostURL string
+	var openCostWindow time.Duration
+	var keplerPower bool
+	var keplerWindow time.Duration
+	var dcgmUsage bool
+	var dcgmWindow time.Duration
+	var powerModelInterval time.Duration
+	var podPowerInterval time.Duration
+	var bmcSecret string
+	var bmcInterval time.Duration
+	var nodePricingRefresh time.Duration
+	var awsPriceListURL string
+	var gcpPriceListURL, gcpPricingAPIKey string
+	var gcpSustainedUse bool
+	var azurePriceListURL string
+	var spotPriceFeedURL string
+	var spotPriceInterval, spotPriceWindow time.Duration
+	var spotRiskWeight float64
+	var transferPricingFile string
+	var gridSignalFeedURL string
+	var gridSignalInterval time.Duration
+	var renewableForecastFeedURL string
+	var renewableForecastInterval time.Duration
+	var rightsizingWindow time.Duration
+	var rightsizingHeadroom float64
+	var slaLatencyMetric string
+	var slaWindow time.Duration
+	var deadlineScheduling bool
+	var thermalScheduling bool
+	thermalLimits := optimizer.DefaultThermalLimits()
+	var reserveDetectedPatterns bool
+	var idlePeriod time.Duration
+	var idleCPUThreshold, idleGPUThreshold float64
+	var schedulingInitialBackoff, schedulingMaxBackoff time.Duration
+	var notificationConfig string
+	var acceleratorRegistryPath string
+	var locale, messageCatalog string
+	var cacheTransferCostPerGB float64
+	var imageLocalityWeights string
+	var overcommitRatios string
+	var placementAnalysis bool
+	var stickinessBonus, stickinessHysteresis float64
+	var schedulingLaneConcurrency int
+	var fragmentationInterval time.Duration
+	var optimizationPlanInterval time.Duration
+	var cpuGovernorInterval time.Duration
+	var costForecastInterval time.Duration
+	var carbonPolicyInterval time.Duration
+	var powerPolicyInterval time.Duration
+	var chargebackInterval time.Duration
+	var chargebackRetention time.Duration
+	var costAllocationInterval, costAllocationRetention time.Duration
+	var costAllocationBasis, costAllocationLabels string
+	var costReportPeriod, costReportDir, costReportUploadURL, costReportCluster string
+	var maxDefragmentationMigrations int
+	var manageWebhookConfig, webhookAcceleratorPodsOnly bool
+	var webhookNamespaceLabel, webhookServiceName, webhookServiceNamespace string
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&apiAddr, "api-bind-address", "0", "The address the REST API binds to. "+
+		"Use :8082 to serve it, or leave as 0 to disable the API server.")
+	flag.StringVar(&apiCertPath, "api-cert-path", "",
+		"The directory that contains the REST API server certificate. A self-signed certificate is generated if unset.")
+	flag.StringVar(&apiCertName, "api-cert-name", "tls.crt", "The name of the REST API server certificate file.")
+	flag.StringVar(&apiCertKey, "api-cert-key", "tls.key", "The name of the REST API server key file.")
+	flag.StringVar(&apiClientCAFile, "api-client-ca-file", "",
+		"If set, REST API callers presenting a client certificate signed by this CA are authenticated as its "+
+			"common name and organizations; kube-scheduler uses this to call the extender endpoints")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -81,6 +187,234 @@ func main() {
 		"The directory that contains the metrics server certificate.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
+	flag.BoolVar(&enableSchedulerExtender, "enable-scheduler-extender", false,
+		"If set, kube-scheduler extender filter and prioritize endpoints are served on the REST API server")
+	flag.BoolVar(&enableCapacityMarketplace, "enable-capacity-marketplace", false,
+		"Let teams offer reserved capacity to other teams as CapacityOffers, and steer workloads onto capacity their team claimed")
+	flag.BoolVar(&enablePurgeAPI, "enable-purge-api", false,
+		"If set, the REST API serves POST /apis/v1/admin/purge to remove scheduling history and decision journal "+
+			"records of a namespace, workload or time range")
+	flag.StringVar(&journalPath, "decision-journal-path", "",
+		"If set, optimization decisions are appended to a checksum-protected journal at this path")
+	flag.DurationVar(&journalRetention, "decision-journal-retention", 90*24*time.Hour,
+		"Decisions older than this are compacted out of the live journal into archive files")
+	flag.BoolVar(&adaptiveThrottling, "adaptive-throttling", false,
+		"If set, reconcile concurrency and client QPS/burst are tuned from observed API server latency and 429 responses")
+	flag.StringVar(&scoreWeights, "score-weights", "",
+		"Node scoring weights as resource=0.4,cost=0.3,power=0.2,placement=0.1; omitted criteria keep their defaults")
+	flag.StringVar(&paretoWeights, "pareto-weights", "",
+		"If set, optimization picks among non-dominated node and replica options using weights written as "+
+			"cost=0.4,power=0.3,performance=0.3; omitted objectives get no weight")
+	flag.StringVar(&optimizationScoreWeights, "optimization-score-weights", "",
+		"Weights of the cost, power and utilization components of a workload's optimization score, as "+
+			"cost=0.4,power=0.3,utilization=0.3; omitted components get no weight")
+	flag.StringVar(&overcommitRatios, "overcommit-ratios", "",
+		"Factors applied to allocatable capacity when checking a workload against the requests and reservations "+
+			"on a node, per workload type and resource, as batch:cpu=1.5,memory=1.2;inference:cpu=1.0; "+
+			"the type * sets the default and resources without a ratio are accounted strictly")
+	flag.StringVar(&imageLocalityWeights, "image-locality-weights", "",
+		"Share of the node score given to cached images per workload type, as training=0.1,serving=0.15; "+
+			"omitted types keep their defaults")
+	flag.Float64Var(&cacheTransferCostPerGB, "dataset-cache-cost-per-gb", scheduler.DefaultCacheTransferCostPerGB,
+		"Estimated cost in USD per GB of populating a dataset cache on a new node, used to report cache placement savings")
+	flag.BoolVar(&placementAnalysis, "placement-analysis", false,
+		"If set, failed placements record how many members fit, the bottleneck resource and the smallest "+
+			"request reduction that would fit in the workload status")
+	flag.Float64Var(&stickinessBonus, "sticky-node-bonus", scheduler.DefaultStickinessBonus,
+		"Score bonus given to a workload's assigned node when it is re-optimized, in [0, 1)")
+	flag.Float64Var(&stickinessHysteresis, "sticky-node-hysteresis", scheduler.DefaultStickinessHysteresis,
+		"Score margin by which another node must beat the assigned node before a workload is moved, in [0, 1)")
+	flag.DurationVar(&fragmentationInterval, "fragmentation-interval", scheduler.DefaultFragmentationInterval,
+		"How often GPU and CPU fragmentation is measured and a defragmentation plan recommended; 0 disables it")
+	flag.DurationVar(&costForecastInterval, "cost-forecast-interval", forecast.DefaultInterval,
+		"How often workload cost is sampled and cost policy spend projected to the end of the month; 0 disables it")
+	flag.DurationVar(&carbonPolicyInterval, "carbon-policy-interval", forecast.DefaultCarbonInterval,
+		"How often carbon policy emissions are totaled and checked against their budgets; 0 disables it")
+	flag.DurationVar(&powerPolicyInterval, "power-policy-interval", controller.DefaultPowerPolicyInterval,
+		"How often power policies compare their workloads' power with their cap and enforce it")
+	flag.DurationVar(&chargebackInterval, "chargeback-interval", chargeback.DefaultInterval,
+		"How often workload cost and power are accrued for chargeback reports; 0 disables chargeback")
+	flag.DurationVar(&chargebackRetention, "chargeback-retention", chargeback.DefaultRetention,
+		"How long hourly chargeback usage is kept")
+	flag.DurationVar(&costAllocationInterval, "cost-allocation-interval", allocation.DefaultInterval,
+		"How often node cost is allocated to the pods running on each node; 0 disables cost allocation")
+	flag.DurationVar(&costAllocationRetention, "cost-allocation-retention", allocation.DefaultRetention,
+		"How long hourly allocated cost is kept")
+	flag.StringVar(&costAllocationBasis, "cost-allocation-basis", string(allocation.BasisMax),
+		"What pods are charged a share of their node by: requests, usage, or the larger of the two (max). "+
+			"Usage is read from --prometheus-url")
+	flag.StringVar(&costAllocationLabels, "cost-allocation-labels", "team",
+		"Comma separated pod or namespace label keys whose allocated cost is exported as metrics")
+	flag.StringVar(&costReportPeriod, "cost-report-period", string(costreport.PeriodDaily),
+		"How long each scheduled cost report covers: daily, weekly or monthly")
+	flag.StringVar(&costReportDir, "cost-report-dir", "",
+		"If set, write a CSV, a JSON and a FOCUS cost report into this directory, such as a mounted volume, as each period ends")
+	flag.StringVar(&costReportUploadURL, "cost-report-upload-url", "",
+		"If set, upload a CSV, a JSON and a FOCUS cost report with HTTP PUT under this object store URL as each period ends")
+	flag.StringVar(&costReportCluster, "cost-report-cluster", "kubernetes",
+		"The cluster's name in cost reports, the billing account of FOCUS reports")
+	flag.IntVar(&maxDefragmentationMigrations, "max-defragmentation-migrations",
+		scheduler.DefaultMaxDefragmentationMigrations, "Maximum pod migrations in one defragmentation recommendation")
+	flag.DurationVar(&optimizationPlanInterval, "optimization-plan-interval", scheduler.DefaultPlanInterval,
+		"How often a node consolidation is proposed as an OptimizationPlan awaiting approval; 0 disables it")
+	flag.DurationVar(&cpuGovernorInterval, "cpu-governor-interval", scheduler.DefaultCPUGovernorInterval,
+		"How often nodes running only workloads with applied CPU frequency recommendations are annotated with "+
+			"the governor for node agents to switch to; 0 disables it")
+	flag.IntVar(&schedulingLaneConcurrency, "scheduling-lane-concurrency", 0,
+		"If positive, schedule each node pool on its own lane with this many concurrent passes and a separate "+
+			"infeasibility cache, so contended GPU pools do not delay CPU-only placements")
+	flag.Float64Var(&usageDeviationPercent, "usage-deviation-percent", 0,
+		"If positive, flag workloads whose cost or power rises more than this percent above their own baseline")
+	flag.DurationVar(&usageBaselineWindow, "usage-baseline-window", optimizer.DefaultBaselineWindow,
+		"Trailing window of usage samples that forms each workload's baseline")
+	flag.StringVar(&historyNamespace, "scheduling-history-namespace", "",
+		"If set, scheduling history, including the usage samples behind workload baselines, is persisted "+
+			"as SchedulingRecords in this namespace so it survives restarts")
+	flag.IntVar(&historyRetention.MaxEvents, "scheduling-history-max-events", 10000,
+		"Maximum number of persisted scheduling history events; 0 disables the count limit")
+	flag.DurationVar(&historyRetention.MaxAge, "scheduling-history-max-age", 7*24*time.Hour,
+		"Persisted scheduling history events older than this are pruned; 0 disables the age limit")
+	flag.DurationVar(&schedulingInitialBackoff, "scheduling-initial-backoff", controller.DefaultSchedulingInitialBackoff,
+		"Delay before retrying a failed scheduling attempt; it doubles with each further failure")
+	flag.DurationVar(&schedulingMaxBackoff, "scheduling-max-backoff", controller.DefaultSchedulingMaxBackoff,
+		"Maximum delay between retries of failed scheduling attempts")
+	flag.Float64Var(&gpuPackingThreshold, "gpu-packing-utilization-threshold", 0,
+		"If positive, pack single-GPU inference workloads whose measured peak GPU utilization (0.0-1.0) stays "+
+			"below this onto shared GPUs; utilization is published by the node agent")
+	flag.Float64Var(&gpuPackingHeadroom, "gpu-packing-headroom", scheduler.DefaultGPUPackingHeadroom,
+		"GPU utilization added to a packed workload's measured peak when sizing its share")
+	flag.DurationVar(&gpuPackingWindow, "gpu-packing-window", scheduler.DefaultGPUPackingWindow,
+		"Trailing window of GPU utilization samples a packing decision is based on")
+	flag.StringVar(&pricingTable, "pricing-table", controller.DefaultPricingTable,
+		"Name of the cluster-scoped PricingTable whose rates resources are priced at")
+	flag.StringVar(&currencyCode, "currency", string(currency.Default),
+		"Currency costs, budgets, savings, metrics and reports are in: USD, EUR or KRW")
+	flag.StringVar(&exchangeRates, "exchange-rates", "",
+		"Units of each currency one US dollar buys, such as EUR=0.92,KRW=1380, used until the exchange rate feed is read")
+	flag.StringVar(&exchangeRateURL, "exchange-rate-url", "",
+		"If set, read exchange rates from this JSON feed of rates against a base currency")
+	flag.DurationVar(&exchangeRateRefresh, "exchange-rate-refresh", currency.DefaultRefresh,
+		"How often the exchange rate feed is read")
+	flag.StringVar(&nodePricingProvider, "node-pricing", "",
+		"If set, price replicas from the on-demand price of their node's instance type in their cloud's "+
+			"price list instead of static rates. A comma separated list of: "+optimizer.NodePricingAWS+", "+
+			optimizer.NodePricingGCP+", "+optimizer.NodePricingAzure)
+	flag.StringVar(&openCostURL, "opencost-url", "",
+		"If set, report running workloads at the realized cost of their pods in this OpenCost API, "+
+			"such as http://opencost.opencost:9003, and record how far estimates are from it")
+	flag.DurationVar(&openCostWindow, "opencost-window", optimizer.DefaultOpenCostWindow,
+		"How far back realized pod cost is averaged from OpenCost")
+	flag.BoolVar(&keplerPower, "kepler", false,
+		"Report running workloads at the power Kepler measured their pods drawing and score nodes by their "+
+			"measured power instead of power-efficiency labels; requires --prometheus-url")
+	flag.DurationVar(&keplerWindow, "kepler-window", optimizer.DefaultKeplerWindow,
+		"How far back measured power is averaged from Kepler")
+	flag.BoolVar(&dcgmUsage, "dcgm", false,
+		"Report the GPU power the DCGM exporter measured for running GPU pods, and pack GPU workloads by the "+
+			"utilization and memory it measured when no node agent reports; requires --prometheus-url")
+	flag.DurationVar(&dcgmWindow, "dcgm-window", optimizer.DefaultDCGMWindow,
+		"How far back GPU usage is averaged from DCGM")
+	flag.DurationVar(&powerModelInterval, "power-model-interval", optimizer.DefaultPowerModelInterval,
+		"How often measured node power and pod usage are sampled to fit a power model per node class, "+
+			"used with --prometheus-url to score the power a placement adds; 0 disables it")
+	flag.DurationVar(&podPowerInterval, "pod-power-interval", optimizer.DefaultPodPowerInterval,
+		"How often measured node power is attributed to running pods by their CPU and GPU usage, "+
+			"used with --prometheus-url to report and annotate per-pod power; 0 disables it")
+	flag.StringVar(&bmcSecret, "bmc-secret", "",
+		"If set, as namespace/name, poll the power of the nodes this secret lists from the Redfish services of "+
+			"their BMCs. Each key is a node name holding its BMC's https url, username, password and optional caBundle "+
+			"as JSON; the "+optimizer.BMCCABundleKey+" key holds CA certificates trusted for every BMC")
+	flag.DurationVar(&bmcInterval, "bmc-interval", optimizer.DefaultBMCInterval,
+		"How often node BMCs are polled for power")
+	flag.StringVar(&nodePricingRegion, "node-pricing-region", "",
+		"Region whose prices apply to nodes without a topology.kubernetes.io/region label")
+	flag.DurationVar(&nodePricingRefresh, "node-pricing-refresh", optimizer.DefaultNodePricingRefresh,
+		"How long fetched instance prices are used before they are fetched again")
+	flag.StringVar(&awsPriceListURL, "aws-price-list-url", optimizer.DefaultAWSPriceListURL,
+		"AWS Price List offer file of EC2 per region, with %s in place of the region code")
+	flag.StringVar(&gcpPriceListURL, "gcp-price-list-url", optimizer.DefaultGCPPriceListURL,
+		"Cloud Billing Catalog API SKU list of Compute Engine")
+	flag.StringVar(&gcpPricingAPIKey, "gcp-pricing-api-key", os.Getenv("GCP_PRICING_API_KEY"),
+		"API key for the Cloud Billing Catalog API, by default from GCP_PRICING_API_KEY")
+	flag.BoolVar(&gcpSustainedUse, "gcp-sustained-use-discount", true,
+		"Apply the sustained use discount of a node running the whole month to GCP on-demand prices")
+	flag.StringVar(&azurePriceListURL, "azure-price-list-url", optimizer.DefaultAzurePriceListURL,
+		"Azure Retail Prices API")
+	flag.StringVar(&spotPriceFeedURL, "spot-price-feed-url", "",
+		"If set, weigh spot nodes for workloads preferring spot by the live prices and interruption rates "+
+			"this feed serves per instance type and zone")
+	flag.DurationVar(&spotPriceInterval, "spot-price-interval", optimizer.DefaultSpotPriceInterval,
+		"How often the spot price feed is read")
+	flag.DurationVar(&spotPriceWindow, "spot-price-window", optimizer.DefaultSpotPriceWindow,
+		"Spot price history the volatility behind interruption risk is measured over")
+	flag.Float64Var(&spotRiskWeight, "spot-risk-weight", 1.0,
+		"Share of a spot node's cost cost-optimized scheduling adds per unit of interruption risk")
+	flag.StringVar(&transferPricingFile, "transfer-pricing", "",
+		"If set, a YAML table of crossZonePerGB and crossRegionPerGB per cloud provider that overrides the "+
+			"list prices charged for the transfer to workloads' data endpoints in other zones and regions")
+	flag.StringVar(&gridSignalFeedURL, "grid-signal-feed-url", "",
+		"If set, hold time-shifted workloads while the grid's carbon intensity or electricity price "+
+			"read from this JSON feed is above their threshold; without it they run at once")
+	flag.DurationVar(&gridSignalInterval, "grid-signal-interval", optimizer.DefaultGridSignalInterval,
+		"How often the grid signal feed is read")
+	flag.StringVar(&renewableForecastFeedURL, "renewable-forecast-feed-url", "",
+		"If set, hold batch and training workloads under PowerPolicies preferring renewable energy until "+
+			"the on-site generation forecast read from this JSON or CSV feed covers their demand")
+	flag.DurationVar(&renewableForecastInterval, "renewable-forecast-interval", optimizer.DefaultRenewableForecastInterval,
+		"How often the renewable generation forecast feed is read")
+	flag.StringVar(&prometheusURL, "prometheus-url", "",
+		"If set, recommend rightsized requests from workload usage queried from this Prometheus server")
+	flag.DurationVar(&rightsizingWindow, "rightsizing-window", optimizer.DefaultRightsizingWindow,
+		"Usage history rightsizing recommendations are based on")
+	flag.Float64Var(&rightsizingHeadroom, "rightsizing-headroom", optimizer.DefaultRightsizingHeadroom,
+		"Fraction added to P95 usage when recommending requests")
+	flag.StringVar(&slaLatencyMetric, "sla-latency-metric", optimizer.DefaultSLALatencyMetric,
+		"Request duration histogram the latency of inference workloads with SLA targets is read from; "+
+			"requires --prometheus-url")
+	flag.DurationVar(&slaWindow, "sla-window", optimizer.DefaultSLAWindow,
+		"Latency history each SLA observation is based on")
+	flag.BoolVar(&deadlineScheduling, "deadline-scheduling", false,
+		"Weigh nodes against the deadlines of batch and training workloads, preferring faster but pricier "+
+			"nodes when a deadline is at risk")
+	flag.BoolVar(&thermalScheduling, "thermal-scheduling", false,
+		"Keep training, batch and GPU workloads off nodes nearing their thermal limits, reading temperatures "+
+			"with --prometheus-url, and spread training workloads across racks labeled "+optimizer.RackLabel)
+	flag.Float64Var(&thermalLimits.CPU, "cpu-thermal-limit", optimizer.DefaultCPUThermalLimit,
+		"Temperature in °C at which node CPUs are assumed to throttle")
+	flag.Float64Var(&thermalLimits.GPU, "gpu-thermal-limit", optimizer.DefaultGPUThermalLimit,
+		"Temperature in °C at which node GPUs are assumed to throttle")
+	flag.Float64Var(&thermalLimits.Margin, "thermal-margin", optimizer.DefaultThermalMargin,
+		"How far in °C below its thermal limit a node stops taking heavy workloads")
+	flag.BoolVar(&reserveDetectedPatterns, "reserve-detected-patterns", true,
+		"Reserve a node ahead of runs predicted from daily or weekly arrival patterns of workloads without "+
+			"a declared recurrence")
+	flag.DurationVar(&idlePeriod, "idle-period", optimizer.DefaultIdlePeriod,
+		"How long a workload's pods must stay under the idle thresholds before it is flagged idle; "+
+			"requires --prometheus-url, 0 disables idle detection")
+	flag.Float64Var(&idleCPUThreshold, "idle-cpu-threshold", optimizer.DefaultIdleCPUThreshold,
+		"CPU usage in cores below which a pod counts as idle")
+	flag.Float64Var(&idleGPUThreshold, "idle-gpu-threshold", optimizer.DefaultIdleGPUThreshold,
+		"GPU utilization in whole GPUs below which a pod counts as idle")
+	flag.StringVar(&locale, "locale", messages.DefaultLocale,
+		"Default language of condition messages and alerts, such as en or ko; workloads may override it "+
+			"with the "+messages.LocaleAnnotation+" annotation")
+	flag.StringVar(&messageCatalog, "message-catalog", "",
+		"Path to a YAML file of message templates per locale that override or extend the built-in catalog")
+	flag.StringVar(&acceleratorRegistryPath, "accelerator-registry", "",
+		"Path to a YAML file mapping accelerator types such as tpu or fpga to extended resources, with the "+
+			"hourly cost and power of one device. Workloads request them under spec.resources.accelerators.")
+	flag.StringVar(&notificationConfig, "notification-config", "",
+		"Path to a YAML file of alert channels and digest schedules. Notifications are disabled when empty.")
+	flag.BoolVar(&manageWebhookConfig, "manage-webhook-configuration", false,
+		"If set, the operator creates the pod MutatingWebhookConfiguration limited to the admission scope below")
+	flag.StringVar(&webhookNamespaceLabel, "webhook-namespace-label", "kcloud.io/optimization=enabled",
+		"Namespace label (key=value) that opts a namespace into pod mutation; empty admits all non-system namespaces")
+	flag.BoolVar(&webhookAcceleratorPodsOnly, "webhook-accelerator-pods-only", true,
+		"If set, only pods requesting GPUs or NPUs are sent to the pod mutating webhook")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "k8s-workload-operator-webhook-service",
+		"Name of the Service fronting the webhook server")
+	flag.StringVar(&webhookServiceNamespace, "webhook-service-namespace", "k8s-workload-operator-system",
+		"Namespace of the Service fronting the webhook server")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	opts := zap.Options{
@@ -158,7 +492,14 @@ func main() {
 		metricsServerOptions.KeyName = metricsCertKey
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	restConfig := ctrl.GetConfigOrDie()
+	var tuner *adaptive.Tuner
+	if adaptiveThrottling {
+		tuner = adaptive.NewTuner(adaptive.DefaultOptions())
+		restConfig = tuner.WrapConfig(restConfig)
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
@@ -182,36 +523,779 @@ func main() {
 		os.Exit(1)
 	}
 
+	if tuner != nil {
+		if err := mgr.Add(tuner); err != nil {
+			setupLog.Error(err, "unable to set up adaptive throttling")
+			os.Exit(1)
+		}
+	}
+
+	// Load the accelerator types workloads may request besides GPUs and NPUs
+	var acceleratorRegistry *optimizer.AcceleratorRegistry
+	if acceleratorRegistryPath != "" {
+		acceleratorRegistry, err = optimizer.LoadAcceleratorRegistry(acceleratorRegistryPath)
+		if err != nil {
+			setupLog.Error(err, "unable to load accelerator registry", "path", acceleratorRegistryPath)
+			os.Exit(1)
+		}
+	}
+
+	// Report costs in the configured currency, converted from the US dollars resources are
+	// priced in
+	reportingCurrency, err := currency.Parse(currencyCode)
+	if err != nil {
+		setupLog.Error(err, "invalid currency")
+		os.Exit(1)
+	}
+	var initialRates currency.Rates
+	if exchangeRates != "" {
+		if initialRates, err = currency.ParseRates(exchangeRates); err != nil {
+			setupLog.Error(err, "invalid exchange rates")
+			os.Exit(1)
+		}
+	}
+	var rateSource currency.RateSource
+	if exchangeRateURL != "" {
+		if rateSource, err = currency.NewExchangeRateFeed(exchangeRateURL); err != nil {
+			setupLog.Error(err, "invalid exchange rate feed")
+			os.Exit(1)
+		}
+	}
+	currencyConverter := currency.NewConverter(reportingCurrency, initialRates, rateSource, exchangeRateRefresh)
+	if rateSource != nil {
+		rateCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := currencyConverter.Refresh(rateCtx); err != nil {
+			setupLog.Error(err, "unable to read exchange rates, using --exchange-rates until the next read")
+		}
+		cancel()
+		if err := mgr.Add(currencyConverter); err != nil {
+			setupLog.Error(err, "unable to set up exchange rates")
+			os.Exit(1)
+		}
+	}
+	if _, ok := currencyConverter.Rate(reportingCurrency); !ok {
+		setupLog.Error(nil, "no exchange rate for the currency, set --exchange-rates or --exchange-rate-url",
+			"currency", reportingCurrency)
+		os.Exit(1)
+	}
+
 	// Initialize optimizer engine and scheduler
 	optimizerEngine := optimizer.NewEngine()
+	optimizerEngine.Currency = currencyConverter
+	optimizerEngine.Accelerators = acceleratorRegistry
+	// Price resources at the rates of the active PricingTable, the defaults without one
+	tablePricing := optimizer.NewTablePricing()
+	optimizerEngine.CostCalculator = tablePricing
+	if paretoWeights != "" {
+		weights, err := optimizer.ParseParetoWeights(paretoWeights)
+		if err != nil {
+			setupLog.Error(err, "invalid pareto weights")
+			os.Exit(1)
+		}
+		optimizerEngine.Pareto = &weights
+	}
+	if optimizationScoreWeights != "" {
+		weights, err := optimizer.ParseOptimizationScoreWeights(optimizationScoreWeights)
+		if err != nil {
+			setupLog.Error(err, "invalid optimization score weights")
+			os.Exit(1)
+		}
+		optimizerEngine.ScoreWeights = &weights
+	}
+	// Price nodes from the pricing table's node prices, then their cloud's live price list,
+	// refreshed in the background
+	onDemandPricing := optimizer.NodePricingChain{tablePricing}
+	if nodePricingProvider != "" {
+		sources, err := optimizer.NewPriceListSources(nodePricingProvider, optimizer.PriceListConfig{
+			AWSURL:          awsPriceListURL,
+			GCPURL:          gcpPriceListURL,
+			GCPAPIKey:       gcpPricingAPIKey,
+			GCPSustainedUse: gcpSustainedUse,
+			AzureURL:        azurePriceListURL,
+		})
+		if err != nil {
+			setupLog.Error(err, "invalid node pricing")
+			os.Exit(1)
+		}
+		cloudPricing := optimizer.NewCloudNodePricing(mgr.GetClient(), sources, nodePricingRefresh)
+		cloudPricing.DefaultRegion = nodePricingRegion
+		if err := mgr.Add(cloudPricing); err != nil {
+			setupLog.Error(err, "unable to set up node pricing")
+			os.Exit(1)
+		}
+		onDemandPricing = append(onDemandPricing, cloudPricing)
+	}
+	// Nodes covered by the pricing table's commitments are priced at their effective rate
+	nodePricing := optimizer.NewCommitmentPricing(mgr.GetClient(), tablePricing, onDemandPricing)
+	if err := mgr.Add(nodePricing); err != nil {
+		setupLog.Error(err, "unable to set up commitment pricing")
+		os.Exit(1)
+	}
+	optimizerEngine.NodePricing = nodePricing
+	// Transfer to data endpoints in other zones and regions is charged at list prices, or at
+	// the rates of the transfer pricing table
+	transferPricing := scheduler.DefaultTransferPricing()
+	if transferPricingFile != "" {
+		if transferPricing, err = scheduler.LoadTransferPricing(transferPricingFile); err != nil {
+			setupLog.Error(err, "unable to load transfer pricing")
+			os.Exit(1)
+		}
+	}
+	optimizerEngine.Transfer = transferPricing
+
+	// Report running workloads at what OpenCost says their pods cost
+	if openCostURL != "" {
+		openCost, err := optimizer.NewOpenCost(openCostURL, openCostWindow)
+		if err != nil {
+			setupLog.Error(err, "invalid opencost url")
+			os.Exit(1)
+		}
+		if err := mgr.Add(openCost); err != nil {
+			setupLog.Error(err, "unable to set up opencost")
+			os.Exit(1)
+		}
+		optimizerEngine.Realized = openCost
+	}
+	// Report running workloads at the power Kepler and DCGM measured
+	var powerMetrics *optimizer.PrometheusUsageSource
+	if keplerPower || dcgmUsage {
+		if prometheusURL == "" {
+			setupLog.Error(nil, "--kepler and --dcgm require --prometheus-url")
+			os.Exit(1)
+		}
+		powerMetrics, err = optimizer.NewPrometheusUsageSource(prometheusURL)
+		if err != nil {
+			setupLog.Error(err, "invalid prometheus url")
+			os.Exit(1)
+		}
+	}
+	var kepler *optimizer.Kepler
+	if keplerPower {
+		kepler = optimizer.NewKepler(powerMetrics, keplerWindow)
+		if err := mgr.Add(kepler); err != nil {
+			setupLog.Error(err, "unable to set up kepler")
+			os.Exit(1)
+		}
+		optimizerEngine.Power = kepler
+	}
+	if dcgmUsage {
+		dcgm := optimizer.NewDCGM(powerMetrics, dcgmWindow)
+		if err := mgr.Add(dcgm); err != nil {
+			setupLog.Error(err, "unable to set up dcgm")
+			os.Exit(1)
+		}
+		optimizerEngine.GPU = dcgm
+	}
+	// Measured node power comes from BMCs first, then Kepler, then RAPL
+	var nodePower optimizer.NodePowerChain
+	if bmcSecret != "" {
+		namespace, name, ok := strings.Cut(bmcSecret, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "--bmc-secret must be namespace/name", "value", bmcSecret)
+			os.Exit(1)
+		}
+		bmcPower := optimizer.NewBMCPower(mgr.GetAPIReader(), types.NamespacedName{Namespace: namespace, Name: name},
+			bmcInterval)
+		if err := mgr.Add(bmcPower); err != nil {
+			setupLog.Error(err, "unable to set up bmc power")
+			os.Exit(1)
+		}
+		nodePower = append(nodePower, bmcPower)
+	}
+	if kepler != nil {
+		nodePower = append(nodePower, kepler)
+	}
+	// Nodes without either fall back to the CPU package power node agents publish from RAPL
+	raplPower := optimizer.NewRAPLPower(mgr.GetClient())
+	if err := mgr.Add(raplPower); err != nil {
+		setupLog.Error(err, "unable to set up rapl power")
+		os.Exit(1)
+	}
+	nodePower = append(nodePower, raplPower)
+	optimizerEngine.NodePower = nodePower
+	// Predict inference latency from per-class profiles, refined once latency is observed
+	slaModel := optimizer.NewSLAModel(nil)
+	slaModel.Window = slaWindow
+	optimizerEngine.SLA = slaModel
 	schedulerInstance := scheduler.NewScheduler()
+	if err := schedulerInstance.SetTransferPricing(transferPricing); err != nil {
+		setupLog.Error(err, "invalid transfer pricing")
+		os.Exit(1)
+	}
+	schedulerInstance.SetSLAModel(slaModel)
+	schedulerInstance.SetDeadlineScheduling(deadlineScheduling)
+	// Keep heavy workloads off hot nodes and spread training across racks
+	if thermalScheduling {
+		var temperatures optimizer.NodeTemperatureSource
+		if prometheusURL != "" {
+			thermalMetrics, err := optimizer.NewPrometheusUsageSource(prometheusURL)
+			if err != nil {
+				setupLog.Error(err, "invalid prometheus url")
+				os.Exit(1)
+			}
+			prometheusTemperature := optimizer.NewPrometheusTemperature(thermalMetrics)
+			if err := mgr.Add(prometheusTemperature); err != nil {
+				setupLog.Error(err, "unable to set up node temperatures")
+				os.Exit(1)
+			}
+			temperatures = prometheusTemperature
+		}
+		schedulerInstance.SetThermalScheduling(temperatures, thermalLimits)
+	}
+	optimizerEngine.Cluster = optimizer.NewClientClusterState(mgr.GetClient())
+	optimizerEngine.Placer = schedulerInstance
+	schedulerInstance.SetAcceleratorRegistry(acceleratorRegistry)
+	schedulerInstance.SetNodePricing(nodePricing)
+	schedulerInstance.SetGPUModelPricing(tablePricing)
+	schedulerInstance.SetCurrency(currencyConverter)
+	schedulerInstance.SetMeasuredPower(nodePower)
+	var podUsage optimizer.PodUsageSource
+	if prometheusURL != "" && (powerModelInterval > 0 || podPowerInterval > 0) {
+		podUsage, err = optimizer.NewPrometheusUsageSource(prometheusURL)
+		if err != nil {
+			setupLog.Error(err, "invalid prometheus url")
+			os.Exit(1)
+		}
+	}
+	// Score power by what placements are predicted to add under models fitted per node class
+	var powerModels *optimizer.NodePowerModels
+	if powerModelInterval > 0 && podUsage != nil {
+		powerModels = optimizer.NewNodePowerModels(mgr.GetClient(), nodePower, podUsage, powerModelInterval)
+		if err := mgr.Add(powerModels); err != nil {
+			setupLog.Error(err, "unable to set up node power models")
+			os.Exit(1)
+		}
+		schedulerInstance.SetPowerModels(powerModels)
+	}
+	// Attribute node power to pods by their usage, for workloads no per-pod source measures
+	if podPowerInterval > 0 && podUsage != nil {
+		podPower := optimizer.NewPodPowerAttribution(mgr.GetClient(), nodePower, podUsage, podPowerInterval)
+		podPower.Models = powerModels
+		if err := mgr.Add(podPower); err != nil {
+			setupLog.Error(err, "unable to set up pod power attribution")
+			os.Exit(1)
+		}
+		optimizerEngine.AttributedPower = podPower
+	}
+	// Weigh spot nodes by their live price and interruption risk
+	if spotPriceFeedURL != "" {
+		feed, err := optimizer.NewSpotPriceFeed(spotPriceFeedURL)
+		if err != nil {
+			setupLog.Error(err, "invalid spot price feed")
+			os.Exit(1)
+		}
+		spotMarket := optimizer.NewSpotMarket(feed, spotPriceInterval, spotPriceWindow)
+		if err := mgr.Add(spotMarket); err != nil {
+			setupLog.Error(err, "unable to set up spot price feed")
+			os.Exit(1)
+		}
+		if err := schedulerInstance.SetSpotMarket(spotMarket, spotRiskWeight); err != nil {
+			setupLog.Error(err, "invalid spot risk weight")
+			os.Exit(1)
+		}
+	}
+	// Forecast the grid's carbon intensity and price for time-shifted workloads
+	var gridForecast *optimizer.GridForecast
+	if gridSignalFeedURL != "" {
+		feed, err := optimizer.NewGridSignalFeed(gridSignalFeedURL)
+		if err != nil {
+			setupLog.Error(err, "invalid grid signal feed")
+			os.Exit(1)
+		}
+		gridForecast = optimizer.NewGridForecast(feed, gridSignalInterval)
+		if err := mgr.Add(gridForecast); err != nil {
+			setupLog.Error(err, "unable to set up grid signal feed")
+			os.Exit(1)
+		}
+	}
+	// Forecast on-site renewable generation for workloads under a renewable preference
+	var renewableForecast *optimizer.RenewableForecast
+	if renewableForecastFeedURL != "" {
+		feed, err := optimizer.NewRenewableForecastFeed(renewableForecastFeedURL)
+		if err != nil {
+			setupLog.Error(err, "invalid renewable forecast feed")
+			os.Exit(1)
+		}
+		renewableForecast = optimizer.NewRenewableForecast(feed, renewableForecastInterval)
+		if err := mgr.Add(renewableForecast); err != nil {
+			setupLog.Error(err, "unable to set up renewable forecast feed")
+			os.Exit(1)
+		}
+	}
+	weights, err := scheduler.ParseScoreWeights(scoreWeights)
+	if err != nil {
+		setupLog.Error(err, "invalid score weights")
+		os.Exit(1)
+	}
+	if err := schedulerInstance.SetScoreWeights(weights); err != nil {
+		setupLog.Error(err, "invalid score weights")
+		os.Exit(1)
+	}
+
+	overcommitPolicy, err := scheduler.ParseOvercommitPolicy(overcommitRatios)
+	if err != nil {
+		setupLog.Error(err, "invalid overcommit ratios")
+		os.Exit(1)
+	}
+	if err := schedulerInstance.SetOvercommitPolicy(overcommitPolicy); err != nil {
+		setupLog.Error(err, "invalid overcommit ratios")
+		os.Exit(1)
+	}
+
+	localityWeights, err := scheduler.ParseImageLocalityWeights(imageLocalityWeights)
+	if err != nil {
+		setupLog.Error(err, "invalid image locality weights")
+		os.Exit(1)
+	}
+	if err := schedulerInstance.SetImageLocalityWeights(localityWeights); err != nil {
+		setupLog.Error(err, "invalid image locality weights")
+		os.Exit(1)
+	}
+	schedulerInstance.SetCacheTransferCost(cacheTransferCostPerGB)
+	schedulerInstance.SetPlacementAnalysis(placementAnalysis)
+	if err := schedulerInstance.SetStickiness(stickinessBonus, stickinessHysteresis); err != nil {
+		setupLog.Error(err, "invalid node stickiness settings")
+		os.Exit(1)
+	}
+	schedulerInstance.SetPoolLanes(schedulingLaneConcurrency)
+
+	// Forget cached pool infeasibility when nodes join, leave or change
+	if err := schedulerInstance.InvalidateOnNodeChanges(context.Background(), mgr.GetCache()); err != nil {
+		setupLog.Error(err, "unable to watch nodes for scheduler cache invalidation")
+		os.Exit(1)
+	}
+
+	// Keep a shared node and pod snapshot so scheduling does not re-list every reconcile
+	clusterSnapshot := scheduler.NewClusterSnapshot()
+	if err := clusterSnapshot.Watch(context.Background(), mgr.GetCache()); err != nil {
+		setupLog.Error(err, "unable to watch nodes and pods for the scheduling snapshot")
+		os.Exit(1)
+	}
+	schedulerInstance.SetSnapshot(clusterSnapshot)
+
+	// Measure fragmentation and recommend migrations that reduce it
+	var fragmentationMonitor *scheduler.FragmentationMonitor
+	if fragmentationInterval > 0 {
+		fragmentationMonitor = scheduler.NewFragmentationMonitor(clusterSnapshot, mgr.GetClient(),
+			fragmentationInterval, maxDefragmentationMigrations)
+		if err := mgr.Add(fragmentationMonitor); err != nil {
+			setupLog.Error(err, "unable to set up fragmentation monitor")
+			os.Exit(1)
+		}
+	}
+
+	// Propose node consolidations as plans applied step by step once approved
+	consolidationPlanner := scheduler.NewConsolidationPlanner(clusterSnapshot, mgr.GetClient())
+	consolidationPlanner.SetPricing(tablePricing)
+	consolidationPlanner.SetNodePricing(nodePricing)
+	consolidationPlanner.SetCurrency(currencyConverter)
+	if optimizationPlanInterval > 0 {
+		if err := mgr.Add(scheduler.NewPlanEmitter(consolidationPlanner, mgr.GetClient(), optimizationPlanInterval)); err != nil {
+			setupLog.Error(err, "unable to set up optimization plan emitter")
+			os.Exit(1)
+		}
+	}
+
+	// Request lower CPU frequency governors on nodes running only throughput-oriented workloads
+	if cpuGovernorInterval > 0 {
+		governorPublisher := scheduler.NewCPUGovernorPublisher(mgr.GetClient(), cpuGovernorInterval)
+		if err := governorPublisher.WatchPods(context.Background(), mgr.GetCache()); err != nil {
+			setupLog.Error(err, "unable to watch pods for CPU governors")
+			os.Exit(1)
+		}
+		if err := mgr.Add(governorPublisher); err != nil {
+			setupLog.Error(err, "unable to set up CPU governor publisher")
+			os.Exit(1)
+		}
+	}
 
 	// Initialize metrics collector
 	metricsCollector := metrics.NewMetricsCollector()
 	systemMetricsCollector := metrics.NewSystemMetricsCollector(mgr.GetClient(), metricsCollector)
+	systemMetricsCollector.SetCurrency(currencyConverter)
+	systemMetricsCollector.SetNodePower(nodePower)
 
 	// Start metrics collection
 	go metricsCollector.StartMetricsCollection(ctrl.SetupSignalHandler())
 	go systemMetricsCollector.StartPeriodicCollection(ctrl.SetupSignalHandler())
 
+	// Open the decision journal
+	var decisionJournal *journal.Journal
+	if journalPath != "" {
+		decisionJournal, err = journal.Open(journalPath)
+		if err != nil {
+			setupLog.Error(err, "unable to open decision journal", "path", journalPath)
+			os.Exit(1)
+		}
+		if err := mgr.Add(journal.NewCompactor(decisionJournal, journalRetention, time.Hour)); err != nil {
+			setupLog.Error(err, "unable to set up decision journal compaction")
+			os.Exit(1)
+		}
+	}
+
+	// Persist scheduling history
+	var historyStore *scheduler.CRDHistoryStore
+	if historyNamespace != "" {
+		historyStore = scheduler.NewCRDHistoryStore(mgr.GetClient(), historyNamespace, historyRetention)
+		if err := mgr.Add(historyStore); err != nil {
+			setupLog.Error(err, "unable to set up scheduling history")
+			os.Exit(1)
+		}
+	}
+
+	// Track per-workload usage baselines
+	var usageHistory *optimizer.UsageHistory
+	if usageDeviationPercent > 0 {
+		usageHistory = optimizer.NewUsageHistory(usageDeviationPercent)
+		usageHistory.Window = usageBaselineWindow
+		if historyStore != nil {
+			usageHistory.SetStore(scheduler.HistoryUsageStore{History: historyStore})
+		}
+	}
+
+	// Pack low-utilization inference workloads onto shared GPUs
+	var gpuPacker *scheduler.GPUPacker
+	if gpuPackingThreshold > 0 {
+		gpuPacker = scheduler.NewGPUPacker(gpuPackingThreshold)
+		gpuPacker.Headroom = gpuPackingHeadroom
+		gpuPacker.Window = gpuPackingWindow
+	}
+
+	// Recommend requests from the usage of workloads' pods, flag idle workloads and observe latency
+	var rightsizer *optimizer.Rightsizer
+	var idleDetector *optimizer.IdleDetector
+	var usageSource *optimizer.PrometheusUsageSource
+	var transmitSource optimizer.TransmitSource
+	if prometheusURL != "" {
+		usageSource, err = optimizer.NewPrometheusUsageSource(prometheusURL)
+		if err != nil {
+			setupLog.Error(err, "invalid prometheus url")
+			os.Exit(1)
+		}
+		usageSource.LatencyMetric = slaLatencyMetric
+		slaModel.Source = usageSource
+		transmitSource = usageSource
+		rightsizer = optimizer.NewRightsizer(usageSource)
+		rightsizer.Currency = currencyConverter
+		rightsizer.Window = rightsizingWindow
+		rightsizer.Headroom = rightsizingHeadroom
+		if idlePeriod > 0 {
+			idleDetector = optimizer.NewIdleDetector(usageSource)
+			idleDetector.Period = idlePeriod
+			idleDetector.CPUThreshold = idleCPUThreshold
+			idleDetector.GPUThreshold = idleGPUThreshold
+		}
+	}
+
+	// Render user-facing messages in the configured language
+	var catalogOverrides map[string]map[messages.ID]string
+	if messageCatalog != "" {
+		catalogOverrides, err = messages.LoadOverrides(messageCatalog)
+		if err != nil {
+			setupLog.Error(err, "unable to load message catalog", "path", messageCatalog)
+			os.Exit(1)
+		}
+	}
+	catalog, err := messages.NewCatalog(locale, reportingCurrency, catalogOverrides)
+	if err != nil {
+		setupLog.Error(err, "invalid message catalog")
+		os.Exit(1)
+	}
+
+	// Route alerts to team channels, batching non-critical ones into digests
+	var notifier *notify.DigestScheduler
+	if notificationConfig != "" {
+		cfg, err := notify.LoadConfig(notificationConfig)
+		if err != nil {
+			setupLog.Error(err, "unable to load notification config", "path", notificationConfig)
+			os.Exit(1)
+		}
+		notifier, err = notify.NewDigestScheduler(cfg)
+		if err != nil {
+			setupLog.Error(err, "unable to set up notification channels")
+			os.Exit(1)
+		}
+		notifier.SetCatalog(catalog)
+		if err := mgr.Add(notifier); err != nil {
+			setupLog.Error(err, "unable to set up notification digests")
+			os.Exit(1)
+		}
+	}
+
+	// Project cost policy spend to the end of the month
+	if costForecastInterval > 0 {
+		budgetForecaster := forecast.NewBudgetForecaster(mgr.GetClient(), costForecastInterval)
+		budgetForecaster.Notifier = notifier
+		budgetForecaster.Messages = catalog
+		budgetForecaster.Currency = currencyConverter
+		if err := mgr.Add(budgetForecaster); err != nil {
+			setupLog.Error(err, "unable to set up cost forecasting")
+			os.Exit(1)
+		}
+	}
+
+	// Track carbon policy emissions against their budgets
+	if carbonPolicyInterval > 0 {
+		carbonAccountant := forecast.NewCarbonAccountant(mgr.GetClient(), carbonPolicyInterval)
+		carbonAccountant.Notifier = notifier
+		carbonAccountant.Messages = catalog
+		if err := mgr.Add(carbonAccountant); err != nil {
+			setupLog.Error(err, "unable to set up carbon accounting")
+			os.Exit(1)
+		}
+	}
+
+	// Accrue realized cost and energy for chargeback reports
+	var chargebackLedger *chargeback.Ledger
+	if chargebackInterval > 0 {
+		chargebackLedger = chargeback.NewLedger(mgr.GetClient(), chargebackInterval, chargebackRetention)
+		chargebackLedger.SetCurrency(currencyConverter)
+		if err := mgr.Add(chargebackLedger); err != nil {
+			setupLog.Error(err, "unable to set up chargeback")
+			os.Exit(1)
+		}
+	}
+
+	// Trade reserved capacity between teams
+	var marketplace *scheduler.Marketplace
+	if enableCapacityMarketplace {
+		marketplace = scheduler.NewMarketplace(mgr.GetClient(), decisionJournal)
+		schedulerInstance.SetMarketplace(marketplace)
+		if err := mgr.Add(marketplace); err != nil {
+			setupLog.Error(err, "unable to set up capacity marketplace")
+			os.Exit(1)
+		}
+	}
+
+	// Allocate node cost to the pods on each node, by namespace and label
+	var costAllocator *allocation.Allocator
+	if costAllocationInterval > 0 {
+		basis, err := allocation.ParseBasis(costAllocationBasis)
+		if err != nil {
+			setupLog.Error(err, "invalid cost allocation basis")
+			os.Exit(1)
+		}
+		costAllocator = allocation.NewAllocator(mgr.GetClient(), tablePricing, nodePricing,
+			costAllocationInterval, costAllocationRetention)
+		costAllocator.SetCurrency(currencyConverter)
+		costAllocator.Basis = basis
+		if usageSource != nil {
+			costAllocator.Usage = usageSource
+		}
+		for key := range strings.SplitSeq(costAllocationLabels, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				costAllocator.LabelKeys = append(costAllocator.LabelKeys, key)
+			}
+		}
+		if err := mgr.Add(costAllocator); err != nil {
+			setupLog.Error(err, "unable to set up cost allocation")
+			os.Exit(1)
+		}
+	}
+
+	// Report workload, namespace and node cost to finance, written as each period ends
+	costReports := &costreport.Generator{Cluster: costReportCluster}
+	if chargebackLedger != nil {
+		costReports.Chargeback = chargebackLedger
+	}
+	if costAllocator != nil {
+		costReports.Allocation = costAllocator
+	}
+	if costReportDir != "" || costReportUploadURL != "" {
+		period, err := costreport.ParsePeriod(costReportPeriod)
+		if err != nil {
+			setupLog.Error(err, "invalid cost report period")
+			os.Exit(1)
+		}
+		if costReports.Chargeback == nil && costReports.Allocation == nil {
+			setupLog.Error(nil, "cost reports require chargeback or cost allocation to be enabled")
+			os.Exit(1)
+		}
+		var sinks []costreport.Sink
+		if costReportDir != "" {
+			sink, err := costreport.NewDirectorySink(costReportDir)
+			if err != nil {
+				setupLog.Error(err, "unable to set up cost report directory", "path", costReportDir)
+				os.Exit(1)
+			}
+			sinks = append(sinks, sink)
+		}
+		if costReportUploadURL != "" {
+			sink, err := costreport.NewHTTPSink(costReportUploadURL)
+			if err != nil {
+				setupLog.Error(err, "invalid cost report upload url")
+				os.Exit(1)
+			}
+			sinks = append(sinks, sink)
+		}
+		if err := mgr.Add(costreport.NewExporter(costReports, period, sinks...)); err != nil {
+			setupLog.Error(err, "unable to set up cost reports")
+			os.Exit(1)
+		}
+	}
+
 	// Setup WorkloadOptimizer controller
 	if err = (&controller.WorkloadOptimizerReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		Optimizer:         optimizerEngine,
+		Scheduler:         schedulerInstance,
+		Metrics:           metricsCollector,
+		Journal:           decisionJournal,
+		Tuner:             tuner,
+		UsageHistory:      usageHistory,
+		GPUPacker:         gpuPacker,
+		Recorder:          mgr.GetEventRecorderFor("workloadoptimizer-controller"),
+		SchedulingBackoff: flowcontrol.NewBackOff(schedulingInitialBackoff, schedulingMaxBackoff),
+		Notifier:          notifier,
+		Messages:          catalog,
+		Rightsizer:        rightsizer,
+		IdleDetector:      idleDetector,
+		SLA:               slaModel,
+		Transmit:          transmitSource,
+		GridForecast:      gridForecast,
+		RenewableForecast: renewableForecast,
+
+		ReserveDetectedPatterns: reserveDetectedPatterns,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "WorkloadOptimizer")
+		os.Exit(1)
+	}
+
+	// Setup PricingTable controller
+	if err = (&controller.PricingTableReconciler{
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
-		Optimizer: optimizerEngine,
-		Scheduler: schedulerInstance,
-		Metrics:   metricsCollector,
+		Pricing:   tablePricing,
+		TableName: pricingTable,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "WorkloadOptimizer")
+		setupLog.Error(err, "unable to create controller", "controller", "PricingTable")
+		os.Exit(1)
+	}
+
+	// Setup NodePowerProfile controller
+	if err = (&controller.NodePowerProfileReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NodePowerProfile")
+		os.Exit(1)
+	}
+
+	// Setup PowerPolicy controller
+	if err = (&controller.PowerPolicyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("powerpolicy-controller"),
+		Messages: catalog,
+		Interval: powerPolicyInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PowerPolicy")
+		os.Exit(1)
+	}
+
+	// Setup OptimizationPlan controller
+	if err = (&controller.OptimizationPlanReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("optimizationplan-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OptimizationPlan")
 		os.Exit(1)
 	}
 
 	// Setup webhooks
+	validator := kcloudwebhook.NewWorkloadOptimizerValidator(mgr.GetClient())
+	validator.Accelerators = acceleratorRegistry
+	validator.Currency = currencyConverter
 	mgr.GetWebhookServer().Register("/validate-kcloud-io-v1alpha1-workloadoptimizer",
-		&webhook.Admission{Handler: kcloudwebhook.NewWorkloadOptimizerValidator(mgr.GetClient())})
+		&webhook.Admission{Handler: validator})
+
+	podMutator := kcloudwebhook.NewPodMutator(mgr.GetClient())
+	podMutator.Accelerators = acceleratorRegistry
+	podMutator.Pricing = tablePricing
+	podMutator.Currency = currencyConverter
+	podMutator.Messages = catalog
+	mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{Handler: podMutator})
 
-	mgr.GetWebhookServer().Register("/mutate-v1-pod",
-		&webhook.Admission{Handler: kcloudwebhook.NewPodMutator(mgr.GetClient())})
+	if manageWebhookConfig {
+		webhookConfig := kcloudwebhook.NewWebhookConfig(mgr.GetClient(), mgr.GetScheme())
+		webhookConfig.Scope.AcceleratorPodsOnly = webhookAcceleratorPodsOnly
+		webhookConfig.Scope.AcceleratorResources = acceleratorRegistry.ResourceNames()
+		if err := webhookConfig.Scope.ParseNamespaceLabel(webhookNamespaceLabel); err != nil {
+			setupLog.Error(err, "invalid webhook namespace label")
+			os.Exit(1)
+		}
+		webhookConfig.ServiceName = webhookServiceName
+		if len(webhookCertPath) > 0 {
+			caBundle, err := os.ReadFile(filepath.Join(webhookCertPath, "ca.crt"))
+			if err != nil {
+				setupLog.Error(err, "unable to read webhook CA bundle")
+				os.Exit(1)
+			}
+			webhookConfig.CABundle = caBundle
+		}
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return webhookConfig.CreateWebhookConfiguration(ctx, webhookServiceNamespace)
+		})); err != nil {
+			setupLog.Error(err, "unable to set up webhook configuration")
+			os.Exit(1)
+		}
+	}
+
+	// Setup REST API server
+	if apiAddr != "0" {
+		apiServer := apiserver.NewServer(apiAddr, mgr.GetClient(), mgr.GetConfig(), mgr.GetScheme())
+		apiServer.CertDir = apiCertPath
+		apiServer.CertName = apiCertName
+		apiServer.KeyName = apiCertKey
+		apiServer.ClientCAFile = apiClientCAFile
+		apiServer.TLSOpts = tlsOpts
+		if enableSchedulerExtender {
+			if err := scheduler.IndexPodsByNode(context.Background(), mgr.GetFieldIndexer()); err != nil {
+				setupLog.Error(err, "unable to index pods by node")
+				os.Exit(1)
+			}
+			apiServer.EnableSchedulerExtender(schedulerInstance)
+		}
+		apiServer.EnableAlgorithmStatistics(schedulerInstance)
+		apiServer.EnableDatasetCacheStatistics(schedulerInstance)
+		apiServer.EnableExplain(schedulerInstance)
+		apiServer.EnableSimulation(optimizerEngine)
+		if fragmentationMonitor != nil {
+			apiServer.EnableDefragmentationPlan(fragmentationMonitor)
+		}
+		apiServer.EnableConsolidationPlan(consolidationPlanner)
+		if chargebackLedger != nil {
+			apiServer.EnableChargeback(chargebackLedger, currencyConverter)
+		}
+		if costAllocator != nil {
+			apiServer.EnableCostAllocation(costAllocator)
+		}
+		if marketplace != nil {
+			apiServer.EnableMarketplace(marketplace)
+		}
+		if costReports.Chargeback != nil || costReports.Allocation != nil {
+			apiServer.EnableCostReport(costReports)
+		}
+		if enablePurgeAPI {
+			var targets []retention.Target
+			if chargebackLedger != nil {
+				targets = append(targets, chargebackLedger)
+			}
+			if costAllocator != nil {
+				targets = append(targets, costAllocator)
+			}
+			if historyStore != nil {
+				targets = append(targets, historyStore)
+			}
+			apiServer.EnablePurge(retention.NewPurger(decisionJournal, targets...))
+		}
+		if err := mgr.Add(apiServer); err != nil {
+			setupLog.Error(err, "unable to set up API server")
+			os.Exit(1)
+		}
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// checkArrivalPattern records the runs started by the workload's pods and looks for a
+// daily or weekly pattern in them. Workloads without a declared recurrence get the pattern
+// as an OptimizationRecommendation; failures to publish it never fail the reconcile.
+func (r *WorkloadOptimizerReconciler) checkArrivalPattern(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	pods []corev1.Pod) {
+	var arrivals []time.Time
+	if wo.Status.ArrivalPattern != nil {
+		for _, arrival := range wo.Status.ArrivalPattern.Arrivals {
+			arrivals = append(arrivals, arrival.Time)
+		}
+	}
+	created := make([]time.Time, 0, len(pods))
+	for _, pod := range pods {
+		created = append(created, pod.CreationTimestamp.Time)
+	}
+	arrivals = optimizer.RecordArrivals(arrivals, created)
+	if len(arrivals) == 0 {
+		return
+	}
+
+	status := &kcloudv1alpha1.ArrivalPattern{Arrivals: make([]metav1.Time, len(arrivals))}
+	for i, arrival := range arrivals {
+		status.Arrivals[i] = metav1.NewTime(arrival)
+	}
+	pattern, found := optimizer.DetectPattern(arrivals, time.Now())
+	if found {
+		nextArrival := metav1.NewTime(pattern.NextArrival)
+		status.Period = pattern.Period
+		status.NextArrival = &nextArrival
+		status.Jitter = &metav1.Duration{Duration: pattern.Jitter}
+		status.Occurrences = int32(pattern.Occurrences)
+	}
+	wo.Status.ArrivalPattern = status
+
+	if !found || wo.Spec.Recurrence != nil {
+		return
+	}
+	if err := r.publishRecurrenceRecommendation(ctx, wo, pattern, arrivals); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to publish recurrence recommendation")
+	}
+}
+
+// publishRecurrenceRecommendation creates or updates the workload's recurrence
+// OptimizationRecommendation, owned by the WorkloadOptimizer so it is removed with it.
+// It only changes when a run is recorded, so it is not rewritten on every reconcile.
+func (r *WorkloadOptimizerReconciler) publishRecurrenceRecommendation(ctx context.Context,
+	wo *kcloudv1alpha1.WorkloadOptimizer, pattern optimizer.RecurringPattern, arrivals []time.Time) error {
+	object := &kcloudv1alpha1.OptimizationRecommendation{
+		ObjectMeta: metav1.ObjectMeta{Name: wo.Name + "-recurrence", Namespace: wo.Namespace},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, object, func() error {
+		object.Spec = kcloudv1alpha1.OptimizationRecommendationSpec{
+			WorkloadOptimizer: wo.Name,
+			Type:              kcloudv1alpha1.RecommendationTypeRecurrence,
+			Recurrence:        pattern.Recurrence(),
+			Window:            arrivals[len(arrivals)-1].Sub(arrivals[0]).String(),
+			Confidence:        pattern.Confidence(),
+			GeneratedAt:       metav1.NewTime(arrivals[len(arrivals)-1]),
+		}
+		return controllerutil.SetControllerReference(wo, object, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply optimization recommendation %s: %w", object.Name, err)
+	}
+
+	log.FromContext(ctx).V(1).Info("Recurrence recommendation applied", "name", object.Name, "result", result,
+		"period", pattern.Period, "nextArrival", pattern.NextArrival, "occurrences", pattern.Occurrences)
+	return nil
+}
+
+// recurrence returns the workload's declared run schedule or, when reservations for
+// detected patterns are on, the schedule of its detected arrival pattern
+func (r *WorkloadOptimizerReconciler) recurrence(wo *kcloudv1alpha1.WorkloadOptimizer) *kcloudv1alpha1.RecurrenceSpec {
+	if wo.Spec.Recurrence != nil || !r.ReserveDetectedPatterns {
+		return wo.Spec.Recurrence
+	}
+	return optimizer.DetectedRecurrence(wo.Status.ArrivalPattern)
+}
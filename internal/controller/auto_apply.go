@@ -0,0 +1,222 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+//+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
+
+// appliedReplicasAnnotation records on a scaled controller the recommended replica count the
+// operator last applied to it
+const appliedReplicasAnnotation = "kcloud.io/applied-replicas"
+
+// applyRecommendations applies the workload's rightsizing and SLA replica count when the
+// first covering CostPolicy with autoApply settings accepts them at their confidence.
+// Failures are logged; they never fail the reconcile.
+func (r *WorkloadOptimizerReconciler) applyRecommendations(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	result *optimizer.OptimizationResult, pods []corev1.Pod) {
+	rightsizing, sla := wo.Status.Rightsizing, result.SLA
+	if rightsizing == nil && sla == nil {
+		return
+	}
+	logger := log.FromContext(ctx)
+	policy, err := r.autoApplyPolicy(ctx, wo)
+	if err != nil {
+		logger.Error(err, "Failed to get auto-apply policy")
+		return
+	}
+
+	if rightsizing != nil && autoApplies(policy, kcloudv1alpha1.AutoApplyRightsizing, rightsizing.Confidence) {
+		if err := r.applyRightsizing(ctx, wo, rightsizing, len(pods)); err != nil {
+			logger.Error(err, "Failed to apply rightsizing recommendation")
+		}
+	}
+	// Suspended workloads stay scaled to zero until their pods come back, and power-capped
+	// ones stay scaled down until their PowerPolicy restores them
+	if sla != nil && wo.Status.Phase != phaseSuspended && !powerCapped(wo) &&
+		autoApplies(policy, kcloudv1alpha1.AutoApplyReplicas, sla.Confidence) {
+		if err := r.applyReplicas(ctx, wo, result.RecommendedReplicas, sla.Confidence, pods); err != nil {
+			logger.Error(err, "Failed to apply recommended replicas")
+		}
+	}
+}
+
+// autoApplyPolicy returns the autoApply settings of the first CostPolicy by name that
+// covers the workload and sets them, or nil
+func (r *WorkloadOptimizerReconciler) autoApplyPolicy(ctx context.Context,
+	wo *kcloudv1alpha1.WorkloadOptimizer) (*kcloudv1alpha1.AutoApplyPolicy, error) {
+	policies, err := r.coveringCostPolicies(ctx, wo)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if policy.Spec.AutoApply != nil {
+			return policy.Spec.AutoApply, nil
+		}
+	}
+	return nil, nil
+}
+
+// autoApplies reports whether the policy applies recommendations of the kind at the confidence
+func autoApplies(policy *kcloudv1alpha1.AutoApplyPolicy, kind string, confidence float64) bool {
+	if policy == nil || confidence < policy.MinConfidence {
+		return false
+	}
+	return len(policy.Types) == 0 || slices.Contains(policy.Types, kind)
+}
+
+// applyRightsizing sets the workload's resources to the recommended requests; the pod
+// mutator gives them to the pods created from then on. What the requests save the running
+// pods is recorded in the workload's savings.
+func (r *WorkloadOptimizerReconciler) applyRightsizing(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	rightsizing *kcloudv1alpha1.Rightsizing, replicas int) error {
+	recommended := rightsizing.Recommended
+	applied := false
+	err := r.updateWithRetry(ctx, wo, func(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+		resources := &wo.Spec.Resources
+		if resources.CPU == recommended.CPU && resources.Memory == recommended.Memory &&
+			resources.GPU == recommended.GPU {
+			return false
+		}
+		resources.CPU, resources.Memory, resources.GPU = recommended.CPU, recommended.Memory, recommended.GPU
+		applied = true
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update resources: %w", err)
+	}
+	if !applied {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Applied rightsizing recommendation", "cpu", recommended.CPU,
+		"memory", recommended.Memory, "gpu", recommended.GPU, "confidence", rightsizing.Confidence)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(wo, corev1.EventTypeNormal, "RecommendationApplied",
+			"Applied rightsizing to cpu %s, memory %s and %d GPUs at confidence %.2f",
+			recommended.CPU, recommended.Memory, recommended.GPU, rightsizing.Confidence)
+	}
+	if err := r.recordRightsizingSavings(ctx, wo, rightsizing, replicas); err != nil {
+		return fmt.Errorf("failed to record rightsizing savings: %w", err)
+	}
+	return nil
+}
+
+// applyReplicas scales the Deployments, StatefulSets and ReplicaSets of the workload's pods
+// to the replica count when it differs from the count last applied to them, so a count
+// changed by hand is only overridden by a new recommendation. Controllers a
+// HorizontalPodAutoscaler scales and pods of other controllers are left alone; Jobs run to
+// completion, so their parallelism is not changed.
+func (r *WorkloadOptimizerReconciler) applyReplicas(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	replicas int32, confidence float64, pods []corev1.Pod) error {
+	autoscaled, err := autoscaledTargets(ctx, r.Client, wo.Namespace)
+	if err != nil {
+		return err
+	}
+	applied := strconv.Itoa(int(replicas))
+	owners := make(map[types.UID]bool)
+	seen := make(map[string]bool)
+	for i := range pods {
+		// Each owner is resolved to its top-level controller once
+		owner := metav1.GetControllerOf(&pods[i])
+		if owner == nil || (owner.Kind != "ReplicaSet" && owner.Kind != "StatefulSet") || owners[owner.UID] {
+			continue
+		}
+		owners[owner.UID] = true
+		controller, err := podController(ctx, r.Client, &pods[i])
+		if err != nil {
+			return err
+		}
+		key := controller.kind + "/" + controller.object.GetName()
+		if seen[key] || autoscaled[key] {
+			continue
+		}
+		seen[key] = true
+		if controller.object.GetAnnotations()[appliedReplicasAnnotation] == applied {
+			continue
+		}
+
+		original := controller.object.DeepCopyObject().(client.Object)
+		var current **int32
+		switch o := controller.object.(type) {
+		case *appsv1.Deployment:
+			current = &o.Spec.Replicas
+		case *appsv1.StatefulSet:
+			current = &o.Spec.Replicas
+		case *appsv1.ReplicaSet:
+			current = &o.Spec.Replicas
+		default:
+			continue
+		}
+		previous := ptr.Deref(*current, 1)
+		*current = ptr.To(replicas)
+		annotations := controller.object.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[appliedReplicasAnnotation] = applied
+		controller.object.SetAnnotations(annotations)
+		if err := r.Patch(ctx, controller.object, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to scale %s %s/%s to %d replicas: %w", controller.kind, wo.Namespace,
+				controller.object.GetName(), replicas, err)
+		}
+		if previous == replicas {
+			continue
+		}
+
+		log.FromContext(ctx).Info("Applied recommended replicas", "kind", controller.kind,
+			"name", controller.object.GetName(), "from", previous, "to", replicas, "confidence", confidence)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(wo, corev1.EventTypeNormal, "RecommendationApplied",
+				"Scaled %s %s from %d to %d replicas at confidence %.2f", controller.kind,
+				controller.object.GetName(), previous, replicas, confidence)
+		}
+	}
+	return nil
+}
+
+// autoscaledTargets returns the controllers, as kind/name, that HorizontalPodAutoscalers in
+// the namespace scale
+func autoscaledTargets(ctx context.Context, c client.Reader, namespace string) (map[string]bool, error) {
+	var autoscalers autoscalingv2.HorizontalPodAutoscalerList
+	if err := c.List(ctx, &autoscalers, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list horizontal pod autoscalers in %s: %w", namespace, err)
+	}
+	targets := make(map[string]bool, len(autoscalers.Items))
+	for _, autoscaler := range autoscalers.Items {
+		target := autoscaler.Spec.ScaleTargetRef
+		targets[target.Kind+"/"+target.Name] = true
+	}
+	return targets, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// bindBackoff bounds how long a write keeps retrying resourceVersion conflicts
+var bindBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 20 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.2,
+	Cap:      time.Second,
+}
+
+// commitStatus writes the desired status guarded by the resourceVersion it was computed
+// from. On a conflict it re-reads the workload: a node bound by another writer since
+// observedNode was read is adopted rather than overwritten, with reestimate, if set,
+// recomputing the status for it, and a status that is already in place is not written
+// again. It reports whether this call changed the stored status.
+func (r *WorkloadOptimizerReconciler) commitStatus(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	observedNode *string, reestimate func(status *kcloudv1alpha1.WorkloadOptimizerStatus, node string)) (bool, error) {
+	log := log.FromContext(ctx)
+	desired := wo.Status.DeepCopy()
+	applied := false
+
+	err := retry.OnError(bindBackoff, apierrors.IsConflict, func() error {
+		err := r.applyStatus(ctx, wo)
+		if err == nil {
+			applied = true
+			return nil
+		}
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+
+		var latest kcloudv1alpha1.WorkloadOptimizer
+		if getErr := r.Get(ctx, client.ObjectKeyFromObject(wo), &latest); getErr != nil {
+			return getErr
+		}
+
+		wo.ResourceVersion = latest.ResourceVersion
+		wo.Status = *desired.DeepCopy()
+		if boundElsewhere(latest.Status.AssignedNode, observedNode) {
+			node := *latest.Status.AssignedNode
+			log.Info("Workload was bound by another writer, keeping its node", "node", node)
+			if reestimate != nil && (wo.Status.AssignedNode == nil || *wo.Status.AssignedNode != node) {
+				reestimate(&wo.Status, node)
+			}
+			wo.Status.AssignedNode = &node
+		}
+		if !statusChanged(&latest.Status, &wo.Status) {
+			// A replayed or concurrent reconcile already stored this decision
+			wo.Status = latest.Status
+			return nil
+		}
+		return err
+	})
+	return applied, err
+}
+
+// boundElsewhere reports whether the stored node differs from the one observed before
+// scheduling, meaning another writer bound the workload in between
+func boundElsewhere(stored, observed *string) bool {
+	if stored == nil || *stored == "" {
+		return false
+	}
+	return observed == nil || *stored != *observed
+}
+
+// updateWithRetry applies mutate and updates the workload, re-reading and re-applying on
+// resourceVersion conflicts; mutate returns false when no update is needed
+func (r *WorkloadOptimizerReconciler) updateWithRetry(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	mutate func(*kcloudv1alpha1.WorkloadOptimizer) bool) error {
+	first := true
+	return retry.OnError(bindBackoff, apierrors.IsConflict, func() error {
+		if !first {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(wo), wo); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		if !mutate(wo) {
+			return nil
+		}
+		return r.Update(ctx, wo)
+	})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/notify"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// conditionCarbonWithinBudget reports whether the workload's projected monthly emissions
+// fit its carbon budget
+const conditionCarbonWithinBudget = "CarbonWithinBudget"
+
+// checkCarbonBudget accrues the workload's emissions for the month at the carbon footprint
+// recorded since the last accrual, at most once per status heartbeat, and compares the
+// projection to the end of the month with the workload's carbon budget. The workload's team
+// is alerted once when the projection exceeds the budget.
+func (r *WorkloadOptimizerReconciler) checkCarbonBudget(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	previous *kcloudv1alpha1.WorkloadOptimizerStatus) {
+	var budget *float64
+	if wo.Spec.PowerConstraints != nil {
+		budget = wo.Spec.PowerConstraints.CarbonBudget
+	}
+
+	now := time.Now()
+	account := wo.Status.Emissions
+	periodStart, _ := optimizer.EmissionsMonth(now)
+	if account == nil || !account.PeriodStart.Time.Equal(periodStart) ||
+		now.Sub(account.LastAccrued.Time) >= statusHeartbeatInterval {
+		wo.Status.Emissions = optimizer.AccrueEmissions(account,
+			optimizer.CarbonGramsPerHour(ptr.Deref(previous.CarbonFootprint, 0)),
+			optimizer.CarbonGramsPerHour(ptr.Deref(wo.Status.CarbonFootprint, 0)), budget, now)
+		account = wo.Status.Emissions
+	}
+
+	if budget == nil || *budget <= 0 {
+		meta.RemoveStatusCondition(&wo.Status.Conditions, conditionCarbonWithinBudget)
+		return
+	}
+	data := map[string]any{
+		"Projected": account.Projected,
+		"Budget":    *budget,
+		"Percent":   (account.Projected - *budget) / *budget * 100,
+	}
+	condition := metav1.Condition{
+		Type:    conditionCarbonWithinBudget,
+		Status:  metav1.ConditionTrue,
+		Reason:  "WithinBudget",
+		Message: r.message(wo, messages.CarbonWithinBudget, data),
+	}
+	if account.Projected > *budget {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ProjectedOverBudget"
+		condition.Message = r.message(wo, messages.CarbonOverBudget, data)
+	}
+
+	last := meta.FindStatusCondition(wo.Status.Conditions, conditionCarbonWithinBudget)
+	if condition.Status == metav1.ConditionFalse && (last == nil || last.Status != metav1.ConditionFalse) {
+		log.FromContext(ctx).Info("Projected emissions exceed carbon budget", "workload", wo.Namespace+"/"+wo.Name,
+			"projected", account.Projected, "emitted", account.Emitted, "budget", *budget)
+		if r.Recorder != nil {
+			r.Recorder.Event(wo, corev1.EventTypeWarning, "CarbonOverBudget", condition.Message)
+		}
+		r.notifyCarbonOverBudget(ctx, wo, condition, account.Emitted >= *budget)
+	}
+	meta.SetStatusCondition(&wo.Status.Conditions, condition)
+}
+
+// notifyCarbonOverBudget alerts the workload's team, which is its namespace; the alert is
+// critical once the month's emissions alone exceed the budget
+func (r *WorkloadOptimizerReconciler) notifyCarbonOverBudget(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	condition metav1.Condition, emitted bool) {
+	if r.Notifier == nil {
+		return
+	}
+	severity := notify.SeverityWarning
+	if emitted {
+		severity = notify.SeverityCritical
+	}
+	r.Notifier.Notify(ctx, notify.Alert{
+		Team:     wo.Namespace,
+		Category: notify.CategoryPower,
+		Severity: severity,
+		Title:    r.message(wo, messages.AlertCarbonOverBudget, map[string]any{"Name": wo.Name}),
+		Message:  condition.Message,
+		Source:   wo.Namespace + "/" + wo.Name,
+	})
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+//+kubebuilder:rbac:groups=kcloud.io,resources=costpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// coveringCostPolicies returns the CostPolicies whose selectors match the workload, by name
+func (r *WorkloadOptimizerReconciler) coveringCostPolicies(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) ([]*kcloudv1alpha1.CostPolicy, error) {
+	return optimizer.CoveringCostPolicies(ctx, r.Client, wo.Namespace, wo)
+}
+
+// pricingPolicy returns the first covering CostPolicy that sets resource prices, or nil
+func pricingPolicy(policies []*kcloudv1alpha1.CostPolicy) *kcloudv1alpha1.CostPolicy {
+	for _, policy := range policies {
+		if policy.Spec.ResourceCostPolicy != nil {
+			return policy
+		}
+	}
+	return nil
+}
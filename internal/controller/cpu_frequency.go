@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// checkCPUFrequency recommends the CPU frequency governor of the workload's type and marks
+// it applied when the workload's CostPolicy auto-applies CPU frequency recommendations at
+// its confidence. A failed policy lookup keeps the previous decision.
+func (r *WorkloadOptimizerReconciler) checkCPUFrequency(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) {
+	advice, ok := optimizer.RecommendCPUFrequency(wo)
+	if !ok {
+		wo.Status.CPUFrequency = nil
+		return
+	}
+	wasApplied := wo.Status.CPUFrequency != nil && wo.Status.CPUFrequency.Applied
+	applied := wasApplied
+	if policy, err := r.autoApplyPolicy(ctx, wo); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get auto-apply policy for CPU frequency")
+	} else {
+		applied = autoApplies(policy, kcloudv1alpha1.AutoApplyCPUFrequency, advice.Confidence)
+	}
+
+	wo.Status.CPUFrequency = &kcloudv1alpha1.CPUFrequencyRecommendation{
+		Governor:              advice.Governor,
+		FrequencyScale:        advice.FrequencyScale,
+		EstimatedPowerSavings: advice.PowerSavings,
+		PerformanceImpact:     advice.PerformanceImpact,
+		Confidence:            advice.Confidence,
+		Applied:               applied,
+		Reason:                advice.Reason,
+	}
+	if applied && !wasApplied && r.Recorder != nil {
+		r.Recorder.Eventf(wo, corev1.EventTypeNormal, "RecommendationApplied",
+			"Nodes running only workloads like this one switch to the %s CPU governor, saving an estimated %.0f W "+
+				"for %.0f%% longer runs, at confidence %.2f", advice.Governor, advice.PowerSavings,
+			advice.PerformanceImpact*100, advice.Confidence)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch
+
+// podClaims returns the persistent volume claims the pods mount, each once. Claims that no
+// longer exist are skipped.
+func (r *WorkloadOptimizerReconciler) podClaims(ctx context.Context, pods []corev1.Pod) ([]corev1.PersistentVolumeClaim, error) {
+	seen := make(map[types.NamespacedName]bool)
+	var claims []corev1.PersistentVolumeClaim
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			key := types.NamespacedName{Namespace: pod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			var claim corev1.PersistentVolumeClaim
+			if err := r.Get(ctx, key, &claim); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			claims = append(claims, claim)
+		}
+	}
+	return claims, nil
+}
+
+// observedTransmit returns the traffic the workload's pods sent, for workloads whose internet
+// egress is sized from it; nil when it is not needed or not measured
+func (r *WorkloadOptimizerReconciler) observedTransmit(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	pods []corev1.Pod) *float64 {
+	if r.Transmit == nil || wo.Spec.Egress == nil || wo.Spec.Egress.GBPerHour != nil || len(pods) == 0 {
+		return nil
+	}
+	names := make([]string, len(pods))
+	for i := range pods {
+		names[i] = pods[i].Name
+	}
+	rate, found, err := r.Transmit.TransmitRate(ctx, wo.Namespace, names, optimizer.DefaultTransmitWindow)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to observe workload traffic")
+		return nil
+	}
+	if !found {
+		return nil
+	}
+	return &rate
+}
+
+// costBreakdown converts the engine's cost components
+func costBreakdown(components optimizer.CostComponents) *kcloudv1alpha1.CostBreakdown {
+	return &kcloudv1alpha1.CostBreakdown{
+		Compute: components.Compute,
+		GPU:     components.GPU,
+		Storage: components.Storage,
+		Network: components.Network,
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// conditionDeschedulingExempt reports whether a do-not-disturb lease shields the workload
+const conditionDeschedulingExempt = "DeschedulingExempt"
+
+// checkDoNotDisturb reflects the workload's do-not-disturb lease in a status condition and
+// records lease grants and expiries in the audit log
+func (r *WorkloadOptimizerReconciler) checkDoNotDisturb(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) {
+	auditLog := log.FromContext(ctx).WithName("audit")
+	previous := meta.FindStatusCondition(wo.Status.Conditions, conditionDeschedulingExempt)
+
+	lease, err := optimizer.DoNotDisturbLease(wo)
+	condition := metav1.Condition{
+		Type:   conditionDeschedulingExempt,
+		Status: metav1.ConditionFalse,
+	}
+	switch {
+	case err != nil:
+		condition.Reason = "InvalidLease"
+		condition.Message = err.Error()
+	case lease == nil:
+		if previous == nil {
+			return
+		}
+		condition.Reason = "NoLease"
+		condition.Message = r.message(wo, messages.LeaseNone, nil)
+	case lease.Active(time.Now()):
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "LeaseActive"
+		condition.Message = r.message(wo, messages.LeaseActive, map[string]any{
+			"Until": lease.Until.UTC().Format(time.RFC3339), "Source": lease.Source, "Reason": lease.Reason,
+		})
+	default:
+		condition.Reason = "LeaseExpired"
+		condition.Message = r.message(wo, messages.LeaseExpired, map[string]any{
+			"Until": lease.Until.UTC().Format(time.RFC3339),
+		})
+	}
+
+	if previous == nil || previous.Status != condition.Status || previous.Reason != condition.Reason {
+		auditLog.Info("Do-not-disturb lease changed",
+			"workload", wo.Namespace+"/"+wo.Name,
+			"state", condition.Reason,
+			"message", condition.Message)
+	}
+	meta.SetStatusCondition(&wo.Status.Conditions, condition)
+}
+
+// doNotDisturbRemaining returns how long the workload's lease has left, or zero
+func doNotDisturbRemaining(wo *kcloudv1alpha1.WorkloadOptimizer) time.Duration {
+	lease, err := optimizer.DoNotDisturbLease(wo)
+	if err != nil || lease == nil {
+		return 0
+	}
+	if remaining := time.Until(lease.Until); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
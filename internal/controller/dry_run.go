@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// dryRunInterval is how often a dry-run workload is simulated again
+const dryRunInterval = time.Minute * 5
+
+// reconcileDryRun simulates a workload annotated for a dry run and records the outcome in
+// its status without optimizing, scheduling or reserving anything for it
+func (r *WorkloadOptimizerReconciler) reconcileDryRun(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	result, err := r.Optimizer.Simulate(ctx, wo)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to simulate workload: %w", err)
+	}
+
+	previous := wo.Status.DeepCopy()
+	wo.Status.Phase = "Pending"
+	wo.Status.Simulation = &kcloudv1alpha1.Simulation{
+		Feasible:        result.Feasible,
+		NodeName:        result.Node,
+		Reason:          result.Reason,
+		EstimatedCost:   result.EstimatedCost,
+		EstimatedPower:  result.EstimatedPower,
+		CarbonFootprint: result.CarbonFootprint,
+		Replicas:        result.RecommendedReplicas,
+		SimulatedAt:     metav1.NewTime(result.SimulatedAt),
+	}
+
+	if !statusChanged(previous, &wo.Status) && !heartbeatDue(previous) {
+		return ctrl.Result{RequeueAfter: dryRunInterval}, nil
+	}
+	now := metav1.Now()
+	wo.Status.LastOptimizationTime = &now
+	if _, err := r.commitStatus(ctx, wo, previous.AssignedNode, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Simulated dry-run workload",
+		"feasible", result.Feasible,
+		"node", result.Node,
+		"estimatedCost", result.EstimatedCost)
+	return ctrl.Result{RequeueAfter: dryRunInterval}, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+// checkGPUPacking samples the workload's measured GPU utilization and records in status
+// whether new pods should share a GPU. Utilization comes from the node agents, or else from
+// the GPU usage DCGM measured, taking the greater of compute and memory. Running pods keep
+// the GPU they were given; the pod mutator applies the share to pods created afterwards.
+func (r *WorkloadOptimizerReconciler) checkGPUPacking(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	result *optimizer.OptimizationResult) {
+	if r.GPUPacker == nil || r.Scheduler == nil {
+		return
+	}
+	workloadID := wo.Namespace + "/" + wo.Name
+	if !scheduler.GPUPackable(wo) {
+		r.GPUPacker.Forget(workloadID)
+		wo.Status.GPUPacking = nil
+		return
+	}
+
+	observed := false
+	if snapshot := r.Scheduler.Snapshot(); snapshot != nil {
+		if utilization, ok := snapshot.WorkloadGPUUtilization(wo.Namespace, wo.Name); ok {
+			r.GPUPacker.Observe(workloadID, utilization)
+			observed = true
+		}
+	}
+	if !observed && result.GPUUsage != nil {
+		r.GPUPacker.Observe(workloadID, result.GPUUsage.Share())
+	}
+	decision, ok := r.GPUPacker.Decide(workloadID)
+	if !ok {
+		return
+	}
+
+	logger := log.FromContext(ctx)
+	previous := wo.Status.GPUPacking
+	switch {
+	case decision.Fraction == "":
+		if previous != nil {
+			logger.Info("Unpacking workload onto whole GPUs", "workload", workloadID,
+				"peakUtilization", decision.Peak, "threshold", r.GPUPacker.Threshold)
+		}
+		wo.Status.GPUPacking = nil
+	case previous == nil || previous.Fraction != decision.Fraction:
+		since := metav1.Now()
+		if previous != nil {
+			since = previous.Since
+		}
+		logger.Info("Packing workload onto a shared GPU", "workload", workloadID,
+			"peakUtilization", decision.Peak, "fraction", decision.Fraction)
+		wo.Status.GPUPacking = &kcloudv1alpha1.GPUPacking{
+			MeasuredUtilization: decision.Peak,
+			Fraction:            decision.Fraction,
+			Since:               since,
+		}
+	}
+}
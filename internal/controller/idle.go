@@ -0,0 +1,225 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=replicasets;statefulsets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;update;patch
+
+// Idle actions recorded in status
+const (
+	idleActionRecommended  = "Recommended"
+	idleActionScaledToZero = "ScaledToZero"
+)
+
+// phaseSuspended is the phase of a workload the operator scaled to zero
+const phaseSuspended = "Suspended"
+
+// checkIdle flags workloads whose pods stayed near zero utilization for the idle period and
+// records the savings of scaling them to zero. Workloads annotated for it are scaled to zero
+// and stay Suspended until their pods come back.
+func (r *WorkloadOptimizerReconciler) checkIdle(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) {
+	if r.IdleDetector == nil {
+		return
+	}
+	logger := log.FromContext(ctx)
+	workloadID := wo.Namespace + "/" + wo.Name
+
+	previous := wo.Status.Idle
+	if previous != nil && previous.Action == idleActionScaledToZero {
+		pods, err := getAssociatedPods(ctx, r.Client, wo)
+		if err != nil {
+			logger.Error(err, "Failed to list pods of suspended workload")
+			wo.Status.Phase = phaseSuspended
+			return
+		}
+		if len(pods) == 0 {
+			wo.Status.Phase = phaseSuspended
+			return
+		}
+		logger.Info("Suspended workload was scaled up again", "workload", workloadID)
+		wo.Status.Idle = nil
+		return
+	}
+
+	if !r.IdleDetector.Due(workloadID) {
+		return
+	}
+	pods, err := getAssociatedPods(ctx, r.Client, wo)
+	if err != nil {
+		logger.Error(err, "Failed to list pods for idle detection")
+		return
+	}
+	report, err := r.IdleDetector.Detect(ctx, wo, pods)
+	if err != nil {
+		logger.Error(err, "Failed to check workload for idleness")
+		return
+	}
+	if !report.Idle {
+		if previous != nil {
+			logger.Info("Workload is no longer idle", "workload", workloadID, "reason", report.Reason)
+		}
+		wo.Status.Idle = nil
+		return
+	}
+
+	hourly := 0.0
+	if wo.Status.CurrentCost != nil {
+		hourly = *wo.Status.CurrentCost
+	}
+	idle := &kcloudv1alpha1.IdleDetection{
+		Since:                   metav1.Now(),
+		CPUUsage:                report.Usage.CPUCores,
+		GPUUsage:                report.Usage.GPUs,
+		EstimatedHourlySavings:  hourly,
+		EstimatedMonthlySavings: r.Optimizer.Currency.Currency().Round(hourly * 24 * 30),
+		Action:                  idleActionRecommended,
+	}
+	if previous != nil {
+		idle.Since = previous.Since
+	} else {
+		logger.Info("Workload is idle", "workload", workloadID, "reason", report.Reason,
+			"monthlySavings", idle.EstimatedMonthlySavings)
+	}
+
+	if optimizer.ScaleToZeroEnabled(wo) {
+		targets, err := scaleDown(ctx, r.Client, wo.Namespace, pods, 0)
+		if err != nil {
+			logger.Error(err, "Failed to scale idle workload to zero", "workload", workloadID)
+		} else {
+			idle.Action = idleActionScaledToZero
+			idle.ScaledTargets = targets
+			wo.Status.Phase = phaseSuspended
+			logger.Info("Scaled idle workload to zero", "workload", workloadID, "targets", len(targets))
+			if r.Recorder != nil {
+				r.Recorder.Eventf(wo, corev1.EventTypeNormal, "ScaledToZero",
+					"Scaled to zero after %s idle: %s", r.IdleDetector.Period, report.Reason)
+			}
+		}
+	}
+	wo.Status.Idle = idle
+}
+
+// scalable is a pod controller that can be scaled to zero or suspended
+type scalable struct {
+	kind   string
+	object client.Object
+}
+
+// scaleDown scales the controllers of the pods down to the replica count. Jobs are suspended
+// when scaling to zero and otherwise left alone. All controllers are resolved before any is
+// changed, so pods with an unsupported or missing controller leave the workload untouched.
+// The targets record the replicas to restore.
+func scaleDown(ctx context.Context, c client.Client, namespace string, pods []corev1.Pod,
+	replicas int32) ([]kcloudv1alpha1.ScaledTarget, error) {
+	var controllers []scalable
+	seen := make(map[string]bool)
+	for _, pod := range pods {
+		controller, err := podController(ctx, c, &pod)
+		if err != nil {
+			return nil, err
+		}
+		if key := controller.kind + "/" + controller.object.GetName(); !seen[key] {
+			seen[key] = true
+			controllers = append(controllers, controller)
+		}
+	}
+
+	targets := make([]kcloudv1alpha1.ScaledTarget, 0, len(controllers))
+	for _, controller := range controllers {
+		original := controller.object.DeepCopyObject().(client.Object)
+		target := kcloudv1alpha1.ScaledTarget{Kind: controller.kind, Name: controller.object.GetName()}
+		var current **int32
+		switch o := controller.object.(type) {
+		case *appsv1.Deployment:
+			current = &o.Spec.Replicas
+		case *appsv1.StatefulSet:
+			current = &o.Spec.Replicas
+		case *appsv1.ReplicaSet:
+			current = &o.Spec.Replicas
+		case *batchv1.Job:
+			if replicas > 0 {
+				continue
+			}
+			o.Spec.Suspend = ptr.To(true)
+		}
+		if current != nil {
+			if ptr.Deref(*current, 1) <= replicas {
+				continue
+			}
+			target.Replicas = ptr.To(ptr.Deref(*current, 1))
+			*current = ptr.To(replicas)
+		}
+		if err := c.Patch(ctx, controller.object, client.MergeFrom(original)); err != nil {
+			return targets, fmt.Errorf("failed to scale %s %s/%s to %d replicas: %w", target.Kind, namespace,
+				target.Name, replicas, err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// podController returns the controller that scales the pod: the Deployment of a ReplicaSet
+// that has one, or the pod's StatefulSet, ReplicaSet or Job
+func podController(ctx context.Context, c client.Client, pod *corev1.Pod) (scalable, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return scalable{}, fmt.Errorf("pod %s/%s has no controller to scale", pod.Namespace, pod.Name)
+	}
+	key := client.ObjectKey{Namespace: pod.Namespace, Name: owner.Name}
+
+	controller := scalable{kind: owner.Kind}
+	switch owner.Kind {
+	case "ReplicaSet":
+		replicaSet := &appsv1.ReplicaSet{}
+		if err := c.Get(ctx, key, replicaSet); err != nil {
+			return scalable{}, fmt.Errorf("failed to get ReplicaSet %s: %w", key, err)
+		}
+		deployment := metav1.GetControllerOf(replicaSet)
+		if deployment == nil || deployment.Kind != "Deployment" {
+			return scalable{kind: owner.Kind, object: replicaSet}, nil
+		}
+		key.Name = deployment.Name
+		controller = scalable{kind: deployment.Kind, object: &appsv1.Deployment{}}
+	case "StatefulSet":
+		controller.object = &appsv1.StatefulSet{}
+	case "Job":
+		controller.object = &batchv1.Job{}
+	default:
+		return scalable{}, fmt.Errorf("pod %s/%s is controlled by a %s, which cannot be scaled",
+			pod.Namespace, pod.Name, owner.Kind)
+	}
+	if err := c.Get(ctx, key, controller.object); err != nil {
+		return scalable{}, fmt.Errorf("failed to get %s %s: %w", controller.kind, key, err)
+	}
+	return controller, nil
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+)
+
+// message renders a user-facing message in the workload's locale, falling back to the
+// operator's default locale when the workload does not choose one
+func (r *WorkloadOptimizerReconciler) message(wo *kcloudv1alpha1.WorkloadOptimizer, id messages.ID, data map[string]any) string {
+	return r.Messages.Render(wo.Annotations[messages.LocaleAnnotation], id, data)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// capNodes lowers the power cap of the nodes running the policy's workloads in proportion to
+// how far their power is over the limit, no lower than the policy's minimum. The node agent
+// applies the cap to the nodes' CPU packages and GPUs. The cap is lowered again only once
+// every workload was evaluated under the current one. Nodes that also run workloads at or
+// above the exempt priority, workloads holding a do-not-disturb lease or pods outside the
+// policy are never capped, and the policy lifts its cap from nodes that became so.
+func (r *PowerPolicyReconciler) capNodes(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy,
+	workloads []kcloudv1alpha1.WorkloadOptimizer, power float64) {
+	logger := log.FromContext(ctx)
+	status := policy.Status.NodePowerCap
+	previous := int32(100)
+	nodes := map[string]bool{}
+	if status != nil {
+		for i := range workloads {
+			evaluated := workloads[i].Status.LastOptimizationTime
+			if workloads[i].Status.Phase != phaseSuspended && (evaluated == nil || !evaluated.After(status.Since.Time)) {
+				return
+			}
+		}
+		previous = status.Percent
+		for _, node := range status.Nodes {
+			nodes[node] = true
+		}
+	}
+	minPercent := max(policy.Spec.Enforcement.NodePowerCap.MinPercent, optimizer.MinPowerCapPercent)
+	percent := int32(math.Floor(float64(previous) * policy.Spec.MaxPowerUsage / power))
+	percent = min(max(percent, minPercent), 99)
+
+	exempt := policy.Spec.Enforcement.ExemptPriority
+	now := time.Now()
+	covered := map[types.UID]bool{}
+	protected := map[string]bool{}
+	for i := range workloads {
+		wo := &workloads[i]
+		pods, err := getAssociatedPods(ctx, r.Client, wo)
+		if err != nil {
+			logger.Error(err, "Failed to list pods for node power cap", "workload", wo.Namespace+"/"+wo.Name)
+			return
+		}
+		spared := (exempt != nil && wo.Spec.Priority >= *exempt) || optimizer.DoNotDisturbActive(wo, now)
+		for _, pod := range pods {
+			if pod.Spec.NodeName == "" {
+				continue
+			}
+			covered[pod.UID] = true
+			switch {
+			case spared:
+				protected[pod.Spec.NodeName] = true
+			case wo.Status.Phase != phaseSuspended:
+				nodes[pod.Spec.NodeName] = true
+			}
+		}
+	}
+	shared, err := r.sharedNodes(ctx, covered)
+	if err != nil {
+		logger.Error(err, "Failed to list pods for node power cap")
+		return
+	}
+
+	var capped []string
+	for node := range nodes {
+		if protected[node] || shared[node] {
+			if err := r.liftNodePowerCap(ctx, node, policy.Name); err != nil {
+				logger.Error(err, "Failed to lift node power cap", "powerPolicy", policy.Name, "node", node)
+			}
+			continue
+		}
+		if err := r.requestNodePowerCap(ctx, node, policy.Name, percent); err != nil {
+			logger.Error(err, "Failed to cap node power", "powerPolicy", policy.Name, "node", node)
+			continue
+		}
+		capped = append(capped, node)
+	}
+	if len(capped) == 0 {
+		if status != nil {
+			policy.Status.NodePowerCap = nil
+		}
+		logger.Info("No node can be power capped without slowing exempt or unrelated workloads",
+			"powerPolicy", policy.Name, "estimatedPower", power, "limit", policy.Spec.MaxPowerUsage)
+		return
+	}
+	slices.Sort(capped)
+	if status != nil && status.Percent == percent && slices.Equal(status.Nodes, capped) {
+		logger.Info("Node power caps are at the power policy minimum", "powerPolicy", policy.Name,
+			"percent", percent, "estimatedPower", power, "limit", policy.Spec.MaxPowerUsage)
+		return
+	}
+	policy.Status.NodePowerCap = &kcloudv1alpha1.NodePowerCapStatus{Percent: percent, Nodes: capped, Since: metav1.Now()}
+	logger.Info("Power policy capped node power", "powerPolicy", policy.Name, "percent", percent, "nodes", capped)
+	r.event(policy, corev1.EventTypeWarning, "NodesPowerCapped",
+		fmt.Sprintf("Capped %d nodes at %d%% of their default power limits", len(capped), percent))
+}
+
+// sharedNodes returns the nodes running pods other than the covered ones, leaving out
+// DaemonSet and static pods that run on every node
+func (r *PowerPolicyReconciler) sharedNodes(ctx context.Context, covered map[types.UID]bool) (map[string]bool, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods); err != nil {
+		return nil, err
+	}
+	shared := map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || covered[pod.UID] ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		shared[pod.Spec.NodeName] = true
+	}
+	return shared, nil
+}
+
+// uncapNodes lifts the power cap the policy requested on its nodes. Caps other policies
+// request stay in place. Nodes that failed to update stay in the status to be retried.
+func (r *PowerPolicyReconciler) uncapNodes(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy) error {
+	status := policy.Status.NodePowerCap
+	if status == nil {
+		return nil
+	}
+	var remaining []string
+	var errs error
+	for _, node := range status.Nodes {
+		if err := r.liftNodePowerCap(ctx, node, policy.Name); err != nil {
+			remaining = append(remaining, node)
+			errs = err
+		}
+	}
+	if len(remaining) > 0 {
+		status.Nodes = remaining
+		return fmt.Errorf("failed to lift power cap of %d nodes: %w", len(remaining), errs)
+	}
+	policy.Status.NodePowerCap = nil
+	log.FromContext(ctx).Info("Power policy lifted node power caps", "powerPolicy", policy.Name)
+	r.event(policy, corev1.EventTypeNormal, "NodesPowerRestored",
+		fmt.Sprintf("Lifted the power cap of %d nodes", len(status.Nodes)))
+	return nil
+}
+
+// requestNodePowerCap records the policy's cap on the node and sets the node's cap to the
+// lowest one any policy requests
+func (r *PowerPolicyReconciler) requestNodePowerCap(ctx context.Context, name, policy string, percent int32) error {
+	return r.updateNodePowerCap(ctx, name, func(annotations map[string]string) {
+		annotations[optimizer.PowerCapRequestPrefix+policy] = strconv.Itoa(int(percent))
+	})
+}
+
+// liftNodePowerCap removes the policy's cap from the node, leaving the node capped at the
+// lowest cap other policies still request
+func (r *PowerPolicyReconciler) liftNodePowerCap(ctx context.Context, name, policy string) error {
+	return r.updateNodePowerCap(ctx, name, func(annotations map[string]string) {
+		delete(annotations, optimizer.PowerCapRequestPrefix+policy)
+	})
+}
+
+// updateNodePowerCap changes the node's cap requests and recomputes the cap the node agent
+// applies from them
+func (r *PowerPolicyReconciler) updateNodePowerCap(ctx context.Context, name string, update func(map[string]string)) error {
+	var node corev1.Node
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	update(node.Annotations)
+	if percent, ok := optimizer.EffectivePowerCap(optimizer.PowerCapRequests(node.Annotations)); ok {
+		node.Annotations[optimizer.PowerCapAnnotation] = strconv.Itoa(int(percent))
+	} else {
+		delete(node.Annotations, optimizer.PowerCapAnnotation)
+	}
+	return r.Patch(ctx, &node, patch)
+}
+
+// nodePowerCapFits reports whether the policy's workloads would stay under its limit with
+// the node caps lifted, assuming their power rises in proportion to the cap
+func nodePowerCapFits(policy *kcloudv1alpha1.PowerPolicy, power float64) bool {
+	status := policy.Status.NodePowerCap
+	return status == nil || power*100/float64(status.Percent) <= policy.Spec.MaxPowerUsage
+}
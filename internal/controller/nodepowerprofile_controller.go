@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// conditionNodesMatched reports whether a NodePowerProfile applies to any node
+const conditionNodesMatched = "NodesMatched"
+
+// NodePowerProfileReconciler reports in each NodePowerProfile's status the nodes it applies
+// to and the renewable fraction of its energy mix
+type NodePowerProfileReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=kcloud.io,resources=nodepowerprofiles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kcloud.io,resources=nodepowerprofiles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// Reconcile counts the nodes the profile applies to, those it selects that no profile
+// earlier by name selects as well
+func (r *NodePowerProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var profile kcloudv1alpha1.NodePowerProfile
+	if err := r.Get(ctx, req.NamespacedName, &profile); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var profiles kcloudv1alpha1.NodePowerProfileList
+	if err := r.List(ctx, &profiles); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list node power profiles: %w", err)
+	}
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	matched := int32(0)
+	for _, node := range nodes.Items {
+		if applied := optimizer.ProfileForNode(profiles.Items, node); applied != nil && applied.Name == profile.Name {
+			matched++
+		}
+	}
+	fraction := optimizer.RenewableFraction(profile.Spec.EnergyMix)
+
+	status := profile.DeepCopy().Status
+	status.MatchedNodes = &matched
+	status.RenewableFraction = &fraction
+	condition := metav1.Condition{
+		Type:               conditionNodesMatched,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NodesSelected",
+		Message:            fmt.Sprintf("Applies to %d nodes", matched),
+		ObservedGeneration: profile.Generation,
+	}
+	if matched == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoNodesSelected"
+		condition.Message = "No node is selected by this profile before any other profile"
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	if equality.Semantic.DeepEqual(status, profile.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	now := metav1.Now()
+	status.LastUpdated = &now
+	profile.Status = status
+	if err := r.Status().Update(ctx, &profile); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update node power profile status: %w", err)
+	}
+	log.FromContext(ctx).V(1).Info("Node power profile status updated", "profile", profile.Name,
+		"matchedNodes", matched, "renewableFraction", fraction)
+	return ctrl.Result{}, nil
+}
+
+// allProfiles enqueues every profile, since a node's labels or another profile decide which
+// profile applies to it
+func (r *NodePowerProfileReconciler) allProfiles(ctx context.Context, _ client.Object) []reconcile.Request {
+	var profiles kcloudv1alpha1.NodePowerProfileList
+	if err := r.List(ctx, &profiles); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list node power profiles")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(profiles.Items))
+	for _, profile := range profiles.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&profile)})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodePowerProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kcloudv1alpha1.NodePowerProfile{}).
+		Watches(&kcloudv1alpha1.NodePowerProfile{}, handler.EnqueueRequestsFromMapFunc(r.allProfiles),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.allProfiles),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// reschedulingRetryInterval is how soon a workload that needs rescheduling is re-optimized
+const reschedulingRetryInterval = time.Minute
+
+// optimizationInterval resolves how often the workload is re-optimized: its own interval,
+// else that of the first CostPolicy by name that covers it and sets one, else the default
+func (r *WorkloadOptimizerReconciler) optimizationInterval(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) optimizer.OptimizationInterval {
+	log := log.FromContext(ctx)
+
+	value, source := wo.Spec.OptimizationInterval, "spec"
+	if value == "" {
+		policies, err := r.coveringCostPolicies(ctx, wo)
+		if err != nil {
+			log.Error(err, "Failed to find the policy optimization interval")
+		}
+		for _, policy := range policies {
+			if policy.Spec.OptimizationInterval != "" {
+				value, source = policy.Spec.OptimizationInterval, "costPolicy/"+policy.Name
+				break
+			}
+		}
+	}
+	if value == "" {
+		return optimizer.OptimizationInterval{Every: optimizer.DefaultOptimizationInterval}
+	}
+
+	interval, err := optimizer.ParseOptimizationInterval(value)
+	if err != nil {
+		log.Error(err, "Ignoring optimization interval", "source", source)
+		return optimizer.OptimizationInterval{Every: optimizer.DefaultOptimizationInterval}
+	}
+	return interval
+}
+
+// optimizedOnce reports whether a workload optimized once per spec change is up to date
+func optimizedOnce(wo *kcloudv1alpha1.WorkloadOptimizer, interval optimizer.OptimizationInterval) bool {
+	return interval.Once && wo.Status.LastOptimizationTime != nil && wo.Status.ObservedGeneration == wo.Generation
+}
@@ -0,0 +1,780 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+const (
+	// optimizationPlanFinalizer lifts the cordons of a plan deleted while it is applied
+	optimizationPlanFinalizer = "optimizationplan.kcloud.io/finalizer"
+
+	// defaultMaxScoreRegression is the score drop that aborts a plan unless the plan sets one
+	defaultMaxScoreRegression = 0.1
+	// defaultMaxCostIncrease is the relative cost rise that aborts a plan unless the plan sets one
+	defaultMaxCostIncrease = 0.1
+	// defaultMaxLatencyIncrease is the relative p99 latency rise that aborts a plan unless the plan sets one
+	defaultMaxLatencyIncrease = 0.2
+	// defaultCanaryFraction is the share of a workload's replicas moved per step unless the plan sets it
+	defaultCanaryFraction = 0.25
+	// defaultPlanStepTimeout is how long a step may take to be replaced and evaluated unless the plan sets it
+	defaultPlanStepTimeout = 5 * time.Minute
+	// planPollInterval is how often an applying plan checks the step in progress
+	planPollInterval = 10 * time.Second
+	// evictionRetryInterval is how long a move waits when a disruption budget blocks its eviction
+	evictionRetryInterval = 30 * time.Second
+)
+
+// OptimizationPlanReconciler applies approved OptimizationPlans one step at a time. It
+// cordons the nodes a plan drains and moves a canary fraction of a workload's replicas per
+// step: it evicts their pods, waits for the replacements to run and for the workloads to be
+// evaluated again before the next step. Plans are aborted when a replacement does not
+// run in time, and rolled back when the workloads with moved pods lose optimization score or
+// become costlier or slower.
+type OptimizationPlanReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Recorder emits events on plans; nil disables events
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=kcloud.io,resources=optimizationplans,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kcloud.io,resources=optimizationplans/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kcloud.io,resources=optimizationplans/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
+
+// Reconcile advances an approved plan by at most one step
+func (r *OptimizationPlanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var plan kcloudv1alpha1.OptimizationPlan
+	if err := r.Get(ctx, req.NamespacedName, &plan); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !plan.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.handlePlanDeletion(ctx, &plan)
+	}
+
+	switch plan.Status.Phase {
+	case kcloudv1alpha1.PlanPhaseApplied, kcloudv1alpha1.PlanPhaseAborted, kcloudv1alpha1.PlanPhaseRolledBack:
+		return ctrl.Result{}, nil
+	case kcloudv1alpha1.PlanPhaseApplying:
+		return r.advancePlan(ctx, &plan)
+	case kcloudv1alpha1.PlanPhaseRollingBack:
+		return r.rollbackPlan(ctx, &plan)
+	}
+
+	if !plan.Spec.Approved {
+		if plan.Status.Phase == "" {
+			plan.Status.Phase = kcloudv1alpha1.PlanPhasePending
+			if err := r.Status().Update(ctx, &plan); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to update optimization plan status: %w", err)
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	return r.startPlan(ctx, &plan)
+}
+
+// startPlan records the baseline score, cost and latency of the workloads with moved pods
+// and cordons the nodes the plan drains. The nodes are recorded before they are cordoned,
+// so a failure in between never leaves a cordon that the plan does not lift.
+func (r *OptimizationPlanReconciler) startPlan(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan) (ctrl.Result, error) {
+	if controllerutil.AddFinalizer(plan, optimizationPlanFinalizer) {
+		if err := r.Update(ctx, plan); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add optimization plan finalizer: %w", err)
+		}
+	}
+
+	workloads, err := r.planWorkloads(ctx, plan)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	now := metav1.Now()
+	plan.Status.StartedAt = &now
+	plan.Status.Workloads = workloads
+	baseline, err := r.workloadMetrics(ctx, workloads)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	plan.Status.BaselineScore = baseline.score
+	plan.Status.BaselineCost = baseline.cost
+	plan.Status.BaselineLatencyP99 = baseline.latencyP99
+
+	for _, name := range plan.Spec.DrainNodes {
+		var node corev1.Node
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &node); err != nil {
+			return r.abortPlan(ctx, plan, fmt.Sprintf("Failed to get node %s: %v", name, err))
+		}
+		if !node.Spec.Unschedulable && !slices.Contains(plan.Status.CordonedNodes, name) {
+			plan.Status.CordonedNodes = append(plan.Status.CordonedNodes, name)
+		}
+	}
+	// A retry after a failure below finds the nodes cordoned already but still recorded
+	plan.Status.Phase = kcloudv1alpha1.PlanPhasePending
+	if err := r.Status().Update(ctx, plan); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update optimization plan status: %w", err)
+	}
+	for _, name := range plan.Status.CordonedNodes {
+		if _, err := r.setUnschedulable(ctx, name, true); err != nil {
+			return r.abortPlan(ctx, plan, fmt.Sprintf("Failed to cordon node %s: %v", name, err))
+		}
+	}
+
+	plan.Status.Phase = kcloudv1alpha1.PlanPhaseApplying
+	plan.Status.Message = fmt.Sprintf("Cordoned %d of %d nodes to drain", len(plan.Status.CordonedNodes),
+		len(plan.Spec.DrainNodes))
+	if err := r.Status().Update(ctx, plan); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update optimization plan status: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Applying optimization plan", "plan", plan.Name, "moves", len(plan.Spec.Moves),
+		"drainNodes", plan.Spec.DrainNodes)
+	r.event(plan, corev1.EventTypeNormal, "PlanApplying", "Applying %d moves to drain %d nodes",
+		len(plan.Spec.Moves), len(plan.Spec.DrainNodes))
+	return ctrl.Result{}, nil
+}
+
+// advancePlan checks the realized metrics, waits for the step in progress to be replaced
+// and evaluated, and starts the next
+func (r *OptimizationPlanReconciler) advancePlan(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan) (ctrl.Result, error) {
+	realized, err := r.workloadMetrics(ctx, plan.Status.Workloads)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	plan.Status.RealizedScore = realized.score
+	plan.Status.RealizedCost = realized.cost
+	plan.Status.RealizedLatencyP99 = realized.latencyP99
+	if reason := planRegression(plan); reason != "" {
+		return r.startRollback(ctx, plan, reason)
+	}
+
+	if plan.Status.StepStartedAt != nil {
+		first := plan.Status.CompletedSteps
+		if first < 0 || int(first+plan.Status.StepMoves) > len(plan.Spec.Moves) || plan.Status.StepMoves <= 0 {
+			return r.abortPlan(ctx, plan, fmt.Sprintf("Step of %d moves from move %d does not match the %d moves of the plan",
+				plan.Status.StepMoves, first, len(plan.Spec.Moves)))
+		}
+		moves := plan.Spec.Moves[first : first+plan.Status.StepMoves]
+		timeout := planStepTimeout(plan)
+		if plan.Status.StepReplacedAt == nil {
+			replaced, err := r.stepReplaced(ctx, moves, plan.Status.StepOwnerUID)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !replaced {
+				if time.Since(plan.Status.StepStartedAt.Time) > timeout {
+					return r.abortPlan(ctx, plan, fmt.Sprintf("%d pods moved off %s were not replaced by running pods within %s",
+						len(moves), moves[0].FromNode, timeout))
+				}
+				return r.updatePlanStatus(ctx, plan, planPollInterval)
+			}
+			now := metav1.Now()
+			plan.Status.StepReplacedAt = &now
+			plan.Status.Message = fmt.Sprintf("Evaluating the placement after moving %d of %d pods",
+				int(first)+len(moves), len(plan.Spec.Moves))
+			return r.updatePlanStatus(ctx, plan, planPollInterval)
+		}
+		if realized.evaluatedAt.Before(plan.Status.StepReplacedAt.Time) &&
+			time.Since(plan.Status.StepReplacedAt.Time) <= timeout {
+			return r.updatePlanStatus(ctx, plan, planPollInterval)
+		}
+		recordStep(plan)
+		plan.Status.CompletedSteps += plan.Status.StepMoves
+		plan.Status.StepMoves = 0
+		plan.Status.StepStartedAt = nil
+		plan.Status.StepReplacedAt = nil
+		plan.Status.StepOwnerUID = ""
+	}
+
+	if int(plan.Status.CompletedSteps) >= len(plan.Spec.Moves) {
+		now := metav1.Now()
+		plan.Status.Phase = kcloudv1alpha1.PlanPhaseApplied
+		plan.Status.CompletedAt = &now
+		plan.Status.Message = fmt.Sprintf("Moved %d pods off %d nodes", len(plan.Spec.Moves), len(plan.Spec.DrainNodes))
+		log.FromContext(ctx).Info("Optimization plan applied", "plan", plan.Name, "moves", len(plan.Spec.Moves))
+		r.event(plan, corev1.EventTypeNormal, "PlanApplied", "%s", plan.Status.Message)
+		return r.updatePlanStatus(ctx, plan, 0)
+	}
+	return r.startStep(ctx, plan)
+}
+
+// startStep evicts the pods of the next step: the consecutive moves of pods with the same
+// controller, up to the plan's canary fraction of its replicas. Pods that already left
+// their node count as moved.
+func (r *OptimizationPlanReconciler) startStep(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan) (ctrl.Result, error) {
+	var pods []*corev1.Pod
+	var indexes []int32
+	var owner types.UID
+	size := 1
+	end := plan.Status.CompletedSteps
+	for ; int(end) < len(plan.Spec.Moves) && len(pods) < size; end++ {
+		pod, err := r.movablePod(ctx, plan.Spec.Moves[end])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if pod == nil {
+			continue
+		}
+		var podOwner types.UID
+		if controller := metav1.GetControllerOf(pod); controller != nil {
+			podOwner = controller.UID
+		}
+		if len(pods) == 0 {
+			owner = podOwner
+			if size, err = r.canaryStepSize(ctx, plan, pod.Namespace, owner); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if podOwner != owner || owner == "" {
+			break
+		}
+		pods = append(pods, pod)
+		indexes = append(indexes, end)
+	}
+	if len(pods) == 0 {
+		plan.Status.CompletedSteps = end
+		return r.updatePlanStatus(ctx, plan, 0)
+	}
+
+	for i, pod := range pods {
+		blocked, err := r.evictPod(ctx, pod)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if blocked == "" {
+			continue
+		}
+		if i == 0 {
+			plan.Status.Message = blocked
+			return r.updatePlanStatus(ctx, plan, evictionRetryInterval)
+		}
+		// The pods evicted so far make up the step
+		pods, end = pods[:i], indexes[i]
+		break
+	}
+
+	now := metav1.Now()
+	plan.Status.StepMoves = end - plan.Status.CompletedSteps
+	plan.Status.StepStartedAt = &now
+	plan.Status.StepOwnerUID = owner
+	plan.Status.Message = fmt.Sprintf("Moving %d pods, starting with %s/%s, off their nodes", len(pods),
+		pods[0].Namespace, pods[0].Name)
+	log.FromContext(ctx).Info("Evicted pods for optimization plan", "plan", plan.Name, "pods", len(pods),
+		"firstPod", client.ObjectKeyFromObject(pods[0]), "movesDone", plan.Status.CompletedSteps+plan.Status.StepMoves,
+		"of", len(plan.Spec.Moves))
+	return r.updatePlanStatus(ctx, plan, planPollInterval)
+}
+
+// evictPod evicts the pod unless its workload holds a do-not-disturb lease or a disruption
+// budget blocks the eviction, returning why it is blocked, empty once evicted
+func (r *OptimizationPlanReconciler) evictPod(ctx context.Context, pod *corev1.Pod) (string, error) {
+	if name := pod.Annotations[scheduler.WorkloadOptimizerAnnotation]; name != "" {
+		var wo kcloudv1alpha1.WorkloadOptimizer
+		err := r.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: name}, &wo)
+		if client.IgnoreNotFound(err) != nil {
+			return "", fmt.Errorf("failed to get workload %s/%s: %w", pod.Namespace, name, err)
+		}
+		if lease, err := optimizer.DoNotDisturbLease(&wo); err == nil && lease.Active(time.Now()) {
+			return fmt.Sprintf("Pod %s/%s is %s", pod.Namespace, pod.Name, lease.Explanation()), nil
+		}
+	}
+
+	eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+	if err := r.SubResource("eviction").Create(ctx, pod, eviction); err != nil {
+		if !apierrors.IsTooManyRequests(err) {
+			return "", fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		return fmt.Sprintf("Disruption budget blocks evicting pod %s/%s", pod.Namespace, pod.Name), nil
+	}
+	return "", nil
+}
+
+// movablePod returns the pod of the move, nil when it already left its node
+func (r *OptimizationPlanReconciler) movablePod(ctx context.Context, move kcloudv1alpha1.PlanMove) (*corev1.Pod, error) {
+	var pod corev1.Pod
+	err := r.Get(ctx, client.ObjectKey{Namespace: move.Namespace, Name: move.Pod}, &pod)
+	if apierrors.IsNotFound(err) || (err == nil && (pod.Spec.NodeName != move.FromNode || !pod.DeletionTimestamp.IsZero())) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", move.Namespace, move.Pod, err)
+	}
+	return &pod, nil
+}
+
+// canaryStepSize returns how many pods of the controller one step moves: the plan's
+// canary fraction of its replicas, rounded up. Pods without a controller move one at a time.
+func (r *OptimizationPlanReconciler) canaryStepSize(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan,
+	namespace string, owner types.UID) (int, error) {
+	if owner == "" {
+		return 1, nil
+	}
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list pods in %s: %w", namespace, err)
+	}
+	replicas := 0
+	for _, pod := range pods.Items {
+		if controller := metav1.GetControllerOf(&pod); controller != nil && controller.UID == owner &&
+			pod.DeletionTimestamp.IsZero() {
+			replicas++
+		}
+	}
+	return max(int(math.Ceil(canaryFraction(plan)*float64(replicas))), 1), nil
+}
+
+// stepReplaced reports whether the evicted pods of the step are gone from their nodes and
+// their controller runs replacements with no pod left pending
+func (r *OptimizationPlanReconciler) stepReplaced(ctx context.Context, moves []kcloudv1alpha1.PlanMove, owner types.UID) (bool, error) {
+	for _, move := range moves {
+		var pod corev1.Pod
+		err := r.Get(ctx, client.ObjectKey{Namespace: move.Namespace, Name: move.Pod}, &pod)
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return false, fmt.Errorf("failed to get pod %s/%s: %w", move.Namespace, move.Pod, err)
+		case pod.Spec.NodeName == move.FromNode:
+			return false, nil
+		}
+	}
+	if owner == "" {
+		return true, nil
+	}
+	return r.replacementsRunning(ctx, moves[0].Namespace, owner)
+}
+
+// replacementsRunning reports whether the controller runs pods in the namespace with no pod
+// left pending
+func (r *OptimizationPlanReconciler) replacementsRunning(ctx context.Context, namespace string,
+	owner types.UID) (bool, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("failed to list pods in %s: %w", namespace, err)
+	}
+	running := 0
+	for _, pod := range pods.Items {
+		if controller := metav1.GetControllerOf(&pod); controller == nil || controller.UID != owner ||
+			!pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		switch pod.Status.Phase {
+		case corev1.PodPending:
+			return false, nil
+		case corev1.PodRunning:
+			running++
+		}
+	}
+	return running > 0, nil
+}
+
+// abortPlan stops the plan and uncordons the nodes it cordoned. Pods already moved keep
+// their new nodes, and the scheduler may place later pods back on the uncordoned nodes.
+func (r *OptimizationPlanReconciler) abortPlan(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan,
+	reason string) (ctrl.Result, error) {
+	if err := r.uncordon(ctx, plan); err != nil {
+		return ctrl.Result{}, err
+	}
+	now := metav1.Now()
+	plan.Status.Phase = kcloudv1alpha1.PlanPhaseAborted
+	plan.Status.CompletedAt = &now
+	plan.Status.StepStartedAt = nil
+	plan.Status.StepReplacedAt = nil
+	plan.Status.Message = reason
+	log.FromContext(ctx).Info("Optimization plan aborted", "plan", plan.Name, "reason", reason,
+		"completedSteps", plan.Status.CompletedSteps)
+	r.event(plan, corev1.EventTypeWarning, "PlanAborted", "%s", reason)
+	return r.updatePlanStatus(ctx, plan, 0)
+}
+
+// startRollback stops the plan because its realized metrics regressed and uncordons the
+// nodes it cordoned, so that rollbackPlan can move the pods of its steps back. The step in
+// progress is rolled back too.
+func (r *OptimizationPlanReconciler) startRollback(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan,
+	reason string) (ctrl.Result, error) {
+	if err := r.uncordon(ctx, plan); err != nil {
+		return ctrl.Result{}, err
+	}
+	recordStep(plan)
+	now := metav1.Now()
+	plan.Status.Phase = kcloudv1alpha1.PlanPhaseRollingBack
+	plan.Status.RollbackStartedAt = &now
+	plan.Status.StepMoves = 0
+	plan.Status.StepStartedAt = nil
+	plan.Status.StepReplacedAt = nil
+	plan.Status.StepOwnerUID = ""
+	plan.Status.Message = reason
+	log.FromContext(ctx).Info("Rolling back optimization plan", "plan", plan.Name, "reason", reason,
+		"steps", len(plan.Status.Steps))
+	r.event(plan, corev1.EventTypeWarning, "PlanRollingBack", "%s", reason)
+	// Nothing is evicted until the phase is stored, since the pod webhook reads it to steer
+	// replacements back
+	return r.updatePlanStatus(ctx, plan, 0)
+}
+
+// rollbackPlan moves the pods of the plan's steps back, last step first. It evicts the
+// replacements of a step and waits for the controller to run their own replacements, which
+// the pod webhook prefers onto the nodes the step moved pods off, until none is left away
+// from them. Replacements that land elsewhere, or do not run within the step timeout, are
+// left as they are.
+func (r *OptimizationPlanReconciler) rollbackPlan(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan) (ctrl.Result, error) {
+	steps := plan.Status.Steps
+	if plan.Status.StepStartedAt != nil && int(plan.Status.RolledBackSteps) < len(steps) {
+		step := steps[len(steps)-1-int(plan.Status.RolledBackSteps)]
+		running, err := r.replacementsRunning(ctx, step.Namespace, step.OwnerUID)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !running {
+			if timeout := planStepTimeout(plan); time.Since(plan.Status.StepStartedAt.Time) <= timeout {
+				return r.updatePlanStatus(ctx, plan, planPollInterval)
+			}
+			log.FromContext(ctx).Info("Rolled back pods were not replaced in time", "plan", plan.Name,
+				"namespace", step.Namespace, "fromNodes", step.FromNodes)
+		}
+		// Replacements whose eviction was blocked are moved back on the next pass
+		plan.Status.StepStartedAt = nil
+	}
+
+	for int(plan.Status.RolledBackSteps) < len(steps) {
+		index := len(steps) - 1 - int(plan.Status.RolledBackSteps)
+		pods, err := r.stepReplacements(ctx, plan, index)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(pods) == 0 {
+			plan.Status.RolledBackSteps++
+			continue
+		}
+
+		evicted := 0
+		for _, pod := range pods {
+			blocked, err := r.evictPod(ctx, pod)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if blocked != "" {
+				if evicted == 0 {
+					plan.Status.Message = blocked
+					return r.updatePlanStatus(ctx, plan, evictionRetryInterval)
+				}
+				break
+			}
+			evicted++
+		}
+		now := metav1.Now()
+		plan.Status.StepStartedAt = &now
+		plan.Status.Message = fmt.Sprintf("Moving %d pods back to %s", evicted, strings.Join(steps[index].FromNodes, ", "))
+		log.FromContext(ctx).Info("Evicted pods to roll back optimization plan", "plan", plan.Name, "pods", evicted,
+			"step", index+1, "of", len(steps))
+		return r.updatePlanStatus(ctx, plan, planPollInterval)
+	}
+
+	now := metav1.Now()
+	plan.Status.Phase = kcloudv1alpha1.PlanPhaseRolledBack
+	plan.Status.CompletedAt = &now
+	plan.Status.Message = fmt.Sprintf("Moved the pods of %d steps back", len(steps))
+	log.FromContext(ctx).Info("Optimization plan rolled back", "plan", plan.Name, "steps", len(steps))
+	r.event(plan, corev1.EventTypeNormal, "PlanRolledBack", "%s", plan.Status.Message)
+	return r.updatePlanStatus(ctx, plan, 0)
+}
+
+// stepReplacements returns the running replacements of the step's pods that are not back on
+// the nodes it moved them off: the pods of its controller created after the step started and
+// before the next one did, or the rollback, oldest first and at most one per move
+func (r *OptimizationPlanReconciler) stepReplacements(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan,
+	index int) ([]*corev1.Pod, error) {
+	step := plan.Status.Steps[index]
+	if step.OwnerUID == "" {
+		return nil, nil
+	}
+	end := plan.Status.RollbackStartedAt
+	if index+1 < len(plan.Status.Steps) {
+		end = &plan.Status.Steps[index+1].StartedAt
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(step.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list pods in %s: %w", step.Namespace, err)
+	}
+	var replacements []*corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		controller := metav1.GetControllerOf(pod)
+		if controller == nil || controller.UID != step.OwnerUID || !pod.DeletionTimestamp.IsZero() ||
+			pod.Status.Phase != corev1.PodRunning || slices.Contains(step.FromNodes, pod.Spec.NodeName) ||
+			pod.CreationTimestamp.Before(&step.StartedAt) || (end != nil && !pod.CreationTimestamp.Before(end)) {
+			continue
+		}
+		replacements = append(replacements, pod)
+	}
+	slices.SortFunc(replacements, func(a, b *corev1.Pod) int {
+		return a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+	})
+	return replacements[:min(len(replacements), int(step.Moves))], nil
+}
+
+// recordStep appends the step in progress to the plan's steps
+func recordStep(plan *kcloudv1alpha1.OptimizationPlan) {
+	first, count := int(plan.Status.CompletedSteps), int(plan.Status.StepMoves)
+	if plan.Status.StepStartedAt == nil || count <= 0 || first < 0 || first+count > len(plan.Spec.Moves) {
+		return
+	}
+	moves := plan.Spec.Moves[first : first+count]
+	step := kcloudv1alpha1.PlanStep{
+		Namespace: moves[0].Namespace,
+		OwnerUID:  plan.Status.StepOwnerUID,
+		Moves:     plan.Status.StepMoves,
+		StartedAt: *plan.Status.StepStartedAt,
+	}
+	for _, move := range moves {
+		if !slices.Contains(step.FromNodes, move.FromNode) {
+			step.FromNodes = append(step.FromNodes, move.FromNode)
+		}
+	}
+	plan.Status.Steps = append(plan.Status.Steps, step)
+}
+
+// handlePlanDeletion uncordons the nodes of a plan deleted before it was fully applied
+func (r *OptimizationPlanReconciler) handlePlanDeletion(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan) error {
+	if !controllerutil.ContainsFinalizer(plan, optimizationPlanFinalizer) {
+		return nil
+	}
+	if plan.Status.Phase != kcloudv1alpha1.PlanPhaseApplied {
+		if err := r.uncordon(ctx, plan); err != nil {
+			return err
+		}
+	}
+	controllerutil.RemoveFinalizer(plan, optimizationPlanFinalizer)
+	if err := r.Update(ctx, plan); err != nil {
+		return fmt.Errorf("failed to remove optimization plan finalizer: %w", err)
+	}
+	return nil
+}
+
+// uncordon makes the nodes the plan cordoned schedulable again
+func (r *OptimizationPlanReconciler) uncordon(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan) error {
+	for _, name := range plan.Status.CordonedNodes {
+		if _, err := r.setUnschedulable(ctx, name, false); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to uncordon node %s: %w", name, err)
+		}
+	}
+	plan.Status.CordonedNodes = nil
+	return nil
+}
+
+// setUnschedulable cordons or uncordons the node, reporting whether it changed
+func (r *OptimizationPlanReconciler) setUnschedulable(ctx context.Context, name string, unschedulable bool) (bool, error) {
+	var node corev1.Node
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, &node); err != nil {
+		return false, err
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return false, nil
+	}
+	original := node.DeepCopy()
+	node.Spec.Unschedulable = unschedulable
+	if err := r.Patch(ctx, &node, client.MergeFrom(original)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// planWorkloads returns the WorkloadOptimizers, as namespace/name, whose pods the plan moves
+func (r *OptimizationPlanReconciler) planWorkloads(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan) ([]string, error) {
+	var workloads []string
+	for _, move := range plan.Spec.Moves {
+		var pod corev1.Pod
+		if err := r.Get(ctx, client.ObjectKey{Namespace: move.Namespace, Name: move.Pod}, &pod); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get pod %s/%s: %w", move.Namespace, move.Pod, err)
+		}
+		name := pod.Annotations[scheduler.WorkloadOptimizerAnnotation]
+		if key := move.Namespace + "/" + name; name != "" && !slices.Contains(workloads, key) {
+			workloads = append(workloads, key)
+		}
+	}
+	slices.Sort(workloads)
+	return workloads, nil
+}
+
+// planWorkloadMetrics are the optimization metrics of the workloads with moved pods
+type planWorkloadMetrics struct {
+	// score is their average optimization score, nil when none has one
+	score *float64
+	// cost is their summed current cost, nil when none has one
+	cost *float64
+	// latencyP99 is their highest predicted p99 latency, nil when none has one
+	latencyP99 *metav1.Duration
+	// evaluatedAt is the oldest of their last optimization times
+	evaluatedAt time.Time
+}
+
+// workloadMetrics reads the optimization metrics of the workloads
+func (r *OptimizationPlanReconciler) workloadMetrics(ctx context.Context, workloads []string) (planWorkloadMetrics, error) {
+	var metrics planWorkloadMetrics
+	var total, cost float64
+	var scored, costed int
+	for i, key := range workloads {
+		namespace, name, _ := strings.Cut(key, "/")
+		var wo kcloudv1alpha1.WorkloadOptimizer
+		if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &wo); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return metrics, fmt.Errorf("failed to get workload %s: %w", key, err)
+		}
+		if wo.Status.OptimizationScore != nil {
+			total += *wo.Status.OptimizationScore
+			scored++
+		}
+		if wo.Status.CurrentCost != nil {
+			cost += *wo.Status.CurrentCost
+			costed++
+		}
+		if sla := wo.Status.SLA; sla != nil && sla.LatencyP99 != nil &&
+			(metrics.latencyP99 == nil || sla.LatencyP99.Duration > metrics.latencyP99.Duration) {
+			metrics.latencyP99 = sla.LatencyP99.DeepCopy()
+		}
+		var evaluatedAt time.Time
+		if wo.Status.LastOptimizationTime != nil {
+			evaluatedAt = wo.Status.LastOptimizationTime.Time
+		}
+		if i == 0 || evaluatedAt.Before(metrics.evaluatedAt) {
+			metrics.evaluatedAt = evaluatedAt
+		}
+	}
+	if scored > 0 {
+		metrics.score = ptr.To(total / float64(scored))
+	}
+	if costed > 0 {
+		metrics.cost = ptr.To(cost)
+	}
+	return metrics, nil
+}
+
+// planRegression explains how the realized metrics underperform the baseline, empty when
+// they are within the plan's limits
+func planRegression(plan *kcloudv1alpha1.OptimizationPlan) string {
+	status := plan.Status
+	if baseline, realized := status.BaselineScore, status.RealizedScore; baseline != nil && realized != nil &&
+		*baseline-*realized > maxScoreRegression(plan) {
+		return fmt.Sprintf("Average optimization score fell from %.2f to %.2f", *baseline, *realized)
+	}
+	if baseline, realized := status.BaselineCost, status.RealizedCost; baseline != nil && realized != nil &&
+		*baseline > 0 && (*realized-*baseline) / *baseline > maxCostIncrease(plan) {
+		money := cmp.Or(plan.Spec.Currency, string(currency.Default))
+		return fmt.Sprintf("Current cost rose from %.4f to %.4f %s per hour", *baseline, *realized, money)
+	}
+	if baseline, realized := status.BaselineLatencyP99, status.RealizedLatencyP99; baseline != nil && realized != nil &&
+		baseline.Duration > 0 &&
+		float64(realized.Duration-baseline.Duration)/float64(baseline.Duration) > maxLatencyIncrease(plan) {
+		return fmt.Sprintf("Predicted p99 latency rose from %s to %s", baseline.Duration, realized.Duration)
+	}
+	return ""
+}
+
+// updatePlanStatus stores the plan's status and requeues after the delay, if any. A status
+// that changed also triggers the next reconcile through the plan's watch.
+func (r *OptimizationPlanReconciler) updatePlanStatus(ctx context.Context, plan *kcloudv1alpha1.OptimizationPlan,
+	requeueAfter time.Duration) (ctrl.Result, error) {
+	if err := r.Status().Update(ctx, plan); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update optimization plan status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// event records an event on the plan when a recorder is set
+func (r *OptimizationPlanReconciler) event(plan *kcloudv1alpha1.OptimizationPlan, eventType, reason, format string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(plan, eventType, reason, format, args...)
+	}
+}
+
+// maxScoreRegression returns the score drop that aborts the plan
+func maxScoreRegression(plan *kcloudv1alpha1.OptimizationPlan) float64 {
+	if plan.Spec.MaxScoreRegression != nil {
+		return *plan.Spec.MaxScoreRegression
+	}
+	return defaultMaxScoreRegression
+}
+
+// maxCostIncrease returns the relative cost rise that aborts the plan
+func maxCostIncrease(plan *kcloudv1alpha1.OptimizationPlan) float64 {
+	if plan.Spec.MaxCostIncrease != nil {
+		return *plan.Spec.MaxCostIncrease
+	}
+	return defaultMaxCostIncrease
+}
+
+// maxLatencyIncrease returns the relative p99 latency rise that aborts the plan
+func maxLatencyIncrease(plan *kcloudv1alpha1.OptimizationPlan) float64 {
+	if plan.Spec.MaxLatencyIncrease != nil {
+		return *plan.Spec.MaxLatencyIncrease
+	}
+	return defaultMaxLatencyIncrease
+}
+
+// canaryFraction returns the share of a workload's replicas moved per step
+func canaryFraction(plan *kcloudv1alpha1.OptimizationPlan) float64 {
+	if plan.Spec.CanaryFraction != nil && *plan.Spec.CanaryFraction > 0 {
+		return *plan.Spec.CanaryFraction
+	}
+	return defaultCanaryFraction
+}
+
+// planStepTimeout returns how long a step may take to be replaced and evaluated
+func planStepTimeout(plan *kcloudv1alpha1.OptimizationPlan) time.Duration {
+	if plan.Spec.StepTimeout != nil && plan.Spec.StepTimeout.Duration > 0 {
+		return plan.Spec.StepTimeout.Duration
+	}
+	return defaultPlanStepTimeout
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OptimizationPlanReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kcloudv1alpha1.OptimizationPlan{}).
+		Complete(r)
+}
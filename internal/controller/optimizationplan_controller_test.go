@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+var _ = Describe("OptimizationPlan Controller", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *OptimizationPlanReconciler
+		stepStart  time.Time
+	)
+
+	const owner = types.UID("web-replicaset")
+
+	pod := func(name, node string, created time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(created),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web", UID: owner, Controller: ptr.To(true),
+				}},
+			},
+			Spec:   corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	reconcile := func() *kcloudv1alpha1.OptimizationPlan {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "consolidation"}})
+		Expect(err).NotTo(HaveOccurred())
+		var plan kcloudv1alpha1.OptimizationPlan
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "consolidation"}, &plan)).To(Succeed())
+		return &plan
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(kcloudv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		stepStart = time.Now().Add(-time.Hour).Truncate(time.Second)
+		plan := &kcloudv1alpha1.OptimizationPlan{
+			ObjectMeta: metav1.ObjectMeta{Name: "consolidation"},
+			Spec: kcloudv1alpha1.OptimizationPlanSpec{
+				Approved:   true,
+				DrainNodes: []string{"node-a"},
+				Moves:      []kcloudv1alpha1.PlanMove{{Namespace: "default", Pod: "web-1", FromNode: "node-a", ToNode: "node-b"}},
+			},
+			Status: kcloudv1alpha1.OptimizationPlanStatus{
+				Phase:          kcloudv1alpha1.PlanPhaseRollingBack,
+				CompletedSteps: 1,
+				Steps: []kcloudv1alpha1.PlanStep{{
+					Namespace: "default",
+					OwnerUID:  owner,
+					FromNodes: []string{"node-a"},
+					Moves:     1,
+					StartedAt: metav1.NewTime(stepStart),
+				}},
+				RollbackStartedAt: ptr.To(metav1.NewTime(stepStart.Add(30 * time.Minute))),
+			},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).
+			WithStatusSubresource(&kcloudv1alpha1.OptimizationPlan{}).
+			WithObjects(plan,
+				pod("web-0", "node-b", stepStart.Add(-time.Hour)),
+				pod("web-2", "node-b", stepStart.Add(time.Minute)),
+				pod("web-3", "node-a", stepStart.Add(2*time.Minute))).
+			Build()
+		reconciler = &OptimizationPlanReconciler{Client: fakeClient, Scheme: scheme}
+	})
+
+	Context("When a plan rolls back", func() {
+		It("Should move the replacements of its steps back and end rolled back", func() {
+			plan := reconcile()
+			Expect(plan.Status.Phase).To(Equal(kcloudv1alpha1.PlanPhaseRollingBack))
+			Expect(plan.Status.StepStartedAt).NotTo(BeNil())
+
+			// Only the replacement created while the step was applied, away from node-a, is evicted
+			var evicted corev1.Pod
+			err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "web-2"}, &evicted)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			for _, name := range []string{"web-0", "web-3"} {
+				Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &corev1.Pod{})).To(Succeed())
+			}
+
+			Expect(fakeClient.Create(ctx, pod("web-4", "node-a", time.Now()))).To(Succeed())
+			plan = reconcile()
+			Expect(plan.Status.Phase).To(Equal(kcloudv1alpha1.PlanPhaseRolledBack))
+			Expect(plan.Status.RolledBackSteps).To(Equal(int32(1)))
+			Expect(plan.Status.CompletedAt).NotTo(BeNil())
+		})
+	})
+
+	It("Should record the step in progress when metrics regress", func() {
+		plan := &kcloudv1alpha1.OptimizationPlan{
+			Spec: kcloudv1alpha1.OptimizationPlanSpec{Moves: []kcloudv1alpha1.PlanMove{
+				{Namespace: "default", Pod: "web-1", FromNode: "node-a"},
+				{Namespace: "default", Pod: "web-2", FromNode: "node-a"},
+				{Namespace: "default", Pod: "web-3", FromNode: "node-c"},
+			}},
+			Status: kcloudv1alpha1.OptimizationPlanStatus{
+				CompletedSteps: 1,
+				StepMoves:      2,
+				StepOwnerUID:   owner,
+				StepStartedAt:  ptr.To(metav1.NewTime(stepStart)),
+			},
+		}
+		recordStep(plan)
+		Expect(plan.Status.Steps).To(HaveLen(1))
+		Expect(plan.Status.Steps[0].FromNodes).To(Equal([]string{"node-a", "node-c"}))
+		Expect(plan.Status.Steps[0].Moves).To(Equal(int32(2)))
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+const (
+	// maxStatusPodCosts is how many pods' costs the status lists
+	maxStatusPodCosts = 100
+
+	// podCostChangeThreshold is the relative change in a pod's cost that is republished
+	podCostChangeThreshold = 0.05
+	// minPodCostChange is the smallest change in a pod's cost per hour that is republished
+	minPodCostChange = 0.0001
+)
+
+// podCosts converts the engine's per-pod costs, keeping the first maxStatusPodCosts by name
+func podCosts(costs []optimizer.PodCost) []kcloudv1alpha1.PodCost {
+	if len(costs) == 0 {
+		return nil
+	}
+	costs = costs[:min(len(costs), maxStatusPodCosts)]
+	converted := make([]kcloudv1alpha1.PodCost, 0, len(costs))
+	for _, cost := range costs {
+		converted = append(converted, kcloudv1alpha1.PodCost{
+			Name:        cost.Name,
+			Node:        cost.Node,
+			CostPerHour: cost.Cost,
+			Realized:    cost.Realized,
+		})
+	}
+	return converted
+}
+
+// annotatePodCosts publishes each running pod's cost per hour in its annotations. Like pod
+// power, a pod is only patched when its cost changed by more than podCostChangeThreshold or
+// its currency changed, so small changes in the estimate do not rewrite the pods.
+func (r *WorkloadOptimizerReconciler) annotatePodCosts(ctx context.Context, pods []corev1.Pod,
+	result *optimizer.OptimizationResult) {
+	logger := log.FromContext(ctx)
+	currency := string(r.Optimizer.Currency.Currency())
+	costs := make(map[string]float64, len(result.PodCosts))
+	for _, cost := range result.PodCosts {
+		costs[cost.Name] = cost.Cost
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		cost, ok := costs[pod.Name]
+		if !ok {
+			continue
+		}
+		published, err := strconv.ParseFloat(pod.Annotations[optimizer.PodCostAnnotation], 64)
+		if err == nil && pod.Annotations[optimizer.PodCostCurrencyAnnotation] == currency &&
+			math.Abs(cost-published) <= math.Max(podCostChangeThreshold*published, minPodCostChange) {
+			continue
+		}
+		original := pod.DeepCopy()
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[optimizer.PodCostAnnotation] = strconv.FormatFloat(cost, 'f', 4, 64)
+		pod.Annotations[optimizer.PodCostCurrencyAnnotation] = currency
+		if err := r.Patch(ctx, pod, client.MergeFrom(original)); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "Failed to annotate pod cost", "pod", pod.Name)
+		}
+	}
+}
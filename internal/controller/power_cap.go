@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+)
+
+// conditionPowerCapped reports whether a PowerPolicy throttled or paused the workload
+const conditionPowerCapped = "PowerCapped"
+
+// checkPowerCap records in the PowerCapped condition whether a PowerPolicy throttled or
+// paused the workload. Paused workloads stay Suspended until the policy restores them.
+func (r *WorkloadOptimizerReconciler) checkPowerCap(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) {
+	var policies kcloudv1alpha1.PowerPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list power policies")
+		return
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		enforced := enforcedWorkload(policy, wo)
+		if enforced == nil {
+			continue
+		}
+		meta.SetStatusCondition(&wo.Status.Conditions, metav1.Condition{
+			Type:   conditionPowerCapped,
+			Status: metav1.ConditionTrue,
+			Reason: enforced.State,
+			Message: r.message(wo, messages.PowerCapped, map[string]any{
+				"State":  enforced.State,
+				"Policy": policy.Name,
+				"Limit":  policy.Spec.MaxPowerUsage,
+			}),
+		})
+		if enforced.State == kcloudv1alpha1.EnforcedPaused {
+			wo.Status.Phase = phaseSuspended
+		}
+		return
+	}
+	meta.RemoveStatusCondition(&wo.Status.Conditions, conditionPowerCapped)
+}
+
+// powerCapped reports whether a PowerPolicy holds the workload scaled down
+func powerCapped(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	return meta.IsStatusConditionTrue(wo.Status.Conditions, conditionPowerCapped)
+}
@@ -0,0 +1,520 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+const (
+	// DefaultPowerPolicyInterval is how often a PowerPolicy compares its workloads' power with its cap
+	DefaultPowerPolicyInterval = time.Minute
+
+	// powerPolicyFinalizer restores the workloads a deleted policy throttled or paused
+	powerPolicyFinalizer = "powerpolicy.kcloud.io/finalizer"
+
+	// conditionPowerWithinLimit reports whether a PowerPolicy's workloads draw no more than its cap
+	conditionPowerWithinLimit = "PowerWithinLimit"
+)
+
+// PowerPolicyReconciler keeps the workloads a PowerPolicy covers under its MaxPowerUsage. It
+// sums their current power into the policy's status and, when the policy enforces its cap,
+// throttles or pauses the lowest-priority workloads until the total is under it, capping
+// node power as a last resort. They are restored, highest priority first, once their power
+// fits under the cap again.
+type PowerPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Recorder emits events on policies and the workloads they enforce; nil disables events
+	Recorder record.EventRecorder
+	// Messages renders condition and event messages; nil uses the built-in catalog
+	Messages *messages.Catalog
+	// Interval is how often each policy is evaluated; zero uses DefaultPowerPolicyInterval
+	Interval time.Duration
+}
+
+//+kubebuilder:rbac:groups=kcloud.io,resources=powerpolicies,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=kcloud.io,resources=powerpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kcloud.io,resources=powerpolicies/finalizers,verbs=update
+
+// Reconcile compares the power of the policy's workloads with its cap and enforces it
+func (r *PowerPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var policy kcloudv1alpha1.PowerPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !policy.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.handlePolicyDeletion(ctx, &policy)
+	}
+	if err := r.syncFinalizer(ctx, &policy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	workloads, err := r.coveredWorkloads(ctx, &policy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	original := policy.DeepCopy()
+	var power, carbon float64
+	for i := range workloads {
+		wo := &workloads[i]
+		power += workloadPower(wo, enforcedWorkload(&policy, wo))
+		if wo.Status.Phase != phaseSuspended {
+			carbon += ptr.Deref(wo.Status.CarbonFootprint, 0)
+		}
+	}
+	policy.Status.CurrentPowerUsage = &power
+	policy.Status.CarbonFootprint = &carbon
+	r.checkPowerLimit(ctx, &policy, power)
+	r.enforce(ctx, &policy, workloads, power)
+
+	now := metav1.Now()
+	policy.Status.LastUpdated = &now
+	if err := r.Status().Patch(ctx, &policy, client.MergeFrom(original)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to patch power policy status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: r.interval()}, nil
+}
+
+// syncFinalizer adds the finalizer to enforcing policies and removes it once a policy that
+// stopped enforcing has restored its workloads
+func (r *PowerPolicyReconciler) syncFinalizer(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy) error {
+	changed := false
+	if policy.Spec.Enforcement != nil {
+		changed = controllerutil.AddFinalizer(policy, powerPolicyFinalizer)
+	} else if len(policy.Status.Enforced) == 0 && policy.Status.NodePowerCap == nil {
+		changed = controllerutil.RemoveFinalizer(policy, powerPolicyFinalizer)
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Update(ctx, policy); err != nil {
+		return fmt.Errorf("failed to update power policy finalizer: %w", err)
+	}
+	return nil
+}
+
+// handlePolicyDeletion restores the workloads a deleted policy throttled or paused and lifts
+// its node power caps
+func (r *PowerPolicyReconciler) handlePolicyDeletion(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy) error {
+	if !controllerutil.ContainsFinalizer(policy, powerPolicyFinalizer) {
+		return nil
+	}
+	if err := r.uncapNodes(ctx, policy); err != nil {
+		return err
+	}
+	for _, enforced := range policy.Status.Enforced {
+		if err := restoreScaled(ctx, r.Client, enforced.Namespace, enforced.ScaledTargets); err != nil {
+			return fmt.Errorf("failed to restore workload %s/%s: %w", enforced.Namespace, enforced.Name, err)
+		}
+	}
+	controllerutil.RemoveFinalizer(policy, powerPolicyFinalizer)
+	if err := r.Update(ctx, policy); err != nil {
+		return fmt.Errorf("failed to remove power policy finalizer: %w", err)
+	}
+	return nil
+}
+
+// coveredWorkloads returns the WorkloadOptimizers the policy's selectors match
+func (r *PowerPolicyReconciler) coveredWorkloads(ctx context.Context,
+	policy *kcloudv1alpha1.PowerPolicy) ([]kcloudv1alpha1.WorkloadOptimizer, error) {
+	var workloads kcloudv1alpha1.WorkloadOptimizerList
+	if err := r.List(ctx, &workloads); err != nil {
+		return nil, fmt.Errorf("failed to list workload optimizers: %w", err)
+	}
+	namespaceLabels := make(map[string]labels.Set)
+	if policy.Spec.NamespaceSelector != nil {
+		var namespaces corev1.NamespaceList
+		if err := r.List(ctx, &namespaces); err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		for _, namespace := range namespaces.Items {
+			namespaceLabels[namespace.Name] = labels.Set(namespace.Labels)
+		}
+	}
+
+	var covered []kcloudv1alpha1.WorkloadOptimizer
+	for _, wo := range workloads.Items {
+		matches, err := optimizer.SelectorsCover(policy.Spec.NamespaceSelector, policy.Spec.WorkloadSelector,
+			namespaceLabels[wo.Namespace], labels.Set(wo.Labels))
+		if err != nil {
+			return nil, fmt.Errorf("invalid power policy selector: %w", err)
+		}
+		if matches {
+			covered = append(covered, wo)
+		}
+	}
+	return covered, nil
+}
+
+// checkPowerLimit sets the PowerWithinLimit condition and the phase, counting each time the
+// workloads go over the cap as a violation
+func (r *PowerPolicyReconciler) checkPowerLimit(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy, power float64) {
+	limit := policy.Spec.MaxPowerUsage
+	locale := policy.Annotations[messages.LocaleAnnotation]
+	data := map[string]any{"Power": power, "Limit": limit}
+	condition := metav1.Condition{
+		Type:               conditionPowerWithinLimit,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: policy.Generation,
+		Reason:             "WithinLimit",
+		Message:            r.Messages.Render(locale, messages.PowerWithinLimit, data),
+	}
+	policy.Status.Phase = "Active"
+	if power > limit {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "OverLimit"
+		condition.Message = r.Messages.Render(locale, messages.PowerOverLimit, data)
+		policy.Status.Phase = "Violated"
+
+		last := meta.FindStatusCondition(policy.Status.Conditions, conditionPowerWithinLimit)
+		if last == nil || last.Status != metav1.ConditionFalse {
+			policy.Status.Violations = ptr.To(ptr.Deref(policy.Status.Violations, 0) + 1)
+			log.FromContext(ctx).Info("Workload power exceeds power policy limit", "powerPolicy", policy.Name,
+				"power", power, "limit", limit)
+			r.event(policy, corev1.EventTypeWarning, "PowerOverLimit", condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&policy.Status.Conditions, condition)
+}
+
+// enforce releases the workloads the policy no longer covers or enforces. While power is over
+// the cap it throttles or pauses the lowest-priority workloads, capping node power as a last
+// resort; otherwise it lifts the node caps and then restores enforced workloads whose power
+// fits under the cap again. Failures are logged and retried on the next evaluation.
+func (r *PowerPolicyReconciler) enforce(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy,
+	workloads []kcloudv1alpha1.WorkloadOptimizer, power float64) {
+	byKey := make(map[string]*kcloudv1alpha1.WorkloadOptimizer, len(workloads))
+	for i := range workloads {
+		byKey[workloads[i].Namespace+"/"+workloads[i].Name] = &workloads[i]
+	}
+
+	var kept []kcloudv1alpha1.EnforcedWorkload
+	for _, enforced := range policy.Status.Enforced {
+		wo := byKey[enforced.Namespace+"/"+enforced.Name]
+		if policy.Spec.Enforcement != nil && wo != nil {
+			kept = append(kept, enforced)
+			continue
+		}
+		if err := r.release(ctx, policy, enforced, wo); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to restore workload", "powerPolicy", policy.Name,
+				"workload", enforced.Namespace+"/"+enforced.Name)
+			kept = append(kept, enforced)
+		}
+	}
+	policy.Status.Enforced = kept
+	if policy.Spec.Enforcement == nil || policy.Spec.Enforcement.NodePowerCap == nil {
+		if err := r.uncapNodes(ctx, policy); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to lift node power caps", "powerPolicy", policy.Name)
+		}
+	}
+	if policy.Spec.Enforcement == nil {
+		return
+	}
+
+	if power > policy.Spec.MaxPowerUsage {
+		power = r.capWorkloads(ctx, policy, workloads, power)
+		if power > policy.Spec.MaxPowerUsage && policy.Spec.Enforcement.NodePowerCap != nil {
+			r.capNodes(ctx, policy, workloads, power)
+		}
+		return
+	}
+	if !nodePowerCapFits(policy, power) {
+		return
+	}
+	if err := r.uncapNodes(ctx, policy); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to lift node power caps", "powerPolicy", policy.Name)
+		return
+	}
+	r.restoreWithinLimit(ctx, policy, byKey, power)
+}
+
+// capWorkloads throttles or pauses workloads, lowest priority and then highest power first,
+// until their estimated power is under the cap, and returns the estimated power. Workloads
+// at or above the exempt priority, or holding an active do-not-disturb lease, are left alone.
+func (r *PowerPolicyReconciler) capWorkloads(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy,
+	workloads []kcloudv1alpha1.WorkloadOptimizer, power float64) float64 {
+	exempt := policy.Spec.Enforcement.ExemptPriority
+	now := time.Now()
+	var candidates []*kcloudv1alpha1.WorkloadOptimizer
+	for i := range workloads {
+		wo := &workloads[i]
+		if enforcedWorkload(policy, wo) != nil || wo.Status.Phase == phaseSuspended ||
+			ptr.Deref(wo.Status.CurrentPower, 0) <= 0 || (exempt != nil && wo.Spec.Priority >= *exempt) ||
+			optimizer.DoNotDisturbActive(wo, now) {
+			continue
+		}
+		candidates = append(candidates, wo)
+	}
+	slices.SortFunc(candidates, func(a, b *kcloudv1alpha1.WorkloadOptimizer) int {
+		return cmp.Or(cmp.Compare(a.Spec.Priority, b.Spec.Priority),
+			cmp.Compare(ptr.Deref(b.Status.CurrentPower, 0), ptr.Deref(a.Status.CurrentPower, 0)),
+			cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	for _, wo := range candidates {
+		if power <= policy.Spec.MaxPowerUsage {
+			return power
+		}
+		enforced, err := r.capWorkload(ctx, policy, wo)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to enforce power policy on workload", "powerPolicy", policy.Name,
+				"workload", wo.Namespace+"/"+wo.Name)
+		}
+		if enforced != nil {
+			policy.Status.Enforced = append(policy.Status.Enforced, *enforced)
+			power -= enforced.Power - enforcedPower(enforced)
+		}
+	}
+	if power > policy.Spec.MaxPowerUsage {
+		log.FromContext(ctx).Info("No more workloads to throttle or pause under power policy", "powerPolicy", policy.Name,
+			"estimatedPower", power, "limit", policy.Spec.MaxPowerUsage)
+	}
+	return power
+}
+
+// capWorkload throttles or pauses the workload as the policy's enforcement says, pausing
+// workloads a HorizontalPodAutoscaler scales since it would undo their throttling. It returns
+// what was scaled down, or nil when the workload had nothing to scale.
+func (r *PowerPolicyReconciler) capWorkload(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy,
+	wo *kcloudv1alpha1.WorkloadOptimizer) (*kcloudv1alpha1.EnforcedWorkload, error) {
+	pods, err := getAssociatedPods(ctx, r.Client, wo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	state, replicas := kcloudv1alpha1.EnforcedPaused, int32(0)
+	if policy.Spec.Enforcement.Action == kcloudv1alpha1.PowerEnforcementThrottle {
+		state, replicas = kcloudv1alpha1.EnforcedThrottled, 1
+		autoscaled, err := autoscaledController(ctx, r.Client, wo.Namespace, pods)
+		if err != nil {
+			return nil, err
+		}
+		if autoscaled != "" {
+			// A HorizontalPodAutoscaler scales a throttled controller straight back up to its
+			// minimum, but leaves one scaled to zero alone
+			state, replicas = kcloudv1alpha1.EnforcedPaused, 0
+			log.FromContext(ctx).Info("Pausing instead of throttling workload scaled by a HorizontalPodAutoscaler",
+				"powerPolicy", policy.Name, "workload", wo.Namespace+"/"+wo.Name, "controller", autoscaled)
+			r.event(policy, corev1.EventTypeNormal, "ThrottleAutoscaled",
+				fmt.Sprintf("Pausing %s/%s instead of throttling it: a HorizontalPodAutoscaler scales %s",
+					wo.Namespace, wo.Name, autoscaled))
+		}
+	}
+	targets, err := scaleDown(ctx, r.Client, wo.Namespace, pods, replicas)
+	if err == nil && len(targets) == 0 && replicas > 0 {
+		// Workloads already at one replica can only be paused
+		state = kcloudv1alpha1.EnforcedPaused
+		targets, err = scaleDown(ctx, r.Client, wo.Namespace, pods, 0)
+	}
+	if len(targets) == 0 {
+		return nil, err
+	}
+
+	enforced := &kcloudv1alpha1.EnforcedWorkload{
+		Namespace:     wo.Namespace,
+		Name:          wo.Name,
+		State:         state,
+		Power:         ptr.Deref(wo.Status.CurrentPower, 0),
+		Since:         metav1.Now(),
+		ScaledTargets: targets,
+	}
+	log.FromContext(ctx).Info("Power policy capped workload", "powerPolicy", policy.Name,
+		"workload", wo.Namespace+"/"+wo.Name, "state", state, "power", enforced.Power)
+	r.event(wo, corev1.EventTypeWarning, "Power"+state,
+		r.Messages.Render(wo.Annotations[messages.LocaleAnnotation], messages.PowerCapped, map[string]any{
+			"State":  state,
+			"Policy": policy.Name,
+			"Limit":  policy.Spec.MaxPowerUsage,
+		}))
+	r.event(policy, corev1.EventTypeNormal, "Workload"+state,
+		fmt.Sprintf("%s %s/%s drawing %.0f W", state, wo.Namespace, wo.Name, enforced.Power))
+	return enforced, err
+}
+
+// autoscaledController returns the first controller of the pods, as kind/name, that a
+// HorizontalPodAutoscaler scales, or "" when none is
+func autoscaledController(ctx context.Context, c client.Client, namespace string, pods []corev1.Pod) (string, error) {
+	autoscaled, err := autoscaledTargets(ctx, c, namespace)
+	if err != nil || len(autoscaled) == 0 {
+		return "", err
+	}
+	for i := range pods {
+		controller, err := podController(ctx, c, &pods[i])
+		if err != nil {
+			return "", err
+		}
+		if key := controller.kind + "/" + controller.object.GetName(); autoscaled[key] {
+			return key, nil
+		}
+	}
+	return "", nil
+}
+
+// restoreWithinLimit restores enforced workloads, highest priority first, while the power
+// they drew before enforcement still fits under the cap. Workloads that are gone or no longer
+// covered are kept as they are; enforce retries their release on every evaluation.
+func (r *PowerPolicyReconciler) restoreWithinLimit(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy,
+	byKey map[string]*kcloudv1alpha1.WorkloadOptimizer, power float64) {
+	var kept, enforced []kcloudv1alpha1.EnforcedWorkload
+	for _, workload := range policy.Status.Enforced {
+		if byKey[workload.Namespace+"/"+workload.Name] == nil {
+			kept = append(kept, workload)
+			continue
+		}
+		enforced = append(enforced, workload)
+	}
+	priority := func(workload kcloudv1alpha1.EnforcedWorkload) int32 {
+		if wo := byKey[workload.Namespace+"/"+workload.Name]; wo != nil {
+			return wo.Spec.Priority
+		}
+		return -1
+	}
+	slices.SortStableFunc(enforced, func(a, b kcloudv1alpha1.EnforcedWorkload) int {
+		return cmp.Compare(priority(b), priority(a))
+	})
+
+	for _, workload := range enforced {
+		wo := byKey[workload.Namespace+"/"+workload.Name]
+		added := workload.Power - workloadPower(wo, &workload)
+		if power+added > policy.Spec.MaxPowerUsage {
+			kept = append(kept, workload)
+			continue
+		}
+		if err := r.release(ctx, policy, workload, wo); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to restore workload", "powerPolicy", policy.Name,
+				"workload", workload.Namespace+"/"+workload.Name)
+			kept = append(kept, workload)
+			continue
+		}
+		power += added
+	}
+	policy.Status.Enforced = kept
+}
+
+// release restores the controllers the policy scaled down for the workload, which is nil when
+// the workload is gone or no longer covered
+func (r *PowerPolicyReconciler) release(ctx context.Context, policy *kcloudv1alpha1.PowerPolicy,
+	enforced kcloudv1alpha1.EnforcedWorkload, wo *kcloudv1alpha1.WorkloadOptimizer) error {
+	if err := restoreScaled(ctx, r.Client, enforced.Namespace, enforced.ScaledTargets); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Power policy restored workload", "powerPolicy", policy.Name,
+		"workload", enforced.Namespace+"/"+enforced.Name, "state", enforced.State)
+	if wo != nil {
+		r.event(wo, corev1.EventTypeNormal, "PowerRestored", fmt.Sprintf("Restored by power policy %s", policy.Name))
+	}
+	r.event(policy, corev1.EventTypeNormal, "WorkloadRestored",
+		fmt.Sprintf("Restored %s/%s", enforced.Namespace, enforced.Name))
+	return nil
+}
+
+// event records an event on the object when a recorder is set
+func (r *PowerPolicyReconciler) event(object runtime.Object, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(object, eventType, reason, message)
+	}
+}
+
+// interval returns how often policies are evaluated
+func (r *PowerPolicyReconciler) interval() time.Duration {
+	if r.Interval > 0 {
+		return r.Interval
+	}
+	return DefaultPowerPolicyInterval
+}
+
+// enforcedWorkload returns the policy's enforcement record for the workload, or nil
+func enforcedWorkload(policy *kcloudv1alpha1.PowerPolicy,
+	wo *kcloudv1alpha1.WorkloadOptimizer) *kcloudv1alpha1.EnforcedWorkload {
+	for i := range policy.Status.Enforced {
+		if enforced := &policy.Status.Enforced[i]; enforced.Namespace == wo.Namespace && enforced.Name == wo.Name {
+			return enforced
+		}
+	}
+	return nil
+}
+
+// workloadPower returns the power the workload draws now. Suspended workloads draw none, and
+// an enforced workload not evaluated since, or whose WorkloadOptimizer is gone, is estimated
+// at the power enforcement left it.
+func workloadPower(wo *kcloudv1alpha1.WorkloadOptimizer, enforced *kcloudv1alpha1.EnforcedWorkload) float64 {
+	if wo == nil {
+		if enforced == nil {
+			return 0
+		}
+		return enforcedPower(enforced)
+	}
+	if wo.Status.Phase == phaseSuspended {
+		return 0
+	}
+	evaluated := wo.Status.LastOptimizationTime
+	if enforced != nil && (evaluated == nil || !evaluated.After(enforced.Since.Time)) {
+		return enforcedPower(enforced)
+	}
+	return ptr.Deref(wo.Status.CurrentPower, 0)
+}
+
+// enforcedPower estimates the power of an enforced workload: none when paused, and its
+// power before throttling scaled by the share of replicas left
+func enforcedPower(enforced *kcloudv1alpha1.EnforcedWorkload) float64 {
+	if enforced.State == kcloudv1alpha1.EnforcedPaused {
+		return 0
+	}
+	var before, after int32
+	for _, target := range enforced.ScaledTargets {
+		if target.Replicas != nil {
+			before += *target.Replicas
+			after++
+		}
+	}
+	if before == 0 {
+		return enforced.Power
+	}
+	return enforced.Power * float64(after) / float64(before)
+}
+
+// SetupWithManager sets up the controller with the Manager. Policies are evaluated on their
+// interval, so status updates do not trigger a reconcile.
+func (r *PowerPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kcloudv1alpha1.PowerPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
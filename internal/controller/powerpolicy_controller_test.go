@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+var _ = Describe("PowerPolicy Controller", func() {
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		reconciler *PowerPolicyReconciler
+		policy     *kcloudv1alpha1.PowerPolicy
+	)
+
+	deployment := func(name string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(replicas)},
+		}
+	}
+	paused := func(name string, power float64) kcloudv1alpha1.EnforcedWorkload {
+		return kcloudv1alpha1.EnforcedWorkload{
+			Namespace:     "default",
+			Name:          name,
+			State:         kcloudv1alpha1.EnforcedPaused,
+			Power:         power,
+			Since:         metav1.Now(),
+			ScaledTargets: []kcloudv1alpha1.ScaledTarget{{Kind: "Deployment", Name: name, Replicas: ptr.To(int32(3))}},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(kcloudv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(autoscalingv2.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		policy = &kcloudv1alpha1.PowerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cap"},
+			Spec: kcloudv1alpha1.PowerPolicySpec{
+				MaxPowerUsage: 1000,
+				Enforcement:   &kcloudv1alpha1.PowerEnforcement{Action: kcloudv1alpha1.PowerEnforcementPause},
+			},
+		}
+	})
+
+	Context("When an enforced workload is gone and its release fails", func() {
+		It("Should keep it enforced and restore the workloads still covered", func() {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(deployment("gone", 0), deployment("api", 0)).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch,
+						opts ...client.PatchOption) error {
+						if obj.GetName() == "gone" {
+							return errors.New("transient error")
+						}
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).Build()
+			reconciler = &PowerPolicyReconciler{Client: fakeClient, Scheme: scheme}
+
+			api := kcloudv1alpha1.WorkloadOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+				Spec:       kcloudv1alpha1.WorkloadOptimizerSpec{Priority: 50},
+			}
+			policy.Status.Enforced = []kcloudv1alpha1.EnforcedWorkload{paused("gone", 200), paused("api", 300)}
+
+			Expect(func() {
+				reconciler.enforce(ctx, policy, []kcloudv1alpha1.WorkloadOptimizer{api}, 100)
+			}).NotTo(Panic())
+
+			Expect(policy.Status.Enforced).To(HaveLen(1))
+			Expect(policy.Status.Enforced[0].Name).To(Equal("gone"))
+
+			var restored appsv1.Deployment
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "api"}, &restored)).To(Succeed())
+			Expect(restored.Spec.Replicas).To(Equal(ptr.To(int32(3))))
+		})
+	})
+
+	Context("When a throttled workload is scaled by a HorizontalPodAutoscaler", func() {
+		It("Should pause it instead", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "web-1",
+				Namespace:       "default",
+				Labels:          map[string]string{"workload-optimizer": "web"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "web", Controller: ptr.To(true)}},
+			}}
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(4))},
+			}
+			autoscaler := &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "StatefulSet", Name: "web"},
+					MaxReplicas:    10,
+				},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(pod, statefulSet, autoscaler).Build()
+			reconciler = &PowerPolicyReconciler{Client: fakeClient, Scheme: scheme}
+			policy.Spec.Enforcement.Action = kcloudv1alpha1.PowerEnforcementThrottle
+
+			wo := &kcloudv1alpha1.WorkloadOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Status:     kcloudv1alpha1.WorkloadOptimizerStatus{CurrentPower: ptr.To(400.0)},
+			}
+			enforced, err := reconciler.capWorkload(ctx, policy, wo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(enforced.State).To(Equal(kcloudv1alpha1.EnforcedPaused))
+
+			var scaled appsv1.StatefulSet
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "web"}, &scaled)).To(Succeed())
+			Expect(scaled.Spec.Replicas).To(Equal(ptr.To(int32(0))))
+		})
+	})
+
+	It("Should estimate a gone workload at the power enforcement left it", func() {
+		enforced := paused("gone", 200)
+		enforced.State = kcloudv1alpha1.EnforcedThrottled
+		Expect(workloadPower(nil, &enforced)).To(BeNumerically("~", 200.0/3))
+		Expect(workloadPower(nil, nil)).To(BeZero())
+	})
+})
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// DefaultPricingTable is the name of the PricingTable the operator prices resources with
+// unless configured otherwise
+const DefaultPricingTable = "default"
+
+// PricingTableReconciler applies the PricingTable of the configured name to the operator's
+// pricing and marks it active. Other tables are marked inactive; deleting the active table
+// restores the default rates.
+type PricingTableReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Pricing receives the rates of the active table
+	Pricing *optimizer.TablePricing
+	// TableName names the active table; empty uses DefaultPricingTable
+	TableName string
+}
+
+//+kubebuilder:rbac:groups=kcloud.io,resources=pricingtables,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kcloud.io,resources=pricingtables/status,verbs=get;update;patch
+
+// Reconcile applies the active table's rates and records which table is active
+func (r *PricingTableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	active := req.Name == r.tableName()
+
+	var table kcloudv1alpha1.PricingTable
+	if err := r.Get(ctx, req.NamespacedName, &table); err != nil {
+		if client.IgnoreNotFound(err) == nil && active {
+			log.Info("Pricing table deleted, restoring default rates", "table", req.Name)
+			r.Pricing.Apply(nil)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if active {
+		r.Pricing.Apply(&table.Spec)
+		log.Info("Pricing table applied", "table", table.Name, "generation", table.Generation)
+	}
+	if table.Status.Active == active && (!active || table.Status.ObservedGeneration == table.Generation) {
+		return ctrl.Result{}, nil
+	}
+	now := metav1.Now()
+	table.Status.Active = active
+	table.Status.ObservedGeneration = table.Generation
+	table.Status.LastUpdated = &now
+	if err := r.Status().Update(ctx, &table); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update pricing table status: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// tableName returns the name of the active table
+func (r *PricingTableReconciler) tableName() string {
+	if r.TableName == "" {
+		return DefaultPricingTable
+	}
+	return r.TableName
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PricingTableReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kcloudv1alpha1.PricingTable{}).
+		Complete(r)
+}
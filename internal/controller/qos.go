@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// checkQoS recommends the request and limit shape of the workload's type and marks it
+// applied when the workload's CostPolicy auto-applies QoS recommendations at its
+// confidence, so the pod webhook gives it to new pods. A failed policy lookup keeps the
+// previous decision.
+func (r *WorkloadOptimizerReconciler) checkQoS(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) {
+	shape := optimizer.RecommendQoS(wo)
+	wasApplied := wo.Status.QoS != nil && wo.Status.QoS.Applied
+	applied := wasApplied
+	if policy, err := r.autoApplyPolicy(ctx, wo); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get auto-apply policy for QoS")
+	} else {
+		applied = autoApplies(policy, kcloudv1alpha1.AutoApplyQoS, shape.Confidence)
+	}
+
+	wo.Status.QoS = &kcloudv1alpha1.QoSRecommendation{
+		Class:            string(shape.Class),
+		Requests:         shape.Requests,
+		Limits:           shape.Limits,
+		CPUOvercommit:    shape.CPUOvercommit,
+		MemoryOvercommit: shape.MemoryOvercommit,
+		Confidence:       shape.Confidence,
+		Applied:          applied,
+		Reason:           shape.Reason,
+	}
+	if applied && !wasApplied && r.Recorder != nil {
+		r.Recorder.Eventf(wo, corev1.EventTypeNormal, "RecommendationApplied",
+			"New pods get the %s shape, cpu %s/%s and memory %s/%s, at confidence %.2f", shape.Class,
+			shape.Requests.CPU, shape.Limits.CPU, shape.Requests.Memory, shape.Limits.Memory, shape.Confidence)
+	}
+}
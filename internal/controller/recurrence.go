@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+// precomputePlacement computes the placement of the next recurring run during its lead
+// window, so the run's pods bind to a reserved node instead of waiting on optimization.
+// Runs are declared by the recurrence spec or predicted from a detected arrival pattern.
+// It returns how long to wait until the next lead window opens or, after a failed attempt,
+// until the next retry; zero if unknown.
+func (r *WorkloadOptimizerReconciler) precomputePlacement(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	state *optimizer.WorkloadState) time.Duration {
+	log := log.FromContext(ctx)
+	now := time.Now()
+	defer r.holdPlacement(ctx, wo, state.Pods, now)
+
+	recurrence := r.recurrence(wo)
+	if recurrence == nil {
+		wo.Status.PrecomputedPlacement = nil
+		wo.Status.PlacementAnalysis = nil
+		wo.Status.RelaxedConstraints = nil
+		wo.Status.CandidateNodes = nil
+		meta.RemoveStatusCondition(&wo.Status.Conditions, conditionSchedulingFailed)
+		return 0
+	}
+
+	next, due := optimizer.PrecomputeDue(recurrence, now)
+	placement := wo.Status.PrecomputedPlacement
+	if !due {
+		// Keep the placement of a run that just started until it lapses
+		if !optimizer.PlacementValid(placement, now) {
+			wo.Status.PrecomputedPlacement = nil
+		}
+		return next.Add(-optimizer.RecurrenceLeadTime(recurrence)).Sub(now)
+	}
+
+	if placement != nil && placement.RunTime.Time.Equal(next) {
+		return 0
+	}
+	if r.Scheduler == nil {
+		return 0
+	}
+
+	decision, err := r.Scheduler.ScheduleWorkload(ctx, wo, state.AvailableNodes)
+	if err != nil {
+		// Keep the analysis so users can resize the run instead of guessing at capacity
+		var capacityErr *scheduler.InsufficientCapacityError
+		if errors.As(err, &capacityErr) {
+			wo.Status.PlacementAnalysis = capacityErr.Analysis
+		}
+		wo.Status.CandidateNodes = nil
+		log.Error(err, "Failed to precompute placement for recurring run", "runTime", next)
+		return r.recordSchedulingFailure(ctx, wo, state.AvailableNodes, err)
+	}
+	r.recordSchedulingSuccess(wo, decision.SelectedNode)
+	wo.Status.PlacementAnalysis = nil
+	wo.Status.RelaxedConstraints = decision.RelaxedConstraints
+	wo.Status.CandidateNodes = candidateNodes(decision)
+
+	wo.Status.PrecomputedPlacement = &kcloudv1alpha1.PrecomputedPlacement{
+		RunTime:       metav1.NewTime(next),
+		NodeName:      decision.SelectedNode,
+		Score:         decision.Score,
+		EstimatedCost: decision.EstimatedCost,
+		ValidUntil:    metav1.NewTime(next.Add(optimizer.RecurrenceLeadTime(recurrence))),
+	}
+
+	log.Info("Precomputed placement for recurring run",
+		"runTime", next,
+		"node", decision.SelectedNode,
+		"score", decision.Score)
+	return 0
+}
+
+// holdPlacement keeps capacity reserved on the precomputed node until the run's pods run
+// there and count against the node themselves
+func (r *WorkloadOptimizerReconciler) holdPlacement(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	pods []corev1.Pod, now time.Time) {
+	if r.Scheduler == nil {
+		return
+	}
+	placement := wo.Status.PrecomputedPlacement
+	if !optimizer.PlacementValid(placement, now) ||
+		(!now.Before(placement.RunTime.Time) && podsBoundTo(pods, placement.NodeName)) {
+		r.Scheduler.ReleasePlacement(wo)
+		return
+	}
+	if err := r.Scheduler.ReservePlacement(wo, placement.NodeName); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to reserve capacity for recurring run", "node", placement.NodeName)
+	}
+}
+
+// podsBoundTo reports whether any of the pods is bound to the node and not finished
+func podsBoundTo(pods []corev1.Pod, nodeName string) bool {
+	for _, pod := range pods {
+		if pod.Spec.NodeName == nodeName && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// candidateNodes converts the scored candidates of a decision to their status form
+func candidateNodes(decision *scheduler.SchedulingDecision) []kcloudv1alpha1.CandidateNode {
+	if len(decision.Candidates) == 0 {
+		return nil
+	}
+	nodes := make([]kcloudv1alpha1.CandidateNode, 0, len(decision.Candidates))
+	for _, candidate := range decision.Candidates {
+		nodes = append(nodes, kcloudv1alpha1.CandidateNode{
+			Node:           candidate.Node,
+			Score:          candidate.Score,
+			Selected:       candidate.Node == decision.SelectedNode,
+			EstimatedCost:  candidate.EstimatedCost,
+			EstimatedPower: candidate.EstimatedPower,
+			Scores:         candidate.Contributions,
+		})
+	}
+	return nodes
+}
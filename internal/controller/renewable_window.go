@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// renewablePolicy returns the first PowerPolicy by name that covers the flexible workload
+// and prefers renewable energy, or nil when none does or no generation is forecast
+func (r *WorkloadOptimizerReconciler) renewablePolicy(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) *kcloudv1alpha1.PowerPolicy {
+	if r.RenewableForecast == nil || !optimizer.RenewableFlexible(wo) {
+		return nil
+	}
+	var policies kcloudv1alpha1.PowerPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list power policies")
+		return nil
+	}
+	sort.Slice(policies.Items, func(i, j int) bool {
+		return policies.Items[i].Name < policies.Items[j].Name
+	})
+
+	var namespaceLabels labels.Set
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		green := policy.Spec.GreenEnergyPolicy
+		if green == nil || !green.Enabled || green.Preference != kcloudv1alpha1.GreenEnergyPreferenceRenewable {
+			continue
+		}
+		if policy.Spec.NamespaceSelector != nil && namespaceLabels == nil {
+			var namespace corev1.Namespace
+			if err := r.Get(ctx, client.ObjectKey{Name: wo.Namespace}, &namespace); err != nil {
+				log.FromContext(ctx).Error(err, "Failed to get namespace")
+				return nil
+			}
+			namespaceLabels = labels.Set(namespace.Labels)
+		}
+		covered, err := optimizer.SelectorsCover(policy.Spec.NamespaceSelector, policy.Spec.WorkloadSelector,
+			namespaceLabels, labels.Set(wo.Labels))
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Skipping power policy", "powerPolicy", policy.Name)
+			continue
+		}
+		if covered {
+			return policy
+		}
+	}
+	return nil
+}
+
+// decideRenewableWindow decides whether the workload may start under the policy's renewable
+// preference. It waits until forecast generation covers the policy's minimum green share of
+// the power of its covered workloads, counting the workload's estimate, for at most the
+// policy's maxDelay. A workload whose pods already run is not held back.
+func (r *WorkloadOptimizerReconciler) decideRenewableWindow(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	policy *kcloudv1alpha1.PowerPolicy, previous *kcloudv1alpha1.TimeShiftStatus, now time.Time) optimizer.TimeShiftDecision {
+	pods, err := getAssociatedPods(ctx, r.Client, wo)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list pods of workload held for renewable supply")
+	}
+	released := previous != nil && previous.State == kcloudv1alpha1.TimeShiftStateRunning
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning ||
+			(released && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed) {
+			return optimizer.TimeShiftDecision{Run: true, Reason: "workload is already running"}
+		}
+	}
+
+	green := policy.Spec.GreenEnergyPolicy
+	maxDelay := optimizer.DefaultRenewableMaxDelay
+	if green.MaxDelay != nil {
+		maxDelay = green.MaxDelay.Duration
+	}
+	waitingSince := now
+	if previous != nil && previous.State == kcloudv1alpha1.TimeShiftStateWaiting {
+		waitingSince = previous.Since.Time
+	}
+	share := 1.0
+	if green.MinGreenEnergyPercentage != nil {
+		share = float64(*green.MinGreenEnergyPercentage) / 100
+	}
+	demand := math.Max(ptr.Deref(policy.Status.CurrentPowerUsage, 0), ptr.Deref(wo.Status.CurrentPower, 0))
+	decision := optimizer.DecideRenewableWindow(r.RenewableForecast, share*demand/1000,
+		optimizer.RenewableLatestStart(wo, waitingSince, maxDelay), now)
+	decision.Reason = fmt.Sprintf("%s under power policy %s", decision.Reason, policy.Name)
+	return decision
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+//+kubebuilder:rbac:groups=kcloud.io,resources=optimizationrecommendations,verbs=get;list;watch;create;update;patch
+
+// checkRightsizing refreshes the requests recommended from the workload's observed usage
+// once per rightsizing interval and publishes them as an OptimizationRecommendation.
+// Failures keep the previous recommendation; they never fail the reconcile.
+func (r *WorkloadOptimizerReconciler) checkRightsizing(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) {
+	if r.Rightsizer == nil {
+		return
+	}
+	now := time.Now()
+	if !r.Rightsizer.Due(wo.Status.Rightsizing, now) {
+		return
+	}
+
+	logger := log.FromContext(ctx)
+	pods, err := getAssociatedPods(ctx, r.Client, wo)
+	if err != nil {
+		logger.Error(err, "Failed to list pods for rightsizing")
+		return
+	}
+	recommendation, err := r.Rightsizer.Recommend(ctx, wo, pods)
+	if err != nil {
+		logger.Error(err, "Failed to compute rightsizing recommendation")
+		return
+	}
+	if recommendation == nil {
+		return
+	}
+
+	computedAt := metav1.NewTime(now)
+	wo.Status.Rightsizing = &kcloudv1alpha1.Rightsizing{
+		CPUP95:                  recommendation.Usage.CPUCores,
+		MemoryP95:               recommendation.Usage.MemoryGB,
+		GPUP95:                  recommendation.Usage.GPUs,
+		Recommended:             recommendation.Recommended,
+		EstimatedMonthlySavings: recommendation.MonthlySavings,
+		Window:                  recommendation.Window.String(),
+		Confidence:              recommendation.Confidence,
+		ComputedAt:              computedAt,
+	}
+	if err := r.publishRecommendation(ctx, wo, recommendation, computedAt); err != nil {
+		logger.Error(err, "Failed to publish rightsizing recommendation")
+	}
+}
+
+// publishRecommendation creates or updates the workload's rightsizing OptimizationRecommendation,
+// owned by the WorkloadOptimizer so it is removed with it
+func (r *WorkloadOptimizerReconciler) publishRecommendation(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	recommendation *optimizer.RightsizingRecommendation, generatedAt metav1.Time) error {
+	object := &kcloudv1alpha1.OptimizationRecommendation{
+		ObjectMeta: metav1.ObjectMeta{Name: wo.Name + "-rightsizing", Namespace: wo.Namespace},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, object, func() error {
+		object.Spec = kcloudv1alpha1.OptimizationRecommendationSpec{
+			WorkloadOptimizer:       wo.Name,
+			Type:                    kcloudv1alpha1.RecommendationTypeRightsizing,
+			Current:                 recommendation.Current,
+			Recommended:             recommendation.Recommended,
+			Window:                  recommendation.Window.String(),
+			EstimatedMonthlySavings: recommendation.MonthlySavings,
+			Currency:                string(r.Rightsizer.Currency.Currency()),
+			Confidence:              recommendation.Confidence,
+			GeneratedAt:             generatedAt,
+		}
+		return controllerutil.SetControllerReference(wo, object, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply optimization recommendation %s: %w", object.Name, err)
+	}
+
+	log.FromContext(ctx).V(1).Info("Rightsizing recommendation applied", "name", object.Name, "result", result,
+		"cpu", recommendation.Recommended.CPU, "memory", recommendation.Recommended.Memory,
+		"gpu", recommendation.Recommended.GPU, "monthlySavings", recommendation.MonthlySavings,
+		"confidence", recommendation.Confidence)
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// checkSavings refreshes the estimated savings of the optimization actions in effect on the
+// workload: an idle scale-down, from the idle detection, or else spot placement, from the
+// optimized cost on the assigned node. Rightsizing savings are kept as recorded when the
+// recommendation was applied.
+func (r *WorkloadOptimizerReconciler) checkSavings(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	result *optimizer.OptimizationResult) {
+	previous := wo.Status.Savings
+	var savings []kcloudv1alpha1.ActionSavings
+	if rightsizing := findSavings(previous, kcloudv1alpha1.SavingsActionRightsizing); rightsizing != nil {
+		savings = append(savings, *rightsizing)
+	}
+
+	if idle := wo.Status.Idle; idle != nil && idle.Action == idleActionScaledToZero {
+		savings = append(savings, kcloudv1alpha1.ActionSavings{
+			Action:        kcloudv1alpha1.SavingsActionIdleScaleDown,
+			HourlySavings: idle.EstimatedHourlySavings,
+			Since:         idle.Since,
+		})
+	} else if result.AssignedNode != "" {
+		var node corev1.Node
+		if err := r.Get(ctx, client.ObjectKey{Name: result.AssignedNode}, &node); err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.FromContext(ctx).Error(err, "Failed to get node for spot savings", "node", result.AssignedNode)
+			}
+		} else if hourly := r.Optimizer.Currency.Currency().Round(
+			optimizer.SpotSavings(&node, result.CostComponents.Compute)); hourly > 0 {
+			savings = append(savings, actionSavings(previous, kcloudv1alpha1.SavingsActionSpot, hourly))
+		}
+	}
+	wo.Status.Savings = savings
+}
+
+// recordRightsizingSavings adds what an applied rightsizing recommendation saves the
+// workload's running pods to its rightsizing savings, and stores them in its status
+func (r *WorkloadOptimizerReconciler) recordRightsizingSavings(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	rightsizing *kcloudv1alpha1.Rightsizing, replicas int) error {
+	hourly := rightsizing.EstimatedMonthlySavings * float64(replicas) / (30 * 24)
+	if hourly == 0 {
+		return nil
+	}
+	if previous := findSavings(wo.Status.Savings, kcloudv1alpha1.SavingsActionRightsizing); previous != nil {
+		hourly += previous.HourlySavings
+	}
+	entry := actionSavings(wo.Status.Savings, kcloudv1alpha1.SavingsActionRightsizing,
+		r.Optimizer.Currency.Currency().Round(hourly))
+
+	wo.Status.Savings = slices.DeleteFunc(wo.Status.Savings, func(savings kcloudv1alpha1.ActionSavings) bool {
+		return savings.Action == kcloudv1alpha1.SavingsActionRightsizing
+	})
+	wo.Status.Savings = append(wo.Status.Savings, entry)
+	return r.applyStatus(ctx, wo)
+}
+
+// actionSavings returns the action's savings, in effect since they were first recorded
+func actionSavings(previous []kcloudv1alpha1.ActionSavings, action string, hourly float64) kcloudv1alpha1.ActionSavings {
+	savings := kcloudv1alpha1.ActionSavings{Action: action, HourlySavings: hourly, Since: metav1.Now()}
+	if recorded := findSavings(previous, action); recorded != nil {
+		savings.Since = recorded.Since
+	}
+	return savings
+}
+
+// findSavings returns the savings of the action, or nil
+func findSavings(savings []kcloudv1alpha1.ActionSavings, action string) *kcloudv1alpha1.ActionSavings {
+	for i := range savings {
+		if savings[i].Action == action {
+			return &savings[i]
+		}
+	}
+	return nil
+}
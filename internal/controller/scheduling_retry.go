@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+const (
+	// conditionSchedulingFailed reports whether the last scheduling attempt found no node
+	conditionSchedulingFailed = "SchedulingFailed"
+
+	// DefaultSchedulingInitialBackoff is the delay before the first scheduling retry
+	DefaultSchedulingInitialBackoff = 10 * time.Second
+
+	// DefaultSchedulingMaxBackoff caps the delay between scheduling retries
+	DefaultSchedulingMaxBackoff = 5 * time.Minute
+
+	// maxRejectionEvents bounds the per-node events emitted for one failed attempt
+	maxRejectionEvents = 10
+)
+
+// recordSchedulingFailure emits events naming why each node was rejected, sets the
+// SchedulingFailed condition and returns how long to back off before the next attempt
+func (r *WorkloadOptimizerReconciler) recordSchedulingFailure(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	nodes []corev1.Node, err error) time.Duration {
+	workloadID := wo.Namespace + "/" + wo.Name
+
+	var retryAfter time.Duration
+	if r.SchedulingBackoff != nil {
+		r.SchedulingBackoff.Next(workloadID, time.Now())
+		retryAfter = r.SchedulingBackoff.Get(workloadID)
+	}
+
+	reason := "SchedulingError"
+	summary := err.Error()
+	var rejections []scheduler.NodeRejection
+	if errors.Is(err, scheduler.ErrNoSuitableNode) && r.Scheduler != nil {
+		reason = "NoSuitableNode"
+		rejections = r.Scheduler.ExplainRejections(wo, nodes)
+		if len(rejections) > 0 {
+			summary = scheduler.SummarizeRejections(rejections)
+		}
+	}
+	message := r.message(wo, messages.SchedulingFailed, map[string]any{
+		"Nodes":      len(nodes),
+		"Rejected":   len(rejections),
+		"Reasons":    summary,
+		"RetryAfter": retryAfter.String(),
+	})
+
+	if r.Recorder != nil {
+		for i, rejection := range rejections {
+			if i == maxRejectionEvents {
+				break
+			}
+			r.Recorder.Eventf(wo, corev1.EventTypeWarning, "NodeRejected", "Node %s: %s", rejection.Node, rejection.Reason)
+		}
+		r.Recorder.Event(wo, corev1.EventTypeWarning, "FailedScheduling", message)
+	}
+	meta.SetStatusCondition(&wo.Status.Conditions, metav1.Condition{
+		Type:    conditionSchedulingFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+
+	log.FromContext(ctx).Info("Scheduling failed, backing off",
+		"workload", workloadID,
+		"rejectedNodes", len(rejections),
+		"reasons", summary,
+		"retryAfter", retryAfter)
+	return retryAfter
+}
+
+// recordSchedulingSuccess resets the workload's backoff and clears a SchedulingFailed condition
+func (r *WorkloadOptimizerReconciler) recordSchedulingSuccess(wo *kcloudv1alpha1.WorkloadOptimizer, nodeName string) {
+	if r.SchedulingBackoff != nil {
+		r.SchedulingBackoff.Reset(wo.Namespace + "/" + wo.Name)
+	}
+	if !meta.IsStatusConditionTrue(wo.Status.Conditions, conditionSchedulingFailed) {
+		return
+	}
+
+	message := r.message(wo, messages.SchedulingRecovered, map[string]any{"Node": nodeName})
+	if r.Recorder != nil {
+		r.Recorder.Event(wo, corev1.EventTypeNormal, "Scheduled", message)
+	}
+	meta.SetStatusCondition(&wo.Status.Conditions, metav1.Condition{
+		Type:    conditionSchedulingFailed,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Scheduled",
+		Message: message,
+	})
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+const (
+	// statusFieldOwner is the field manager used for server-side apply of status
+	statusFieldOwner = "kcloud-workloadoptimizer-controller"
+
+	// statusHeartbeatInterval bounds how long an unchanged status may go without refreshing timestamps
+	statusHeartbeatInterval = time.Minute * 10
+)
+
+// statusChanged reports whether the desired status differs from the current one,
+// ignoring timestamps that would otherwise change on every reconcile
+func statusChanged(current, desired *kcloudv1alpha1.WorkloadOptimizerStatus) bool {
+	a := current.DeepCopy()
+	b := desired.DeepCopy()
+
+	a.LastOptimizationTime, b.LastOptimizationTime = nil, nil
+	for i := range a.Conditions {
+		a.Conditions[i].LastTransitionTime = metav1.Time{}
+	}
+	for i := range b.Conditions {
+		b.Conditions[i].LastTransitionTime = metav1.Time{}
+	}
+	for _, status := range []*kcloudv1alpha1.WorkloadOptimizerStatus{a, b} {
+		if status.PlacementAnalysis != nil {
+			status.PlacementAnalysis.AnalyzedAt = metav1.Time{}
+		}
+		if status.Simulation != nil {
+			status.Simulation.SimulatedAt = metav1.Time{}
+		}
+	}
+
+	return !equality.Semantic.DeepEqual(a, b)
+}
+
+// heartbeatDue reports whether the status timestamps are old enough to warrant a refresh
+func heartbeatDue(status *kcloudv1alpha1.WorkloadOptimizerStatus) bool {
+	if status.LastOptimizationTime == nil {
+		return true
+	}
+	return time.Since(status.LastOptimizationTime.Time) >= statusHeartbeatInterval
+}
+
+// applyStatus writes the status via server-side apply, owning only status fields. The
+// resourceVersion makes the apply fail with a conflict if the workload changed since it was read.
+func (r *WorkloadOptimizerReconciler) applyStatus(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) error {
+	applyObj := &kcloudv1alpha1.WorkloadOptimizer{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kcloudv1alpha1.GroupVersion.String(),
+			Kind:       "WorkloadOptimizer",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            wo.Name,
+			Namespace:       wo.Namespace,
+			ResourceVersion: wo.ResourceVersion,
+		},
+		Status: wo.Status,
+	}
+
+	if err := r.Status().Patch(ctx, applyObj, client.Apply,
+		client.FieldOwner(statusFieldOwner), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply status: %w", err)
+	}
+
+	log.FromContext(ctx).V(1).Info("Status applied", "phase", wo.Status.Phase)
+	return nil
+}
+
+// paretoFront converts the engine's Pareto options to their status form
+func paretoFront(options []optimizer.ParetoOption) []kcloudv1alpha1.ParetoOption {
+	if len(options) == 0 {
+		return nil
+	}
+	front := make([]kcloudv1alpha1.ParetoOption, 0, len(options))
+	for _, option := range options {
+		front = append(front, kcloudv1alpha1.ParetoOption{
+			Node:           option.Node,
+			Replicas:       option.Replicas,
+			EstimatedCost:  option.EstimatedCost,
+			EstimatedPower: option.EstimatedPower,
+			Performance:    option.Performance,
+			Score:          option.Score,
+			Selected:       option.Selected,
+		})
+	}
+	return front
+}
+
+// scoreBreakdown converts the engine's explanation of the optimization score
+func scoreBreakdown(breakdown optimizer.ScoreBreakdown) *kcloudv1alpha1.ScoreBreakdown {
+	return &kcloudv1alpha1.ScoreBreakdown{
+		Cost:              breakdown.Cost,
+		Power:             breakdown.Power,
+		Utilization:       breakdown.Utilization,
+		ConstraintPenalty: breakdown.ConstraintPenalty,
+		Weights: kcloudv1alpha1.ScoreWeights{
+			Cost:        breakdown.Weights.Cost,
+			Power:       breakdown.Weights.Power,
+			Utilization: breakdown.Weights.Utilization,
+		},
+	}
+}
+
+// slaPrediction converts the engine's SLA prediction, rounded so that small changes in
+// observed latency do not rewrite the status
+func slaPrediction(prediction *optimizer.SLAPrediction) *kcloudv1alpha1.SLAPrediction {
+	if prediction == nil {
+		return nil
+	}
+	status := &kcloudv1alpha1.SLAPrediction{
+		NodeClass:  prediction.NodeClass,
+		Replicas:   prediction.Replicas,
+		Capacity:   math.Round(prediction.Capacity),
+		Met:        prediction.Met,
+		Confidence: prediction.Confidence,
+		Message:    prediction.Reason,
+	}
+	if prediction.LatencyP99 > 0 {
+		status.LatencyP99 = &metav1.Duration{Duration: prediction.LatencyP99.Round(time.Millisecond)}
+	}
+	return status
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// checkTimeShift holds time-shifted workloads while the grid's carbon intensity or
+// electricity price is above their threshold, and releases them when it drops or when their
+// deadline would otherwise be at risk. A released workload runs for at least its minimum run
+// time before it is held again. Waiting workloads keep new pods gated; paused ones also have
+// their controllers scaled to zero until they run again, unless a do-not-disturb lease
+// holds them, and a pause that fails partway is retried. Without a time shift, flexible
+// workloads under a PowerPolicy preferring renewable energy wait for forecast on-site
+// generation the same way.
+func (r *WorkloadOptimizerReconciler) checkTimeShift(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) {
+	logger := log.FromContext(ctx)
+	workloadID := wo.Namespace + "/" + wo.Name
+	previous := wo.Status.TimeShift
+
+	var policy *kcloudv1alpha1.PowerPolicy
+	if wo.Spec.TimeShift == nil {
+		policy = r.renewablePolicy(ctx, wo)
+	}
+	if wo.Spec.TimeShift == nil && policy == nil {
+		if previous == nil {
+			return
+		}
+		if err := r.releaseTimeShift(ctx, wo, previous); err != nil {
+			logger.Error(err, "Failed to release workload after time shifting was removed", "workload", workloadID)
+			return
+		}
+		wo.Status.TimeShift = nil
+		return
+	}
+
+	now := time.Now()
+	var decision optimizer.TimeShiftDecision
+	if policy != nil {
+		decision = r.decideRenewableWindow(ctx, wo, policy, previous, now)
+	} else {
+		decision = optimizer.DecideTimeShift(wo, r.GridForecast, now)
+	}
+	if !decision.Run && previous != nil && previous.State == kcloudv1alpha1.TimeShiftStateRunning {
+		if minRun := optimizer.TimeShiftMinRunTime(wo); now.Sub(previous.Since.Time) < minRun {
+			decision.Run = true
+			decision.Reason = fmt.Sprintf("%s, but the workload has run for less than %s", decision.Reason, minRun)
+		}
+	}
+	state := kcloudv1alpha1.TimeShiftStateRunning
+	if !decision.Run {
+		state = kcloudv1alpha1.TimeShiftStateWaiting
+		if wo.Spec.TimeShift != nil && wo.Spec.TimeShift.Pause {
+			state = kcloudv1alpha1.TimeShiftStatePaused
+		}
+		// A do-not-disturb lease keeps running pods, so only new ones wait
+		if state == kcloudv1alpha1.TimeShiftStatePaused && optimizer.DoNotDisturbActive(wo, now) &&
+			(previous == nil || previous.State != kcloudv1alpha1.TimeShiftStatePaused) {
+			state = kcloudv1alpha1.TimeShiftStateWaiting
+			decision.Reason += "; not paused during the do-not-disturb lease"
+		}
+	}
+
+	status := &kcloudv1alpha1.TimeShiftStatus{
+		State:       state,
+		Since:       metav1.NewTime(now),
+		Reason:      decision.Reason,
+		Signal:      decision.Signal,
+		LatestStart: optionalTime(decision.LatestStart),
+		NextWindow:  optionalTime(decision.NextWindow),
+	}
+	if policy != nil {
+		status.Policy = policy.Name
+	}
+	if previous != nil {
+		status.PausedTargets = previous.PausedTargets
+		if previous.State == state {
+			status.Since = previous.Since
+		}
+	}
+
+	switch state {
+	case kcloudv1alpha1.TimeShiftStateRunning:
+		if err := r.releaseTimeShift(ctx, wo, status); err != nil {
+			logger.Error(err, "Failed to release time-shifted workload", "workload", workloadID)
+			return
+		}
+		status.PausedTargets = nil
+	case kcloudv1alpha1.TimeShiftStatePaused:
+		if previous == nil || previous.State != kcloudv1alpha1.TimeShiftStatePaused {
+			pods, err := getAssociatedPods(ctx, r.Client, wo)
+			if err != nil {
+				logger.Error(err, "Failed to list pods of time-shifted workload", "workload", workloadID)
+				return
+			}
+			targets, err := scaleDown(ctx, r.Client, wo.Namespace, pods, 0)
+			status.PausedTargets = mergeScaledTargets(status.PausedTargets, targets)
+			if err != nil {
+				// Record what was paused, but not the pause, so the remaining targets are retried
+				logger.Error(err, "Failed to pause time-shifted workload", "workload", workloadID)
+				status.State = kcloudv1alpha1.TimeShiftStateWaiting
+				if previous == nil || previous.State != status.State {
+					status.Since = metav1.NewTime(now)
+				}
+				wo.Status.TimeShift = status
+				return
+			}
+		}
+		wo.Status.Phase = phaseSuspended
+	}
+
+	if previous == nil || previous.State != state {
+		logger.Info("Time-shifted workload changed state", "workload", workloadID,
+			"state", state, "reason", decision.Reason)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(wo, corev1.EventTypeNormal, "TimeShift"+state, "%s: %s", state, decision.Reason)
+		}
+	}
+	wo.Status.TimeShift = status
+}
+
+// mergeScaledTargets adds the targets not recorded yet, keeping the replicas first recorded
+func mergeScaledTargets(recorded, targets []kcloudv1alpha1.ScaledTarget) []kcloudv1alpha1.ScaledTarget {
+	for _, target := range targets {
+		if !slices.ContainsFunc(recorded, func(t kcloudv1alpha1.ScaledTarget) bool {
+			return t.Kind == target.Kind && t.Name == target.Name
+		}) {
+			recorded = append(recorded, target)
+		}
+	}
+	return recorded
+}
+
+// releaseTimeShift lets the workload run: it restores the controllers it paused and removes
+// the time-shift gate from its pods
+func (r *WorkloadOptimizerReconciler) releaseTimeShift(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	status *kcloudv1alpha1.TimeShiftStatus) error {
+	if err := restoreScaled(ctx, r.Client, wo.Namespace, status.PausedTargets); err != nil {
+		return err
+	}
+	pods, err := getAssociatedPods(ctx, r.Client, wo)
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range pods {
+		pod := &pods[i]
+		gates := make([]corev1.PodSchedulingGate, 0, len(pod.Spec.SchedulingGates))
+		for _, gate := range pod.Spec.SchedulingGates {
+			if gate.Name != optimizer.TimeShiftGate {
+				gates = append(gates, gate)
+			}
+		}
+		if len(gates) == len(pod.Spec.SchedulingGates) {
+			continue
+		}
+		original := pod.DeepCopy()
+		pod.Spec.SchedulingGates = gates
+		if err := r.Patch(ctx, pod, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to remove time-shift gate from pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// restoreScaled scales the targets back to their recorded replicas, or resumes them for Jobs
+func restoreScaled(ctx context.Context, c client.Client, namespace string,
+	targets []kcloudv1alpha1.ScaledTarget) error {
+	for _, target := range targets {
+		var object client.Object
+		switch target.Kind {
+		case "Deployment":
+			object = &appsv1.Deployment{}
+		case "StatefulSet":
+			object = &appsv1.StatefulSet{}
+		case "ReplicaSet":
+			object = &appsv1.ReplicaSet{}
+		case "Job":
+			object = &batchv1.Job{}
+		default:
+			continue
+		}
+		key := client.ObjectKey{Namespace: namespace, Name: target.Name}
+		if err := c.Get(ctx, key, object); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return fmt.Errorf("failed to get %s %s: %w", target.Kind, key, err)
+		}
+		original := object.DeepCopyObject().(client.Object)
+		replicas := ptr.To(ptr.Deref(target.Replicas, 1))
+		switch o := object.(type) {
+		case *appsv1.Deployment:
+			o.Spec.Replicas = replicas
+		case *appsv1.StatefulSet:
+			o.Spec.Replicas = replicas
+		case *appsv1.ReplicaSet:
+			o.Spec.Replicas = replicas
+		case *batchv1.Job:
+			o.Spec.Suspend = ptr.To(false)
+		}
+		if err := c.Patch(ctx, object, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to restore %s %s: %w", target.Kind, key, err)
+		}
+	}
+	return nil
+}
+
+// optionalTime converts an optional time to its API form
+func optionalTime(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	return &metav1.Time{Time: *t}
+}
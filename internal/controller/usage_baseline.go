@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/notify"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// conditionUsageWithinBaseline reports whether cost and power stay near the workload's own baseline
+const conditionUsageWithinBaseline = "UsageWithinBaseline"
+
+// checkUsageBaseline compares the workload's usage with its trailing baseline and
+// reflects the outcome in a status condition and metrics
+func (r *WorkloadOptimizerReconciler) checkUsageBaseline(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	result *optimizer.OptimizationResult) {
+	if r.UsageHistory == nil {
+		return
+	}
+
+	workloadID := wo.Namespace + "/" + wo.Name
+	deviation := r.UsageHistory.Observe(workloadID, result.EstimatedCost, result.EstimatedPower)
+
+	if r.Metrics != nil && deviation.Samples > 0 {
+		r.Metrics.RecordWorkloadBaselineDeviation(wo.Namespace, wo.Name, "cost", deviation.CostDeviation)
+		r.Metrics.RecordWorkloadBaselineDeviation(wo.Namespace, wo.Name, "power", deviation.PowerDeviation)
+	}
+
+	condition := metav1.Condition{
+		Type:    conditionUsageWithinBaseline,
+		Status:  metav1.ConditionTrue,
+		Reason:  "WithinBaseline",
+		Message: r.message(wo, messages.UsageWithinBaseline, nil),
+	}
+	switch {
+	case deviation.Samples < r.UsageHistory.MinSamples:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "CollectingBaseline"
+		condition.Message = r.message(wo, messages.UsageCollectingBaseline, nil)
+	case deviation.CostExceeded:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "CostAboveBaseline"
+		condition.Message = r.message(wo, messages.UsageCostAboveBaseline, map[string]any{
+			"Cost": result.EstimatedCost, "Percent": deviation.CostDeviation * 100, "Baseline": deviation.BaselineCost,
+		})
+	case deviation.PowerExceeded:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PowerAboveBaseline"
+		condition.Message = r.message(wo, messages.UsagePowerAboveBaseline, map[string]any{
+			"Power": result.EstimatedPower, "Percent": deviation.PowerDeviation * 100, "Baseline": deviation.BaselinePower,
+		})
+	}
+
+	if deviation.Exceeded() {
+		log.FromContext(ctx).Info("Workload usage deviates from its baseline",
+			"costDeviation", deviation.CostDeviation,
+			"powerDeviation", deviation.PowerDeviation,
+			"baselineCost", deviation.BaselineCost,
+			"baselinePower", deviation.BaselinePower,
+			"samples", deviation.Samples)
+	}
+
+	// Alert once when the workload leaves its baseline, not on every reconcile
+	previous := meta.FindStatusCondition(wo.Status.Conditions, conditionUsageWithinBaseline)
+	if condition.Status == metav1.ConditionFalse && (previous == nil || previous.Status != metav1.ConditionFalse) {
+		r.notifyBaselineDeviation(ctx, wo, condition, deviation)
+	}
+
+	meta.SetStatusCondition(&wo.Status.Conditions, condition)
+}
+
+// notifyBaselineDeviation alerts the workload's team, which is its namespace, that usage
+// left the baseline; deviations of twice the allowed amount are critical
+func (r *WorkloadOptimizerReconciler) notifyBaselineDeviation(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	condition metav1.Condition, deviation optimizer.UsageDeviation) {
+	if r.Notifier == nil {
+		return
+	}
+
+	category := notify.CategoryCost
+	ratio := deviation.CostDeviation
+	if !deviation.CostExceeded {
+		category = notify.CategoryPower
+		ratio = deviation.PowerDeviation
+	}
+
+	severity := notify.SeverityWarning
+	if ratio >= 2*r.UsageHistory.MaxDeviation {
+		severity = notify.SeverityCritical
+	}
+
+	r.Notifier.Notify(ctx, notify.Alert{
+		Team:     wo.Namespace,
+		Category: category,
+		Severity: severity,
+		Title:    r.message(wo, messages.AlertUsageAboveBaseline, map[string]any{"Category": category}),
+		Message:  condition.Message,
+		Source:   wo.Namespace + "/" + wo.Name,
+	})
+}
@@ -19,18 +19,29 @@ package controller
 import (
 	"context"
 	"fmt"
-	"time"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/adaptive"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/journal"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
 	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/metrics"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/notify"
 	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
 	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
 )
@@ -42,13 +53,60 @@ type WorkloadOptimizerReconciler struct {
 	Optimizer *optimizer.Engine
 	Scheduler *scheduler.Scheduler
 	Metrics   *metrics.MetricsCollector
+	Journal   *journal.Journal
+	Tuner     *adaptive.Tuner
+	// UsageHistory tracks per-workload usage baselines; nil disables baseline alerts
+	UsageHistory *optimizer.UsageHistory
+	// Recorder emits events about scheduling failures; nil disables events
+	Recorder record.EventRecorder
+	// SchedulingBackoff spaces out retries of failed scheduling attempts per workload;
+	// nil retries on the regular requeue interval
+	SchedulingBackoff *flowcontrol.Backoff
+
+	// GPUPacker packs low-utilization inference workloads onto shared GPUs; nil disables packing
+	GPUPacker *scheduler.GPUPacker
+
+	// Notifier delivers alerts to team channels; nil disables notifications
+	Notifier *notify.DigestScheduler
+
+	// Messages renders condition and alert text; nil uses the built-in English catalog
+	Messages *messages.Catalog
+
+	// Rightsizer recommends requests from the workload's observed usage; nil disables rightsizing
+	Rightsizer *optimizer.Rightsizer
+
+	// IdleDetector flags workloads that stayed near zero utilization; nil disables idle detection
+	IdleDetector *optimizer.IdleDetector
+
+	// ReserveDetectedPatterns reserves a node ahead of runs predicted from daily or weekly
+	// arrival patterns, as for a declared recurrence
+	ReserveDetectedPatterns bool
+
+	// SLA learns the latency of inference workloads with SLA targets from their running pods;
+	// nil leaves SLA predictions to the default profiles
+	SLA *optimizer.SLAModel
+
+	// Transmit reports the traffic workloads' pods send, sizing internet egress that is not
+	// declared; nil leaves it unpriced
+	Transmit optimizer.TransmitSource
+
+	// GridForecast is the grid's carbon intensity and electricity price that time-shifted
+	// workloads wait out; nil runs them at once
+	GridForecast *optimizer.GridForecast
+
+	// RenewableForecast is the on-site renewable generation that flexible workloads under a
+	// PowerPolicy preferring renewable energy wait for; nil runs them at once
+	RenewableForecast *optimizer.RenewableForecast
 }
 
 //+kubebuilder:rbac:groups=kcloud.io,resources=workloadoptimizers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=kcloud.io,resources=workloadoptimizers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kcloud.io,resources=workloadoptimizers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=kcloud.io,resources=nodepowerprofiles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kcloud.io,resources=powerpolicies,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -73,6 +131,7 @@ func (r *WorkloadOptimizerReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		// Record deletion metrics
 		if r.Metrics != nil {
 			r.Metrics.RecordWorkloadOptimizerDeleted(wo.Namespace, wo.Name, wo.Spec.WorkloadType)
+			r.Metrics.ResetPodCosts(wo.Namespace, wo.Name)
 		}
 		return r.handleDeletion(ctx, &wo)
 	}
@@ -82,6 +141,18 @@ func (r *WorkloadOptimizerReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Only report what would happen for workloads that ask for a simulation
+	if optimizer.DryRun(&wo) {
+		return r.reconcileDryRun(ctx, &wo)
+	}
+
+	// Workloads optimized once are left alone until their spec changes
+	interval := r.optimizationInterval(ctx, &wo)
+	if optimizedOnce(&wo, interval) {
+		log.V(1).Info("Workload already optimized for its current spec", "generation", wo.Generation)
+		return ctrl.Result{}, nil
+	}
+
 	// Analyze current state
 	currentState, err := r.analyzeCurrentState(ctx, &wo)
 	if err != nil {
@@ -96,12 +167,24 @@ func (r *WorkloadOptimizerReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Learn when the workload's runs recur
+	r.checkArrivalPattern(ctx, &wo, currentState.Pods)
+
+	// Reserve a node ahead of the next recurring run
+	untilLeadWindow := r.precomputePlacement(ctx, &wo, currentState)
+
 	// Update status
-	if err := r.updateStatus(ctx, &wo, optimizationResult); err != nil {
+	if err := r.updateStatus(ctx, &wo, currentState, optimizationResult); err != nil {
 		log.Error(err, "Failed to update status")
 		return ctrl.Result{}, err
 	}
 
+	// Publish each running pod's cost on the pod
+	r.annotatePodCosts(ctx, currentState.Pods, optimizationResult)
+
+	// Apply recommendations confident enough for the covering cost policy
+	r.applyRecommendations(ctx, &wo, optimizationResult, currentState.Pods)
+
 	// Record metrics
 	if r.Metrics != nil {
 		r.Metrics.RecordWorkloadOptimizerCreated(wo.Namespace, wo.Name, wo.Spec.WorkloadType)
@@ -112,15 +195,25 @@ func (r *WorkloadOptimizerReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		if optimizationResult.EstimatedCost > 0 {
 			r.Metrics.RecordWorkloadOptimizerCost(wo.Namespace, wo.Name, wo.Spec.WorkloadType, optimizationResult.EstimatedCost)
 		}
+		r.Metrics.ResetPodCosts(wo.Namespace, wo.Name)
+		for _, pod := range optimizationResult.PodCosts {
+			r.Metrics.RecordPodCost(wo.Namespace, wo.Name, pod.Name, pod.Node, pod.Cost)
+		}
 		if optimizationResult.EstimatedPower > 0 {
 			r.Metrics.RecordWorkloadOptimizerPower(wo.Namespace, wo.Name, wo.Spec.WorkloadType, optimizationResult.EstimatedPower)
 		}
 	}
 
-	// Set requeue time based on optimization result
-	requeueAfter := time.Minute * 5
-	if optimizationResult.RequiresRescheduling {
-		requeueAfter = time.Minute * 1
+	// Set requeue time based on the workload's interval and the optimization result
+	requeueAfter := interval.Every
+	if optimizationResult.RequiresRescheduling && (interval.Once || requeueAfter > reschedulingRetryInterval) {
+		requeueAfter = reschedulingRetryInterval
+	}
+	if untilLeadWindow > 0 && untilLeadWindow < requeueAfter {
+		requeueAfter = untilLeadWindow
+	}
+	if untilLeaseExpiry := doNotDisturbRemaining(&wo); untilLeaseExpiry > 0 && untilLeaseExpiry < requeueAfter {
+		requeueAfter = untilLeaseExpiry
 	}
 
 	log.Info("Reconciliation completed successfully",
@@ -136,7 +229,7 @@ func (r *WorkloadOptimizerReconciler) analyzeCurrentState(ctx context.Context, w
 	log := log.FromContext(ctx)
 
 	// Get pods associated with this workload
-	pods, err := r.getAssociatedPods(ctx, wo)
+	pods, err := getAssociatedPods(ctx, r.Client, wo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get associated pods: %w", err)
 	}
@@ -147,17 +240,161 @@ func (r *WorkloadOptimizerReconciler) analyzeCurrentState(ctx context.Context, w
 		return nil, fmt.Errorf("failed to get available nodes: %w", err)
 	}
 
+	// Get node pool energy profiles
+	profiles, err := r.refreshEnergyProfiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node power profiles: %w", err)
+	}
+
+	// Get the cost policies covering the workload, whose prices replace the defaults
+	policies, err := r.coveringCostPolicies(ctx, wo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost policies: %w", err)
+	}
+
+	// Refine the SLA model from the latency the workload's pods served
+	if r.SLA != nil {
+		if err := r.SLA.Refresh(ctx, wo, pods, nodes); err != nil {
+			log.Error(err, "Failed to observe workload latency")
+		}
+	}
+
+	// Get the volumes and traffic the workload's storage and network cost are priced from
+	claims, err := r.podClaims(ctx, pods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get persistent volume claims: %w", err)
+	}
+
 	log.Info("Current state analyzed",
 		"podsCount", len(pods),
-		"nodesCount", len(nodes))
+		"nodesCount", len(nodes),
+		"energyProfiles", len(profiles),
+		"costPolicies", len(policies))
 
 	return &optimizer.WorkloadState{
 		WorkloadOptimizer: wo,
 		Pods:              pods,
 		AvailableNodes:    nodes,
+		PlacementNodes:    scheduler.SchedulableNodes(nodes),
+		EnergyProfiles:    profiles,
+		ReservedNode:      r.reservedNode(wo),
+		CostPolicy:        pricingPolicy(policies),
+		Claims:            claims,
+		TransmitGBPerHour: r.observedTransmit(ctx, wo, pods),
 	}, nil
 }
 
+// refreshEnergyProfiles loads node pool energy profiles, ordered by name, and shares them
+// with the scheduler together with the green energy policies that select pools by renewable
+// fraction
+func (r *WorkloadOptimizerReconciler) refreshEnergyProfiles(ctx context.Context) ([]kcloudv1alpha1.NodePowerProfile, error) {
+	var profiles kcloudv1alpha1.NodePowerProfileList
+	if err := r.List(ctx, &profiles); err != nil {
+		return nil, err
+	}
+	sort.Slice(profiles.Items, func(i, j int) bool {
+		return profiles.Items[i].Name < profiles.Items[j].Name
+	})
+
+	var policies kcloudv1alpha1.PowerPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		return nil, err
+	}
+
+	// Each policy sets the threshold for, and holds standby headroom for, the workloads it covers
+	var greenPools []scheduler.GreenPoolPolicy
+	var standby []scheduler.StandbyPolicy
+	selectsNamespaces := false
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		green := policy.Spec.GreenEnergyPolicy
+		selectsPools := green != nil && green.Enabled && green.SelectPoolsByRenewableFraction && green.MinGreenEnergyPercentage != nil
+		if !selectsPools && len(policy.Spec.StandbyReservations) == 0 {
+			continue
+		}
+		scope, err := powerPolicyScope(policy)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Skipping power policy with an invalid selector", "powerPolicy", policy.Name)
+			continue
+		}
+		selectsNamespaces = selectsNamespaces || scope.NamespaceSelector != nil
+		if selectsPools {
+			greenPools = append(greenPools, scheduler.GreenPoolPolicy{
+				Name:                 policy.Name,
+				WorkloadScope:        scope,
+				MinRenewableFraction: float64(*green.MinGreenEnergyPercentage) / 100,
+			})
+		}
+		if len(policy.Spec.StandbyReservations) > 0 {
+			reservations, err := standbyReservations(policy)
+			if err != nil {
+				log.FromContext(ctx).Error(err, "Skipping standby reservations of power policy", "powerPolicy", policy.Name)
+				continue
+			}
+			standby = append(standby, scheduler.StandbyPolicy{
+				Name:          policy.Name,
+				WorkloadScope: scope,
+				Reservations:  reservations,
+			})
+		}
+	}
+
+	var namespaceLabels map[string]labels.Set
+	if selectsNamespaces {
+		var namespaces corev1.NamespaceList
+		if err := r.List(ctx, &namespaces); err != nil {
+			return nil, err
+		}
+		namespaceLabels = make(map[string]labels.Set, len(namespaces.Items))
+		for _, namespace := range namespaces.Items {
+			namespaceLabels[namespace.Name] = labels.Set(namespace.Labels)
+		}
+	}
+
+	if r.Scheduler != nil {
+		r.Scheduler.SetNodePowerProfiles(profiles.Items, greenPools, namespaceLabels)
+		if err := r.Scheduler.SetStandbyPolicies(standby); err != nil {
+			return nil, err
+		}
+	}
+
+	return profiles.Items, nil
+}
+
+// standbyReservations converts and validates the standby reservations of a power policy
+func standbyReservations(policy *kcloudv1alpha1.PowerPolicy) ([]scheduler.StandbyReservation, error) {
+	reservations := make([]scheduler.StandbyReservation, 0, len(policy.Spec.StandbyReservations))
+	for _, spec := range policy.Spec.StandbyReservations {
+		reservation := scheduler.StandbyReservation{
+			Name:         spec.Name,
+			NodeSelector: spec.NodeSelector,
+			Headroom:     spec.Headroom,
+		}
+		if err := reservation.Validate(); err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, nil
+}
+
+// powerPolicyScope converts the selectors of a power policy
+func powerPolicyScope(policy *kcloudv1alpha1.PowerPolicy) (scheduler.WorkloadScope, error) {
+	var scope scheduler.WorkloadScope
+	var err error
+	if policy.Spec.NamespaceSelector != nil {
+		if scope.NamespaceSelector, err = metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector); err != nil {
+			return scope, fmt.Errorf("invalid namespace selector: %w", err)
+		}
+	}
+	if policy.Spec.WorkloadSelector != nil {
+		if scope.WorkloadSelector, err = metav1.LabelSelectorAsSelector(policy.Spec.WorkloadSelector); err != nil {
+			return scope, fmt.Errorf("invalid workload selector: %w", err)
+		}
+	}
+	return scope, nil
+}
+
 // performOptimization performs optimization using the optimizer engine
 func (r *WorkloadOptimizerReconciler) performOptimization(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, state *optimizer.WorkloadState) (*optimizer.OptimizationResult, error) {
 	log := log.FromContext(ctx)
@@ -170,36 +407,113 @@ func (r *WorkloadOptimizerReconciler) performOptimization(ctx context.Context, w
 		"estimatedCost", result.EstimatedCost,
 		"estimatedPower", result.EstimatedPower,
 		"requiresRescheduling", result.RequiresRescheduling)
+	if result.DeschedulingExemption != "" {
+		log.Info("Rescheduling suppressed", "explanation", result.DeschedulingExemption)
+	}
 
 	return result, nil
 }
 
-// updateStatus updates the status of the WorkloadOptimizer
-func (r *WorkloadOptimizerReconciler) updateStatus(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, result *optimizer.OptimizationResult) error {
+// updateStatus updates the status of the WorkloadOptimizer from the optimization of state;
+// a nil state leaves the estimate as is if another writer bound the workload meanwhile
+func (r *WorkloadOptimizerReconciler) updateStatus(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	state *optimizer.WorkloadState, result *optimizer.OptimizationResult) error {
 	log := log.FromContext(ctx)
 
+	previous := wo.Status.DeepCopy()
+
 	// Update status fields
-	now := metav1.Now()
-	wo.Status.Phase = r.determinePhase(result)
-	wo.Status.CurrentCost = &result.EstimatedCost
-	wo.Status.CurrentPower = &result.EstimatedPower
-	wo.Status.AssignedNode = &result.AssignedNode
-	wo.Status.OptimizationScore = &result.Score
-	wo.Status.LastOptimizationTime = &now
-	wo.Status.Replicas = &result.RecommendedReplicas
+	r.setOptimizationResult(&wo.Status, result)
+	wo.Status.Simulation = nil
+	wo.Status.ObservedGeneration = wo.Generation
 
 	// Update conditions
 	r.updateConditions(wo, result)
+	r.checkUsageBaseline(ctx, wo, result)
+	r.checkCarbonBudget(ctx, wo, previous)
+	r.checkDoNotDisturb(ctx, wo)
+	r.checkGPUPacking(ctx, wo, result)
+	r.checkRightsizing(ctx, wo)
+	r.checkQoS(ctx, wo)
+	r.checkCPUFrequency(ctx, wo)
+	r.checkIdle(ctx, wo)
+	r.checkTimeShift(ctx, wo)
+	r.checkPowerCap(ctx, wo)
+	r.checkSavings(ctx, wo, result)
+
+	// Skip the write entirely when nothing meaningful changed
+	if !statusChanged(previous, &wo.Status) && !heartbeatDue(previous) {
+		log.V(1).Info("Status unchanged, skipping update", "phase", wo.Status.Phase)
+		return nil
+	}
 
-	// Update the status
-	if err := r.Status().Update(ctx, wo); err != nil {
-		return fmt.Errorf("failed to update status: %w", err)
+	now := metav1.Now()
+	wo.Status.LastOptimizationTime = &now
+
+	// Apply the status, deferring to a node bound concurrently by another writer
+	var reestimate func(status *kcloudv1alpha1.WorkloadOptimizerStatus, node string)
+	if state != nil {
+		reestimate = func(status *kcloudv1alpha1.WorkloadOptimizerStatus, node string) {
+			// Price the workload on the adopted node; running pods are still priced where they run
+			adopted := *state
+			adopted.ReservedNode = node
+			r.setOptimizationResult(status, r.Optimizer.Optimize(ctx, &adopted))
+		}
+	}
+	applied, err := r.commitStatus(ctx, wo, previous.AssignedNode, reestimate)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		log.V(1).Info("Status already stored by another writer", "phase", wo.Status.Phase)
+		return nil
 	}
 
+	r.journalDecision(ctx, wo)
+
 	log.Info("Status updated successfully", "phase", wo.Status.Phase)
 	return nil
 }
 
+// setOptimizationResult sets the status fields derived from an optimization result
+func (r *WorkloadOptimizerReconciler) setOptimizationResult(status *kcloudv1alpha1.WorkloadOptimizerStatus,
+	result *optimizer.OptimizationResult) {
+	status.Phase = r.determinePhase(result)
+	status.CurrentCost = &result.EstimatedCost
+	status.Currency = string(r.Optimizer.Currency.Currency())
+	status.CurrentPower = &result.EstimatedPower
+	status.FacilityPower = &result.FacilityPower
+	status.GPUPower = nil
+	if result.GPUUsage != nil {
+		status.GPUPower = &result.GPUUsage.Power
+	}
+	status.AssignedNode = &result.AssignedNode
+	status.OptimizationScore = &result.Score
+	status.ScoreBreakdown = scoreBreakdown(result.ScoreBreakdown)
+	status.CostBreakdown = costBreakdown(result.CostComponents)
+	status.PodCosts = podCosts(result.PodCosts)
+	status.Replicas = &result.RecommendedReplicas
+	status.RenewableEnergyShare = &result.RenewableShare
+	status.CarbonFootprint = &result.CarbonFootprint
+	status.ParetoFront = paretoFront(result.ParetoFront)
+	status.SLA = slaPrediction(result.SLA)
+}
+
+// journalDecision records the applied optimization decision in the decision journal
+func (r *WorkloadOptimizerReconciler) journalDecision(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) {
+	if r.Journal == nil {
+		return
+	}
+
+	if _, err := r.Journal.Append("optimization", map[string]interface{}{
+		"namespace": wo.Namespace,
+		"name":      wo.Name,
+		"status":    wo.Status,
+	}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to journal optimization decision")
+	}
+}
+
 // determinePhase determines the current phase based on optimization result
 func (r *WorkloadOptimizerReconciler) determinePhase(result *optimizer.OptimizationResult) string {
 	if result.Score >= 0.8 {
@@ -213,44 +527,38 @@ func (r *WorkloadOptimizerReconciler) determinePhase(result *optimizer.Optimizat
 
 // updateConditions updates the conditions based on optimization result
 func (r *WorkloadOptimizerReconciler) updateConditions(wo *kcloudv1alpha1.WorkloadOptimizer, result *optimizer.OptimizationResult) {
-	now := metav1.Now()
-
-	// Update or add conditions
 	conditions := []metav1.Condition{
 		{
-			Type:               "Available",
-			Status:             metav1.ConditionTrue,
-			Reason:             "OptimizationCompleted",
-			Message:            fmt.Sprintf("Workload optimization completed with score %.2f", result.Score),
-			LastTransitionTime: now,
+			Type:    "Available",
+			Status:  metav1.ConditionTrue,
+			Reason:  "OptimizationCompleted",
+			Message: r.message(wo, messages.ConditionOptimized, map[string]any{"Score": result.Score}),
 		},
 		{
-			Type:               "CostOptimized",
-			Status:             metav1.ConditionTrue,
-			Reason:             "WithinBudget",
-			Message:            fmt.Sprintf("Current cost $%.2f/hour is within constraints", result.EstimatedCost),
-			LastTransitionTime: now,
+			Type:    "CostOptimized",
+			Status:  metav1.ConditionTrue,
+			Reason:  "WithinBudget",
+			Message: r.message(wo, messages.ConditionCostWithinBudget, map[string]any{"Cost": result.EstimatedCost}),
 		},
 		{
-			Type:               "PowerOptimized",
-			Status:             metav1.ConditionTrue,
-			Reason:             "WithinLimits",
-			Message:            fmt.Sprintf("Current power %.2fW is within constraints", result.EstimatedPower),
-			LastTransitionTime: now,
+			Type:    "PowerOptimized",
+			Status:  metav1.ConditionTrue,
+			Reason:  "WithinLimits",
+			Message: r.message(wo, messages.ConditionPowerWithinLimit, map[string]any{"Power": result.EstimatedPower}),
 		},
 	}
 
-	// Update conditions in the status
+	// SetStatusCondition only bumps LastTransitionTime when the status flips
 	for _, condition := range conditions {
 		metav1.SetMetaDataAnnotation(&wo.ObjectMeta, fmt.Sprintf("condition-%s", condition.Type), condition.Message)
+		meta.SetStatusCondition(&wo.Status.Conditions, condition)
 	}
-	wo.Status.Conditions = conditions
 }
 
 // getAssociatedPods gets pods associated with this workload optimizer
-func (r *WorkloadOptimizerReconciler) getAssociatedPods(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) ([]corev1.Pod, error) {
+func getAssociatedPods(ctx context.Context, c client.Client, wo *kcloudv1alpha1.WorkloadOptimizer) ([]corev1.Pod, error) {
 	var pods corev1.PodList
-	err := r.List(ctx, &pods, client.InNamespace(wo.Namespace))
+	err := c.List(ctx, &pods, client.InNamespace(wo.Namespace))
 	if err != nil {
 		return nil, err
 	}
@@ -258,9 +566,11 @@ func (r *WorkloadOptimizerReconciler) getAssociatedPods(ctx context.Context, wo
 	// Filter pods that match this workload optimizer
 	var associatedPods []corev1.Pod
 	for _, pod := range pods.Items {
-		// Check if pod has the workload optimizer label or annotation
+		// Check if pod has the workload optimizer label or annotation, including the one the
+		// pod webhook sets
 		if pod.Labels["workload-optimizer"] == wo.Name ||
-			pod.Annotations["workload-optimizer"] == wo.Name {
+			pod.Annotations["workload-optimizer"] == wo.Name ||
+			pod.Annotations[scheduler.WorkloadOptimizerAnnotation] == wo.Name {
 			associatedPods = append(associatedPods, pod)
 		}
 	}
@@ -268,8 +578,29 @@ func (r *WorkloadOptimizerReconciler) getAssociatedPods(ctx context.Context, wo
 	return associatedPods, nil
 }
 
-// getAvailableNodes gets all available nodes in the cluster
+// reservedNode returns the node the scheduler holds capacity on for the workload, if any
+func (r *WorkloadOptimizerReconciler) reservedNode(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	if r.Scheduler == nil {
+		return ""
+	}
+	snapshot := r.Scheduler.Snapshot()
+	if snapshot == nil {
+		return ""
+	}
+	node, _ := snapshot.Reservation(wo.Name)
+	return node
+}
+
+// getAvailableNodes gets all ready nodes in the cluster, cordoned and draining ones included
+// so the pods still running on them are accounted
 func (r *WorkloadOptimizerReconciler) getAvailableNodes(ctx context.Context) ([]corev1.Node, error) {
+	// Prefer the informer-backed snapshot once it has synced
+	if r.Scheduler != nil {
+		if snapshot := r.Scheduler.Snapshot(); snapshot != nil && snapshot.HasSynced() {
+			return snapshot.ReadyNodes(), nil
+		}
+	}
+
 	var nodes corev1.NodeList
 	err := r.List(ctx, &nodes)
 	if err != nil {
@@ -294,11 +625,13 @@ func (r *WorkloadOptimizerReconciler) getAvailableNodes(ctx context.Context) ([]
 func (r *WorkloadOptimizerReconciler) addFinalizer(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) error {
 	finalizerName := "workloadoptimizer.kcloud.io/finalizer"
 
-	if !containsString(wo.ObjectMeta.Finalizers, finalizerName) {
+	return r.updateWithRetry(ctx, wo, func(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+		if containsString(wo.ObjectMeta.Finalizers, finalizerName) {
+			return false
+		}
 		wo.ObjectMeta.Finalizers = append(wo.ObjectMeta.Finalizers, finalizerName)
-		return r.Update(ctx, wo)
-	}
-	return nil
+		return true
+	})
 }
 
 // handleDeletion handles the deletion of WorkloadOptimizer
@@ -309,10 +642,33 @@ func (r *WorkloadOptimizerReconciler) handleDeletion(ctx context.Context, wo *kc
 	if containsString(wo.ObjectMeta.Finalizers, finalizerName) {
 		// Perform cleanup operations here
 		log.Info("Performing cleanup operations")
+		if r.UsageHistory != nil {
+			r.UsageHistory.Forget(wo.Namespace + "/" + wo.Name)
+			if r.Metrics != nil {
+				r.Metrics.ForgetWorkloadBaseline(wo.Namespace, wo.Name)
+			}
+		}
+		if r.GPUPacker != nil {
+			r.GPUPacker.Forget(wo.Namespace + "/" + wo.Name)
+		}
+		if r.IdleDetector != nil {
+			r.IdleDetector.Forget(wo.Namespace + "/" + wo.Name)
+		}
+		if r.SchedulingBackoff != nil {
+			r.SchedulingBackoff.Reset(wo.Namespace + "/" + wo.Name)
+		}
+		if r.Scheduler != nil {
+			r.Scheduler.ReleasePlacement(wo)
+		}
 
 		// Remove finalizer
-		wo.ObjectMeta.Finalizers = removeString(wo.ObjectMeta.Finalizers, finalizerName)
-		if err := r.Update(ctx, wo); err != nil {
+		if err := r.updateWithRetry(ctx, wo, func(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+			if !containsString(wo.ObjectMeta.Finalizers, finalizerName) {
+				return false
+			}
+			wo.ObjectMeta.Finalizers = removeString(wo.ObjectMeta.Finalizers, finalizerName)
+			return true
+		}); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
@@ -322,9 +678,20 @@ func (r *WorkloadOptimizerReconciler) handleDeletion(ctx context.Context, wo *kc
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *WorkloadOptimizerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&kcloudv1alpha1.WorkloadOptimizer{}).
-		Complete(r)
+	// Only spec and annotation changes re-optimize early; status writes, including the
+	// controller's own, wait for the optimization interval
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&kcloudv1alpha1.WorkloadOptimizer{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})))
+
+	// With adaptive throttling, run the maximum worker count and let the tuner gate them
+	if r.Tuner != nil {
+		return controllerBuilder.
+			WithOptions(controller.Options{MaxConcurrentReconciles: r.Tuner.MaxConcurrentReconciles()}).
+			Complete(r.Tuner.Reconciler(r))
+	}
+
+	return controllerBuilder.Complete(r)
 }
 
 // Helper functions
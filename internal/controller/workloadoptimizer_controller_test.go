@@ -411,7 +411,7 @@ var _ = Describe("WorkloadOptimizer Controller", func() {
 			}
 
 			// Update status
-			err := reconciler.updateStatus(ctx, workload, result)
+			err := reconciler.updateStatus(ctx, workload, nil, result)
 
 			// Verify result
 			Expect(err).NotTo(HaveOccurred())
@@ -442,7 +442,7 @@ var _ = Describe("WorkloadOptimizer Controller", func() {
 			}
 
 			// Update status
-			err := reconciler.updateStatus(ctx, workload, result)
+			err := reconciler.updateStatus(ctx, workload, nil, result)
 
 			// Verify result
 			Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptive
+
+import (
+	"context"
+	"sync"
+)
+
+// Gate is a concurrency limiter whose limit can change while callers are waiting
+type Gate struct {
+	limit   int
+	inUse   int
+	waiters []chan struct{}
+	mutex   sync.Mutex
+}
+
+// NewGate creates a new gate admitting up to limit concurrent holders
+func NewGate(limit int) *Gate {
+	return &Gate{limit: limit}
+}
+
+// Acquire blocks until a slot is free or the context is cancelled
+func (g *Gate) Acquire(ctx context.Context) error {
+	g.mutex.Lock()
+	if g.inUse < g.limit && len(g.waiters) == 0 {
+		g.inUse++
+		g.mutex.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	g.waiters = append(g.waiters, ready)
+	g.mutex.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		g.mutex.Lock()
+		defer g.mutex.Unlock()
+
+		for i, waiter := range g.waiters {
+			if waiter == ready {
+				g.waiters = append(g.waiters[:i], g.waiters[i+1:]...)
+				return ctx.Err()
+			}
+		}
+
+		// The slot was granted while we were cancelling, hand it on
+		g.inUse--
+		g.wakeLocked()
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot acquired with Acquire
+func (g *Gate) Release() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.inUse--
+	g.wakeLocked()
+}
+
+// SetLimit changes the number of concurrent holders; existing holders are not interrupted
+func (g *Gate) SetLimit(limit int) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.limit = limit
+	g.wakeLocked()
+}
+
+// Limit returns the current limit
+func (g *Gate) Limit() int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.limit
+}
+
+// wakeLocked admits waiters while slots are available; callers must hold the mutex
+func (g *Gate) wakeLocked() {
+	for g.inUse < g.limit && len(g.waiters) > 0 {
+		ready := g.waiters[0]
+		g.waiters = g.waiters[1:]
+		g.inUse++
+		close(ready)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptive
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+var _ flowcontrol.RateLimiter = &RateLimiter{}
+
+// RateLimiter is a client-go compatible token bucket whose QPS and burst can be retuned
+type RateLimiter struct {
+	limiter *rate.Limiter
+	qps     float32
+	mutex   sync.RWMutex
+}
+
+// NewRateLimiter creates a new rate limiter
+func NewRateLimiter(qps float32, burst int) *RateLimiter {
+	return &RateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(qps), burst),
+		qps:     qps,
+	}
+}
+
+// TryAccept implements flowcontrol.RateLimiter
+func (r *RateLimiter) TryAccept() bool {
+	return r.limiter.Allow()
+}
+
+// Accept implements flowcontrol.RateLimiter
+func (r *RateLimiter) Accept() {
+	_ = r.limiter.Wait(context.Background())
+}
+
+// Stop implements flowcontrol.RateLimiter
+func (r *RateLimiter) Stop() {}
+
+// QPS implements flowcontrol.RateLimiter
+func (r *RateLimiter) QPS() float32 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.qps
+}
+
+// Wait implements flowcontrol.RateLimiter
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	return r.limiter.Wait(ctx)
+}
+
+// SetRate changes QPS and burst for subsequent requests
+func (r *RateLimiter) SetRate(qps float32, burst int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.qps = qps
+	r.limiter.SetLimit(rate.Limit(qps))
+	r.limiter.SetBurst(burst)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptive
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Options bounds the adaptive tuning of reconcile concurrency and client rate limits
+type Options struct {
+	MinConcurrency int
+	MaxConcurrency int
+	MinQPS         float32
+	MaxQPS         float32
+	// BurstRatio sets burst as a multiple of QPS
+	BurstRatio float32
+	// LatencyTarget is the mean API server latency above which the tuner backs off
+	LatencyTarget time.Duration
+	// MaxThrottleRate is the fraction of 429 responses above which the tuner backs off
+	MaxThrottleRate float64
+	// Interval is how often the tuner re-evaluates
+	Interval time.Duration
+}
+
+// DefaultOptions returns conservative tuning bounds
+func DefaultOptions() Options {
+	return Options{
+		MinConcurrency:  1,
+		MaxConcurrency:  10,
+		MinQPS:          5,
+		MaxQPS:          100,
+		BurstRatio:      1.5,
+		LatencyTarget:   500 * time.Millisecond,
+		MaxThrottleRate: 0.01,
+		Interval:        15 * time.Second,
+	}
+}
+
+// Tuner adjusts reconcile concurrency and client QPS from observed API server latency and 429s
+// using additive increase and multiplicative decrease
+type Tuner struct {
+	opts    Options
+	gate    *Gate
+	limiter *RateLimiter
+
+	requests     int64
+	throttled    int64
+	totalLatency time.Duration
+	mutex        sync.Mutex
+}
+
+// NewTuner creates a new tuner starting at the midpoint of the configured bounds
+func NewTuner(opts Options) *Tuner {
+	concurrency := (opts.MinConcurrency + opts.MaxConcurrency) / 2
+	qps := (opts.MinQPS + opts.MaxQPS) / 2
+	return &Tuner{
+		opts:    opts,
+		gate:    NewGate(concurrency),
+		limiter: NewRateLimiter(qps, burstFor(qps, opts.BurstRatio)),
+	}
+}
+
+// WrapConfig returns a copy of config that uses the tuner's rate limiter and reports latency
+func (t *Tuner) WrapConfig(config *rest.Config) *rest.Config {
+	wrapped := rest.CopyConfig(config)
+	wrapped.RateLimiter = t.limiter
+	wrapped.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &observingRoundTripper{next: rt, tuner: t}
+	})
+	return wrapped
+}
+
+// MaxConcurrentReconciles is the worker count to configure on the controller;
+// the tuner's gate limits how many of them run at once
+func (t *Tuner) MaxConcurrentReconciles() int {
+	return t.opts.MaxConcurrency
+}
+
+// Reconciler wraps a reconciler so it only runs when the gate admits it
+func (t *Tuner) Reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		if err := t.gate.Acquire(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+		defer t.gate.Release()
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// Start re-evaluates limits on each interval until the context is cancelled
+func (t *Tuner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.adjust(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (t *Tuner) NeedLeaderElection() bool {
+	return false
+}
+
+// observe records the outcome of a single API request
+func (t *Tuner) observe(latency time.Duration, throttled bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.requests++
+	t.totalLatency += latency
+	if throttled {
+		t.throttled++
+	}
+}
+
+// adjust applies one AIMD step based on the requests observed since the last step
+func (t *Tuner) adjust(ctx context.Context) {
+	t.mutex.Lock()
+	requests, throttled, totalLatency := t.requests, t.throttled, t.totalLatency
+	t.requests, t.throttled, t.totalLatency = 0, 0, 0
+	t.mutex.Unlock()
+
+	if requests == 0 {
+		return
+	}
+
+	meanLatency := totalLatency / time.Duration(requests)
+	throttleRate := float64(throttled) / float64(requests)
+	concurrency := t.gate.Limit()
+	qps := t.limiter.QPS()
+
+	stressed := meanLatency > t.opts.LatencyTarget || throttleRate > t.opts.MaxThrottleRate
+	if stressed {
+		concurrency = max(t.opts.MinConcurrency, concurrency/2)
+		qps = float32(math.Max(float64(t.opts.MinQPS), float64(qps)/2))
+	} else {
+		concurrency = min(t.opts.MaxConcurrency, concurrency+1)
+		qps = float32(math.Min(float64(t.opts.MaxQPS), float64(qps+t.opts.MaxQPS/10)))
+	}
+
+	if concurrency != t.gate.Limit() || qps != t.limiter.QPS() {
+		t.gate.SetLimit(concurrency)
+		t.limiter.SetRate(qps, burstFor(qps, t.opts.BurstRatio))
+
+		log.FromContext(ctx).WithName("adaptive").Info("Adjusted reconcile concurrency and client rate",
+			"stressed", stressed,
+			"meanLatency", meanLatency,
+			"throttleRate", throttleRate,
+			"concurrency", concurrency,
+			"qps", qps)
+	}
+}
+
+// observingRoundTripper reports request latency and throttling to the tuner
+type observingRoundTripper struct {
+	next  http.RoundTripper
+	tuner *Tuner
+}
+
+// RoundTrip implements http.RoundTripper
+func (o *observingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := o.next.RoundTrip(req)
+
+	// Watches are long-lived and would skew latency
+	if req.URL.Query().Get("watch") == "true" {
+		return resp, err
+	}
+
+	throttled := resp != nil && resp.StatusCode == http.StatusTooManyRequests
+	o.tuner.observe(time.Since(start), throttled)
+	return resp, err
+}
+
+// burstFor derives burst from QPS
+func burstFor(qps, ratio float32) int {
+	return max(1, int(qps*ratio))
+}
@@ -0,0 +1,618 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package allocation attributes the realized cost of nodes to the pods running on them and
+// rolls it up to namespaces, nodes and label values such as teams.
+package allocation
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/retention"
+)
+
+const (
+	// DefaultInterval is how often node cost is allocated to pods
+	DefaultInterval = 5 * time.Minute
+	// DefaultRetention is how long hourly allocated cost is kept
+	DefaultRetention = 30 * 24 * time.Hour
+
+	// GroupByNamespace, GroupByPod, GroupByNode and GroupByLabelPrefix select how a report
+	// is grouped; the prefix is followed by a label key such as team
+	GroupByNamespace   = "namespace"
+	GroupByPod         = "pod"
+	GroupByNode        = "node"
+	GroupByLabelPrefix = "label:"
+
+	// workloadAnnotation names the WorkloadOptimizer managing a pod
+	workloadAnnotation = "kcloud.io/workload-optimizer"
+
+	// targetName names the allocator in purge results
+	targetName = "allocation"
+)
+
+// Basis is the measure of a pod's share of its node
+type Basis string
+
+const (
+	// BasisRequests shares nodes by the resources pods request
+	BasisRequests Basis = "requests"
+	// BasisUsage shares nodes by the resources pods use
+	BasisUsage Basis = "usage"
+	// BasisMax shares nodes by the larger of what pods request and use, so pods are charged
+	// for what they reserve and for what they burst into
+	BasisMax Basis = "max"
+)
+
+// ParseBasis returns the basis of its name
+func ParseBasis(name string) (Basis, error) {
+	switch basis := Basis(name); basis {
+	case BasisRequests, BasisUsage, BasisMax:
+		return basis, nil
+	}
+	return "", fmt.Errorf("unknown cost allocation basis %q; use requests, usage or max", name)
+}
+
+var (
+	namespaceCost = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_cost_allocation_namespace_cost_per_hour",
+		Help: "Node cost allocated to the pods of a namespace per hour in the reporting currency",
+	}, []string{"namespace"})
+	labelCost = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_cost_allocation_label_cost_per_hour",
+		Help: "Node cost allocated to the pods with a label value per hour in the reporting currency",
+	}, []string{"label", "value"})
+	idleCost = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_cost_allocation_idle_cost_per_hour",
+		Help: "Node cost allocated to no pod per hour in the reporting currency",
+	}, []string{"node"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(namespaceCost, labelCost, idleCost)
+}
+
+// UsageSource reports the recent resource usage of every pod
+type UsageSource interface {
+	PodUsage(ctx context.Context, window time.Duration) (map[types.NamespacedName]optimizer.PodResourceUsage, error)
+}
+
+// resources are amounts of CPU cores, memory in GiB and GPUs
+type resources [3]float64
+
+// add returns the sum of both amounts
+func (r resources) add(other resources) resources {
+	for i := range r {
+		r[i] += other[i]
+	}
+	return r
+}
+
+// PodCost is the node cost allocated to a running pod per hour
+type PodCost struct {
+	Namespace string
+	Name      string
+	Node      string
+	// Workload is the WorkloadOptimizer managing the pod, if any
+	Workload string
+	// Labels are the pod's labels over those of its namespace
+	Labels      labels.Set
+	CostPerHour float64
+}
+
+// Allocation is the split of node cost among the pods running at one moment, in the
+// reporting currency
+type Allocation struct {
+	Pods []PodCost
+	// Idle is the hourly cost of each node no pod was allocated
+	Idle map[string]float64
+}
+
+// podKey identifies a pod
+type podKey struct {
+	namespace string
+	name      string
+}
+
+// account is the hourly cost allocated to a pod, and the node, workload and labels it was
+// last seen with
+type account struct {
+	node     string
+	workload string
+	labels   labels.Set
+	hours    map[time.Time]float64
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Allocator prices every node and splits its price among the pods running on it. The price
+// is divided into CPU, memory and GPU parts in proportion to their rates, and each part is
+// shared by the pods' amounts of the resource against the node's allocatable amount. What no
+// pod is charged is the node's idle cost. Allocated cost accrues in hourly buckets from when
+// the operator started allocating; the allocator is kept in memory.
+type Allocator struct {
+	client      client.Client
+	pricing     optimizer.PricingProvider
+	nodePricing optimizer.NodePricing
+	interval    time.Duration
+	retention   time.Duration
+	// currency is the reporting currency allocated costs are in; nil is US dollars
+	currency *currency.Converter
+
+	// Basis measures the pods' shares of their nodes; empty shares by requests
+	Basis Basis
+	// Usage reports pod usage for the usage and max bases; pods without usage, or every pod
+	// when nil, are measured by their requests
+	Usage UsageSource
+	// LabelKeys are the pod or namespace label keys, such as team, whose allocated cost is
+	// exported as metrics
+	LabelKeys []string
+
+	mu       sync.Mutex
+	clock    clock.PassiveClock
+	sampled  time.Time
+	accounts map[podKey]*account
+	idle     map[string]map[time.Time]float64
+}
+
+// NewAllocator returns an allocator splitting resource rates from the pricing and node prices
+// from the node pricing, every interval, and keeping allocated cost for the retention. Nodes
+// the node pricing does not know, or all when it is nil, are priced at the resource rates.
+func NewAllocator(c client.Client, pricing optimizer.PricingProvider, nodePricing optimizer.NodePricing,
+	interval, retention time.Duration) *Allocator {
+	return &Allocator{
+		client:      c,
+		pricing:     pricing,
+		nodePricing: nodePricing,
+		interval:    interval,
+		retention:   retention,
+		clock:       clock.RealClock{},
+		accounts:    make(map[podKey]*account),
+		idle:        make(map[string]map[time.Time]float64),
+	}
+}
+
+// SetCurrency sets the reporting currency allocated costs are in
+func (a *Allocator) SetCurrency(converter *currency.Converter) {
+	a.currency = converter
+}
+
+// SetClock replaces the clock samples are timed with
+func (a *Allocator) SetClock(c clock.PassiveClock) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clock = c
+}
+
+// Allocate splits the price of each node among the running pods on it
+func (a *Allocator) Allocate(nodes []corev1.Node, pods []corev1.Pod, namespaceLabels map[string]labels.Set,
+	usage map[types.NamespacedName]optimizer.PodResourceUsage) *Allocation {
+	running := make(map[string][]*corev1.Pod)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != "" && pod.Status.Phase == corev1.PodRunning {
+			running[pod.Spec.NodeName] = append(running[pod.Spec.NodeName], pod)
+		}
+	}
+
+	allocation := &Allocation{Idle: make(map[string]float64, len(nodes))}
+	for i := range nodes {
+		node := &nodes[i]
+		gpus := node.Status.Allocatable["nvidia.com/gpu"]
+		allocatable := resources{
+			node.Status.Allocatable.Cpu().AsApproximateFloat64(),
+			node.Status.Allocatable.Memory().AsApproximateFloat64() / (1 << 30),
+			gpus.AsApproximateFloat64(),
+		}
+		rates := resources{
+			a.pricing.CalculateCost(allocatable[0], 0, 0, 0),
+			a.pricing.CalculateCost(0, allocatable[1], 0, 0),
+			a.pricing.CalculateCost(0, 0, int32(allocatable[2]), 0),
+		}
+		listed := rates[0] + rates[1] + rates[2]
+		if listed <= 0 {
+			continue
+		}
+		price := listed
+		if a.nodePricing != nil {
+			if nodePrice, ok := a.nodePricing.NodePrice(node); ok {
+				price = nodePrice
+			}
+		}
+		price = a.currency.FromUSD(price)
+
+		amounts := make([]resources, len(running[node.Name]))
+		var used resources
+		for j, pod := range running[node.Name] {
+			amounts[j] = a.amounts(pod, usage)
+			used = used.add(amounts[j])
+		}
+		var allocated float64
+		for j, pod := range running[node.Name] {
+			var cost float64
+			for r := range amounts[j] {
+				// Pods bursting past the node share it by what they use
+				if capacity := math.Max(allocatable[r], used[r]); capacity > 0 {
+					cost += price * rates[r] / listed * amounts[j][r] / capacity
+				}
+			}
+			allocated += cost
+			allocation.Pods = append(allocation.Pods, PodCost{
+				Namespace:   pod.Namespace,
+				Name:        pod.Name,
+				Node:        node.Name,
+				Workload:    pod.Annotations[workloadAnnotation],
+				Labels:      labels.Merge(namespaceLabels[pod.Namespace], pod.Labels),
+				CostPerHour: cost,
+			})
+		}
+		allocation.Idle[node.Name] = math.Max(price-allocated, 0)
+	}
+	return allocation
+}
+
+// amounts measures a pod's resources by the allocator's basis
+func (a *Allocator) amounts(pod *corev1.Pod, usage map[types.NamespacedName]optimizer.PodResourceUsage) resources {
+	cpuCores, memoryGB, gpus, _ := optimizer.PodResources(pod)
+	requested := resources{cpuCores, memoryGB, float64(gpus)}
+	used, ok := usage[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]
+	if !ok || a.Basis == "" || a.Basis == BasisRequests {
+		return requested
+	}
+	measured := resources{used.CPUCores, used.MemoryGB, used.GPUs}
+	if a.Basis == BasisUsage {
+		return measured
+	}
+	for r := range measured {
+		measured[r] = math.Max(measured[r], requested[r])
+	}
+	return measured
+}
+
+// Observe accrues the allocation over the time since the previous sample, or one interval
+// for the first, drops cost older than the retention and exports the allocation as metrics
+func (a *Allocator) Observe(allocation *Allocation) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock.Now()
+	from := now.Add(-a.interval)
+	if !a.sampled.IsZero() {
+		from = a.sampled
+	}
+	a.sampled = now
+
+	for _, pod := range allocation.Pods {
+		key := podKey{namespace: pod.Namespace, name: pod.Name}
+		acct, ok := a.accounts[key]
+		if !ok {
+			acct = &account{hours: make(map[time.Time]float64)}
+			a.accounts[key] = acct
+		}
+		acct.node, acct.workload, acct.labels = pod.Node, pod.Workload, pod.Labels
+		if pod.CostPerHour > 0 {
+			accrue(acct.hours, from, now, pod.CostPerHour)
+		}
+	}
+	for node, cost := range allocation.Idle {
+		if cost <= 0 {
+			continue
+		}
+		hours, ok := a.idle[node]
+		if !ok {
+			hours = make(map[time.Time]float64)
+			a.idle[node] = hours
+		}
+		accrue(hours, from, now, cost)
+	}
+
+	cutoff := now.Add(-a.retention).Truncate(time.Hour)
+	for key, acct := range a.accounts {
+		prune(acct.hours, cutoff)
+		if len(acct.hours) == 0 {
+			delete(a.accounts, key)
+		}
+	}
+	for node, hours := range a.idle {
+		prune(hours, cutoff)
+		if len(hours) == 0 {
+			delete(a.idle, node)
+		}
+	}
+
+	a.export(allocation)
+}
+
+// export replaces the allocation metrics with the allocation
+func (a *Allocator) export(allocation *Allocation) {
+	namespaceCost.Reset()
+	labelCost.Reset()
+	idleCost.Reset()
+	for _, pod := range allocation.Pods {
+		namespaceCost.WithLabelValues(pod.Namespace).Add(pod.CostPerHour)
+		for _, key := range a.LabelKeys {
+			labelCost.WithLabelValues(key, pod.Labels[key]).Add(pod.CostPerHour)
+		}
+	}
+	for node, cost := range allocation.Idle {
+		idleCost.WithLabelValues(node).Set(cost)
+	}
+}
+
+// accrue adds the hourly cost over the period to the hours, split at hour boundaries
+func accrue(hours map[time.Time]float64, from, to time.Time, cost float64) {
+	for start := from; start.Before(to); {
+		hour := start.Truncate(time.Hour)
+		end := hour.Add(time.Hour)
+		if end.After(to) {
+			end = to
+		}
+		hours[hour] += cost * end.Sub(start).Hours()
+		start = end
+	}
+}
+
+// prune drops the hours before the cutoff
+func prune(hours map[time.Time]float64, cutoff time.Time) {
+	for hour := range hours {
+		if hour.Before(cutoff) {
+			delete(hours, hour)
+		}
+	}
+}
+
+// Query selects and groups the allocated cost of a report
+type Query struct {
+	// GroupBy is namespace, pod, node or label:<key>; it defaults to namespace
+	GroupBy string
+	// From and To bound the report; cost is counted by the hour it accrued in
+	From time.Time
+	To   time.Time
+	// Namespace limits the report to one namespace when set
+	Namespace string
+	// Selector limits the report to pods whose labels match; nil matches all
+	Selector labels.Selector
+}
+
+// Row is the cost allocated to one group
+type Row struct {
+	// Group is the namespace, namespace/name of the pod, node, or label value; pods without
+	// the label are grouped under an empty value
+	Group string  `json:"group"`
+	Cost  float64 `json:"cost"`
+	// IdleCost is the cost of the node allocated to no pod, given by node without a
+	// namespace or selector
+	IdleCost float64 `json:"idleCost,omitempty"`
+	Pods     int     `json:"pods"`
+}
+
+// Report is the cost allocated in a time range, grouped and sorted by cost, highest first
+type Report struct {
+	GroupBy   string            `json:"groupBy"`
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Currency  currency.Currency `json:"currency"`
+	Rows      []Row             `json:"rows"`
+	TotalCost float64           `json:"totalCost"`
+	// IdleCost is the node cost allocated to no pod; it is left out of the total and only
+	// reported without a namespace or selector, as it belongs to neither
+	IdleCost float64 `json:"idleCost"`
+}
+
+// Report rolls up the cost allocated in the hours from the query's From to its To
+func (a *Allocator) Report(query Query) (*Report, error) {
+	if query.GroupBy == "" {
+		query.GroupBy = GroupByNamespace
+	}
+	labelKey, byLabel := strings.CutPrefix(query.GroupBy, GroupByLabelPrefix)
+	if byLabel && labelKey == "" {
+		return nil, errors.New("group by label requires a label key")
+	}
+	if !byLabel && !slices.Contains([]string{GroupByNamespace, GroupByPod, GroupByNode}, query.GroupBy) {
+		return nil, fmt.Errorf("unknown grouping %q; use namespace, pod, node or label:<key>", query.GroupBy)
+	}
+	if !query.To.After(query.From) {
+		return nil, fmt.Errorf("report end %s is not after its start %s",
+			query.To.Format(time.RFC3339), query.From.Format(time.RFC3339))
+	}
+	from := query.From.Truncate(time.Hour)
+	inRange := func(hours map[time.Time]float64) float64 {
+		var total float64
+		for hour, cost := range hours {
+			if !hour.Before(from) && hour.Before(query.To) {
+				total += cost
+			}
+		}
+		return total
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rows := make(map[string]*Row)
+	for key, acct := range a.accounts {
+		if query.Namespace != "" && key.namespace != query.Namespace {
+			continue
+		}
+		if query.Selector != nil && !query.Selector.Matches(acct.labels) {
+			continue
+		}
+		cost := inRange(acct.hours)
+		if cost == 0 {
+			continue
+		}
+
+		group := key.namespace
+		switch {
+		case byLabel:
+			group = acct.labels[labelKey]
+		case query.GroupBy == GroupByPod:
+			group = key.namespace + "/" + key.name
+		case query.GroupBy == GroupByNode:
+			group = acct.node
+		}
+		row, ok := rows[group]
+		if !ok {
+			row = &Row{Group: group}
+			rows[group] = row
+		}
+		row.Cost += cost
+		row.Pods++
+	}
+
+	money := a.currency.Currency()
+	report := &Report{GroupBy: query.GroupBy, From: from, To: query.To, Currency: money, Rows: make([]Row, 0, len(rows))}
+	if query.Namespace == "" && query.Selector == nil {
+		for node, hours := range a.idle {
+			idle := inRange(hours)
+			report.IdleCost += idle
+			if query.GroupBy != GroupByNode || idle == 0 {
+				continue
+			}
+			row, ok := rows[node]
+			if !ok {
+				row = &Row{Group: node}
+				rows[node] = row
+			}
+			row.IdleCost += idle
+		}
+		report.IdleCost = money.Round(report.IdleCost)
+	}
+	for _, row := range rows {
+		report.TotalCost += row.Cost
+		row.Cost, row.IdleCost = money.Round(row.Cost), money.Round(row.IdleCost)
+		report.Rows = append(report.Rows, *row)
+	}
+	report.TotalCost = money.Round(report.TotalCost)
+	slices.SortFunc(report.Rows, func(a, b Row) int {
+		return cmp.Or(cmp.Compare(b.Cost, a.Cost), cmp.Compare(a.Group, b.Group))
+	})
+	return report, nil
+}
+
+// Name identifies the allocator in purge results
+func (a *Allocator) Name() string {
+	return targetName
+}
+
+// Purge removes the hourly cost the filter selects, by the start of each hour. Pods are
+// matched by the WorkloadOptimizer managing them; idle node cost belongs to no namespace, so
+// only a purge of a time range removes it.
+func (a *Allocator) Purge(_ context.Context, filter retention.Filter) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	purged := 0
+	for key, acct := range a.accounts {
+		for hour := range acct.hours {
+			if filter.Matches(key.namespace, acct.workload, hour) {
+				delete(acct.hours, hour)
+				purged++
+			}
+		}
+		if len(acct.hours) == 0 {
+			delete(a.accounts, key)
+		}
+	}
+	for node, hours := range a.idle {
+		for hour := range hours {
+			if filter.Matches("", "", hour) {
+				delete(hours, hour)
+				purged++
+			}
+		}
+		if len(hours) == 0 {
+			delete(a.idle, node)
+		}
+	}
+	return purged, nil
+}
+
+// Refresh allocates the cost of every node to its pods, measuring pods by their requests
+// when their usage cannot be read
+func (a *Allocator) Refresh(ctx context.Context) error {
+	var nodes corev1.NodeList
+	if err := a.client.List(ctx, &nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var pods corev1.PodList
+	if err := a.client.List(ctx, &pods); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	var namespaces corev1.NamespaceList
+	if err := a.client.List(ctx, &namespaces); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaceLabels := make(map[string]labels.Set, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		namespaceLabels[namespace.Name] = namespace.Labels
+	}
+
+	var usage map[types.NamespacedName]optimizer.PodResourceUsage
+	if a.Usage != nil && a.Basis != "" && a.Basis != BasisRequests {
+		var err error
+		if usage, err = a.Usage.PodUsage(ctx, a.interval); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to read pod usage, allocating by requests")
+		}
+	}
+	a.Observe(a.Allocate(nodes.Items, pods.Items, namespaceLabels, usage))
+	return nil
+}
+
+// Start allocates every interval until the context is cancelled
+func (a *Allocator) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("cost-allocation")
+	ctx = log.IntoContext(ctx, logger)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.Refresh(ctx); err != nil {
+				logger.Error(err, "Failed to allocate node cost")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection allocates on every replica so any of them can serve reports
+func (a *Allocator) NeedLeaderElection() bool {
+	return false
+}
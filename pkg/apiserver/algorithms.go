@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+// AlgorithmStatisticsSource reports per-algorithm scheduling statistics
+type AlgorithmStatisticsSource interface {
+	GetAlgorithmStatistics() []scheduler.AlgorithmStatistics
+}
+
+// EnableAlgorithmStatistics serves read-only per-algorithm scheduling statistics
+func (s *Server) EnableAlgorithmStatistics(source AlgorithmStatisticsSource) {
+	s.mux.HandleFunc("/apis/v1/scheduler/algorithms", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"algorithms": source.GetAlgorithmStatistics(),
+		})
+	})
+}
+
+// DatasetCacheStatisticsSource reports dataset cache placement coordination statistics
+type DatasetCacheStatisticsSource interface {
+	GetDatasetCacheStatistics() []scheduler.DatasetCacheStatistics
+}
+
+// EnableDatasetCacheStatistics serves read-only dataset cache hits, misses and savings
+func (s *Server) EnableDatasetCacheStatistics(source DatasetCacheStatisticsSource) {
+	s.mux.HandleFunc("/apis/v1/scheduler/dataset-caches", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"caches": source.GetDatasetCacheStatistics(),
+		})
+	})
+}
+
+// DefragmentationPlanSource reports measured fragmentation and the recommended migrations
+type DefragmentationPlanSource interface {
+	DefragmentationPlan() *optimizer.DefragmentationPlan
+}
+
+// EnableDefragmentationPlan serves the latest fragmentation measurement and defragmentation plan
+func (s *Server) EnableDefragmentationPlan(source DefragmentationPlanSource) {
+	s.mux.HandleFunc("/apis/v1/scheduler/fragmentation", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		plan := source.DefragmentationPlan()
+		if plan == nil {
+			writeError(w, http.StatusServiceUnavailable, fmt.Errorf("fragmentation has not been measured yet"))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"plan":           plan,
+			"recommendation": plan.Recommendation(),
+		})
+	})
+}
+
+// ConsolidationPlanSource plans packing workloads onto fewer nodes
+type ConsolidationPlanSource interface {
+	Plan(ctx context.Context) (*optimizer.ConsolidationPlan, error)
+}
+
+// EnableConsolidationPlan serves the source's node consolidation plan
+func (s *Server) EnableConsolidationPlan(source ConsolidationPlanSource) {
+	s.mux.HandleFunc("/apis/v1/optimizer/consolidation", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		plan, err := source.Plan(r.Context())
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"plan":           plan,
+			"recommendation": plan.Recommendation(),
+		})
+	})
+}
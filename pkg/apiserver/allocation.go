@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/allocation"
+)
+
+// AllocationSource rolls up node cost allocated to pods
+type AllocationSource interface {
+	Report(query allocation.Query) (*allocation.Report, error)
+}
+
+// EnableCostAllocation serves the node cost allocated to pods grouped by namespace, pod, node
+// or label. The groupBy, from, to, namespace and selector query parameters select the
+// report; it covers the current month by namespace by default. Callers must be allowed to
+// list the pods of the namespace, or of every namespace when the report is not limited to one.
+func (s *Server) EnableCostAllocation(source AllocationSource) {
+	s.mux.HandleFunc("/apis/v1/allocation", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		params := r.URL.Query()
+		if err := s.authorizeReport(r.Context(), params.Get("namespace"), "pods"); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		from, to, selector, err := reportScope(params)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		report, err := source.Report(allocation.Query{
+			GroupBy:   params.Get("groupBy"),
+			From:      from,
+			To:        to,
+			Namespace: params.Get("namespace"),
+			Selector:  selector,
+		})
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// errUnauthenticated is returned when a request carries no credentials the server can verify
+var errUnauthenticated = errors.New("request is not authenticated")
+
+type identityKey struct{}
+
+// authenticated serves next only for callers that present a verified client certificate
+// or a bearer token the cluster accepts, passing the resolved identity in the context
+func (s *Server) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := s.authenticate(r)
+		if err != nil {
+			log.FromContext(r.Context()).WithName("audit").Info("Rejected unauthenticated request",
+				"path", r.URL.Path,
+				"remoteAddr", r.RemoteAddr,
+				"reason", err.Error())
+			writeError(w, http.StatusUnauthorized, errUnauthenticated)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, identity)))
+	})
+}
+
+// authenticate resolves the caller from its client certificate, whose common name and
+// organizations are the user and groups as in Kubernetes x509 authentication, or from a
+// bearer token reviewed by the cluster's TokenReview API
+func (s *Server) authenticate(r *http.Request) (*Identity, error) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		subject := r.TLS.VerifiedChains[0][0].Subject
+		if subject.CommonName == "" {
+			return nil, errors.New("client certificate has no common name")
+		}
+		return &Identity{User: subject.CommonName, Groups: subject.Organization}, nil
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		return nil, errUnauthenticated
+	}
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: strings.TrimSpace(token)},
+	}
+	if err := s.Client.Create(r.Context(), review); err != nil {
+		return nil, fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return nil, errors.New(review.Status.Error)
+		}
+		return nil, errUnauthenticated
+	}
+
+	user := review.Status.User
+	identity := &Identity{User: user.Username, UID: user.UID, Groups: user.Groups}
+	if len(user.Extra) > 0 {
+		identity.Extra = make(map[string][]string, len(user.Extra))
+		for key, values := range user.Extra {
+			identity.Extra[key] = values
+		}
+	}
+	return identity, nil
+}
+
+// callerFrom returns the authenticated caller of a request served behind authenticated
+func callerFrom(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// authorize checks with a SubjectAccessReview that the identity may perform the action
+func (s *Server) authorize(ctx context.Context, identity *Identity, attributes authorizationv1.ResourceAttributes) error {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attributes,
+			User:               identity.User,
+			UID:                identity.UID,
+			Groups:             identity.Groups,
+		},
+	}
+	if len(identity.Extra) > 0 {
+		review.Spec.Extra = make(map[string]authorizationv1.ExtraValue, len(identity.Extra))
+		for key, values := range identity.Extra {
+			review.Spec.Extra[key] = values
+		}
+	}
+	if err := s.Client.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to review access: %w", err)
+	}
+	if !review.Status.Allowed {
+		resource := attributes.Resource
+		if attributes.Subresource != "" {
+			resource += "/" + attributes.Subresource
+		}
+		if attributes.Name != "" {
+			resource += " " + attributes.Name
+		}
+		if attributes.Namespace != "" {
+			return fmt.Errorf("user %q may not %s %s in namespace %s", identity.User, attributes.Verb, resource, attributes.Namespace)
+		}
+		return fmt.Errorf("user %q may not %s %s", identity.User, attributes.Verb, resource)
+	}
+	return nil
+}
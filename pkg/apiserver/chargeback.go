@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/chargeback"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+)
+
+// ChargebackSource rolls up realized workload cost and energy
+type ChargebackSource interface {
+	Report(query chargeback.Query) (*chargeback.Report, error)
+}
+
+// EnableChargeback serves the realized cost and energy of workloads grouped by namespace,
+// workload or label. The groupBy, from, to, namespace and selector query parameters select
+// the report; it covers the current month by namespace by default. The currency parameter
+// converts its costs from the reporting currency at the converter's rates. Callers must be
+// allowed to list the WorkloadOptimizers of the namespace, or of every namespace when the
+// report is not limited to one. Only the leader serves reports; other replicas answer 503.
+func (s *Server) EnableChargeback(source ChargebackSource, converter *currency.Converter) {
+	s.mux.HandleFunc("/apis/v1/chargeback", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		params := r.URL.Query()
+		if err := s.authorizeReport(r.Context(), params.Get("namespace"), "workloadoptimizers"); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		from, to, selector, err := reportScope(params)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		query := chargeback.Query{
+			GroupBy:   params.Get("groupBy"),
+			From:      from,
+			To:        to,
+			Namespace: params.Get("namespace"),
+			Selector:  selector,
+		}
+
+		var target currency.Currency
+		if raw := params.Get("currency"); raw != "" {
+			parsed, err := currency.Parse(raw)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			target = parsed
+		}
+
+		report, err := source.Report(query)
+		if err != nil {
+			writeError(w, reportErrorStatus(err), err)
+			return
+		}
+		if target != "" && target != report.Currency {
+			if report, err = report.Converted(converter, target); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// authorizeReport checks with a SubjectAccessReview that the caller may list the resource,
+// in every namespace when namespace is empty, before reading its costs
+func (s *Server) authorizeReport(ctx context.Context, namespace, resource string) error {
+	attributes := authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "list", Resource: resource}
+	if resource == "workloadoptimizers" {
+		attributes.Group = kcloudv1alpha1.GroupVersion.Group
+	}
+	return s.authorize(ctx, callerFrom(ctx), attributes)
+}
+
+// reportErrorStatus returns the status of a failed report: unavailable on replicas that do
+// not serve reports, bad request otherwise
+func reportErrorStatus(err error) int {
+	if errors.Is(err, chargeback.ErrNotServing) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
+
+// reportScope reads the from, to and selector parameters of a cost report; the range
+// defaults to the current month
+func reportScope(params url.Values) (time.Time, time.Time, labels.Selector, error) {
+	now := time.Now().UTC()
+	from, to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), now
+	for name, value := range map[string]*time.Time{"from": &from, "to": &to} {
+		if raw := params.Get(name); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return from, to, nil, fmt.Errorf("invalid %s time: %w", name, err)
+			}
+			*value = parsed
+		}
+	}
+	var selector labels.Selector
+	if raw := params.Get("selector"); raw != "" {
+		parsed, err := labels.Parse(raw)
+		if err != nil {
+			return from, to, nil, fmt.Errorf("invalid selector: %w", err)
+		}
+		selector = parsed
+	}
+	return from, to, selector, nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/costreport"
+)
+
+// EnableCostReport serves the cost of every workload, namespace and node over the from and
+// to query parameters, by default the current month. The format parameter, csv, focus or json
+// by default, selects the file format. The report covers every namespace and node, so
+// callers must be allowed to list WorkloadOptimizers and pods cluster-wide.
+func (s *Server) EnableCostReport(generator *costreport.Generator) {
+	s.mux.HandleFunc("/apis/v1/reports/cost", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		for _, resource := range []string{"workloadoptimizers", "pods"} {
+			if err := s.authorizeReport(r.Context(), "", resource); err != nil {
+				writeError(w, http.StatusForbidden, err)
+				return
+			}
+		}
+		params := r.URL.Query()
+		from, to, _, err := reportScope(params)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		format := costreport.FormatJSON
+		if raw := params.Get("format"); raw != "" {
+			if format, err = costreport.ParseFormat(raw); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		report, err := generator.Generate(from, to)
+		if err != nil {
+			writeError(w, reportErrorStatus(err), err)
+			return
+		}
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+			fmt.Sprintf("cost-report-%s-%s.%s", report.From.Format("20060102"), report.To.Format("20060102"), format.Extension())))
+		w.WriteHeader(http.StatusOK)
+		_ = report.Write(w, format)
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+// ExplanationSource explains how a workload would be placed on the given nodes
+type ExplanationSource interface {
+	Explain(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node) (*scheduler.Explanation, error)
+}
+
+// EnableExplain serves per-node filter results and score breakdowns for a workload, selected
+// with the namespace and name query parameters
+func (s *Server) EnableExplain(source ExplanationSource) {
+	s.mux.HandleFunc("/apis/v1/scheduler/explain", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		key := types.NamespacedName{Namespace: r.URL.Query().Get("namespace"), Name: r.URL.Query().Get("name")}
+		if key.Namespace == "" || key.Name == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("namespace and name query parameters are required"))
+			return
+		}
+
+		ctx := r.Context()
+		var wo kcloudv1alpha1.WorkloadOptimizer
+		if err := s.Client.Get(ctx, key, &wo); err != nil {
+			status := http.StatusInternalServerError
+			if apierrors.IsNotFound(err) {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err)
+			return
+		}
+		var nodes corev1.NodeList
+		if err := s.Client.List(ctx, &nodes); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to list nodes: %w", err))
+			return
+		}
+
+		explanation, err := source.Explain(ctx, &wo, nodes.Items)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, explanation)
+	})
+}
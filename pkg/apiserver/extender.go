@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+// EnableSchedulerExtender serves kube-scheduler extender filter and prioritize endpoints
+func (s *Server) EnableSchedulerExtender(sched *scheduler.Scheduler) {
+	plugin := scheduler.NewNodePlugin(sched, s.Client)
+	s.mux.HandleFunc("/scheduler/filter", func(w http.ResponseWriter, r *http.Request) {
+		s.handleExtenderFilter(w, r, plugin)
+	})
+	s.mux.HandleFunc("/scheduler/prioritize", func(w http.ResponseWriter, r *http.Request) {
+		s.handleExtenderPrioritize(w, r, plugin)
+	})
+}
+
+// handleExtenderFilter removes nodes that cannot satisfy the pod's workload requirements
+func (s *Server) handleExtenderFilter(w http.ResponseWriter, r *http.Request, plugin *scheduler.NodePlugin) {
+	ctx := r.Context()
+
+	args, err := decodeExtenderArgs(r)
+	if err != nil {
+		writeJSON(w, http.StatusOK, &scheduler.ExtenderFilterResult{Error: err.Error()})
+		return
+	}
+
+	wo, err := plugin.ResolveWorkload(ctx, args.Pod)
+	if err != nil {
+		writeJSON(w, http.StatusOK, &scheduler.ExtenderFilterResult{Error: err.Error()})
+		return
+	}
+
+	nodes, err := s.extenderNodes(ctx, args)
+	if err != nil {
+		writeJSON(w, http.StatusOK, &scheduler.ExtenderFilterResult{Error: err.Error()})
+		return
+	}
+
+	feasible, failed := plugin.Scheduler.FilterNodes(ctx, wo, nodes)
+
+	result := &scheduler.ExtenderFilterResult{FailedNodes: failed}
+	if args.Nodes != nil {
+		result.Nodes = &corev1.NodeList{Items: feasible}
+	} else {
+		names := make([]string, 0, len(feasible))
+		for _, node := range feasible {
+			names = append(names, node.Name)
+		}
+		result.NodeNames = &names
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleExtenderPrioritize scores nodes using the operator's cost and power model
+func (s *Server) handleExtenderPrioritize(w http.ResponseWriter, r *http.Request, plugin *scheduler.NodePlugin) {
+	ctx := r.Context()
+
+	args, err := decodeExtenderArgs(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	wo, err := plugin.ResolveWorkload(ctx, args.Pod)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	nodes, err := s.extenderNodes(ctx, args)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	priorities, err := plugin.Scheduler.PrioritizeNodes(ctx, wo, nodes)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, priorities)
+}
+
+// decodeExtenderArgs reads the extender request body
+func decodeExtenderArgs(r *http.Request) (*scheduler.ExtenderArgs, error) {
+	if r.Method != http.MethodPost {
+		return nil, fmt.Errorf("method %s not allowed", r.Method)
+	}
+
+	var args scheduler.ExtenderArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		return nil, fmt.Errorf("failed to decode extender args: %w", err)
+	}
+	if args.Pod == nil {
+		return nil, fmt.Errorf("extender args contain no pod")
+	}
+	return &args, nil
+}
+
+// extenderNodes returns full node objects for the candidates in the request
+func (s *Server) extenderNodes(ctx context.Context, args *scheduler.ExtenderArgs) ([]corev1.Node, error) {
+	if args.Nodes != nil {
+		return args.Nodes.Items, nil
+	}
+	if args.NodeNames == nil {
+		return nil, nil
+	}
+
+	nodes := make([]corev1.Node, 0, len(*args.NodeNames))
+	for _, name := range *args.NodeNames {
+		var node corev1.Node
+		if err := s.Client.Get(ctx, types.NamespacedName{Name: name}, &node); err != nil {
+			return nil, fmt.Errorf("failed to get node %s: %w", name, err)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
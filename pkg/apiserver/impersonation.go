@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SubmittedByAnnotation records the end user a workload was submitted on behalf of
+	SubmittedByAnnotation = "kcloud.io/submitted-by"
+	// SubmittedByGroupsAnnotation records the groups of the end user
+	SubmittedByGroupsAnnotation = "kcloud.io/submitted-by-groups"
+)
+
+//+kubebuilder:rbac:groups="",resources=users;groups;serviceaccounts,verbs=impersonate
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=userextras;uids,verbs=impersonate
+
+// Identity is the end-user identity a request is made on behalf of
+type Identity struct {
+	User   string
+	UID    string
+	Groups []string
+	Extra  map[string][]string
+}
+
+// ImpersonationPolicy limits which identities the API server may impersonate
+type ImpersonationPolicy struct {
+	// Enabled allows impersonation headers to be honored for callers authorized to impersonate
+	Enabled bool
+	// AllowedGroups restricts impersonation to users in at least one of these groups (empty allows any)
+	AllowedGroups []string
+	// DeniedUserPrefixes rejects impersonation of users with these prefixes
+	DeniedUserPrefixes []string
+	// DeniedGroups rejects impersonation into these groups
+	DeniedGroups []string
+}
+
+// DefaultImpersonationPolicy returns a policy that refuses privileged system identities
+func DefaultImpersonationPolicy() ImpersonationPolicy {
+	return ImpersonationPolicy{
+		Enabled:            true,
+		DeniedUserPrefixes: []string{"system:"},
+		DeniedGroups:       []string{"system:masters", "system:nodes"},
+	}
+}
+
+// identityFromRequest returns the identity a request acts as: the authenticated caller, or
+// the user named in impersonation headers when the policy permits it and the caller may
+// impersonate that user, its groups and extra fields, as kube-apiserver requires
+func (s *Server) identityFromRequest(r *http.Request) (*Identity, error) {
+	caller := callerFrom(r.Context())
+	if caller == nil {
+		return nil, errUnauthenticated
+	}
+
+	user := r.Header.Get(authenticationv1.ImpersonateUserHeader)
+	groups := r.Header.Values(authenticationv1.ImpersonateGroupHeader)
+	extra := map[string][]string{}
+	for key, values := range r.Header {
+		if strings.HasPrefix(key, authenticationv1.ImpersonateUserExtraHeaderPrefix) {
+			extraKey := strings.ToLower(strings.TrimPrefix(key, authenticationv1.ImpersonateUserExtraHeaderPrefix))
+			extra[extraKey] = values
+		}
+	}
+	if user == "" {
+		if len(groups) > 0 || len(extra) > 0 {
+			return nil, errors.New("impersonating groups or extra fields requires impersonating a user")
+		}
+		return caller, nil
+	}
+
+	identity := &Identity{User: user, Groups: groups, Extra: extra}
+	if err := s.Impersonation.Validate(identity); err != nil {
+		return nil, err
+	}
+
+	ctx := r.Context()
+	if err := s.authorize(ctx, caller, authorizationv1.ResourceAttributes{
+		Verb: "impersonate", Resource: "users", Name: user,
+	}); err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if err := s.authorize(ctx, caller, authorizationv1.ResourceAttributes{
+			Verb: "impersonate", Resource: "groups", Name: group,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for key, values := range extra {
+		for _, value := range values {
+			if err := s.authorize(ctx, caller, authorizationv1.ResourceAttributes{
+				Verb:        "impersonate",
+				Group:       authenticationv1.SchemeGroupVersion.Group,
+				Resource:    "userextras",
+				Subresource: key,
+				Name:        value,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return identity, nil
+}
+
+// Validate checks an identity against the policy
+func (p ImpersonationPolicy) Validate(identity *Identity) error {
+	if !p.Enabled {
+		return fmt.Errorf("impersonation is disabled")
+	}
+
+	for _, prefix := range p.DeniedUserPrefixes {
+		if strings.HasPrefix(identity.User, prefix) {
+			return fmt.Errorf("impersonation of user %q is not allowed", identity.User)
+		}
+	}
+
+	for _, group := range identity.Groups {
+		for _, denied := range p.DeniedGroups {
+			if group == denied {
+				return fmt.Errorf("impersonation of group %q is not allowed", group)
+			}
+		}
+	}
+
+	if len(p.AllowedGroups) == 0 {
+		return nil
+	}
+	for _, group := range identity.Groups {
+		for _, allowed := range p.AllowedGroups {
+			if group == allowed {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("user %q is not in an allowed group", identity.User)
+}
+
+// clientFor returns a client acting as the given identity, so the cluster authorizes every
+// request with the caller's own permissions rather than the operator's
+func (s *Server) clientFor(identity *Identity) (client.Client, error) {
+	if identity == nil {
+		return nil, errUnauthenticated
+	}
+
+	config := rest.CopyConfig(s.Config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: identity.User,
+		UID:      identity.UID,
+		Groups:   identity.Groups,
+		Extra:    identity.Extra,
+	}
+
+	c, err := client.New(config, client.Options{Scheme: s.Scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonating client: %w", err)
+	}
+	return c, nil
+}
+
+// annotate records the identity on an object's annotations for audit and cost attribution
+func (identity *Identity) annotate(annotations map[string]string) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[SubmittedByAnnotation] = identity.User
+	if len(identity.Groups) > 0 {
+		annotations[SubmittedByGroupsAnnotation] = strings.Join(identity.Groups, ",")
+	}
+	return annotations
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+// offerRequest offers the capacity of a workload to other teams
+type offerRequest struct {
+	Namespace    string  `json:"namespace"`
+	Workload     string  `json:"workload"`
+	PricePerHour float64 `json:"pricePerHour"`
+	// TTL is how long the offer stays up, as a duration such as 8h; it never expires when empty
+	TTL string `json:"ttl,omitempty"`
+}
+
+// claimRequest claims an offer for a team
+type claimRequest struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Team      string `json:"team"`
+}
+
+// releaseRequest withdraws an offer or ends the claim on it
+type releaseRequest struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// EnableMarketplace serves the capacity marketplace. GET /apis/v1/marketplace/offers lists
+// open offers to callers allowed to list CapacityOffers in every namespace. Teams are
+// namespaces, and callers act for a team when they may update its WorkloadOptimizers:
+// POST /apis/v1/marketplace/offers offers the capacity of one of the team's assigned
+// workloads, POST /apis/v1/marketplace/claims claims an offer for the team, and
+// POST /apis/v1/marketplace/releases withdraws an offer or ends a claim, for either the
+// offering or the claiming team.
+func (s *Server) EnableMarketplace(market *scheduler.Marketplace) {
+	s.mux.HandleFunc("/apis/v1/marketplace/offers", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.listOffers(w, r, market)
+		case http.MethodPost:
+			s.createOffer(w, r, market)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		}
+	})
+
+	s.mux.HandleFunc("/apis/v1/marketplace/claims", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		var request claimRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode claim: %w", err))
+			return
+		}
+		if request.Team == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("claim must name a team"))
+			return
+		}
+		if err := s.authorizeTeam(r.Context(), request.Team, ""); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+
+		offer, err := market.Claim(r.Context(), request.Namespace, request.Name, request.Team)
+		if err != nil {
+			writeError(w, marketplaceErrorStatus(err), err)
+			return
+		}
+		log.FromContext(r.Context()).WithName("audit").Info("Capacity offer claimed",
+			"namespace", offer.Namespace, "name", offer.Name, "team", request.Team, "caller", callerFrom(r.Context()).User)
+		writeJSON(w, http.StatusOK, offer)
+	})
+
+	s.mux.HandleFunc("/apis/v1/marketplace/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		var request releaseRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode release: %w", err))
+			return
+		}
+
+		offer := &kcloudv1alpha1.CapacityOffer{}
+		if err := s.Client.Get(r.Context(), client.ObjectKey{Namespace: request.Namespace, Name: request.Name}, offer); err != nil {
+			writeError(w, marketplaceErrorStatus(err), err)
+			return
+		}
+		err := s.authorizeTeam(r.Context(), offer.Namespace, "")
+		if err != nil && offer.Status.ClaimedBy != "" {
+			err = s.authorizeTeam(r.Context(), offer.Status.ClaimedBy, "")
+		}
+		if err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+
+		transfer, err := market.Release(r.Context(), offer.Namespace, offer.Name)
+		if err != nil {
+			writeError(w, marketplaceErrorStatus(err), err)
+			return
+		}
+		log.FromContext(r.Context()).WithName("audit").Info("Capacity offer released",
+			"namespace", offer.Namespace, "name", offer.Name, "caller", callerFrom(r.Context()).User)
+		if transfer == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, transfer)
+	})
+}
+
+// listOffers serves the open offers
+func (s *Server) listOffers(w http.ResponseWriter, r *http.Request, market *scheduler.Marketplace) {
+	err := s.authorize(r.Context(), callerFrom(r.Context()), authorizationv1.ResourceAttributes{
+		Group:    kcloudv1alpha1.GroupVersion.Group,
+		Resource: "capacityoffers",
+		Verb:     "list",
+	})
+	if err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	offers, err := market.OpenOffers(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, offers)
+}
+
+// createOffer offers the capacity a team's workload holds on its assigned node
+func (s *Server) createOffer(w http.ResponseWriter, r *http.Request, market *scheduler.Marketplace) {
+	ctx := r.Context()
+
+	var request offerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode offer: %w", err))
+		return
+	}
+	if request.Namespace == "" || request.Workload == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("offer must name a namespace and workload"))
+		return
+	}
+	var expiresAt *metav1.Time
+	if request.TTL != "" {
+		ttl, err := time.ParseDuration(request.TTL)
+		if err != nil || ttl <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid ttl %q", request.TTL))
+			return
+		}
+		expiresAt = &metav1.Time{Time: time.Now().Add(ttl)}
+	}
+	if err := s.authorizeTeam(ctx, request.Namespace, request.Workload); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	// The team can only offer capacity its own workload holds
+	wo := &kcloudv1alpha1.WorkloadOptimizer{}
+	if err := s.Client.Get(ctx, client.ObjectKey{Namespace: request.Namespace, Name: request.Workload}, wo); err != nil {
+		writeError(w, marketplaceErrorStatus(err), err)
+		return
+	}
+	if wo.Status.AssignedNode == nil || *wo.Status.AssignedNode == "" {
+		writeError(w, http.StatusConflict, fmt.Errorf("workload %s/%s is not assigned to a node", wo.Namespace, wo.Name))
+		return
+	}
+
+	offer := &kcloudv1alpha1.CapacityOffer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: wo.Namespace},
+		Spec: kcloudv1alpha1.CapacityOfferSpec{
+			Workload:     wo.Name,
+			NodeName:     *wo.Status.AssignedNode,
+			CPU:          wo.Spec.Resources.CPU,
+			Memory:       wo.Spec.Resources.Memory,
+			GPU:          wo.Spec.Resources.GPU,
+			NPU:          wo.Spec.Resources.NPU,
+			PricePerHour: request.PricePerHour,
+			ExpiresAt:    expiresAt,
+		},
+	}
+	if err := market.Offer(ctx, offer); err != nil {
+		writeError(w, marketplaceErrorStatus(err), err)
+		return
+	}
+	log.FromContext(ctx).WithName("audit").Info("Capacity offered",
+		"namespace", offer.Namespace, "name", offer.Name, "workload", wo.Name, "caller", callerFrom(ctx).User)
+	writeJSON(w, http.StatusCreated, offer)
+}
+
+// authorizeTeam checks with a SubjectAccessReview that the caller acts for a team, by
+// being allowed to update its WorkloadOptimizers, or the named one
+func (s *Server) authorizeTeam(ctx context.Context, namespace, workload string) error {
+	return s.authorize(ctx, callerFrom(ctx), authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Group:     kcloudv1alpha1.GroupVersion.Group,
+		Resource:  "workloadoptimizers",
+		Name:      workload,
+		Verb:      "update",
+	})
+}
+
+// marketplaceErrorStatus returns the status of a failed marketplace request
+func marketplaceErrorStatus(err error) int {
+	switch {
+	case apierrors.IsNotFound(err):
+		return http.StatusNotFound
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return http.StatusConflict
+	case apierrors.IsForbidden(err):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
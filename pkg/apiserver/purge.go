@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/retention"
+)
+
+// Purger removes records selected by a retention filter
+type Purger interface {
+	Purge(ctx context.Context, request retention.Request) (*retention.Result, error)
+}
+
+// EnablePurge serves POST /apis/v1/admin/purge, which removes history and journal records
+// of a namespace, a workload or a time range. Callers must identify themselves with
+// impersonation headers and be allowed to deletecollection WorkloadOptimizers in the
+// namespace, or cluster-wide when the filter has none.
+func (s *Server) EnablePurge(purger Purger) {
+	s.mux.HandleFunc("/apis/v1/admin/purge", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		auditLog := log.FromContext(ctx).WithName("audit")
+
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+
+		// requestedBy is never taken from the body; the audit trail names the authenticated caller
+		var body struct {
+			Filter retention.Filter `json:"filter"`
+			Reason string           `json:"reason,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode purge request: %w", err))
+			return
+		}
+		if err := body.Filter.Validate(); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		identity := callerFrom(ctx)
+		request := retention.Request{Filter: body.Filter, RequestedBy: identity.User, Reason: body.Reason}
+		if err := s.authorizePurge(ctx, identity, request.Filter); err != nil {
+			auditLog.Info("Rejected purge",
+				"namespace", request.Filter.Namespace,
+				"workload", request.Filter.Workload,
+				"requestedBy", identity.User,
+				"reason", err.Error())
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+
+		auditLog.Info("Purge requested",
+			"namespace", request.Filter.Namespace,
+			"workload", request.Filter.Workload,
+			"from", request.Filter.From,
+			"to", request.Filter.To,
+			"requestedBy", identity.User,
+			"reason", request.Reason,
+			"remoteAddr", r.RemoteAddr)
+		result, err := purger.Purge(ctx, request)
+		if err != nil {
+			if result == nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			// Some stores were purged; report them along with the failures
+			writeJSON(w, http.StatusInternalServerError, result)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// authorizePurge checks with a SubjectAccessReview that the caller may delete every
+// workload the filter covers
+func (s *Server) authorizePurge(ctx context.Context, identity *Identity, filter retention.Filter) error {
+	return s.authorize(ctx, identity, authorizationv1.ResourceAttributes{
+		Namespace: filter.Namespace,
+		Verb:      "deletecollection",
+		Group:     kcloudv1alpha1.GroupVersion.Group,
+		Resource:  "workloadoptimizers",
+	})
+}
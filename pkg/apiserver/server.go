@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Server exposes the operator's REST API for workload submission and queries
+type Server struct {
+	// Client is the operator's own client, used for read-only queries
+	Client client.Client
+	// Config is the base REST config used to build impersonating clients
+	Config *rest.Config
+	// Scheme is used to construct impersonating clients
+	Scheme *runtime.Scheme
+	// Impersonation controls which identities may be impersonated
+	Impersonation ImpersonationPolicy
+	// CertDir holds the serving certificate; a self-signed certificate is generated when empty
+	CertDir string
+	// CertName is the serving certificate file in CertDir
+	CertName string
+	// KeyName is the serving key file in CertDir
+	KeyName string
+	// ClientCAFile, if set, authenticates callers presenting a client certificate signed by
+	// this CA, such as kube-scheduler calling the extender endpoints
+	ClientCAFile string
+	// TLSOpts customize the serving TLS configuration
+	TLSOpts []func(*tls.Config)
+
+	addr string
+	mux  *http.ServeMux
+}
+
+// NewServer creates a new API server listening on addr
+func NewServer(addr string, c client.Client, config *rest.Config, scheme *runtime.Scheme) *Server {
+	s := &Server{
+		Client:        c,
+		Config:        config,
+		Scheme:        scheme,
+		Impersonation: DefaultImpersonationPolicy(),
+		CertName:      "tls.crt",
+		KeyName:       "tls.key",
+		addr:          addr,
+		mux:           http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/apis/v1/workloads", s.handleWorkloads)
+
+	return s
+}
+
+// Handle registers an additional handler on the API server
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start serves the API over TLS until the context is cancelled. Every request must be
+// authenticated with a client certificate or a bearer token.
+func (s *Server) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("apiserver")
+
+	tlsConfig, err := s.tlsConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.authenticated(s.mux),
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "Failed to shut down API server")
+		}
+	}()
+
+	log.Info("Starting API server", "address", s.addr)
+	if err := srv.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("API server failed: %w", err)
+	}
+	return nil
+}
+
+// tlsConfig loads the serving certificate, watching CertDir for rotation, and the client CA
+func (s *Server) tlsConfig(ctx context.Context) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if s.CertDir != "" {
+		watcher, err := certwatcher.New(filepath.Join(s.CertDir, s.CertName), filepath.Join(s.CertDir, s.KeyName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load API server certificate: %w", err)
+		}
+		go func() {
+			if err := watcher.Start(ctx); err != nil {
+				log.FromContext(ctx).Error(err, "API server certificate watcher stopped")
+			}
+		}()
+		config.GetCertificate = watcher.GetCertificate
+	} else {
+		certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("k8s-workload-operator-api", nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate API server certificate: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load API server certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if s.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(s.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in client CA %s", s.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	for _, opt := range s.TLSOpts {
+		opt(config)
+	}
+	return config, nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// Simulator evaluates hypothetical workloads against the current cluster
+type Simulator interface {
+	Simulate(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) (*optimizer.SimulationResult, error)
+}
+
+// EnableSimulation serves what-if evaluations of WorkloadOptimizers posted to the API.
+// Nothing is created.
+func (s *Server) EnableSimulation(simulator Simulator) {
+	s.mux.HandleFunc("/apis/v1/simulations", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+
+		var wo kcloudv1alpha1.WorkloadOptimizer
+		if err := json.NewDecoder(r.Body).Decode(&wo); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode WorkloadOptimizer: %w", err))
+			return
+		}
+
+		result, err := simulator.Simulate(r.Context(), &wo)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, optimizer.ErrSimulationUnavailable) {
+				status = http.StatusServiceUnavailable
+			}
+			writeError(w, status, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
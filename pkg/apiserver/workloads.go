@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// handleWorkloads accepts WorkloadOptimizer submissions as the caller, or on behalf of a user it may impersonate
+func (s *Server) handleWorkloads(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	auditLog := log.FromContext(ctx).WithName("audit")
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	var wo kcloudv1alpha1.WorkloadOptimizer
+	if err := json.NewDecoder(r.Body).Decode(&wo); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode WorkloadOptimizer: %w", err))
+		return
+	}
+
+	identity, err := s.identityFromRequest(r)
+	if err != nil {
+		auditLog.Info("Rejected impersonated submission",
+			"namespace", wo.Namespace,
+			"name", wo.Name,
+			"caller", callerFrom(ctx).User,
+			"reason", err.Error())
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+	c, err := s.clientFor(identity)
+	if err != nil {
+		auditLog.Info("Rejected submission",
+			"namespace", wo.Namespace,
+			"name", wo.Name,
+			"reason", err.Error())
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	wo.Annotations = identity.annotate(wo.Annotations)
+
+	if err := c.Create(ctx, &wo); err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsForbidden(err) {
+			status = http.StatusForbidden
+		} else if apierrors.IsAlreadyExists(err) {
+			status = http.StatusConflict
+		} else if apierrors.IsInvalid(err) {
+			status = http.StatusUnprocessableEntity
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	auditLog.Info("Workload submitted",
+		"namespace", wo.Namespace,
+		"name", wo.Name,
+		"workloadType", wo.Spec.WorkloadType,
+		"submittedBy", identity.User,
+		"caller", callerFrom(ctx).User,
+		"remoteAddr", r.RemoteAddr)
+
+	writeJSON(w, http.StatusCreated, &wo)
+}
@@ -0,0 +1,593 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chargeback accrues the realized cost and energy of workloads by the hour so
+// platform teams can charge them back to namespaces, teams and projects.
+package chargeback
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/retention"
+)
+
+const (
+	// DefaultInterval is how often workload cost and power are sampled
+	DefaultInterval = 5 * time.Minute
+	// DefaultRetention is how long hourly usage is kept
+	DefaultRetention = 90 * 24 * time.Hour
+
+	// GroupByNamespace, GroupByWorkload and GroupByLabelPrefix select how a report is grouped;
+	// the prefix is followed by a label key such as team
+	GroupByNamespace   = "namespace"
+	GroupByWorkload    = "workload"
+	GroupByLabelPrefix = "label:"
+
+	// targetName names the ledger in purge results
+	targetName = "chargeback"
+)
+
+// ErrNotServing is returned by reports on replicas that are not the leader; only the leader
+// samples usage and holds the ledger
+var ErrNotServing = errors.New("chargeback reports are served by the leader replica only")
+
+// workloadKey identifies a WorkloadOptimizer
+type workloadKey struct {
+	namespace string
+	name      string
+}
+
+// usage is the realized cost in the reporting currency and energy in kWh of a workload in
+// one hour
+type usage struct {
+	cost   float64
+	energy float64
+}
+
+// account is the hourly usage of a workload and the labels it was last seen with
+type account struct {
+	labels labels.Set
+	hours  map[time.Time]*usage
+}
+
+// dayKey identifies the ChargebackRecord of a workload's UTC day
+type dayKey struct {
+	workloadKey
+	day time.Time
+}
+
+// +kubebuilder:rbac:groups=kcloud.io,resources=workloadoptimizers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kcloud.io,resources=chargebackrecords,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Ledger samples the current cost and power of every workload and accrues them as realized
+// cost and energy in hourly buckets. A workload's labels are its own over those of its
+// namespace, so teams may be labeled on either. The ledger runs on the leader, which loads
+// the usage persisted as ChargebackRecords, one per workload and UTC day, when it starts
+// and writes the days that changed after every sample.
+type Ledger struct {
+	client    client.Client
+	interval  time.Duration
+	retention time.Duration
+	// currency is the reporting currency workload costs are in; nil is US dollars
+	currency *currency.Converter
+
+	mu       sync.Mutex
+	clock    clock.PassiveClock
+	sampled  time.Time
+	accounts map[workloadKey]*account
+	// dirty are the days whose usage changed since they were last persisted
+	dirty map[dayKey]bool
+	// serving is set once the persisted usage was loaded on the leader
+	serving bool
+}
+
+// NewLedger returns a ledger sampling every interval and keeping usage for the retention
+func NewLedger(c client.Client, interval, retention time.Duration) *Ledger {
+	return &Ledger{
+		client:    c,
+		interval:  interval,
+		retention: retention,
+		clock:     clock.RealClock{},
+		accounts:  make(map[workloadKey]*account),
+		dirty:     make(map[dayKey]bool),
+	}
+}
+
+// SetCurrency sets the reporting currency workload costs, and so reports, are in
+func (l *Ledger) SetCurrency(converter *currency.Converter) {
+	l.currency = converter
+}
+
+// SetClock replaces the clock samples are timed with
+func (l *Ledger) SetClock(c clock.PassiveClock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = c
+}
+
+// Observe accrues each workload's current cost and power over the time since the previous
+// sample, or one interval for the first, and drops usage older than the retention
+func (l *Ledger) Observe(workloads []kcloudv1alpha1.WorkloadOptimizer, namespaceLabels map[string]labels.Set) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now().UTC()
+	from := now.Add(-l.interval)
+	if !l.sampled.IsZero() {
+		from = l.sampled
+	}
+	l.sampled = now
+
+	for _, wo := range workloads {
+		key := workloadKey{namespace: wo.Namespace, name: wo.Name}
+		acct, ok := l.accounts[key]
+		if !ok {
+			acct = &account{hours: make(map[time.Time]*usage)}
+			l.accounts[key] = acct
+		}
+		acct.labels = labels.Merge(namespaceLabels[wo.Namespace], wo.Labels)
+
+		var cost, power float64
+		if wo.Status.CurrentCost != nil {
+			cost = *wo.Status.CurrentCost
+		}
+		// Energy includes the datacenter overhead when the workload's pool has a PUE
+		if wo.Status.FacilityPower != nil {
+			power = *wo.Status.FacilityPower
+		} else if wo.Status.CurrentPower != nil {
+			power = *wo.Status.CurrentPower
+		}
+		if cost > 0 || power > 0 {
+			for _, hour := range acct.accrue(from, now, cost, power) {
+				l.dirty[dayKey{workloadKey: key, day: utcDay(hour)}] = true
+			}
+		}
+	}
+
+	cutoff := now.Add(-l.retention).Truncate(time.Hour)
+	for key, acct := range l.accounts {
+		for hour := range acct.hours {
+			if hour.Before(cutoff) {
+				delete(acct.hours, hour)
+			}
+		}
+		if len(acct.hours) == 0 {
+			delete(l.accounts, key)
+		}
+	}
+}
+
+// accrue adds the hourly cost and power in Watts over the period, split at hour boundaries,
+// and returns the hours it added to
+func (a *account) accrue(from, to time.Time, cost, power float64) []time.Time {
+	var hours []time.Time
+	for start := from; start.Before(to); {
+		hour := start.Truncate(time.Hour)
+		end := hour.Add(time.Hour)
+		if end.After(to) {
+			end = to
+		}
+		share := end.Sub(start).Hours()
+		bucket, ok := a.hours[hour]
+		if !ok {
+			bucket = &usage{}
+			a.hours[hour] = bucket
+		}
+		bucket.cost += cost * share
+		bucket.energy += power * share / 1000
+		hours = append(hours, hour)
+		start = end
+	}
+	return hours
+}
+
+// Query selects and groups the usage of a report
+type Query struct {
+	// GroupBy is namespace, workload or label:<key>; it defaults to namespace
+	GroupBy string
+	// From and To bound the report; usage is counted by the hour it accrued in
+	From time.Time
+	To   time.Time
+	// Namespace limits the report to one namespace when set
+	Namespace string
+	// Selector limits the report to workloads whose labels match; nil matches all
+	Selector labels.Selector
+}
+
+// Row is the usage of one group
+type Row struct {
+	// Group is the namespace, namespace/name of the workload, or label value; workloads
+	// without the label are grouped under an empty value
+	Group     string  `json:"group"`
+	Cost      float64 `json:"cost"`
+	EnergyKWh float64 `json:"energyKWh"`
+	Workloads int     `json:"workloads"`
+}
+
+// Report is the realized usage in a time range, grouped and sorted by cost, highest first
+type Report struct {
+	GroupBy        string            `json:"groupBy"`
+	From           time.Time         `json:"from"`
+	To             time.Time         `json:"to"`
+	Currency       currency.Currency `json:"currency"`
+	Rows           []Row             `json:"rows"`
+	TotalCost      float64           `json:"totalCost"`
+	TotalEnergyKWh float64           `json:"totalEnergyKWh"`
+}
+
+// Converted returns a copy of the report with its costs converted from the reporting
+// currency into another at the converter's current rates
+func (r *Report) Converted(converter *currency.Converter, to currency.Currency) (*Report, error) {
+	rate, err := converter.Convert(1, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert report into %s: %w", to, err)
+	}
+	converted := *r
+	converted.Currency = to
+	converted.Rows = slices.Clone(r.Rows)
+	for i := range converted.Rows {
+		converted.Rows[i].Cost = to.Round(converted.Rows[i].Cost * rate)
+	}
+	converted.TotalCost = to.Round(r.TotalCost * rate)
+	return &converted, nil
+}
+
+// Report rolls up the usage that accrued in the hours from the query's From to its To
+func (l *Ledger) Report(query Query) (*Report, error) {
+	if query.GroupBy == "" {
+		query.GroupBy = GroupByNamespace
+	}
+	labelKey, byLabel := strings.CutPrefix(query.GroupBy, GroupByLabelPrefix)
+	if byLabel && labelKey == "" {
+		return nil, errors.New("group by label requires a label key")
+	}
+	if !byLabel && query.GroupBy != GroupByNamespace && query.GroupBy != GroupByWorkload {
+		return nil, fmt.Errorf("unknown grouping %q; use namespace, workload or label:<key>", query.GroupBy)
+	}
+	if !query.To.After(query.From) {
+		return nil, fmt.Errorf("report end %s is not after its start %s",
+			query.To.Format(time.RFC3339), query.From.Format(time.RFC3339))
+	}
+	from := query.From.Truncate(time.Hour)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.serving {
+		return nil, ErrNotServing
+	}
+
+	rows := make(map[string]*Row)
+	for key, acct := range l.accounts {
+		if query.Namespace != "" && key.namespace != query.Namespace {
+			continue
+		}
+		if query.Selector != nil && !query.Selector.Matches(acct.labels) {
+			continue
+		}
+		var total usage
+		for hour, bucket := range acct.hours {
+			if !hour.Before(from) && hour.Before(query.To) {
+				total.cost += bucket.cost
+				total.energy += bucket.energy
+			}
+		}
+		if total.cost == 0 && total.energy == 0 {
+			continue
+		}
+
+		group := key.namespace
+		switch {
+		case byLabel:
+			group = acct.labels[labelKey]
+		case query.GroupBy == GroupByWorkload:
+			group = key.namespace + "/" + key.name
+		}
+		row, ok := rows[group]
+		if !ok {
+			row = &Row{Group: group}
+			rows[group] = row
+		}
+		row.Cost += total.cost
+		row.EnergyKWh += total.energy
+		row.Workloads++
+	}
+
+	money := l.currency.Currency()
+	report := &Report{GroupBy: query.GroupBy, From: from, To: query.To, Currency: money, Rows: make([]Row, 0, len(rows))}
+	for _, row := range rows {
+		report.TotalCost += row.Cost
+		report.TotalEnergyKWh += row.EnergyKWh
+		row.Cost, row.EnergyKWh = money.Round(row.Cost), roundWattHours(row.EnergyKWh)
+		report.Rows = append(report.Rows, *row)
+	}
+	report.TotalCost, report.TotalEnergyKWh = money.Round(report.TotalCost), roundWattHours(report.TotalEnergyKWh)
+	slices.SortFunc(report.Rows, func(a, b Row) int {
+		return cmp.Or(cmp.Compare(b.Cost, a.Cost), cmp.Compare(a.Group, b.Group))
+	})
+	return report, nil
+}
+
+// Name identifies the ledger in purge results
+func (l *Ledger) Name() string {
+	return targetName
+}
+
+// Purge removes the hourly usage the filter selects, by the start of each hour, and
+// rewrites the records of the days it was removed from
+func (l *Ledger) Purge(ctx context.Context, filter retention.Filter) (int, error) {
+	l.mu.Lock()
+	if !l.serving {
+		l.mu.Unlock()
+		return 0, ErrNotServing
+	}
+	purged := 0
+	for key, acct := range l.accounts {
+		for hour := range acct.hours {
+			if filter.Matches(key.namespace, key.name, hour) {
+				delete(acct.hours, hour)
+				l.dirty[dayKey{workloadKey: key, day: utcDay(hour)}] = true
+				purged++
+			}
+		}
+		if len(acct.hours) == 0 {
+			delete(l.accounts, key)
+		}
+	}
+	l.mu.Unlock()
+	return purged, l.persist(ctx)
+}
+
+// Load fills the ledger with the usage persisted within the retention. Records in another
+// currency than the reporting currency are skipped.
+func (l *Ledger) Load(ctx context.Context) error {
+	var records kcloudv1alpha1.ChargebackRecordList
+	if err := l.client.List(ctx, &records); err != nil {
+		return fmt.Errorf("failed to list chargeback records: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	money := string(l.currency.Currency())
+	cutoff := l.clock.Now().UTC().Add(-l.retention).Truncate(time.Hour)
+	// The day the labels of each account were taken from, so the latest record's win
+	labeled := make(map[workloadKey]time.Time)
+	skipped := 0
+	for _, record := range records.Items {
+		if record.Spec.Currency != money {
+			skipped++
+			continue
+		}
+		key := workloadKey{namespace: record.Namespace, name: record.Spec.Workload}
+		acct, ok := l.accounts[key]
+		if !ok {
+			acct = &account{hours: make(map[time.Time]*usage)}
+			l.accounts[key] = acct
+		}
+		day := record.Spec.Day.UTC()
+		if day.After(labeled[key]) || acct.labels == nil {
+			acct.labels, labeled[key] = labels.Set(record.Spec.Labels), day
+		}
+		for _, hour := range record.Spec.Hours {
+			start := day.Add(time.Duration(hour.Hour) * time.Hour)
+			if !start.Before(cutoff) {
+				acct.hours[start] = &usage{cost: hour.Cost, energy: hour.EnergyKWh}
+			}
+		}
+		if len(acct.hours) == 0 {
+			delete(l.accounts, key)
+		}
+	}
+	if skipped > 0 {
+		log.FromContext(ctx).Info("Skipped chargeback records in another currency", "records", skipped,
+			"currency", money)
+	}
+	return nil
+}
+
+// persist writes the days whose usage changed to their ChargebackRecords and deletes the
+// records of days left without usage. Days that fail to be written are retried on the
+// next call.
+func (l *Ledger) persist(ctx context.Context) error {
+	l.mu.Lock()
+	money := string(l.currency.Currency())
+	specs := make(map[dayKey]*kcloudv1alpha1.ChargebackRecordSpec, len(l.dirty))
+	for key := range l.dirty {
+		specs[key] = l.recordSpec(key, money)
+	}
+	clear(l.dirty)
+	l.mu.Unlock()
+
+	var errs []error
+	for key, spec := range specs {
+		if err := l.writeRecord(ctx, key, spec); err != nil {
+			errs = append(errs, err)
+			l.mu.Lock()
+			l.dirty[key] = true
+			l.mu.Unlock()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recordSpec returns the usage of the day as a record, nil when it has none
+func (l *Ledger) recordSpec(key dayKey, money string) *kcloudv1alpha1.ChargebackRecordSpec {
+	acct, ok := l.accounts[key.workloadKey]
+	if !ok {
+		return nil
+	}
+	spec := &kcloudv1alpha1.ChargebackRecordSpec{
+		Workload: key.name,
+		Day:      metav1.NewTime(key.day),
+		Currency: money,
+		Labels:   acct.labels,
+	}
+	for hour, bucket := range acct.hours {
+		if utcDay(hour).Equal(key.day) {
+			spec.Hours = append(spec.Hours, kcloudv1alpha1.ChargebackHour{
+				Hour:      int32(hour.Sub(key.day) / time.Hour),
+				Cost:      bucket.cost,
+				EnergyKWh: bucket.energy,
+			})
+		}
+	}
+	if len(spec.Hours) == 0 {
+		return nil
+	}
+	slices.SortFunc(spec.Hours, func(a, b kcloudv1alpha1.ChargebackHour) int { return cmp.Compare(a.Hour, b.Hour) })
+	return spec
+}
+
+// writeRecord creates or updates the day's record, or deletes it when spec is nil
+func (l *Ledger) writeRecord(ctx context.Context, key dayKey, spec *kcloudv1alpha1.ChargebackRecordSpec) error {
+	record := &kcloudv1alpha1.ChargebackRecord{ObjectMeta: metav1.ObjectMeta{
+		Namespace: key.namespace,
+		Name:      recordName(key.name, key.day),
+	}}
+	if spec == nil {
+		if err := l.client.Delete(ctx, record); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete chargeback record %s/%s: %w", record.Namespace, record.Name, err)
+		}
+		return nil
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, l.client, record, func() error {
+		record.Spec = *spec
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write chargeback record %s/%s: %w", record.Namespace, record.Name, err)
+	}
+	return nil
+}
+
+// pruneRecords deletes the records of days that ended before the retention
+func (l *Ledger) pruneRecords(ctx context.Context) error {
+	var records kcloudv1alpha1.ChargebackRecordList
+	if err := l.client.List(ctx, &records); err != nil {
+		return fmt.Errorf("failed to list chargeback records: %w", err)
+	}
+	cutoff := l.clock.Now().UTC().Add(-l.retention)
+	var errs []error
+	for i := range records.Items {
+		record := &records.Items[i]
+		if record.Spec.Day.Add(24 * time.Hour).After(cutoff) {
+			continue
+		}
+		if err := l.client.Delete(ctx, record); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed to delete chargeback record %s/%s: %w", record.Namespace, record.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recordName names the record of a workload's day, such as web-20240131; names too long
+// for an object name are shortened and suffixed with a hash of the workload name
+func recordName(workload string, day time.Time) string {
+	suffix := "-" + day.Format("20060102")
+	if len(workload)+len(suffix) <= validation.DNS1123SubdomainMaxLength {
+		return workload + suffix
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(workload))
+	hashed := fmt.Sprintf("-%08x", hash.Sum32())
+	return strings.TrimRight(workload[:validation.DNS1123SubdomainMaxLength-len(suffix)-len(hashed)], "-.") + hashed + suffix
+}
+
+// utcDay returns the start of the UTC day of the time
+func utcDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Refresh samples every workload with the labels of its namespace, persists the days that
+// changed and deletes expired records
+func (l *Ledger) Refresh(ctx context.Context) error {
+	var workloads kcloudv1alpha1.WorkloadOptimizerList
+	if err := l.client.List(ctx, &workloads); err != nil {
+		return fmt.Errorf("failed to list workload optimizers: %w", err)
+	}
+	var namespaces corev1.NamespaceList
+	if err := l.client.List(ctx, &namespaces); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaceLabels := make(map[string]labels.Set, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		namespaceLabels[namespace.Name] = namespace.Labels
+	}
+	l.Observe(workloads.Items, namespaceLabels)
+	return errors.Join(l.persist(ctx), l.pruneRecords(ctx))
+}
+
+// Start loads the persisted usage, then samples every interval until the context is
+// cancelled. Reports are served from when the usage is loaded.
+func (l *Ledger) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("chargeback")
+
+	if err := l.Load(ctx); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.serving = true
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.serving = false
+		l.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := l.Refresh(ctx); err != nil {
+				log.Error(err, "Failed to sample workload usage")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection samples and serves reports on the leader only, so each sample is
+// accrued and persisted once
+func (l *Ledger) NeedLeaderElection() bool {
+	return true
+}
+
+// roundWattHours rounds energy in kWh to whole Watt-hours
+func roundWattHours(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
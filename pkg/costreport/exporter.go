@@ -0,0 +1,234 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costreport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// checkInterval is how often the exporter looks for a period that has ended
+	checkInterval = 15 * time.Minute
+
+	// defaultUploadTimeout bounds uploading one report
+	defaultUploadTimeout = time.Minute
+)
+
+// Period is how long each scheduled report covers
+type Period string
+
+const (
+	PeriodDaily   Period = "daily"
+	PeriodWeekly  Period = "weekly"
+	PeriodMonthly Period = "monthly"
+)
+
+// ParsePeriod returns the period of its name
+func ParsePeriod(name string) (Period, error) {
+	switch period := Period(name); period {
+	case PeriodDaily, PeriodWeekly, PeriodMonthly:
+		return period, nil
+	}
+	return "", fmt.Errorf("unknown report period %q; use daily, weekly or monthly", name)
+}
+
+// Last returns the bounds of the latest period to have ended by t, in UTC. Weeks start on
+// Monday.
+func (p Period) Last(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case PeriodWeekly:
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return monday.AddDate(0, 0, -7), monday
+	case PeriodMonthly:
+		first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return first.AddDate(0, -1, 0), first
+	}
+	return today.AddDate(0, 0, -1), today
+}
+
+// Name names the period starting at from, as in 2026-10-17, 2026-W42 or 2026-10
+func (p Period) Name(from time.Time) string {
+	switch p {
+	case PeriodWeekly:
+		year, week := from.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonthly:
+		return from.Format("2006-01")
+	}
+	return from.Format("2006-01-02")
+}
+
+// Sink stores report files
+type Sink interface {
+	Store(ctx context.Context, name string, body []byte) error
+}
+
+// DirectorySink writes report files into a directory, such as a mounted volume
+type DirectorySink struct {
+	dir string
+}
+
+// NewDirectorySink returns a sink writing into the directory, creating it if needed
+func NewDirectorySink(dir string) (*DirectorySink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	}
+	return &DirectorySink{dir: dir}, nil
+}
+
+// Store writes the file through a temporary file, so readers never see it half written
+func (s *DirectorySink) Store(_ context.Context, name string, body []byte) error {
+	target := filepath.Join(s.dir, name)
+	temp := target + ".tmp"
+	if err := os.WriteFile(temp, body, 0o644); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", name, err)
+	}
+	if err := os.Rename(temp, target); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", name, err)
+	}
+	return nil
+}
+
+// HTTPSink uploads report files to an object store with HTTP PUT requests, to the base URL
+// followed by the file name. The base URL's query, such as a shared access signature, is
+// kept on every upload.
+type HTTPSink struct {
+	base   *url.URL
+	client *http.Client
+}
+
+// NewHTTPSink returns a sink uploading under the base URL
+func NewHTTPSink(baseURL string) (*HTTPSink, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid report upload url %q", baseURL)
+	}
+	return &HTTPSink{base: parsed, client: &http.Client{Timeout: defaultUploadTimeout}}, nil
+}
+
+// Store uploads the file
+func (s *HTTPSink) Store(ctx context.Context, name string, body []byte) error {
+	target := *s.base
+	target.Path = path.Join("/", s.base.Path, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build report upload: %w", err)
+	}
+	req.Header.Set("Content-Type", Format(path.Ext(name)[1:]).ContentType())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload report %s: %w", name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("report upload of %s returned status %d", name, resp.StatusCode)
+	}
+	return nil
+}
+
+// Exporter writes a report in every format to its sinks each time a period ends
+type Exporter struct {
+	generator *Generator
+	period    Period
+	sinks     []Sink
+
+	clock clock.PassiveClock
+	// exported is the end of the latest period written to every sink
+	exported time.Time
+}
+
+// NewExporter returns an exporter writing the generator's reports for each period to the sinks
+func NewExporter(generator *Generator, period Period, sinks ...Sink) *Exporter {
+	return &Exporter{generator: generator, period: period, sinks: sinks, clock: clock.RealClock{}}
+}
+
+// SetClock replaces the clock periods are timed with
+func (e *Exporter) SetClock(c clock.PassiveClock) {
+	e.clock = c
+}
+
+// Export writes the report of the latest period to have ended, unless it was written
+// already. A period that failed to be written to any sink is written again on the next call.
+func (e *Exporter) Export(ctx context.Context) error {
+	from, to := e.period.Last(e.clock.Now())
+	if !to.After(e.exported) {
+		return nil
+	}
+	report, err := e.generator.Generate(from, to)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, format := range Formats {
+		var body bytes.Buffer
+		if err := report.Write(&body, format); err != nil {
+			return fmt.Errorf("failed to write %s report: %w", format, err)
+		}
+		name := fmt.Sprintf("cost-report-%s.%s", e.period.Name(from), format.Extension())
+		for _, sink := range e.sinks {
+			errs = append(errs, sink.Store(ctx, name, body.Bytes()))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	e.exported = to
+	log.FromContext(ctx).Info("Cost report exported", "period", e.period.Name(from), "rows", len(report.Rows))
+	return nil
+}
+
+// Start exports each period as it ends until the context is cancelled. Usage is kept in
+// memory from when the operator started, so periods that had ended by then are skipped
+// rather than overwritten with partial reports.
+func (e *Exporter) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("cost-report")
+	ctx = log.IntoContext(ctx, logger)
+	_, e.exported = e.period.Last(e.clock.Now())
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := e.Export(ctx); err != nil {
+				logger.Error(err, "Failed to export cost report")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection exports on the leader only, so each report is written once
+func (e *Exporter) NeedLeaderElection() bool {
+	return true
+}
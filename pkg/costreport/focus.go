@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costreport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// FOCUS values of the columns every row shares. Costs are charged for using the cluster's
+// capacity, so they are usage charges of the compute service.
+const (
+	focusProviderName     = "Kubernetes"
+	focusServiceName      = "Kubernetes"
+	focusServiceCategory  = "Compute"
+	focusChargeCategory   = "Usage"
+	focusChargeFrequency  = "Usage-Based"
+	focusDefaultCluster   = "kubernetes"
+	focusResourceWorkload = "WorkloadOptimizer"
+	focusResourceNode     = "Node"
+)
+
+// focusHeader names the columns of a FOCUS file: the FOCUS 1.0 columns the operator knows,
+// then the energy as an x_ extension column
+var focusHeader = []string{
+	"BillingAccountId", "BillingAccountName", "BillingCurrency",
+	"BillingPeriodStart", "BillingPeriodEnd", "ChargePeriodStart", "ChargePeriodEnd",
+	"BilledCost", "EffectiveCost", "ListCost", "ContractedCost",
+	"ChargeCategory", "ChargeClass", "ChargeDescription", "ChargeFrequency",
+	"InvoiceIssuerName", "ProviderName", "PublisherName",
+	"ServiceCategory", "ServiceName",
+	"ResourceId", "ResourceName", "ResourceType",
+	"SubAccountId", "SubAccountName", "Tags",
+	"x_EnergyKWh",
+}
+
+// focusCharge is a row of a FOCUS file
+type focusCharge struct {
+	description  string
+	resourceID   string
+	resourceName string
+	resourceType string
+	subAccount   string
+	cost         float64
+	energyKWh    float64
+	tags         map[string]string
+}
+
+// focusCharges returns the charges of the report without counting any cost twice. Workloads
+// are charged their cost under their namespace as the sub-account, and nodes their idle cost.
+// Namespace rows repeat the cost of their workloads and are left out, as are the costs nodes
+// allocated to pods, unless the report has no workload costs to charge instead.
+func (r *Report) focusCharges() []focusCharge {
+	chargesWorkloads := false
+	for _, row := range r.Rows {
+		if row.Dimension == DimensionWorkload {
+			chargesWorkloads = true
+			break
+		}
+	}
+
+	var charges []focusCharge
+	for _, row := range r.Rows {
+		switch row.Dimension {
+		case DimensionWorkload:
+			namespace, name, _ := strings.Cut(row.Group, "/")
+			charges = append(charges, focusCharge{
+				description:  fmt.Sprintf("Cost of workload %s", row.Group),
+				resourceID:   row.Group,
+				resourceName: name,
+				resourceType: focusResourceWorkload,
+				subAccount:   namespace,
+				cost:         row.Cost,
+				energyKWh:    row.EnergyKWh,
+				tags:         map[string]string{"namespace": namespace, "workload": name},
+			})
+		case DimensionNode:
+			if !chargesWorkloads && row.Cost > 0 {
+				charges = append(charges, focusCharge{
+					description:  fmt.Sprintf("Capacity of node %s allocated to pods", row.Group),
+					resourceID:   row.Group,
+					resourceName: row.Group,
+					resourceType: focusResourceNode,
+					cost:         row.Cost,
+					tags:         map[string]string{"node": row.Group},
+				})
+			}
+			if row.IdleCost > 0 {
+				charges = append(charges, focusCharge{
+					description:  fmt.Sprintf("Idle capacity of node %s", row.Group),
+					resourceID:   row.Group,
+					resourceName: row.Group,
+					resourceType: focusResourceNode,
+					cost:         row.IdleCost,
+					tags:         map[string]string{"node": row.Group, "idle": "true"},
+				})
+			}
+		}
+	}
+	return charges
+}
+
+// writeFOCUS writes the report as a FOCUS file, so it can be ingested by FinOps tools
+// alongside cloud bills. The cluster is the billing account and the report's period is both
+// the billing and the charge period. Costs are the operator's own, with no discounts to
+// tell apart, so the billed, effective, list and contracted costs are the same.
+func (r *Report) writeFOCUS(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(focusHeader); err != nil {
+		return err
+	}
+	cluster := r.Cluster
+	if cluster == "" {
+		cluster = focusDefaultCluster
+	}
+	from, to := r.From.UTC().Format(time.RFC3339), r.To.UTC().Format(time.RFC3339)
+	for _, charge := range r.focusCharges() {
+		tags, err := json.Marshal(charge.tags)
+		if err != nil {
+			return err
+		}
+		cost := formatFloat(charge.cost)
+		energy := ""
+		if charge.energyKWh > 0 {
+			energy = formatFloat(charge.energyKWh)
+		}
+		if err := out.Write([]string{
+			cluster, cluster, string(r.Currency),
+			from, to, from, to,
+			cost, cost, cost, cost,
+			focusChargeCategory, "", charge.description, focusChargeFrequency,
+			cluster, focusProviderName, focusProviderName,
+			focusServiceCategory, focusServiceName,
+			charge.resourceID, charge.resourceName, charge.resourceType,
+			charge.subAccount, charge.subAccount, string(tags),
+			energy,
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package costreport exports cost reports by workload, namespace and node as CSV and JSON
+// files, and in the FinOps FOCUS billing format, so finance teams can consume costs without
+// querying Prometheus.
+package costreport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/allocation"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/chargeback"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+)
+
+// DimensionWorkload, DimensionNamespace and DimensionNode are what a report row is the cost of
+const (
+	DimensionWorkload  = "workload"
+	DimensionNamespace = "namespace"
+	DimensionNode      = "node"
+)
+
+// Format is the file format of a report
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	// FormatFOCUS is a CSV file in the FinOps Open Cost and Usage Specification
+	FormatFOCUS Format = "focus"
+)
+
+// Formats lists the formats reports are written in
+var Formats = []Format{FormatCSV, FormatJSON, FormatFOCUS}
+
+// ParseFormat returns the format of its name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatCSV, FormatJSON, FormatFOCUS:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown report format %q; use csv, json or focus", name)
+}
+
+// ContentType returns the media type of the format
+func (f Format) ContentType() string {
+	if f == FormatCSV || f == FormatFOCUS {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/json"
+}
+
+// Extension returns the file name extension of the format
+func (f Format) Extension() string {
+	if f == FormatFOCUS {
+		return "focus.csv"
+	}
+	return string(f)
+}
+
+// ChargebackSource rolls up realized workload cost and energy
+type ChargebackSource interface {
+	Report(query chargeback.Query) (*chargeback.Report, error)
+}
+
+// AllocationSource rolls up node cost allocated to pods
+type AllocationSource interface {
+	Report(query allocation.Query) (*allocation.Report, error)
+}
+
+// Row is the cost of one workload, namespace or node in the report's period
+type Row struct {
+	Dimension string `json:"dimension"`
+	// Group is the namespace/name of a workload, the namespace or the node
+	Group string  `json:"group"`
+	Cost  float64 `json:"cost"`
+	// IdleCost is the cost of a node allocated to no pod; it is not part of Cost
+	IdleCost float64 `json:"idleCost,omitempty"`
+	// EnergyKWh is the energy of workloads and namespaces
+	EnergyKWh float64 `json:"energyKWh,omitempty"`
+}
+
+// Report is the cost of workloads and namespaces, from chargeback, and of nodes, from cost
+// allocation, in a period
+type Report struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Cluster names the cluster the costs were incurred in
+	Cluster  string            `json:"cluster,omitempty"`
+	Currency currency.Currency `json:"currency"`
+	Rows     []Row             `json:"rows"`
+}
+
+// Generator builds reports from the sources it has; a nil source leaves its rows out
+type Generator struct {
+	Chargeback ChargebackSource
+	Allocation AllocationSource
+	// Cluster names the cluster in reports, as the billing account of FOCUS files
+	Cluster string
+}
+
+// Generate builds the report of the period from the start of the hour of from to to
+func (g *Generator) Generate(from, to time.Time) (*Report, error) {
+	if g.Chargeback == nil && g.Allocation == nil {
+		return nil, errors.New("cost reports require chargeback or cost allocation")
+	}
+	report := &Report{From: from.Truncate(time.Hour), To: to, GeneratedAt: time.Now().UTC(),
+		Cluster: g.Cluster, Currency: currency.Default, Rows: []Row{}}
+
+	if g.Chargeback != nil {
+		for _, grouping := range []struct {
+			dimension string
+			groupBy   string
+		}{
+			{DimensionWorkload, chargeback.GroupByWorkload},
+			{DimensionNamespace, chargeback.GroupByNamespace},
+		} {
+			costs, err := g.Chargeback.Report(chargeback.Query{GroupBy: grouping.groupBy, From: from, To: to})
+			if err != nil {
+				return nil, fmt.Errorf("failed to report %s cost: %w", grouping.dimension, err)
+			}
+			report.Currency = costs.Currency
+			for _, row := range costs.Rows {
+				report.Rows = append(report.Rows, Row{Dimension: grouping.dimension, Group: row.Group,
+					Cost: row.Cost, EnergyKWh: row.EnergyKWh})
+			}
+		}
+	}
+	if g.Allocation != nil {
+		costs, err := g.Allocation.Report(allocation.Query{GroupBy: allocation.GroupByNode, From: from, To: to})
+		if err != nil {
+			return nil, fmt.Errorf("failed to report node cost: %w", err)
+		}
+		report.Currency = costs.Currency
+		for _, row := range costs.Rows {
+			report.Rows = append(report.Rows, Row{Dimension: DimensionNode, Group: row.Group,
+				Cost: row.Cost, IdleCost: row.IdleCost})
+		}
+	}
+	return report, nil
+}
+
+// csvHeader names the columns of a CSV report
+var csvHeader = []string{"dimension", "group", "cost", "idle_cost", "energy_kwh", "currency", "from", "to"}
+
+// Write writes the report in the format. A CSV report has a row per cost, each carrying the
+// currency and period, so files can be concatenated.
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case FormatFOCUS:
+		return r.writeFOCUS(w)
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+	from, to := r.From.UTC().Format(time.RFC3339), r.To.UTC().Format(time.RFC3339)
+	for _, row := range r.Rows {
+		if err := out.Write([]string{
+			row.Dimension,
+			row.Group,
+			formatFloat(row.Cost),
+			formatFloat(row.IdleCost),
+			formatFloat(row.EnergyKWh),
+			string(r.Currency),
+			from,
+			to,
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// formatFloat writes a number in the fewest digits that read back the same
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultRefresh is how often exchange rates are read
+	DefaultRefresh = 6 * time.Hour
+
+	// defaultFeedTimeout bounds reading the exchange rate feed
+	defaultFeedTimeout = 30 * time.Second
+)
+
+// RateSource reads current exchange rates
+type RateSource interface {
+	Rates(ctx context.Context) (Rates, error)
+}
+
+// ExchangeRateFeed reads exchange rates from an HTTP endpoint answering with JSON such as
+// {"base": "USD", "rates": {"EUR": 0.92, "KRW": 1380}}, the layout of most public rate APIs.
+// Rates quoted against another base are converted to US dollars.
+type ExchangeRateFeed struct {
+	url    string
+	client *http.Client
+}
+
+// NewExchangeRateFeed returns a rate source reading the feed at feedURL
+func NewExchangeRateFeed(feedURL string) (*ExchangeRateFeed, error) {
+	parsed, err := url.Parse(feedURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid exchange rate feed url %q", feedURL)
+	}
+	return &ExchangeRateFeed{url: feedURL, client: &http.Client{Timeout: defaultFeedTimeout}}, nil
+}
+
+// exchangeRates is the body of an exchange rate feed
+type exchangeRates struct {
+	Base     string             `json:"base"`
+	BaseCode string             `json:"base_code"`
+	Rates    map[string]float64 `json:"rates"`
+}
+
+// Rates reads the feed and returns the rates of the supported currencies per US dollar
+func (f *ExchangeRateFeed) Rates(ctx context.Context) (Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build exchange rate request: %w", err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rate feed returned status %d", resp.StatusCode)
+	}
+	var body exchangeRates
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rates: %w", err)
+	}
+
+	base := Default
+	if code := body.Base + body.BaseCode; code != "" {
+		if base, err = Parse(code); err != nil {
+			return nil, fmt.Errorf("exchange rates quoted in an unsupported base: %w", err)
+		}
+	}
+	quoted := Rates{base: 1}
+	for code, rate := range body.Rates {
+		if c, err := Parse(code); err == nil && rate > 0 {
+			quoted[c] = rate
+		}
+	}
+	perDollar, ok := quoted[USD]
+	if !ok {
+		return nil, fmt.Errorf("exchange rates quoted in %s lack a rate for %s", base, USD)
+	}
+	rates := make(Rates, len(quoted))
+	for c, rate := range quoted {
+		rates[c] = rate / perDollar
+	}
+	return rates, nil
+}
+
+// Converter converts cost figures from US dollars into the currency the operator reports
+// in, at rates read from a source in the background. A nil converter reports in US dollars.
+type Converter struct {
+	currency Currency
+	source   RateSource
+	interval time.Duration
+	rates    atomic.Pointer[Rates]
+}
+
+// NewConverter returns a converter reporting in the currency at the initial rates until the
+// source, if any, is read every interval
+func NewConverter(currency Currency, initial Rates, source RateSource, interval time.Duration) *Converter {
+	c := &Converter{currency: currency, source: source, interval: interval}
+	c.store(initial)
+	return c
+}
+
+// store replaces the rates; a US dollar is always worth one
+func (c *Converter) store(rates Rates) {
+	stored := maps.Clone(rates)
+	if stored == nil {
+		stored = Rates{}
+	}
+	stored[USD] = 1
+	c.rates.Store(&stored)
+}
+
+// Currency returns the currency cost figures are reported in
+func (c *Converter) Currency() Currency {
+	if c == nil {
+		return Default
+	}
+	return c.currency
+}
+
+// Rate returns the units of the currency a US dollar buys, and whether it is known
+func (c *Converter) Rate(currency Currency) (float64, bool) {
+	if currency == USD {
+		return 1, true
+	}
+	if c == nil {
+		return 0, false
+	}
+	rate, ok := (*c.rates.Load())[currency]
+	return rate, ok
+}
+
+// FromUSD converts an amount in US dollars into the reporting currency. Amounts are left in
+// US dollars while its rate is unknown.
+func (c *Converter) FromUSD(usd float64) float64 {
+	rate, ok := c.Rate(c.Currency())
+	if !ok {
+		return usd
+	}
+	return usd * rate
+}
+
+// Convert converts an amount in the reporting currency into another currency
+func (c *Converter) Convert(amount float64, to Currency) (float64, error) {
+	from, ok := c.Rate(c.Currency())
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %s", c.Currency())
+	}
+	rate, ok := c.Rate(to)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %s", to)
+	}
+	return amount / from * rate, nil
+}
+
+// Refresh reads the source and replaces the rates it returns
+func (c *Converter) Refresh(ctx context.Context) error {
+	if c.source == nil {
+		return nil
+	}
+	rates, err := c.source.Rates(ctx)
+	if err != nil {
+		return err
+	}
+	merged := maps.Clone(*c.rates.Load())
+	maps.Copy(merged, rates)
+	c.store(merged)
+	return nil
+}
+
+// Start reads the source again every interval until the context is cancelled; a failed read
+// keeps the previous rates
+func (c *Converter) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("exchange-rates")
+	if c.source == nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := c.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read exchange rates")
+			continue
+		}
+		rate, _ := c.Rate(c.currency)
+		log.V(1).Info("Exchange rates read", "currency", c.currency, "rate", rate)
+	}
+}
+
+// NeedLeaderElection reads rates on every replica, as every replica reports costs
+func (c *Converter) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package currency labels the operator's cost figures with a currency and converts them from
+// the US dollars resources are priced in.
+package currency
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+// Currencies cost figures can be reported in
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	KRW Currency = "KRW"
+)
+
+// Default is the currency resources are priced in
+const Default = USD
+
+// Supported lists the currencies cost figures can be reported in
+var Supported = []Currency{USD, EUR, KRW}
+
+// symbols and decimals are the sign and the minor unit digits of each currency
+var (
+	symbols  = map[Currency]string{USD: "$", EUR: "€", KRW: "₩"}
+	decimals = map[Currency]int{USD: 2, EUR: 2, KRW: 0}
+)
+
+// Parse returns the supported currency of the code, ignoring case
+func Parse(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := symbols[c]; !ok {
+		return "", fmt.Errorf("unsupported currency %q, supported currencies are %s", code, joinSupported())
+	}
+	return c, nil
+}
+
+// joinSupported lists the supported currency codes for error messages
+func joinSupported() string {
+	codes := make([]string, len(Supported))
+	for i, c := range Supported {
+		codes[i] = string(c)
+	}
+	return strings.Join(codes, ", ")
+}
+
+// Round rounds the amount to the currency's minor unit, cents or whole won
+func (c Currency) Round(amount float64) float64 {
+	scale := math.Pow10(c.decimals())
+	return math.Round(amount*scale) / scale
+}
+
+// Format writes the amount with the currency's symbol in its minor unit, as in $12.34 or ₩16500
+func (c Currency) Format(amount float64) string {
+	symbol, ok := symbols[c]
+	if !ok {
+		symbol = string(c) + " "
+	}
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return sign + symbol + strconv.FormatFloat(amount, 'f', c.decimals(), 64)
+}
+
+// decimals returns the digits of the currency's minor unit
+func (c Currency) decimals() int {
+	if d, ok := decimals[c]; ok {
+		return d
+	}
+	return 2
+}
+
+// Rates are the units of each currency one US dollar buys
+type Rates map[Currency]float64
+
+// ParseRates parses rates written as "EUR=0.92,KRW=1380"
+func ParseRates(spec string) (Rates, error) {
+	rates := Rates{}
+	for _, pair := range strings.Split(spec, ",") {
+		code, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid exchange rate %q, expected currency=rate", pair)
+		}
+		c, err := Parse(code)
+		if err != nil {
+			return nil, err
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate for %s: %w", c, err)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("exchange rate for %s must be positive", c)
+		}
+		rates[c] = rate
+	}
+	return rates, nil
+}
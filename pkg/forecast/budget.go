@@ -0,0 +1,522 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forecast
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/notify"
+)
+
+const (
+	// DefaultInterval is how often workload cost is sampled and projections are refreshed
+	DefaultInterval = time.Hour
+
+	// ConditionForecastWithinBudget reports whether a cost policy's projected spend fits its budget
+	ConditionForecastWithinBudget = "ForecastWithinBudget"
+
+	// maxSamples caps the cost samples kept for the model
+	maxSamples = 2048
+
+	// maxNamespaceForecasts and maxWorkloadForecasts cap the forecasts in cost policy status
+	maxNamespaceForecasts = 50
+	maxWorkloadForecasts  = 10
+)
+
+var projectedMonthlyCost = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kcloud_cost_policy_projected_monthly_cost_usd",
+	Help: "Projected spend of the workloads a cost policy covers for the current month in USD",
+}, []string{"policy"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(projectedMonthlyCost)
+}
+
+// workloadKey identifies a WorkloadOptimizer
+type workloadKey struct {
+	namespace string
+	name      string
+}
+
+// projection is the spend to date and the projected spend by the end of a month or budget
+// period of a set of workloads
+type projection struct {
+	ToDate    float64
+	Projected float64
+}
+
+// +kubebuilder:rbac:groups=kcloud.io,resources=costpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kcloud.io,resources=costpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kcloud.io,resources=optimizationplans,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// BudgetForecaster samples the hourly cost of every workload, forecasts it to the end of
+// the month and records the projections in the status of the cost policies covering it,
+// along with what optimization actions saved them. Spend and savings accrue from when the
+// operator started sampling and history is kept in memory; a policy's total spend resumes
+// from what its status recorded earlier in the month and budget period.
+type BudgetForecaster struct {
+	client   client.Client
+	interval time.Duration
+	model    HoltWinters
+
+	// Notifier alerts the namespaces of a policy whose projection exceeds its budget; nil disables alerts
+	Notifier *notify.DigestScheduler
+	// Messages renders condition and alert text; nil uses the built-in English catalog
+	Messages *messages.Catalog
+	// Currency is the reporting currency workload costs and budgets are in; nil is US dollars
+	Currency *currency.Converter
+
+	mu      sync.Mutex
+	clock   clock.PassiveClock
+	samples []map[workloadKey]float64
+	month   *ledger
+	week    *ledger
+	// seeds hold each policy's spend recorded before sampling started, read once per policy
+	seeds map[string]spendSeed
+}
+
+// spendSeed is the spend a policy's status recorded for a month and a budget period
+type spendSeed struct {
+	month       time.Time
+	monthly     float64
+	periodStart time.Time
+	period      float64
+}
+
+// NewBudgetForecaster returns a forecaster sampling every interval with a daily season
+func NewBudgetForecaster(c client.Client, interval time.Duration) *BudgetForecaster {
+	return &BudgetForecaster{
+		client:   c,
+		interval: interval,
+		model:    NewHoltWinters(int(24 * time.Hour / interval)),
+		clock:    clock.RealClock{},
+		seeds:    make(map[string]spendSeed),
+	}
+}
+
+// SetClock replaces the clock used to place samples in a month
+func (f *BudgetForecaster) SetClock(c clock.PassiveClock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clock = c
+}
+
+// Observe records one sample of each workload's hourly cost and accrues it as spend for
+// one interval, along with the hourly savings of the optimization actions in effect on it
+// and its share of applied consolidation plans. Spend and savings reset when a new month
+// or week starts; the samples are kept for the model.
+func (f *BudgetForecaster) Observe(workloads []kcloudv1alpha1.WorkloadOptimizer, plans []kcloudv1alpha1.OptimizationPlan) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.clock.Now()
+	if month := monthStart(now); f.month == nil || !month.Equal(f.month.start) {
+		f.month = newLedger(month)
+	}
+	if week := weekStart(now); f.week == nil || !week.Equal(f.week.start) {
+		f.week = newLedger(week)
+	}
+
+	consolidation := consolidationSavings(plans)
+	sample := make(map[workloadKey]float64, len(workloads))
+	for _, wo := range workloads {
+		key := workloadKey{namespace: wo.Namespace, name: wo.Name}
+		savings := workloadSavings(&wo, consolidation[key])
+		for _, ledger := range []*ledger{f.month, f.week} {
+			ledger.accrueSavings(key, savings, f.interval.Hours())
+		}
+		if wo.Status.CurrentCost == nil {
+			continue
+		}
+		sample[key] = *wo.Status.CurrentCost
+		for _, ledger := range []*ledger{f.month, f.week} {
+			ledger.spend[key] += *wo.Status.CurrentCost * f.interval.Hours()
+		}
+	}
+	f.samples = append(f.samples, sample)
+	if len(f.samples) > maxSamples {
+		f.samples = f.samples[len(f.samples)-maxSamples:]
+	}
+}
+
+// history returns the month-to-date and week-to-date spend and the cost series of the
+// selected workloads, and the current time
+func (f *BudgetForecaster) history(selected map[workloadKey]bool) (map[workloadKey]float64,
+	map[workloadKey]float64, map[workloadKey][]float64, time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	monthToDate := make(map[workloadKey]float64, len(selected))
+	weekToDate := make(map[workloadKey]float64, len(selected))
+	series := make(map[workloadKey][]float64, len(selected))
+	for key := range selected {
+		monthToDate[key] = f.month.spentBy(key)
+		weekToDate[key] = f.week.spentBy(key)
+		series[key] = make([]float64, len(f.samples))
+	}
+	for i, sample := range f.samples {
+		for key, cost := range sample {
+			if selected[key] {
+				series[key][i] = cost
+			}
+		}
+	}
+	return monthToDate, weekToDate, series, f.clock.Now()
+}
+
+// project adds the forecast of the series from now until end to the spend to date
+func (f *BudgetForecaster) project(now, end time.Time, toDate float64, series []float64) projection {
+	steps := int(math.Ceil(float64(end.Sub(now)) / float64(f.interval)))
+	projected := toDate
+	for _, cost := range f.model.Forecast(series, steps) {
+		projected += cost * f.interval.Hours()
+	}
+	money := f.Currency.Currency()
+	return projection{ToDate: money.Round(toDate), Projected: money.Round(projected)}
+}
+
+// projectAll returns the month-to-date and projected monthly spend of each selected workload,
+// of each namespace and of all of them together
+func (f *BudgetForecaster) projectAll(selected map[workloadKey]bool) (workloads map[workloadKey]projection,
+	namespaces map[string]projection, total projection) {
+	monthToDate, _, series, now := f.history(selected)
+	monthEnd := monthStart(now).AddDate(0, 1, 0)
+
+	namespaceSpend := make(map[string]float64)
+	namespaceSeries := make(map[string][]float64)
+	var totalSpend float64
+	var totalSeries []float64
+	workloads = make(map[workloadKey]projection, len(selected))
+	for key := range selected {
+		workloads[key] = f.project(now, monthEnd, monthToDate[key], series[key])
+		namespaceSpend[key.namespace] += monthToDate[key]
+		namespaceSeries[key.namespace] = addSeries(namespaceSeries[key.namespace], series[key])
+		totalSpend += monthToDate[key]
+		totalSeries = addSeries(totalSeries, series[key])
+	}
+
+	namespaces = make(map[string]projection, len(namespaceSeries))
+	for namespace := range namespaceSeries {
+		namespaces[namespace] = f.project(now, monthEnd, namespaceSpend[namespace], namespaceSeries[namespace])
+	}
+	return workloads, namespaces, f.project(now, monthEnd, totalSpend, totalSeries)
+}
+
+// projectPeriod returns the spend of the selected workloads in the budget period that
+// contains the current time and its projection to the end of the period, and the period's
+// bounds
+func (f *BudgetForecaster) projectPeriod(selected map[workloadKey]bool, period string) (projection, time.Time, time.Time) {
+	monthToDate, weekToDate, series, now := f.history(selected)
+	toDate := monthToDate
+	if period == kcloudv1alpha1.BudgetPeriodWeekly {
+		toDate = weekToDate
+	}
+
+	var spend float64
+	var total []float64
+	for key := range selected {
+		spend += toDate[key]
+		total = addSeries(total, series[key])
+	}
+	start, end := periodBounds(period, now)
+	return f.project(now, end, spend, total), start, end
+}
+
+// Refresh samples every workload and updates the projections of every cost policy
+func (f *BudgetForecaster) Refresh(ctx context.Context) error {
+	var workloads kcloudv1alpha1.WorkloadOptimizerList
+	if err := f.client.List(ctx, &workloads); err != nil {
+		return fmt.Errorf("failed to list workload optimizers: %w", err)
+	}
+	var plans kcloudv1alpha1.OptimizationPlanList
+	if err := f.client.List(ctx, &plans); err != nil {
+		return fmt.Errorf("failed to list optimization plans: %w", err)
+	}
+	f.Observe(workloads.Items, plans.Items)
+
+	var namespaces corev1.NamespaceList
+	if err := f.client.List(ctx, &namespaces); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaceLabels := make(map[string]labels.Set, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		namespaceLabels[namespace.Name] = namespace.Labels
+	}
+
+	var policies kcloudv1alpha1.CostPolicyList
+	if err := f.client.List(ctx, &policies); err != nil {
+		return fmt.Errorf("failed to list cost policies: %w", err)
+	}
+	for i := range policies.Items {
+		if err := f.updatePolicy(ctx, &policies.Items[i], workloads.Items, namespaceLabels); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update cost forecast", "costPolicy", policies.Items[i].Name)
+		}
+	}
+	return nil
+}
+
+// updatePolicy records the projections of the workloads a policy covers in its status
+func (f *BudgetForecaster) updatePolicy(ctx context.Context, policy *kcloudv1alpha1.CostPolicy,
+	workloads []kcloudv1alpha1.WorkloadOptimizer, namespaceLabels map[string]labels.Set) error {
+	namespaceSelector, err := selectorOrEverything(policy.Spec.NamespaceSelector)
+	if err != nil {
+		return fmt.Errorf("invalid namespace selector: %w", err)
+	}
+	workloadSelector, err := selectorOrEverything(policy.Spec.WorkloadSelector)
+	if err != nil {
+		return fmt.Errorf("invalid workload selector: %w", err)
+	}
+
+	covered := make(map[workloadKey]bool)
+	for _, wo := range workloads {
+		if namespaceSelector.Matches(namespaceLabels[wo.Namespace]) && workloadSelector.Matches(labels.Set(wo.Labels)) {
+			covered[workloadKey{namespace: wo.Namespace, name: wo.Name}] = true
+		}
+	}
+
+	workloadProjections, namespaceProjections, total := f.projectAll(covered)
+	period := budgetPeriod(policy)
+	spent, start, end := f.projectPeriod(covered, period)
+	monthly, periodic := f.seededSpend(policy, start)
+	total = total.plus(monthly, f.Currency.Currency())
+	spent = spent.plus(periodic, f.Currency.Currency())
+	namespaceForecasts := make([]kcloudv1alpha1.SpendForecast, 0, len(namespaceProjections))
+	for namespace, projected := range namespaceProjections {
+		namespaceForecasts = append(namespaceForecasts, spendForecast(namespace, "", projected))
+	}
+	workloadForecasts := make([]kcloudv1alpha1.SpendForecast, 0, len(workloadProjections))
+	for key, projected := range workloadProjections {
+		workloadForecasts = append(workloadForecasts, spendForecast(key.namespace, key.name, projected))
+	}
+
+	original := policy.DeepCopy()
+	policy.Status.Currency = string(f.Currency.Currency())
+	policy.Status.MonthlySpend = &total.ToDate
+	policy.Status.ProjectedMonthlyCost = &total.Projected
+	policy.Status.NamespaceForecasts = highestProjections(namespaceForecasts, maxNamespaceForecasts)
+	policy.Status.WorkloadForecasts = highestProjections(workloadForecasts, maxWorkloadForecasts)
+	updated := metav1.Now()
+	policy.Status.LastUpdated = &updated
+	budget := f.trackBudget(ctx, policy, start, end, spent.ToDate)
+	f.checkBudget(ctx, policy, period, budget, spent)
+	savings := f.periodSavings(covered, period)
+	f.recordSavings(policy, savings)
+
+	if err := f.client.Status().Patch(ctx, policy, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to patch cost policy status: %w", err)
+	}
+	if projectedUSD, err := f.Currency.Convert(total.Projected, currency.USD); err == nil {
+		projectedMonthlyCost.WithLabelValues(policy.Name).Set(projectedUSD)
+	}
+	f.exportSavings(policy.Name, savings)
+	return nil
+}
+
+// seededSpend returns the spend the policy's status recorded before the forecaster started
+// sampling, for the current month and for the budget period starting at periodStart. The
+// status is read the first time the policy is seen, and only in its own currency, so spend
+// accrued since is not counted twice.
+func (f *BudgetForecaster) seededSpend(policy *kcloudv1alpha1.CostPolicy, periodStart time.Time) (float64, float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	seed, ok := f.seeds[policy.Name]
+	if !ok {
+		status := &policy.Status
+		sameCurrency := status.Currency == string(f.Currency.Currency())
+		if sameCurrency && status.MonthlySpend != nil && status.LastUpdated != nil {
+			seed.month, seed.monthly = monthStart(status.LastUpdated.In(f.clock.Now().Location())), *status.MonthlySpend
+		}
+		if sameCurrency && status.ConsumedBudget != nil && status.PeriodStart != nil {
+			seed.periodStart, seed.period = status.PeriodStart.Time, *status.ConsumedBudget
+		}
+		f.seeds[policy.Name] = seed
+	}
+
+	var monthly, periodic float64
+	if f.month != nil && seed.month.Equal(f.month.start) {
+		monthly = seed.monthly
+	}
+	if seed.periodStart.Equal(periodStart) {
+		periodic = seed.period
+	}
+	return monthly, periodic
+}
+
+// plus adds spend recorded earlier to a projection
+func (p projection) plus(spend float64, money currency.Currency) projection {
+	return projection{ToDate: money.Round(p.ToDate + spend), Projected: money.Round(p.Projected + spend)}
+}
+
+// checkBudget compares the projection to the end of the budget period with the period's
+// budget, and alerts the covered namespaces once when it is exceeded
+func (f *BudgetForecaster) checkBudget(ctx context.Context, policy *kcloudv1alpha1.CostPolicy, period string,
+	budget float64, spent projection) {
+	if budget <= 0 {
+		meta.RemoveStatusCondition(&policy.Status.Conditions, ConditionForecastWithinBudget)
+		return
+	}
+
+	locale := policy.Annotations[messages.LocaleAnnotation]
+	data := map[string]any{
+		"Projected": spent.Projected,
+		"Budget":    budget,
+		"Percent":   (spent.Projected - budget) / budget * 100,
+		"Period":    periodNoun(period),
+	}
+	condition := metav1.Condition{
+		Type:    ConditionForecastWithinBudget,
+		Status:  metav1.ConditionTrue,
+		Reason:  "WithinBudget",
+		Message: f.Messages.Render(locale, messages.ForecastWithinBudget, data),
+	}
+	if spent.Projected > budget {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ProjectedOverBudget"
+		condition.Message = f.Messages.Render(locale, messages.ForecastOverBudget, data)
+	}
+
+	previous := meta.FindStatusCondition(policy.Status.Conditions, ConditionForecastWithinBudget)
+	if condition.Status == metav1.ConditionFalse && (previous == nil || previous.Status != metav1.ConditionFalse) {
+		log.FromContext(ctx).Info("Projected spend exceeds budget", "costPolicy", policy.Name,
+			"projected", spent.Projected, "consumed", spent.ToDate, "budget", budget)
+		f.notifyOverBudget(ctx, policy, condition, spent.ToDate >= budget)
+	}
+	meta.SetStatusCondition(&policy.Status.Conditions, condition)
+}
+
+// notifyOverBudget alerts each namespace, which is a team, covered by the policy; the alert
+// is critical once the spend of the period alone exceeds the budget
+func (f *BudgetForecaster) notifyOverBudget(ctx context.Context, policy *kcloudv1alpha1.CostPolicy,
+	condition metav1.Condition, spent bool) {
+	if f.Notifier == nil {
+		return
+	}
+	severity := notify.SeverityWarning
+	if spent {
+		severity = notify.SeverityCritical
+	}
+	title := f.Messages.Render(policy.Annotations[messages.LocaleAnnotation], messages.AlertForecastOverBudget,
+		map[string]any{"Policy": policy.Name})
+	for _, forecast := range policy.Status.NamespaceForecasts {
+		f.Notifier.Notify(ctx, notify.Alert{
+			Team:     forecast.Namespace,
+			Category: notify.CategoryCost,
+			Severity: severity,
+			Title:    title,
+			Message:  condition.Message,
+			Source:   "costpolicy/" + policy.Name,
+		})
+	}
+}
+
+// Start refreshes the projections every interval until the context is cancelled
+func (f *BudgetForecaster) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("cost-forecast")
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := f.Refresh(ctx); err != nil {
+				log.Error(err, "Failed to refresh cost forecasts")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection keeps a single replica sampling spend and writing policy status
+func (f *BudgetForecaster) NeedLeaderElection() bool {
+	return true
+}
+
+// selectorOrEverything converts a label selector, matching everything when it is nil
+func selectorOrEverything(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// spendForecast converts a projection for status
+func spendForecast(namespace, workload string, projected projection) kcloudv1alpha1.SpendForecast {
+	return kcloudv1alpha1.SpendForecast{
+		Namespace:            namespace,
+		Workload:             workload,
+		MonthToDateSpend:     projected.ToDate,
+		ProjectedMonthlyCost: projected.Projected,
+	}
+}
+
+// highestProjections keeps the limit forecasts with the highest projected spend, highest first
+func highestProjections(forecasts []kcloudv1alpha1.SpendForecast, limit int) []kcloudv1alpha1.SpendForecast {
+	slices.SortFunc(forecasts, func(a, b kcloudv1alpha1.SpendForecast) int {
+		if c := cmp.Compare(b.ProjectedMonthlyCost, a.ProjectedMonthlyCost); c != 0 {
+			return c
+		}
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Workload, b.Workload))
+	})
+	if len(forecasts) > limit {
+		forecasts = forecasts[:limit]
+	}
+	return forecasts
+}
+
+// monthStart returns the first instant of t's month
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// weekStart returns the first instant of t's week, which starts on Monday
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// addSeries adds the values of series to total, which may be nil
+func addSeries(total, series []float64) []float64 {
+	if total == nil {
+		total = make([]float64, len(series))
+	}
+	for i, value := range series {
+		total[i] += value
+	}
+	return total
+}
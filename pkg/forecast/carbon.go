@@ -0,0 +1,317 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forecast
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/notify"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+const (
+	// DefaultCarbonInterval is how often carbon policies are brought up to date
+	DefaultCarbonInterval = 5 * time.Minute
+
+	// ConditionCarbonWithinBudget reports whether a carbon policy's projected emissions fit its budget
+	ConditionCarbonWithinBudget = "CarbonWithinBudget"
+	// ConditionCarbonRateWithinLimit reports whether a carbon policy's emission rate is within its cap
+	ConditionCarbonRateWithinLimit = "CarbonRateWithinLimit"
+
+	// maxNamespaceEmissions caps the namespaces in carbon policy status
+	maxNamespaceEmissions = 50
+)
+
+var (
+	carbonPolicyEmissions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_carbon_policy_emissions_grams",
+		Help: "Emissions of the workloads a carbon policy covers in the current month in g CO2e",
+	}, []string{"policy"})
+	carbonPolicyProjectedEmissions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_carbon_policy_projected_emissions_grams",
+		Help: "Projected emissions of the workloads a carbon policy covers for the current month in g CO2e",
+	}, []string{"policy"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(carbonPolicyEmissions, carbonPolicyProjectedEmissions)
+}
+
+// +kubebuilder:rbac:groups=kcloud.io,resources=carbonpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kcloud.io,resources=carbonpolicies/status,verbs=get;update;patch
+
+// CarbonAccountant totals the emissions the WorkloadOptimizer controller accrues in each
+// workload's status for the carbon policies covering it, and tracks them against each
+// policy's monthly budget and emission rate cap
+type CarbonAccountant struct {
+	client   client.Client
+	interval time.Duration
+	clock    clock.PassiveClock
+
+	// Notifier alerts the namespaces of a policy whose projection exceeds its budget; nil disables alerts
+	Notifier *notify.DigestScheduler
+	// Messages renders condition and alert text; nil uses the built-in English catalog
+	Messages *messages.Catalog
+}
+
+// NewCarbonAccountant returns an accountant updating carbon policies every interval
+func NewCarbonAccountant(c client.Client, interval time.Duration) *CarbonAccountant {
+	return &CarbonAccountant{client: c, interval: interval, clock: clock.RealClock{}}
+}
+
+// SetClock replaces the clock used to place emissions in a month
+func (a *CarbonAccountant) SetClock(c clock.PassiveClock) {
+	a.clock = c
+}
+
+// Refresh updates the status of every carbon policy
+func (a *CarbonAccountant) Refresh(ctx context.Context) error {
+	var policies kcloudv1alpha1.CarbonPolicyList
+	if err := a.client.List(ctx, &policies); err != nil {
+		return fmt.Errorf("failed to list carbon policies: %w", err)
+	}
+	if len(policies.Items) == 0 {
+		return nil
+	}
+	var workloads kcloudv1alpha1.WorkloadOptimizerList
+	if err := a.client.List(ctx, &workloads); err != nil {
+		return fmt.Errorf("failed to list workload optimizers: %w", err)
+	}
+	var namespaces corev1.NamespaceList
+	if err := a.client.List(ctx, &namespaces); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaceLabels := make(map[string]labels.Set, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		namespaceLabels[namespace.Name] = namespace.Labels
+	}
+
+	for i := range policies.Items {
+		if err := a.updatePolicy(ctx, &policies.Items[i], workloads.Items, namespaceLabels); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update carbon accounting", "carbonPolicy", policies.Items[i].Name)
+		}
+	}
+	return nil
+}
+
+// updatePolicy records the emissions of the workloads a policy covers in its status. Only
+// emissions accrued in the current month count, so workloads not reconciled since it
+// started add their current rate but nothing emitted.
+func (a *CarbonAccountant) updatePolicy(ctx context.Context, policy *kcloudv1alpha1.CarbonPolicy,
+	workloads []kcloudv1alpha1.WorkloadOptimizer, namespaceLabels map[string]labels.Set) error {
+	namespaceSelector, err := selectorOrEverything(policy.Spec.NamespaceSelector)
+	if err != nil {
+		return fmt.Errorf("invalid namespace selector: %w", err)
+	}
+	workloadSelector, err := selectorOrEverything(policy.Spec.WorkloadSelector)
+	if err != nil {
+		return fmt.Errorf("invalid workload selector: %w", err)
+	}
+
+	now := a.clock.Now()
+	start, end := optimizer.EmissionsMonth(now)
+	byNamespace := make(map[string]*kcloudv1alpha1.NamespaceEmissions)
+	var rate, emitted float64
+	for _, wo := range workloads {
+		if !namespaceSelector.Matches(namespaceLabels[wo.Namespace]) || !workloadSelector.Matches(labels.Set(wo.Labels)) {
+			continue
+		}
+		namespace := byNamespace[wo.Namespace]
+		if namespace == nil {
+			namespace = &kcloudv1alpha1.NamespaceEmissions{Namespace: wo.Namespace}
+			byNamespace[wo.Namespace] = namespace
+		}
+		workloadRate := optimizer.CarbonGramsPerHour(ptr.Deref(wo.Status.CarbonFootprint, 0))
+		namespace.CarbonRate += workloadRate
+		rate += workloadRate
+		if account := wo.Status.Emissions; account != nil && account.PeriodStart.Time.Equal(start) {
+			namespace.Emitted += account.Emitted
+			emitted += account.Emitted
+		}
+	}
+	namespaces := make([]kcloudv1alpha1.NamespaceEmissions, 0, len(byNamespace))
+	for _, namespace := range byNamespace {
+		namespace.CarbonRate = math.Round(namespace.CarbonRate)
+		namespace.Emitted = math.Round(namespace.Emitted)
+		namespaces = append(namespaces, *namespace)
+	}
+	slices.SortFunc(namespaces, func(x, y kcloudv1alpha1.NamespaceEmissions) int {
+		return cmp.Or(cmp.Compare(y.Emitted, x.Emitted), cmp.Compare(x.Namespace, y.Namespace))
+	})
+	if len(namespaces) > maxNamespaceEmissions {
+		namespaces = namespaces[:maxNamespaceEmissions]
+	}
+	projected := math.Round(emitted + rate*end.Sub(now).Hours())
+	emitted, rate = math.Round(emitted), math.Round(rate)
+
+	original := policy.DeepCopy()
+	status := &policy.Status
+	status.PeriodStart = &metav1.Time{Time: start}
+	status.PeriodEnd = &metav1.Time{Time: end}
+	status.CarbonRate = &rate
+	status.Emitted = &emitted
+	status.ProjectedEmissions = &projected
+	status.RemainingBudget, status.BudgetUtilization = nil, nil
+	if budget := policy.Spec.CarbonBudget; budget > 0 {
+		status.RemainingBudget = ptr.To(budget - emitted)
+		status.BudgetUtilization = ptr.To(math.Min(emitted/budget*100, 100))
+	}
+	status.Namespaces = namespaces
+	updated := metav1.NewTime(now)
+	status.LastUpdated = &updated
+	a.checkBudget(ctx, policy, emitted, projected)
+	a.checkRate(policy, rate)
+	status.Phase = "Active"
+	for _, condition := range status.Conditions {
+		if condition.Status == metav1.ConditionFalse {
+			status.Phase = "Violated"
+		}
+	}
+
+	if err := a.client.Status().Patch(ctx, policy, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to patch carbon policy status: %w", err)
+	}
+	carbonPolicyEmissions.WithLabelValues(policy.Name).Set(emitted)
+	carbonPolicyProjectedEmissions.WithLabelValues(policy.Name).Set(projected)
+	return nil
+}
+
+// checkBudget compares the projection to the end of the month with the policy's budget, and
+// alerts the covered namespaces once when it is exceeded
+func (a *CarbonAccountant) checkBudget(ctx context.Context, policy *kcloudv1alpha1.CarbonPolicy, emitted, projected float64) {
+	budget := policy.Spec.CarbonBudget
+	if budget <= 0 {
+		meta.RemoveStatusCondition(&policy.Status.Conditions, ConditionCarbonWithinBudget)
+		return
+	}
+
+	locale := policy.Annotations[messages.LocaleAnnotation]
+	data := map[string]any{
+		"Projected": projected,
+		"Budget":    budget,
+		"Percent":   (projected - budget) / budget * 100,
+	}
+	condition := metav1.Condition{
+		Type:    ConditionCarbonWithinBudget,
+		Status:  metav1.ConditionTrue,
+		Reason:  "WithinBudget",
+		Message: a.Messages.Render(locale, messages.CarbonWithinBudget, data),
+	}
+	if projected > budget {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ProjectedOverBudget"
+		condition.Message = a.Messages.Render(locale, messages.CarbonOverBudget, data)
+	}
+
+	previous := meta.FindStatusCondition(policy.Status.Conditions, ConditionCarbonWithinBudget)
+	if condition.Status == metav1.ConditionFalse && (previous == nil || previous.Status != metav1.ConditionFalse) {
+		log.FromContext(ctx).Info("Projected emissions exceed carbon budget", "carbonPolicy", policy.Name,
+			"projected", projected, "emitted", emitted, "budget", budget)
+		a.notifyOverBudget(ctx, policy, condition, emitted >= budget)
+	}
+	meta.SetStatusCondition(&policy.Status.Conditions, condition)
+}
+
+// checkRate compares the policy's emission rate with its cap
+func (a *CarbonAccountant) checkRate(policy *kcloudv1alpha1.CarbonPolicy, rate float64) {
+	limit := policy.Spec.MaxCarbonPerHour
+	if limit == nil {
+		meta.RemoveStatusCondition(&policy.Status.Conditions, ConditionCarbonRateWithinLimit)
+		return
+	}
+
+	locale := policy.Annotations[messages.LocaleAnnotation]
+	data := map[string]any{"Rate": rate, "Limit": *limit}
+	condition := metav1.Condition{
+		Type:    ConditionCarbonRateWithinLimit,
+		Status:  metav1.ConditionTrue,
+		Reason:  "WithinLimit",
+		Message: a.Messages.Render(locale, messages.CarbonRateWithinLimit, data),
+	}
+	if rate > *limit {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "OverLimit"
+		condition.Message = a.Messages.Render(locale, messages.CarbonRateOverLimit, data)
+	}
+	meta.SetStatusCondition(&policy.Status.Conditions, condition)
+}
+
+// notifyOverBudget alerts each namespace, which is a team, covered by the policy; the alert
+// is critical once the month's emissions alone exceed the budget
+func (a *CarbonAccountant) notifyOverBudget(ctx context.Context, policy *kcloudv1alpha1.CarbonPolicy,
+	condition metav1.Condition, emitted bool) {
+	if a.Notifier == nil {
+		return
+	}
+	severity := notify.SeverityWarning
+	if emitted {
+		severity = notify.SeverityCritical
+	}
+	title := a.Messages.Render(policy.Annotations[messages.LocaleAnnotation], messages.AlertCarbonOverBudget,
+		map[string]any{"Name": policy.Name})
+	for _, namespace := range policy.Status.Namespaces {
+		a.Notifier.Notify(ctx, notify.Alert{
+			Team:     namespace.Namespace,
+			Category: notify.CategoryPower,
+			Severity: severity,
+			Title:    title,
+			Message:  condition.Message,
+			Source:   "carbonpolicy/" + policy.Name,
+		})
+	}
+}
+
+// Start updates carbon policies every interval until the context is cancelled
+func (a *CarbonAccountant) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("carbon-accounting")
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		if err := a.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to update carbon policies")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection keeps a single replica writing policy status
+func (a *CarbonAccountant) NeedLeaderElection() bool {
+	return true
+}
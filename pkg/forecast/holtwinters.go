@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package forecast projects workload and namespace spend for the rest of the budget period
+package forecast
+
+import "math"
+
+// Default smoothing factors
+const (
+	DefaultAlpha   = 0.5
+	DefaultBeta    = 0.1
+	DefaultGamma   = 0.3
+	DefaultDamping = 0.98
+)
+
+// HoltWinters forecasts a series with additive level, damped trend and seasonal smoothing
+type HoltWinters struct {
+	// Alpha, Beta and Gamma smooth the level, trend and seasonal components (0.0-1.0)
+	Alpha float64
+	Beta  float64
+	Gamma float64
+	// Damping flattens the trend further into the horizon, so one busy day does not
+	// extrapolate to a month of growth; 1 keeps the trend linear
+	Damping float64
+	// Season is the number of samples per seasonal cycle; below 2 disables seasonality
+	Season int
+}
+
+// NewHoltWinters returns a forecaster with the default smoothing factors
+func NewHoltWinters(season int) HoltWinters {
+	return HoltWinters{
+		Alpha:   DefaultAlpha,
+		Beta:    DefaultBeta,
+		Gamma:   DefaultGamma,
+		Damping: DefaultDamping,
+		Season:  season,
+	}
+}
+
+// Forecast returns the next horizon values of the series. Seasonality needs two full
+// cycles of samples; shorter series fall back to level and trend only, and a single
+// sample is carried forward. Forecasts are never negative.
+func (hw HoltWinters) Forecast(series []float64, horizon int) []float64 {
+	if horizon <= 0 {
+		return nil
+	}
+	forecast := make([]float64, horizon)
+	if len(series) == 0 {
+		return forecast
+	}
+
+	season := hw.Season
+	if season < 2 || len(series) < 2*season {
+		season = 0
+	}
+
+	var level, trend float64
+	var seasonal []float64
+	start := 1
+	switch {
+	case season > 0:
+		first, second := mean(series[:season]), mean(series[season:2*season])
+		level = first
+		trend = (second - first) / float64(season)
+		seasonal = make([]float64, season)
+		for i := range seasonal {
+			seasonal[i] = series[i] - first
+		}
+		start = season
+	case len(series) > 1:
+		level = series[0]
+		trend = series[1] - series[0]
+	default:
+		level = series[0]
+	}
+
+	for t := start; t < len(series); t++ {
+		var index int
+		var component float64
+		if season > 0 {
+			index = t % season
+			component = seasonal[index]
+		}
+		previous := level
+		level = hw.Alpha*(series[t]-component) + (1-hw.Alpha)*(previous+hw.Damping*trend)
+		trend = hw.Beta*(level-previous) + (1-hw.Beta)*hw.Damping*trend
+		if season > 0 {
+			seasonal[index] = hw.Gamma*(series[t]-level) + (1-hw.Gamma)*component
+		}
+	}
+
+	damped := 0.0
+	factor := 1.0
+	for h := range forecast {
+		factor *= hw.Damping
+		damped += factor
+		value := level + damped*trend
+		if season > 0 {
+			value += seasonal[(len(series)+h)%season]
+		}
+		forecast[h] = math.Max(value, 0)
+	}
+	return forecast
+}
+
+// mean returns the average of the values
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forecast
+
+import (
+	"context"
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// budgetPeriod returns the period of the policy's budget, monthly unless it sets another
+func budgetPeriod(policy *kcloudv1alpha1.CostPolicy) string {
+	if policy.Spec.BudgetPeriod != nil && policy.Spec.BudgetPeriod.Period == kcloudv1alpha1.BudgetPeriodWeekly {
+		return kcloudv1alpha1.BudgetPeriodWeekly
+	}
+	return kcloudv1alpha1.BudgetPeriodMonthly
+}
+
+// periodBounds returns the start and end of the budget period containing t
+func periodBounds(period string, t time.Time) (time.Time, time.Time) {
+	if period == kcloudv1alpha1.BudgetPeriodWeekly {
+		start := weekStart(t)
+		return start, start.AddDate(0, 0, 7)
+	}
+	start := monthStart(t)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// periodNoun names the period in messages
+func periodNoun(period string) string {
+	if period == kcloudv1alpha1.BudgetPeriodWeekly {
+		return "week"
+	}
+	return "month"
+}
+
+// periodBudget returns the budget of each of the policy's periods before any rollover: the
+// monthly budget of a monthly period when set, and the budget limit otherwise
+func periodBudget(policy *kcloudv1alpha1.CostPolicy) float64 {
+	if budgetPeriod(policy) == kcloudv1alpha1.BudgetPeriodMonthly && policy.Spec.MonthlyBudget != nil {
+		return *policy.Spec.MonthlyBudget
+	}
+	return policy.Spec.BudgetLimit
+}
+
+// carriedBudget returns the budget rolled over into the period starting at start. What the
+// status last recorded as remaining rolls over when its period ended at start; a period the
+// status skipped, such as while the operator was down, carries nothing, and neither does
+// overspending.
+func carriedBudget(policy *kcloudv1alpha1.CostPolicy, start time.Time) float64 {
+	spec := policy.Spec.BudgetPeriod
+	if spec == nil || !spec.Rollover {
+		return 0
+	}
+	status := policy.Status
+	if status.PeriodStart != nil && status.PeriodStart.Equal(&metav1.Time{Time: start}) {
+		return ptr.Deref(status.RolloverBudget, 0)
+	}
+	if status.PeriodEnd == nil || !status.PeriodEnd.Equal(&metav1.Time{Time: start}) || status.RemainingBudget == nil {
+		return 0
+	}
+	carried := max(*status.RemainingBudget, 0)
+	if spec.MaxRollover != nil {
+		carried = math.Min(carried, *spec.MaxRollover)
+	}
+	return carried
+}
+
+// trackBudget records the current budget period and the spend against its budget in the
+// policy's status, rolling unused budget over when a new period starts, and returns the
+// period's budget including the rollover
+func (f *BudgetForecaster) trackBudget(ctx context.Context, policy *kcloudv1alpha1.CostPolicy,
+	start, end time.Time, spent float64) float64 {
+	status := &policy.Status
+	carried := carriedBudget(policy, start)
+	if status.PeriodStart != nil && !status.PeriodStart.Equal(&metav1.Time{Time: start}) {
+		log.FromContext(ctx).Info("Budget period reset", "costPolicy", policy.Name,
+			"periodStart", start, "periodEnd", end, "rollover", carried)
+	}
+
+	money := f.Currency.Currency()
+	status.PeriodStart = &metav1.Time{Time: start}
+	status.PeriodEnd = &metav1.Time{Time: end}
+	status.ConsumedBudget = ptr.To(money.Round(spent))
+	status.RolloverBudget, status.RemainingBudget, status.BudgetUtilization = nil, nil, nil
+
+	budget := periodBudget(policy)
+	if budget <= 0 {
+		return 0
+	}
+	budget += carried
+	if carried > 0 {
+		status.RolloverBudget = ptr.To(money.Round(carried))
+	}
+	status.RemainingBudget = ptr.To(money.Round(budget - spent))
+	status.BudgetUtilization = ptr.To(math.Min(spent/budget*100, 100))
+	return budget
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forecast
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/utils/ptr"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+)
+
+// savingsActions are the optimization actions whose savings cost policies report, in order
+var savingsActions = []string{
+	kcloudv1alpha1.SavingsActionRightsizing,
+	kcloudv1alpha1.SavingsActionConsolidation,
+	kcloudv1alpha1.SavingsActionSpot,
+	kcloudv1alpha1.SavingsActionIdleScaleDown,
+}
+
+var periodSavingsUSD = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kcloud_cost_policy_savings_usd",
+	Help: "Savings of the workloads a cost policy covers in the current budget period by optimization action in USD",
+}, []string{"policy", "action"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(periodSavingsUSD)
+}
+
+// ledger accrues the spend and savings of each workload from the start of a month or week
+type ledger struct {
+	start   time.Time
+	spend   map[workloadKey]float64
+	savings map[workloadKey]map[string]float64
+}
+
+// newLedger returns an empty ledger of the period starting at start
+func newLedger(start time.Time) *ledger {
+	return &ledger{
+		start:   start,
+		spend:   make(map[workloadKey]float64),
+		savings: make(map[workloadKey]map[string]float64),
+	}
+}
+
+// spentBy returns the workload's spend; a nil ledger has none
+func (l *ledger) spentBy(key workloadKey) float64 {
+	if l == nil {
+		return 0
+	}
+	return l.spend[key]
+}
+
+// accrueSavings adds the hourly savings of each action over the hours to the workload's savings
+func (l *ledger) accrueSavings(key workloadKey, hourly map[string]float64, hours float64) {
+	if len(hourly) == 0 {
+		return
+	}
+	accrued := l.savings[key]
+	if accrued == nil {
+		accrued = make(map[string]float64, len(hourly))
+		l.savings[key] = accrued
+	}
+	for action, savings := range hourly {
+		accrued[action] += savings * hours
+	}
+}
+
+// consolidationSavings splits the expected hourly savings of each applied consolidation
+// plan evenly between the workloads whose pods it moved
+func consolidationSavings(plans []kcloudv1alpha1.OptimizationPlan) map[workloadKey]float64 {
+	savings := make(map[workloadKey]float64)
+	for _, plan := range plans {
+		if plan.Status.Phase != kcloudv1alpha1.PlanPhaseApplied || len(plan.Status.Workloads) == 0 {
+			continue
+		}
+		share := plan.Spec.ExpectedHourlySavings / float64(len(plan.Status.Workloads))
+		for _, workload := range plan.Status.Workloads {
+			namespace, name, ok := strings.Cut(workload, "/")
+			if ok {
+				savings[workloadKey{namespace: namespace, name: name}] += share
+			}
+		}
+	}
+	return savings
+}
+
+// workloadSavings returns the hourly savings by action of the optimization actions in effect
+// on the workload, with its share of consolidation
+func workloadSavings(wo *kcloudv1alpha1.WorkloadOptimizer, consolidation float64) map[string]float64 {
+	if len(wo.Status.Savings) == 0 && consolidation == 0 {
+		return nil
+	}
+	savings := make(map[string]float64, len(wo.Status.Savings)+1)
+	for _, action := range wo.Status.Savings {
+		savings[action.Action] += action.HourlySavings
+	}
+	if consolidation != 0 {
+		savings[kcloudv1alpha1.SavingsActionConsolidation] += consolidation
+	}
+	return savings
+}
+
+// periodSavings returns what each action saved the selected workloads in the budget period
+// that contains the current time
+func (f *BudgetForecaster) periodSavings(selected map[workloadKey]bool, period string) map[string]float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ledger := f.month
+	if period == kcloudv1alpha1.BudgetPeriodWeekly {
+		ledger = f.week
+	}
+	savings := make(map[string]float64, len(savingsActions))
+	if ledger == nil {
+		return savings
+	}
+	for key := range selected {
+		for action, saved := range ledger.savings[key] {
+			savings[action] += saved
+		}
+	}
+	return savings
+}
+
+// recordSavings records the period's savings by action and their total in the policy's status
+func (f *BudgetForecaster) recordSavings(policy *kcloudv1alpha1.CostPolicy, savings map[string]float64) {
+	money := f.Currency.Currency()
+	var total float64
+	var byAction []kcloudv1alpha1.PeriodSavings
+	for _, action := range savingsActions {
+		if saved, ok := savings[action]; ok {
+			total += saved
+			byAction = append(byAction, kcloudv1alpha1.PeriodSavings{Action: action, Savings: money.Round(saved)})
+		}
+	}
+	policy.Status.TotalSavings = ptr.To(money.Round(total))
+	policy.Status.Savings = byAction
+}
+
+// exportSavings exports the period's savings of the policy by action in USD
+func (f *BudgetForecaster) exportSavings(policy string, savings map[string]float64) {
+	for _, action := range savingsActions {
+		if savingsUSD, err := f.Currency.Convert(savings[action], currency.USD); err == nil {
+			periodSavingsUSD.WithLabelValues(policy, action).Set(savingsUSD)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CompactionResult describes the outcome of a compaction
+type CompactionResult struct {
+	Compacted  int
+	Remaining  int
+	Archive    string
+	Checkpoint *Entry
+}
+
+// Compact moves entries older than before into an archive file and records a checkpoint
+// so the live journal still verifies against the archived chain
+func (j *Journal) Compact(before time.Time) (*CompactionResult, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entries, err := readEntries(j.path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := verifyEntries(entries); err != nil {
+		return nil, fmt.Errorf("refusing to compact: %w", err)
+	}
+
+	cut := 0
+	for cut < len(entries) && entries[cut].Time.Before(before) {
+		cut++
+	}
+	if cut == 0 {
+		return &CompactionResult{Remaining: len(entries)}, nil
+	}
+
+	compacted := entries[:cut]
+	last := compacted[len(compacted)-1]
+	archive := fmt.Sprintf("%s.%d-%d.archive", j.path, compacted[0].Seq, last.Seq)
+	if err := writeFileAtomic(archive, compacted); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	raw, err := json.Marshal(&Checkpoint{
+		FirstSeq:    compacted[0].Seq,
+		ThroughSeq:  last.Seq,
+		ThroughHash: last.Hash,
+		Entries:     len(compacted),
+		Archive:     filepath.Base(archive),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	// Record the checkpoint in the live chain before truncating so a crash leaves a valid journal
+	checkpoint, err := j.appendLocked(CheckpointType, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := append(append([]Entry{}, entries[cut:]...), *checkpoint)
+	if err := writeFileAtomic(j.path, remaining); err != nil {
+		return nil, fmt.Errorf("failed to rewrite journal: %w", err)
+	}
+
+	// Reopen since the file was replaced
+	if err := j.file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close journal: %w", err)
+	}
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reopen journal: %w", err)
+	}
+	j.file = file
+
+	return &CompactionResult{
+		Compacted:  len(compacted),
+		Remaining:  len(remaining),
+		Archive:    archive,
+		Checkpoint: checkpoint,
+	}, nil
+}
+
+// Compactor periodically compacts a journal, keeping entries within the retention window
+type Compactor struct {
+	Journal   *Journal
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// NewCompactor creates a new periodic compactor
+func NewCompactor(j *Journal, retention, interval time.Duration) *Compactor {
+	return &Compactor{
+		Journal:   j,
+		Retention: retention,
+		Interval:  interval,
+	}
+}
+
+// Start runs compaction on each interval until the context is cancelled
+func (c *Compactor) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("journal")
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			result, err := c.Journal.Compact(time.Now().Add(-c.Retention))
+			if err != nil {
+				log.Error(err, "Failed to compact decision journal")
+				continue
+			}
+			seq, hash := c.Journal.Head()
+			log.Info("Decision journal compacted",
+				"compacted", result.Compacted,
+				"remaining", result.Remaining,
+				"archive", result.Archive,
+				"headSeq", seq,
+				"headHash", hash)
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (c *Compactor) NeedLeaderElection() bool {
+	return false
+}
+
+// writeFileAtomic writes entries to a temporary file and renames it into place
+func writeFileAtomic(path string, entries []Entry) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if err := writeEntry(f, &entries[i]); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CheckpointType marks the record left in place of compacted entries
+const CheckpointType = "checkpoint"
+
+// ErrTampered is returned when the journal's hash chain does not verify
+var ErrTampered = errors.New("journal integrity check failed")
+
+// Entry is a single checksum-protected journal record
+type Entry struct {
+	Seq      uint64          `json:"seq"`
+	Time     time.Time       `json:"time"`
+	Type     string          `json:"type"`
+	Data     json.RawMessage `json:"data,omitempty"`
+	PrevHash string          `json:"prevHash"`
+	Hash     string          `json:"hash"`
+}
+
+// Checkpoint describes the entries removed by a compaction
+type Checkpoint struct {
+	FirstSeq    uint64 `json:"firstSeq"`
+	ThroughSeq  uint64 `json:"throughSeq"`
+	ThroughHash string `json:"throughHash"`
+	Entries     int    `json:"entries"`
+	Archive     string `json:"archive,omitempty"`
+}
+
+// Journal is an append-only, hash-chained log of decisions stored as JSON lines
+type Journal struct {
+	path     string
+	file     *os.File
+	lastSeq  uint64
+	lastHash string
+	mutex    sync.Mutex
+}
+
+// Open opens or creates the journal at path, verifying the existing chain
+func Open(path string) (*Journal, error) {
+	report, err := VerifyFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal %s: %w", path, err)
+	}
+
+	j := &Journal{path: path, file: file}
+	if report != nil {
+		j.lastSeq = report.LastSeq
+		j.lastHash = report.HeadHash
+	}
+	return j, nil
+}
+
+// Append writes a new entry whose hash chains to the previous one
+func (j *Journal) Append(entryType string, data interface{}) (*Entry, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal journal data: %w", err)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.appendLocked(entryType, raw)
+}
+
+// appendLocked appends an entry; callers must hold the mutex
+func (j *Journal) appendLocked(entryType string, raw json.RawMessage) (*Entry, error) {
+	entry := &Entry{
+		Seq:      j.lastSeq + 1,
+		Time:     time.Now().UTC(),
+		Type:     entryType,
+		Data:     raw,
+		PrevHash: j.lastHash,
+	}
+	entry.Hash = entryHash(entry)
+
+	if err := writeEntry(j.file, entry); err != nil {
+		return nil, err
+	}
+	if err := j.file.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync journal: %w", err)
+	}
+
+	j.lastSeq = entry.Seq
+	j.lastHash = entry.Hash
+	return entry, nil
+}
+
+// Head returns the sequence number and hash of the latest entry, suitable for external anchoring
+func (j *Journal) Head() (uint64, string) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.lastSeq, j.lastHash
+}
+
+// Path returns the journal file path
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Close closes the underlying journal file
+func (j *Journal) Close() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.file.Close()
+}
+
+// entryHash computes the checksum that covers an entry and its link to the previous one
+func entryHash(e *Entry) string {
+	h := sha256.New()
+	h.Write([]byte(strconv.FormatUint(e.Seq, 10)))
+	h.Write([]byte{0})
+	h.Write([]byte(e.Time.UTC().Format(time.RFC3339Nano)))
+	h.Write([]byte{0})
+	h.Write([]byte(e.Type))
+	h.Write([]byte{0})
+	h.Write(e.Data)
+	h.Write([]byte{0})
+	h.Write([]byte(e.PrevHash))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeEntry writes an entry as a single JSON line
+func writeEntry(f *os.File, e *Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	return nil
+}
+
+// readEntries reads all entries from a journal file
+func readEntries(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%w: line %d is not a valid entry: %v", ErrTampered, line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal %s: %w", path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// TombstoneType marks an entry whose data was purged; it keeps its place and hash in the chain
+	TombstoneType = "tombstone"
+	// PurgeType marks the record of a purge, listing the entries it turned into tombstones
+	PurgeType = "purge"
+)
+
+// Tombstone is the data left in place of a purged entry
+type Tombstone struct {
+	// PurgedType is the type the entry had before it was purged
+	PurgedType string `json:"purgedType"`
+}
+
+// PurgedEntry identifies an entry turned into a tombstone
+type PurgedEntry struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// PurgeRecord describes a purge. Tombstones verify against the seq and hash listed here
+// since their original data can no longer be hashed.
+type PurgeRecord struct {
+	Entries []PurgedEntry   `json:"entries"`
+	Files   []string        `json:"files,omitempty"`
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// PurgeResult describes the outcome of a purge
+type PurgeResult struct {
+	Purged int
+	Files  []string
+	Record *Entry
+}
+
+// Purge turns the entries that match into tombstones, in the live journal and in every
+// archive it still references, and records a purge entry carrying details. Checkpoints,
+// tombstones and purge records are never matched.
+func (j *Journal) Purge(match func(Entry) bool, details interface{}) (*PurgeResult, error) {
+	rawDetails, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal purge details: %w", err)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	live, err := readEntries(j.path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := verifyEntries(live); err != nil {
+		return nil, fmt.Errorf("refusing to purge: %w", err)
+	}
+
+	record := &PurgeRecord{Details: rawDetails}
+	archives := make(map[string][]Entry)
+	for _, archive := range j.archivesLocked(live) {
+		entries, err := readEntries(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive %s: %w", archive, err)
+		}
+		if purged := redactEntries(entries, match); len(purged) > 0 {
+			record.Entries = append(record.Entries, purged...)
+			record.Files = append(record.Files, filepath.Base(archive))
+			archives[archive] = entries
+		}
+	}
+	livePurged := redactEntries(live, match)
+	if len(livePurged) > 0 {
+		record.Entries = append(record.Entries, livePurged...)
+		record.Files = append(record.Files, filepath.Base(j.path))
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal purge record: %w", err)
+	}
+
+	// Record the purge before rewriting so every tombstone on disk is covered by it
+	entry, err := j.appendLocked(PurgeType, raw)
+	if err != nil {
+		return nil, err
+	}
+	result := &PurgeResult{Purged: len(record.Entries), Files: record.Files, Record: entry}
+	if len(record.Entries) == 0 {
+		return result, nil
+	}
+
+	for archive, entries := range archives {
+		if err := writeFileAtomic(archive, entries); err != nil {
+			return nil, fmt.Errorf("failed to rewrite archive %s: %w", archive, err)
+		}
+	}
+	if len(livePurged) == 0 {
+		return result, nil
+	}
+	if err := writeFileAtomic(j.path, append(live, *entry)); err != nil {
+		return nil, fmt.Errorf("failed to rewrite journal: %w", err)
+	}
+
+	// Reopen since the file was replaced
+	if err := j.file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close journal: %w", err)
+	}
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reopen journal: %w", err)
+	}
+	j.file = file
+	return result, nil
+}
+
+// archivesLocked returns the archive files reachable through the checkpoints of entries,
+// newest first; archives removed from disk are skipped
+func (j *Journal) archivesLocked(entries []Entry) []string {
+	var archives []string
+	seen := make(map[string]bool)
+	for len(entries) > 0 {
+		var next []Entry
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entries[i].Type != CheckpointType {
+				continue
+			}
+			var cp Checkpoint
+			if err := json.Unmarshal(entries[i].Data, &cp); err != nil || cp.Archive == "" {
+				continue
+			}
+			archive := filepath.Join(filepath.Dir(j.path), cp.Archive)
+			if seen[archive] {
+				continue
+			}
+			seen[archive] = true
+			archived, err := readEntries(archive)
+			if err != nil {
+				continue
+			}
+			archives = append(archives, archive)
+			next = append(next, archived...)
+		}
+		entries = next
+	}
+	return archives
+}
+
+// redactEntries replaces the data of matching entries with a tombstone, keeping their hashes
+func redactEntries(entries []Entry, match func(Entry) bool) []PurgedEntry {
+	var purged []PurgedEntry
+	for i := range entries {
+		e := &entries[i]
+		switch e.Type {
+		case CheckpointType, TombstoneType, PurgeType:
+			continue
+		}
+		if !match(*e) {
+			continue
+		}
+		raw, _ := json.Marshal(&Tombstone{PurgedType: e.Type})
+		e.Type = TombstoneType
+		e.Data = raw
+		purged = append(purged, PurgedEntry{Seq: e.Seq, Hash: e.Hash})
+	}
+	return purged
+}
+
+// ReadDecisions returns the entries of journal files, oldest first, leaving out
+// checkpoints, purge records and tombstones so purged decisions never reach consumers
+// such as training pipelines. The files are verified as one chain first.
+func ReadDecisions(paths ...string) ([]Entry, error) {
+	var entries []Entry
+	for _, path := range paths {
+		fileEntries, err := readEntries(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	if _, err := verifyEntries(entries); err != nil {
+		return nil, err
+	}
+
+	decisions := entries[:0]
+	for _, e := range entries {
+		switch e.Type {
+		case CheckpointType, TombstoneType, PurgeType:
+			continue
+		}
+		decisions = append(decisions, e)
+	}
+	return decisions, nil
+}
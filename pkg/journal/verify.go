@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// VerifyReport summarizes a successful integrity verification
+type VerifyReport struct {
+	Entries     int
+	Checkpoints int
+	Tombstones  int
+	FirstSeq    uint64
+	LastSeq     uint64
+	// AnchorHash is the hash the first entry chains to; empty when the chain starts at genesis
+	AnchorHash string
+	HeadHash   string
+}
+
+// VerifyFile verifies a single journal file
+func VerifyFile(path string) (*VerifyReport, error) {
+	return VerifyFiles(path)
+}
+
+// VerifyFiles verifies archives and the live journal, in order, as one continuous chain
+func VerifyFiles(paths ...string) (*VerifyReport, error) {
+	var entries []Entry
+	for _, path := range paths {
+		fileEntries, err := readEntries(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return verifyEntries(entries)
+}
+
+// verifyEntries checks every checksum and link in the chain
+func verifyEntries(entries []Entry) (*VerifyReport, error) {
+	report := &VerifyReport{Entries: len(entries)}
+	if len(entries) == 0 {
+		return report, nil
+	}
+
+	first := entries[0]
+	report.FirstSeq = first.Seq
+	report.AnchorHash = first.PrevHash
+
+	if first.Seq == 1 {
+		if first.PrevHash != "" {
+			return nil, fmt.Errorf("%w: genesis entry has a previous hash", ErrTampered)
+		}
+	} else if !hasCheckpointFor(entries, first.Seq-1, first.PrevHash) {
+		return nil, fmt.Errorf("%w: entries before seq %d are missing and no checkpoint covers them",
+			ErrTampered, first.Seq)
+	}
+
+	purged := purgedEntries(entries)
+	prevSeq := first.Seq - 1
+	prevHash := first.PrevHash
+	for i := range entries {
+		e := &entries[i]
+		if e.Seq != prevSeq+1 {
+			return nil, fmt.Errorf("%w: expected seq %d, found %d", ErrTampered, prevSeq+1, e.Seq)
+		}
+		if e.PrevHash != prevHash {
+			return nil, fmt.Errorf("%w: seq %d does not chain to seq %d", ErrTampered, e.Seq, prevSeq)
+		}
+		switch {
+		case e.Type == TombstoneType:
+			// The purged data cannot be rehashed, so the purge record vouches for the hash
+			if purged[e.Seq] != e.Hash {
+				return nil, fmt.Errorf("%w: tombstone at seq %d is not covered by a purge record", ErrTampered, e.Seq)
+			}
+			report.Tombstones++
+		case entryHash(e) != e.Hash:
+			return nil, fmt.Errorf("%w: checksum mismatch at seq %d", ErrTampered, e.Seq)
+		case e.Type == CheckpointType:
+			report.Checkpoints++
+		}
+		prevSeq = e.Seq
+		prevHash = e.Hash
+	}
+
+	report.LastSeq = prevSeq
+	report.HeadHash = prevHash
+	return report, nil
+}
+
+// hasCheckpointFor reports whether a checkpoint entry records compaction through seq with the given hash
+func hasCheckpointFor(entries []Entry, seq uint64, hash string) bool {
+	for _, e := range entries {
+		if e.Type != CheckpointType {
+			continue
+		}
+		var cp Checkpoint
+		if err := json.Unmarshal(e.Data, &cp); err != nil {
+			continue
+		}
+		if cp.ThroughSeq == seq && cp.ThroughHash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// purgedEntries returns the hashes of the entries purge records turned into tombstones, by seq
+func purgedEntries(entries []Entry) map[uint64]string {
+	purged := make(map[uint64]string)
+	for _, e := range entries {
+		if e.Type != PurgeType {
+			continue
+		}
+		var record PurgeRecord
+		if err := json.Unmarshal(e.Data, &record); err != nil {
+			continue
+		}
+		for _, p := range record.Entries {
+			purged[p.Seq] = p.Hash
+		}
+	}
+	return purged
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messages
+
+// builtin holds the messages shipped with the operator. English is the reference locale:
+// every message ID must have an English template.
+var builtin = map[string]map[ID]string{
+	"en": {
+		ConditionOptimized:        `Workload optimization completed with score {{printf "%.2f" .Score}}`,
+		ConditionCostWithinBudget: `Current cost {{money .Cost}}/hour is within constraints`,
+		ConditionPowerWithinLimit: `Current power {{printf "%.2f" .Power}}W is within constraints`,
+
+		UsageWithinBaseline:     `Cost and power are within the workload's baseline`,
+		UsageCollectingBaseline: `Not enough usage samples for a baseline yet`,
+		UsageCostAboveBaseline: `Cost {{money .Cost}}/hour is {{printf "%.0f" .Percent}}% above ` +
+			`the baseline of {{money .Baseline}}/hour`,
+		UsagePowerAboveBaseline: `Power {{printf "%.2f" .Power}}W is {{printf "%.0f" .Percent}}% above ` +
+			`the baseline of {{printf "%.2f" .Baseline}}W`,
+
+		LeaseNone: `The workload holds no do-not-disturb lease`,
+		LeaseActive: `exempt from descheduling until {{.Until}} by {{.Source}} lease: ` +
+			`{{if .Reason}}{{.Reason}}{{else}}no reason given{{end}}`,
+		LeaseExpired: `Do-not-disturb lease expired at {{.Until}}`,
+
+		SchedulingFailed: `No node can run the workload ({{.Rejected}} of {{.Nodes}} nodes rejected): {{.Reasons}}; ` +
+			`retrying in {{.RetryAfter}}`,
+		SchedulingRecovered: `Placed on node {{.Node}}`,
+
+		ForecastWithinBudget: `Projected {{.Period}}ly cost {{money .Projected}} is within the budget of ` +
+			`{{money .Budget}}`,
+		ForecastOverBudget: `Projected {{.Period}}ly cost {{money .Projected}} is {{printf "%.0f" .Percent}}% ` +
+			`over the budget of {{money .Budget}}`,
+
+		CarbonWithinBudget: `Projected monthly emissions of {{printf "%.0f" .Projected}} g CO2e are within the budget of ` +
+			`{{printf "%.0f" .Budget}} g CO2e`,
+		CarbonOverBudget: `Projected monthly emissions of {{printf "%.0f" .Projected}} g CO2e are ` +
+			`{{printf "%.0f" .Percent}}% over the budget of {{printf "%.0f" .Budget}} g CO2e`,
+		CarbonRateWithinLimit: `Emissions of {{printf "%.0f" .Rate}} g CO2e/hour are within the limit of ` +
+			`{{printf "%.0f" .Limit}} g CO2e/hour`,
+		CarbonRateOverLimit: `Emissions of {{printf "%.0f" .Rate}} g CO2e/hour exceed the limit of ` +
+			`{{printf "%.0f" .Limit}} g CO2e/hour`,
+
+		PowerWithinLimit: `Power of {{printf "%.0f" .Power}} W is within the limit of {{printf "%.0f" .Limit}} W`,
+		PowerOverLimit:   `Power of {{printf "%.0f" .Power}} W exceeds the limit of {{printf "%.0f" .Limit}} W`,
+		PowerCapped:      `{{.State}} by power policy {{.Policy}} to keep power under {{printf "%.0f" .Limit}} W`,
+
+		AdmissionCostEstimate: `estimated {{money .Cost}}/hr`,
+		AdmissionCostEstimateBudget: `estimated {{money .Cost}}/hr, namespace at {{printf "%.0f" .Utilization}}% ` +
+			`of {{.Period}}ly budget`,
+
+		AlertUsageAboveBaseline: `{{.Category}} usage above baseline`,
+		AlertForecastOverBudget: `projected spend of cost policy {{.Policy}} exceeds its budget`,
+		AlertCarbonOverBudget:   `projected emissions of {{.Name}} exceed its carbon budget`,
+		AlertSubject:            `[kcloud] {{.Severity}} {{.Category}} alert for {{.Team}}: {{.Title}}`,
+		DigestSubject:           `[kcloud] {{.Schedule}} digest for {{.Team}}: {{.Total}} alerts`,
+		DigestBody: `{{range .Sections}}{{.Category}} ({{len .Items}})
+{{range .Items}}  - [{{.Severity}}] {{.Title}}{{if gt .Count 1}} (x{{.Count}}){{end}}: {{.Message}}
+{{end}}{{end}}{{if .Dropped}}{{.Dropped}} older alerts were dropped
+{{end}}`,
+	},
+	"ko": {
+		ConditionOptimized:        `워크로드 최적화가 완료되었습니다 (점수 {{printf "%.2f" .Score}})`,
+		ConditionCostWithinBudget: `현재 비용(시간당 {{money .Cost}})이 제약 조건 이내입니다`,
+		ConditionPowerWithinLimit: `현재 전력({{printf "%.2f" .Power}}W)이 제약 조건 이내입니다`,
+
+		UsageWithinBaseline:     `비용과 전력이 워크로드 기준선 이내입니다`,
+		UsageCollectingBaseline: `기준선을 계산할 사용량 샘플이 아직 부족합니다`,
+		UsageCostAboveBaseline: `비용(시간당 {{money .Cost}})이 기준선(시간당 {{money .Baseline}})보다 ` +
+			`{{printf "%.0f" .Percent}}% 높습니다`,
+		UsagePowerAboveBaseline: `전력({{printf "%.2f" .Power}}W)이 기준선({{printf "%.2f" .Baseline}}W)보다 ` +
+			`{{printf "%.0f" .Percent}}% 높습니다`,
+
+		LeaseNone: `워크로드에 방해 금지(do-not-disturb) 임대가 없습니다`,
+		LeaseActive: `{{.Source}} 임대에 따라 {{.Until}}까지 재배치에서 제외됩니다: ` +
+			`{{if .Reason}}{{.Reason}}{{else}}사유 없음{{end}}`,
+		LeaseExpired: `방해 금지 임대가 {{.Until}}에 만료되었습니다`,
+
+		SchedulingFailed: `워크로드를 실행할 수 있는 노드가 없습니다 (노드 {{.Nodes}}개 중 {{.Rejected}}개 제외): {{.Reasons}}; ` +
+			`{{.RetryAfter}} 후 다시 시도합니다`,
+		SchedulingRecovered: `{{.Node}} 노드에 배치되었습니다`,
+
+		ForecastWithinBudget: `예상 {{if eq .Period "week"}}주{{else}}월{{end}} 비용({{money .Projected}})이 예산({{money .Budget}}) 이내입니다`,
+		ForecastOverBudget: `예상 {{if eq .Period "week"}}주{{else}}월{{end}} 비용({{money .Projected}})이 예산({{money .Budget}})보다 ` +
+			`{{printf "%.0f" .Percent}}% 많습니다`,
+
+		CarbonWithinBudget: `예상 월 배출량({{printf "%.0f" .Projected}} g CO2e)이 탄소 예산({{printf "%.0f" .Budget}} g CO2e) 이내입니다`,
+		CarbonOverBudget: `예상 월 배출량({{printf "%.0f" .Projected}} g CO2e)이 탄소 예산({{printf "%.0f" .Budget}} g CO2e)보다 ` +
+			`{{printf "%.0f" .Percent}}% 많습니다`,
+		CarbonRateWithinLimit: `배출량(시간당 {{printf "%.0f" .Rate}} g CO2e)이 한도(시간당 {{printf "%.0f" .Limit}} g CO2e) 이내입니다`,
+		CarbonRateOverLimit:   `배출량(시간당 {{printf "%.0f" .Rate}} g CO2e)이 한도(시간당 {{printf "%.0f" .Limit}} g CO2e)를 초과합니다`,
+
+		PowerWithinLimit: `전력({{printf "%.0f" .Power}} W)이 한도({{printf "%.0f" .Limit}} W) 이내입니다`,
+		PowerOverLimit:   `전력({{printf "%.0f" .Power}} W)이 한도({{printf "%.0f" .Limit}} W)를 초과합니다`,
+		PowerCapped: `전력을 {{printf "%.0f" .Limit}} W 미만으로 유지하기 위해 전력 정책 {{.Policy}}에 의해 ` +
+			`{{if eq .State "Paused"}}일시 중지{{else}}축소{{end}}되었습니다`,
+
+		AdmissionCostEstimate: `예상 비용 시간당 {{money .Cost}}`,
+		AdmissionCostEstimateBudget: `예상 비용 시간당 {{money .Cost}}, 네임스페이스가 ` +
+			`{{if eq .Period "week"}}주{{else}}월{{end}} 예산의 {{printf "%.0f" .Utilization}}%를 사용했습니다`,
+
+		AlertUsageAboveBaseline: `{{.Category}} 사용량이 기준선을 초과했습니다`,
+		AlertForecastOverBudget: `비용 정책 {{.Policy}}의 예상 지출이 예산을 초과합니다`,
+		AlertCarbonOverBudget:   `{{.Name}}의 예상 배출량이 탄소 예산을 초과합니다`,
+		AlertSubject:            `[kcloud] {{.Team}} 팀 {{.Category}} {{.Severity}} 알림: {{.Title}}`,
+		DigestSubject:           `[kcloud] {{.Team}} 팀 {{.Schedule}} 요약: 알림 {{.Total}}건`,
+		DigestBody: `{{range .Sections}}{{.Category}} ({{len .Items}}건)
+{{range .Items}}  - [{{.Severity}}] {{.Title}}{{if gt .Count 1}} ({{.Count}}회){{end}}: {{.Message}}
+{{end}}{{end}}{{if .Dropped}}이전 알림 {{.Dropped}}건이 삭제되었습니다
+{{end}}`,
+	},
+}
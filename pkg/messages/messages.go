@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package messages renders user-facing operator text from a localized template catalog
+package messages
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+)
+
+// ID identifies a user-facing message
+type ID string
+
+// Messages shown in conditions, alerts, notification digests and admission warnings
+const (
+	ConditionOptimized        ID = "condition.optimized"
+	ConditionCostWithinBudget ID = "condition.cost-within-budget"
+	ConditionPowerWithinLimit ID = "condition.power-within-limit"
+
+	UsageWithinBaseline     ID = "usage.within-baseline"
+	UsageCollectingBaseline ID = "usage.collecting-baseline"
+	UsageCostAboveBaseline  ID = "usage.cost-above-baseline"
+	UsagePowerAboveBaseline ID = "usage.power-above-baseline"
+
+	LeaseNone    ID = "lease.none"
+	LeaseActive  ID = "lease.active"
+	LeaseExpired ID = "lease.expired"
+
+	SchedulingFailed    ID = "scheduling.failed"
+	SchedulingRecovered ID = "scheduling.recovered"
+
+	ForecastWithinBudget ID = "forecast.within-budget"
+	ForecastOverBudget   ID = "forecast.over-budget"
+
+	CarbonWithinBudget    ID = "carbon.within-budget"
+	CarbonOverBudget      ID = "carbon.over-budget"
+	CarbonRateWithinLimit ID = "carbon.rate-within-limit"
+	CarbonRateOverLimit   ID = "carbon.rate-over-limit"
+
+	PowerWithinLimit ID = "power.within-limit"
+	PowerOverLimit   ID = "power.over-limit"
+	PowerCapped      ID = "power.capped"
+
+	AdmissionCostEstimate       ID = "admission.cost-estimate"
+	AdmissionCostEstimateBudget ID = "admission.cost-estimate-budget"
+
+	AlertUsageAboveBaseline ID = "alert.usage-above-baseline"
+	AlertForecastOverBudget ID = "alert.forecast-over-budget"
+	AlertCarbonOverBudget   ID = "alert.carbon-over-budget"
+	AlertSubject            ID = "alert.subject"
+	DigestSubject           ID = "digest.subject"
+	DigestBody              ID = "digest.body"
+)
+
+// LocaleAnnotation selects the language of a workload's conditions and alerts
+const LocaleAnnotation = "kcloud.io/locale"
+
+// DefaultLocale is used when no locale is configured
+const DefaultLocale = "en"
+
+// Catalog holds message templates per locale. It is read-only once built and safe
+// for concurrent use. Templates format amounts with {{money .Cost}} in the catalog's currency.
+type Catalog struct {
+	defaultLocale string
+	currency      currency.Currency
+	templates     map[string]map[ID]*template.Template
+}
+
+var defaultCatalog = mustCatalog(DefaultLocale, currency.Default, nil)
+
+// Default returns the built-in catalog with English as the default locale
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+// NewCatalog builds a catalog formatting amounts in the currency from the built-in messages
+// and the overrides, which may replace built-in messages or add locales. Locales lacking a
+// message fall back to the default locale and then to English.
+func NewCatalog(defaultLocale string, money currency.Currency, overrides map[string]map[ID]string) (*Catalog, error) {
+	if defaultLocale == "" {
+		defaultLocale = DefaultLocale
+	}
+	if money == "" {
+		money = currency.Default
+	}
+
+	c := &Catalog{
+		defaultLocale: defaultLocale,
+		currency:      money,
+		templates:     make(map[string]map[ID]*template.Template),
+	}
+	funcs := template.FuncMap{"money": c.currency.Format}
+	for _, source := range []map[string]map[ID]string{builtin, overrides} {
+		for locale, messages := range source {
+			for id, text := range messages {
+				if _, known := builtin[DefaultLocale][id]; !known {
+					return nil, fmt.Errorf("unknown message %q in locale %s", id, locale)
+				}
+				tmpl, err := template.New(string(id)).Option("missingkey=zero").Funcs(funcs).Parse(text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid message %s in locale %s: %w", id, locale, err)
+				}
+				if c.templates[locale] == nil {
+					c.templates[locale] = make(map[ID]*template.Template)
+				}
+				c.templates[locale][id] = tmpl
+			}
+		}
+	}
+
+	if _, ok := c.templates[defaultLocale]; !ok {
+		return nil, fmt.Errorf("unknown locale %q, available locales are %s",
+			defaultLocale, strings.Join(c.Locales(), ", "))
+	}
+	return c, nil
+}
+
+// mustCatalog builds a catalog and panics on error, for built-in catalogs
+func mustCatalog(defaultLocale string, money currency.Currency, overrides map[string]map[ID]string) *Catalog {
+	c, err := NewCatalog(defaultLocale, money, overrides)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Locales returns the locales in the catalog, sorted
+func (c *Catalog) Locales() []string {
+	locales := make([]string, 0, len(c.templates))
+	for locale := range c.templates {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Render renders a message in the locale, or in the catalog's default locale when
+// locale is empty or lacks the message. A message that fails to render in every
+// locale is returned as its ID so callers always have text to show.
+func (c *Catalog) Render(locale string, id ID, data any) string {
+	if c == nil {
+		c = defaultCatalog
+	}
+	if locale == "" {
+		locale = c.defaultLocale
+	}
+
+	for _, candidate := range []string{locale, c.defaultLocale, DefaultLocale} {
+		tmpl, ok := c.templates[candidate][id]
+		if !ok {
+			continue
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, data); err == nil {
+			return out.String()
+		}
+	}
+	return string(id)
+}
+
+// catalogFile is the layout of a message catalog override file
+type catalogFile struct {
+	// Locales maps each locale to message IDs and their templates
+	Locales map[string]map[ID]string `json:"locales"`
+}
+
+// LoadOverrides reads a YAML or JSON file of message templates per locale
+func LoadOverrides(path string) (map[string]map[ID]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message catalog: %w", err)
+	}
+
+	var file catalogFile
+	if err := yaml.UnmarshalStrict(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse message catalog: %w", err)
+	}
+	return file.Locales, nil
+}
@@ -25,12 +25,24 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
 )
 
 // SystemMetricsCollector collects system-wide metrics from Kubernetes
 type SystemMetricsCollector struct {
 	client  client.Client
 	metrics *MetricsCollector
+	// currency is the reporting currency cost metrics are in; nil is US dollars
+	currency *currency.Converter
+	// power reports what nodes were measured drawing; nil estimates node power from the
+	// instance type
+	power NodePowerSource
+}
+
+// NodePowerSource reports the power nodes were measured drawing
+type NodePowerSource interface {
+	// NodePower returns the node's average power in watts and whether it is known
+	NodePower(node string) (float64, bool)
 }
 
 // NewSystemMetricsCollector creates a new system metrics collector
@@ -41,6 +53,17 @@ func NewSystemMetricsCollector(client client.Client, metrics *MetricsCollector)
 	}
 }
 
+// SetCurrency sets the reporting currency recorded alongside the cost metrics
+func (smc *SystemMetricsCollector) SetCurrency(converter *currency.Converter) {
+	smc.currency = converter
+}
+
+// SetNodePower sets the source of measured node power, such as Kepler, recorded instead of
+// the instance type estimate for nodes it knows
+func (smc *SystemMetricsCollector) SetNodePower(source NodePowerSource) {
+	smc.power = source
+}
+
 // CollectNodeMetrics collects metrics from all nodes
 func (smc *SystemMetricsCollector) CollectNodeMetrics(ctx context.Context) error {
 	log := log.FromContext(ctx)
@@ -170,6 +193,11 @@ func (smc *SystemMetricsCollector) estimateNodeCost(node *corev1.Node) float64 {
 
 // estimateNodePower estimates the power consumption of a node
 func (smc *SystemMetricsCollector) estimateNodePower(node *corev1.Node) float64 {
+	if smc.power != nil {
+		if watts, ok := smc.power.NodePower(node.Name); ok {
+			return watts
+		}
+	}
 	instanceType := smc.getInstanceType(node)
 
 	// Simplified power estimation based on instance type
@@ -241,6 +269,9 @@ func (smc *SystemMetricsCollector) collectWorkloadOptimizerMetrics(ctx context.C
 	if workload.Status.CurrentPower != nil {
 		smc.metrics.RecordWorkloadOptimizerPower(namespace, name, workloadType, *workload.Status.CurrentPower)
 	}
+	if workload.Status.GPUPower != nil {
+		smc.metrics.RecordWorkloadOptimizerGPUPower(namespace, name, workloadType, *workload.Status.GPUPower)
+	}
 
 	log.V(1).Info("Collected WorkloadOptimizer metrics",
 		"namespace", namespace,
@@ -389,6 +420,11 @@ func (smc *SystemMetricsCollector) collectAllMetrics(ctx context.Context) {
 
 	log.V(1).Info("Starting periodic metrics collection")
 
+	// Record the currency cost metrics are reported in
+	if rate, ok := smc.currency.Rate(smc.currency.Currency()); ok {
+		smc.metrics.RecordCurrency(string(smc.currency.Currency()), rate)
+	}
+
 	// Collect node metrics
 	if err := smc.CollectNodeMetrics(ctx); err != nil {
 		log.Error(err, "Failed to collect node metrics")
@@ -47,6 +47,7 @@ type MetricsCollector struct {
 	costOptimizationSavings    prometheus.Counter
 	costOptimizationViolations prometheus.Counter
 	costPerHour                *prometheus.GaugeVec
+	podCostPerHour             *prometheus.GaugeVec
 	budgetUtilization          *prometheus.GaugeVec
 
 	// Power optimization metrics
@@ -54,6 +55,7 @@ type MetricsCollector struct {
 	powerOptimizationSavings    prometheus.Counter
 	powerOptimizationViolations prometheus.Counter
 	powerUsage                  *prometheus.GaugeVec
+	gpuPowerUsage               *prometheus.GaugeVec
 	powerEfficiency             *prometheus.GaugeVec
 
 	// Webhook metrics
@@ -70,6 +72,13 @@ type MetricsCollector struct {
 	// Policy metrics
 	policyViolations *prometheus.CounterVec
 	policyCompliance *prometheus.GaugeVec
+
+	// Usage baseline metrics
+	workloadBaselineDeviation *prometheus.GaugeVec
+
+	// Currency metrics
+	costCurrency *prometheus.GaugeVec
+	exchangeRate *prometheus.GaugeVec
 }
 
 // NewMetricsCollector creates a new metrics collector
@@ -95,7 +104,7 @@ func NewMetricsCollector() *MetricsCollector {
 		}, []string{"namespace", "name", "workload_type"}),
 		workloadOptimizerCost: promauto.NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "kcloud_workloadoptimizer_cost_per_hour",
-			Help:    "Cost per hour of WorkloadOptimizer resources",
+			Help:    "Cost per hour of WorkloadOptimizer resources in the reporting currency",
 			Buckets: prometheus.ExponentialBuckets(0.1, 2, 10), // 0.1 to 51.2
 		}, []string{"namespace", "name", "workload_type"}),
 		workloadOptimizerPower: promauto.NewHistogramVec(prometheus.HistogramOpts{
@@ -142,9 +151,13 @@ func NewMetricsCollector() *MetricsCollector {
 			Help: "Total number of cost constraint violations",
 		}),
 		costPerHour: promauto.NewGaugeVec(prometheus.GaugeOpts{
-			Name: "kcloud_cost_per_hour_usd",
-			Help: "Current cost per hour in USD",
+			Name: "kcloud_cost_per_hour",
+			Help: "Current cost per hour in the reporting currency",
 		}, []string{"namespace", "name", "workload_type"}),
+		podCostPerHour: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kcloud_pod_cost_per_hour",
+			Help: "Cost per hour of each running pod of a workload in the reporting currency",
+		}, []string{"namespace", "workload", "pod", "node"}),
 		budgetUtilization: promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "kcloud_budget_utilization_ratio",
 			Help: "Budget utilization ratio (0.0 to 1.0)",
@@ -167,6 +180,10 @@ func NewMetricsCollector() *MetricsCollector {
 			Name: "kcloud_power_usage_watts",
 			Help: "Current power usage in Watts",
 		}, []string{"namespace", "name", "workload_type"}),
+		gpuPowerUsage: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kcloud_gpu_power_usage_watts",
+			Help: "Measured GPU power usage in Watts",
+		}, []string{"namespace", "name", "workload_type"}),
 		powerEfficiency: promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "kcloud_power_efficiency_ratio",
 			Help: "Power efficiency ratio (0.0 to 1.0)",
@@ -214,6 +231,22 @@ func NewMetricsCollector() *MetricsCollector {
 			Name: "kcloud_policy_compliance_ratio",
 			Help: "Policy compliance ratio (0.0 to 1.0)",
 		}, []string{"policy_type", "policy_name"}),
+
+		// Usage baseline metrics
+		workloadBaselineDeviation: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kcloud_workload_baseline_deviation_ratio",
+			Help: "Deviation of a workload's usage from its own trailing baseline (1.0 means double)",
+		}, []string{"namespace", "name", "resource"}),
+
+		// Currency metrics
+		costCurrency: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kcloud_cost_currency_info",
+			Help: "Currency cost, spend and savings metrics are reported in, set to 1",
+		}, []string{"currency"}),
+		exchangeRate: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kcloud_exchange_rate",
+			Help: "Units of the reporting currency one US dollar buys",
+		}, []string{"currency"}),
 	}
 }
 
@@ -252,12 +285,28 @@ func (mc *MetricsCollector) RecordWorkloadOptimizerCost(namespace, name, workloa
 	mc.costPerHour.WithLabelValues(namespace, name, workloadType).Set(cost)
 }
 
+// ResetPodCosts removes the pod costs recorded for a workload, such as before recording the
+// costs of its current pods
+func (mc *MetricsCollector) ResetPodCosts(namespace, workload string) {
+	mc.podCostPerHour.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "workload": workload})
+}
+
+// RecordPodCost records the cost per hour of one of a workload's pods
+func (mc *MetricsCollector) RecordPodCost(namespace, workload, pod, node string, cost float64) {
+	mc.podCostPerHour.WithLabelValues(namespace, workload, pod, node).Set(cost)
+}
+
 // RecordWorkloadOptimizerPower records a WorkloadOptimizer power usage
 func (mc *MetricsCollector) RecordWorkloadOptimizerPower(namespace, name, workloadType string, power float64) {
 	mc.workloadOptimizerPower.WithLabelValues(namespace, name, workloadType).Observe(power)
 	mc.powerUsage.WithLabelValues(namespace, name, workloadType).Set(power)
 }
 
+// RecordWorkloadOptimizerGPUPower records a WorkloadOptimizer measured GPU power usage
+func (mc *MetricsCollector) RecordWorkloadOptimizerGPUPower(namespace, name, workloadType string, power float64) {
+	mc.gpuPowerUsage.WithLabelValues(namespace, name, workloadType).Set(power)
+}
+
 // RecordSchedulingOperation records a scheduling operation
 func (mc *MetricsCollector) RecordSchedulingOperation(algorithm, workloadType string, success bool, duration time.Duration, score float64) {
 	mc.schedulingTotal.Inc()
@@ -330,6 +379,25 @@ func (mc *MetricsCollector) RecordPowerEfficiency(namespace, name, workloadType
 	mc.powerEfficiency.WithLabelValues(namespace, name, workloadType).Set(efficiency)
 }
 
+// RecordWorkloadBaselineDeviation records how far a workload's cost or power is from its baseline
+func (mc *MetricsCollector) RecordWorkloadBaselineDeviation(namespace, name, resource string, deviation float64) {
+	mc.workloadBaselineDeviation.WithLabelValues(namespace, name, resource).Set(deviation)
+}
+
+// ForgetWorkloadBaseline removes the baseline deviations recorded for a deleted workload
+func (mc *MetricsCollector) ForgetWorkloadBaseline(namespace, name string) {
+	mc.workloadBaselineDeviation.DeleteLabelValues(namespace, name, "cost")
+	mc.workloadBaselineDeviation.DeleteLabelValues(namespace, name, "power")
+}
+
+// RecordCurrency records the reporting currency and its exchange rate to the US dollar
+func (mc *MetricsCollector) RecordCurrency(currency string, rate float64) {
+	mc.costCurrency.Reset()
+	mc.costCurrency.WithLabelValues(currency).Set(1)
+	mc.exchangeRate.Reset()
+	mc.exchangeRate.WithLabelValues(currency).Set(rate)
+}
+
 // StartMetricsCollection starts periodic metrics collection
 func (mc *MetricsCollector) StartMetricsCollection(ctx context.Context) {
 	log := log.FromContext(ctx)
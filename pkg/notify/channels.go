@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Built-in channel types
+const (
+	ChannelTypeWebhook = "webhook"
+	ChannelTypeLog     = "log"
+)
+
+func init() {
+	MustRegisterChannelType(ChannelTypeWebhook, newWebhookChannel)
+	MustRegisterChannelType(ChannelTypeLog, newLogChannel)
+}
+
+// defaultWebhookTimeout bounds a single webhook delivery
+const defaultWebhookTimeout = 10 * time.Second
+
+// WebhookChannel posts messages as JSON; the "text" field makes the payload accepted
+// by Slack and Mattermost incoming webhooks
+type WebhookChannel struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// webhookPayload is the JSON body posted by a webhook channel
+type webhookPayload struct {
+	Text string `json:"text"`
+	Message
+}
+
+// newWebhookChannel builds a webhook channel from its configuration
+func newWebhookChannel(cfg ChannelConfig) (Channel, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook channel %s requires a url", cfg.Name)
+	}
+	return &WebhookChannel{
+		name:   cfg.Name,
+		url:    cfg.URL,
+		client: &http.Client{Timeout: defaultWebhookTimeout},
+	}, nil
+}
+
+// Name returns the channel name
+func (c *WebhookChannel) Name() string { return c.name }
+
+// Send posts the message to the webhook URL
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(webhookPayload{Text: msg.Subject + "\n" + msg.Body, Message: msg})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification to %s: %w", c.name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification channel %s returned %s", c.name, resp.Status)
+	}
+	return nil
+}
+
+// LogChannel writes messages to the operator log, useful before a real channel is configured
+type LogChannel struct {
+	name string
+}
+
+// newLogChannel builds a log channel from its configuration
+func newLogChannel(cfg ChannelConfig) (Channel, error) {
+	return &LogChannel{name: cfg.Name}, nil
+}
+
+// Name returns the channel name
+func (c *LogChannel) Name() string { return c.name }
+
+// Send logs the message
+func (c *LogChannel) Send(ctx context.Context, msg Message) error {
+	log.FromContext(ctx).WithName("notify").Info(msg.Subject,
+		"channel", c.name,
+		"team", msg.Team,
+		"digest", msg.Digest,
+		"alerts", len(msg.Alerts),
+		"body", msg.Body)
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config lists the channels alerts are delivered to
+type Config struct {
+	Channels []ChannelConfig `json:"channels"`
+}
+
+// ChannelConfig configures one delivery channel and how alerts reach it
+type ChannelConfig struct {
+	// Name identifies the channel in logs
+	Name string `json:"name"`
+
+	// Type is a registered channel type such as webhook or log
+	Type string `json:"type"`
+
+	// URL is the destination of webhook channels
+	URL string `json:"url,omitempty"`
+
+	// Schedule is immediate, hourly or daily; critical alerts are always sent immediately
+	Schedule string `json:"schedule,omitempty"`
+
+	// Teams limits the channel to these teams; empty means every team
+	Teams []string `json:"teams,omitempty"`
+
+	// MinSeverity drops alerts below this severity
+	MinSeverity string `json:"minSeverity,omitempty"`
+
+	// Template overrides the digest body, rendered with text/template
+	Template string `json:"template,omitempty"`
+
+	// Locale is the language of subjects and the default digest body, such as en or ko;
+	// empty uses the operator's default locale
+	Locale string `json:"locale,omitempty"`
+}
+
+// LoadConfig reads a YAML or JSON notification config
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.UnmarshalStrict(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse notification config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks channel names, schedules, severities and templates
+func (c *Config) Validate() error {
+	names := make(map[string]bool, len(c.Channels))
+	for i, channel := range c.Channels {
+		if channel.Name == "" {
+			return fmt.Errorf("channels[%d].name is required", i)
+		}
+		if names[channel.Name] {
+			return fmt.Errorf("channel %s is defined more than once", channel.Name)
+		}
+		names[channel.Name] = true
+
+		switch channel.Schedule {
+		case "", ScheduleImmediate, ScheduleHourly, ScheduleDaily:
+		default:
+			return fmt.Errorf("channel %s has invalid schedule %q", channel.Name, channel.Schedule)
+		}
+
+		switch channel.MinSeverity {
+		case "", SeverityInfo, SeverityWarning, SeverityCritical:
+		default:
+			return fmt.Errorf("channel %s has invalid minSeverity %q", channel.Name, channel.MinSeverity)
+		}
+
+		if channel.Template != "" {
+			if _, err := template.New(channel.Name).Parse(channel.Template); err != nil {
+				return fmt.Errorf("channel %s has invalid template: %w", channel.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+)
+
+// Delivery schedules
+const (
+	ScheduleImmediate = "immediate"
+	ScheduleHourly    = "hourly"
+	ScheduleDaily     = "daily"
+)
+
+const (
+	// DefaultFlushInterval is how often pending digests are checked for delivery
+	DefaultFlushInterval = time.Minute
+
+	// maxDigestItems bounds the distinct alerts held for one digest
+	maxDigestItems = 500
+)
+
+// sectionOrder lists the categories that lead a digest, in order
+var sectionOrder = []string{CategoryCost, CategoryPower, CategoryQueue, CategoryCompliance}
+
+// DigestItem is an alert in a digest, collapsed with its repeats
+type DigestItem struct {
+	Alert
+	Count int
+}
+
+// DigestSection groups digest items of one category
+type DigestSection struct {
+	Category string
+	Items    []DigestItem
+}
+
+// DigestData is the input of digest templates
+type DigestData struct {
+	Team     string
+	Channel  string
+	Schedule string
+	Period   time.Time
+	Total    int
+	Dropped  int
+	Sections []DigestSection
+}
+
+// route is a configured channel with its delivery rules
+type route struct {
+	channel     Channel
+	schedule    string
+	teams       map[string]bool
+	minSeverity string
+	locale      string
+	// template overrides the catalog's digest body when set
+	template *template.Template
+}
+
+// accepts reports whether an alert is delivered through the route
+func (r *route) accepts(alert Alert) bool {
+	if len(r.teams) > 0 && !r.teams[alert.Team] {
+		return false
+	}
+	return severityRank(alert.Severity) >= severityRank(r.minSeverity)
+}
+
+// digestKey identifies the digest of one team on one route
+type digestKey struct {
+	route int
+	team  string
+}
+
+// pendingDigest accumulates alerts until its period ends
+type pendingDigest struct {
+	period  time.Time
+	items   []DigestItem
+	index   map[string]int
+	dropped int
+}
+
+// DigestScheduler routes alerts to channels, sending critical and immediate ones right
+// away and batching the rest into one hourly or daily digest per team and channel
+type DigestScheduler struct {
+	routes   []*route
+	clock    clock.PassiveClock
+	interval time.Duration
+	catalog  *messages.Catalog
+
+	mu      sync.Mutex
+	pending map[digestKey]*pendingDigest
+}
+
+// NewDigestScheduler builds the channels of a notification config
+func NewDigestScheduler(cfg *Config) (*DigestScheduler, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	d := &DigestScheduler{
+		clock:    clock.RealClock{},
+		interval: DefaultFlushInterval,
+		catalog:  messages.Default(),
+		pending:  make(map[digestKey]*pendingDigest),
+	}
+	for _, channelCfg := range cfg.Channels {
+		channel, err := newChannel(channelCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &route{
+			channel:     channel,
+			schedule:    channelCfg.Schedule,
+			teams:       make(map[string]bool, len(channelCfg.Teams)),
+			minSeverity: channelCfg.MinSeverity,
+			locale:      channelCfg.Locale,
+		}
+		if r.schedule == "" {
+			r.schedule = ScheduleImmediate
+		}
+		for _, team := range channelCfg.Teams {
+			r.teams[team] = true
+		}
+		if channelCfg.Template != "" {
+			r.template = template.Must(template.New(channelCfg.Name).Parse(channelCfg.Template))
+		}
+		d.routes = append(d.routes, r)
+	}
+	return d, nil
+}
+
+// SetClock replaces the clock used for alert times and digest periods
+func (d *DigestScheduler) SetClock(c clock.PassiveClock) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clock = c
+}
+
+// SetCatalog replaces the catalog that renders subjects and default digest bodies
+func (d *DigestScheduler) SetCatalog(c *messages.Catalog) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.catalog = c
+}
+
+// Notify delivers an alert: immediately on immediate routes or when critical, otherwise
+// into the pending digest of its team
+func (d *DigestScheduler) Notify(ctx context.Context, alert Alert) {
+	d.mu.Lock()
+	if alert.Time.IsZero() {
+		alert.Time = d.clock.Now()
+	}
+
+	var immediate []*route
+	for i, r := range d.routes {
+		if !r.accepts(alert) {
+			continue
+		}
+		if r.schedule == ScheduleImmediate || alert.Severity == SeverityCritical {
+			immediate = append(immediate, r)
+			continue
+		}
+		d.addLocked(digestKey{route: i, team: alert.Team}, periodStart(r.schedule, alert.Time), alert)
+	}
+	catalog := d.catalog
+	d.mu.Unlock()
+
+	for _, r := range immediate {
+		msg := Message{
+			Team:    alert.Team,
+			Subject: catalog.Render(r.locale, messages.AlertSubject, alert),
+			Body:    alert.Message,
+			Alerts:  []Alert{alert},
+		}
+		if err := r.channel.Send(ctx, msg); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to send alert", "channel", r.channel.Name(), "team", alert.Team)
+		}
+	}
+}
+
+// addLocked adds an alert to a pending digest, collapsing repeats of the same alert
+func (d *DigestScheduler) addLocked(key digestKey, period time.Time, alert Alert) {
+	digest, ok := d.pending[key]
+	if !ok {
+		digest = &pendingDigest{period: period, index: make(map[string]int)}
+		d.pending[key] = digest
+	}
+
+	id := digestItemID(alert)
+	if i, ok := digest.index[id]; ok {
+		count := digest.items[i].Count
+		digest.items[i] = DigestItem{Alert: alert, Count: count + 1}
+		return
+	}
+
+	if len(digest.items) >= maxDigestItems {
+		delete(digest.index, digestItemID(digest.items[0].Alert))
+		digest.items = digest.items[1:]
+		for existing, i := range digest.index {
+			digest.index[existing] = i - 1
+		}
+		digest.dropped++
+	}
+	digest.index[id] = len(digest.items)
+	digest.items = append(digest.items, DigestItem{Alert: alert, Count: 1})
+}
+
+// digestItemID identifies repeats of the same alert within a digest
+func digestItemID(alert Alert) string {
+	return alert.Category + "\x00" + alert.Source + "\x00" + alert.Title
+}
+
+// Flush sends every digest whose period has ended; with all set it sends every pending digest
+func (d *DigestScheduler) Flush(ctx context.Context, all bool) {
+	type delivery struct {
+		key    digestKey
+		digest *pendingDigest
+	}
+
+	d.mu.Lock()
+	now := d.clock.Now()
+	var due []delivery
+	for key, digest := range d.pending {
+		if all || periodStart(d.routes[key.route].schedule, now).After(digest.period) {
+			due = append(due, delivery{key: key, digest: digest})
+			delete(d.pending, key)
+		}
+	}
+	catalog := d.catalog
+	d.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].key.route != due[j].key.route {
+			return due[i].key.route < due[j].key.route
+		}
+		return due[i].key.team < due[j].key.team
+	})
+
+	for _, delivery := range due {
+		r := d.routes[delivery.key.route]
+		msg, err := renderDigest(catalog, r, delivery.key.team, delivery.digest)
+		if err == nil {
+			err = r.channel.Send(ctx, msg)
+		}
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to send digest, keeping it for the next flush",
+				"channel", r.channel.Name(), "team", delivery.key.team)
+			d.requeue(delivery.key, delivery.digest)
+		}
+	}
+}
+
+// requeue merges an undelivered digest back into the pending one
+func (d *DigestScheduler) requeue(key digestKey, digest *pendingDigest) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	current, ok := d.pending[key]
+	if !ok {
+		d.pending[key] = digest
+		return
+	}
+	for _, item := range digest.items {
+		d.addLocked(key, current.period, item.Alert)
+		current.items[current.index[digestItemID(item.Alert)]].Count += item.Count - 1
+	}
+	current.dropped += digest.dropped
+}
+
+// renderDigest builds the digest message of a team in the route's locale
+func renderDigest(catalog *messages.Catalog, r *route, team string, digest *pendingDigest) (Message, error) {
+	data := DigestData{
+		Team:     team,
+		Channel:  r.channel.Name(),
+		Schedule: r.schedule,
+		Period:   digest.period,
+		Dropped:  digest.dropped,
+	}
+
+	byCategory := make(map[string][]DigestItem)
+	alerts := make([]Alert, 0, len(digest.items))
+	for _, item := range digest.items {
+		byCategory[item.Category] = append(byCategory[item.Category], item)
+		alerts = append(alerts, item.Alert)
+		data.Total += item.Count
+	}
+
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		ri, rj := sectionRank(categories[i]), sectionRank(categories[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return categories[i] < categories[j]
+	})
+	for _, category := range categories {
+		data.Sections = append(data.Sections, DigestSection{Category: category, Items: byCategory[category]})
+	}
+
+	body := catalog.Render(r.locale, messages.DigestBody, data)
+	if r.template != nil {
+		var custom strings.Builder
+		if err := r.template.Execute(&custom, data); err != nil {
+			return Message{}, fmt.Errorf("failed to render digest: %w", err)
+		}
+		body = custom.String()
+	}
+
+	return Message{
+		Team:    team,
+		Subject: catalog.Render(r.locale, messages.DigestSubject, data),
+		Body:    body,
+		Digest:  true,
+		Alerts:  alerts,
+	}, nil
+}
+
+// sectionRank places well-known categories first
+func sectionRank(category string) int {
+	for i, known := range sectionOrder {
+		if category == known {
+			return i
+		}
+	}
+	return len(sectionOrder)
+}
+
+// periodStart returns the start of the digest period containing t
+func periodStart(schedule string, t time.Time) time.Time {
+	switch schedule {
+	case ScheduleDaily:
+		year, month, day := t.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	default:
+		return t.Truncate(time.Hour)
+	}
+}
+
+// Start flushes due digests on each interval and sends what is left when stopped
+func (d *DigestScheduler) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithName("notify"))
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultWebhookTimeout)
+			d.Flush(shutdownCtx, true)
+			cancel()
+			return nil
+		case <-ticker.C:
+			d.Flush(ctx, false)
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader send digests
+func (d *DigestScheduler) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notify delivers workload alerts to team channels, either immediately or batched
+// into periodic digests
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Alert severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert categories grouped into digest sections
+const (
+	CategoryCost       = "cost"
+	CategoryPower      = "power"
+	CategoryQueue      = "queue"
+	CategoryCompliance = "compliance"
+)
+
+// Alert is a single notable event for a team
+type Alert struct {
+	Team     string            `json:"team"`
+	Category string            `json:"category"`
+	Severity string            `json:"severity"`
+	Title    string            `json:"title"`
+	Message  string            `json:"message"`
+	Source   string            `json:"source,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Time     time.Time         `json:"time"`
+}
+
+// Message is what a channel delivers: one alert, or a digest of several
+type Message struct {
+	Team    string  `json:"team"`
+	Subject string  `json:"subject"`
+	Body    string  `json:"body"`
+	Digest  bool    `json:"digest"`
+	Alerts  []Alert `json:"alerts"`
+}
+
+// Channel delivers messages to a destination such as a chat webhook
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// ChannelFactory builds a channel from its configuration
+type ChannelFactory func(cfg ChannelConfig) (Channel, error)
+
+var (
+	channelTypes = map[string]ChannelFactory{}
+	channelMutex sync.RWMutex
+)
+
+// RegisterChannelType makes a channel type available to notification configs.
+// Downstream builds call it from an init function to add delivery backends.
+func RegisterChannelType(kind string, factory ChannelFactory) error {
+	channelMutex.Lock()
+	defer channelMutex.Unlock()
+
+	if _, exists := channelTypes[kind]; exists {
+		return fmt.Errorf("notification channel type %s is already registered", kind)
+	}
+	channelTypes[kind] = factory
+	return nil
+}
+
+// MustRegisterChannelType registers a channel type and panics on duplicate names
+func MustRegisterChannelType(kind string, factory ChannelFactory) {
+	if err := RegisterChannelType(kind, factory); err != nil {
+		panic(err)
+	}
+}
+
+// RegisteredChannelTypes returns the names of all registered channel types in sorted order
+func RegisteredChannelTypes() []string {
+	channelMutex.RLock()
+	defer channelMutex.RUnlock()
+
+	kinds := make([]string, 0, len(channelTypes))
+	for kind := range channelTypes {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// newChannel builds a channel of a registered type
+func newChannel(cfg ChannelConfig) (Channel, error) {
+	channelMutex.RLock()
+	factory, ok := channelTypes[cfg.Type]
+	channelMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown notification channel type %q", cfg.Type)
+	}
+	return factory(cfg)
+}
+
+// severityRank orders severities so they can be compared
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
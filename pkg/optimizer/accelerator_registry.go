@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// AcceleratorType maps a logical accelerator type to the extended resource its device
+// plugin advertises, with the cost and power of one device
+type AcceleratorType struct {
+	// Name is the logical type workloads request, such as tpu or fpga
+	Name string `json:"name"`
+
+	// ResourceName is the extended resource of one device, such as google.com/tpu
+	ResourceName string `json:"resourceName"`
+
+	// CostPerHour is the cost of one device per hour in USD
+	CostPerHour float64 `json:"costPerHour"`
+
+	// PowerWatts is the power draw of one busy device in Watts
+	PowerWatts float64 `json:"powerWatts"`
+}
+
+// AcceleratorRegistryConfig is the file format of the accelerator registry
+type AcceleratorRegistryConfig struct {
+	Accelerators []AcceleratorType `json:"accelerators"`
+}
+
+// AcceleratorRegistry resolves the accelerator types workloads request. A nil registry
+// knows no types.
+type AcceleratorRegistry struct {
+	byName     map[string]AcceleratorType
+	byResource map[corev1.ResourceName]AcceleratorType
+}
+
+// reservedAcceleratorResources are handled by the gpu and npu fields and cannot be registered
+var reservedAcceleratorResources = map[string]bool{
+	"nvidia.com/gpu": true,
+	"npu.com/npu":    true,
+}
+
+// NewAcceleratorRegistry creates a registry of the given types
+func NewAcceleratorRegistry(types ...AcceleratorType) (*AcceleratorRegistry, error) {
+	r := &AcceleratorRegistry{
+		byName:     make(map[string]AcceleratorType, len(types)),
+		byResource: make(map[corev1.ResourceName]AcceleratorType, len(types)),
+	}
+	for i, t := range types {
+		if errs := validation.IsDNS1123Label(t.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("accelerator %d: invalid name %q: %v", i, t.Name, errs)
+		}
+		if errs := validation.IsQualifiedName(t.ResourceName); len(errs) > 0 || !strings.Contains(t.ResourceName, "/") {
+			return nil, fmt.Errorf("accelerator %s: resourceName %q must be a domain-prefixed extended resource",
+				t.Name, t.ResourceName)
+		}
+		if reservedAcceleratorResources[t.ResourceName] {
+			return nil, fmt.Errorf("accelerator %s: %s is requested through the gpu and npu fields", t.Name, t.ResourceName)
+		}
+		if t.CostPerHour < 0 || t.PowerWatts < 0 {
+			return nil, fmt.Errorf("accelerator %s: costPerHour and powerWatts must not be negative", t.Name)
+		}
+		if _, dup := r.byName[t.Name]; dup {
+			return nil, fmt.Errorf("accelerator %s is registered twice", t.Name)
+		}
+		if other, dup := r.byResource[corev1.ResourceName(t.ResourceName)]; dup {
+			return nil, fmt.Errorf("accelerators %s and %s share resource %s", other.Name, t.Name, t.ResourceName)
+		}
+		r.byName[t.Name] = t
+		r.byResource[corev1.ResourceName(t.ResourceName)] = t
+	}
+	return r, nil
+}
+
+// LoadAcceleratorRegistry reads a YAML or JSON accelerator registry
+func LoadAcceleratorRegistry(path string) (*AcceleratorRegistry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accelerator registry: %w", err)
+	}
+
+	var cfg AcceleratorRegistryConfig
+	if err := yaml.UnmarshalStrict(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse accelerator registry: %w", err)
+	}
+	return NewAcceleratorRegistry(cfg.Accelerators...)
+}
+
+// Lookup returns the registered accelerator type of the given name
+func (r *AcceleratorRegistry) Lookup(name string) (AcceleratorType, bool) {
+	if r == nil {
+		return AcceleratorType{}, false
+	}
+	t, ok := r.byName[name]
+	return t, ok
+}
+
+// ForResource returns the accelerator type advertised as the given extended resource
+func (r *AcceleratorRegistry) ForResource(name corev1.ResourceName) (AcceleratorType, bool) {
+	if r == nil {
+		return AcceleratorType{}, false
+	}
+	t, ok := r.byResource[name]
+	return t, ok
+}
+
+// Types returns the registered accelerator types ordered by name
+func (r *AcceleratorRegistry) Types() []AcceleratorType {
+	if r == nil {
+		return nil
+	}
+	types := make([]AcceleratorType, 0, len(r.byName))
+	for _, t := range r.byName {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// ResourceNames returns the extended resources of the registered types ordered by name
+func (r *AcceleratorRegistry) ResourceNames() []string {
+	var names []string
+	for _, t := range r.Types() {
+		names = append(names, t.ResourceName)
+	}
+	return names
+}
+
+// Resources returns the extended resources the requests need. Repeated types add up, as
+// in Cost and Power. Unregistered types are returned as an error since no node can
+// satisfy them.
+func (r *AcceleratorRegistry) Resources(requests []kcloudv1alpha1.AcceleratorRequest) (corev1.ResourceList, error) {
+	resources := corev1.ResourceList{}
+	for _, request := range requests {
+		t, ok := r.Lookup(request.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown accelerator type %q", request.Type)
+		}
+		name := corev1.ResourceName(t.ResourceName)
+		quantity := resources[name]
+		quantity.Add(*resource.NewQuantity(int64(request.Count), resource.DecimalSI))
+		resources[name] = quantity
+	}
+	return resources, nil
+}
+
+// Cost returns the hourly cost of the requested accelerators; unregistered types cost nothing
+func (r *AcceleratorRegistry) Cost(requests []kcloudv1alpha1.AcceleratorRequest) float64 {
+	cost := 0.0
+	for _, request := range requests {
+		if t, ok := r.Lookup(request.Type); ok {
+			cost += float64(request.Count) * t.CostPerHour
+		}
+	}
+	return cost
+}
+
+// Power returns the power draw of the requested accelerators in Watts; unregistered types
+// draw nothing
+func (r *AcceleratorRegistry) Power(requests []kcloudv1alpha1.AcceleratorRequest) float64 {
+	power := 0.0
+	for _, request := range requests {
+		if t, ok := r.Lookup(request.Type); ok {
+			power += float64(request.Count) * t.PowerWatts
+		}
+	}
+	return power
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAWSPriceListURL is the AWS Price List bulk offer file of EC2 in a region; %s is
+	// replaced with the region code
+	DefaultAWSPriceListURL = "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEC2/current/%s/index.json"
+
+	// defaultPriceListTimeout bounds downloading a region's price list, which runs to
+	// hundreds of megabytes and is decoded as it streams in
+	defaultPriceListTimeout = 5 * time.Minute
+)
+
+// AWSPriceList reads EC2 on-demand prices from the AWS Price List bulk API, which needs no
+// credentials. Only shared-tenancy Linux instances without pre-installed software are priced,
+// which is what Kubernetes nodes run.
+type AWSPriceList struct {
+	url    string
+	client *http.Client
+}
+
+// NewAWSPriceList returns a price list source reading the offer files at urlTemplate, whose
+// %s is replaced with the region; empty uses DefaultAWSPriceListURL
+func NewAWSPriceList(urlTemplate string) (*AWSPriceList, error) {
+	if urlTemplate == "" {
+		urlTemplate = DefaultAWSPriceListURL
+	}
+	if strings.Count(urlTemplate, "%s") != 1 {
+		return nil, fmt.Errorf("invalid aws price list url %q: must contain one %%s for the region", urlTemplate)
+	}
+	return &AWSPriceList{
+		url:    urlTemplate,
+		client: &http.Client{Timeout: defaultPriceListTimeout},
+	}, nil
+}
+
+// awsProduct is the subset of an EC2 offer file's product the price list reads
+type awsProduct struct {
+	ProductFamily string `json:"productFamily"`
+	Attributes    struct {
+		InstanceType    string `json:"instanceType"`
+		OperatingSystem string `json:"operatingSystem"`
+		Tenancy         string `json:"tenancy"`
+		PreInstalledSW  string `json:"preInstalledSw"`
+		CapacityStatus  string `json:"capacitystatus"`
+	} `json:"attributes"`
+}
+
+// priced reports whether the product is a node the price list prices
+func (p *awsProduct) priced() bool {
+	attributes := p.Attributes
+	return p.ProductFamily == "Compute Instance" && attributes.InstanceType != "" &&
+		attributes.OperatingSystem == "Linux" && attributes.Tenancy == "Shared" &&
+		attributes.PreInstalledSW == "NA" && attributes.CapacityStatus == "Used"
+}
+
+// awsTerm is the subset of an EC2 offer file's on-demand term the price list reads
+type awsTerm struct {
+	PriceDimensions map[string]struct {
+		Unit         string            `json:"unit"`
+		PricePerUnit map[string]string `json:"pricePerUnit"`
+	} `json:"priceDimensions"`
+}
+
+// RegionPricing prices nodes by the hourly on-demand price of their EC2 instance type in
+// the region. The offer file runs to hundreds of megabytes, so it is streamed: only the
+// products and on-demand terms are decoded, one SKU at a time, and the reserved terms are
+// skipped.
+func (a *AWSPriceList) RegionPricing(ctx context.Context, region string) (NodePricing, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(a.url, region), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build aws price list request: %w", err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch aws price list: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aws price list returned status %d", resp.StatusCode)
+	}
+
+	// The instance type of each priced SKU and the lowest hourly USD price of each SKU
+	instanceTypes := make(map[string]string)
+	hourly := make(map[string]float64)
+	decoder := json.NewDecoder(resp.Body)
+	err = forEachJSONMember(decoder, func(key string) error {
+		switch key {
+		case "products":
+			return forEachJSONMember(decoder, func(sku string) error {
+				var product awsProduct
+				if err := decoder.Decode(&product); err != nil {
+					return err
+				}
+				if product.priced() {
+					instanceTypes[sku] = product.Attributes.InstanceType
+				}
+				return nil
+			})
+		case "terms":
+			return forEachJSONMember(decoder, func(kind string) error {
+				if kind != "OnDemand" {
+					return skipJSONValue(decoder)
+				}
+				return forEachJSONMember(decoder, func(sku string) error {
+					var terms map[string]awsTerm
+					if err := decoder.Decode(&terms); err != nil {
+						return err
+					}
+					for _, term := range terms {
+						for _, dimension := range term.PriceDimensions {
+							if dimension.Unit != "Hrs" {
+								continue
+							}
+							price, err := strconv.ParseFloat(dimension.PricePerUnit["USD"], 64)
+							if err != nil || price <= 0 {
+								continue
+							}
+							if current, ok := hourly[sku]; !ok || price < current {
+								hourly[sku] = price
+							}
+						}
+					}
+					return nil
+				})
+			})
+		}
+		return skipJSONValue(decoder)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode aws price list: %w", err)
+	}
+
+	prices := make(InstancePrices)
+	for sku, instanceType := range instanceTypes {
+		price, ok := hourly[sku]
+		if !ok {
+			continue
+		}
+		if current, ok := prices[instanceType]; !ok || price < current {
+			prices[instanceType] = price
+		}
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("aws price list for region %s has no on-demand instance prices", region)
+	}
+	return prices, nil
+}
+
+// forEachJSONMember reads the object the decoder is at and calls member with each key; member
+// must consume the key's value
+func forEachJSONMember(decoder *json.Decoder, member func(key string) error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected an object, found %v", token)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if err := member(token.(string)); err != nil {
+			return err
+		}
+	}
+	// The closing brace
+	_, err = decoder.Token()
+	return err
+}
+
+// skipJSONValue consumes the value the decoder is at without keeping it
+func skipJSONValue(decoder *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultAzurePriceListURL is the Azure Retail Prices API, which needs no credentials
+const DefaultAzurePriceListURL = "https://prices.azure.com/api/retail/prices"
+
+// azureMaxPages bounds the pages read for one region in case the API keeps linking onwards
+const azureMaxPages = 1000
+
+// AzurePriceList reads Virtual Machines pay-as-you-go prices from the Azure Retail Prices API.
+// Linux prices are used, without spot, low priority or Windows meters. GPU VM sizes such as
+// Standard_NC24ads_A100_v4 are priced with their GPUs, as Azure bills them.
+type AzurePriceList struct {
+	url    string
+	client *http.Client
+}
+
+// NewAzurePriceList returns a price list source reading the Retail Prices API at apiURL,
+// empty for DefaultAzurePriceListURL
+func NewAzurePriceList(apiURL string) (*AzurePriceList, error) {
+	if apiURL == "" {
+		apiURL = DefaultAzurePriceListURL
+	}
+	parsed, err := url.Parse(apiURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid azure price list url %q", apiURL)
+	}
+	return &AzurePriceList{
+		url:    apiURL,
+		client: &http.Client{Timeout: defaultPriceListTimeout},
+	}, nil
+}
+
+// azurePricePage is the subset of a Retail Prices API page the price list reads
+type azurePricePage struct {
+	Items []struct {
+		CurrencyCode  string  `json:"currencyCode"`
+		RetailPrice   float64 `json:"retailPrice"`
+		ArmSkuName    string  `json:"armSkuName"`
+		SkuName       string  `json:"skuName"`
+		ProductName   string  `json:"productName"`
+		UnitOfMeasure string  `json:"unitOfMeasure"`
+		Type          string  `json:"type"`
+	} `json:"Items"`
+	NextPageLink string `json:"NextPageLink"`
+}
+
+// RegionPricing prices nodes by the hourly pay-as-you-go price of their VM size in the region
+func (a *AzurePriceList) RegionPricing(ctx context.Context, region string) (NodePricing, error) {
+	filter := fmt.Sprintf("serviceName eq 'Virtual Machines' and armRegionName eq '%s' and priceType eq 'Consumption'", region)
+	next := a.url + "?" + url.Values{"currencyCode": {"USD"}, "$filter": {filter}}.Encode()
+
+	prices := make(azureVMPrices)
+	for pages := 0; next != "" && pages < azureMaxPages; pages++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build azure price list request: %w", err)
+		}
+		page, err := a.fetchPage(req)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			if item.CurrencyCode != "USD" || item.Type != "Consumption" || item.UnitOfMeasure != "1 Hour" ||
+				item.RetailPrice <= 0 || item.ArmSkuName == "" || strings.Contains(item.ProductName, "Windows") ||
+				strings.Contains(item.SkuName, "Spot") || strings.Contains(item.SkuName, "Low Priority") {
+				continue
+			}
+			size := azureVMSize(item.ArmSkuName)
+			if current, ok := prices[size]; !ok || item.RetailPrice < current {
+				prices[size] = item.RetailPrice
+			}
+		}
+		next = page.NextPageLink
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("azure price list for region %s has no virtual machine prices", region)
+	}
+	return prices, nil
+}
+
+// fetchPage fetches and decodes one page of prices
+func (a *AzurePriceList) fetchPage(req *http.Request) (*azurePricePage, error) {
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch azure price list: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("azure price list returned status %d", resp.StatusCode)
+	}
+	var page azurePricePage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode azure price list: %w", err)
+	}
+	return &page, nil
+}
+
+// azureVMPrices prices nodes by the hourly price of their VM size, keyed by azureVMSize
+type azureVMPrices map[string]float64
+
+// NodePrice returns the price of the node's VM size
+func (p azureVMPrices) NodePrice(node *corev1.Node) (float64, bool) {
+	price, ok := p[azureVMSize(NodeInstanceType(node))]
+	return price, ok
+}
+
+// azureVMSize normalizes a VM size as labeled on nodes or named in the price list, such as
+// Standard_NC6s_v3, standard_nc6s_v3 or NC6s_v3, to one key
+func azureVMSize(size string) string {
+	size = strings.ToLower(size)
+	if !strings.HasPrefix(size, "standard_") && !strings.HasPrefix(size, "basic_") {
+		size = "standard_" + size
+	}
+	return size
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// BMCCABundleKey is the key of the BMC secret holding CA certificates trusted for every BMC
+	BMCCABundleKey = "ca.crt"
+
+	// DefaultBMCInterval is how often node BMCs are polled
+	DefaultBMCInterval = time.Minute
+
+	// bmcTimeout bounds reading one BMC, and bmcConcurrency how many are read at once
+	bmcTimeout     = 15 * time.Second
+	bmcConcurrency = 8
+)
+
+// BMCEndpoint is the Redfish service of one node's BMC, stored in the BMC secret as JSON under
+// the node's name
+type BMCEndpoint struct {
+	// URL is the https address of the Redfish service, such as https://10.0.0.5
+	URL string `json:"url"`
+	// Username and Password log into this BMC
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// CABundle holds PEM certificates trusted for this BMC in addition to the shared bundle
+	CABundle string `json:"caBundle,omitempty"`
+}
+
+// bmcSnapshot is the power each BMC last reported, and when it was read
+type bmcSnapshot struct {
+	watts  float64
+	readAt time.Time
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// BMCPower polls the power draw of bare-metal nodes from the Redfish services of their
+// baseboard management controllers. Which nodes are polled, where their BMCs are and how to
+// log into them come from one secret, so neither node annotations nor anyone able to write
+// them can redirect the operator's credentials. Redfish is read only over https with
+// verified certificates.
+type BMCPower struct {
+	reader   client.Reader
+	secret   types.NamespacedName
+	interval time.Duration
+	clock    clock.PassiveClock
+
+	mu      sync.RWMutex
+	nodes   map[string]bmcSnapshot
+	clients map[string]*http.Client
+}
+
+// NewBMCPower returns node power polled every interval from the BMCs listed in the secret
+func NewBMCPower(c client.Reader, secret types.NamespacedName, interval time.Duration) *BMCPower {
+	if interval <= 0 {
+		interval = DefaultBMCInterval
+	}
+	return &BMCPower{
+		reader:   c,
+		secret:   secret,
+		interval: interval,
+		clock:    clock.RealClock{},
+		nodes:    make(map[string]bmcSnapshot),
+		clients:  make(map[string]*http.Client),
+	}
+}
+
+// SetClock replaces the clock reading ages are measured with
+func (b *BMCPower) SetClock(c clock.PassiveClock) {
+	b.clock = c
+}
+
+// NodePower returns the power the node's BMC last reported, unless it has not been read for
+// three polls
+func (b *BMCPower) NodePower(node string) (float64, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	snapshot, ok := b.nodes[node]
+	if !ok || b.clock.Since(snapshot.readAt) > 3*b.interval {
+		return 0, false
+	}
+	return snapshot.watts, true
+}
+
+// Refresh reads the BMC of every node in the secret; a node whose BMC fails keeps its last
+// reading until it expires
+func (b *BMCPower) Refresh(ctx context.Context) error {
+	endpoints, sharedCA, err := b.endpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	var errs []error
+	fail := func(err error) {
+		errMu.Lock()
+		errs = append(errs, err)
+		errMu.Unlock()
+	}
+	slots := make(chan struct{}, bmcConcurrency)
+	for node, endpoint := range endpoints {
+		httpClient, err := b.clientFor(sharedCA, endpoint.CABundle)
+		if err != nil {
+			fail(fmt.Errorf("node %s: %w", node, err))
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() { <-slots; wg.Done() }()
+			readCtx, cancel := context.WithTimeout(ctx, bmcTimeout)
+			defer cancel()
+			watts, err := readRedfish(readCtx, httpClient, endpoint)
+			if err != nil {
+				fail(fmt.Errorf("node %s: %w", node, err))
+				return
+			}
+			b.mu.Lock()
+			b.nodes[node] = bmcSnapshot{watts: watts, readAt: b.clock.Now()}
+			b.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// endpoints returns the BMC of each node in the secret and the CA bundle shared by all of
+// them. Entries that are not valid https endpoints are reported and skipped.
+func (b *BMCPower) endpoints(ctx context.Context) (map[string]BMCEndpoint, string, error) {
+	var secret corev1.Secret
+	if err := b.reader.Get(ctx, b.secret, &secret); err != nil {
+		return nil, "", fmt.Errorf("failed to get bmc secret %s: %w", b.secret, err)
+	}
+
+	log := log.FromContext(ctx).WithName("bmc-power")
+	endpoints := make(map[string]BMCEndpoint, len(secret.Data))
+	for node, data := range secret.Data {
+		if node == BMCCABundleKey {
+			continue
+		}
+		var endpoint BMCEndpoint
+		if err := json.Unmarshal(data, &endpoint); err != nil {
+			log.Error(err, "Ignoring invalid bmc entry", "node", node)
+			continue
+		}
+		parsed, err := url.Parse(endpoint.URL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			log.Error(fmt.Errorf("bmc url %q is not an https url", endpoint.URL), "Ignoring invalid bmc entry", "node", node)
+			continue
+		}
+		endpoint.URL = strings.TrimSuffix(endpoint.URL, "/")
+		endpoints[node] = endpoint
+	}
+	return endpoints, string(secret.Data[BMCCABundleKey]), nil
+}
+
+// clientFor returns an http client verifying BMC certificates against the system roots and
+// the shared and per-BMC bundles, reusing one client per distinct set of bundles
+func (b *BMCPower) clientFor(sharedCA, caBundle string) (*http.Client, error) {
+	key := sharedCA + "\x00" + caBundle
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.clients[key]; ok {
+		return c, nil
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	for _, bundle := range []string{sharedCA, caBundle} {
+		if bundle != "" && !roots.AppendCertsFromPEM([]byte(bundle)) {
+			return nil, errors.New("bmc CA bundle holds no certificates")
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	c := &http.Client{
+		Timeout:   bmcTimeout,
+		Transport: transport,
+		// Credentials are sent only to the configured BMC, never to where it redirects
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	b.clients[key] = c
+	return c, nil
+}
+
+// redfishCollection is the subset of a Redfish resource collection the poller reads
+type redfishCollection struct {
+	Members []struct {
+		ID string `json:"@odata.id"`
+	} `json:"Members"`
+}
+
+// redfishPower is the subset of a chassis Power resource the poller reads
+type redfishPower struct {
+	PowerControl []struct {
+		PowerConsumedWatts *float64 `json:"PowerConsumedWatts"`
+	} `json:"PowerControl"`
+}
+
+// readRedfish returns the power consumed by the first chassis of the BMC's Redfish service
+// that reports one
+func readRedfish(ctx context.Context, c *http.Client, endpoint BMCEndpoint) (float64, error) {
+	var chassis redfishCollection
+	if err := getRedfish(ctx, c, endpoint, endpoint.URL+"/redfish/v1/Chassis", &chassis); err != nil {
+		return 0, err
+	}
+	for _, member := range chassis.Members {
+		// Member links are paths on the same service; anything else is not followed
+		if !strings.HasPrefix(member.ID, "/") {
+			continue
+		}
+		var power redfishPower
+		if err := getRedfish(ctx, c, endpoint, endpoint.URL+strings.TrimSuffix(member.ID, "/")+"/Power", &power); err != nil {
+			continue
+		}
+		for _, control := range power.PowerControl {
+			if control.PowerConsumedWatts != nil {
+				return *control.PowerConsumedWatts, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no chassis of %s reports consumed power", endpoint.URL)
+}
+
+// getRedfish decodes the Redfish resource at target, logging in as the endpoint's user
+func getRedfish(ctx context.Context, c *http.Client, endpoint BMCEndpoint, target string, into any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build redfish request: %w", err)
+	}
+	req.SetBasicAuth(endpoint.Username, endpoint.Password)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query redfish: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("redfish %s returned status %d", target, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+		return fmt.Errorf("failed to decode redfish %s: %w", target, err)
+	}
+	return nil
+}
+
+// Start polls BMCs every interval until the context is cancelled
+func (b *BMCPower) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("bmc-power")
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		if err := b.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read node power from BMCs")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection polls BMCs only on the leader, as BMCs serve few sessions at once; other
+// replicas score power from the remaining sources
+func (b *BMCPower) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// CarbonGramsPerHour converts a carbon footprint in kg CO2 per hour to g CO2e per hour, the
+// unit of carbon caps and budgets
+func CarbonGramsPerHour(footprint float64) float64 {
+	return footprint * 1000
+}
+
+// EmissionsMonth returns the start and end of the calendar month containing t
+func EmissionsMonth(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+// AccrueEmissions adds the emissions since the account was last accrued at the rate in
+// effect since then, in g CO2e per hour, and projects the month at the current rate. A new
+// month starts a new account, with what was emitted since the month started. Without an
+// account, accrual starts now.
+func AccrueEmissions(account *kcloudv1alpha1.EmissionsAccount, previousRate, currentRate float64,
+	budget *float64, now time.Time) *kcloudv1alpha1.EmissionsAccount {
+	start, end := EmissionsMonth(now)
+	accrued := &kcloudv1alpha1.EmissionsAccount{
+		PeriodStart: metav1.NewTime(start),
+		LastAccrued: metav1.NewTime(now),
+	}
+	if account != nil {
+		since := account.LastAccrued.Time
+		if account.PeriodStart.Time.Equal(start) {
+			accrued.Emitted = account.Emitted
+		} else if since.Before(start) {
+			since = start
+		}
+		if elapsed := now.Sub(since); elapsed > 0 {
+			accrued.Emitted += previousRate * elapsed.Hours()
+		}
+	}
+	accrued.Projected = math.Round(accrued.Emitted + currentRate*end.Sub(now).Hours())
+	if budget != nil && *budget > 0 {
+		utilization := math.Min(accrued.Emitted / *budget * 100, 100)
+		accrued.BudgetUtilization = &utilization
+	}
+	return accrued
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// commitmentRefresh is how often nodes are assigned to commitments
+const commitmentRefresh = time.Minute
+
+var (
+	commitmentCoveredNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_commitment_covered_nodes",
+		Help: "Nodes priced at the rate of a reserved instance, savings plan or committed use discount",
+	}, []string{"commitment"})
+	commitmentUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_commitment_utilization_ratio",
+		Help: "Share of a commitment's nodes or hourly spend that covers nodes",
+	}, []string{"commitment"})
+	commitmentSavings = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_commitment_hourly_savings_usd",
+		Help: "On-demand price of the nodes a commitment covers less their effective price, per hour in USD",
+	}, []string{"commitment"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(commitmentCoveredNodes, commitmentUtilization, commitmentSavings)
+}
+
+// CommitmentSource lists the commitments nodes are covered by
+type CommitmentSource interface {
+	Commitments() []kcloudv1alpha1.Commitment
+}
+
+// commitmentUse is what a commitment covers
+type commitmentUse struct {
+	nodes   int32
+	spend   float64
+	savings float64
+}
+
+// CommitmentPricing prices the nodes covered by reserved instances, savings plans and
+// committed use discounts at their effective rate, and other nodes at their on-demand price.
+// Nodes are assigned to commitments in the background, oldest first, each to the first
+// commitment selecting it with nodes or hourly spend left.
+type CommitmentPricing struct {
+	client      client.Reader
+	commitments CommitmentSource
+	onDemand    NodePricing
+
+	// coverage is the effective price of each covered node by name
+	coverage atomic.Pointer[map[string]float64]
+}
+
+// NewCommitmentPricing returns pricing covering nodes by the source's commitments, with their
+// on-demand price from onDemand
+func NewCommitmentPricing(c client.Reader, commitments CommitmentSource, onDemand NodePricing) *CommitmentPricing {
+	return &CommitmentPricing{client: c, commitments: commitments, onDemand: onDemand}
+}
+
+// NodePrice returns the effective price of a covered node, otherwise its on-demand price
+func (p *CommitmentPricing) NodePrice(node *corev1.Node) (float64, bool) {
+	if coverage := p.coverage.Load(); coverage != nil {
+		if price, ok := (*coverage)[node.Name]; ok {
+			return price, true
+		}
+	}
+	return p.onDemand.NodePrice(node)
+}
+
+// cover assigns the nodes to the commitments and returns the effective price of each
+// covered node. A node without an on-demand price is only covered at an effective rate.
+func (p *CommitmentPricing) cover(nodes []corev1.Node, commitments []kcloudv1alpha1.Commitment) (map[string]float64, map[string]commitmentUse) {
+	ordered := make([]*corev1.Node, len(nodes))
+	for i := range nodes {
+		ordered[i] = &nodes[i]
+	}
+	slices.SortFunc(ordered, func(a, b *corev1.Node) int {
+		return cmp.Or(a.CreationTimestamp.Compare(b.CreationTimestamp.Time), cmp.Compare(a.Name, b.Name))
+	})
+
+	coverage := make(map[string]float64)
+	uses := make(map[string]commitmentUse, len(commitments))
+	for _, node := range ordered {
+		onDemand, priced := p.onDemand.NodePrice(node)
+		for _, commitment := range commitments {
+			if commitment.EffectiveCostPerHour == nil && (commitment.DiscountPercent == nil || !priced) {
+				continue
+			}
+			if !labels.SelectorFromSet(commitment.NodeSelector).Matches(labels.Set(node.Labels)) {
+				continue
+			}
+			use := uses[commitment.Name]
+			if commitment.Nodes != nil && use.nodes >= *commitment.Nodes {
+				continue
+			}
+			remaining := math.Inf(1)
+			if commitment.HourlyCommitment != nil {
+				if remaining = *commitment.HourlyCommitment - use.spend; remaining <= 0 {
+					continue
+				}
+			}
+
+			effective := onDemand * (1 - ptrFloat(commitment.DiscountPercent)/100)
+			if commitment.EffectiveCostPerHour != nil {
+				effective = *commitment.EffectiveCostPerHour
+			}
+			price := effective
+			if effective > remaining && priced {
+				// The spend left covers part of the node; the rest is charged on-demand
+				covered := remaining / effective
+				price = remaining + (1-covered)*onDemand
+				effective = remaining
+			}
+			use.nodes++
+			use.spend += effective
+			if priced {
+				use.savings += onDemand - price
+			}
+			uses[commitment.Name] = use
+			coverage[node.Name] = price
+			break
+		}
+	}
+	return coverage, uses
+}
+
+// ptrFloat returns the value, or zero for nil
+func ptrFloat(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// Refresh assigns the cluster's nodes to the commitments in effect
+func (p *CommitmentPricing) Refresh(ctx context.Context) error {
+	commitments := p.commitments.Commitments()
+	if len(commitments) == 0 {
+		p.coverage.Store(nil)
+		commitmentCoveredNodes.Reset()
+		commitmentUtilization.Reset()
+		commitmentSavings.Reset()
+		return nil
+	}
+	var nodes corev1.NodeList
+	if err := p.client.List(ctx, &nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	coverage, uses := p.cover(nodes.Items, commitments)
+	p.coverage.Store(&coverage)
+
+	commitmentCoveredNodes.Reset()
+	commitmentUtilization.Reset()
+	commitmentSavings.Reset()
+	for _, commitment := range commitments {
+		use := uses[commitment.Name]
+		commitmentCoveredNodes.WithLabelValues(commitment.Name).Set(float64(use.nodes))
+		commitmentSavings.WithLabelValues(commitment.Name).Set(use.savings)
+		switch {
+		case commitment.HourlyCommitment != nil && *commitment.HourlyCommitment > 0:
+			commitmentUtilization.WithLabelValues(commitment.Name).Set(use.spend / *commitment.HourlyCommitment)
+		case commitment.Nodes != nil && *commitment.Nodes > 0:
+			commitmentUtilization.WithLabelValues(commitment.Name).Set(float64(use.nodes) / float64(*commitment.Nodes))
+		}
+	}
+	return nil
+}
+
+// Start assigns nodes to commitments every minute until the context is cancelled
+func (p *CommitmentPricing) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("commitments")
+
+	ticker := time.NewTicker(commitmentRefresh)
+	defer ticker.Stop()
+	for {
+		if err := p.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to assign nodes to commitments")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection covers nodes on every replica, as every replica estimates costs
+func (p *CommitmentPricing) NeedLeaderElection() bool {
+	return false
+}
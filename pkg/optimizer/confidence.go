@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"math"
+)
+
+// Confidence combines how much data a recommendation rests on with how steady that data is
+// into a value from 0 to 1. Volume is samples out of expected, capped at 1; variation is the
+// coefficient of variation of the data, and halves the confidence when the spread equals the
+// mean.
+func Confidence(samples, expected, variation float64) float64 {
+	if samples <= 0 || expected <= 0 {
+		return 0
+	}
+	volume := math.Min(samples/expected, 1)
+	stability := 1 / (1 + math.Max(variation, 0))
+	return math.Round(volume*stability*100) / 100
+}
+
+// variation returns the coefficient of variation of the values, zero for fewer than two
+// values or a zero mean
+func variation(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(squares/float64(len(values))) / math.Abs(mean)
+}
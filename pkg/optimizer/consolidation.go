@@ -0,0 +1,285 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+)
+
+// DrainedNode is a node the consolidation plan empties, with what removing it saves
+type DrainedNode struct {
+	Name string `json:"name"`
+	// HourlyCost is the estimated cost per hour of the node's allocatable capacity in the
+	// plan's currency
+	HourlyCost float64 `json:"hourlyCost"`
+	// Power is the node's estimated base power draw in Watts; the power of migrated pods moves with them
+	Power float64 `json:"power"`
+}
+
+// BlockedNode is a node the plan cannot empty
+type BlockedNode struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ConsolidationPlan packs the movable pods onto fewer nodes. Migrations are listed in the
+// order they must be applied: node by node, in the order of DrainedNodes. The plan is a
+// recommendation; nothing is evicted.
+type ConsolidationPlan struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	// KeptNodes host every workload once the plan is applied
+	KeptNodes    []string      `json:"keptNodes"`
+	DrainedNodes []DrainedNode `json:"drainedNodes"`
+	Migrations   []Migration   `json:"migrations"`
+	Blocked      []BlockedNode `json:"blocked,omitempty"`
+	// Currency is the currency of the cost savings
+	Currency currency.Currency `json:"currency"`
+	// HourlyCostSavings and PowerSavings sum the drained nodes
+	HourlyCostSavings  float64 `json:"hourlyCostSavings"`
+	MonthlyCostSavings float64 `json:"monthlyCostSavings"`
+	PowerSavings       float64 `json:"powerSavings"`
+	// Confidence is how far the plan can be trusted, from 0 to 1: it drops with migrated pods
+	// that have not settled and with uneven load on the kept nodes
+	Confidence float64 `json:"confidence"`
+}
+
+// Recommendation summarizes the plan for operators
+func (p *ConsolidationPlan) Recommendation() string {
+	if len(p.DrainedNodes) == 0 {
+		return "No node can be emptied onto the others"
+	}
+	money := p.Currency
+	if money == "" {
+		money = currency.Default
+	}
+	return fmt.Sprintf("Drain %d of %d nodes with %d pod migrations to save %s/hour and %.0fW",
+		len(p.DrainedNodes), len(p.DrainedNodes)+len(p.KeptNodes), len(p.Migrations),
+		money.Format(p.HourlyCostSavings), p.PowerSavings)
+}
+
+// consolidationSettleTime is how long a pod must have run for its requests to be trusted
+const consolidationSettleTime = time.Hour
+
+// NodePriceLookup returns the hourly price of the named node and whether it is known
+type NodePriceLookup func(node string) (float64, bool)
+
+// PlanConsolidation looks for the smallest set of nodes able to host every pod. It tries to
+// empty nodes one at a time, least utilized first, moving each pod, largest first, to the
+// kept node it fills the most that placeable admits. A node is only drained when all of
+// its pods fit elsewhere. Nodes that receive pods are kept, so no pod is migrated twice.
+// DaemonSet pods leave with their node; any other pod that cannot move keeps its node. A
+// drained node saves its price from prices, or what costs charges for its allocatable
+// capacity when its price is unknown.
+func PlanConsolidation(nodes []NodeUsage, placeable PlacementFilter, prices NodePriceLookup, costs PricingProvider,
+	power *PowerCalculator) *ConsolidationPlan {
+	s := newFragmentationState(nodes, placeable)
+	plan := &ConsolidationPlan{}
+
+	order := make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Or(cmp.Compare(s.utilization(a), s.utilization(b)), cmp.Compare(nodes[a].Name, nodes[b].Name))
+	})
+
+	drained := make([]bool, len(nodes))
+	kept := make([]bool, len(nodes))
+	for _, candidate := range order {
+		if kept[candidate] {
+			continue
+		}
+		if len(nodes)-countTrue(drained) == 1 {
+			break
+		}
+		migrations, targets, reason := s.drain(candidate, drained)
+		if reason != "" {
+			plan.Blocked = append(plan.Blocked, BlockedNode{Name: nodes[candidate].Name, Reason: reason})
+			continue
+		}
+		drained[candidate] = true
+		for _, target := range targets {
+			kept[target] = true
+		}
+		plan.Migrations = append(plan.Migrations, migrations...)
+
+		node := drainedNode(nodes[candidate], prices, costs, power)
+		plan.DrainedNodes = append(plan.DrainedNodes, node)
+		plan.HourlyCostSavings += node.HourlyCost
+		plan.PowerSavings += node.Power
+	}
+
+	for i, node := range nodes {
+		if !drained[i] {
+			plan.KeptNodes = append(plan.KeptNodes, node.Name)
+		}
+	}
+	slices.Sort(plan.KeptNodes)
+	plan.Confidence = s.consolidationConfidence(plan.Migrations, drained, time.Now())
+	plan.HourlyCostSavings = math.Round(plan.HourlyCostSavings*100) / 100
+	plan.MonthlyCostSavings = math.Round(plan.HourlyCostSavings*24*30*100) / 100
+	return plan
+}
+
+// consolidationConfidence rates a plan by the share of its migrated pods that have run for
+// consolidationSettleTime and by how evenly it loads the kept nodes
+func (s *fragmentationState) consolidationConfidence(migrations []Migration, drained []bool, now time.Time) float64 {
+	settled, moved := 1.0, 1.0
+	if len(migrations) > 0 {
+		created := make(map[string]time.Time)
+		for _, node := range s.nodes {
+			for _, pod := range node.Pods {
+				created[pod.Namespace+"/"+pod.Name] = pod.Created
+			}
+		}
+		settled, moved = 0, float64(len(migrations))
+		for _, migration := range migrations {
+			if now.Sub(created[migration.Namespace+"/"+migration.Pod]) >= consolidationSettleTime {
+				settled++
+			}
+		}
+	}
+	var load []float64
+	for i := range s.nodes {
+		if !drained[i] {
+			load = append(load, s.utilization(i))
+		}
+	}
+	return Confidence(settled, moved, variation(load))
+}
+
+// drain moves every pod off node i onto nodes that are not drained, returning the
+// migrations and the nodes that received pods. When some pod cannot move, nothing is
+// moved and the reason is returned.
+func (s *fragmentationState) drain(i int, drained []bool) ([]Migration, []int, string) {
+	node := s.nodes[i]
+	var pods []PodUsage
+	for _, pod := range node.Pods {
+		switch {
+		case pod.DaemonSet:
+		case !pod.Movable:
+			return nil, nil, fmt.Sprintf("pod %s/%s cannot be migrated", pod.Namespace, pod.Name)
+		default:
+			pods = append(pods, pod)
+		}
+	}
+	slices.SortStableFunc(pods, func(a, b PodUsage) int {
+		return cmp.Compare(s.share(i, b.Requests), s.share(i, a.Requests))
+	})
+
+	var moved []int
+	var migrations []Migration
+	var targets []int
+	for _, pod := range pods {
+		best, bestUtilization := -1, -1.0
+		for to := range s.nodes {
+			if to == i || drained[to] || !s.fits(to, pod) {
+				continue
+			}
+			s.move(i, to, pod.Requests)
+			utilization := s.utilization(to)
+			s.move(to, i, pod.Requests)
+			if utilization > bestUtilization {
+				best, bestUtilization = to, utilization
+			}
+		}
+		if best < 0 {
+			for p := len(moved) - 1; p >= 0; p-- {
+				s.move(moved[p], i, pods[p].Requests)
+			}
+			return nil, nil, fmt.Sprintf("no remaining node fits pod %s/%s", pod.Namespace, pod.Name)
+		}
+		s.move(i, best, pod.Requests)
+		moved = append(moved, best)
+		if !slices.Contains(targets, best) {
+			targets = append(targets, best)
+		}
+		migrations = append(migrations, Migration{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			FromNode:  node.Name,
+			ToNode:    s.nodes[best].Name,
+		})
+	}
+	return migrations, targets, ""
+}
+
+// utilization returns the requested share of node i's most requested resource
+func (s *fragmentationState) utilization(i int) float64 {
+	largest := 0.0
+	for name, quantity := range s.nodes[i].Allocatable {
+		allocatable := quantity.MilliValue()
+		if allocatable <= 0 {
+			continue
+		}
+		largest = max(largest, float64(allocatable-s.free[i][name])/float64(allocatable))
+	}
+	return largest
+}
+
+// share returns the largest fraction of node i's allocatable capacity the requests take
+func (s *fragmentationState) share(i int, requests corev1.ResourceList) float64 {
+	largest := 0.0
+	for name, quantity := range requests {
+		allocatable, ok := s.nodes[i].Allocatable[name]
+		if !ok || allocatable.MilliValue() <= 0 {
+			continue
+		}
+		largest = max(largest, float64(quantity.MilliValue())/float64(allocatable.MilliValue()))
+	}
+	return largest
+}
+
+// drainedNode estimates what removing the node saves
+func drainedNode(node NodeUsage, prices NodePriceLookup, costs PricingProvider, power *PowerCalculator) DrainedNode {
+	cost, ok := 0.0, false
+	if prices != nil {
+		cost, ok = prices(node.Name)
+	}
+	if !ok {
+		cpu := node.Allocatable[corev1.ResourceCPU]
+		memory := node.Allocatable[corev1.ResourceMemory]
+		gpus := node.Allocatable["nvidia.com/gpu"]
+		npus := node.Allocatable["npu.com/npu"]
+		cost = costs.CalculateCost(cpu.AsApproximateFloat64(), memory.AsApproximateFloat64()/(1<<30),
+			int32(gpus.Value()), int32(npus.Value()))
+	}
+	return DrainedNode{
+		Name:       node.Name,
+		HourlyCost: math.Round(cost*100) / 100,
+		Power:      power.BaseSystemPower,
+	}
+}
+
+// countTrue returns how many values are true
+func countTrue(values []bool) int {
+	count := 0
+	for _, value := range values {
+		if value {
+			count++
+		}
+	}
+	return count
+}
@@ -39,6 +39,10 @@ type CostCalculator struct {
 	SpotInstanceDiscount float64
 	// Reserved instance discount factor (0.0-1.0)
 	ReservedInstanceDiscount float64
+	// Cost per GiB of persistent volume per month in USD
+	StorageCostPerGiBMonth float64
+	// Cost per GB sent to the internet in USD
+	EgressCostPerGB float64
 }
 
 // CostBreakdown provides detailed cost breakdown
@@ -72,9 +76,25 @@ func NewCostCalculator() *CostCalculator {
 		BaseInfrastructureCostPerHour: 0.10, // $0.10 base infrastructure cost
 		SpotInstanceDiscount:          0.30, // 30% discount for spot instances
 		ReservedInstanceDiscount:      0.20, // 20% discount for reserved instances
+		StorageCostPerGiBMonth:        0.10, // $0.10 per GiB of block storage per month
+		EgressCostPerGB:               0.09, // $0.09 per GB to the internet
 	}
 }
 
+// HoursPerMonth converts monthly storage rates to hourly ones
+const HoursPerMonth = 730
+
+// StorageCost returns the hourly cost of gib GiB of persistent volume; the calculator prices
+// every storage class alike
+func (c *CostCalculator) StorageCost(gib float64, storageClass string) float64 {
+	return gib * c.StorageCostPerGiBMonth / HoursPerMonth
+}
+
+// EgressCost returns the cost of gb GB sent to the internet
+func (c *CostCalculator) EgressCost(gb float64) float64 {
+	return gb * c.EgressCostPerGB
+}
+
 // CalculateCost calculates the total cost for running a workload
 func (c *CostCalculator) CalculateCost(cpuCores, memoryGB float64, gpuCount, npuCount int32) float64 {
 	breakdown := c.CalculateCostBreakdown(cpuCores, memoryGB, gpuCount, npuCount)
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// CoveringCostPolicies returns the CostPolicies whose selectors match the namespace and the
+// workload, by name. Without a workload, as for pods of no WorkloadOptimizer, only policies
+// selecting no workloads cover it. Policies with invalid selectors are logged and skipped.
+func CoveringCostPolicies(ctx context.Context, c client.Reader, namespace string,
+	wo *kcloudv1alpha1.WorkloadOptimizer) ([]*kcloudv1alpha1.CostPolicy, error) {
+	var policies kcloudv1alpha1.CostPolicyList
+	if err := c.List(ctx, &policies); err != nil {
+		return nil, fmt.Errorf("failed to list cost policies: %w", err)
+	}
+	sort.Slice(policies.Items, func(i, j int) bool {
+		return policies.Items[i].Name < policies.Items[j].Name
+	})
+
+	var workloadLabels labels.Set
+	if wo != nil {
+		workloadLabels = labels.Set(wo.Labels)
+	}
+
+	var covering []*kcloudv1alpha1.CostPolicy
+	var namespaceLabels labels.Set
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if policy.Spec.WorkloadSelector != nil && wo == nil {
+			continue
+		}
+		if policy.Spec.NamespaceSelector != nil && namespaceLabels == nil {
+			var ns corev1.Namespace
+			if err := c.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+				return nil, fmt.Errorf("failed to get namespace: %w", err)
+			}
+			namespaceLabels = labels.Set(ns.Labels)
+		}
+		covered, err := SelectorsCover(policy.Spec.NamespaceSelector, policy.Spec.WorkloadSelector,
+			namespaceLabels, workloadLabels)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Skipping cost policy", "costPolicy", policy.Name)
+			continue
+		}
+		if covered {
+			covering = append(covering, policy)
+		}
+	}
+	return covering, nil
+}
+
+// SelectorsCover reports whether a policy's namespace and workload selectors match the
+// workload; a missing selector matches everything
+func SelectorsCover(namespaceSelector, workloadSelector *metav1.LabelSelector,
+	namespaceLabels, workloadLabels labels.Set) (bool, error) {
+	for _, match := range []struct {
+		selector *metav1.LabelSelector
+		labels   labels.Set
+	}{{namespaceSelector, namespaceLabels}, {workloadSelector, workloadLabels}} {
+		if match.selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(match.selector)
+		if err != nil {
+			return false, fmt.Errorf("invalid selector: %w", err)
+		}
+		if !selector.Matches(match.labels) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
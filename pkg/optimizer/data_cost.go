@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// DefaultTransmitWindow is the window a workload's observed transmit rate is averaged over
+const DefaultTransmitWindow = time.Hour
+
+// StoragePricing is a pricing provider that charges for persistent volume capacity
+type StoragePricing interface {
+	// StorageCost returns the cost per hour of gib GiB of the storage class
+	StorageCost(gib float64, storageClass string) float64
+}
+
+// EgressPricing is a pricing provider that charges for traffic sent to the internet
+type EgressPricing interface {
+	// EgressCost returns the cost of gb GB sent to the internet
+	EgressCost(gb float64) float64
+}
+
+// DataTransferPricing prices the transfer to a workload's data endpoints in other zones and
+// regions from a node, such as the scheduler's transfer pricing
+type DataTransferPricing interface {
+	EgressCost(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64
+}
+
+// TransmitSource reports the traffic a workload's pods send
+type TransmitSource interface {
+	// TransmitRate returns the GB per hour the pods sent over the window; found is false
+	// without samples
+	TransmitRate(ctx context.Context, namespace string, pods []string, window time.Duration) (float64, bool, error)
+}
+
+// CostComponents splits an hourly cost in the engine's reporting currency by what it pays for
+type CostComponents struct {
+	// Compute is the CPU, memory, accelerators and energy
+	Compute float64
+	// GPU is the part of Compute paid for GPUs
+	GPU float64
+	// Storage is the persistent volumes
+	Storage float64
+	// Network is the transfer to other zones and regions and egress to the internet
+	Network float64
+}
+
+// addDataCost adds the workload's storage and network cost to the compute estimate and
+// splits the result into its components; a realized cost already includes both
+func (e *Engine) addDataCost(state *WorkloadState, realized RealizedCost, result *OptimizationResult) {
+	components := &result.CostComponents
+	if result.CostRealized {
+		components.GPU = e.Currency.FromUSD(realized.GPU)
+		components.Storage, components.Network = e.Currency.FromUSD(realized.Storage), e.Currency.FromUSD(realized.Network)
+		components.Compute = result.EstimatedCost - components.Storage - components.Network
+		return
+	}
+	components.Compute = result.EstimatedCost
+	components.Storage = e.Currency.FromUSD(e.storageCost(state))
+	components.Network = e.Currency.FromUSD(e.networkCost(state, findNode(state.AvailableNodes, result.AssignedNode)))
+	result.EstimatedCost += components.Storage + components.Network
+}
+
+// storageCost prices the capacity of the workload's claims in USD per hour, or its declared
+// storage before any claim is bound
+func (e *Engine) storageCost(state *WorkloadState) float64 {
+	pricing, ok := e.CostCalculator.(StoragePricing)
+	if !ok {
+		return 0
+	}
+	cost, bound := 0.0, false
+	for i := range state.Claims {
+		claim := &state.Claims[i]
+		if claim.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+		capacity, ok := claim.Status.Capacity[corev1.ResourceStorage]
+		if !ok {
+			capacity = claim.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+		class := ""
+		if claim.Spec.StorageClassName != nil {
+			class = *claim.Spec.StorageClassName
+		}
+		cost += pricing.StorageCost(gibibytes(capacity), class)
+		bound = true
+	}
+	if bound {
+		return cost
+	}
+	resources := state.WorkloadOptimizer.Spec.Resources
+	capacity, err := resource.ParseQuantity(resources.Storage)
+	if resources.Storage == "" || err != nil {
+		return 0
+	}
+	return pricing.StorageCost(gibibytes(capacity), resources.StorageClassName)
+}
+
+// networkCost prices the workload's transfer to data endpoints from the node and its
+// internet egress in USD per hour
+func (e *Engine) networkCost(state *WorkloadState, node *corev1.Node) float64 {
+	wo := state.WorkloadOptimizer
+	cost := 0.0
+	if e.Transfer != nil && node != nil {
+		cost += e.Transfer.EgressCost(wo, node)
+	}
+	if pricing, ok := e.CostCalculator.(EgressPricing); ok {
+		cost += pricing.EgressCost(EgressGBPerHour(wo, state.TransmitGBPerHour))
+	}
+	return cost
+}
+
+// EgressGBPerHour returns the internet egress of the workload: the declared volume, or the
+// observed transmit rate less the transfer declared to its data endpoints. Workloads without
+// an egress spec send nothing to the internet.
+func EgressGBPerHour(wo *kcloudv1alpha1.WorkloadOptimizer, transmitGBPerHour *float64) float64 {
+	egress := wo.Spec.Egress
+	switch {
+	case egress == nil:
+		return 0
+	case egress.GBPerHour != nil:
+		return *egress.GBPerHour
+	case transmitGBPerHour == nil:
+		return 0
+	}
+	observed := *transmitGBPerHour
+	if wo.Spec.PlacementPolicy != nil {
+		for _, endpoint := range wo.Spec.PlacementPolicy.DataEndpoints {
+			observed -= endpoint.TransferGBPerHour
+		}
+	}
+	return max(observed, 0)
+}
+
+// gibibytes converts a storage quantity to GiB
+func gibibytes(quantity resource.Quantity) float64 {
+	return quantity.AsApproximateFloat64() / (1 << 30)
+}
+
+// TransmitRate returns the GB per hour the pods sent over the window, from the cAdvisor
+// network counters
+func (p *PrometheusUsageSource) TransmitRate(ctx context.Context, namespace string, pods []string,
+	window time.Duration) (float64, bool, error) {
+	if len(pods) == 0 {
+		return 0, false, nil
+	}
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = regexp.QuoteMeta(pod)
+	}
+	bytesPerSecond, found, err := p.query(ctx, fmt.Sprintf(
+		`sum(rate(container_network_transmit_bytes_total{namespace=%q,pod=~%q}[%ds]))`,
+		namespace, strings.Join(names, "|"), int64(max(window, prometheusStepDuration).Seconds())))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query transmit rate: %w", err)
+	}
+	return bytesPerSecond * 3600 / 1e9, found, nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultDCGMWindow is how far back GPU usage is averaged
+	DefaultDCGMWindow = 5 * time.Minute
+
+	// dcgmRefresh is how often DCGM metrics are read, and dcgmExpiry how long they are used
+	// while reads fail
+	dcgmRefresh = time.Minute
+	dcgmExpiry  = 5 * dcgmRefresh
+)
+
+// GPUUsage is the measured usage of the GPUs a pod or workload holds
+type GPUUsage struct {
+	// Power is the power the GPUs draw in watts
+	Power float64
+	// Utilization is the compute utilization (0.0-1.0) of the busiest GPU
+	Utilization float64
+	// MemoryGB is the framebuffer memory used
+	MemoryGB float64
+	// MemoryShare is the share (0.0-1.0) of its framebuffer the fullest GPU uses
+	MemoryShare float64
+}
+
+// Share is the share of a GPU the usage needs, the greater of its compute and memory
+func (u GPUUsage) Share() float64 {
+	return max(u.Utilization, u.MemoryShare)
+}
+
+// GPUUsageSource reports the measured usage of pods' GPUs
+type GPUUsageSource interface {
+	// PodGPU returns the usage of the pod's GPUs and whether it is known
+	PodGPU(namespace, name string) (GPUUsage, bool)
+}
+
+// dcgmSnapshot is the GPU usage of every pod DCGM reported, and when it was read
+type dcgmSnapshot struct {
+	pods   map[types.NamespacedName]GPUUsage
+	readAt time.Time
+}
+
+// DCGM reads the GPU power, utilization and memory the NVIDIA DCGM exporter reports to
+// Prometheus per pod, averaged over a trailing window
+type DCGM struct {
+	prometheus *PrometheusUsageSource
+	window     time.Duration
+	clock      clock.PassiveClock
+
+	snapshot atomic.Pointer[dcgmSnapshot]
+}
+
+// NewDCGM returns a GPU usage source reading DCGM exporter metrics from prometheus
+func NewDCGM(prometheus *PrometheusUsageSource, window time.Duration) *DCGM {
+	if window <= 0 {
+		window = DefaultDCGMWindow
+	}
+	return &DCGM{
+		prometheus: prometheus,
+		window:     max(window, prometheusStepDuration),
+		clock:      clock.RealClock{},
+	}
+}
+
+// SetClock replaces the clock measurement ages are measured with
+func (d *DCGM) SetClock(c clock.PassiveClock) {
+	d.clock = c
+}
+
+// PodGPU returns the pod's GPU usage over the window from the last metrics read, unless
+// they have expired
+func (d *DCGM) PodGPU(namespace, name string) (GPUUsage, bool) {
+	snapshot := d.snapshot.Load()
+	if snapshot == nil || d.clock.Since(snapshot.readAt) > dcgmExpiry {
+		return GPUUsage{}, false
+	}
+	usage, ok := snapshot.pods[types.NamespacedName{Namespace: namespace, Name: name}]
+	return usage, ok
+}
+
+// Refresh reads the GPU usage of every pod over the window
+func (d *DCGM) Refresh(ctx context.Context) error {
+	rangeSelector := fmt.Sprintf("[%ds]", int64(d.window.Seconds()))
+
+	pods := make(map[types.NamespacedName]GPUUsage)
+	for _, series := range []struct {
+		metric string
+		query  string
+		set    func(u *GPUUsage, value float64)
+	}{
+		{"power", fmt.Sprintf(`sum by (namespace, pod) (avg_over_time(DCGM_FI_DEV_POWER_USAGE{pod!=""}%s))`,
+			rangeSelector), func(u *GPUUsage, v float64) { u.Power = v }},
+		{"utilization", fmt.Sprintf(`max by (namespace, pod) (avg_over_time(DCGM_FI_DEV_GPU_UTIL{pod!=""}%s)) / 100`,
+			rangeSelector), func(u *GPUUsage, v float64) { u.Utilization = math.Min(v, 1) }},
+		// Framebuffer memory is reported in MiB
+		{"memory", fmt.Sprintf(`sum by (namespace, pod) (avg_over_time(DCGM_FI_DEV_FB_USED{pod!=""}%s))`,
+			rangeSelector), func(u *GPUUsage, v float64) { u.MemoryGB = v / 1024 }},
+		{"memory share", fmt.Sprintf(`max by (namespace, pod) (avg_over_time(DCGM_FI_DEV_FB_USED{pod!=""}%[1]s) / `+
+			`(avg_over_time(DCGM_FI_DEV_FB_USED{pod!=""}%[1]s) + avg_over_time(DCGM_FI_DEV_FB_FREE{pod!=""}%[1]s)))`,
+			rangeSelector), func(u *GPUUsage, v float64) { u.MemoryShare = math.Min(v, 1) }},
+	} {
+		values, err := d.prometheus.queryByPod(ctx, series.query)
+		if err != nil {
+			return fmt.Errorf("failed to query dcgm gpu %s: %w", series.metric, err)
+		}
+		for pod, value := range values {
+			u := pods[pod]
+			series.set(&u, value)
+			pods[pod] = u
+		}
+	}
+	d.snapshot.Store(&dcgmSnapshot{pods: pods, readAt: d.clock.Now()})
+	return nil
+}
+
+// Start reads metrics every minute until the context is cancelled; a failed read keeps the
+// previous metrics until they expire
+func (d *DCGM) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("dcgm")
+
+	ticker := time.NewTicker(dcgmRefresh)
+	defer ticker.Stop()
+	for {
+		if err := d.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read DCGM GPU usage")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reads metrics on every replica, as every replica estimates power
+func (d *DCGM) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// DeadlineAnnotation carries a workload's deadline, in RFC 3339, on its pods so that the
+	// scheduling queue can order them without looking the workload up
+	DeadlineAnnotation = "kcloud.io/deadline"
+
+	// RelativeSpeedLabel declares how fast a node runs workloads relative to a typical node,
+	// such as "1.5" for a node that finishes them in two thirds of the time
+	RelativeSpeedLabel = "kcloud.io/relative-speed"
+
+	// deadlineMargin is the share of the run time kept as slack before a deadline is at risk
+	deadlineMargin = 0.1
+)
+
+// NodeSpeed returns how fast the node runs workloads relative to a typical node: its
+// relative speed label, else its cost tier, as pricier tiers are assumed to be faster
+func NodeSpeed(node *corev1.Node) float64 {
+	if speed, err := strconv.ParseFloat(node.Labels[RelativeSpeedLabel], 64); err == nil && speed > 0 {
+		return speed
+	}
+	switch node.Labels["cost-tier"] {
+	case "high":
+		return 1.25
+	case "low":
+		return 0.75
+	default:
+		return 1.0
+	}
+}
+
+// DeadlineFit rates how well the node meets the workload's deadline: 1 when the workload
+// completes in time with margin to spare, otherwise at most 0.5, in proportion to the share
+// of the run that fits before the deadline. Workloads without a deadline and expected
+// duration fit every node.
+func DeadlineFit(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, now time.Time) float64 {
+	deadline := wo.Spec.Deadline
+	if deadline == nil || deadline.ExpectedDuration == nil || deadline.ExpectedDuration.Duration <= 0 {
+		return 1.0
+	}
+	needed := float64(deadline.ExpectedDuration.Duration) / NodeSpeed(node) * (1 + deadlineMargin)
+	available := float64(deadline.CompleteBy.Sub(now))
+	if available >= needed {
+		return 1.0
+	}
+	return 0.5 * max(available, 0) / needed
+}
+
+// DeadlineAtRisk reports whether a node of relative speed 1.0 would miss the workload's
+// deadline, leaving only faster nodes to meet it
+func DeadlineAtRisk(wo *kcloudv1alpha1.WorkloadOptimizer, now time.Time) bool {
+	deadline := wo.Spec.Deadline
+	if deadline == nil || deadline.ExpectedDuration == nil {
+		return false
+	}
+	needed := time.Duration(float64(deadline.ExpectedDuration.Duration) * (1 + deadlineMargin))
+	return deadline.CompleteBy.Sub(now) < needed
+}
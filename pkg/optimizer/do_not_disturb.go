@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"time"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// DoNotDisturbUntilAnnotation takes a do-not-disturb lease without a spec change; the
+	// value is an RFC 3339 expiry time
+	DoNotDisturbUntilAnnotation = "kcloud.io/do-not-disturb-until"
+
+	// DoNotDisturbReasonAnnotation explains an annotation lease
+	DoNotDisturbReasonAnnotation = "kcloud.io/do-not-disturb-reason"
+
+	// MaxDoNotDisturbLease bounds how far ahead a lease may expire
+	MaxDoNotDisturbLease = 7 * 24 * time.Hour
+)
+
+// DoNotDisturb is the workload's do-not-disturb lease, from its spec or annotation
+type DoNotDisturb struct {
+	Until  time.Time
+	Reason string
+	Source string
+}
+
+// Active reports whether the lease still exempts the workload at now
+func (d *DoNotDisturb) Active(now time.Time) bool {
+	return d != nil && now.Before(d.Until)
+}
+
+// Explanation describes the lease for decision explanations
+func (d *DoNotDisturb) Explanation() string {
+	reason := d.Reason
+	if reason == "" {
+		reason = "no reason given"
+	}
+	return fmt.Sprintf("exempt from descheduling until %s by %s lease: %s",
+		d.Until.UTC().Format(time.RFC3339), d.Source, reason)
+}
+
+// DoNotDisturbLease returns the workload's lease; the spec field wins over the annotation.
+// It returns an error if the annotation is not an RFC 3339 time.
+func DoNotDisturbLease(wo *kcloudv1alpha1.WorkloadOptimizer) (*DoNotDisturb, error) {
+	if lease := wo.Spec.DoNotDisturb; lease != nil {
+		return &DoNotDisturb{Until: lease.Until.Time, Reason: lease.Reason, Source: "spec"}, nil
+	}
+
+	value, ok := wo.Annotations[DoNotDisturbUntilAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", DoNotDisturbUntilAnnotation, err)
+	}
+	return &DoNotDisturb{
+		Until:  until,
+		Reason: wo.Annotations[DoNotDisturbReasonAnnotation],
+		Source: "annotation",
+	}, nil
+}
+
+// DoNotDisturbActive reports whether the workload holds an unexpired lease at now
+func DoNotDisturbActive(wo *kcloudv1alpha1.WorkloadOptimizer, now time.Time) bool {
+	lease, err := DoNotDisturbLease(wo)
+	return err == nil && lease.Active(now)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// CPUGovernorAnnotation is the node annotation the operator requests a CPU frequency
+	// governor in
+	CPUGovernorAnnotation = "kcloud.io/cpu-governor"
+	// CPUGovernorAppliedAnnotation is the node annotation the node agent reports the governor
+	// it applied in. It is removed once the agent restored the original governors.
+	CPUGovernorAppliedAnnotation = "kcloud.io/cpu-governor-applied"
+	// OriginalCPUGovernorsAnnotation is the node annotation the node agent records the
+	// governor of each CPU in before it switches the first time, as a JSON map of CPU to
+	// governor, so it can restore exactly those
+	OriginalCPUGovernorsAnnotation = "kcloud.io/original-cpu-governors"
+)
+
+// CPUGovernor is a Linux cpufreq governor and the share of the maximum frequency CPUs settle
+// at under it while busy
+type CPUGovernor struct {
+	Name           string
+	FrequencyScale float64
+}
+
+// DefaultCPUGovernors is the CPU frequency governor recommended per workload type. Batch
+// jobs care about throughput over a deadline rather than latency and run at the lowest
+// frequency; training is mostly bound by its GPUs, so its CPUs ramp up only under sustained
+// load. Request-serving workloads are latency-sensitive and keep the node's governor.
+var DefaultCPUGovernors = map[string]CPUGovernor{
+	"batch":    {Name: "powersave", FrequencyScale: 0.6},
+	"training": {Name: "conservative", FrequencyScale: 0.8},
+}
+
+// CPUFrequencyAdvice is the CPU frequency governor recommended for a workload
+type CPUFrequencyAdvice struct {
+	Governor       string
+	FrequencyScale float64
+	// PowerSavings is the estimated power saved, in watts
+	PowerSavings float64
+	// PerformanceImpact is the estimated share by which the workload's runtime grows
+	PerformanceImpact float64
+	// Confidence is that of the rightsizing usage the performance impact is estimated
+	// from, and 0 without it
+	Confidence float64
+	Reason     string
+}
+
+// RecommendCPUFrequency recommends the governor of the workload's type from
+// DefaultCPUGovernors, false for types that keep the node's governor. The CPU power, the
+// workload's power less that of its GPUs, falls with the square of the frequency as the
+// voltage drops with it. Runtime grows with the inverse of the frequency for the share of
+// time the CPUs are busy, from the P95 usage over the requests, or all of it before usage
+// is measured.
+func RecommendCPUFrequency(wo *kcloudv1alpha1.WorkloadOptimizer) (CPUFrequencyAdvice, bool) {
+	governor, ok := DefaultCPUGovernors[wo.Spec.WorkloadType]
+	if !ok {
+		return CPUFrequencyAdvice{}, false
+	}
+	cpuPower := max(ptr.Deref(wo.Status.CurrentPower, 0)-ptr.Deref(wo.Status.GPUPower, 0), 0)
+	busy := 1.0
+	confidence := 0.0
+	if rightsizing := wo.Status.Rightsizing; rightsizing != nil {
+		if cores := quantityValue(wo.Spec.Resources.CPU); cores > 0 {
+			busy = math.Min(rightsizing.CPUP95/cores, 1)
+		}
+		confidence = rightsizing.Confidence
+	}
+
+	advice := CPUFrequencyAdvice{
+		Governor:          governor.Name,
+		FrequencyScale:    governor.FrequencyScale,
+		PowerSavings:      math.Round(cpuPower*(1-governor.FrequencyScale*governor.FrequencyScale)*10) / 10,
+		PerformanceImpact: math.Round(busy*(1/governor.FrequencyScale-1)*100) / 100,
+		Confidence:        confidence,
+	}
+	advice.Reason = fmt.Sprintf("%s workloads tolerate lower CPU frequencies: %s saves an estimated %.0f W "+
+		"for %.0f%% longer runs", wo.Spec.WorkloadType, governor.Name, advice.PowerSavings, advice.PerformanceImpact*100)
+	return advice, true
+}
+
+// ReadCPUGovernors returns the cpufreq governor of every CPU under root, by CPU
+func ReadCPUGovernors(root string) (map[string]string, error) {
+	dirs, err := filepath.Glob(filepath.Join(root, "cpu[0-9]*", "cpufreq"))
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no cpufreq policies under %s", root)
+	}
+	governors := make(map[string]string, len(dirs))
+	for _, dir := range dirs {
+		governor, err := os.ReadFile(filepath.Join(dir, "scaling_governor"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read governor: %w", err)
+		}
+		governors[filepath.Base(filepath.Dir(dir))] = strings.TrimSpace(string(governor))
+	}
+	return governors, nil
+}
+
+// RestoreCPUGovernors sets each CPU under root back to the recorded governor; CPUs without
+// one are left alone
+func RestoreCPUGovernors(root string, governors map[string]string) error {
+	for cpu, governor := range governors {
+		path := filepath.Join(root, filepath.Base(cpu), "cpufreq", "scaling_governor")
+		if err := os.WriteFile(path, []byte(governor), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// ApplyCPUGovernor sets the cpufreq governor of every CPU under root, usually
+// /sys/devices/system/cpu. It fails when a CPU does not offer the governor.
+func ApplyCPUGovernor(root, governor string) error {
+	dirs, err := filepath.Glob(filepath.Join(root, "cpu[0-9]*", "cpufreq"))
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("no cpufreq policies under %s", root)
+	}
+	for _, dir := range dirs {
+		available, err := os.ReadFile(filepath.Join(dir, "scaling_available_governors"))
+		if err != nil {
+			return fmt.Errorf("failed to read available governors: %w", err)
+		}
+		if !slices.Contains(strings.Fields(string(available)), governor) {
+			return fmt.Errorf("governor %q is not available in %s", governor, dir)
+		}
+		path := filepath.Join(dir, "scaling_governor")
+		if err := os.WriteFile(path, []byte(governor), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// defaultGridCarbonIntensity is used when a profile does not set one, in g CO2 per kWh
+const defaultGridCarbonIntensity = 500.0
+
+// CarbonAccount captures the energy source breakdown for a workload's power draw
+type CarbonAccount struct {
+	PoolName        string
+	RenewableShare  float64 // 0.0-1.0
+	CarbonIntensity float64 // g CO2 per kWh
+	FacilityPower   float64 // Watts including datacenter overhead
+	CarbonFootprint float64 // kg CO2 per hour
+}
+
+// PUE returns the power usage effectiveness of the profile's pool, 1 when it is not configured
+func PUE(profile *kcloudv1alpha1.NodePowerProfile) float64 {
+	if profile == nil || profile.Spec.PUE == nil || *profile.Spec.PUE < 1 {
+		return 1
+	}
+	return *profile.Spec.PUE
+}
+
+// RenewableFraction returns the effective renewable share (0.0-1.0) of an energy mix
+func RenewableFraction(mix kcloudv1alpha1.EnergyMix) float64 {
+	total := mix.GridPercentage + mix.OnSiteSolarPercentage + mix.PPAPercentage
+	if total <= 0 {
+		return 0
+	}
+
+	renewable := mix.OnSiteSolarPercentage + mix.PPAPercentage +
+		mix.GridPercentage*mix.GridRenewablePercentage/100
+	return renewable / total
+}
+
+// CarbonIntensity returns the blended carbon intensity of an energy mix in g CO2 per kWh
+func CarbonIntensity(mix kcloudv1alpha1.EnergyMix) float64 {
+	gridIntensity := mix.GridCarbonIntensity
+	if gridIntensity <= 0 {
+		gridIntensity = defaultGridCarbonIntensity
+	}
+	return (1 - RenewableFraction(mix)) * gridIntensity
+}
+
+// ProfileForNode returns the profile whose node selector matches the node, the first by
+// name when several do, so the answer does not depend on the order profiles were listed in
+func ProfileForNode(profiles []kcloudv1alpha1.NodePowerProfile, node corev1.Node) *kcloudv1alpha1.NodePowerProfile {
+	var match *kcloudv1alpha1.NodePowerProfile
+	for i := range profiles {
+		if profileSelectsNode(&profiles[i], node) && (match == nil || profiles[i].Name < match.Name) {
+			match = &profiles[i]
+		}
+	}
+	return match
+}
+
+// AccountCarbon attributes a workload's power draw, with its datacenter overhead, to the
+// energy mix of its node pool
+func AccountCarbon(powerWatts float64, profile *kcloudv1alpha1.NodePowerProfile) *CarbonAccount {
+	if profile == nil {
+		return &CarbonAccount{
+			CarbonIntensity: defaultGridCarbonIntensity,
+			FacilityPower:   powerWatts,
+			CarbonFootprint: powerWatts * defaultGridCarbonIntensity / 1e6,
+		}
+	}
+
+	intensity := CarbonIntensity(profile.Spec.EnergyMix)
+	facilityPower := powerWatts * PUE(profile)
+	return &CarbonAccount{
+		PoolName:        profile.Spec.PoolName,
+		RenewableShare:  RenewableFraction(profile.Spec.EnergyMix),
+		CarbonIntensity: intensity,
+		FacilityPower:   facilityPower,
+		CarbonFootprint: facilityPower * intensity / 1e6, // W * g/kWh -> kg/h
+	}
+}
+
+// profileSelectsNode checks whether all of the profile's selector labels match the node
+func profileSelectsNode(profile *kcloudv1alpha1.NodePowerProfile, node corev1.Node) bool {
+	if len(profile.Spec.NodeSelector) == 0 {
+		return false
+	}
+	for key, value := range profile.Spec.NodeSelector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -2,34 +2,131 @@ package optimizer
 
 import (
 	"context"
-	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
 )
 
+// PricingProvider prices a resource request per hour in USD
+type PricingProvider interface {
+	CalculateCost(cpuCores, memoryGB float64, gpuCount, npuCount int32) float64
+}
+
+// PowerEstimator estimates the power draw of a resource request in Watts
+type PowerEstimator interface {
+	CalculatePower(cpuCores, memoryGB float64, gpuCount, npuCount int32) float64
+}
+
 type Engine struct {
-	CostCalculator  *CostCalculator
-	PowerCalculator *PowerCalculator
+	CostCalculator  PricingProvider
+	PowerCalculator PowerEstimator
+	// Accelerators prices and powers the registered accelerator types workloads request
+	Accelerators *AcceleratorRegistry
+	// NodePricing prices replicas from the live price of the node they run on; nil or an
+	// unpriced node uses the CostCalculator's rates
+	NodePricing NodePricing
+	// Pareto, when set, picks among non-dominated node and replica options instead of
+	// estimating a single replica
+	Pareto *ParetoWeights
+	// ScoreWeights weigh the components of the optimization score; nil uses DefaultOptimizationScoreWeights
+	ScoreWeights *OptimizationScoreWeights
+	// SLA predicts the latency of inference workloads with SLA targets; nil ignores the targets
+	SLA *SLAModel
+	// Cluster and Placer let Simulate evaluate workloads against the current cluster
+	Cluster ClusterStateSource
+	Placer  Placer
+	// Currency converts estimated costs from US dollars into the reporting currency the
+	// workloads' cost constraints are written in; nil reports in US dollars
+	Currency *currency.Converter
+	// Realized reports what running pods were charged, such as by OpenCost; a workload whose
+	// running pods it all knows is reported at that cost instead of the estimate. nil
+	// estimates every cost.
+	Realized RealizedCostSource
+	// Power reports what running pods were measured drawing, such as by Kepler; a workload
+	// whose running pods it all knows is reported at that power instead of the estimate. nil
+	// estimates every power.
+	Power MeasuredPowerSource
+	// NodePower reports what nodes were measured drawing, such as by their BMCs; running pods
+	// the Power source does not know are given a share of their node's power by the CPU they
+	// request. nil estimates the power of those pods.
+	NodePower NodePowerSource
+	// AttributedPower reports the share of their node's measured power attributed to running
+	// pods by their utilization; it replaces the request share for pods neither the Power nor
+	// the GPU source knows. nil shares node power by request.
+	AttributedPower PodPowerSource
+	// GPU reports the measured usage of running pods' GPUs, such as by DCGM; the GPU power of
+	// pods the Power source does not know replaces their estimated GPU power. nil estimates
+	// GPU power.
+	GPU GPUUsageSource
+	// Transfer prices the transfer to workloads' data endpoints in other zones and regions;
+	// nil leaves it out of estimates
+	Transfer DataTransferPricing
 }
 
 type WorkloadState struct {
 	WorkloadOptimizer *kcloudv1alpha1.WorkloadOptimizer
 	Pods              []corev1.Pod
-	AvailableNodes    []corev1.Node
+	// AvailableNodes are the ready nodes; running pods are accounted on them even when cordoned
+	AvailableNodes []corev1.Node
+	// PlacementNodes are the nodes new placements may choose, leaving out cordoned and
+	// draining ones; nil chooses among AvailableNodes
+	PlacementNodes []corev1.Node
+	EnergyProfiles []kcloudv1alpha1.NodePowerProfile
+	// ReservedNode is where the scheduler holds capacity for the workload before its pods run
+	ReservedNode string
+	// CostPolicy is the policy covering the workload; its resource prices replace the defaults
+	CostPolicy *kcloudv1alpha1.CostPolicy
+	// Claims are the persistent volume claims the workload's pods mount
+	Claims []corev1.PersistentVolumeClaim
+	// TransmitGBPerHour is the traffic the workload's pods were observed to send; nil when
+	// it was not measured
+	TransmitGBPerHour *float64
+}
+
+// placementNodes returns the nodes new placements may choose
+func (s *WorkloadState) placementNodes() []corev1.Node {
+	if s.PlacementNodes != nil {
+		return s.PlacementNodes
+	}
+	return s.AvailableNodes
 }
 
 type OptimizationResult struct {
-	EstimatedCost        float64
-	EstimatedPower       float64
-	Score                float64
+	// EstimatedCost is the cost per hour in the engine's reporting currency
+	EstimatedCost  float64
+	EstimatedPower float64
+	// CostRealized is true when EstimatedCost is what the running pods were charged
+	CostRealized bool
+	// PowerMeasured is true when EstimatedPower is what the running pods were measured drawing
+	PowerMeasured bool
+	// GPUUsage is the measured usage of the running pods' GPUs, nil when none was measured
+	GPUUsage *GPUUsage
+	// CostComponents splits EstimatedCost into compute, storage and network cost
+	CostComponents CostComponents
+	// PodCosts are the costs of the running pods, ordered by name
+	PodCosts []PodCost
+	Score    float64
+	// ScoreBreakdown explains Score
+	ScoreBreakdown       ScoreBreakdown
 	RequiresRescheduling bool
 	AssignedNode         string
 	RecommendedReplicas  int32
+	RenewableShare       float64
+	CarbonFootprint      float64
+	// FacilityPower is EstimatedPower including the datacenter overhead of the node pool
+	FacilityPower float64
+	// DeschedulingExemption explains why rescheduling was suppressed, if it was
+	DeschedulingExemption string
+	// ParetoFront holds the best non-dominated options when Pareto optimization is enabled
+	ParetoFront []ParetoOption
+	// SLA is the predicted latency of the result, for workloads with SLA targets
+	SLA *SLAPrediction
 }
 
 func NewEngine() *Engine {
@@ -41,41 +138,100 @@ func NewEngine() *Engine {
 
 func (e *Engine) Optimize(ctx context.Context, state *WorkloadState) *OptimizationResult {
 	log := log.FromContext(ctx)
+	e = e.withPolicyPricing(state.CostPolicy)
 	wo := state.WorkloadOptimizer
 	result := &OptimizationResult{RequiresRescheduling: false, RecommendedReplicas: 1}
 	cpuCores := e.parseCPU(wo.Spec.Resources.CPU)
 	memoryGB := e.parseMemory(wo.Spec.Resources.Memory)
-	baseCost := e.CostCalculator.CalculateCost(cpuCores, memoryGB, wo.Spec.Resources.GPU, wo.Spec.Resources.NPU)
-	if wo.Spec.CostConstraints != nil && wo.Spec.CostConstraints.PreferSpot {
-		baseCost *= 0.7
-	}
-	result.EstimatedCost = baseCost
-	basePower := e.PowerCalculator.CalculatePower(cpuCores, memoryGB, wo.Spec.Resources.GPU, wo.Spec.Resources.NPU)
-	if wo.Spec.PowerConstraints != nil && wo.Spec.PowerConstraints.PreferGreen {
-		basePower *= 0.9
+	replicaCost := e.CostCalculator.CalculateCost(cpuCores, memoryGB, wo.Spec.Resources.GPU, wo.Spec.Resources.NPU)
+	replicaCost += e.Accelerators.Cost(wo.Spec.Resources.Accelerators)
+	replicaPower := e.PowerCalculator.CalculatePower(cpuCores, memoryGB, wo.Spec.Resources.GPU, wo.Spec.Resources.NPU)
+	replicaPower += e.Accelerators.Power(wo.Spec.Resources.Accelerators)
+	var realized RealizedCost
+	if running, ok := e.estimateRunning(state); ok {
+		// Running pods are priced from their own requests on their nodes
+		result.EstimatedCost, result.EstimatedPower, result.AssignedNode = running.Cost, running.Power, running.Node
+		result.CostComponents.GPU = e.Currency.FromUSD(running.GPUCost)
+		if watts, ok := e.measuredRunning(state); ok {
+			result.EstimatedPower, result.PowerMeasured = watts, true
+		}
+		result.GPUUsage = e.measuredGPU(state)
+		if realized, ok = e.realizedRunning(state); ok {
+			// What the pods were charged replaces the estimate, which is calibrated against it
+			recordCostDiscrepancy(wo, running.Cost, realized.Compute)
+			result.EstimatedCost, result.CostRealized = realized.Total, true
+		}
+		result.PodCosts = e.podCosts(wo, running.Pods, result.CostRealized)
+	} else {
+		// Otherwise one replica is priced on the reserved node, if any
+		node := reservedNode(state)
+		result.EstimatedCost, result.EstimatedPower = nodeAdjusted(wo, node,
+			e.costOnNode(wo, node, cpuCores, memoryGB, wo.Spec.Resources.GPU, replicaCost), replicaPower)
+		result.AssignedNode = state.ReservedNode
+		result.CostComponents.GPU = e.Currency.FromUSD(e.gpuCostOnNode(wo, node, cpuCores, memoryGB, wo.Spec.Resources.GPU))
 	}
-	result.EstimatedPower = basePower
-	result.Score = e.calculateScore(wo, result)
-	result.RequiresRescheduling = result.Score < 0.5
+	result.EstimatedCost = e.Currency.FromUSD(result.EstimatedCost)
 	if wo.Spec.AutoScaling != nil {
 		result.RecommendedReplicas = wo.Spec.AutoScaling.MinReplicas
 	}
-	log.Info("Optimization completed", "cost", result.EstimatedCost)
+	if e.SLA != nil && wo.Spec.SLA != nil {
+		// Scale up to the fewest replicas predicted to meet the SLA where the workload runs
+		prediction := e.SLA.Plan(wo, findNode(state.AvailableNodes, result.AssignedNode))
+		result.RecommendedReplicas = max(result.RecommendedReplicas, prediction.Replicas)
+		result.SLA = &prediction
+	}
+	if e.Pareto != nil {
+		// Pareto options apply each node's own multipliers to the per-replica estimate
+		replicaCost, replicaPower = nodeAdjusted(wo, nil, replicaCost, replicaPower)
+		e.optimizePareto(state, cpuCores, memoryGB, replicaCost, replicaPower, result)
+	}
+	if energy, ok := e.CostCalculator.(EnergyPricing); ok && !result.CostRealized {
+		// The energy drawn is charged at the electricity tariff
+		result.EstimatedCost += e.Currency.FromUSD(energy.EnergyCost(result.EstimatedPower))
+	}
+	e.addDataCost(state, realized, result)
+	account := e.accountCarbon(state, result.EstimatedPower)
+	result.RenewableShare, result.CarbonFootprint, result.FacilityPower =
+		account.RenewableShare, account.CarbonFootprint, account.FacilityPower
+	result.Score, result.ScoreBreakdown = e.calculateScore(wo, result)
+	result.RequiresRescheduling = result.Score < 0.5
+	if lease, err := DoNotDisturbLease(wo); err == nil && lease.Active(time.Now()) {
+		result.RequiresRescheduling = false
+		result.DeschedulingExemption = lease.Explanation()
+	}
+	log.Info("Optimization completed", "cost", result.EstimatedCost, "realized", result.CostRealized,
+		"power", result.EstimatedPower, "measured", result.PowerMeasured, "node", result.AssignedNode)
 	return result
 }
 
-func (e *Engine) calculateScore(wo *kcloudv1alpha1.WorkloadOptimizer, result *OptimizationResult) float64 {
-	score := 1.0
-	if wo.Spec.CostConstraints != nil {
-		ratio := result.EstimatedCost / wo.Spec.CostConstraints.MaxCostPerHour
-		if ratio > 1.0 {
-			score -= (ratio - 1.0) * 0.5
+// accountCarbon averages the carbon accounts of the nodes running the workload's pods
+func (e *Engine) accountCarbon(state *WorkloadState, powerWatts float64) CarbonAccount {
+	nodes := make(map[string]corev1.Node, len(state.AvailableNodes))
+	for _, node := range state.AvailableNodes {
+		nodes[node.Name] = node
+	}
+	var total CarbonAccount
+	var placed int
+	for _, pod := range state.Pods {
+		node, ok := nodes[pod.Spec.NodeName]
+		if !ok {
+			continue
 		}
+		account := AccountCarbon(powerWatts, ProfileForNode(state.EnergyProfiles, node))
+		total.RenewableShare += account.RenewableShare
+		total.FacilityPower += account.FacilityPower
+		total.CarbonFootprint += account.CarbonFootprint
+		placed++
+	}
+	if placed == 0 {
+		account := AccountCarbon(powerWatts, nil)
+		return CarbonAccount{FacilityPower: account.FacilityPower, CarbonFootprint: account.CarbonFootprint}
 	}
-	if score < 0 {
-		score = 0
+	return CarbonAccount{
+		RenewableShare:  total.RenewableShare / float64(placed),
+		FacilityPower:   total.FacilityPower / float64(placed),
+		CarbonFootprint: total.CarbonFootprint / float64(placed),
 	}
-	return math.Round(score*100) / 100
 }
 
 func (e *Engine) parseCPU(cpu string) float64 {
@@ -0,0 +1,350 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// spotPriceFactor is the share of the on-demand price a spot node costs
+const spotPriceFactor = 0.7
+
+// clusterEstimate is the cost and power of a workload on the nodes it runs or is reserved on
+type clusterEstimate struct {
+	Cost  float64
+	Power float64
+	// GPUCost is the part of Cost paid for GPUs
+	GPUCost float64
+	// Pods are the costs of the running pods that make up Cost
+	Pods []PodCost
+	// Node is where most of the workload's pods run, or its reserved node
+	Node string
+}
+
+// withPolicyPricing returns the engine with the per-resource prices of the cost policy
+// covering the workload in place of the defaults or the pricing table's. Other custom pricing
+// providers are kept.
+func (e *Engine) withPolicyPricing(policy *kcloudv1alpha1.CostPolicy) *Engine {
+	if policy == nil || policy.Spec.ResourceCostPolicy == nil {
+		return e
+	}
+	engine := *e
+	switch pricing := e.CostCalculator.(type) {
+	case *CostCalculator:
+		priced := *pricing
+		applyResourceRates(&priced, policy.Spec.ResourceCostPolicy)
+		engine.CostCalculator = &priced
+	case *TablePricing:
+		engine.CostCalculator = pricing.withResourceRates(policy.Spec.ResourceCostPolicy)
+	default:
+		return e
+	}
+	return &engine
+}
+
+// applyResourceRates replaces the calculator's rates with those the prices set
+func applyResourceRates(calculator *CostCalculator, prices *kcloudv1alpha1.ResourceCostPolicy) {
+	for _, price := range []struct {
+		policy *float64
+		rate   *float64
+	}{
+		{prices.CPUCostPerCorePerHour, &calculator.CPUCostPerCorePerHour},
+		{prices.MemoryCostPerGiPerHour, &calculator.MemoryCostPerGBPerHour},
+		{prices.GPUCostPerHour, &calculator.GPUCostPerHour},
+		{prices.NPUCostPerHour, &calculator.NPUCostPerHour},
+	} {
+		if price.policy != nil {
+			*price.rate = *price.policy
+		}
+	}
+}
+
+// estimateRunning prices the workload's running pods from their own requests on the nodes
+// they run on. It reports false when no pod is running.
+func (e *Engine) estimateRunning(state *WorkloadState) (clusterEstimate, bool) {
+	wo := state.WorkloadOptimizer
+	nodes := make(map[string]*corev1.Node, len(state.AvailableNodes))
+	for i := range state.AvailableNodes {
+		nodes[state.AvailableNodes[i].Name] = &state.AvailableNodes[i]
+	}
+
+	var estimate clusterEstimate
+	podsPerNode := make(map[string]int)
+	for i := range state.Pods {
+		pod := &state.Pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		cpuCores, memoryGB, gpus, npus := PodResources(pod)
+		node := nodes[pod.Spec.NodeName]
+		cost := e.CostCalculator.CalculateCost(cpuCores, memoryGB, gpus, npus) + e.Accelerators.Cost(wo.Spec.Resources.Accelerators)
+		power := e.PowerCalculator.CalculatePower(cpuCores, memoryGB, gpus, npus) + e.Accelerators.Power(wo.Spec.Resources.Accelerators)
+		cost, power = nodeAdjusted(wo, node, e.costOnNode(wo, node, cpuCores, memoryGB, gpus, cost), power)
+		estimate.Cost += cost
+		estimate.Power += power
+		estimate.GPUCost += e.gpuCostOnNode(wo, node, cpuCores, memoryGB, gpus)
+		estimate.Pods = append(estimate.Pods, PodCost{Name: pod.Name, Node: pod.Spec.NodeName, Cost: cost})
+
+		podsPerNode[pod.Spec.NodeName]++
+		if count := podsPerNode[pod.Spec.NodeName]; count > podsPerNode[estimate.Node] ||
+			(count == podsPerNode[estimate.Node] && pod.Spec.NodeName < estimate.Node) {
+			estimate.Node = pod.Spec.NodeName
+		}
+	}
+	return estimate, len(podsPerNode) > 0
+}
+
+// realizedRunning returns what the workload's running pods were charged, and whether the
+// realized cost source knows every one of them
+func (e *Engine) realizedRunning(state *WorkloadState) (RealizedCost, bool) {
+	if e.Realized == nil {
+		return RealizedCost{}, false
+	}
+	var total RealizedCost
+	found := false
+	for i := range state.Pods {
+		pod := &state.Pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		cost, ok := e.Realized.PodCost(pod.Namespace, pod.Name)
+		if !ok {
+			return RealizedCost{}, false
+		}
+		total.Compute += cost.Compute
+		total.GPU += cost.GPU
+		total.Storage += cost.Storage
+		total.Network += cost.Network
+		total.Total += cost.Total
+		found = true
+	}
+	return total, found
+}
+
+// measuredRunning returns the power the workload's running pods were measured drawing, and
+// whether every one of them was measured, on its own or as a share of its node
+func (e *Engine) measuredRunning(state *WorkloadState) (float64, bool) {
+	if e.Power == nil && e.GPU == nil && e.NodePower == nil {
+		return 0, false
+	}
+	var total float64
+	found := false
+	for i := range state.Pods {
+		pod := &state.Pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		watts, ok := e.measuredPod(state, pod)
+		if !ok {
+			return 0, false
+		}
+		total += watts
+		found = true
+	}
+	return total, found
+}
+
+// measuredPod returns the power the pod was measured drawing. Without a measurement of the
+// whole pod, its measured GPU power is added to the estimated power of the rest of its
+// requests, and without that the pod is given its share of its node's measured power.
+func (e *Engine) measuredPod(state *WorkloadState, pod *corev1.Pod) (float64, bool) {
+	if e.Power != nil {
+		if watts, ok := e.Power.PodPower(pod.Namespace, pod.Name); ok {
+			return watts, true
+		}
+	}
+	if e.GPU != nil {
+		if usage, ok := e.GPU.PodGPU(pod.Namespace, pod.Name); ok {
+			cpuCores, memoryGB, _, npus := PodResources(pod)
+			return usage.Power + e.PowerCalculator.CalculatePower(cpuCores, memoryGB, 0, npus), true
+		}
+	}
+	if e.AttributedPower != nil {
+		if watts, ok := e.AttributedPower.PodPower(pod.Namespace, pod.Name); ok {
+			return watts, true
+		}
+	}
+	return podPowerShare(e.NodePower, pod, findNode(state.AvailableNodes, pod.Spec.NodeName))
+}
+
+// measuredGPU returns the measured usage of the GPUs of the workload's running pods, summing
+// power and memory and taking the busiest and fullest GPU, or nil when none was measured
+func (e *Engine) measuredGPU(state *WorkloadState) *GPUUsage {
+	if e.GPU == nil {
+		return nil
+	}
+	var total *GPUUsage
+	for i := range state.Pods {
+		pod := &state.Pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		usage, ok := e.GPU.PodGPU(pod.Namespace, pod.Name)
+		if !ok {
+			continue
+		}
+		if total == nil {
+			total = &GPUUsage{}
+		}
+		total.Power += usage.Power
+		total.MemoryGB += usage.MemoryGB
+		total.Utilization = math.Max(total.Utilization, usage.Utilization)
+		total.MemoryShare = math.Max(total.MemoryShare, usage.MemoryShare)
+	}
+	return total
+}
+
+// reservedNode returns the available node the scheduler reserved for the workload, or nil
+func reservedNode(state *WorkloadState) *corev1.Node {
+	return findNode(state.AvailableNodes, state.ReservedNode)
+}
+
+// findNode returns the named node, nil when the name is empty or the node is not listed
+func findNode(nodes []corev1.Node, name string) *corev1.Node {
+	if name == "" {
+		return nil
+	}
+	for i := range nodes {
+		if nodes[i].Name == name {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+// costOnNode returns a replica's cost on the node, nil when unknown, from its cost at the
+// calculator's rates. A node with a known price charges the replica its share of that price;
+// otherwise the rates, with GPUs at the rate of the node's model where priced, are scaled by
+// the node's cost tier.
+func (e *Engine) costOnNode(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node,
+	cpuCores, memoryGB float64, gpus int32, rateCost float64) float64 {
+	if shared, ok := ReplicaNodeCost(e.NodePricing, node, cpuCores, memoryGB, gpus); ok {
+		return shared + e.Accelerators.Cost(wo.Spec.Resources.Accelerators)
+	}
+	if node == nil {
+		return rateCost
+	}
+	if models, ok := e.CostCalculator.(GPUModelPricing); ok {
+		// GPUs are priced at the rate of the node's model
+		rateCost += models.GPUModelAdjustment(node, gpus)
+	}
+	return rateCost * nodeCostMultiplier(node)
+}
+
+// gpuCostOnNode returns the part of a replica's cost on the node paid for its GPUs, at the
+// rate of the node's GPU model where priced. It is zero on nodes priced whole, whose price
+// does not separate GPUs.
+func (e *Engine) gpuCostOnNode(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node,
+	cpuCores, memoryGB float64, gpus int32) float64 {
+	if gpus == 0 {
+		return 0
+	}
+	if _, ok := ReplicaNodeCost(e.NodePricing, node, cpuCores, memoryGB, gpus); ok {
+		return 0
+	}
+	rate, ok := gpuRate(e.CostCalculator)
+	if !ok {
+		return 0
+	}
+	cost := float64(gpus) * rate
+	if node != nil {
+		if models, ok := e.CostCalculator.(GPUModelPricing); ok {
+			cost += models.GPUModelAdjustment(node, gpus)
+		}
+		cost *= nodeCostMultiplier(node)
+	}
+	cost, _ = nodeAdjusted(wo, node, cost, 0)
+	return cost
+}
+
+// gpuRate returns the pricing's rate of one GPU per hour, false for pricing that does not
+// expose its rates
+func gpuRate(pricing PricingProvider) (float64, bool) {
+	switch pricing := pricing.(type) {
+	case *CostCalculator:
+		return pricing.GPUCostPerHour, true
+	case *TablePricing:
+		return pricing.rates.Load().calculator.GPUCostPerHour, true
+	default:
+		return 0, false
+	}
+}
+
+// nodeAdjusted scales a replica's cost and power to the node it runs on: its spot pricing,
+// its power efficiency, and renewable supply for workloads that prefer it.
+// Without a known node the workload's spot and green preferences are assumed to be met.
+func nodeAdjusted(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, cost, power float64) (float64, float64) {
+	preferSpot := wo.Spec.CostConstraints != nil && wo.Spec.CostConstraints.PreferSpot
+	preferGreen := wo.Spec.PowerConstraints != nil && wo.Spec.PowerConstraints.PreferGreen
+	if node == nil {
+		if preferSpot {
+			cost *= spotPriceFactor
+		}
+		if preferGreen {
+			power *= 0.9
+		}
+		return cost, power
+	}
+
+	if node.Labels["lifecycle"] == "spot" {
+		cost *= spotPriceFactor
+	}
+	power *= nodePowerMultiplier(node)
+	if preferGreen && node.Labels["energy-source"] == "renewable" {
+		power *= 0.9
+	}
+	return cost, power
+}
+
+// SpotSavings returns what a workload's compute cost per hour on the node saves over the
+// same capacity on demand; nothing off spot nodes
+func SpotSavings(node *corev1.Node, compute float64) float64 {
+	if node == nil || node.Labels["lifecycle"] != "spot" {
+		return 0
+	}
+	return compute/spotPriceFactor - compute
+}
+
+// PodResources returns a pod's effective CPU cores, memory in GiB, GPUs and NPUs: the sum of
+// its containers, or its largest init container where that is higher
+func PodResources(pod *corev1.Pod) (float64, float64, int32, int32) {
+	total := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			sum := total[name]
+			sum.Add(quantity)
+			total[name] = sum
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current := total[name]; quantity.Cmp(current) > 0 {
+				total[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	cpu := total[corev1.ResourceCPU]
+	memory := total[corev1.ResourceMemory]
+	gpus := total["nvidia.com/gpu"]
+	npus := total["npu.com/npu"]
+	return cpu.AsApproximateFloat64(), memory.AsApproximateFloat64() / (1 << 30), int32(gpus.Value()), int32(npus.Value())
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// FragmentationResources are the resources whose fragmentation is measured
+var FragmentationResources = []corev1.ResourceName{"nvidia.com/gpu", corev1.ResourceCPU}
+
+// PodUsage is a pod's resource requests on its node
+type PodUsage struct {
+	Namespace string
+	Name      string
+	Requests  corev1.ResourceList
+	// Movable is false for pods a migration must not touch, such as DaemonSet pods
+	Movable bool
+	// DaemonSet pods run on every node, so draining a node removes them rather than moving them
+	DaemonSet bool
+	// Created is when the pod was created
+	Created time.Time
+}
+
+// NodeUsage is a node's allocatable capacity and the pods requesting part of it
+type NodeUsage struct {
+	Name        string
+	Allocatable corev1.ResourceList
+	Pods        []PodUsage
+}
+
+// Fragmentation measures how much free capacity of a resource the typical request shape
+// cannot use
+type Fragmentation struct {
+	Resource corev1.ResourceName `json:"resource"`
+	// Shape is the most common request of the resource among running pods
+	Shape resource.Quantity `json:"shape"`
+	// Free is the unrequested capacity across nodes
+	Free resource.Quantity `json:"free"`
+	// Unusable is the part of Free left over on each node after packing it with Shape
+	Unusable resource.Quantity `json:"unusable"`
+	// Score is Unusable as a fraction of Free (0.0-1.0)
+	Score float64 `json:"score"`
+}
+
+// PlacementFilter reports whether a pod may run on the named node beyond having room for
+// its requests, e.g. whether the node is schedulable and matches the pod's tolerations and
+// selectors. A nil filter admits every node.
+type PlacementFilter func(pod PodUsage, node string) bool
+
+// Migration moves a pod to another node
+type Migration struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	FromNode  string `json:"fromNode"`
+	ToNode    string `json:"toNode"`
+}
+
+// DefragmentationPlan is a set of migrations that reduces fragmentation
+type DefragmentationPlan struct {
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Before      []Fragmentation `json:"before"`
+	After       []Fragmentation `json:"after"`
+	Migrations  []Migration     `json:"migrations"`
+}
+
+// Recommendation summarizes the plan for operators
+func (p *DefragmentationPlan) Recommendation() string {
+	if len(p.Migrations) == 0 {
+		return "No migration reduces fragmentation"
+	}
+	summary := fmt.Sprintf("Apply %d pod migrations to reduce fragmentation", len(p.Migrations))
+	for i, before := range p.Before {
+		summary += fmt.Sprintf("; %s %.0f%% to %.0f%%", before.Resource, before.Score*100, p.After[i].Score*100)
+	}
+	return summary
+}
+
+// fragmentationState tracks free capacity in milli-units while migrations are simulated
+type fragmentationState struct {
+	nodes     []NodeUsage
+	placeable PlacementFilter
+	free      []map[corev1.ResourceName]int64
+	shapes    map[corev1.ResourceName]int64
+}
+
+// newFragmentationState computes the free capacity of each node and the typical shapes
+func newFragmentationState(nodes []NodeUsage, placeable PlacementFilter) *fragmentationState {
+	s := &fragmentationState{
+		nodes:     nodes,
+		placeable: placeable,
+		free:      make([]map[corev1.ResourceName]int64, len(nodes)),
+		shapes:    make(map[corev1.ResourceName]int64),
+	}
+	counts := make(map[corev1.ResourceName]map[int64]int)
+	for i, node := range nodes {
+		s.free[i] = make(map[corev1.ResourceName]int64, len(node.Allocatable))
+		for name, quantity := range node.Allocatable {
+			s.free[i][name] = quantity.MilliValue()
+		}
+		for _, pod := range node.Pods {
+			for name, quantity := range pod.Requests {
+				s.free[i][name] -= quantity.MilliValue()
+				if quantity.IsZero() {
+					continue
+				}
+				if counts[name] == nil {
+					counts[name] = make(map[int64]int)
+				}
+				counts[name][quantity.MilliValue()]++
+			}
+		}
+	}
+
+	// The most common request wins; ties go to the larger request
+	for _, name := range FragmentationResources {
+		best, bestCount := int64(0), 0
+		for shape, count := range counts[name] {
+			if count > bestCount || (count == bestCount && shape > best) {
+				best, bestCount = shape, count
+			}
+		}
+		if best > 0 {
+			s.shapes[name] = best
+		}
+	}
+	return s
+}
+
+// unusable returns the free capacity of a resource on a node that its shape cannot use
+func (s *fragmentationState) unusable(i int, name corev1.ResourceName) int64 {
+	shape := s.shapes[name]
+	free := s.free[i][name]
+	if shape == 0 || free <= 0 {
+		return 0
+	}
+	return free % shape
+}
+
+// measure reports the fragmentation of every measured resource
+func (s *fragmentationState) measure() []Fragmentation {
+	result := make([]Fragmentation, 0, len(FragmentationResources))
+	for _, name := range FragmentationResources {
+		var free, unusable int64
+		for i := range s.nodes {
+			free += max(s.free[i][name], 0)
+			unusable += s.unusable(i, name)
+		}
+		f := Fragmentation{
+			Resource: name,
+			Shape:    *resource.NewMilliQuantity(s.shapes[name], resource.DecimalSI),
+			Free:     *resource.NewMilliQuantity(free, resource.DecimalSI),
+			Unusable: *resource.NewMilliQuantity(unusable, resource.DecimalSI),
+		}
+		if free > 0 {
+			f.Score = float64(unusable) / float64(free)
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// totalFree returns the free capacity of a resource across nodes; migrations do not change it
+func (s *fragmentationState) totalFree(name corev1.ResourceName) int64 {
+	var free int64
+	for i := range s.nodes {
+		free += max(s.free[i][name], 0)
+	}
+	return free
+}
+
+// fits reports whether the pod may run on node i and it has room for the pod's requests
+func (s *fragmentationState) fits(i int, pod PodUsage) bool {
+	for name, quantity := range pod.Requests {
+		if s.free[i][name] < quantity.MilliValue() {
+			return false
+		}
+	}
+	return s.placeable == nil || s.placeable(pod, s.nodes[i].Name)
+}
+
+// move shifts requests from node from to node to
+func (s *fragmentationState) move(from, to int, requests corev1.ResourceList) {
+	for name, quantity := range requests {
+		s.free[from][name] += quantity.MilliValue()
+		s.free[to][name] -= quantity.MilliValue()
+	}
+}
+
+// gain returns how much moving requests from one node to another lowers the summed
+// fragmentation scores
+func (s *fragmentationState) gain(from, to int, requests corev1.ResourceList, totals map[corev1.ResourceName]int64) float64 {
+	var gain float64
+	for _, name := range FragmentationResources {
+		if totals[name] == 0 {
+			continue
+		}
+		before := s.unusable(from, name) + s.unusable(to, name)
+		s.move(from, to, requests)
+		after := s.unusable(from, name) + s.unusable(to, name)
+		s.move(to, from, requests)
+		gain += float64(before-after) / float64(totals[name])
+	}
+	return gain
+}
+
+// MeasureFragmentation reports how much free capacity of each resource in
+// FragmentationResources the most common request shape cannot use
+func MeasureFragmentation(nodes []NodeUsage) []Fragmentation {
+	return newFragmentationState(nodes, nil).measure()
+}
+
+// PlanDefragmentation proposes up to maxMigrations pod moves, one at a time, each the
+// move of a movable pod that lowers fragmentation the most. Moves only go where the pod
+// fits and placeable admits it, and planning stops when no move helps. Migrations are
+// listed in the order they must be applied, since later moves may use capacity earlier
+// ones free.
+func PlanDefragmentation(nodes []NodeUsage, maxMigrations int, placeable PlacementFilter) *DefragmentationPlan {
+	s := newFragmentationState(nodes, placeable)
+	plan := &DefragmentationPlan{Before: s.measure()}
+
+	totals := make(map[corev1.ResourceName]int64, len(FragmentationResources))
+	for _, name := range FragmentationResources {
+		totals[name] = s.totalFree(name)
+	}
+
+	// Pods already planned keep their first move so the plan never chains migrations
+	type podRef struct{ node, pod int }
+	moved := make(map[podRef]bool)
+
+	for len(plan.Migrations) < maxMigrations {
+		best, bestGain := podRef{}, 0.0
+		bestTarget := -1
+		for from := range s.nodes {
+			for p, pod := range s.nodes[from].Pods {
+				ref := podRef{node: from, pod: p}
+				if !pod.Movable || moved[ref] {
+					continue
+				}
+				for to := range s.nodes {
+					if to == from || !s.fits(to, pod) {
+						continue
+					}
+					if gain := s.gain(from, to, pod.Requests, totals); gain > bestGain+1e-9 {
+						best, bestGain, bestTarget = ref, gain, to
+					}
+				}
+			}
+		}
+		if bestTarget < 0 {
+			break
+		}
+
+		pod := s.nodes[best.node].Pods[best.pod]
+		s.move(best.node, bestTarget, pod.Requests)
+		moved[best] = true
+		plan.Migrations = append(plan.Migrations, Migration{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			FromNode:  s.nodes[best.node].Name,
+			ToNode:    s.nodes[bestTarget].Name,
+		})
+	}
+
+	plan.After = s.measure()
+	return plan
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultGCPPriceListURL lists the SKUs of Compute Engine in the Cloud Billing Catalog API
+	DefaultGCPPriceListURL = "https://cloudbilling.googleapis.com/v1/services/6F81-5844-456A/skus"
+
+	// gcpAcceleratorLabel names the GPU type attached to a GKE node
+	gcpAcceleratorLabel = "cloud.google.com/gke-accelerator"
+	// gcpCatalogReuse is how long a fetched catalog prices further regions before it is
+	// fetched again; the catalog lists every region at once
+	gcpCatalogReuse = time.Hour
+	// gcpPageSize is the number of SKUs requested per catalog page
+	gcpPageSize = 5000
+)
+
+// gcpSustainedUseFactors approximate the sustained use discount of each machine family as the
+// share of the list price paid by a node running the whole month
+var gcpSustainedUseFactors = map[string]float64{
+	"n1":  0.7,
+	"n2":  0.8,
+	"n2d": 0.8,
+	"c2":  0.8,
+	"m1":  0.8,
+	"m2":  0.8,
+}
+
+// gcpAMDFamilies are the machine families whose SKUs are described as AMD instances
+var gcpAMDFamilies = []string{"n2d", "c2d", "t2d"}
+
+// gcpAcceleratorSKUs maps GKE accelerator label values to the name of their GPU SKU where it
+// does not follow from the label
+var gcpAcceleratorSKUs = map[string]string{
+	"nvidia-tesla-a100":     "Nvidia Tesla A100 GPU",
+	"nvidia-a100-80gb":      "Nvidia Tesla A100 80GB GPU",
+	"nvidia-h100-80gb":      "Nvidia H100 80GB GPU",
+	"nvidia-h100-mega-80gb": "Nvidia H100 80GB Mega GPU",
+}
+
+// GCPPriceList reads Compute Engine on-demand prices from the Cloud Billing Catalog API.
+// Machines are priced per vCPU and GiB of memory of their family and per attached GPU, so a
+// node is priced from its capacity, its machine type and its GKE accelerator label.
+type GCPPriceList struct {
+	// SustainedUse applies the sustained use discount of a node running the whole month to
+	// on-demand nodes of the families that earn it
+	SustainedUse bool
+
+	url    string
+	apiKey string
+	client *http.Client
+
+	mu        sync.Mutex
+	catalog   []gcpSKU
+	fetchedAt time.Time
+}
+
+// NewGCPPriceList returns a price list source reading the catalog at catalogURL, empty for
+// DefaultGCPPriceListURL, with the API key
+func NewGCPPriceList(catalogURL, apiKey string) (*GCPPriceList, error) {
+	if catalogURL == "" {
+		catalogURL = DefaultGCPPriceListURL
+	}
+	parsed, err := url.Parse(catalogURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid gcp price list url %q", catalogURL)
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("gcp price list requires an api key")
+	}
+	return &GCPPriceList{
+		SustainedUse: true,
+		url:          catalogURL,
+		apiKey:       apiKey,
+		client:       &http.Client{Timeout: defaultPriceListTimeout},
+	}, nil
+}
+
+// gcpSKU is the subset of a catalog SKU the price list reads
+type gcpSKU struct {
+	Description string `json:"description"`
+	Category    struct {
+		UsageType string `json:"usageType"`
+	} `json:"category"`
+	ServiceRegions []string `json:"serviceRegions"`
+	PricingInfo    []struct {
+		PricingExpression struct {
+			TieredRates []struct {
+				StartUsageAmount float64 `json:"startUsageAmount"`
+				UnitPrice        struct {
+					CurrencyCode string `json:"currencyCode"`
+					Units        string `json:"units"`
+					Nanos        int64  `json:"nanos"`
+				} `json:"unitPrice"`
+			} `json:"tieredRates"`
+		} `json:"pricingExpression"`
+	} `json:"pricingInfo"`
+}
+
+// gcpSKUPage is a page of the catalog's SKU list
+type gcpSKUPage struct {
+	SKUs          []gcpSKU `json:"skus"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+// RegionPricing prices nodes from the on-demand and spot vCPU, memory and GPU rates of the region
+func (g *GCPPriceList) RegionPricing(ctx context.Context, region string) (NodePricing, error) {
+	catalog, err := g.fetchCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pricing := gcpRegionPricing{
+		rates:        make(map[string]float64),
+		spotRates:    make(map[string]float64),
+		sustainedUse: g.SustainedUse,
+	}
+	for _, sku := range catalog {
+		name, _, found := strings.Cut(sku.Description, " running in ")
+		if !found || !slices.Contains(sku.ServiceRegions, region) {
+			continue
+		}
+		rate, ok := sku.hourlyRate()
+		if !ok {
+			continue
+		}
+		switch sku.Category.UsageType {
+		case "OnDemand":
+			pricing.rates[name] = rate
+		case "Preemptible":
+			pricing.spotRates[gcpSpotSKUName(name)] = rate
+		}
+	}
+	if len(pricing.rates) == 0 {
+		return nil, fmt.Errorf("gcp price list for region %s has no on-demand rates", region)
+	}
+	return pricing, nil
+}
+
+// hourlyRate returns the SKU's first tier USD price per unit
+func (s gcpSKU) hourlyRate() (float64, bool) {
+	if len(s.PricingInfo) == 0 {
+		return 0, false
+	}
+	for _, tier := range s.PricingInfo[0].PricingExpression.TieredRates {
+		if tier.StartUsageAmount != 0 || tier.UnitPrice.CurrencyCode != "USD" {
+			continue
+		}
+		units, err := strconv.ParseInt(cmp.Or(tier.UnitPrice.Units, "0"), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return float64(units) + float64(tier.UnitPrice.Nanos)/1e9, true
+	}
+	return 0, false
+}
+
+// fetchCatalog returns every Compute Engine SKU, reusing a recently fetched catalog
+func (g *GCPPriceList) fetchCatalog(ctx context.Context) ([]gcpSKU, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.catalog != nil && time.Since(g.fetchedAt) < gcpCatalogReuse {
+		return g.catalog, nil
+	}
+
+	var catalog []gcpSKU
+	pageToken := ""
+	for {
+		query := url.Values{"currencyCode": {"USD"}, "pageSize": {strconv.Itoa(gcpPageSize)}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.url+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build gcp price list request: %w", err)
+		}
+		// The key goes in a header so it stays out of URLs that errors and proxies log
+		req.Header.Set("X-Goog-Api-Key", g.apiKey)
+		page, err := g.fetchPage(req)
+		if err != nil {
+			return nil, err
+		}
+		catalog = append(catalog, page.SKUs...)
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	g.catalog, g.fetchedAt = catalog, time.Now()
+	return catalog, nil
+}
+
+// fetchPage fetches and decodes one page of SKUs
+func (g *GCPPriceList) fetchPage(req *http.Request) (*gcpSKUPage, error) {
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch gcp price list: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gcp price list returned status %d", resp.StatusCode)
+	}
+	var page gcpSKUPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode gcp price list: %w", err)
+	}
+	return &page, nil
+}
+
+// gcpRegionPricing prices nodes from a region's rates, keyed by SKU name such as
+// "N2 Instance Core" or "Nvidia Tesla T4 GPU"
+type gcpRegionPricing struct {
+	rates map[string]float64
+	// spotRates are the rates of spot and preemptible VMs, keyed by the on-demand SKU name
+	spotRates    map[string]float64
+	sustainedUse bool
+}
+
+// NodePrice returns the node's vCPUs, memory and GPUs at the rates of its machine family and
+// accelerator type. Spot and preemptible nodes are priced at spot rates. Machine types
+// without known rates are not priced.
+func (p gcpRegionPricing) NodePrice(node *corev1.Node) (float64, bool) {
+	family, custom := gcpMachineFamily(NodeInstanceType(node))
+	if family == "" {
+		return 0, false
+	}
+	spot := node.Labels["cloud.google.com/gke-spot"] == "true" || node.Labels["cloud.google.com/gke-preemptible"] == "true"
+	rates := p.rates
+	if spot {
+		rates = p.spotRates
+	}
+	prefix := gcpSKUPrefix(family, custom)
+	coreRate, ok := rates[prefix+" Core"]
+	if !ok {
+		return 0, false
+	}
+	ramRate, ok := rates[prefix+" Ram"]
+	if !ok {
+		return 0, false
+	}
+	price := node.Status.Capacity.Cpu().AsApproximateFloat64()*coreRate +
+		node.Status.Capacity.Memory().AsApproximateFloat64()/(1<<30)*ramRate
+
+	gpus := node.Status.Capacity["nvidia.com/gpu"]
+	if count := gpus.AsApproximateFloat64(); count > 0 {
+		gpuRate, ok := rates[gcpAcceleratorSKU(node.Labels[gcpAcceleratorLabel])]
+		if !ok {
+			return 0, false
+		}
+		price += count * gpuRate
+	}
+
+	if factor, ok := gcpSustainedUseFactors[family]; ok && p.sustainedUse && !spot {
+		price *= factor
+	}
+	return price, true
+}
+
+// gcpSpotSKUName returns the on-demand SKU name of a preemptible SKU, such as
+// "N2 Instance Core" for "Spot Preemptible N2 Instance Core" and "Nvidia Tesla T4 GPU" for
+// "Nvidia Tesla T4 GPU attached to Spot Preemptible VMs"
+func gcpSpotSKUName(name string) string {
+	name = strings.TrimSuffix(name, " attached to Spot Preemptible VMs")
+	name = strings.TrimSuffix(name, " attached to Preemptible VMs")
+	if trimmed, ok := strings.CutPrefix(name, "Spot Preemptible "); ok {
+		return trimmed
+	}
+	return strings.TrimPrefix(name, "Preemptible ")
+}
+
+// gcpMachineFamily returns the family of a machine type such as n2-standard-8, and whether it
+// is a custom machine type
+func gcpMachineFamily(machineType string) (string, bool) {
+	parts := strings.Split(machineType, "-")
+	if len(parts) < 2 {
+		return "", false
+	}
+	if parts[0] == "custom" {
+		// Custom machine types without a family are N1
+		return "n1", true
+	}
+	return parts[0], parts[1] == "custom"
+}
+
+// gcpSKUPrefix returns how the catalog names the vCPU and memory SKUs of a machine family
+func gcpSKUPrefix(family string, custom bool) string {
+	switch {
+	case family == "n1" && custom:
+		return "Custom Instance"
+	case family == "n1":
+		return "N1 Predefined Instance"
+	case family == "c2":
+		return "Compute optimized"
+	case family == "m1":
+		return "Memory-optimized Instance"
+	}
+	prefix := strings.ToUpper(family)
+	if slices.Contains(gcpAMDFamilies, family) {
+		prefix += " AMD"
+	}
+	if custom {
+		prefix += " Custom"
+	}
+	return prefix + " Instance"
+}
+
+// gcpAcceleratorSKU returns the name of the GPU SKU of a GKE accelerator label value, such as
+// "Nvidia Tesla T4 GPU" for nvidia-tesla-t4
+func gcpAcceleratorSKU(accelerator string) string {
+	if name, ok := gcpAcceleratorSKUs[accelerator]; ok {
+		return name
+	}
+	words := strings.Split(accelerator, "-")
+	for i, word := range words {
+		if strings.ContainsAny(word, "0123456789") {
+			words[i] = strings.ToUpper(word)
+		} else if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ") + " GPU"
+}
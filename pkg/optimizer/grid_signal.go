@@ -0,0 +1,212 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultGridSignalInterval is how often the grid signal feed is read
+	DefaultGridSignalInterval = 15 * time.Minute
+
+	// gridSignalMaxAge is how long a point stays the current value when no later one has
+	// started, covering hourly forecasts and a missed read
+	gridSignalMaxAge = 2 * time.Hour
+	// defaultGridFeedTimeout bounds reading the grid signal feed
+	defaultGridFeedTimeout = 30 * time.Second
+)
+
+// GridSignalPoint is the grid's carbon intensity and electricity price from a point in time
+// until the next point, measured for past points and forecast for future ones
+type GridSignalPoint struct {
+	Time time.Time `json:"time"`
+	// CarbonIntensity is in g CO2 per kWh
+	CarbonIntensity *float64 `json:"carbonIntensity,omitempty"`
+	// Price is in USD per kWh
+	Price *float64 `json:"price,omitempty"`
+}
+
+// value returns the point's value of the signal, if it has one
+func (p GridSignalPoint) value(signal string) (float64, bool) {
+	value := p.CarbonIntensity
+	if signal == kcloudv1alpha1.TimeShiftSignalElectricityPrice {
+		value = p.Price
+	}
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
+
+// GridSignalSource reads the grid's carbon intensity and electricity price
+type GridSignalSource interface {
+	GridSignal(ctx context.Context) ([]GridSignalPoint, error)
+}
+
+// GridSignalFeed reads the grid signal as a JSON array of GridSignalPoint from an HTTP
+// endpoint, such as an exporter of a carbon intensity or day-ahead price API
+type GridSignalFeed struct {
+	url    string
+	client *http.Client
+}
+
+// NewGridSignalFeed returns a grid signal source reading the feed at feedURL
+func NewGridSignalFeed(feedURL string) (*GridSignalFeed, error) {
+	parsed, err := url.Parse(feedURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid grid signal feed url %q", feedURL)
+	}
+	return &GridSignalFeed{url: feedURL, client: &http.Client{Timeout: defaultGridFeedTimeout}}, nil
+}
+
+// GridSignal reads the feed
+func (f *GridSignalFeed) GridSignal(ctx context.Context) ([]GridSignalPoint, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build grid signal feed request: %w", err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read grid signal feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("grid signal feed returned status %d", resp.StatusCode)
+	}
+	var points []GridSignalPoint
+	if err := json.NewDecoder(resp.Body).Decode(&points); err != nil {
+		return nil, fmt.Errorf("failed to decode grid signal feed: %w", err)
+	}
+	return points, nil
+}
+
+// GridForecast keeps the grid signal read from a source every interval, and answers what it
+// is now and when it next drops to a threshold
+type GridForecast struct {
+	source   GridSignalSource
+	interval time.Duration
+	clock    clock.PassiveClock
+
+	mu     sync.RWMutex
+	points []GridSignalPoint
+}
+
+// NewGridForecast returns a forecast reading the source every interval
+func NewGridForecast(source GridSignalSource, interval time.Duration) *GridForecast {
+	if interval <= 0 {
+		interval = DefaultGridSignalInterval
+	}
+	return &GridForecast{source: source, interval: interval, clock: clock.RealClock{}}
+}
+
+// SetClock replaces the clock the current point is found with
+func (g *GridForecast) SetClock(c clock.PassiveClock) {
+	g.clock = c
+}
+
+// Observe replaces the forecast with the points
+func (g *GridForecast) Observe(points []GridSignalPoint) {
+	sorted := append([]GridSignalPoint(nil), points...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.points = sorted
+}
+
+// Current returns the signal's value from the latest point that has started, unless it is
+// more than two hours old
+func (g *GridForecast) Current(signal string) (float64, bool) {
+	now := g.clock.Now()
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for i := len(g.points) - 1; i >= 0; i-- {
+		point := g.points[i]
+		if point.Time.After(now) {
+			continue
+		}
+		value, ok := point.value(signal)
+		if !ok || now.Sub(point.Time) > gridSignalMaxAge {
+			return 0, false
+		}
+		return value, true
+	}
+	return 0, false
+}
+
+// NextAtOrBelow returns when the forecast next has the signal at or below the threshold
+func (g *GridForecast) NextAtOrBelow(signal string, threshold float64) (time.Time, bool) {
+	now := g.clock.Now()
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, point := range g.points {
+		if !point.Time.After(now) {
+			continue
+		}
+		if value, ok := point.value(signal); ok && value <= threshold {
+			return point.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Refresh reads the source
+func (g *GridForecast) Refresh(ctx context.Context) error {
+	points, err := g.source.GridSignal(ctx)
+	if err != nil {
+		return err
+	}
+	g.Observe(points)
+	return nil
+}
+
+// Start reads the source every interval until the context is cancelled; a failed read keeps
+// the previous forecast
+func (g *GridForecast) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("grid-signal")
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		if err := g.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read grid signal")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reads the feed on every replica, as every replica admits pods
+func (g *GridForecast) NeedLeaderElection() bool {
+	return false
+}
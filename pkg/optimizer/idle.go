@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// IdleActionAnnotation set to IdleActionScaleToZero on a WorkloadOptimizer lets the operator
+// scale its idle pods to zero instead of only recommending it
+const IdleActionAnnotation = "kcloud.io/idle-action"
+
+// IdleActionScaleToZero opts a workload into automatic scale-to-zero
+const IdleActionScaleToZero = "scale-to-zero"
+
+// Idle detection defaults
+const (
+	DefaultIdlePeriod        = 24 * time.Hour
+	DefaultIdleCPUThreshold  = 0.05
+	DefaultIdleGPUThreshold  = 0.05
+	DefaultIdleCheckInterval = 15 * time.Minute
+
+	// idleQuantile is the usage percentile that must stay under the thresholds, ignoring
+	// brief spikes such as health checks
+	idleQuantile = 0.99
+)
+
+// ScaleToZeroEnabled reports whether the workload opted into automatic scale-to-zero
+func ScaleToZeroEnabled(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	return wo.Annotations[IdleActionAnnotation] == IdleActionScaleToZero
+}
+
+// IdleReport is the outcome of checking a workload for idleness
+type IdleReport struct {
+	Idle bool
+	// Usage is the P99 usage of the busiest pod over the idle period
+	Usage UsagePercentile
+	// Reason explains why the workload is or is not idle
+	Reason string
+}
+
+// IdleDetector flags workloads whose pods stayed near zero utilization for a whole period
+type IdleDetector struct {
+	Source UsageSource
+	// Period is how long usage must stay under the thresholds
+	Period time.Duration
+	// CPUThreshold is the CPU usage in cores below which a pod is idle
+	CPUThreshold float64
+	// GPUThreshold is the GPU utilization in whole GPUs below which a pod is idle
+	GPUThreshold float64
+	// Interval is how often a workload is checked
+	Interval time.Duration
+
+	mu      sync.Mutex
+	clock   clock.PassiveClock
+	checked map[string]time.Time
+}
+
+// NewIdleDetector returns an idle detector with the default period, thresholds and interval
+func NewIdleDetector(source UsageSource) *IdleDetector {
+	return &IdleDetector{
+		Source:       source,
+		Period:       DefaultIdlePeriod,
+		CPUThreshold: DefaultIdleCPUThreshold,
+		GPUThreshold: DefaultIdleGPUThreshold,
+		Interval:     DefaultIdleCheckInterval,
+		clock:        clock.RealClock{},
+		checked:      make(map[string]time.Time),
+	}
+}
+
+// SetClock replaces the clock used to space out checks and age pods
+func (d *IdleDetector) SetClock(c clock.PassiveClock) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clock = c
+}
+
+// Due reports whether the workload should be checked now, and if so counts it as checked
+func (d *IdleDetector) Due(workloadID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	if last, ok := d.checked[workloadID]; ok && now.Sub(last) < d.Interval {
+		return false
+	}
+	d.checked[workloadID] = now
+	return true
+}
+
+// Forget drops the workload's check time, e.g. after it was deleted
+func (d *IdleDetector) Forget(workloadID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.checked, workloadID)
+}
+
+// Detect checks whether every pod of the workload stayed idle for the whole period. Pods
+// younger than the period have too little history, so their workload is never idle.
+func (d *IdleDetector) Detect(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	pods []corev1.Pod) (IdleReport, error) {
+	if len(pods) == 0 {
+		return IdleReport{Reason: "no running pods"}, nil
+	}
+
+	d.mu.Lock()
+	now := d.clock.Now()
+	d.mu.Unlock()
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		if age := now.Sub(pod.CreationTimestamp.Time); age < d.Period {
+			return IdleReport{Reason: fmt.Sprintf("pod %s is younger than the idle period of %s", pod.Name, d.Period)}, nil
+		}
+		names[i] = pod.Name
+	}
+
+	usage, err := d.Source.UsagePercentile(ctx, wo.Namespace, names, idleQuantile, d.Period)
+	if err != nil {
+		return IdleReport{}, err
+	}
+	report := IdleReport{Usage: usage}
+	switch {
+	case !usage.Found:
+		report.Reason = "no usage samples"
+	case usage.CPUCores >= d.CPUThreshold:
+		report.Reason = fmt.Sprintf("CPU usage %.3f cores reached the idle threshold of %.3f", usage.CPUCores, d.CPUThreshold)
+	case usage.GPUs >= d.GPUThreshold:
+		report.Reason = fmt.Sprintf("GPU utilization %.3f reached the idle threshold of %.3f", usage.GPUs, d.GPUThreshold)
+	default:
+		report.Idle = true
+		report.Reason = fmt.Sprintf("CPU usage stayed below %.3f cores and GPU utilization below %.3f for %s",
+			d.CPUThreshold, d.GPUThreshold, d.Period)
+	}
+	return report, nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// OptimizationIntervalOnce optimizes a workload once per spec change, e.g. for short-lived batch jobs
+	OptimizationIntervalOnce = "once"
+	// DefaultOptimizationInterval applies when neither the workload nor a policy sets one
+	DefaultOptimizationInterval = 5 * time.Minute
+	// MinOptimizationInterval keeps short intervals from turning into a busy loop
+	MinOptimizationInterval = 10 * time.Second
+)
+
+// OptimizationInterval is how often a workload is re-optimized
+type OptimizationInterval struct {
+	// Every is the time between optimizations; zero when Once is set
+	Every time.Duration
+	// Once optimizes the workload only when its spec changes
+	Once bool
+}
+
+// ParseOptimizationInterval parses "once" or a duration of at least MinOptimizationInterval
+func ParseOptimizationInterval(value string) (OptimizationInterval, error) {
+	if value == OptimizationIntervalOnce {
+		return OptimizationInterval{Once: true}, nil
+	}
+	every, err := time.ParseDuration(value)
+	if err != nil {
+		return OptimizationInterval{}, fmt.Errorf("invalid optimization interval %q: %w", value, err)
+	}
+	if every < MinOptimizationInterval {
+		return OptimizationInterval{}, fmt.Errorf("optimization interval %s is shorter than %s", every, MinOptimizationInterval)
+	}
+	return OptimizationInterval{Every: every}, nil
+}
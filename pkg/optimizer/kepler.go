@@ -0,0 +1,174 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultKeplerWindow is how far back measured power is averaged
+	DefaultKeplerWindow = 5 * time.Minute
+
+	// keplerRefresh is how often Kepler's energy counters are read, and keplerExpiry how long
+	// they are used while reads fail
+	keplerRefresh = time.Minute
+	keplerExpiry  = 5 * keplerRefresh
+)
+
+// MeasuredPowerSource reports the power pods and nodes were measured drawing
+type MeasuredPowerSource interface {
+	NodePowerSource
+	// PodPower returns the pod's average power in watts and whether it is known
+	PodPower(namespace, name string) (float64, bool)
+}
+
+// keplerSnapshot is the power of every pod and node Kepler measured, and when it was read
+type keplerSnapshot struct {
+	pods   map[types.NamespacedName]float64
+	nodes  map[string]float64
+	readAt time.Time
+}
+
+// Kepler reads measured power from the energy counters Kepler exports to Prometheus, averaged
+// over a trailing window. Node series must carry the node name in their instance label, as
+// Kepler's service monitor relabels it.
+type Kepler struct {
+	prometheus *PrometheusUsageSource
+	window     time.Duration
+	clock      clock.PassiveClock
+
+	snapshot atomic.Pointer[keplerSnapshot]
+}
+
+// NewKepler returns a measured power source reading Kepler's metrics from prometheus
+func NewKepler(prometheus *PrometheusUsageSource, window time.Duration) *Kepler {
+	if window <= 0 {
+		window = DefaultKeplerWindow
+	}
+	return &Kepler{
+		prometheus: prometheus,
+		window:     max(window, prometheusStepDuration),
+		clock:      clock.RealClock{},
+	}
+}
+
+// SetClock replaces the clock measurement ages are measured with
+func (k *Kepler) SetClock(c clock.PassiveClock) {
+	k.clock = c
+}
+
+// PodPower returns the pod's average power over the window from the last measurements read,
+// unless they have expired
+func (k *Kepler) PodPower(namespace, name string) (float64, bool) {
+	snapshot := k.current()
+	if snapshot == nil {
+		return 0, false
+	}
+	watts, ok := snapshot.pods[types.NamespacedName{Namespace: namespace, Name: name}]
+	return watts, ok
+}
+
+// NodePower returns the node's average power over the window from the last measurements
+// read, unless they have expired
+func (k *Kepler) NodePower(node string) (float64, bool) {
+	snapshot := k.current()
+	if snapshot == nil {
+		return 0, false
+	}
+	watts, ok := snapshot.nodes[node]
+	return watts, ok
+}
+
+// current returns the last measurements read, or nil once they have expired
+func (k *Kepler) current() *keplerSnapshot {
+	snapshot := k.snapshot.Load()
+	if snapshot == nil || k.clock.Since(snapshot.readAt) > keplerExpiry {
+		return nil
+	}
+	return snapshot
+}
+
+// Refresh reads the power of every pod and node over the window
+func (k *Kepler) Refresh(ctx context.Context) error {
+	rangeSelector := fmt.Sprintf("[%ds]", int64(k.window.Seconds()))
+
+	// Joules per second are watts
+	podSamples, err := k.prometheus.queryVector(ctx, fmt.Sprintf(
+		`sum by (container_namespace, pod_name) (rate(kepler_container_joules_total%s))`, rangeSelector))
+	if err != nil {
+		return fmt.Errorf("failed to query kepler pod power: %w", err)
+	}
+	pods := make(map[types.NamespacedName]float64, len(podSamples))
+	for _, sample := range podSamples {
+		if sample.labels["pod_name"] == "" {
+			continue
+		}
+		pods[types.NamespacedName{Namespace: sample.labels["container_namespace"], Name: sample.labels["pod_name"]}] = sample.value
+	}
+
+	// Platform power covers the whole node; nodes without a platform sensor report their
+	// CPU packages and memory
+	nodeSamples, err := k.prometheus.queryVector(ctx, fmt.Sprintf(
+		`sum by (instance) (rate(kepler_node_platform_joules_total%[1]s)) > 0 or `+
+			`sum by (instance) (rate(kepler_node_package_joules_total%[1]s)) + `+
+			`sum by (instance) (rate(kepler_node_dram_joules_total%[1]s))`, rangeSelector))
+	if err != nil {
+		return fmt.Errorf("failed to query kepler node power: %w", err)
+	}
+	nodes := make(map[string]float64, len(nodeSamples))
+	for _, sample := range nodeSamples {
+		if sample.labels["instance"] == "" {
+			continue
+		}
+		nodes[sample.labels["instance"]] = sample.value
+	}
+
+	k.snapshot.Store(&keplerSnapshot{pods: pods, nodes: nodes, readAt: k.clock.Now()})
+	return nil
+}
+
+// Start reads measurements every minute until the context is cancelled; a failed read keeps
+// the previous measurements until they expire
+func (k *Kepler) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("kepler")
+
+	ticker := time.NewTicker(keplerRefresh)
+	defer ticker.Stop()
+	for {
+		if err := k.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read Kepler power")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reads measurements on every replica, as every replica estimates power
+func (k *Kepler) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodePowerSource reports the power nodes were measured drawing
+type NodePowerSource interface {
+	// NodePower returns the node's average power in watts and whether it is known
+	NodePower(node string) (float64, bool)
+}
+
+// NodePowerChain reports each node's power from the first source that knows it
+type NodePowerChain []NodePowerSource
+
+// NodePower returns the node's power from the first source that knows it
+func (c NodePowerChain) NodePower(node string) (float64, bool) {
+	for _, source := range c {
+		if watts, ok := source.NodePower(node); ok {
+			return watts, true
+		}
+	}
+	return 0, false
+}
+
+// podPowerShare returns the share of its node's measured power a pod draws, in proportion to
+// the node's allocatable CPU it requests
+func podPowerShare(source NodePowerSource, pod *corev1.Pod, node *corev1.Node) (float64, bool) {
+	if source == nil || node == nil {
+		return 0, false
+	}
+	watts, ok := source.NodePower(node.Name)
+	if !ok {
+		return 0, false
+	}
+	cores := node.Status.Allocatable.Cpu().AsApproximateFloat64()
+	if cores <= 0 {
+		return 0, false
+	}
+	requested, _, _, _ := PodResources(pod)
+	return watts * math.Min(1, requested/cores), true
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultNodePricingRefresh is how long fetched instance prices are used before they are
+	// fetched again
+	DefaultNodePricingRefresh = 24 * time.Hour
+
+	// nodePricingPoll is how often the nodes are checked for regions without prices
+	nodePricingPoll = time.Minute
+	// nodePricingRetry is how long a region whose prices failed to fetch is left alone
+	nodePricingRetry = 10 * time.Minute
+)
+
+// NodePricingAWS, NodePricingGCP and NodePricingAzure name the cloud providers whose price
+// lists nodes can be priced from
+const (
+	NodePricingAWS   = "aws"
+	NodePricingGCP   = "gcp"
+	NodePricingAzure = "azure"
+)
+
+// providerIDClouds maps the scheme of a node's provider ID to the cloud that runs it
+var providerIDClouds = map[string]string{
+	"aws":   NodePricingAWS,
+	"gce":   NodePricingGCP,
+	"azure": NodePricingAzure,
+}
+
+// PriceListConfig configures the price list sources
+type PriceListConfig struct {
+	// AWSURL is the EC2 offer file template of AWSPriceList; empty uses DefaultAWSPriceListURL
+	AWSURL string
+	// GCPURL is the Compute Engine SKU list of GCPPriceList; empty uses DefaultGCPPriceListURL
+	GCPURL string
+	// GCPAPIKey authenticates GCPPriceList to the Cloud Billing Catalog API
+	GCPAPIKey string
+	// GCPSustainedUse approximates sustained use discounts in GCP prices
+	GCPSustainedUse bool
+	// AzureURL is the Retail Prices API of AzurePriceList; empty uses DefaultAzurePriceListURL
+	AzureURL string
+}
+
+// NewPriceListSource returns the price list source of the named cloud provider
+func NewPriceListSource(provider string, config PriceListConfig) (PriceListSource, error) {
+	switch provider {
+	case NodePricingAWS:
+		return NewAWSPriceList(config.AWSURL)
+	case NodePricingGCP:
+		source, err := NewGCPPriceList(config.GCPURL, config.GCPAPIKey)
+		if err != nil {
+			return nil, err
+		}
+		source.SustainedUse = config.GCPSustainedUse
+		return source, nil
+	case NodePricingAzure:
+		return NewAzurePriceList(config.AzureURL)
+	default:
+		return nil, fmt.Errorf("unknown node pricing provider %q", provider)
+	}
+}
+
+// NewPriceListSources returns the price list source of each cloud provider in a comma
+// separated list such as "aws,azure"
+func NewPriceListSources(providers string, config PriceListConfig) (map[string]PriceListSource, error) {
+	sources := make(map[string]PriceListSource)
+	for _, provider := range strings.Split(providers, ",") {
+		provider = strings.TrimSpace(provider)
+		if _, ok := sources[provider]; ok {
+			return nil, fmt.Errorf("node pricing provider %q listed twice", provider)
+		}
+		source, err := NewPriceListSource(provider, config)
+		if err != nil {
+			return nil, err
+		}
+		sources[provider] = source
+	}
+	return sources, nil
+}
+
+// NodePricing prices whole nodes, typically from a cloud provider's price list
+type NodePricing interface {
+	// NodePrice returns the node's hourly on-demand price in USD and whether it is known
+	NodePrice(node *corev1.Node) (float64, bool)
+}
+
+// ReplicaNodeCost charges a replica its dominant share of the node's hourly price: the
+// largest fraction of the node's allocatable CPU, memory or GPUs it requests. It reports
+// false when there is no node or its price is unknown.
+func ReplicaNodeCost(pricing NodePricing, node *corev1.Node, cpuCores, memoryGB float64, gpus int32) (float64, bool) {
+	if pricing == nil || node == nil {
+		return 0, false
+	}
+	price, ok := pricing.NodePrice(node)
+	if !ok {
+		return 0, false
+	}
+	gpuAllocatable := node.Status.Allocatable["nvidia.com/gpu"]
+	var share float64
+	for _, resource := range []struct {
+		requested   float64
+		allocatable float64
+	}{
+		{cpuCores, node.Status.Allocatable.Cpu().AsApproximateFloat64()},
+		{memoryGB, node.Status.Allocatable.Memory().AsApproximateFloat64() / (1 << 30)},
+		{float64(gpus), gpuAllocatable.AsApproximateFloat64()},
+	} {
+		if resource.requested > 0 && resource.allocatable > 0 {
+			share = math.Max(share, resource.requested/resource.allocatable)
+		}
+	}
+	return price * math.Min(share, 1), true
+}
+
+// NodeInstanceType returns the node's cloud instance type from its well-known label
+func NodeInstanceType(node *corev1.Node) string {
+	if instanceType := node.Labels[corev1.LabelInstanceTypeStable]; instanceType != "" {
+		return instanceType
+	}
+	return node.Labels[corev1.LabelInstanceType]
+}
+
+// NodeRegion returns the node's cloud region from its well-known label
+func NodeRegion(node *corev1.Node) string {
+	if region := node.Labels[corev1.LabelTopologyRegion]; region != "" {
+		return region
+	}
+	return node.Labels[corev1.LabelFailureDomainBetaRegion]
+}
+
+// NodeZone returns the node's cloud zone from its well-known label
+func NodeZone(node *corev1.Node) string {
+	if zone := node.Labels[corev1.LabelTopologyZone]; zone != "" {
+		return zone
+	}
+	return node.Labels[corev1.LabelFailureDomainBetaZone]
+}
+
+// PriceListSource fetches a cloud provider's on-demand prices
+type PriceListSource interface {
+	// RegionPricing returns the pricing of the nodes in the region
+	RegionPricing(ctx context.Context, region string) (NodePricing, error)
+}
+
+// InstancePrices prices nodes by the hourly price in USD of their instance type
+type InstancePrices map[string]float64
+
+// NodePrice returns the price of the node's instance type
+func (p InstancePrices) NodePrice(node *corev1.Node) (float64, bool) {
+	price, ok := p[NodeInstanceType(node)]
+	return price, ok
+}
+
+// cloudRegion is a region of a cloud provider
+type cloudRegion struct {
+	cloud  string
+	region string
+}
+
+// regionPrices are the prices of a region and when they were last fetched
+type regionPrices struct {
+	pricing   NodePricing
+	fetchedAt time.Time
+	failedAt  time.Time
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// CloudNodePricing prices nodes from the price list of their cloud and region.
+// Prices are fetched in the background for the regions the cluster's nodes run in and kept
+// until they are older than the refresh interval; a node is unpriced until its region's
+// prices have been fetched. With sources for several clouds, each node is priced by the
+// cloud its provider ID names.
+type CloudNodePricing struct {
+	// DefaultRegion prices nodes without a region label
+	DefaultRegion string
+
+	client  client.Reader
+	sources map[string]PriceListSource
+	refresh time.Duration
+	clock   clock.PassiveClock
+
+	mu      sync.RWMutex
+	regions map[cloudRegion]*regionPrices
+}
+
+// NewCloudNodePricing returns node pricing from the sources by cloud provider, fetching a
+// region's prices again after the refresh interval
+func NewCloudNodePricing(c client.Reader, sources map[string]PriceListSource, refresh time.Duration) *CloudNodePricing {
+	if refresh <= 0 {
+		refresh = DefaultNodePricingRefresh
+	}
+	return &CloudNodePricing{
+		client:  c,
+		sources: sources,
+		refresh: refresh,
+		clock:   clock.RealClock{},
+		regions: make(map[cloudRegion]*regionPrices),
+	}
+}
+
+// SetClock replaces the clock price ages are measured with
+func (p *CloudNodePricing) SetClock(c clock.PassiveClock) {
+	p.clock = c
+}
+
+// NodePrice returns the node's price from the last fetched prices of its region
+func (p *CloudNodePricing) NodePrice(node *corev1.Node) (float64, bool) {
+	if NodeInstanceType(node) == "" {
+		return 0, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	region, ok := p.regions[p.nodeRegion(node)]
+	if !ok || region.pricing == nil {
+		return 0, false
+	}
+	return region.pricing.NodePrice(node)
+}
+
+// nodeRegion returns the node's cloud and region, the default region for nodes without one.
+// The cloud is empty when no source prices it.
+func (p *CloudNodePricing) nodeRegion(node *corev1.Node) cloudRegion {
+	region := cmp.Or(NodeRegion(node), p.DefaultRegion)
+	if len(p.sources) == 1 {
+		// A single source prices every node, whatever its provider ID
+		for cloud := range p.sources {
+			return cloudRegion{cloud: cloud, region: region}
+		}
+	}
+	scheme, _, _ := strings.Cut(node.Spec.ProviderID, "://")
+	cloud := providerIDClouds[scheme]
+	if _, ok := p.sources[cloud]; !ok {
+		return cloudRegion{region: region}
+	}
+	return cloudRegion{cloud: cloud, region: region}
+}
+
+// Refresh fetches the prices of every region the nodes run in that has none yet or whose
+// prices are older than the refresh interval. Regions that fail keep their old prices.
+func (p *CloudNodePricing) Refresh(ctx context.Context) error {
+	var nodes corev1.NodeList
+	if err := p.client.List(ctx, &nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	wanted := make(map[cloudRegion]bool)
+	for i := range nodes.Items {
+		region := p.nodeRegion(&nodes.Items[i])
+		if region.cloud != "" && region.region != "" && NodeInstanceType(&nodes.Items[i]) != "" {
+			wanted[region] = true
+		}
+	}
+	regions := make([]cloudRegion, 0, len(wanted))
+	for region := range wanted {
+		regions = append(regions, region)
+	}
+	slices.SortFunc(regions, func(a, b cloudRegion) int {
+		return cmp.Or(cmp.Compare(a.cloud, b.cloud), cmp.Compare(a.region, b.region))
+	})
+
+	var errs []error
+	for _, region := range regions {
+		if !p.due(region) {
+			continue
+		}
+		pricing, err := p.sources[region.cloud].RegionPricing(ctx, region.region)
+		now := p.clock.Now()
+		p.mu.Lock()
+		current := p.regions[region]
+		if current == nil {
+			current = &regionPrices{}
+			p.regions[region] = current
+		}
+		if err != nil {
+			current.failedAt = now
+		} else {
+			current.pricing, current.fetchedAt = pricing, now
+		}
+		p.mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch %s prices for region %s: %w", region.cloud, region.region, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// due reports whether the region's prices should be fetched
+func (p *CloudNodePricing) due(region cloudRegion) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	current, ok := p.regions[region]
+	if !ok {
+		return true
+	}
+	now := p.clock.Now()
+	if now.Sub(current.failedAt) < nodePricingRetry {
+		return false
+	}
+	return current.pricing == nil || now.Sub(current.fetchedAt) >= p.refresh
+}
+
+// Start fetches prices for new and stale regions until the context is cancelled
+func (p *CloudNodePricing) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("node-pricing")
+
+	ticker := time.NewTicker(nodePricingPoll)
+	defer ticker.Stop()
+	for {
+		if err := p.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to refresh node prices")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection prices nodes on every replica, as every replica estimates costs
+func (p *CloudNodePricing) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultOpenCostWindow is how far back realized pod cost is averaged
+	DefaultOpenCostWindow = time.Hour
+
+	// openCostRefresh is how often OpenCost allocations are read, and openCostExpiry how long
+	// they are used while reads fail
+	openCostRefresh = 10 * time.Minute
+	openCostExpiry  = 3 * openCostRefresh
+
+	// defaultOpenCostTimeout bounds reading OpenCost allocations
+	defaultOpenCostTimeout = time.Minute
+)
+
+var costEstimateDiscrepancy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kcloud_cost_estimate_discrepancy_ratio",
+	Help: "Relative difference of a workload's estimated compute cost from its realized compute cost " +
+		"(positive when the estimate is high)",
+}, []string{"namespace", "name"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(costEstimateDiscrepancy)
+}
+
+// RealizedCost is the hourly cost in USD a pod was charged
+type RealizedCost struct {
+	// Compute is the cost of its CPU, memory and GPUs, comparable to estimates
+	Compute float64
+	// GPU is the part of Compute charged for GPUs
+	GPU float64
+	// Storage is the cost of its persistent volumes
+	Storage float64
+	// Network is the cost of its traffic
+	Network float64
+	// Total adds storage, network and load balancers
+	Total float64
+}
+
+// RealizedCostSource reports what running pods were charged
+type RealizedCostSource interface {
+	// PodCost returns the realized hourly cost of the pod and whether it is known
+	PodCost(namespace, name string) (RealizedCost, bool)
+}
+
+// recordCostDiscrepancy records how far the workload's estimated compute cost is from its
+// realized compute cost, both in USD
+func recordCostDiscrepancy(wo *kcloudv1alpha1.WorkloadOptimizer, estimated, realized float64) {
+	if realized > 0 {
+		costEstimateDiscrepancy.WithLabelValues(wo.Namespace, wo.Name).Set((estimated - realized) / realized)
+	}
+}
+
+// openCostAllocations is the body of an OpenCost allocation response with accumulated sets
+type openCostAllocations struct {
+	Code    int                             `json:"code"`
+	Message string                          `json:"message"`
+	Data    []map[string]openCostAllocation `json:"data"`
+}
+
+// openCostAllocation is the subset of an OpenCost pod allocation the source reads
+type openCostAllocation struct {
+	Properties struct {
+		Namespace string `json:"namespace"`
+		Pod       string `json:"pod"`
+	} `json:"properties"`
+	Minutes     float64 `json:"minutes"`
+	CPUCost     float64 `json:"cpuCost"`
+	GPUCost     float64 `json:"gpuCost"`
+	RAMCost     float64 `json:"ramCost"`
+	PVCost      float64 `json:"pvCost"`
+	NetworkCost float64 `json:"networkCost"`
+	TotalCost   float64 `json:"totalCost"`
+}
+
+// openCostSnapshot is the realized cost of every pod OpenCost allocated, and when it was read
+type openCostSnapshot struct {
+	pods   map[types.NamespacedName]RealizedCost
+	readAt time.Time
+}
+
+// OpenCost reads realized pod cost from the allocation API of an OpenCost deployment, averaged
+// over a trailing window. OpenCost must report in USD, the currency resources are priced in.
+type OpenCost struct {
+	url    string
+	window time.Duration
+	client *http.Client
+	clock  clock.PassiveClock
+
+	snapshot atomic.Pointer[openCostSnapshot]
+}
+
+// NewOpenCost returns a realized cost source for the OpenCost API at baseURL, such as
+// http://opencost.opencost:9003
+func NewOpenCost(baseURL string, window time.Duration) (*OpenCost, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid opencost url %q", baseURL)
+	}
+	if window <= 0 {
+		window = DefaultOpenCostWindow
+	}
+	return &OpenCost{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		window: window,
+		client: &http.Client{Timeout: defaultOpenCostTimeout},
+		clock:  clock.RealClock{},
+	}, nil
+}
+
+// SetClock replaces the clock allocation ages are measured with
+func (o *OpenCost) SetClock(c clock.PassiveClock) {
+	o.clock = c
+}
+
+// PodCost returns the pod's average hourly cost over the window from the last allocations
+// read, unless they have expired
+func (o *OpenCost) PodCost(namespace, name string) (RealizedCost, bool) {
+	snapshot := o.snapshot.Load()
+	if snapshot == nil || o.clock.Since(snapshot.readAt) > openCostExpiry {
+		return RealizedCost{}, false
+	}
+	cost, ok := snapshot.pods[types.NamespacedName{Namespace: namespace, Name: name}]
+	return cost, ok
+}
+
+// Refresh reads the allocations of every pod over the window
+func (o *OpenCost) Refresh(ctx context.Context) error {
+	query := url.Values{
+		"window":     {fmt.Sprintf("%dm", int64(o.window.Minutes()))},
+		"aggregate":  {"pod"},
+		"accumulate": {"true"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.url+"/allocation/compute?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build opencost request: %w", err)
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query opencost: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body openCostAllocations
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode opencost allocations (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || (body.Code != 0 && body.Code != http.StatusOK) {
+		return fmt.Errorf("opencost allocation query failed (status %d): %s", resp.StatusCode, body.Message)
+	}
+
+	pods := make(map[types.NamespacedName]RealizedCost)
+	for _, set := range body.Data {
+		for name, allocation := range set {
+			// Idle and unallocated costs belong to no pod
+			if strings.HasPrefix(name, "__") || allocation.Properties.Pod == "" || allocation.Minutes <= 0 {
+				continue
+			}
+			hours := allocation.Minutes / 60
+			key := types.NamespacedName{Namespace: allocation.Properties.Namespace, Name: allocation.Properties.Pod}
+			cost := pods[key]
+			cost.Compute += (allocation.CPUCost + allocation.GPUCost + allocation.RAMCost) / hours
+			cost.GPU += allocation.GPUCost / hours
+			cost.Storage += allocation.PVCost / hours
+			cost.Network += allocation.NetworkCost / hours
+			cost.Total += allocation.TotalCost / hours
+			pods[key] = cost
+		}
+	}
+	o.snapshot.Store(&openCostSnapshot{pods: pods, readAt: o.clock.Now()})
+	return nil
+}
+
+// Start reads allocations every few minutes until the context is cancelled; a failed read
+// keeps the previous allocations until they expire
+func (o *OpenCost) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("opencost")
+
+	ticker := time.NewTicker(openCostRefresh)
+	defer ticker.Stop()
+	for {
+		if err := o.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read OpenCost allocations")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reads allocations on every replica, as every replica estimates costs
+func (o *OpenCost) NeedLeaderElection() bool {
+	return false
+}
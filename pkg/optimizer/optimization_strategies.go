@@ -475,14 +475,14 @@ func (sm *StrategyManager) createNodeSelectionStrategy() OptimizationStrategy {
 				context.WorkloadOptimizer.Spec.Resources.GPU,
 				context.WorkloadOptimizer.Spec.Resources.NPU)
 
-			nodeCostMultiplier := sm.getNodeCostMultiplier(bestNode)
+			nodeCostMultiplier := nodeCostMultiplier(bestNode)
 			optimizedCost := baseCost * nodeCostMultiplier
 
 			basePower := context.PowerCalculator.CalculatePower(cpuCores, memoryGB,
 				context.WorkloadOptimizer.Spec.Resources.GPU,
 				context.WorkloadOptimizer.Spec.Resources.NPU)
 
-			nodePowerMultiplier := sm.getNodePowerMultiplier(bestNode)
+			nodePowerMultiplier := nodePowerMultiplier(bestNode)
 			optimizedPower := basePower * nodePowerMultiplier
 
 			log.V(1).Info("Node selection strategy evaluated",
@@ -518,7 +518,9 @@ func (sm *StrategyManager) createWorkloadMigrationStrategy() OptimizationStrateg
 		Description: "Optimize by migrating workload to more efficient nodes",
 		Priority:    0, // Lowest priority as it's disruptive
 		Applicable: func(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
-			return wo.Status.AssignedNode != nil && len(*wo.Status.AssignedNode) > 0 // Only if already assigned to a node
+			// Only if already assigned to a node and not holding a do-not-disturb lease
+			return wo.Status.AssignedNode != nil && len(*wo.Status.AssignedNode) > 0 &&
+				!DoNotDisturbActive(wo, time.Now())
 		},
 		Execute: func(ctx context.Context, context *OptimizationContext) (*StrategyOptimizationResult, error) {
 			log := log.FromContext(ctx)
@@ -594,7 +596,8 @@ func (sm *StrategyManager) calculateNodeScore(node corev1.Node, wo *kcloudv1alph
 	return math.Min(1.0, score)
 }
 
-func (sm *StrategyManager) getNodeCostMultiplier(node *corev1.Node) float64 {
+// nodeCostMultiplier scales cost by the node's cost-tier label
+func nodeCostMultiplier(node *corev1.Node) float64 {
 	if costLabel, exists := node.Labels["cost-tier"]; exists {
 		switch costLabel {
 		case "low":
@@ -608,7 +611,8 @@ func (sm *StrategyManager) getNodeCostMultiplier(node *corev1.Node) float64 {
 	return 1.0
 }
 
-func (sm *StrategyManager) getNodePowerMultiplier(node *corev1.Node) float64 {
+// nodePowerMultiplier scales power by the node's power-efficiency label
+func nodePowerMultiplier(node *corev1.Node) float64 {
 	if powerLabel, exists := node.Labels["power-efficiency"]; exists {
 		switch powerLabel {
 		case "high":
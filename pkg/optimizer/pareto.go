@@ -0,0 +1,322 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// MaxParetoAlternatives is the number of options of the Pareto front kept in a result
+const MaxParetoAlternatives = 10
+
+// ParetoWeights pick one option from the Pareto front of cost, power and performance
+type ParetoWeights struct {
+	Cost        float64
+	Power       float64
+	Performance float64
+}
+
+// Validate checks that the weights are non-negative and not all zero
+func (w ParetoWeights) Validate() error {
+	if w.Cost < 0 || w.Power < 0 || w.Performance < 0 {
+		return fmt.Errorf("pareto weights cannot be negative")
+	}
+	if w.Cost+w.Power+w.Performance <= 0 {
+		return fmt.Errorf("at least one pareto weight must be positive")
+	}
+	return nil
+}
+
+// ParseParetoWeights parses weights written as "cost=0.4,power=0.3,performance=0.3";
+// objectives that are omitted get no weight
+func ParseParetoWeights(spec string) (ParetoWeights, error) {
+	var weights ParetoWeights
+	for _, pair := range strings.Split(spec, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return weights, fmt.Errorf("invalid pareto weight %q, expected name=value", pair)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return weights, fmt.Errorf("invalid pareto weight for %s: %w", name, err)
+		}
+		switch name {
+		case "cost":
+			weights.Cost = weight
+		case "power":
+			weights.Power = weight
+		case "performance":
+			weights.Performance = weight
+		default:
+			return weights, fmt.Errorf("unknown pareto objective %q", name)
+		}
+	}
+	return weights, weights.Validate()
+}
+
+// ParetoOption is a placement and replica count with its estimated objectives
+type ParetoOption struct {
+	Node     string `json:"node"`
+	Replicas int32  `json:"replicas"`
+	// EstimatedCost and EstimatedPower are totals across the replicas
+	EstimatedCost  float64 `json:"estimatedCost"`
+	EstimatedPower float64 `json:"estimatedPower"`
+	// Performance is the number of replicas the node can run at their full request
+	Performance float64 `json:"performance"`
+	// Score is the weighted, front-normalized value that ranks the options
+	Score float64 `json:"score"`
+	// Selected marks the option the weights picked
+	Selected bool `json:"selected,omitempty"`
+
+	// carbon is the emission rate across the replicas in g CO2e per hour, held to the
+	// workload's carbon cap
+	carbon float64
+}
+
+// dominates reports whether o is no worse than other in every objective and better in one
+func (o ParetoOption) dominates(other ParetoOption) bool {
+	if o.EstimatedCost > other.EstimatedCost || o.EstimatedPower > other.EstimatedPower ||
+		o.Performance < other.Performance {
+		return false
+	}
+	return o.EstimatedCost < other.EstimatedCost || o.EstimatedPower < other.EstimatedPower ||
+		o.Performance > other.Performance
+}
+
+// paretoOptions lists every node that fits one replica, at each replica count the workload
+// may scale to, priced from the per-replica cost on the node and the per-replica power, with
+// the emissions of that power in the node's pool
+func paretoOptions(wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node, profiles []kcloudv1alpha1.NodePowerProfile,
+	cpuCores, memoryGB float64, replicaCost func(*corev1.Node) float64, replicaPower float64) []ParetoOption {
+	minReplicas, maxReplicas := replicaRange(wo)
+
+	var options []ParetoOption
+	for i := range nodes {
+		node := &nodes[i]
+		capacity := replicaCapacity(wo, node, cpuCores, memoryGB)
+		if capacity < 1 {
+			continue
+		}
+		cost, powerMultiplier := replicaCost(node), nodePowerMultiplier(node)
+		profile := ProfileForNode(profiles, *node)
+		for replicas := minReplicas; replicas <= maxReplicas; replicas++ {
+			power := replicaPower * powerMultiplier * float64(replicas)
+			options = append(options, ParetoOption{
+				Node:           node.Name,
+				Replicas:       replicas,
+				EstimatedCost:  cost * float64(replicas),
+				EstimatedPower: power,
+				Performance:    math.Min(float64(replicas), capacity),
+				carbon:         CarbonGramsPerHour(AccountCarbon(power, profile).CarbonFootprint),
+			})
+		}
+	}
+	return options
+}
+
+// replicaCapacity returns how many replicas fit in the node's allocatable CPU and memory,
+// zero when the node does not match the workload's node selector or lacks its GPUs
+func replicaCapacity(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, cpuCores, memoryGB float64) float64 {
+	if wo.Spec.PlacementPolicy != nil {
+		for key, value := range wo.Spec.PlacementPolicy.NodeSelector {
+			if node.Labels[key] != value {
+				return 0
+			}
+		}
+	}
+	capacity := math.Inf(1)
+	if cpuCores > 0 {
+		capacity = math.Min(capacity, node.Status.Allocatable.Cpu().AsApproximateFloat64()/cpuCores)
+	}
+	if memoryGB > 0 {
+		capacity = math.Min(capacity, node.Status.Allocatable.Memory().AsApproximateFloat64()/(1<<30)/memoryGB)
+	}
+	if gpu := wo.Spec.Resources.GPU; gpu > 0 {
+		allocatable := node.Status.Allocatable["nvidia.com/gpu"]
+		capacity = math.Min(capacity, math.Floor(allocatable.AsApproximateFloat64()/float64(gpu)))
+	}
+	if math.IsInf(capacity, 1) {
+		return 0
+	}
+	return capacity
+}
+
+// paretoFront returns the options no other option dominates, cheapest first. Options over
+// the workload's cost, power or carbon caps are left out unless none is within them.
+func paretoFront(wo *kcloudv1alpha1.WorkloadOptimizer, options []ParetoOption) []ParetoOption {
+	var within []ParetoOption
+	for _, option := range options {
+		if wo.Spec.CostConstraints != nil && wo.Spec.CostConstraints.MaxCostPerHour > 0 &&
+			option.EstimatedCost > wo.Spec.CostConstraints.MaxCostPerHour {
+			continue
+		}
+		if wo.Spec.PowerConstraints != nil && wo.Spec.PowerConstraints.MaxPowerUsage > 0 &&
+			option.EstimatedPower > wo.Spec.PowerConstraints.MaxPowerUsage {
+			continue
+		}
+		if wo.Spec.PowerConstraints != nil && wo.Spec.PowerConstraints.MaxCarbonPerHour != nil &&
+			option.carbon > *wo.Spec.PowerConstraints.MaxCarbonPerHour {
+			continue
+		}
+		within = append(within, option)
+	}
+	if len(within) > 0 {
+		options = within
+	}
+
+	sorted := make([]ParetoOption, len(options))
+	copy(sorted, options)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.EstimatedCost != b.EstimatedCost {
+			return a.EstimatedCost < b.EstimatedCost
+		}
+		if a.EstimatedPower != b.EstimatedPower {
+			return a.EstimatedPower < b.EstimatedPower
+		}
+		return a.Performance > b.Performance
+	})
+
+	// An option can only be dominated by one sorted before it
+	var front []ParetoOption
+	for _, option := range sorted {
+		dominated := false
+		for _, member := range front {
+			if member.dominates(option) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, option)
+		}
+	}
+	return front
+}
+
+// selectParetoOption scores the front with each objective normalized over it and returns
+// the index of the best option; ties keep the cheaper option
+func selectParetoOption(front []ParetoOption, weights ParetoWeights) int {
+	normalize := func(value, low, high float64) float64 {
+		if high == low {
+			return 1
+		}
+		return (value - low) / (high - low)
+	}
+	minCost, maxCost := math.Inf(1), math.Inf(-1)
+	minPower, maxPower := math.Inf(1), math.Inf(-1)
+	minPerf, maxPerf := math.Inf(1), math.Inf(-1)
+	for _, option := range front {
+		minCost, maxCost = math.Min(minCost, option.EstimatedCost), math.Max(maxCost, option.EstimatedCost)
+		minPower, maxPower = math.Min(minPower, option.EstimatedPower), math.Max(maxPower, option.EstimatedPower)
+		minPerf, maxPerf = math.Min(minPerf, option.Performance), math.Max(maxPerf, option.Performance)
+	}
+
+	total := weights.Cost + weights.Power + weights.Performance
+	best := 0
+	for i := range front {
+		option := &front[i]
+		score := weights.Cost*(1-normalize(option.EstimatedCost, minCost, maxCost)) +
+			weights.Power*(1-normalize(option.EstimatedPower, minPower, maxPower)) +
+			weights.Performance*normalize(option.Performance, minPerf, maxPerf)
+		option.Score = math.Round(score/total*1e4) / 1e4
+		if option.Score > front[best].Score {
+			best = i
+		}
+	}
+	return best
+}
+
+// paretoAlternatives keeps the highest scoring options of the front, cheapest first
+func paretoAlternatives(front []ParetoOption, limit int) []ParetoOption {
+	if len(front) <= limit {
+		return front
+	}
+	ranked := make([]int, len(front))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return front[ranked[i]].Score > front[ranked[j]].Score })
+	ranked = ranked[:limit]
+	sort.Ints(ranked)
+
+	alternatives := make([]ParetoOption, len(ranked))
+	for i, index := range ranked {
+		alternatives[i] = front[index]
+	}
+	return alternatives
+}
+
+// optimizePareto replaces the single estimate with the option the Pareto weights pick from
+// the front of node and replica options, and keeps the best alternatives in the result.
+// It leaves the result unchanged when no node fits a replica.
+func (e *Engine) optimizePareto(state *WorkloadState, cpuCores, memoryGB, replicaCost, replicaPower float64,
+	result *OptimizationResult) {
+	wo := state.WorkloadOptimizer
+	costOn := func(node *corev1.Node) float64 {
+		if _, ok := ReplicaNodeCost(e.NodePricing, node, cpuCores, memoryGB, wo.Spec.Resources.GPU); ok {
+			// A priced node's share replaces the rate-based estimate
+			cost, _ := nodeAdjusted(wo, nil, e.costOnNode(wo, node, cpuCores, memoryGB, wo.Spec.Resources.GPU, 0), 0)
+			return e.Currency.FromUSD(cost)
+		}
+		return e.Currency.FromUSD(replicaCost * nodeCostMultiplier(node))
+	}
+	options := paretoOptions(wo, state.placementNodes(), state.EnergyProfiles, cpuCores, memoryGB, costOn, replicaPower)
+	front := paretoFront(wo, e.slaOptions(wo, state.placementNodes(), options))
+	if len(front) == 0 {
+		return
+	}
+	best := selectParetoOption(front, *e.Pareto)
+	front[best].Selected = true
+
+	selected := front[best]
+	result.EstimatedCost = selected.EstimatedCost
+	result.EstimatedPower = selected.EstimatedPower
+	result.RecommendedReplicas = selected.Replicas
+	result.ParetoFront = paretoAlternatives(front, MaxParetoAlternatives)
+	if e.SLA != nil && wo.Spec.SLA != nil {
+		prediction := e.SLA.Predict(wo, findNode(state.AvailableNodes, selected.Node), selected.Replicas)
+		result.SLA = &prediction
+	}
+}
+
+// slaOptions leaves out the options predicted to miss the workload's SLA, however cheap,
+// unless every option misses it
+func (e *Engine) slaOptions(wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node,
+	options []ParetoOption) []ParetoOption {
+	if e.SLA == nil || wo.Spec.SLA == nil {
+		return options
+	}
+	var met []ParetoOption
+	for _, option := range options {
+		if e.SLA.Predict(wo, findNode(nodes, option.Node), option.Replicas).Met {
+			met = append(met, option)
+		}
+	}
+	if len(met) == 0 {
+		return options
+	}
+	return met
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// Recurring pattern periods
+const (
+	PatternDaily  = "daily"
+	PatternWeekly = "weekly"
+)
+
+const (
+	// MaxArrivals is how many run starts are kept per workload
+	MaxArrivals = 30
+	// MinPatternOccurrences is how many runs must match before a pattern is trusted
+	MinPatternOccurrences = 3
+	// PatternTolerance is how far a run may start from the pattern and still match it
+	PatternTolerance = 30 * time.Minute
+
+	// arrivalRunGap separates runs: pods created within it of a run's start belong to the run
+	arrivalRunGap = 30 * time.Minute
+	// patternMatchShare is the share of the kept runs that must match the pattern
+	patternMatchShare = 0.8
+	// patternConfidentOccurrences is how many matching runs give a pattern full confidence
+	patternConfidentOccurrences = 10
+	// dailyCoverage is the share of days spanned that must have a run, so weekday-only jobs
+	// still count as daily while weekly ones do not
+	dailyCoverage = 0.6
+)
+
+// patternPeriods are tried in order, so a daily pattern wins over a weekly one
+var patternPeriods = []struct {
+	name     string
+	interval time.Duration
+}{
+	{name: PatternDaily, interval: 24 * time.Hour},
+	{name: PatternWeekly, interval: 7 * 24 * time.Hour},
+}
+
+// RecurringPattern is a daily or weekly pattern in a workload's run starts
+type RecurringPattern struct {
+	Period   string
+	Interval time.Duration
+	// Start is the predicted start of the run in the period of the first matching run
+	Start time.Time
+	// NextArrival is the first predicted start after now
+	NextArrival time.Time
+	// Jitter is how far the matching runs strayed from the predicted start at most
+	Jitter time.Duration
+	// Occurrences is how many runs matched
+	Occurrences int
+}
+
+// Recurrence returns the run schedule the pattern predicts, reserving capacity long enough
+// ahead to cover the jitter
+func (p RecurringPattern) Recurrence() *kcloudv1alpha1.RecurrenceSpec {
+	return &kcloudv1alpha1.RecurrenceSpec{
+		StartTime: metav1.NewTime(p.Start),
+		Interval:  metav1.Duration{Duration: p.Interval},
+		LeadTime:  &metav1.Duration{Duration: DefaultRecurrenceLeadTime + p.Jitter},
+	}
+}
+
+// Confidence rates the pattern by how many runs matched it and how far they strayed from
+// it relative to PatternTolerance
+func (p RecurringPattern) Confidence() float64 {
+	return Confidence(float64(p.Occurrences), patternConfidentOccurrences, float64(p.Jitter)/float64(PatternTolerance))
+}
+
+// patternInterval returns the interval of a pattern period, zero for an unknown one
+func patternInterval(period string) time.Duration {
+	for _, p := range patternPeriods {
+		if p.name == period {
+			return p.interval
+		}
+	}
+	return 0
+}
+
+// DetectedRecurrence returns the run schedule of a pattern recorded in a workload's status,
+// nil when none was detected
+func DetectedRecurrence(pattern *kcloudv1alpha1.ArrivalPattern) *kcloudv1alpha1.RecurrenceSpec {
+	if pattern == nil || pattern.NextArrival == nil || patternInterval(pattern.Period) == 0 {
+		return nil
+	}
+	detected := RecurringPattern{Start: pattern.NextArrival.Time, Interval: patternInterval(pattern.Period)}
+	if pattern.Jitter != nil {
+		detected.Jitter = pattern.Jitter.Duration
+	}
+	return detected.Recurrence()
+}
+
+// RecordArrivals adds the runs started by pods created at the given times to the known run
+// starts. A pod created within arrivalRunGap of the latest run belongs to it; the oldest
+// runs are dropped beyond MaxArrivals.
+func RecordArrivals(arrivals []time.Time, created []time.Time) []time.Time {
+	sorted := append([]time.Time(nil), created...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	for _, t := range sorted {
+		if n := len(arrivals); n > 0 && t.Before(arrivals[n-1].Add(arrivalRunGap)) {
+			continue
+		}
+		arrivals = append(arrivals, t)
+	}
+	if len(arrivals) > MaxArrivals {
+		arrivals = arrivals[len(arrivals)-MaxArrivals:]
+	}
+	return arrivals
+}
+
+// DetectPattern looks for a daily, then a weekly, pattern in the run starts, oldest first.
+// Runs match a period when they start at about the same offset into it; a pattern needs
+// MinPatternOccurrences matching runs in distinct periods, most runs to match, and a run in
+// the last two periods. Daily patterns also need runs on most days they span.
+func DetectPattern(arrivals []time.Time, now time.Time) (RecurringPattern, bool) {
+	for _, period := range patternPeriods {
+		pattern, coverage, ok := detectPeriod(arrivals, now, period.interval)
+		if !ok || (period.name == PatternDaily && coverage < dailyCoverage) {
+			continue
+		}
+		pattern.Period = period.name
+		return pattern, true
+	}
+	return RecurringPattern{}, false
+}
+
+// detectPeriod fits the run starts to one period by the circular mean of their offsets. It
+// also returns the share of periods between the first and last matching run that had one.
+func detectPeriod(arrivals []time.Time, now time.Time, interval time.Duration) (RecurringPattern, float64, bool) {
+	if len(arrivals) < MinPatternOccurrences || now.Sub(arrivals[len(arrivals)-1]) > 2*interval {
+		return RecurringPattern{}, 0, false
+	}
+	var sin, cos float64
+	for _, t := range arrivals {
+		angle := 2 * math.Pi * float64(offset(t, interval)) / float64(interval)
+		sin += math.Sin(angle)
+		cos += math.Cos(angle)
+	}
+	mean := math.Atan2(sin, cos)
+	if mean < 0 {
+		mean += 2 * math.Pi
+	}
+	meanOffset := time.Duration(mean / (2 * math.Pi) * float64(interval))
+
+	pattern := RecurringPattern{Interval: interval}
+	cycles := make(map[int64]bool)
+	var first, last int64
+	for _, t := range arrivals {
+		distance := (offset(t, interval) - meanOffset).Abs()
+		if wrapped := interval - distance; wrapped < distance {
+			distance = wrapped
+		}
+		if distance > PatternTolerance {
+			continue
+		}
+		// Number periods so that each is centered on the predicted start
+		cycle := (t.UnixNano() - int64(meanOffset) + int64(interval/2)) / int64(interval)
+		if pattern.Occurrences == 0 {
+			pattern.Start = time.Unix(0, cycle*int64(interval)+int64(meanOffset)).In(t.Location()).Truncate(time.Minute)
+			first = cycle
+		}
+		last = cycle
+		cycles[cycle] = true
+		pattern.Occurrences++
+		pattern.Jitter = max(pattern.Jitter, distance)
+	}
+	if len(cycles) < MinPatternOccurrences || float64(pattern.Occurrences) < patternMatchShare*float64(len(arrivals)) {
+		return RecurringPattern{}, 0, false
+	}
+	pattern.Jitter = pattern.Jitter.Round(time.Second)
+	pattern.NextArrival = NextRun(pattern.Recurrence(), now)
+	return pattern, float64(len(cycles)) / float64(last-first+1), true
+}
+
+// offset returns how far into its period, counted from the Unix epoch in UTC, t falls
+func offset(t time.Time, interval time.Duration) time.Duration {
+	return time.Duration(t.UnixNano() % int64(interval))
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"sort"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// Annotations managed pods carry their hourly cost in
+const (
+	// PodCostAnnotation holds the pod's cost per hour, in the currency of PodCostCurrencyAnnotation
+	PodCostAnnotation = "kcloud.io/cost-per-hour"
+	// PodCostCurrencyAnnotation holds the ISO 4217 code of the currency of PodCostAnnotation
+	PodCostCurrencyAnnotation = "kcloud.io/cost-currency"
+)
+
+// PodCost is the hourly cost of one of a workload's running pods
+type PodCost struct {
+	Name string
+	Node string
+	// Cost is the cost per hour, in US dollars while estimating and in the engine's reporting
+	// currency in results
+	Cost float64
+	// Realized is true when Cost is what the pod was charged rather than an estimate
+	Realized bool
+}
+
+// podCosts converts the running pods' estimated costs into the reporting currency, ordered by
+// name. When the workload's cost is realized, what each pod was charged replaces its estimate.
+func (e *Engine) podCosts(wo *kcloudv1alpha1.WorkloadOptimizer, pods []PodCost, realized bool) []PodCost {
+	costs := make([]PodCost, 0, len(pods))
+	for _, pod := range pods {
+		if realized {
+			if charged, ok := e.Realized.PodCost(wo.Namespace, pod.Name); ok {
+				pod.Cost, pod.Realized = charged.Total, true
+			}
+		}
+		pod.Cost = e.Currency.FromUSD(pod.Cost)
+		costs = append(costs, pod)
+	}
+	sort.Slice(costs, func(i, j int) bool { return costs[i].Name < costs[j].Name })
+	return costs
+}
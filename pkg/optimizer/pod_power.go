@@ -0,0 +1,260 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DefaultPodPowerInterval is how often node power is attributed to pods
+	DefaultPodPowerInterval = time.Minute
+
+	// PodPowerAnnotation is the pod annotation the power attributed to the pod is published
+	// in, in whole watts
+	PodPowerAnnotation = "kcloud.io/power"
+
+	// podPowerChangeThreshold is the relative change in a pod's power that is republished
+	podPowerChangeThreshold = 0.05
+
+	// managedPodAnnotation names the WorkloadOptimizer that manages a pod
+	managedPodAnnotation = "kcloud.io/workload-optimizer"
+)
+
+var podPowerWatts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kcloud_pod_power_watts",
+	Help: "Measured node power attributed to the pod by its CPU and GPU utilization, in watts",
+}, []string{"namespace", "pod", "node"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(podPowerWatts)
+}
+
+// PodPowerSource reports the power attributed to pods
+type PodPowerSource interface {
+	// PodPower returns the pod's power in watts and whether it is known
+	PodPower(namespace, name string) (float64, bool)
+}
+
+// podPowerSnapshot is the power attributed to every pod, and when
+type podPowerSnapshot struct {
+	pods         map[types.NamespacedName]float64
+	attributedAt time.Time
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+
+// PodPowerAttribution splits the measured power of each node among its running pods. The
+// GPU part of the power, from the node class's power model or else the node's GPU over its
+// CPU utilization, is shared by the GPUs the pods use; the rest, idle power included, by the
+// CPU cores they use. Pods without measured usage count at their requests. The attributed
+// power is exported as a metric and annotated on each pod.
+type PodPowerAttribution struct {
+	client   client.Client
+	power    NodePowerSource
+	usage    PodUsageSource
+	interval time.Duration
+	clock    clock.PassiveClock
+
+	// Models splits node power into its CPU and GPU parts; nil splits it by utilization
+	Models *NodePowerModels
+
+	snapshot atomic.Pointer[podPowerSnapshot]
+}
+
+// NewPodPowerAttribution returns an attribution of the node power source among the pods c
+// lists, by their usage, every interval
+func NewPodPowerAttribution(c client.Client, power NodePowerSource, usage PodUsageSource,
+	interval time.Duration) *PodPowerAttribution {
+	if interval <= 0 {
+		interval = DefaultPodPowerInterval
+	}
+	return &PodPowerAttribution{client: c, power: power, usage: usage, interval: interval, clock: clock.RealClock{}}
+}
+
+// SetClock replaces the clock attribution ages are measured with
+func (a *PodPowerAttribution) SetClock(c clock.PassiveClock) {
+	a.clock = c
+}
+
+// PodPower returns the power last attributed to the pod, unless attribution has stopped for
+// five intervals
+func (a *PodPowerAttribution) PodPower(namespace, name string) (float64, bool) {
+	snapshot := a.snapshot.Load()
+	if snapshot == nil || a.clock.Since(snapshot.attributedAt) > 5*a.interval {
+		return 0, false
+	}
+	watts, ok := snapshot.pods[types.NamespacedName{Namespace: namespace, Name: name}]
+	return watts, ok
+}
+
+// Attribute splits the measured power of each node among its running pods
+func (a *PodPowerAttribution) Attribute(nodes []corev1.Node, pods []corev1.Pod,
+	usage map[types.NamespacedName]PodResourceUsage) map[types.NamespacedName]float64 {
+	running := make(map[string][]*corev1.Pod)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != "" && pod.Status.Phase == corev1.PodRunning {
+			running[pod.Spec.NodeName] = append(running[pod.Spec.NodeName], pod)
+		}
+	}
+
+	attributed := make(map[types.NamespacedName]float64)
+	for i := range nodes {
+		node := &nodes[i]
+		watts, ok := a.power.NodePower(node.Name)
+		if !ok || len(running[node.Name]) == 0 {
+			continue
+		}
+		cpu := make([]float64, len(running[node.Name]))
+		gpu := make([]float64, len(running[node.Name]))
+		var cpuUsed, gpuUsed float64
+		for j, pod := range running[node.Name] {
+			if used, ok := usage[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]; ok {
+				cpu[j], gpu[j] = used.CPUCores, used.GPUs
+			} else {
+				cores, _, gpus, _ := PodResources(pod)
+				cpu[j], gpu[j] = cores, float64(gpus)
+			}
+			cpuUsed += cpu[j]
+			gpuUsed += gpu[j]
+		}
+
+		gpuWatts := a.gpuWatts(node, watts, cpuUsed, gpuUsed)
+		cpuWatts := watts - gpuWatts
+		for j, pod := range running[node.Name] {
+			share := 1 / float64(len(cpu))
+			if cpuUsed > 0 {
+				share = cpu[j] / cpuUsed
+			}
+			podWatts := cpuWatts * share
+			if gpuUsed > 0 {
+				podWatts += gpuWatts * gpu[j] / gpuUsed
+			}
+			attributed[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = podWatts
+		}
+	}
+	return attributed
+}
+
+// gpuWatts returns the part of the node's power its GPUs draw: what the node class's power
+// model predicts at their utilization, or else the node's power split by its GPU and CPU
+// utilization
+func (a *PodPowerAttribution) gpuWatts(node *corev1.Node, watts, cpuUsed, gpuUsed float64) float64 {
+	gpuAllocatable := node.Status.Allocatable["nvidia.com/gpu"]
+	gpus := gpuAllocatable.AsApproximateFloat64()
+	if gpuUsed <= 0 || gpus <= 0 {
+		return 0
+	}
+	gpuUtilization := math.Min(1, gpuUsed/gpus)
+	if a.Models != nil {
+		if model, ok := a.Models.Model(node); ok {
+			return math.Min(watts, model.GPU*gpuUtilization)
+		}
+	}
+	var cpuUtilization float64
+	if cores := node.Status.Allocatable.Cpu().AsApproximateFloat64(); cores > 0 {
+		cpuUtilization = math.Min(1, cpuUsed/cores)
+	}
+	return watts * gpuUtilization / (gpuUtilization + cpuUtilization)
+}
+
+// Refresh attributes node power to the running pods, and exports it and annotates pods whose
+// power changed noticeably for the pods a WorkloadOptimizer manages. Other pods still take
+// their share of their node's power.
+func (a *PodPowerAttribution) Refresh(ctx context.Context) error {
+	var nodes corev1.NodeList
+	if err := a.client.List(ctx, &nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var pods corev1.PodList
+	if err := a.client.List(ctx, &pods); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	usage, err := a.usage.PodUsage(ctx, a.interval)
+	if err != nil {
+		return err
+	}
+	attributed := a.Attribute(nodes.Items, pods.Items, usage)
+	a.snapshot.Store(&podPowerSnapshot{pods: attributed, attributedAt: a.clock.Now()})
+
+	podPowerWatts.Reset()
+	var annotateErr error
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Annotations[managedPodAnnotation] == "" {
+			continue
+		}
+		watts, ok := attributed[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]
+		if !ok {
+			continue
+		}
+		podPowerWatts.WithLabelValues(pod.Namespace, pod.Name, pod.Spec.NodeName).Set(watts)
+
+		published, err := strconv.ParseFloat(pod.Annotations[PodPowerAnnotation], 64)
+		if err == nil && math.Abs(watts-published) <= podPowerChangeThreshold*math.Max(published, 1) {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[PodPowerAnnotation] = strconv.FormatFloat(math.Round(watts), 'f', 0, 64)
+		if err := a.client.Patch(ctx, pod, patch); err != nil && annotateErr == nil {
+			annotateErr = fmt.Errorf("failed to annotate pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	return client.IgnoreNotFound(annotateErr)
+}
+
+// Start attributes node power every interval until the context is cancelled
+func (a *PodPowerAttribution) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("pod-power")
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		if err := a.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to attribute node power to pods")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection lets only the leader annotate pods, which is where workload power is
+// evaluated
+func (a *PodPowerAttribution) NeedLeaderElection() bool {
+	return true
+}
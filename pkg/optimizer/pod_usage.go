@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PodResourceUsage is the average resource usage of a pod over a window
+type PodResourceUsage struct {
+	CPUCores float64
+	MemoryGB float64
+	// GPUs is the GPU utilization of the pod in whole GPUs
+	GPUs float64
+}
+
+// PodUsage returns the average usage over the window of every pod Prometheus has samples
+// for, by namespace and name
+func (p *PrometheusUsageSource) PodUsage(ctx context.Context, window time.Duration) (map[types.NamespacedName]PodResourceUsage, error) {
+	window = max(window, prometheusStepDuration)
+	rangeSelector := fmt.Sprintf("[%ds]", int64(window.Seconds()))
+	subquery := fmt.Sprintf("[%ds:%s]", int64(window.Seconds()), prometheusStep)
+
+	usage := make(map[types.NamespacedName]PodResourceUsage)
+	for _, series := range []struct {
+		resource string
+		query    string
+		set      func(u *PodResourceUsage, value float64)
+	}{
+		{"cpu", fmt.Sprintf(`sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!=""}%s))`,
+			rangeSelector), func(u *PodResourceUsage, v float64) { u.CPUCores = v }},
+		{"memory", fmt.Sprintf(`sum by (namespace, pod) (avg_over_time(container_memory_working_set_bytes{container!=""}%s))`,
+			rangeSelector), func(u *PodResourceUsage, v float64) { u.MemoryGB = v / (1 << 30) }},
+		{"gpu", fmt.Sprintf(`avg_over_time((sum by (namespace, pod) (DCGM_FI_DEV_GPU_UTIL) / 100)%s)`,
+			subquery), func(u *PodResourceUsage, v float64) { u.GPUs = v }},
+	} {
+		values, err := p.queryByPod(ctx, series.query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s usage by pod: %w", series.resource, err)
+		}
+		for pod, value := range values {
+			u := usage[pod]
+			series.set(&u, value)
+			usage[pod] = u
+		}
+	}
+	return usage, nil
+}
+
+// prometheusVectorResponse is the subset of an instant query response with series labels
+// the source reads
+type prometheusVectorResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []any             `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryByPod runs an instant query returning one series per pod, skipping samples that are
+// not a number
+func (p *PrometheusUsageSource) queryByPod(ctx context.Context, query string) (map[types.NamespacedName]float64, error) {
+	samples, err := p.queryVector(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[types.NamespacedName]float64, len(samples))
+	for _, sample := range samples {
+		if sample.labels["pod"] == "" {
+			continue
+		}
+		values[types.NamespacedName{Namespace: sample.labels["namespace"], Name: sample.labels["pod"]}] = sample.value
+	}
+	return values, nil
+}
+
+// vectorSample is one series of an instant query result
+type vectorSample struct {
+	labels map[string]string
+	value  float64
+}
+
+// queryVector runs an instant query, skipping samples that are not a number
+func (p *PrometheusUsageSource) queryVector(ctx context.Context, query string) ([]vectorSample, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		p.url+"/api/v1/query?"+url.Values{"query": {query}}.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prometheus request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body prometheusVectorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode prometheus response (status %d): %w", resp.StatusCode, err)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed (status %d): %s", resp.StatusCode, body.Error)
+	}
+	samples := make([]vectorSample, 0, len(body.Data.Result))
+	for _, series := range body.Data.Result {
+		if len(series.Value) != 2 {
+			continue
+		}
+		raw, ok := series.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		samples = append(samples, vectorSample{labels: series.Metric, value: value})
+	}
+	return samples, nil
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"math"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -58,6 +60,7 @@ type PowerConstraintsResult struct {
 	EstimatedPower   float64
 	PowerRemaining   float64
 	GreenEnergyScore float64
+	RenewableShare   float64
 	CarbonFootprint  float64
 	Recommendations  []string
 }
@@ -205,13 +208,17 @@ func (pa *PolicyApplier) applyPowerPolicies(ctx context.Context, wo *kcloudv1alp
 
 		// Check green energy preference
 		if wo.Spec.PowerConstraints.PreferGreen {
-			result.GreenEnergyScore = 0.8                    // Assume 80% green energy
-			result.CarbonFootprint = powerWatts * 0.5 / 1000 // Simplified carbon footprint calculation
 			result.Recommendations = append(result.Recommendations,
 				"Consider green energy sources for better sustainability")
 		}
 	}
 
+	// Account carbon against the energy mix of the assigned node pool
+	account := pa.accountCarbon(ctx, wo, powerWatts)
+	result.RenewableShare = account.RenewableShare
+	result.GreenEnergyScore = account.RenewableShare
+	result.CarbonFootprint = account.CarbonFootprint
+
 	// Apply cluster-wide power policies
 	powerPolicies, err := pa.getPowerPolicies(ctx, wo.Namespace)
 	if err != nil {
@@ -225,6 +232,28 @@ func (pa *PolicyApplier) applyPowerPolicies(ctx context.Context, wo *kcloudv1alp
 	return result, nil
 }
 
+// accountCarbon computes the realized renewable share and carbon footprint of a workload
+func (pa *PolicyApplier) accountCarbon(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	powerWatts float64) *CarbonAccount {
+	if wo.Status.AssignedNode == nil || *wo.Status.AssignedNode == "" {
+		return AccountCarbon(powerWatts, nil)
+	}
+
+	var node corev1.Node
+	if err := pa.Client.Get(ctx, types.NamespacedName{Name: *wo.Status.AssignedNode}, &node); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get assigned node", "node", *wo.Status.AssignedNode)
+		return AccountCarbon(powerWatts, nil)
+	}
+
+	var profiles kcloudv1alpha1.NodePowerProfileList
+	if err := pa.Client.List(ctx, &profiles); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list node power profiles")
+		return AccountCarbon(powerWatts, nil)
+	}
+
+	return AccountCarbon(powerWatts, ProfileForNode(profiles.Items, node))
+}
+
 // applyCostPolicy applies a single cost policy
 func (pa *PolicyApplier) applyCostPolicy(result *CostConstraintsResult, policy *kcloudv1alpha1.CostPolicy,
 	costBreakdown *CostBreakdown) {
@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PowerCapAnnotation is the node annotation the operator requests a power cap in, as a
+	// percentage of the default power limits of the node's CPU packages and GPUs. It holds
+	// the lowest cap any power policy requests on the node.
+	PowerCapAnnotation = "kcloud.io/power-cap-percent"
+	// PowerCapRequestPrefix prefixes the node annotations each power policy requests its cap
+	// in, keyed by the policy name
+	PowerCapRequestPrefix = "power-cap.kcloud.io/"
+	// OriginalCPUPowerLimitsAnnotation and OriginalGPUPowerLimitsAnnotation are the node
+	// annotations the node agent records the CPU package and GPU power limits in that were
+	// set before it applied the first cap, so it can restore exactly those once no cap is
+	// requested. They hold JSON maps of package zone to microwatts and GPU index to watts.
+	OriginalCPUPowerLimitsAnnotation = "kcloud.io/original-cpu-power-limits"
+	OriginalGPUPowerLimitsAnnotation = "kcloud.io/original-gpu-power-limits"
+	// CPUPowerCapAppliedAnnotation and GPUPowerCapAppliedAnnotation are the node annotations
+	// the node agent reports the cap it applied to the CPU packages and GPUs in. They are
+	// removed once the agent restored the default limits.
+	CPUPowerCapAppliedAnnotation = "kcloud.io/cpu-power-cap-applied"
+	GPUPowerCapAppliedAnnotation = "kcloud.io/gpu-power-cap-applied"
+
+	// MinPowerCapPercent is the lowest cap node agents apply
+	MinPowerCapPercent = 10
+)
+
+// ParsePowerCap returns the cap a node annotation requests in percent, raised to
+// MinPowerCapPercent; false when it requests none
+func ParsePowerCap(value string) (int32, bool) {
+	percent, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || percent <= 0 || percent >= 100 {
+		return 0, false
+	}
+	return int32(max(percent, MinPowerCapPercent)), true
+}
+
+// PowerCapRequests returns the cap each power policy requests on a node, by policy name
+func PowerCapRequests(annotations map[string]string) map[string]int32 {
+	requests := map[string]int32{}
+	for key, value := range annotations {
+		policy, ok := strings.CutPrefix(key, PowerCapRequestPrefix)
+		if !ok {
+			continue
+		}
+		if percent, ok := ParsePowerCap(value); ok {
+			requests[policy] = percent
+		}
+	}
+	return requests
+}
+
+// EffectivePowerCap returns the lowest cap the requests ask for; false when there are none
+func EffectivePowerCap(requests map[string]int32) (int32, bool) {
+	lowest, found := int32(100), false
+	for _, percent := range requests {
+		lowest, found = min(lowest, percent), true
+	}
+	return lowest, found
+}
+
+// ReadRAPLPowerLimits returns the long-term power limit of each CPU package zone under the
+// powercap root
+func ReadRAPLPowerLimits(root string) (map[string]uint64, error) {
+	dirs, err := raplPackageZones(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no rapl package zones under %s", root)
+	}
+	limits := make(map[string]uint64, len(dirs))
+	for _, dir := range dirs {
+		limit, err := readUint(filepath.Join(dir, "constraint_0_power_limit_uw"))
+		if err != nil {
+			return nil, err
+		}
+		limits[filepath.Base(dir)] = limit
+	}
+	return limits, nil
+}
+
+// ApplyRAPLPowerCap sets the long-term power limit of each CPU package zone under the
+// powercap root to the percent of its maximum
+func ApplyRAPLPowerCap(root string, percent int32) error {
+	dirs, err := raplPackageZones(root)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("no rapl package zones under %s", root)
+	}
+	for _, dir := range dirs {
+		maxPower, err := readUint(filepath.Join(dir, "constraint_0_max_power_uw"))
+		if err != nil {
+			return err
+		}
+		if err := writeRAPLLimit(dir, maxPower*uint64(percent)/100); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreRAPLPowerLimits sets each CPU package zone under the powercap root back to the
+// recorded limit; zones without one are left alone
+func RestoreRAPLPowerLimits(root string, limits map[string]uint64) error {
+	dirs, err := raplPackageZones(root)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		limit, ok := limits[filepath.Base(dir)]
+		if !ok {
+			continue
+		}
+		if err := writeRAPLLimit(dir, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRAPLLimit writes the long-term power limit of a package zone
+func writeRAPLLimit(dir string, limit uint64) error {
+	path := filepath.Join(dir, "constraint_0_power_limit_uw")
+	if err := os.WriteFile(path, []byte(strconv.FormatUint(limit, 10)), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// GPUPowerLimit is the power limit range of one GPU and its current limit, in watts
+type GPUPowerLimit struct {
+	Index   int
+	Default float64
+	Min     float64
+	Current float64
+}
+
+// Capped returns the GPU's limit at the percent of its default, no lower than its minimum;
+// the default for percent 0
+func (l GPUPowerLimit) Capped(percent int32) float64 {
+	if percent <= 0 {
+		return l.Default
+	}
+	return max(l.Default*float64(percent)/100, l.Min)
+}
+
+// ParseGPUPowerLimits parses the output of nvidia-smi
+// --query-gpu=index,power.default_limit,power.min_limit,power.limit --format=csv,noheader,nounits.
+// GPUs that do not support power limits report [N/A] and are skipped.
+func ParseGPUPowerLimits(output string) ([]GPUPowerLimit, error) {
+	var limits []GPUPowerLimit
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 4 {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid GPU index %q", fields[0])
+		}
+		defaultLimit, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		if err != nil {
+			continue
+		}
+		minLimit, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		if err != nil {
+			continue
+		}
+		current, err := strconv.ParseFloat(strings.TrimSpace(fields[3]), 64)
+		if err != nil {
+			continue
+		}
+		limits = append(limits, GPUPowerLimit{Index: index, Default: defaultLimit, Min: minLimit, Current: current})
+	}
+	return limits, nil
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultPowerModelInterval is how often node power and utilization are sampled
+	DefaultPowerModelInterval = 5 * time.Minute
+
+	// powerModelSamples is how many samples of each node class are kept, a week at the
+	// default interval
+	powerModelSamples = 2016
+	// minPowerModelSamples is how many samples a class needs before its model is fitted
+	minPowerModelSamples = 12
+	// minUtilizationSpread is how far utilization must range across a class's samples for
+	// its power slope to be fitted
+	minUtilizationSpread = 0.1
+)
+
+// PodUsageSource reports the recent resource usage of every pod
+type PodUsageSource interface {
+	PodUsage(ctx context.Context, window time.Duration) (map[types.NamespacedName]PodResourceUsage, error)
+}
+
+// PowerModel predicts a node's power from its CPU and GPU utilization
+type PowerModel struct {
+	// Idle is the power in watts at no utilization
+	Idle float64
+	// CPU and GPU are the power in watts added at full CPU and full GPU utilization
+	CPU float64
+	GPU float64
+	// Samples is how many samples the model was fitted to
+	Samples int
+}
+
+// Predict returns the power in watts at the CPU and GPU utilization, from 0 to 1
+func (m PowerModel) Predict(cpu, gpu float64) float64 {
+	return m.Idle + m.CPU*cpu + m.GPU*gpu
+}
+
+// powerSample is a node's measured power at its utilization
+type powerSample struct {
+	cpu, gpu, watts float64
+}
+
+// nodeUtilization is the share of a node's allocatable CPU and GPUs its pods use
+type nodeUtilization struct {
+	cpu, gpu float64
+}
+
+// powerModelSnapshot is the fitted model of each node class and the last utilization of
+// each node
+type powerModelSnapshot struct {
+	models      map[string]PowerModel
+	utilization map[string]nodeUtilization
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes;pods,verbs=get;list;watch
+
+// NodePowerModels fits a power model per node class from the measured power of its nodes
+// at the CPU and GPU utilization of their pods. Nodes are grouped by NodeClass.
+type NodePowerModels struct {
+	reader   client.Reader
+	power    NodePowerSource
+	usage    PodUsageSource
+	interval time.Duration
+
+	mu      sync.Mutex
+	samples map[string][]powerSample
+
+	snapshot atomic.Pointer[powerModelSnapshot]
+}
+
+// NewNodePowerModels returns models fitted from the nodes and pods c lists, the node power
+// source and pod usage, sampled every interval
+func NewNodePowerModels(c client.Reader, power NodePowerSource, usage PodUsageSource,
+	interval time.Duration) *NodePowerModels {
+	if interval <= 0 {
+		interval = DefaultPowerModelInterval
+	}
+	return &NodePowerModels{reader: c, power: power, usage: usage, interval: interval,
+		samples: make(map[string][]powerSample)}
+}
+
+// Model returns the power model of the node's class, false until it is fitted
+func (m *NodePowerModels) Model(node *corev1.Node) (PowerModel, bool) {
+	snapshot := m.snapshot.Load()
+	if snapshot == nil {
+		return PowerModel{}, false
+	}
+	model, ok := snapshot.models[NodeClass(node)]
+	return model, ok
+}
+
+// PlacementDelta predicts the power in watts a replica using the CPU cores and GPUs adds to
+// the node, from the model of its class and its last sampled utilization
+func (m *NodePowerModels) PlacementDelta(node *corev1.Node, cpuCores float64, gpus int32) (float64, bool) {
+	model, ok := m.Model(node)
+	if !ok {
+		return 0, false
+	}
+	current := m.snapshot.Load().utilization[node.Name]
+	next := current
+	if cores := node.Status.Allocatable.Cpu().AsApproximateFloat64(); cores > 0 {
+		next.cpu = math.Min(1, current.cpu+cpuCores/cores)
+	}
+	gpuAllocatable := node.Status.Allocatable["nvidia.com/gpu"]
+	if allocatable := gpuAllocatable.AsApproximateFloat64(); allocatable > 0 {
+		next.gpu = math.Min(1, current.gpu+float64(gpus)/allocatable)
+	}
+	return model.Predict(next.cpu, next.gpu) - model.Predict(current.cpu, current.gpu), true
+}
+
+// Refresh samples the power and utilization of every measured node and refits the model
+// of each class
+func (m *NodePowerModels) Refresh(ctx context.Context) error {
+	var nodes corev1.NodeList
+	if err := m.reader.List(ctx, &nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var pods corev1.PodList
+	if err := m.reader.List(ctx, &pods); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	usage, err := m.usage.PodUsage(ctx, m.interval)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]PodResourceUsage)
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		podUsage := usage[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]
+		nodeUsage := used[pod.Spec.NodeName]
+		nodeUsage.CPUCores += podUsage.CPUCores
+		nodeUsage.GPUs += podUsage.GPUs
+		used[pod.Spec.NodeName] = nodeUsage
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	utilization := make(map[string]nodeUtilization, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		var current nodeUtilization
+		if cores := node.Status.Allocatable.Cpu().AsApproximateFloat64(); cores > 0 {
+			current.cpu = math.Min(1, used[node.Name].CPUCores/cores)
+		}
+		gpuAllocatable := node.Status.Allocatable["nvidia.com/gpu"]
+		if gpus := gpuAllocatable.AsApproximateFloat64(); gpus > 0 {
+			current.gpu = math.Min(1, used[node.Name].GPUs/gpus)
+		}
+		utilization[node.Name] = current
+
+		watts, ok := m.power.NodePower(node.Name)
+		if !ok {
+			continue
+		}
+		class := NodeClass(node)
+		samples := append(m.samples[class], powerSample{cpu: current.cpu, gpu: current.gpu, watts: watts})
+		if len(samples) > powerModelSamples {
+			samples = samples[len(samples)-powerModelSamples:]
+		}
+		m.samples[class] = samples
+	}
+
+	models := make(map[string]PowerModel, len(m.samples))
+	for class, samples := range m.samples {
+		if model, ok := fitPowerModel(samples); ok {
+			models[class] = model
+		}
+	}
+	m.snapshot.Store(&powerModelSnapshot{models: models, utilization: utilization})
+	log.FromContext(ctx).V(1).Info("Fitted node power models", "classes", len(models))
+	return nil
+}
+
+// fitPowerModel fits idle power and the CPU and GPU slopes to the samples by least squares.
+// The GPU slope is fitted only when GPU utilization varies, and no model is fitted without
+// enough samples spread over CPU utilization or when a term comes out negative.
+func fitPowerModel(samples []powerSample) (PowerModel, bool) {
+	if len(samples) < minPowerModelSamples {
+		return PowerModel{}, false
+	}
+	cpuMin, cpuMax, gpuMin, gpuMax := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, s := range samples {
+		cpuMin, cpuMax = math.Min(cpuMin, s.cpu), math.Max(cpuMax, s.cpu)
+		gpuMin, gpuMax = math.Min(gpuMin, s.gpu), math.Max(gpuMax, s.gpu)
+	}
+	if cpuMax-cpuMin < minUtilizationSpread {
+		return PowerModel{}, false
+	}
+	terms := 2
+	if gpuMax-gpuMin >= minUtilizationSpread {
+		terms = 3
+	}
+
+	// Normal equations of watts = idle + cpu*CPU + gpu*GPU
+	var a [3][4]float64
+	for _, s := range samples {
+		x := [3]float64{1, s.cpu, s.gpu}
+		for i := range terms {
+			for j := range terms {
+				a[i][j] += x[i] * x[j]
+			}
+			a[i][3] += x[i] * s.watts
+		}
+	}
+	coefficients, ok := solveLinear(a, terms)
+	if !ok {
+		return PowerModel{}, false
+	}
+	model := PowerModel{Idle: coefficients[0], CPU: coefficients[1], GPU: coefficients[2], Samples: len(samples)}
+	if model.Idle < 0 || model.CPU < 0 || model.GPU < 0 {
+		return PowerModel{}, false
+	}
+	return model, true
+}
+
+// solveLinear solves the first n rows of the augmented system by Gaussian elimination with
+// partial pivoting, false when it is singular
+func solveLinear(a [3][4]float64, n int) ([3]float64, bool) {
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-9 {
+			return [3]float64{}, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for row := range n {
+			if row == col {
+				continue
+			}
+			factor := a[row][col] / a[col][col]
+			for k := col; k < 4; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+		}
+	}
+	var x [3]float64
+	for i := range n {
+		x[i] = a[i][3] / a[i][i]
+	}
+	return x, true
+}
+
+// Start samples nodes every interval until the context is cancelled
+func (m *NodePowerModels) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("power-model")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		if err := m.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to sample node power for power models")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection samples on every replica, as every replica scores nodes
+func (m *NodePowerModels) NeedLeaderElection() bool {
+	return false
+}
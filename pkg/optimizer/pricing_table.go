@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"math"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// GPUModelPricing is a pricing provider that prices GPUs by the model installed on a node
+type GPUModelPricing interface {
+	// GPUModelAdjustment returns what gpus GPUs of the node's model cost over the default
+	// GPU rate per hour, negative for cheaper models
+	GPUModelAdjustment(node *corev1.Node, gpus int32) float64
+}
+
+// EnergyPricing is a pricing provider that charges for the energy workloads draw
+type EnergyPricing interface {
+	// EnergyCost returns the cost per hour of drawing the given power in Watts
+	EnergyCost(powerWatts float64) float64
+}
+
+// pricingRates are the rates of a pricing table
+type pricingRates struct {
+	calculator     CostCalculator
+	gpuModelLabel  string
+	gpuModels      map[string]float64
+	nodePrices     []kcloudv1alpha1.NodeLabelPrice
+	costPerKWh     float64
+	commitments    []kcloudv1alpha1.Commitment
+	storageClasses map[string]float64
+}
+
+// TablePricing prices resources at the rates of the PricingTable in effect, and the default
+// rates without one. It prices nodes matching the table's node prices and GPUs by model, and
+// charges for energy at the table's electricity tariff. It is safe for concurrent use while
+// the table is replaced.
+type TablePricing struct {
+	rates atomic.Pointer[pricingRates]
+}
+
+// NewTablePricing returns pricing at the default rates until a table is applied
+func NewTablePricing() *TablePricing {
+	p := &TablePricing{}
+	p.Apply(nil)
+	return p
+}
+
+// Apply replaces the rates with the table's; nil restores the defaults
+func (p *TablePricing) Apply(spec *kcloudv1alpha1.PricingTableSpec) {
+	rates := &pricingRates{calculator: *NewCostCalculator()}
+	if spec != nil {
+		applyResourceRates(&rates.calculator, &spec.ResourceCostPolicy)
+		if spec.BaseCostPerHour != nil {
+			rates.calculator.BaseInfrastructureCostPerHour = *spec.BaseCostPerHour
+		}
+		rates.gpuModelLabel = spec.GPUModelLabel
+		if rates.gpuModelLabel == "" {
+			rates.gpuModelLabel = kcloudv1alpha1.DefaultGPUModelLabel
+		}
+		rates.gpuModels = make(map[string]float64, len(spec.GPUModels))
+		for _, model := range spec.GPUModels {
+			rates.gpuModels[model.Model] = model.CostPerHour
+		}
+		rates.nodePrices = spec.NodePrices
+		if spec.ElectricityTariff != nil {
+			rates.costPerKWh = spec.ElectricityTariff.CostPerKWh
+		}
+		rates.commitments = spec.Commitments
+		if spec.Storage != nil {
+			if spec.Storage.CostPerGiBMonth != nil {
+				rates.calculator.StorageCostPerGiBMonth = *spec.Storage.CostPerGiBMonth
+			}
+			rates.storageClasses = make(map[string]float64, len(spec.Storage.StorageClasses))
+			for _, class := range spec.Storage.StorageClasses {
+				rates.storageClasses[class.StorageClassName] = class.CostPerGiBMonth
+			}
+		}
+		if spec.Network != nil && spec.Network.EgressCostPerGB != nil {
+			rates.calculator.EgressCostPerGB = *spec.Network.EgressCostPerGB
+		}
+	}
+	p.rates.Store(rates)
+}
+
+// withResourceRates returns a copy of the pricing with the given per-resource rates in place
+// of the table's
+func (p *TablePricing) withResourceRates(prices *kcloudv1alpha1.ResourceCostPolicy) *TablePricing {
+	rates := *p.rates.Load()
+	applyResourceRates(&rates.calculator, prices)
+	priced := &TablePricing{}
+	priced.rates.Store(&rates)
+	return priced
+}
+
+// Calculator returns the per-resource rates in effect
+func (p *TablePricing) Calculator() *CostCalculator {
+	calculator := p.rates.Load().calculator
+	return &calculator
+}
+
+// CalculateCost prices resources at the table's per-resource rates
+func (p *TablePricing) CalculateCost(cpuCores, memoryGB float64, gpuCount, npuCount int32) float64 {
+	calculator := p.rates.Load().calculator
+	return calculator.CalculateCost(cpuCores, memoryGB, gpuCount, npuCount)
+}
+
+// GPUModelAdjustment returns the difference between the rate of the node's GPU model and the
+// table's GPU rate for gpus GPUs, zero for models the table does not price
+func (p *TablePricing) GPUModelAdjustment(node *corev1.Node, gpus int32) float64 {
+	rates := p.rates.Load()
+	if node == nil || gpus == 0 {
+		return 0
+	}
+	rate, ok := rates.gpuModels[node.Labels[rates.gpuModelLabel]]
+	if !ok {
+		return 0
+	}
+	return float64(gpus) * (rate - rates.calculator.GPUCostPerHour)
+}
+
+// NodePrice returns the price of the first of the table's node prices selecting the node
+func (p *TablePricing) NodePrice(node *corev1.Node) (float64, bool) {
+	for _, price := range p.rates.Load().nodePrices {
+		if labels.SelectorFromSet(price.NodeSelector).Matches(labels.Set(node.Labels)) {
+			return nodeLabelCostPerHour(price), true
+		}
+	}
+	return 0, false
+}
+
+// nodeLabelCostPerHour returns the hourly price of a node price entry, amortizing the
+// hardware's capital cost over its utilized lifetime when the entry sets it
+func nodeLabelCostPerHour(price kcloudv1alpha1.NodeLabelPrice) float64 {
+	amortization := price.Amortization
+	if amortization == nil {
+		return price.CostPerHour
+	}
+	utilization := 1.0
+	if amortization.Utilization != nil && *amortization.Utilization > 0 {
+		utilization = math.Min(*amortization.Utilization, 1)
+	}
+	capital := amortization.PurchasePrice
+	if amortization.ResidualValue != nil {
+		capital = max(capital-*amortization.ResidualValue, 0)
+	}
+	cost := capital / (float64(max(amortization.LifetimeMonths, 1)) * HoursPerMonth * utilization)
+	if amortization.OperatingCostPerHour != nil {
+		cost += *amortization.OperatingCostPerHour
+	}
+	return cost
+}
+
+// Commitments returns the table's reserved instances, savings plans and committed use discounts
+func (p *TablePricing) Commitments() []kcloudv1alpha1.Commitment {
+	return p.rates.Load().commitments
+}
+
+// StorageCost prices gib GiB of persistent volume at the storage class's rate, or the
+// table's default storage rate for classes it does not list
+func (p *TablePricing) StorageCost(gib float64, storageClass string) float64 {
+	rates := p.rates.Load()
+	if rate, ok := rates.storageClasses[storageClass]; ok {
+		return gib * rate / HoursPerMonth
+	}
+	return rates.calculator.StorageCost(gib, storageClass)
+}
+
+// EgressCost prices internet traffic at the table's egress rate
+func (p *TablePricing) EgressCost(gb float64) float64 {
+	calculator := p.rates.Load().calculator
+	return calculator.EgressCost(gb)
+}
+
+// EnergyCost charges the power at the table's electricity tariff
+func (p *TablePricing) EnergyCost(powerWatts float64) float64 {
+	return powerWatts / 1000 * p.rates.Load().costPerKWh
+}
+
+// NodePricingChain prices a node from the first pricing that knows its price, such as a
+// pricing table's node prices before a cloud price list
+type NodePricingChain []NodePricing
+
+// NodePrice returns the node's price from the first pricing that knows it
+func (c NodePricingChain) NodePrice(node *corev1.Node) (float64, bool) {
+	for _, pricing := range c {
+		if price, ok := pricing.NodePrice(node); ok {
+			return price, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// Overcommit is how far a workload type's limits may exceed its requests
+type Overcommit struct {
+	CPU    float64
+	Memory float64
+}
+
+// DefaultOvercommit is the limit over request ratio per workload type. Request-serving
+// workloads are latency-sensitive and get Guaranteed pods; training and batch tolerate CPU
+// contention, and batch also some memory pressure, so their requests are overcommitted.
+var DefaultOvercommit = map[string]Overcommit{
+	"inference": {CPU: 1, Memory: 1},
+	"serving":   {CPU: 1, Memory: 1},
+	"training":  {CPU: 1.5, Memory: 1},
+	"batch":     {CPU: 2, Memory: 1.25},
+}
+
+// QoSShape is the request and limit shape recommended for a workload's containers
+type QoSShape struct {
+	Class            corev1.PodQOSClass
+	Requests         kcloudv1alpha1.RecommendedResources
+	Limits           kcloudv1alpha1.RecommendedResources
+	CPUOvercommit    float64
+	MemoryOvercommit float64
+	// Confidence is 1 for Guaranteed shapes, otherwise that of the rightsizing usage the
+	// requests keep room for, and 0 without it
+	Confidence float64
+	Reason     string
+}
+
+// RecommendQoS shapes the workload's requests and limits by its type's DefaultOvercommit. Limits stay at the
+// workload's resources; requests are the limits over the type's overcommit, but never below
+// the rightsizing recommendation, if any. GPUs cannot be overcommitted.
+func RecommendQoS(wo *kcloudv1alpha1.WorkloadOptimizer) QoSShape {
+	resources := wo.Spec.Resources
+	ratio, ok := DefaultOvercommit[wo.Spec.WorkloadType]
+	if !ok {
+		ratio = Overcommit{CPU: 1, Memory: 1}
+	}
+	shape := QoSShape{
+		Requests:         kcloudv1alpha1.RecommendedResources{CPU: resources.CPU, Memory: resources.Memory, GPU: resources.GPU},
+		Limits:           kcloudv1alpha1.RecommendedResources{CPU: resources.CPU, Memory: resources.Memory, GPU: resources.GPU},
+		CPUOvercommit:    1,
+		MemoryOvercommit: 1,
+	}
+
+	var rightsized kcloudv1alpha1.RecommendedResources
+	if wo.Status.Rightsizing != nil {
+		rightsized = wo.Status.Rightsizing.Recommended
+	}
+	if cores := quantityValue(resources.CPU); cores > 0 && ratio.CPU > 1 {
+		milliCores := int64(math.Ceil(max(cores/ratio.CPU, quantityValue(rightsized.CPU)) * 1000))
+		if milliCores < int64(math.Round(cores*1000)) {
+			shape.Requests.CPU = fmt.Sprintf("%dm", milliCores)
+			shape.CPUOvercommit = math.Round(cores*1000/float64(milliCores)*100) / 100
+		}
+	}
+	if bytes := quantityValue(resources.Memory); bytes > 0 && ratio.Memory > 1 {
+		mebibytes := int64(math.Ceil(max(bytes/ratio.Memory, quantityValue(rightsized.Memory)) / (1 << 20)))
+		if mebibytes < int64(math.Round(bytes/(1<<20))) {
+			shape.Requests.Memory = fmt.Sprintf("%dMi", mebibytes)
+			shape.MemoryOvercommit = math.Round(bytes/(1<<20)/float64(mebibytes)*100) / 100
+		}
+	}
+
+	if shape.CPUOvercommit == 1 && shape.MemoryOvercommit == 1 {
+		shape.Class = corev1.PodQOSGuaranteed
+		shape.Confidence = 1
+		shape.Reason = fmt.Sprintf("%s workloads get requests equal to limits", wo.Spec.WorkloadType)
+		if ratio.CPU > 1 || ratio.Memory > 1 {
+			shape.Reason = fmt.Sprintf("rightsized usage leaves no room to overcommit this %s workload", wo.Spec.WorkloadType)
+		}
+		return shape
+	}
+	shape.Class = corev1.PodQOSBurstable
+	if wo.Status.Rightsizing != nil {
+		shape.Confidence = wo.Status.Rightsizing.Confidence
+	}
+	shape.Reason = fmt.Sprintf("%s workloads overcommit CPU %.2fx and memory %.2fx", wo.Spec.WorkloadType,
+		shape.CPUOvercommit, shape.MemoryOvercommit)
+	return shape
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// CPUPackagePowerAnnotation is the node annotation the node agent publishes the node's CPU
+	// package power in, in whole watts, estimated from RAPL counters
+	CPUPackagePowerAnnotation = "kcloud.io/cpu-package-power"
+	// CPUPackagePowerTimeAnnotation is when the node agent last published the power, in RFC 3339
+	CPUPackagePowerTimeAnnotation = "kcloud.io/cpu-package-power-time"
+
+	// RAPLHeartbeat is how often the node agent publishes unchanged power, so readings older
+	// than raplExpiry are from an agent that stopped
+	RAPLHeartbeat = 10 * time.Minute
+	raplExpiry    = 3 * RAPLHeartbeat
+
+	// raplRefresh is how often published power is read from nodes
+	raplRefresh = time.Minute
+)
+
+// RAPLCounter is the cumulative energy of a node's CPU packages in microjoules
+type RAPLCounter struct {
+	// Zones are the energy of each package zone, by its powercap directory
+	Zones map[string]RAPLZone
+	Time  time.Time
+}
+
+// RAPLZone is the energy counter of one RAPL package zone
+type RAPLZone struct {
+	EnergyUJ uint64
+	// MaxEnergyUJ is where the counter wraps to zero
+	MaxEnergyUJ uint64
+}
+
+// ReadRAPLCounter reads the energy counters of the CPU package zones under the powercap
+// root, such as /sys/class/powercap
+func ReadRAPLCounter(root string, now time.Time) (RAPLCounter, error) {
+	dirs, err := raplPackageZones(root)
+	if err != nil {
+		return RAPLCounter{}, err
+	}
+	counter := RAPLCounter{Zones: map[string]RAPLZone{}, Time: now}
+	for _, dir := range dirs {
+		energy, err := readUint(filepath.Join(dir, "energy_uj"))
+		if err != nil {
+			return RAPLCounter{}, err
+		}
+		maxEnergy, err := readUint(filepath.Join(dir, "max_energy_range_uj"))
+		if err != nil {
+			return RAPLCounter{}, err
+		}
+		counter.Zones[filepath.Base(dir)] = RAPLZone{EnergyUJ: energy, MaxEnergyUJ: maxEnergy}
+	}
+	if len(counter.Zones) == 0 {
+		return RAPLCounter{}, fmt.Errorf("no rapl package zones under %s", root)
+	}
+	return counter, nil
+}
+
+// raplPackageZones returns the directories of the CPU package zones under the powercap root
+func raplPackageZones(root string) ([]string, error) {
+	dirs, err := filepath.Glob(filepath.Join(root, "intel-rapl:*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rapl zones: %w", err)
+	}
+	var packages []string
+	for _, dir := range dirs {
+		// Subzones such as intel-rapl:0:0 are parts of a package
+		if strings.Count(filepath.Base(dir), ":") != 1 {
+			continue
+		}
+		name, err := os.ReadFile(filepath.Join(dir, "name"))
+		if err != nil || !strings.HasPrefix(strings.TrimSpace(string(name)), "package") {
+			continue
+		}
+		packages = append(packages, dir)
+	}
+	return packages, nil
+}
+
+// readUint reads a sysfs file holding an unsigned integer
+func readUint(path string) (uint64, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return value, nil
+}
+
+// RAPLPackagePower returns the average power in watts the CPU packages drew between two
+// counter readings, allowing for counters that wrapped once
+func RAPLPackagePower(previous, current RAPLCounter) (float64, bool) {
+	seconds := current.Time.Sub(previous.Time).Seconds()
+	if seconds <= 0 {
+		return 0, false
+	}
+	var microjoules float64
+	for name, zone := range current.Zones {
+		before, ok := previous.Zones[name]
+		if !ok {
+			return 0, false
+		}
+		if zone.EnergyUJ >= before.EnergyUJ {
+			microjoules += float64(zone.EnergyUJ - before.EnergyUJ)
+		} else {
+			microjoules += float64(zone.MaxEnergyUJ - before.EnergyUJ + zone.EnergyUJ)
+		}
+	}
+	return microjoules / 1e6 / seconds, true
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// RAPLPower reads the CPU package power node agents publish from RAPL counters on plain
+// Linux hosts. It covers only the CPU packages, so it is the last resort for nodes without
+// BMC or Kepler readings.
+type RAPLPower struct {
+	reader client.Reader
+	clock  clock.PassiveClock
+
+	nodes atomic.Pointer[map[string]raplReading]
+}
+
+// raplReading is the power a node agent published, and when
+type raplReading struct {
+	watts       float64
+	publishedAt time.Time
+}
+
+// NewRAPLPower returns the CPU package power published on the nodes c lists
+func NewRAPLPower(c client.Reader) *RAPLPower {
+	return &RAPLPower{reader: c, clock: clock.RealClock{}}
+}
+
+// SetClock replaces the clock publication ages are measured with
+func (r *RAPLPower) SetClock(c clock.PassiveClock) {
+	r.clock = c
+}
+
+// NodePower returns the CPU package power the node's agent last published, unless it has
+// stopped publishing
+func (r *RAPLPower) NodePower(node string) (float64, bool) {
+	nodes := r.nodes.Load()
+	if nodes == nil {
+		return 0, false
+	}
+	reading, ok := (*nodes)[node]
+	if !ok || r.clock.Since(reading.publishedAt) > raplExpiry {
+		return 0, false
+	}
+	return reading.watts, true
+}
+
+// Refresh reads the power published on every node
+func (r *RAPLPower) Refresh(ctx context.Context) error {
+	var list corev1.NodeList
+	if err := r.reader.List(ctx, &list); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	nodes := make(map[string]raplReading)
+	for _, node := range list.Items {
+		watts, err := strconv.ParseFloat(node.Annotations[CPUPackagePowerAnnotation], 64)
+		if err != nil || watts < 0 {
+			continue
+		}
+		published, err := time.Parse(time.RFC3339, node.Annotations[CPUPackagePowerTimeAnnotation])
+		if err != nil {
+			continue
+		}
+		nodes[node.Name] = raplReading{watts: watts, publishedAt: published}
+	}
+	r.nodes.Store(&nodes)
+	return nil
+}
+
+// Start reads published power every minute until the context is cancelled
+func (r *RAPLPower) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("rapl-power")
+
+	ticker := time.NewTicker(raplRefresh)
+	defer ticker.Stop()
+	for {
+		if err := r.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read published RAPL power")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reads published power on every replica, as every replica scores nodes
+func (r *RAPLPower) NeedLeaderElection() bool {
+	return false
+}
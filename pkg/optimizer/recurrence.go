@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"time"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// DefaultRecurrenceLeadTime is how long before a recurring run its placement is computed
+const DefaultRecurrenceLeadTime = 5 * time.Minute
+
+// RecurrenceLeadTime returns the precompute lead time of the schedule
+func RecurrenceLeadTime(spec *kcloudv1alpha1.RecurrenceSpec) time.Duration {
+	if spec.LeadTime == nil || spec.LeadTime.Duration <= 0 {
+		return DefaultRecurrenceLeadTime
+	}
+	return spec.LeadTime.Duration
+}
+
+// NextRun returns the first scheduled run at or after now
+func NextRun(spec *kcloudv1alpha1.RecurrenceSpec, now time.Time) time.Time {
+	start := spec.StartTime.Time
+	interval := spec.Interval.Duration
+	if !now.After(start) || interval <= 0 {
+		return start
+	}
+
+	elapsed := now.Sub(start)
+	runs := elapsed / interval
+	if elapsed%interval != 0 {
+		runs++
+	}
+	return start.Add(runs * interval)
+}
+
+// PrecomputeDue returns the next run and whether now falls in the lead window before it
+func PrecomputeDue(spec *kcloudv1alpha1.RecurrenceSpec, now time.Time) (time.Time, bool) {
+	next := NextRun(spec, now)
+	return next, !now.Before(next.Add(-RecurrenceLeadTime(spec)))
+}
+
+// PlacementValid reports whether a precomputed placement can still be used at now
+func PlacementValid(placement *kcloudv1alpha1.PrecomputedPlacement, now time.Time) bool {
+	return placement != nil && placement.NodeName != "" && now.Before(placement.ValidUntil.Time)
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultRenewableForecastInterval is how often the renewable generation forecast is read
+	DefaultRenewableForecastInterval = 15 * time.Minute
+
+	// DefaultRenewableMaxDelay is how long a renewable preference holds a workload at most
+	DefaultRenewableMaxDelay = 12 * time.Hour
+)
+
+// RenewablePoint is the on-site renewable generation from a point in time until the next
+// point, measured for past points and forecast for future ones
+type RenewablePoint struct {
+	Time time.Time `json:"time"`
+	// Generation is in kW
+	Generation float64 `json:"generation"`
+}
+
+// RenewableForecastSource reads the on-site renewable generation forecast
+type RenewableForecastSource interface {
+	RenewableForecast(ctx context.Context) ([]RenewablePoint, error)
+}
+
+// RenewableForecastFeed reads the generation forecast from an HTTP endpoint, such as an
+// exporter of a solar forecast API, as a JSON array of RenewablePoint or as CSV rows of an
+// RFC 3339 time and kW with an optional header
+type RenewableForecastFeed struct {
+	url    string
+	client *http.Client
+}
+
+// NewRenewableForecastFeed returns a renewable forecast source reading the feed at feedURL
+func NewRenewableForecastFeed(feedURL string) (*RenewableForecastFeed, error) {
+	parsed, err := url.Parse(feedURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid renewable forecast feed url %q", feedURL)
+	}
+	return &RenewableForecastFeed{url: feedURL, client: &http.Client{Timeout: defaultGridFeedTimeout}}, nil
+}
+
+// RenewableForecast reads the feed
+func (f *RenewableForecastFeed) RenewableForecast(ctx context.Context) ([]RenewablePoint, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build renewable forecast feed request: %w", err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read renewable forecast feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("renewable forecast feed returned status %d", resp.StatusCode)
+	}
+	return ParseRenewableForecast(resp.Body)
+}
+
+// ParseRenewableForecast parses a JSON array of RenewablePoint, or CSV rows of time and kW
+func ParseRenewableForecast(r io.Reader) ([]RenewablePoint, error) {
+	body := bufio.NewReader(r)
+	first, err := firstNonSpace(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read renewable forecast: %w", err)
+	}
+	if first == '[' {
+		var points []RenewablePoint
+		if err := json.NewDecoder(body).Decode(&points); err != nil {
+			return nil, fmt.Errorf("failed to decode renewable forecast: %w", err)
+		}
+		return points, nil
+	}
+
+	rows, err := csv.NewReader(body).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read renewable forecast csv: %w", err)
+	}
+	points := make([]RenewablePoint, 0, len(rows))
+	for i, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("renewable forecast row %d has %d columns, expected time and generation", i+1, len(row))
+		}
+		at, err := time.Parse(time.RFC3339, strings.TrimSpace(row[0]))
+		if err != nil {
+			if i == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("invalid time in renewable forecast row %d: %w", i+1, err)
+		}
+		generation, err := strconv.ParseFloat(strings.TrimSpace(row[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid generation in renewable forecast row %d: %w", i+1, err)
+		}
+		points = append(points, RenewablePoint{Time: at, Generation: generation})
+	}
+	return points, nil
+}
+
+// firstNonSpace returns the first byte of the reader that is not white space, leaving it unread
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err == io.EOF {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		if _, err := r.Discard(1); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// RenewableForecast keeps the generation forecast read from a source every interval, and
+// answers what generation is now and when it next covers a demand
+type RenewableForecast struct {
+	source   RenewableForecastSource
+	interval time.Duration
+	clock    clock.PassiveClock
+
+	mu     sync.RWMutex
+	points []RenewablePoint
+}
+
+// NewRenewableForecast returns a forecast reading the source every interval
+func NewRenewableForecast(source RenewableForecastSource, interval time.Duration) *RenewableForecast {
+	if interval <= 0 {
+		interval = DefaultRenewableForecastInterval
+	}
+	return &RenewableForecast{source: source, interval: interval, clock: clock.RealClock{}}
+}
+
+// SetClock replaces the clock the current point is found with
+func (f *RenewableForecast) SetClock(c clock.PassiveClock) {
+	f.clock = c
+}
+
+// Observe replaces the forecast with the points
+func (f *RenewableForecast) Observe(points []RenewablePoint) {
+	sorted := append([]RenewablePoint(nil), points...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.points = sorted
+}
+
+// Current returns the generation from the latest point that has started, unless it is more
+// than two hours old
+func (f *RenewableForecast) Current() (float64, bool) {
+	now := f.clock.Now()
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for i := len(f.points) - 1; i >= 0; i-- {
+		point := f.points[i]
+		if point.Time.After(now) {
+			continue
+		}
+		if now.Sub(point.Time) > gridSignalMaxAge {
+			return 0, false
+		}
+		return point.Generation, true
+	}
+	return 0, false
+}
+
+// NextAtLeast returns when the forecast next has generation of at least kw
+func (f *RenewableForecast) NextAtLeast(kw float64) (time.Time, bool) {
+	now := f.clock.Now()
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, point := range f.points {
+		if point.Time.After(now) && point.Generation >= kw {
+			return point.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Refresh reads the source
+func (f *RenewableForecast) Refresh(ctx context.Context) error {
+	points, err := f.source.RenewableForecast(ctx)
+	if err != nil {
+		return err
+	}
+	f.Observe(points)
+	return nil
+}
+
+// Start reads the source every interval until the context is cancelled; a failed read keeps
+// the previous forecast
+func (f *RenewableForecast) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("renewable-forecast")
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		if err := f.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read renewable generation forecast")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reads the feed on every replica, as every replica reconciles workloads
+func (f *RenewableForecast) NeedLeaderElection() bool {
+	return false
+}
+
+// RenewableFlexible reports whether a renewable preference may hold the workload: batch and
+// training workloads, which can start later
+func RenewableFlexible(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	return wo.Spec.WorkloadType == "batch" || wo.Spec.WorkloadType == "training"
+}
+
+// RenewableLatestStart returns when a workload held since waitingSince must start: after
+// maxDelay, or earlier with the time-shift safety margin to meet its deadline
+func RenewableLatestStart(wo *kcloudv1alpha1.WorkloadOptimizer, waitingSince time.Time, maxDelay time.Duration) time.Time {
+	latest := waitingSince.Add(maxDelay)
+	if deadline := wo.Spec.Deadline; deadline != nil {
+		start := deadline.CompleteBy.Add(-DefaultTimeShiftSafetyMargin)
+		if deadline.ExpectedDuration != nil {
+			start = start.Add(-time.Duration(float64(deadline.ExpectedDuration.Duration) * (1 + deadlineMargin)))
+		}
+		if start.Before(latest) {
+			latest = start
+		}
+	}
+	return latest
+}
+
+// DecideRenewableWindow decides whether a workload held for renewable supply may start now:
+// when forecast generation covers the demand in kW, when its latest start has come, or when
+// generation is unknown or never forecast to cover it, so the workload is not held blind
+func DecideRenewableWindow(forecast *RenewableForecast, demandKW float64, latestStart time.Time,
+	now time.Time) TimeShiftDecision {
+	decision := TimeShiftDecision{LatestStart: &latestStart}
+	if next, ok := forecast.NextAtLeast(demandKW); ok {
+		decision.NextWindow = &next
+	}
+
+	if !now.Before(latestStart) {
+		decision.Run = true
+		decision.Reason = "latest start has come"
+		return decision
+	}
+	generation, ok := forecast.Current()
+	if !ok {
+		decision.Run = true
+		decision.Reason = "current renewable generation is unknown"
+		return decision
+	}
+	decision.Signal = &generation
+	if generation >= demandKW {
+		decision.Run = true
+		decision.Reason = fmt.Sprintf("renewable generation %.1f kW covers demand %.1f kW", generation, demandKW)
+		return decision
+	}
+	if decision.NextWindow == nil || !decision.NextWindow.Before(latestStart) {
+		decision.Run = true
+		decision.Reason = fmt.Sprintf("renewable generation %.1f kW is not forecast to cover demand %.1f kW before the latest start",
+			generation, demandKW)
+		return decision
+	}
+	decision.Reason = fmt.Sprintf("renewable generation %.1f kW is below demand %.1f kW", generation, demandKW)
+	return decision
+}
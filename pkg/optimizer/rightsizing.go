@@ -0,0 +1,312 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+)
+
+// Rightsizing defaults
+const (
+	DefaultRightsizingWindow   = 7 * 24 * time.Hour
+	DefaultRightsizingHeadroom = 0.15
+	DefaultRightsizingInterval = time.Hour
+
+	// rightsizingQuantile is the usage percentile requests are sized for
+	rightsizingQuantile = 0.95
+)
+
+// UsagePercentile is a usage percentile of the busiest of a workload's pods
+type UsagePercentile struct {
+	CPUCores float64
+	MemoryGB float64
+	// GPUs is GPU utilization in whole GPUs, so 1.5 is one and a half fully busy GPUs
+	GPUs float64
+	// Samples is how many usage samples the window holds for the longest-running pod
+	Samples int
+	// Variation is the largest coefficient of variation of a pod's CPU usage over the window
+	Variation float64
+	// Found is false when the source has no samples for the pods
+	Found bool
+}
+
+// UsageSource reports historical resource usage of pods
+type UsageSource interface {
+	UsagePercentile(ctx context.Context, namespace string, pods []string, quantile float64,
+		window time.Duration) (UsagePercentile, error)
+}
+
+// defaultPrometheusTimeout bounds a single Prometheus query
+const defaultPrometheusTimeout = 30 * time.Second
+
+// prometheusStep is the resolution usage is sampled at within the window
+const (
+	prometheusStep         = "5m"
+	prometheusStepDuration = 5 * time.Minute
+)
+
+// PrometheusUsageSource queries container usage from cAdvisor metrics and GPU utilization
+// from the DCGM exporter through the Prometheus HTTP API
+type PrometheusUsageSource struct {
+	// LatencyMetric is the request duration histogram RequestLatency reads; empty uses
+	// DefaultSLALatencyMetric
+	LatencyMetric string
+
+	url    string
+	client *http.Client
+}
+
+// NewPrometheusUsageSource returns a usage source for the Prometheus server at baseURL
+func NewPrometheusUsageSource(baseURL string) (*PrometheusUsageSource, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid prometheus url %q", baseURL)
+	}
+	return &PrometheusUsageSource{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{Timeout: defaultPrometheusTimeout},
+	}, nil
+}
+
+// UsagePercentile returns the given quantile of per-pod usage over the window, taking the
+// busiest pod so every replica fits the recommendation
+func (p *PrometheusUsageSource) UsagePercentile(ctx context.Context, namespace string, pods []string,
+	quantile float64, window time.Duration) (UsagePercentile, error) {
+	if len(pods) == 0 {
+		return UsagePercentile{}, nil
+	}
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = regexp.QuoteMeta(pod)
+	}
+	selector := fmt.Sprintf(`namespace=%q,pod=~%q`, namespace, strings.Join(names, "|"))
+	rangeSelector := fmt.Sprintf("[%ds:%s]", int64(window.Seconds()), prometheusStep)
+	percentile := func(perPod string) string {
+		return fmt.Sprintf("max(quantile_over_time(%g, (%s)%s))", quantile, perPod, rangeSelector)
+	}
+	cpuUsage := fmt.Sprintf(`sum by (pod) (rate(container_cpu_usage_seconds_total{%s,container!=""}[%s]))`,
+		selector, prometheusStep)
+
+	var usage UsagePercentile
+	cpu, found, err := p.query(ctx, percentile(cpuUsage))
+	if err != nil {
+		return usage, fmt.Errorf("failed to query cpu usage: %w", err)
+	}
+	usage.CPUCores, usage.Found = cpu, found
+
+	samples, _, err := p.query(ctx, fmt.Sprintf("max(count_over_time((%s)%s))", cpuUsage, rangeSelector))
+	if err != nil {
+		return usage, fmt.Errorf("failed to query cpu sample count: %w", err)
+	}
+	usage.Samples = int(samples)
+
+	spread, _, err := p.query(ctx, fmt.Sprintf("max(stddev_over_time((%[1]s)%[2]s) / avg_over_time((%[1]s)%[2]s))",
+		cpuUsage, rangeSelector))
+	if err != nil {
+		return usage, fmt.Errorf("failed to query cpu usage variation: %w", err)
+	}
+	usage.Variation = spread
+
+	memory, found, err := p.query(ctx, percentile(fmt.Sprintf(
+		`sum by (pod) (container_memory_working_set_bytes{%s,container!=""})`, selector)))
+	if err != nil {
+		return usage, fmt.Errorf("failed to query memory usage: %w", err)
+	}
+	usage.MemoryGB = memory / (1 << 30)
+	usage.Found = usage.Found && found
+
+	// Pods without GPUs have no DCGM series, which is zero GPU usage
+	gpus, _, err := p.query(ctx, percentile(fmt.Sprintf(
+		`sum by (pod) (DCGM_FI_DEV_GPU_UTIL{%s}) / 100`, selector)))
+	if err != nil {
+		return usage, fmt.Errorf("failed to query gpu utilization: %w", err)
+	}
+	usage.GPUs = gpus
+	return usage, nil
+}
+
+// prometheusResponse is the subset of an instant query response the source reads
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Value []any `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// query runs an instant query returning a single scalar; found is false for an empty result
+func (p *PrometheusUsageSource) query(ctx context.Context, query string) (float64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		p.url+"/api/v1/query?"+url.Values{"query": {query}}.Encode(), nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to build prometheus request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, false, fmt.Errorf("failed to decode prometheus response (status %d): %w", resp.StatusCode, err)
+	}
+	if body.Status != "success" {
+		return 0, false, fmt.Errorf("prometheus query failed (status %d): %s", resp.StatusCode, body.Error)
+	}
+	if len(body.Data.Result) == 0 || len(body.Data.Result[0].Value) != 2 {
+		return 0, false, nil
+	}
+	raw, ok := body.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, false, fmt.Errorf("unexpected prometheus sample %v", body.Data.Result[0].Value[1])
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse prometheus sample %q: %w", raw, err)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false, nil
+	}
+	return value, true, nil
+}
+
+// RightsizingRecommendation is per-pod requests sized from observed usage
+type RightsizingRecommendation struct {
+	Usage       UsagePercentile
+	Current     kcloudv1alpha1.RecommendedResources
+	Recommended kcloudv1alpha1.RecommendedResources
+	// MonthlySavings is the current minus the recommended monthly cost per pod, in the
+	// reporting currency
+	MonthlySavings float64
+	Window         time.Duration
+	// Confidence is how far the usage history can be trusted, from 0 to 1
+	Confidence float64
+}
+
+// Rightsizer recommends requests from the P95 usage of a workload's pods plus headroom
+type Rightsizer struct {
+	Source UsageSource
+	Costs  *CostCalculator
+	// Currency converts savings into the reporting currency; nil reports in US dollars
+	Currency *currency.Converter
+	// Window is how much usage history recommendations are based on
+	Window time.Duration
+	// Headroom is the fraction added on top of P95 usage
+	Headroom float64
+	// Interval is how often a workload's usage is queried
+	Interval time.Duration
+}
+
+// NewRightsizer returns a rightsizer with the default window, headroom and interval
+func NewRightsizer(source UsageSource) *Rightsizer {
+	return &Rightsizer{
+		Source:   source,
+		Costs:    NewCostCalculator(),
+		Window:   DefaultRightsizingWindow,
+		Headroom: DefaultRightsizingHeadroom,
+		Interval: DefaultRightsizingInterval,
+	}
+}
+
+// Due reports whether a recommendation computed at last should be refreshed
+func (r *Rightsizer) Due(last *kcloudv1alpha1.Rightsizing, now time.Time) bool {
+	return last == nil || now.Sub(last.ComputedAt.Time) >= r.Interval
+}
+
+// Recommend sizes the workload's requests from its pods' usage. It returns nil when the
+// workload has no pods or the source has no samples for them.
+func (r *Rightsizer) Recommend(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	pods []corev1.Pod) (*RightsizingRecommendation, error) {
+	if len(pods) == 0 {
+		return nil, nil
+	}
+	names := make([]string, len(pods))
+	for i := range pods {
+		names[i] = pods[i].Name
+	}
+	usage, err := r.Source.UsagePercentile(ctx, wo.Namespace, names, rightsizingQuantile, r.Window)
+	if err != nil {
+		return nil, err
+	}
+	if !usage.Found {
+		return nil, nil
+	}
+
+	requested := wo.Spec.Resources
+	scale := 1 + r.Headroom
+	milliCores := max(int64(math.Ceil(usage.CPUCores*scale*1000)), 1)
+	mebibytes := max(int64(math.Ceil(usage.MemoryGB*scale*1024)), 1)
+	recommended := kcloudv1alpha1.RecommendedResources{
+		CPU:    fmt.Sprintf("%dm", milliCores),
+		Memory: fmt.Sprintf("%dMi", mebibytes),
+	}
+	// Only whole GPUs are rightsized, and a GPU workload keeps at least one
+	if requested.GPU > 0 {
+		recommended.GPU = max(int32(math.Ceil(usage.GPUs*scale)), 1)
+	}
+
+	currentCPU, currentMemory := quantityValue(requested.CPU), quantityValue(requested.Memory)/(1<<30)
+	current := r.Costs.CalculateMonthlyCost(currentCPU, currentMemory, requested.GPU, requested.NPU)
+	proposed := r.Costs.CalculateMonthlyCost(float64(milliCores)/1000, float64(mebibytes)/1024,
+		recommended.GPU, requested.NPU)
+
+	return &RightsizingRecommendation{
+		Usage: usage,
+		Current: kcloudv1alpha1.RecommendedResources{
+			CPU:    requested.CPU,
+			Memory: requested.Memory,
+			GPU:    requested.GPU,
+		},
+		Recommended:    recommended,
+		MonthlySavings: r.Currency.Currency().Round(r.Currency.FromUSD(current - proposed)),
+		Window:         r.Window,
+		Confidence:     usageConfidence(usage, r.Window),
+	}, nil
+}
+
+// usageConfidence rates usage by how much of the window its samples cover and how much
+// CPU usage varied
+func usageConfidence(usage UsagePercentile, window time.Duration) float64 {
+	return Confidence(float64(usage.Samples), float64(window/prometheusStepDuration), usage.Variation)
+}
+
+// quantityValue parses a resource quantity, treating invalid values as zero
+func quantityValue(value string) float64 {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0
+	}
+	return quantity.AsApproximateFloat64()
+}
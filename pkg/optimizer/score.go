@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// constraintPenaltyRate is the score lost per unit a constraint is exceeded by, relative to the limit
+const constraintPenaltyRate = 0.5
+
+// OptimizationScoreWeights weigh the components of a workload's optimization score
+type OptimizationScoreWeights struct {
+	Cost        float64
+	Power       float64
+	Utilization float64
+}
+
+// DefaultOptimizationScoreWeights are used when the engine has no weights configured
+var DefaultOptimizationScoreWeights = OptimizationScoreWeights{Cost: 0.4, Power: 0.3, Utilization: 0.3}
+
+// Validate checks that the weights are non-negative and not all zero
+func (w OptimizationScoreWeights) Validate() error {
+	if w.Cost < 0 || w.Power < 0 || w.Utilization < 0 {
+		return fmt.Errorf("optimization score weights cannot be negative")
+	}
+	if w.Cost+w.Power+w.Utilization <= 0 {
+		return fmt.Errorf("at least one optimization score weight must be positive")
+	}
+	return nil
+}
+
+// ParseOptimizationScoreWeights parses weights written as "cost=0.4,power=0.3,utilization=0.3";
+// components that are omitted get no weight
+func ParseOptimizationScoreWeights(spec string) (OptimizationScoreWeights, error) {
+	var weights OptimizationScoreWeights
+	for _, pair := range strings.Split(spec, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return weights, fmt.Errorf("invalid optimization score weight %q, expected name=value", pair)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return weights, fmt.Errorf("invalid optimization score weight for %s: %w", name, err)
+		}
+		switch name {
+		case "cost":
+			weights.Cost = weight
+		case "power":
+			weights.Power = weight
+		case "utilization":
+			weights.Utilization = weight
+		default:
+			return weights, fmt.Errorf("unknown optimization score component %q", name)
+		}
+	}
+	return weights, weights.Validate()
+}
+
+// ScoreBreakdown explains an optimization score. The score is the weighted average of the
+// cost, power and utilization components, each between 0 and 1, less the constraint penalty.
+type ScoreBreakdown struct {
+	// Cost is 1 within the cost limit and falls as the limit is exceeded
+	Cost float64
+	// Power is 1 within the power and carbon limits and falls as either is exceeded
+	Power float64
+	// Utilization is the share of the requested CPU and memory the workload uses at P95,
+	// or 1 before usage has been measured
+	Utilization float64
+	// ConstraintPenalty is subtracted for each limit exceeded, in proportion to the excess
+	ConstraintPenalty float64
+	Weights           OptimizationScoreWeights
+}
+
+// scoreWeights returns the configured weights or the defaults
+func (e *Engine) scoreWeights() OptimizationScoreWeights {
+	if e.ScoreWeights != nil {
+		return *e.ScoreWeights
+	}
+	return DefaultOptimizationScoreWeights
+}
+
+// calculateScore scores the result and explains the score
+func (e *Engine) calculateScore(wo *kcloudv1alpha1.WorkloadOptimizer, result *OptimizationResult) (float64, ScoreBreakdown) {
+	breakdown := ScoreBreakdown{Cost: 1, Power: 1, Utilization: 1, Weights: e.scoreWeights()}
+	if wo.Spec.CostConstraints != nil {
+		breakdown.Cost, breakdown.ConstraintPenalty = limitScore(result.EstimatedCost, wo.Spec.CostConstraints.MaxCostPerHour)
+	}
+	if wo.Spec.PowerConstraints != nil {
+		score, penalty := limitScore(result.EstimatedPower, wo.Spec.PowerConstraints.MaxPowerUsage)
+		if limit := wo.Spec.PowerConstraints.MaxCarbonPerHour; limit != nil {
+			carbonScore, carbonPenalty := limitScore(CarbonGramsPerHour(result.CarbonFootprint), *limit)
+			score = math.Min(score, carbonScore)
+			penalty += carbonPenalty
+		}
+		breakdown.Power = score
+		breakdown.ConstraintPenalty += penalty
+	}
+	if usage := wo.Status.Rightsizing; usage != nil {
+		breakdown.Utilization = requestUtilization(usage.CPUP95, e.parseCPU(wo.Spec.Resources.CPU),
+			usage.MemoryP95, e.parseMemory(wo.Spec.Resources.Memory))
+	}
+
+	w := breakdown.Weights
+	score := (w.Cost*breakdown.Cost + w.Power*breakdown.Power + w.Utilization*breakdown.Utilization) /
+		(w.Cost + w.Power + w.Utilization)
+	score = math.Max(score-breakdown.ConstraintPenalty, 0)
+
+	breakdown.Cost = roundScore(breakdown.Cost)
+	breakdown.Power = roundScore(breakdown.Power)
+	breakdown.Utilization = roundScore(breakdown.Utilization)
+	breakdown.ConstraintPenalty = roundScore(breakdown.ConstraintPenalty)
+	return roundScore(score), breakdown
+}
+
+// limitScore scores a value against its limit: 1 within the limit, otherwise limit/value,
+// with a penalty in proportion to the excess
+func limitScore(value, limit float64) (float64, float64) {
+	if limit <= 0 || value <= limit {
+		return 1, 0
+	}
+	ratio := value / limit
+	return 1 / ratio, (ratio - 1) * constraintPenaltyRate
+}
+
+// requestUtilization averages the used share of the CPU and memory requests, capped at 1
+func requestUtilization(cpuUsed, cpuRequested, memoryUsed, memoryRequested float64) float64 {
+	var total float64
+	var count int
+	for _, pair := range [][2]float64{{cpuUsed, cpuRequested}, {memoryUsed, memoryRequested}} {
+		if pair[1] <= 0 {
+			continue
+		}
+		total += math.Min(pair[0]/pair[1], 1)
+		count++
+	}
+	if count == 0 {
+		return 1
+	}
+	return total / float64(count)
+}
+
+// roundScore rounds a score to two decimals
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// DryRunAnnotation set to "true" on a WorkloadOptimizer makes the operator simulate it and
+// report the outcome in status instead of optimizing it
+const DryRunAnnotation = "kcloud.io/dry-run"
+
+// DryRun reports whether the workload only asks for a simulation
+func DryRun(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	return wo.Annotations[DryRunAnnotation] == "true"
+}
+
+// ClusterStateSource supplies the nodes and node pool energy profiles simulations run against
+type ClusterStateSource interface {
+	ClusterState(ctx context.Context) ([]corev1.Node, []kcloudv1alpha1.NodePowerProfile, error)
+}
+
+// PlacementPreview is where a workload would be placed, without reserving anything
+type PlacementPreview struct {
+	// Node is empty when no node can run the workload
+	Node   string
+	Reason string
+}
+
+// Placer previews placements without recording them
+type Placer interface {
+	PreviewPlacement(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node) (PlacementPreview, error)
+}
+
+// ErrSimulationUnavailable is returned when the engine has no cluster state or placer
+var ErrSimulationUnavailable = errors.New("simulation requires a cluster state source and a placer")
+
+// clientClusterState reads cluster state through a controller-runtime client
+type clientClusterState struct {
+	client client.Client
+}
+
+// NewClientClusterState returns a cluster state source that lists nodes and node power profiles
+func NewClientClusterState(c client.Client) ClusterStateSource {
+	return &clientClusterState{client: c}
+}
+
+func (s *clientClusterState) ClusterState(ctx context.Context) ([]corev1.Node, []kcloudv1alpha1.NodePowerProfile, error) {
+	var nodes corev1.NodeList
+	if err := s.client.List(ctx, &nodes); err != nil {
+		return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var profiles kcloudv1alpha1.NodePowerProfileList
+	if err := s.client.List(ctx, &profiles); err != nil {
+		return nil, nil, fmt.Errorf("failed to list node power profiles: %w", err)
+	}
+	return nodes.Items, profiles.Items, nil
+}
+
+// SimulationResult is the what-if evaluation of a workload against the current cluster
+type SimulationResult struct {
+	Feasible bool `json:"feasible"`
+	// Node is the node the workload would likely be placed on
+	Node string `json:"node,omitempty"`
+	// Reason explains the placement, or why no node can run the workload
+	Reason              string  `json:"reason"`
+	EstimatedCost       float64 `json:"estimatedCost"`
+	EstimatedPower      float64 `json:"estimatedPower"`
+	RenewableShare      float64 `json:"renewableShare"`
+	CarbonFootprint     float64 `json:"carbonFootprint"`
+	Score               float64 `json:"score"`
+	RecommendedReplicas int32   `json:"recommendedReplicas"`
+	// ParetoFront holds the best non-dominated options when Pareto optimization is enabled
+	ParetoFront []ParetoOption `json:"paretoFront,omitempty"`
+	SimulatedAt time.Time      `json:"simulatedAt"`
+}
+
+// Simulate evaluates the cost, power, feasibility and likely node of a workload against the
+// current cluster state. Nothing is created or reserved.
+func (e *Engine) Simulate(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer) (*SimulationResult, error) {
+	if e.Cluster == nil || e.Placer == nil {
+		return nil, ErrSimulationUnavailable
+	}
+	nodes, profiles, err := e.Cluster.ClusterState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	optimized := e.Optimize(ctx, &WorkloadState{
+		WorkloadOptimizer: wo,
+		AvailableNodes:    nodes,
+		EnergyProfiles:    profiles,
+	})
+	preview, err := e.Placer.PreviewPlacement(ctx, wo, nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview placement: %w", err)
+	}
+
+	result := &SimulationResult{
+		Feasible:            preview.Node != "",
+		Node:                preview.Node,
+		Reason:              preview.Reason,
+		EstimatedCost:       optimized.EstimatedCost,
+		EstimatedPower:      optimized.EstimatedPower,
+		RenewableShare:      optimized.RenewableShare,
+		CarbonFootprint:     optimized.CarbonFootprint,
+		Score:               optimized.Score,
+		RecommendedReplicas: optimized.RecommendedReplicas,
+		ParetoFront:         optimized.ParetoFront,
+		SimulatedAt:         time.Now(),
+	}
+	// A hypothetical workload has no pods yet, so account carbon on the node it would get
+	for _, node := range nodes {
+		if node.Name == preview.Node {
+			account := AccountCarbon(optimized.EstimatedPower, ProfileForNode(profiles, node))
+			result.RenewableShare = account.RenewableShare
+			result.CarbonFootprint = account.CarbonFootprint
+			break
+		}
+	}
+	return result, nil
+}
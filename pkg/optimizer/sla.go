@@ -0,0 +1,406 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultSLALatencyMetric is the request duration histogram inference latency is read from
+	DefaultSLALatencyMetric = "http_request_duration_seconds"
+	// DefaultSLAWindow is the latency history each observation covers
+	DefaultSLAWindow = 15 * time.Minute
+
+	// slaMaxUtilization is the busiest replicas may run before they are treated as saturated
+	slaMaxUtilization = 0.9
+	// slaQuantile is the request latency percentile predicted against the SLA
+	slaQuantile = 0.99
+	// slaIdleUtilization is the load below which observed latency is taken as the unloaded latency
+	slaIdleUtilization = 0.05
+	// slaSmoothing is the weight of a new observation in a learned profile
+	slaSmoothing = 0.3
+	// slaDemandSamples is how many observed request rates are kept per workload, and how
+	// many give a replica count full confidence
+	slaDemandSamples = 12
+)
+
+// SLAProfile is how one replica of a workload serves requests on a node class
+type SLAProfile struct {
+	// BaseLatency is the P99 latency of an unloaded replica
+	BaseLatency time.Duration
+	// Capacity is the request rate, per second, at which one replica saturates, the
+	// service rate of the queueing model
+	Capacity float64
+}
+
+// DefaultSLAProfiles are the profiles by accelerator kind before any latency is observed
+var DefaultSLAProfiles = map[string]SLAProfile{
+	"gpu": {BaseLatency: 30 * time.Millisecond, Capacity: 200},
+	"npu": {BaseLatency: 40 * time.Millisecond, Capacity: 150},
+	"cpu": {BaseLatency: 120 * time.Millisecond, Capacity: 25},
+}
+
+// SLAPrediction is the expected latency and capacity of replicas on a node class
+type SLAPrediction struct {
+	NodeClass string
+	Replicas  int32
+	// LatencyP99 is zero when the replicas saturate
+	LatencyP99 time.Duration
+	// Capacity is the request rate the replicas sustain, per second
+	Capacity float64
+	Met      bool
+	// Reason explains a predicted violation
+	Reason string
+	// Confidence is how far the observed demand behind the prediction can be trusted, from 0
+	// to 1; zero before any demand is observed
+	Confidence float64
+}
+
+// LatencySample is the request latency and rate of a group of pods over a window
+type LatencySample struct {
+	LatencyP99  time.Duration
+	RequestRate float64
+	// Found is false when the source has no samples for the pods
+	Found bool
+}
+
+// LatencySource reports historical request latency of pods
+type LatencySource interface {
+	RequestLatency(ctx context.Context, namespace string, pods []string, window time.Duration) (LatencySample, error)
+}
+
+// slaKey identifies a learned profile
+type slaKey struct {
+	workload  string
+	nodeClass string
+}
+
+// SLAModel predicts the latency of inference workloads per node class. Profiles start from
+// DefaultSLAProfiles and are refined from latency observed on each node class.
+type SLAModel struct {
+	// Source reports observed latency; nil predicts from the default profiles only
+	Source LatencySource
+	// Window is the latency history each observation covers
+	Window time.Duration
+
+	mu       sync.RWMutex
+	profiles map[slaKey]SLAProfile
+	// demand is the last observed request rates of each workload across its replicas,
+	// oldest first
+	demand map[string][]float64
+}
+
+// NewSLAModel returns a model refined from the source's latency, if any
+func NewSLAModel(source LatencySource) *SLAModel {
+	return &SLAModel{
+		Source:   source,
+		Window:   DefaultSLAWindow,
+		profiles: make(map[slaKey]SLAProfile),
+		demand:   make(map[string][]float64),
+	}
+}
+
+// NodeClass groups nodes expected to serve requests alike: the node's instance type, or its
+// accelerator kind when the instance type is not labeled
+func NodeClass(node *corev1.Node) string {
+	if instanceType := node.Labels[corev1.LabelInstanceTypeStable]; instanceType != "" {
+		return instanceType
+	}
+	return acceleratorKind(node)
+}
+
+// acceleratorKind returns gpu, npu or cpu by the accelerators the node offers
+func acceleratorKind(node *corev1.Node) string {
+	if gpus := node.Status.Allocatable["nvidia.com/gpu"]; !gpus.IsZero() {
+		return "gpu"
+	}
+	if npus := node.Status.Allocatable["npu.com/npu"]; !npus.IsZero() {
+		return "npu"
+	}
+	return "cpu"
+}
+
+// workloadKind returns the accelerator kind the workload requests, the class of a
+// workload not yet placed
+func workloadKind(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	switch {
+	case wo.Spec.Resources.GPU > 0:
+		return "gpu"
+	case wo.Spec.Resources.NPU > 0:
+		return "npu"
+	default:
+		return "cpu"
+	}
+}
+
+// workloadKey identifies the workload's learned profiles and demand
+func workloadKey(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	return wo.Namespace + "/" + wo.Name
+}
+
+// replicaRange returns the replica counts the workload may scale between
+func replicaRange(wo *kcloudv1alpha1.WorkloadOptimizer) (int32, int32) {
+	minReplicas, maxReplicas := int32(1), int32(1)
+	if wo.Spec.AutoScaling != nil {
+		minReplicas = max(wo.Spec.AutoScaling.MinReplicas, 1)
+		maxReplicas = max(wo.Spec.AutoScaling.MaxReplicas, minReplicas)
+	}
+	return minReplicas, maxReplicas
+}
+
+// profile returns the learned profile of the workload on the node's class, or the default
+// profile of the node's accelerator kind. A nil node stands for the kind the workload requests.
+func (m *SLAModel) profile(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) (SLAProfile, string) {
+	kind := workloadKind(wo)
+	class := kind
+	if node != nil {
+		class, kind = NodeClass(node), acceleratorKind(node)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if profile, ok := m.profiles[slaKey{workload: workloadKey(wo), nodeClass: class}]; ok {
+		return profile, class
+	}
+	return DefaultSLAProfiles[kind], class
+}
+
+// load returns the request rate the workload must serve: its throughput target, or the
+// observed demand when that is higher
+func (m *SLAModel) load(wo *kcloudv1alpha1.WorkloadOptimizer) float64 {
+	var load float64
+	m.mu.RLock()
+	if rates := m.demand[workloadKey(wo)]; len(rates) > 0 {
+		load = rates[len(rates)-1]
+	}
+	m.mu.RUnlock()
+	if sla := wo.Spec.SLA; sla != nil && sla.Throughput != nil {
+		load = max(load, *sla.Throughput)
+	}
+	return load
+}
+
+// Predict returns the expected latency of the given replicas on the node. The replicas are
+// modeled as an M/M/c queue sharing the load: a request waits for a free replica, then is
+// served in the unloaded latency.
+func (m *SLAModel) Predict(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, replicas int32) SLAPrediction {
+	profile, class := m.profile(wo, node)
+	replicas = max(replicas, 1)
+	prediction := SLAPrediction{
+		NodeClass: class,
+		Replicas:  replicas,
+		Capacity:  float64(replicas) * profile.Capacity * slaMaxUtilization,
+		Met:       true,
+	}
+	load := m.load(wo)
+	if load >= prediction.Capacity {
+		prediction.Met = false
+		prediction.Reason = fmt.Sprintf("%d replicas on %s sustain %.0f requests/s, %.0f needed",
+			replicas, class, prediction.Capacity, load)
+		return prediction
+	}
+	prediction.LatencyP99 = profile.BaseLatency + queueingDelay(replicas, profile.Capacity, load)
+	if sla := wo.Spec.SLA; sla != nil && sla.LatencyP99 != nil && prediction.LatencyP99 > sla.LatencyP99.Duration {
+		prediction.Met = false
+		prediction.Reason = fmt.Sprintf("predicted p99 latency %s on %s exceeds %s",
+			prediction.LatencyP99.Round(time.Millisecond), class, sla.LatencyP99.Duration)
+	}
+	return prediction
+}
+
+// Plan returns the prediction for the fewest replicas in the workload's autoscaling range
+// that meet its SLA on the node, or for the most replicas when none does. Its confidence
+// grows with the number of demand observations and shrinks with their spread.
+func (m *SLAModel) Plan(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) SLAPrediction {
+	minReplicas, maxReplicas := replicaRange(wo)
+	for replicas := minReplicas; ; replicas++ {
+		if prediction := m.Predict(wo, node, replicas); prediction.Met || replicas >= maxReplicas {
+			m.mu.RLock()
+			rates := m.demand[workloadKey(wo)]
+			prediction.Confidence = Confidence(float64(len(rates)), slaDemandSamples, variation(rates))
+			m.mu.RUnlock()
+			return prediction
+		}
+	}
+}
+
+// Refresh queries the latency of the workload's running pods per node class and refines
+// the workload's profiles and demand from it. A refresh without samples records no demand.
+func (m *SLAModel) Refresh(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	pods []corev1.Pod, nodes []corev1.Node) error {
+	if m.Source == nil || wo.Spec.SLA == nil {
+		return nil
+	}
+	byName := make(map[string]*corev1.Node, len(nodes))
+	for i := range nodes {
+		byName[nodes[i].Name] = &nodes[i]
+	}
+	groups := make(map[string][]string)
+	classNodes := make(map[string]*corev1.Node)
+	for _, pod := range pods {
+		node, ok := byName[pod.Spec.NodeName]
+		if !ok || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		class := NodeClass(node)
+		groups[class] = append(groups[class], pod.Name)
+		classNodes[class] = node
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var demand float64
+	var observed bool
+	for class, names := range groups {
+		sample, err := m.Source.RequestLatency(ctx, wo.Namespace, names, m.Window)
+		if err != nil {
+			return fmt.Errorf("failed to query latency on %s: %w", class, err)
+		}
+		if !sample.Found {
+			continue
+		}
+		demand += sample.RequestRate
+		observed = true
+		m.observe(wo, classNodes[class], len(names), sample)
+	}
+	if !observed {
+		return nil
+	}
+	m.mu.Lock()
+	rates := append(m.demand[workloadKey(wo)], demand)
+	m.demand[workloadKey(wo)] = rates[max(len(rates)-slaDemandSamples, 0):]
+	m.mu.Unlock()
+	return nil
+}
+
+// observe refines the workload's profile on the node's class from a latency sample of the
+// given number of replicas. Lightly loaded replicas reveal the unloaded latency; busier
+// ones reveal the rate at which a replica saturates, as the capacity whose queueing delay
+// explains the latency above the unloaded one.
+func (m *SLAModel) observe(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, replicas int, sample LatencySample) {
+	profile, class := m.profile(wo, node)
+	if sample.LatencyP99 <= 0 || replicas == 0 {
+		return
+	}
+	delay := sample.LatencyP99 - profile.BaseLatency
+	if perReplica := sample.RequestRate / float64(replicas); float64(delay) < slaIdleUtilization*float64(sample.LatencyP99) {
+		profile.BaseLatency = time.Duration(smooth(float64(profile.BaseLatency), float64(sample.LatencyP99)))
+	} else if perReplica > 0 {
+		capacity := serviceCapacity(int32(replicas), sample.RequestRate, delay)
+		profile.Capacity = smooth(profile.Capacity, max(capacity, perReplica/slaMaxUtilization))
+	}
+	m.mu.Lock()
+	m.profiles[slaKey{workload: workloadKey(wo), nodeClass: class}] = profile
+	m.mu.Unlock()
+}
+
+// erlangC returns the probability that a request waits for a free server in an M/M/c queue
+// of the given servers and offered load, the arrival rate over the service rate of one
+// server. The load must be below the number of servers.
+func erlangC(servers int32, offered float64) float64 {
+	// Erlang B by its recurrence, which stays stable for many servers
+	blocking := 1.0
+	for k := 1; k <= int(servers); k++ {
+		blocking = offered * blocking / (float64(k) + offered*blocking)
+	}
+	utilization := offered / float64(servers)
+	return blocking / (1 - utilization*(1-blocking))
+}
+
+// queueingDelay returns the slaQuantile of the time a request waits for one of the replicas,
+// each serving capacity requests per second, at the load. In an M/M/c queue the wait exceeds
+// t with probability ErlangC * exp(-(c*capacity - load) * t).
+func queueingDelay(replicas int32, capacity, load float64) time.Duration {
+	if load <= 0 {
+		return 0
+	}
+	wait := erlangC(replicas, load/capacity)
+	if wait <= 1-slaQuantile {
+		return 0
+	}
+	seconds := math.Log(wait/(1-slaQuantile)) / (float64(replicas)*capacity - load)
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// serviceCapacity returns the per-replica capacity at which the replicas make requests wait
+// the given delay at the load, inverting queueingDelay by bisection
+func serviceCapacity(replicas int32, load float64, delay time.Duration) float64 {
+	low := load / float64(replicas)
+	high := 2 * low
+	for queueingDelay(replicas, high, load) > delay {
+		low, high = high, 2*high
+	}
+	for range 50 {
+		middle := (low + high) / 2
+		if queueingDelay(replicas, middle, load) > delay {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return high
+}
+
+// smooth moves a learned value toward an observation
+func smooth(value, observed float64) float64 {
+	return value + slaSmoothing*(observed-value)
+}
+
+// RequestLatency returns the P99 latency and request rate of the pods over the window from
+// the latency histogram
+func (p *PrometheusUsageSource) RequestLatency(ctx context.Context, namespace string, pods []string,
+	window time.Duration) (LatencySample, error) {
+	if len(pods) == 0 {
+		return LatencySample{}, nil
+	}
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = regexp.QuoteMeta(pod)
+	}
+	selector := fmt.Sprintf(`namespace=%q,pod=~%q`, namespace, strings.Join(names, "|"))
+	rangeSelector := fmt.Sprintf("[%ds]", int64(window.Seconds()))
+	metric := p.LatencyMetric
+	if metric == "" {
+		metric = DefaultSLALatencyMetric
+	}
+
+	var sample LatencySample
+	latency, found, err := p.query(ctx, fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(%s_bucket{%s}%s)))`,
+		metric, selector, rangeSelector))
+	if err != nil {
+		return sample, fmt.Errorf("failed to query request latency: %w", err)
+	}
+	sample.LatencyP99, sample.Found = time.Duration(latency*float64(time.Second)), found
+
+	rate, _, err := p.query(ctx, fmt.Sprintf(`sum(rate(%s_count{%s}%s))`, metric, selector, rangeSelector))
+	if err != nil {
+		return sample, fmt.Errorf("failed to query request rate: %w", err)
+	}
+	sample.RequestRate = rate
+	return sample, nil
+}
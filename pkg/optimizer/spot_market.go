@@ -0,0 +1,265 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultSpotPriceInterval is how often the spot price feed is read
+	DefaultSpotPriceInterval = 5 * time.Minute
+	// DefaultSpotPriceWindow is the price history volatility is measured over
+	DefaultSpotPriceWindow = 24 * time.Hour
+
+	// spotVolatilityCeiling is the price volatility, the standard deviation over the mean, at
+	// which interruption is taken as certain
+	spotVolatilityCeiling = 0.5
+	// defaultSpotFeedTimeout bounds reading the spot price feed
+	defaultSpotFeedTimeout = 30 * time.Second
+)
+
+// SpotPrice is the price of spot capacity of an instance type in a zone
+type SpotPrice struct {
+	InstanceType string  `json:"instanceType"`
+	Zone         string  `json:"zone"`
+	Price        float64 `json:"price"`
+	// Time is when the price took effect; zero is when it was read
+	Time time.Time `json:"time,omitempty"`
+	// InterruptionRate is the share of instances the provider reports interrupting, if known
+	InterruptionRate *float64 `json:"interruptionRate,omitempty"`
+}
+
+// SpotQuote is the current spot price of a node and the risk of running on it
+type SpotQuote struct {
+	// Price is the latest hourly price in USD
+	Price float64
+	// Volatility is the standard deviation of the price over the window relative to its mean
+	Volatility float64
+	// InterruptionRisk is the chance, from 0 to 1, that the node is reclaimed: the reported
+	// interruption rate or, when higher, the risk the price volatility suggests
+	InterruptionRisk float64
+}
+
+// SpotPriceSource reads current spot prices
+type SpotPriceSource interface {
+	SpotPrices(ctx context.Context) ([]SpotPrice, error)
+}
+
+// SpotPriceFeed reads spot prices as a JSON array of SpotPrice from an HTTP endpoint, such as
+// an exporter of a cloud provider's spot price history
+type SpotPriceFeed struct {
+	url    string
+	client *http.Client
+}
+
+// NewSpotPriceFeed returns a spot price source reading the feed at feedURL
+func NewSpotPriceFeed(feedURL string) (*SpotPriceFeed, error) {
+	parsed, err := url.Parse(feedURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid spot price feed url %q", feedURL)
+	}
+	return &SpotPriceFeed{url: feedURL, client: &http.Client{Timeout: defaultSpotFeedTimeout}}, nil
+}
+
+// SpotPrices reads the feed
+func (f *SpotPriceFeed) SpotPrices(ctx context.Context) ([]SpotPrice, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build spot price feed request: %w", err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spot price feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("spot price feed returned status %d", resp.StatusCode)
+	}
+	var prices []SpotPrice
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return nil, fmt.Errorf("failed to decode spot price feed: %w", err)
+	}
+	return prices, nil
+}
+
+// spotKey identifies spot capacity of an instance type in a zone
+type spotKey struct {
+	instanceType string
+	zone         string
+}
+
+// spotSample is a spot price at a time
+type spotSample struct {
+	price float64
+	time  time.Time
+}
+
+// spotHistory is the price history of spot capacity and its last reported interruption rate
+type spotHistory struct {
+	samples          []spotSample
+	interruptionRate float64
+}
+
+// SpotMarket keeps the recent spot price history of each instance type and zone, read from a
+// source every interval, and quotes nodes their current price and interruption risk
+type SpotMarket struct {
+	source   SpotPriceSource
+	interval time.Duration
+	window   time.Duration
+	clock    clock.PassiveClock
+
+	mu      sync.RWMutex
+	history map[spotKey]*spotHistory
+}
+
+// NewSpotMarket returns a spot market reading the source every interval and measuring
+// volatility over the window
+func NewSpotMarket(source SpotPriceSource, interval, window time.Duration) *SpotMarket {
+	if interval <= 0 {
+		interval = DefaultSpotPriceInterval
+	}
+	if window <= 0 {
+		window = DefaultSpotPriceWindow
+	}
+	return &SpotMarket{
+		source:   source,
+		interval: interval,
+		window:   window,
+		clock:    clock.RealClock{},
+		history:  make(map[spotKey]*spotHistory),
+	}
+}
+
+// SetClock replaces the clock sample ages are measured with
+func (m *SpotMarket) SetClock(c clock.PassiveClock) {
+	m.clock = c
+}
+
+// Observe records prices, dropping samples older than the window
+func (m *SpotMarket) Observe(prices []SpotPrice) {
+	now := m.clock.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, price := range prices {
+		if price.InstanceType == "" || price.Price <= 0 {
+			continue
+		}
+		key := spotKey{instanceType: price.InstanceType, zone: price.Zone}
+		history := m.history[key]
+		if history == nil {
+			history = &spotHistory{}
+			m.history[key] = history
+		}
+		at := price.Time
+		if at.IsZero() || at.After(now) {
+			at = now
+		}
+		if n := len(history.samples); n == 0 || at.After(history.samples[n-1].time) {
+			history.samples = append(history.samples, spotSample{price: price.Price, time: at})
+		}
+		if price.InterruptionRate != nil {
+			history.interruptionRate = math.Max(0, math.Min(1, *price.InterruptionRate))
+		}
+	}
+	for key, history := range m.history {
+		// Keep the latest sample however old so the current price stays known
+		first := 0
+		for first < len(history.samples)-1 && now.Sub(history.samples[first].time) > m.window {
+			first++
+		}
+		history.samples = history.samples[first:]
+		if len(history.samples) == 0 {
+			delete(m.history, key)
+		}
+	}
+}
+
+// Quote returns the spot price and interruption risk of the node's instance type in its zone
+func (m *SpotMarket) Quote(node *corev1.Node) (SpotQuote, bool) {
+	key := spotKey{instanceType: NodeInstanceType(node), zone: NodeZone(node)}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	history, ok := m.history[key]
+	if !ok || key.instanceType == "" {
+		return SpotQuote{}, false
+	}
+
+	var sum, squares float64
+	for _, sample := range history.samples {
+		sum += sample.price
+		squares += sample.price * sample.price
+	}
+	n := float64(len(history.samples))
+	mean := sum / n
+	quote := SpotQuote{Price: history.samples[len(history.samples)-1].price}
+	if n > 1 && mean > 0 {
+		quote.Volatility = math.Sqrt(math.Max(0, squares/n-mean*mean)) / mean
+	}
+	quote.InterruptionRisk = math.Max(history.interruptionRate, math.Min(1, quote.Volatility/spotVolatilityCeiling))
+	return quote, true
+}
+
+// NodePrice returns the node's current spot price
+func (m *SpotMarket) NodePrice(node *corev1.Node) (float64, bool) {
+	quote, ok := m.Quote(node)
+	return quote.Price, ok
+}
+
+// Refresh reads the source and records its prices
+func (m *SpotMarket) Refresh(ctx context.Context) error {
+	prices, err := m.source.SpotPrices(ctx)
+	if err != nil {
+		return err
+	}
+	m.Observe(prices)
+	return nil
+}
+
+// Start reads the source every interval until the context is cancelled
+func (m *SpotMarket) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("spot-market")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		if err := m.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read spot prices")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reads prices on every replica, as every replica schedules
+func (m *SpotMarket) NeedLeaderElection() bool {
+	return false
+}
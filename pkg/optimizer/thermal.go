@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultCPUThermalLimit and DefaultGPUThermalLimit are the temperatures in °C at which
+	// CPUs and GPUs are assumed to start throttling
+	DefaultCPUThermalLimit = 90.0
+	DefaultGPUThermalLimit = 83.0
+
+	// DefaultThermalMargin is how far in °C below its limit a node stops taking heavy workloads
+	DefaultThermalMargin = 10.0
+
+	// RackLabel is the node label naming the rack a node is in
+	RackLabel = "kcloud.io/rack"
+	// topologyRackLabel is the rack label some topology tooling sets instead
+	topologyRackLabel = "topology.kubernetes.io/rack"
+
+	// thermalWindow is how far back temperatures are taken at their peak, thermalRefresh how
+	// often they are read, and thermalExpiry how long they are used while reads fail
+	thermalWindow  = 2 * time.Minute
+	thermalRefresh = time.Minute
+	thermalExpiry  = 5 * thermalRefresh
+)
+
+// NodeTemperature is the hottest sensor of a node's CPUs and of its GPUs in °C; zero when
+// not measured
+type NodeTemperature struct {
+	CPU float64
+	GPU float64
+}
+
+// NodeTemperatureSource reports the measured temperature of nodes
+type NodeTemperatureSource interface {
+	// NodeTemperature returns the node's temperature and whether it is known
+	NodeTemperature(node string) (NodeTemperature, bool)
+}
+
+// ThermalLimits are the temperatures CPUs and GPUs throttle at, and how far below them a
+// node counts as nearing its limit
+type ThermalLimits struct {
+	CPU    float64
+	GPU    float64
+	Margin float64
+}
+
+// DefaultThermalLimits returns the default CPU and GPU limits and margin
+func DefaultThermalLimits() ThermalLimits {
+	return ThermalLimits{CPU: DefaultCPUThermalLimit, GPU: DefaultGPUThermalLimit, Margin: DefaultThermalMargin}
+}
+
+// Headroom returns how far in °C the hotter of the node's CPUs and GPUs, relative to its
+// limit, is below that limit
+func (l ThermalLimits) Headroom(t NodeTemperature) float64 {
+	headroom := math.Inf(1)
+	if t.CPU > 0 {
+		headroom = l.CPU - t.CPU
+	}
+	if t.GPU > 0 {
+		headroom = math.Min(headroom, l.GPU-t.GPU)
+	}
+	return headroom
+}
+
+// NodeRack returns the rack the node's labels place it in, or "" when unlabeled
+func NodeRack(node *corev1.Node) string {
+	if rack := node.Labels[RackLabel]; rack != "" {
+		return rack
+	}
+	return node.Labels[topologyRackLabel]
+}
+
+// thermalSnapshot is the temperature of every node measured, and when it was read
+type thermalSnapshot struct {
+	nodes  map[string]NodeTemperature
+	readAt time.Time
+}
+
+// PrometheusTemperature reads node temperatures from Prometheus: CPU and other board sensors
+// from node exporter's hwmon metrics and GPU temperatures from the DCGM exporter. Series must
+// carry the node name in their instance label, as for Kepler.
+type PrometheusTemperature struct {
+	prometheus *PrometheusUsageSource
+	clock      clock.PassiveClock
+
+	snapshot atomic.Pointer[thermalSnapshot]
+}
+
+// NewPrometheusTemperature returns a node temperature source reading from prometheus
+func NewPrometheusTemperature(prometheus *PrometheusUsageSource) *PrometheusTemperature {
+	return &PrometheusTemperature{prometheus: prometheus, clock: clock.RealClock{}}
+}
+
+// SetClock replaces the clock measurement ages are measured with
+func (p *PrometheusTemperature) SetClock(c clock.PassiveClock) {
+	p.clock = c
+}
+
+// NodeTemperature returns the node's peak temperature from the last read, unless it has
+// expired
+func (p *PrometheusTemperature) NodeTemperature(node string) (NodeTemperature, bool) {
+	snapshot := p.snapshot.Load()
+	if snapshot == nil || p.clock.Since(snapshot.readAt) > thermalExpiry {
+		return NodeTemperature{}, false
+	}
+	temperature, ok := snapshot.nodes[node]
+	return temperature, ok
+}
+
+// Refresh reads the peak temperature of every node's hottest CPU and GPU sensor
+func (p *PrometheusTemperature) Refresh(ctx context.Context) error {
+	rangeSelector := fmt.Sprintf("[%ds]", int64(thermalWindow.Seconds()))
+
+	nodes := make(map[string]NodeTemperature)
+	for _, series := range []struct {
+		metric string
+		query  string
+		set    func(t *NodeTemperature, value float64)
+	}{
+		{"cpu", fmt.Sprintf(`max by (instance) (max_over_time(node_hwmon_temp_celsius%s))`, rangeSelector),
+			func(t *NodeTemperature, v float64) { t.CPU = v }},
+		{"gpu", fmt.Sprintf(`max by (instance) (max_over_time(DCGM_FI_DEV_GPU_TEMP%s))`, rangeSelector),
+			func(t *NodeTemperature, v float64) { t.GPU = v }},
+	} {
+		samples, err := p.prometheus.queryVector(ctx, series.query)
+		if err != nil {
+			return fmt.Errorf("failed to query %s temperature: %w", series.metric, err)
+		}
+		for _, sample := range samples {
+			node := sample.labels["instance"]
+			if node == "" || sample.value <= 0 {
+				continue
+			}
+			t := nodes[node]
+			series.set(&t, sample.value)
+			nodes[node] = t
+		}
+	}
+	p.snapshot.Store(&thermalSnapshot{nodes: nodes, readAt: p.clock.Now()})
+	return nil
+}
+
+// Start reads temperatures every minute until the context is cancelled; a failed read keeps
+// the previous temperatures until they expire
+func (p *PrometheusTemperature) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("thermal")
+
+	ticker := time.NewTicker(thermalRefresh)
+	defer ticker.Stop()
+	for {
+		if err := p.Refresh(ctx); err != nil {
+			log.Error(err, "Failed to read node temperatures")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection reads temperatures on every replica, as every replica scores nodes
+func (p *PrometheusTemperature) NeedLeaderElection() bool {
+	return false
+}
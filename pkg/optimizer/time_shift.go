@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"fmt"
+	"time"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// TimeShiftGate is the scheduling gate holding the pods of a time-shifted workload until
+	// it may run
+	TimeShiftGate = "kcloud.io/time-shift"
+
+	// DefaultTimeShiftSafetyMargin is kept before the latest start that meets a deadline
+	DefaultTimeShiftSafetyMargin = time.Hour
+
+	// DefaultTimeShiftMinRunTime is how long a released workload runs before it may be held again
+	DefaultTimeShiftMinRunTime = 30 * time.Minute
+)
+
+// TimeShiftDecision is whether a time-shifted workload may run now
+type TimeShiftDecision struct {
+	Run    bool
+	Reason string
+	// Signal is the current value of the signal, nil when unknown
+	Signal *float64
+	// LatestStart is when the workload must start to meet its deadline, nil without one
+	LatestStart *time.Time
+	// NextWindow is when the forecast next has the signal at or below the threshold
+	NextWindow *time.Time
+}
+
+// TimeShiftSignal returns the signal the workload waits out
+func TimeShiftSignal(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	if wo.Spec.TimeShift == nil || wo.Spec.TimeShift.Signal == "" {
+		return kcloudv1alpha1.TimeShiftSignalCarbonIntensity
+	}
+	return wo.Spec.TimeShift.Signal
+}
+
+// TimeShiftLatestStart returns when the workload must start, with the safety margin and the
+// deadline's margin on its expected duration to spare, to complete by its deadline
+func TimeShiftLatestStart(wo *kcloudv1alpha1.WorkloadOptimizer) (time.Time, bool) {
+	deadline := wo.Spec.Deadline
+	if deadline == nil || wo.Spec.TimeShift == nil {
+		return time.Time{}, false
+	}
+	margin := DefaultTimeShiftSafetyMargin
+	if wo.Spec.TimeShift.SafetyMargin != nil {
+		margin = wo.Spec.TimeShift.SafetyMargin.Duration
+	}
+	latest := deadline.CompleteBy.Add(-margin)
+	if deadline.ExpectedDuration != nil {
+		latest = latest.Add(-time.Duration(float64(deadline.ExpectedDuration.Duration) * (1 + deadlineMargin)))
+	}
+	return latest, true
+}
+
+// TimeShiftMinRunTime returns how long the workload runs once released before it may be held again
+func TimeShiftMinRunTime(wo *kcloudv1alpha1.WorkloadOptimizer) time.Duration {
+	if wo.Spec.TimeShift == nil || wo.Spec.TimeShift.MinRunTime == nil {
+		return DefaultTimeShiftMinRunTime
+	}
+	return wo.Spec.TimeShift.MinRunTime.Duration
+}
+
+// DecideTimeShift decides whether the time-shifted workload may run now: when the signal is
+// at or below the threshold, when its latest start has come, or when the signal is unknown
+// so the workload is not held blind
+func DecideTimeShift(wo *kcloudv1alpha1.WorkloadOptimizer, forecast *GridForecast, now time.Time) TimeShiftDecision {
+	spec := wo.Spec.TimeShift
+	signal := TimeShiftSignal(wo)
+	decision := TimeShiftDecision{}
+	if latest, ok := TimeShiftLatestStart(wo); ok {
+		decision.LatestStart = &latest
+	}
+	if forecast != nil {
+		if next, ok := forecast.NextAtOrBelow(signal, spec.Threshold); ok {
+			decision.NextWindow = &next
+		}
+	}
+
+	if decision.LatestStart != nil && !now.Before(*decision.LatestStart) {
+		decision.Run = true
+		decision.Reason = "latest start to meet the deadline has come"
+		return decision
+	}
+	if forecast == nil {
+		decision.Run = true
+		decision.Reason = "no grid signal is configured"
+		return decision
+	}
+	value, ok := forecast.Current(signal)
+	if !ok {
+		decision.Run = true
+		decision.Reason = fmt.Sprintf("current %s is unknown", signalNoun(signal))
+		return decision
+	}
+	decision.Signal = &value
+	if value <= spec.Threshold {
+		decision.Run = true
+		decision.Reason = fmt.Sprintf("%s %g is at or below threshold %g", signalNoun(signal), value, spec.Threshold)
+		return decision
+	}
+	decision.Reason = fmt.Sprintf("%s %g is above threshold %g", signalNoun(signal), value, spec.Threshold)
+	return decision
+}
+
+// TimeShiftHolds reports whether new pods of the workload are held by the time-shift gate:
+// until the operator first decides a time-shifted workload may run, and while it waits,
+// including for renewable supply under a PowerPolicy
+func TimeShiftHolds(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	if wo.Spec.TimeShift == nil {
+		return wo.Status.TimeShift != nil && wo.Status.TimeShift.State != kcloudv1alpha1.TimeShiftStateRunning
+	}
+	return wo.Status.TimeShift == nil || wo.Status.TimeShift.State != kcloudv1alpha1.TimeShiftStateRunning
+}
+
+// signalNoun names the signal in reasons
+func signalNoun(signal string) string {
+	if signal == kcloudv1alpha1.TimeShiftSignalElectricityPrice {
+		return "electricity price"
+	}
+	return "carbon intensity"
+}
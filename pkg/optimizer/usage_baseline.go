@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimizer
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DefaultBaselineWindow is how far back usage samples count toward a workload's baseline
+	DefaultBaselineWindow = 24 * time.Hour
+
+	// DefaultBaselineMinSamples is how many samples a baseline needs before it can alert
+	DefaultBaselineMinSamples = 6
+
+	// maxUsageSamples caps the samples kept per workload
+	maxUsageSamples = 2048
+)
+
+// UsageSample is a workload's cost and power at a point in time
+type UsageSample struct {
+	Timestamp time.Time
+	Cost      float64
+	Power     float64
+}
+
+// UsageDeviation compares a workload's current usage with its trailing baseline
+type UsageDeviation struct {
+	// Samples is the number of samples in the baseline
+	Samples       int
+	BaselineCost  float64
+	BaselinePower float64
+	// CostDeviation and PowerDeviation are relative to the baseline, e.g. 1.0 for double
+	CostDeviation  float64
+	PowerDeviation float64
+	// CostExceeded and PowerExceeded report deviations above the configured limit
+	CostExceeded  bool
+	PowerExceeded bool
+}
+
+// Exceeded reports whether cost or power is above the baseline limit
+func (d UsageDeviation) Exceeded() bool {
+	return d.CostExceeded || d.PowerExceeded
+}
+
+// UsageStore persists usage samples so baselines survive restarts
+type UsageStore interface {
+	// RecordUsage persists a sample of the workload's usage
+	RecordUsage(workloadID string, sample UsageSample)
+	// UsageSamples returns the workload's persisted samples taken at or after since, oldest first
+	UsageSamples(workloadID string, since time.Time) []UsageSample
+}
+
+// UsageHistory keeps a trailing window of usage samples per workload and flags samples
+// deviating from the workload's own baseline
+type UsageHistory struct {
+	// Window bounds the samples that make up the baseline
+	Window time.Duration
+	// MaxDeviation is the allowed rise over the baseline as a fraction, e.g. 0.5 for 50%
+	MaxDeviation float64
+	// MinSamples is the number of baseline samples required before alerting
+	MinSamples int
+
+	mu      sync.Mutex
+	clock   clock.PassiveClock
+	store   UsageStore
+	samples map[string][]UsageSample
+}
+
+// NewUsageHistory creates a usage history alerting above maxDeviationPercent
+func NewUsageHistory(maxDeviationPercent float64) *UsageHistory {
+	return &UsageHistory{
+		Window:       DefaultBaselineWindow,
+		MaxDeviation: maxDeviationPercent / 100,
+		MinSamples:   DefaultBaselineMinSamples,
+		clock:        clock.RealClock{},
+		samples:      make(map[string][]UsageSample),
+	}
+}
+
+// SetClock replaces the clock used to timestamp samples
+func (h *UsageHistory) SetClock(c clock.PassiveClock) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clock = c
+}
+
+// SetStore persists samples to store and loads the baseline of workloads seen for the
+// first time since the start from it
+func (h *UsageHistory) SetStore(store UsageStore) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.store = store
+}
+
+// Observe compares the usage with the workload's baseline, then adds it to the history
+func (h *UsageHistory) Observe(workloadID string, cost, power float64) UsageDeviation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.clock.Now()
+	cutoff := now.Add(-h.Window)
+
+	samples, known := h.samples[workloadID]
+	if !known && h.store != nil {
+		samples = h.store.UsageSamples(workloadID, cutoff)
+	}
+	first := 0
+	for first < len(samples) && samples[first].Timestamp.Before(cutoff) {
+		first++
+	}
+	samples = samples[first:]
+
+	deviation := UsageDeviation{Samples: len(samples)}
+	if len(samples) > 0 {
+		for _, sample := range samples {
+			deviation.BaselineCost += sample.Cost
+			deviation.BaselinePower += sample.Power
+		}
+		deviation.BaselineCost /= float64(len(samples))
+		deviation.BaselinePower /= float64(len(samples))
+		deviation.CostDeviation = relativeDeviation(cost, deviation.BaselineCost)
+		deviation.PowerDeviation = relativeDeviation(power, deviation.BaselinePower)
+
+		if len(samples) >= h.MinSamples && h.MaxDeviation > 0 {
+			deviation.CostExceeded = deviation.CostDeviation > h.MaxDeviation
+			deviation.PowerExceeded = deviation.PowerDeviation > h.MaxDeviation
+		}
+	}
+
+	sample := UsageSample{Timestamp: now, Cost: cost, Power: power}
+	samples = append(samples, sample)
+	if len(samples) > maxUsageSamples {
+		samples = samples[len(samples)-maxUsageSamples:]
+	}
+	h.samples[workloadID] = samples
+	if h.store != nil {
+		h.store.RecordUsage(workloadID, sample)
+	}
+
+	return deviation
+}
+
+// Samples returns the workload's samples within the window, oldest first
+func (h *UsageHistory) Samples(workloadID string) []UsageSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := h.clock.Now().Add(-h.Window)
+	var result []UsageSample
+	for _, sample := range h.samples[workloadID] {
+		if !sample.Timestamp.Before(cutoff) {
+			result = append(result, sample)
+		}
+	}
+	return result
+}
+
+// Forget drops the workload's history, e.g. after it was deleted
+func (h *UsageHistory) Forget(workloadID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.samples, workloadID)
+}
+
+// relativeDeviation returns how far current is above or below baseline as a fraction
+func relativeDeviation(current, baseline float64) float64 {
+	if baseline <= 0 {
+		return 0
+	}
+	return (current - baseline) / baseline
+}
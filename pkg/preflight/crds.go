@@ -0,0 +1,320 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	checkCRDVersions    = "crd-versions"
+	checkCustomResource = "custom-resources"
+
+	// maxReportedInvalid bounds how many invalid objects a result names
+	maxReportedInvalid = 5
+	// listPageSize is the page size used when listing custom resources
+	listPageSize = 500
+)
+
+// LoadCRDs reads CustomResourceDefinitions from YAML or JSON files and directories,
+// ignoring documents of other kinds
+func LoadCRDs(paths ...string) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	var crds []apiextensionsv1.CustomResourceDefinition
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".yaml", ".yml", ".json":
+			default:
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+
+			found, err := readCRDs(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			crds = append(crds, found...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(crds) == 0 {
+		return nil, fmt.Errorf("no CustomResourceDefinitions found in %s", strings.Join(paths, ", "))
+	}
+	return crds, nil
+}
+
+// readCRDs decodes every CustomResourceDefinition document in a file
+func readCRDs(path string) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var crds []apiextensionsv1.CustomResourceDefinition
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return crds, nil
+			}
+			return nil, err
+		}
+		if doc["kind"] != "CustomResourceDefinition" {
+			continue
+		}
+
+		var crd apiextensionsv1.CustomResourceDefinition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(doc, &crd); err != nil {
+			return nil, err
+		}
+		crds = append(crds, crd)
+	}
+}
+
+// storageVersion returns the version a CRD persists objects as
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) *apiextensionsv1.CustomResourceDefinitionVersion {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Storage {
+			return &crd.Spec.Versions[i]
+		}
+	}
+	return nil
+}
+
+// servedVersions returns the names of the versions a CRD serves
+func servedVersions(crd *apiextensionsv1.CustomResourceDefinition) map[string]bool {
+	served := make(map[string]bool)
+	for _, version := range crd.Spec.Versions {
+		if version.Served {
+			served[version.Name] = true
+		}
+	}
+	return served
+}
+
+// checkCRDVersions verifies that objects stored under the installed CRDs stay readable
+func (u *UpgradeChecker) checkCRDVersions(ctx context.Context, report *Report) {
+	shipped := make(map[string]bool)
+	for i := range u.CRDs {
+		crd := &u.CRDs[i]
+		if crd.Spec.Group != u.Group {
+			continue
+		}
+		shipped[crd.Name] = true
+
+		var installed apiextensionsv1.CustomResourceDefinition
+		if err := u.Client.Get(ctx, client.ObjectKey{Name: crd.Name}, &installed); err != nil {
+			if apierrors.IsNotFound(err) {
+				report.add(checkCRDVersions, crd.Name, StatusPass, "not installed, will be created", "")
+				continue
+			}
+			report.add(checkCRDVersions, crd.Name, StatusFail, fmt.Sprintf("failed to read installed CRD: %v", err),
+				"grant get on customresourcedefinitions and re-run")
+			continue
+		}
+
+		if installed.Spec.Scope != crd.Spec.Scope {
+			report.add(checkCRDVersions, crd.Name, StatusFail,
+				fmt.Sprintf("scope changes from %s to %s", installed.Spec.Scope, crd.Spec.Scope),
+				"back up the objects, delete the CRD, and restore the objects after installing the new release")
+			continue
+		}
+
+		served := servedVersions(crd)
+		var dropped []string
+		for _, version := range installed.Status.StoredVersions {
+			if !served[version] {
+				dropped = append(dropped, version)
+			}
+		}
+		target := storageVersion(crd)
+		if len(dropped) > 0 {
+			remediation := "migrate stored objects to a version the new release serves and remove the old versions from status.storedVersions"
+			if target != nil {
+				remediation = fmt.Sprintf("rewrite every object as %s (for example with kube-storage-version-migrator) "+
+					"and remove %s from status.storedVersions", target.Name, strings.Join(dropped, ", "))
+			}
+			report.add(checkCRDVersions, crd.Name, StatusFail,
+				fmt.Sprintf("objects are stored as %s, which the new release does not serve", strings.Join(dropped, ", ")),
+				remediation)
+			continue
+		}
+
+		var removed []string
+		for version := range servedVersions(&installed) {
+			if !served[version] {
+				removed = append(removed, version)
+			}
+		}
+		if len(removed) > 0 {
+			sort.Strings(removed)
+			report.add(checkCRDVersions, crd.Name, StatusWarn,
+				fmt.Sprintf("version %s will no longer be served", strings.Join(removed, ", ")),
+				"update clients and manifests that use the removed versions")
+			continue
+		}
+
+		report.add(checkCRDVersions, crd.Name, StatusPass,
+			fmt.Sprintf("stored versions %s remain served", strings.Join(installed.Status.StoredVersions, ", ")), "")
+	}
+
+	// CRDs of the operator's group that the new release no longer ships
+	var installed apiextensionsv1.CustomResourceDefinitionList
+	if err := u.Client.List(ctx, &installed); err != nil {
+		report.add(checkCRDVersions, u.Group, StatusFail, fmt.Sprintf("failed to list installed CRDs: %v", err),
+			"grant list on customresourcedefinitions and re-run")
+		return
+	}
+	for _, crd := range installed.Items {
+		if crd.Spec.Group == u.Group && !shipped[crd.Name] {
+			report.add(checkCRDVersions, crd.Name, StatusWarn, "installed but not part of the new release",
+				"delete the CRD and its objects once nothing depends on them")
+		}
+	}
+}
+
+// checkCustomResources validates existing objects against the new release's schemas
+func (u *UpgradeChecker) checkCustomResources(ctx context.Context, report *Report) {
+	for i := range u.CRDs {
+		crd := &u.CRDs[i]
+		if crd.Spec.Group != u.Group {
+			continue
+		}
+
+		var installed apiextensionsv1.CustomResourceDefinition
+		if err := u.Client.Get(ctx, client.ObjectKey{Name: crd.Name}, &installed); err != nil {
+			// Missing or unreadable CRDs were reported by the version check
+			continue
+		}
+
+		target := storageVersion(crd)
+		if target == nil || target.Schema == nil || target.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		if !servedVersions(&installed)[target.Name] {
+			report.add(checkCustomResource, crd.Name, StatusWarn,
+				fmt.Sprintf("cannot read objects as %s before the upgrade", target.Name),
+				"validate the objects after installing the new CRDs and before starting the new operator")
+			continue
+		}
+
+		validator, err := schemaValidator(target.Schema.OpenAPIV3Schema)
+		if err != nil {
+			report.add(checkCustomResource, crd.Name, StatusFail,
+				fmt.Sprintf("invalid schema for %s in the new release: %v", target.Name, err), "")
+			continue
+		}
+
+		gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: target.Name, Kind: crd.Spec.Names.ListKind}
+		total, invalid, err := u.validateObjects(ctx, gvk, validator)
+		if err != nil {
+			report.add(checkCustomResource, crd.Name, StatusFail, fmt.Sprintf("failed to list objects: %v", err),
+				"grant list on the resource and re-run")
+			continue
+		}
+		if len(invalid) > 0 {
+			report.add(checkCustomResource, crd.Name, StatusFail,
+				fmt.Sprintf("%d of %d objects fail the new schema: %s", len(invalid), total, summarize(invalid)),
+				"fix or delete the listed objects so they satisfy the new release's schema")
+			continue
+		}
+		report.add(checkCustomResource, crd.Name, StatusPass, fmt.Sprintf("%d objects valid", total), "")
+	}
+}
+
+// schemaValidator builds a structural validator for a CRD version schema
+func schemaValidator(props *apiextensionsv1.JSONSchemaProps) (validation.SchemaValidator, error) {
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(props, internal, nil); err != nil {
+		return nil, err
+	}
+	validator, _, err := validation.NewSchemaValidator(internal)
+	return validator, err
+}
+
+// validateObjects lists every object of the kind and returns the total and the failures
+func (u *UpgradeChecker) validateObjects(ctx context.Context, listGVK schema.GroupVersionKind,
+	validator validation.SchemaValidator) (int, []string, error) {
+	total := 0
+	var invalid []string
+
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(listGVK)
+		if err := u.Client.List(ctx, list, client.Limit(listPageSize), client.Continue(continueToken)); err != nil {
+			return 0, nil, err
+		}
+
+		for _, item := range list.Items {
+			total++
+			errs := validation.ValidateCustomResource(nil, item.UnstructuredContent(), validator)
+			if len(errs) > 0 {
+				invalid = append(invalid, fmt.Sprintf("%s (%s)", objectName(&item), errs[0].Error()))
+			}
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return total, invalid, nil
+		}
+	}
+}
+
+// objectName formats a namespaced or cluster-scoped object name
+func objectName(obj client.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// summarize lists the first few entries and counts the rest
+func summarize(items []string) string {
+	if len(items) <= maxReportedInvalid {
+		return strings.Join(items, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(items[:maxReportedInvalid], "; "), len(items)-maxReportedInvalid)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+const checkHistorySchema = "history-schema"
+
+// checkHistorySchema verifies that persisted scheduling history uses a layout the new
+// release can read. SQL history stores check their own schema when they open.
+func (u *UpgradeChecker) checkHistorySchema(ctx context.Context, report *Report) {
+	const target = "schedulingrecords"
+
+	newest := 0
+	total := 0
+	continueToken := ""
+	for {
+		var records kcloudv1alpha1.SchedulingRecordList
+		err := u.Client.List(ctx, &records, client.Limit(listPageSize), client.Continue(continueToken))
+		if err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				report.add(checkHistorySchema, target, StatusPass, "no CRD-backed scheduling history", "")
+				return
+			}
+			report.add(checkHistorySchema, target, StatusFail, fmt.Sprintf("failed to list scheduling records: %v", err),
+				"grant list on schedulingrecords and re-run")
+			return
+		}
+
+		for i := range records.Items {
+			version, err := scheduler.RecordSchemaVersion(&records.Items[i])
+			if err != nil {
+				report.add(checkHistorySchema, target, StatusFail, err.Error(),
+					"fix or delete the scheduling record with the invalid annotation")
+				return
+			}
+			newest = max(newest, version)
+			total++
+		}
+
+		continueToken = records.Continue
+		if continueToken == "" {
+			break
+		}
+	}
+
+	if newest > scheduler.HistorySchemaVersion {
+		report.add(checkHistorySchema, target, StatusFail,
+			fmt.Sprintf("records use history schema %d, but the new release reads up to %d", newest, scheduler.HistorySchemaVersion),
+			"upgrade to a release that supports the recorded schema, or delete the newer scheduling records")
+		return
+	}
+	report.add(checkHistorySchema, target, StatusPass,
+		fmt.Sprintf("%d records readable with history schema %d", total, scheduler.HistorySchemaVersion), "")
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preflight checks a cluster for problems that would break an operator upgrade.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "PASS"
+	StatusWarn Status = "WARN"
+	StatusFail Status = "FAIL"
+)
+
+// Result is the outcome of one check with a hint for fixing failures
+type Result struct {
+	Check       string `json:"check"`
+	Target      string `json:"target"`
+	Status      Status `json:"status"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// Report collects the results of an upgrade pre-flight run
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Passed reports whether no check failed; warnings do not block an upgrade
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteText prints the report as a table followed by remediation hints
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tCHECK\tTARGET\tMESSAGE")
+	for _, result := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Status, result.Check, result.Target, result.Message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, result := range r.Results {
+		if result.Status != StatusPass && result.Remediation != "" {
+			fmt.Fprintf(w, "\n%s %s: %s", result.Check, result.Target, result.Remediation)
+		}
+	}
+
+	verdict := "PASSED"
+	if !r.Passed() {
+		verdict = "FAILED"
+	}
+	_, err := fmt.Fprintf(w, "\n\nUpgrade pre-flight %s\n", verdict)
+	return err
+}
+
+// add appends a result
+func (r *Report) add(check, target string, status Status, message, remediation string) {
+	r.Results = append(r.Results, Result{
+		Check:       check,
+		Target:      target,
+		Status:      status,
+		Message:     message,
+		Remediation: remediation,
+	})
+}
+
+// UpgradeChecker validates the installed operator state against a new release
+type UpgradeChecker struct {
+	// Client reads the live cluster; its scheme must include apiextensions/v1
+	Client client.Client
+	// CRDs are the CustomResourceDefinitions shipped with the new release
+	CRDs []apiextensionsv1.CustomResourceDefinition
+	// Group is the API group owned by the operator
+	Group string
+}
+
+// NewUpgradeChecker creates a checker for the release CRDs
+func NewUpgradeChecker(c client.Client, crds []apiextensionsv1.CustomResourceDefinition) *UpgradeChecker {
+	return &UpgradeChecker{
+		Client: c,
+		CRDs:   crds,
+		Group:  "kcloud.io",
+	}
+}
+
+// Run executes every check; errors reaching the cluster are reported as failures
+func (u *UpgradeChecker) Run(ctx context.Context) *Report {
+	report := &Report{}
+	u.checkCRDVersions(ctx, report)
+	u.checkWebhooks(ctx, report)
+	u.checkHistorySchema(ctx, report)
+	u.checkCustomResources(ctx, report)
+	return report
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const checkWebhooks = "webhooks"
+
+// webhookTarget is the part of a webhook the health check needs
+type webhookTarget struct {
+	name          string
+	configuration string
+	clientConfig  admissionregistrationv1.WebhookClientConfig
+	failurePolicy *admissionregistrationv1.FailurePolicyType
+}
+
+// checkWebhooks verifies that the operator's admission webhooks can be reached, since a
+// broken webhook with failurePolicy Fail blocks the upgrade's own writes
+func (u *UpgradeChecker) checkWebhooks(ctx context.Context, report *Report) {
+	var targets []webhookTarget
+
+	var mutating admissionregistrationv1.MutatingWebhookConfigurationList
+	if err := u.Client.List(ctx, &mutating); err != nil {
+		report.add(checkWebhooks, "mutatingwebhookconfigurations", StatusFail,
+			fmt.Sprintf("failed to list: %v", err), "grant list on mutatingwebhookconfigurations and re-run")
+		return
+	}
+	for _, configuration := range mutating.Items {
+		for _, hook := range configuration.Webhooks {
+			targets = append(targets, webhookTarget{hook.Name, configuration.Name, hook.ClientConfig, hook.FailurePolicy})
+		}
+	}
+
+	var validating admissionregistrationv1.ValidatingWebhookConfigurationList
+	if err := u.Client.List(ctx, &validating); err != nil {
+		report.add(checkWebhooks, "validatingwebhookconfigurations", StatusFail,
+			fmt.Sprintf("failed to list: %v", err), "grant list on validatingwebhookconfigurations and re-run")
+		return
+	}
+	for _, configuration := range validating.Items {
+		for _, hook := range configuration.Webhooks {
+			targets = append(targets, webhookTarget{hook.Name, configuration.Name, hook.ClientConfig, hook.FailurePolicy})
+		}
+	}
+
+	found := false
+	for _, target := range targets {
+		if !strings.HasSuffix(target.name, "."+u.Group) {
+			continue
+		}
+		found = true
+		u.checkWebhook(ctx, report, target)
+	}
+	if !found {
+		report.add(checkWebhooks, u.Group, StatusPass, "no operator webhooks registered", "")
+	}
+}
+
+// checkWebhook checks the CA bundle and the service endpoints behind one webhook
+func (u *UpgradeChecker) checkWebhook(ctx context.Context, report *Report, target webhookTarget) {
+	name := target.configuration + "/" + target.name
+	blocking := target.failurePolicy == nil || *target.failurePolicy == admissionregistrationv1.Fail
+
+	if target.clientConfig.Service == nil {
+		report.add(checkWebhooks, name, StatusPass, "uses an external URL, not checked", "")
+		return
+	}
+	if len(target.clientConfig.CABundle) == 0 {
+		report.add(checkWebhooks, name, StatusFail, "caBundle is empty",
+			"inject the webhook CA, e.g. with the cert-manager inject-ca-from annotation")
+		return
+	}
+
+	ref := target.clientConfig.Service
+	var service corev1.Service
+	if err := u.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &service); err != nil {
+		report.add(checkWebhooks, name, StatusFail,
+			fmt.Sprintf("service %s/%s is unavailable: %v", ref.Namespace, ref.Name, err),
+			"restore the webhook service or delete the stale webhook configuration")
+		return
+	}
+
+	var slices discoveryv1.EndpointSliceList
+	if err := u.Client.List(ctx, &slices, client.InNamespace(ref.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: ref.Name}); err != nil {
+		report.add(checkWebhooks, name, StatusFail, fmt.Sprintf("failed to list endpoints: %v", err),
+			"grant list on endpointslices and re-run")
+		return
+	}
+	ready := 0
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+
+	switch {
+	case ready > 0:
+		report.add(checkWebhooks, name, StatusPass, fmt.Sprintf("%d ready endpoints", ready), "")
+	case blocking:
+		report.add(checkWebhooks, name, StatusFail, "no ready endpoints and failurePolicy is Fail",
+			"start the webhook server or temporarily set failurePolicy to Ignore before upgrading")
+	default:
+		report.add(checkWebhooks, name, StatusWarn, "no ready endpoints; requests are admitted unchanged",
+			"start the webhook server")
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/journal"
+)
+
+// journalTarget names the decision journal in purge results
+const journalTarget = "journal"
+
+var purgedRecords = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "kcloud_retention_purged_records_total",
+	Help: "Records removed by administrative purges, by store",
+}, []string{"target"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(purgedRecords)
+}
+
+// Filter selects the records a purge removes. Set fields narrow the selection; at least
+// one must be set.
+type Filter struct {
+	// Namespace limits the purge to workloads in this namespace
+	Namespace string `json:"namespace,omitempty"`
+	// Workload limits the purge to the named workload; requires Namespace
+	Workload string `json:"workload,omitempty"`
+	// From limits the purge to records at or after this time
+	From time.Time `json:"from,omitempty"`
+	// To limits the purge to records before this time
+	To time.Time `json:"to,omitempty"`
+}
+
+// Validate rejects filters that would purge everything or select nothing
+func (f Filter) Validate() error {
+	if f.Namespace == "" && f.Workload == "" && f.From.IsZero() && f.To.IsZero() {
+		return errors.New("purge filter must set a namespace, workload or time range")
+	}
+	if f.Workload != "" && f.Namespace == "" {
+		return errors.New("purging a workload requires its namespace")
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.To.After(f.From) {
+		return fmt.Errorf("purge range end %s is not after its start %s",
+			f.To.Format(time.RFC3339), f.From.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Matches reports whether a record of a workload at time t is selected. Records without a
+// namespace predate namespaced history and match a workload filter on the name alone.
+func (f Filter) Matches(namespace, workload string, t time.Time) bool {
+	if f.Namespace != "" && namespace != "" && namespace != f.Namespace {
+		return false
+	}
+	if f.Namespace != "" && namespace == "" && f.Workload == "" {
+		return false
+	}
+	if f.Workload != "" && workload != f.Workload {
+		return false
+	}
+	if !f.From.IsZero() && t.Before(f.From) {
+		return false
+	}
+	return f.To.IsZero() || t.Before(f.To)
+}
+
+// Target is a store that can remove the records selected by a filter
+type Target interface {
+	// Name identifies the store in purge results
+	Name() string
+	// Purge removes the selected records and returns how many were removed
+	Purge(ctx context.Context, filter Filter) (int, error)
+}
+
+// TargetFunc adapts a purge function to a Target
+type TargetFunc struct {
+	TargetName string
+	Func       func(ctx context.Context, filter Filter) (int, error)
+}
+
+// Name returns the target name
+func (t TargetFunc) Name() string {
+	return t.TargetName
+}
+
+// Purge calls the function
+func (t TargetFunc) Purge(ctx context.Context, filter Filter) (int, error) {
+	return t.Func(ctx, filter)
+}
+
+// Request is a purge and who asked for it
+type Request struct {
+	Filter      Filter `json:"filter"`
+	RequestedBy string `json:"requestedBy,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Result reports what a purge removed from each store
+type Result struct {
+	Request
+	Time   time.Time      `json:"time"`
+	Purged map[string]int `json:"purged"`
+	// Errors lists the stores that failed, by name
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// Purger removes records across the history stores and the decision journal. Purged
+// journal entries become tombstones and every purge is recorded in the journal, so
+// consumers reading it for training data skip purged decisions.
+type Purger struct {
+	journal *journal.Journal
+	targets []Target
+}
+
+// NewPurger creates a purger over the journal, which may be nil, and the given stores
+func NewPurger(j *journal.Journal, targets ...Target) *Purger {
+	return &Purger{journal: j, targets: targets}
+}
+
+// AddTarget adds a store to purge
+func (p *Purger) AddTarget(target Target) {
+	p.targets = append(p.targets, target)
+}
+
+// Purge removes the selected records from every store, then tombstones them in the
+// journal. A failing store does not stop the others; its error is returned with the result.
+func (p *Purger) Purge(ctx context.Context, request Request) (*Result, error) {
+	if err := request.Filter.Validate(); err != nil {
+		return nil, err
+	}
+	log := log.FromContext(ctx).WithName("retention")
+
+	result := &Result{Request: request, Time: time.Now().UTC(), Purged: map[string]int{}}
+	var errs []error
+	fail := func(target string, err error) {
+		if result.Errors == nil {
+			result.Errors = map[string]string{}
+		}
+		result.Errors[target] = err.Error()
+		errs = append(errs, fmt.Errorf("failed to purge %s: %w", target, err))
+	}
+
+	for _, target := range p.targets {
+		count, err := target.Purge(ctx, request.Filter)
+		result.Purged[target.Name()] = count
+		purgedRecords.WithLabelValues(target.Name()).Add(float64(count))
+		if err != nil {
+			fail(target.Name(), err)
+		}
+	}
+
+	if p.journal != nil {
+		purge, err := p.journal.Purge(func(e journal.Entry) bool {
+			return matchesEntry(request.Filter, e)
+		}, result)
+		if err != nil {
+			fail(journalTarget, err)
+		} else {
+			result.Purged[journalTarget] = purge.Purged
+			purgedRecords.WithLabelValues(journalTarget).Add(float64(purge.Purged))
+		}
+	}
+
+	log.Info("Purged records",
+		"namespace", request.Filter.Namespace,
+		"workload", request.Filter.Workload,
+		"from", request.Filter.From,
+		"to", request.Filter.To,
+		"requestedBy", request.RequestedBy,
+		"purged", result.Purged)
+	return result, errors.Join(errs...)
+}
+
+// matchesEntry reports whether a journal entry records a workload selected by the filter.
+// Only optimization and scheduling decisions identify a workload; other entries are kept.
+func matchesEntry(filter Filter, e journal.Entry) bool {
+	switch e.Type {
+	case "optimization":
+		var data struct {
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+		}
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return false
+		}
+		return filter.Matches(data.Namespace, data.Name, e.Time)
+	case "scheduling":
+		var data struct {
+			Namespace  string
+			WorkloadID string
+		}
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return false
+		}
+		return filter.Matches(data.Namespace, data.WorkloadID, e.Time)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// SetAcceleratorRegistry sets the accelerator types workloads may request besides GPUs and NPUs
+func (s *Scheduler) SetAcceleratorRegistry(registry *optimizer.AcceleratorRegistry) {
+	s.accelerators = registry
+}
+
+// checkAccelerators reports whether the node has room for the workload's registered
+// accelerators beyond those already requested. Without a registry, as in the kube-scheduler
+// plugin, the check is left to the scheduler's own extended resource fit.
+func checkAccelerators(registry *optimizer.AcceleratorRegistry, wo *kcloudv1alpha1.WorkloadOptimizer,
+	node *corev1.Node, requested corev1.ResourceList) (bool, string) {
+	if registry == nil || len(wo.Spec.Resources.Accelerators) == 0 {
+		return true, ""
+	}
+	resources, err := registry.Resources(wo.Spec.Resources.Accelerators)
+	if err != nil {
+		return false, err.Error()
+	}
+	for name, request := range resources {
+		if available := availableResource(*node, requested, name); request.Cmp(available) > 0 {
+			return false, fmt.Sprintf("node has %s %s available, workload needs %s", available.String(), name, request.String())
+		}
+	}
+	return true, ""
+}
@@ -26,17 +26,28 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/journal"
 )
 
 // AdvancedScheduler provides advanced scheduling capabilities
 type AdvancedScheduler struct {
 	*Scheduler
 	resourceReservations map[string]*ResourceReservation
-	schedulingHistory    []SchedulingEvent
+	history              HistoryStore
+	clock                clock.PassiveClock
 	metrics              *SchedulingMetrics
+	journal              *journal.Journal
+	marketplace          *Marketplace
+	plugins              []Plugin
+	pluginWeights        map[string]float64
+	overcommit           OvercommitPolicy
+	normalization        ScoreNormalization
+	standby              map[string][]StandbyReservation
+	algorithmStats       *algorithmStatsRecorder
 	mutex                sync.RWMutex
 }
 
@@ -47,15 +58,39 @@ type ResourceReservation struct {
 	ReservedMemory resource.Quantity
 	ReservedGPU    int32
 	ReservedNPU    int32
-	WorkloadID     string
-	ExpiresAt      time.Time
-	Priority       int32
+	// ReservedAccelerators holds the extended resources of registered accelerator types
+	ReservedAccelerators corev1.ResourceList
+	WorkloadID           string
+	// Namespace is the workload's namespace, the team that owns the reservation
+	Namespace string
+	ExpiresAt time.Time
+	Priority  int32
+	// Policy is the scheduling policy the workload was placed under
+	Policy string
 }
 
-// SchedulingEvent represents a scheduling event for history tracking
+// resources returns the reservation as a resource list
+func (r *ResourceReservation) resources() corev1.ResourceList {
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    r.ReservedCPU.DeepCopy(),
+		corev1.ResourceMemory: r.ReservedMemory.DeepCopy(),
+	}
+	if r.ReservedGPU > 0 {
+		resources["nvidia.com/gpu"] = *resource.NewQuantity(int64(r.ReservedGPU), resource.DecimalSI)
+	}
+	if r.ReservedNPU > 0 {
+		resources["npu.com/npu"] = *resource.NewQuantity(int64(r.ReservedNPU), resource.DecimalSI)
+	}
+	addResources(resources, r.ReservedAccelerators)
+	return resources
+}
+
+// SchedulingEvent represents a scheduling event for history tracking. Namespace is empty
+// for events recorded before history kept it.
 type SchedulingEvent struct {
 	Timestamp  time.Time
 	WorkloadID string
+	Namespace  string
 	NodeName   string
 	Decision   SchedulingDecision
 	Duration   time.Duration
@@ -77,14 +112,26 @@ type SchedulingMetrics struct {
 
 // SchedulingPolicy defines advanced scheduling policies
 type SchedulingPolicy struct {
-	Name                string
-	Algorithm           SchedulingAlgorithm
+	Name      string
+	Algorithm SchedulingAlgorithm
+	// FallbackAlgorithms are tried in order when Algorithm yields no node scoring at least MinScore
+	FallbackAlgorithms []SchedulingAlgorithm
+	// MinScore is the lowest acceptable decision score (0-1)
+	MinScore            float64
 	ResourceConstraints *ResourceConstraints
 	CostConstraints     *CostConstraints
 	PowerConstraints    *PowerConstraints
 	TimeConstraints     *TimeConstraints
-	Enabled             bool
-	Priority            int
+	// PluginWeights overrides the default score weight of registered plugins by name
+	PluginWeights map[string]float64
+	// ScoreExpressions add site-specific CEL score terms
+	ScoreExpressions []ScoreExpression
+	// StandbyReservations hold headroom that only workloads scheduled under this policy may use
+	StandbyReservations []StandbyReservation
+	// Normalization overrides the scheduler's score normalization for balanced scheduling
+	Normalization ScoreNormalization
+	Enabled       bool
+	Priority      int
 }
 
 // SchedulingAlgorithm defines different scheduling algorithms
@@ -97,6 +144,10 @@ const (
 	AlgorithmPowerOptimized SchedulingAlgorithm = "power_optimized"
 	AlgorithmBalanced       SchedulingAlgorithm = "balanced"
 	AlgorithmPriorityBased  SchedulingAlgorithm = "priority_based"
+
+	// AlgorithmWeightedScore is the base scheduler's weighted sum of resource, cost, power
+	// and placement scores, recorded for comparison with the policy algorithms
+	AlgorithmWeightedScore SchedulingAlgorithm = "weighted_score"
 )
 
 // ResourceConstraints defines resource-based constraints
@@ -142,14 +193,47 @@ type TimeSlot struct {
 
 // NewAdvancedScheduler creates a new advanced scheduler
 func NewAdvancedScheduler() *AdvancedScheduler {
-	return &AdvancedScheduler{
+	as := &AdvancedScheduler{
 		Scheduler:            NewScheduler(),
 		resourceReservations: make(map[string]*ResourceReservation),
-		schedulingHistory:    []SchedulingEvent{},
+		history:              NewMemoryHistoryStore(defaultHistoryLimit),
+		clock:                clock.RealClock{},
+		normalization:        DefaultScoreNormalization,
+		algorithmStats:       newAlgorithmStatsRecorder(),
 		metrics: &SchedulingMetrics{
 			LastUpdated: time.Now(),
 		},
 	}
+	as.plugins, as.pluginWeights = instantiatePlugins(as)
+	return as
+}
+
+// Plugins returns the plugins active in this scheduler
+func (as *AdvancedScheduler) Plugins() []Plugin {
+	plugins := make([]Plugin, len(as.plugins))
+	copy(plugins, as.plugins)
+	return plugins
+}
+
+// SetClock replaces the clock used for timestamps, reservations and durations
+func (as *AdvancedScheduler) SetClock(c clock.PassiveClock) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	as.clock = c
+}
+
+// SetHistoryStore replaces the store that keeps scheduling history
+func (as *AdvancedScheduler) SetHistoryStore(h HistoryStore) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	as.history = h
+}
+
+// SetJournal enables durable journaling of scheduling decisions
+func (as *AdvancedScheduler) SetJournal(j *journal.Journal) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	as.journal = j
 }
 
 // ScheduleWithPolicy schedules a workload using advanced policies
@@ -162,14 +246,14 @@ func (as *AdvancedScheduler) ScheduleWithPolicy(ctx context.Context, wo *kcloudv
 		policy = as.getDefaultPolicy()
 	}
 
-	startTime := time.Now()
+	startTime := as.clock.Now()
 	log.Info("Starting advanced scheduling",
 		"workload", wo.Name,
 		"algorithm", policy.Algorithm,
 		"nodeCount", len(nodes))
 
 	// Filter nodes based on constraints
-	filteredNodes, err := as.filterNodesByConstraints(nodes, policy)
+	filteredNodes, err := as.filterNodesByConstraints(ctx, wo, nodes, policy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to filter nodes: %w", err)
 	}
@@ -178,28 +262,11 @@ func (as *AdvancedScheduler) ScheduleWithPolicy(ctx context.Context, wo *kcloudv
 		return nil, fmt.Errorf("no nodes meet the scheduling constraints")
 	}
 
-	// Apply scheduling algorithm
-	var decision *SchedulingDecision
-	switch policy.Algorithm {
-	case AlgorithmRoundRobin:
-		decision, err = as.scheduleRoundRobin(ctx, wo, filteredNodes, policy)
-	case AlgorithmLeastLoaded:
-		decision, err = as.scheduleLeastLoaded(ctx, wo, filteredNodes, policy)
-	case AlgorithmCostOptimized:
-		decision, err = as.scheduleCostOptimized(ctx, wo, filteredNodes, policy)
-	case AlgorithmPowerOptimized:
-		decision, err = as.schedulePowerOptimized(ctx, wo, filteredNodes, policy)
-	case AlgorithmBalanced:
-		decision, err = as.scheduleBalanced(ctx, wo, filteredNodes, policy)
-	case AlgorithmPriorityBased:
-		decision, err = as.schedulePriorityBased(ctx, wo, filteredNodes, policy)
-	default:
-		decision, err = as.scheduleBalanced(ctx, wo, filteredNodes, policy)
-	}
-
+	// Walk the algorithm chain until one clears the policy's minimum score
+	decision, err := as.runAlgorithmChain(ctx, wo, filteredNodes, policy)
 	if err != nil {
-		as.recordSchedulingEvent(ctx, wo.Name, "", SchedulingDecision{},
-			time.Since(startTime), false, err.Error())
+		as.recordSchedulingEvent(ctx, wo, "", SchedulingDecision{},
+			as.clock.Since(startTime), false, err.Error())
 		return nil, err
 	}
 
@@ -213,20 +280,98 @@ func (as *AdvancedScheduler) ScheduleWithPolicy(ctx context.Context, wo *kcloudv
 	}
 
 	// Record scheduling event
-	as.recordSchedulingEvent(ctx, wo.Name, decision.SelectedNode, *decision,
-		time.Since(startTime), true, "Success")
+	as.recordSchedulingEvent(ctx, wo, decision.SelectedNode, *decision,
+		as.clock.Since(startTime), true, "Success")
 
 	// Update metrics
-	as.updateMetrics(*decision, time.Since(startTime))
+	as.updateMetrics(*decision, as.clock.Since(startTime))
 
 	log.Info("Advanced scheduling completed",
 		"selectedNode", decision.SelectedNode,
 		"score", decision.Score,
-		"duration", time.Since(startTime))
+		"algorithm", decision.Algorithm,
+		"stage", decision.Stage,
+		"duration", as.clock.Since(startTime))
 
 	return decision, nil
 }
 
+// AlgorithmChain returns the primary algorithm followed by the policy's fallbacks
+func (p *SchedulingPolicy) AlgorithmChain() []SchedulingAlgorithm {
+	chain := []SchedulingAlgorithm{p.Algorithm}
+	for _, algorithm := range p.FallbackAlgorithms {
+		if algorithm != p.Algorithm {
+			chain = append(chain, algorithm)
+		}
+	}
+	return chain
+}
+
+// runAlgorithmChain tries each algorithm in the chain and keeps the first decision
+// scoring at least MinScore, recording the stage that produced it
+func (as *AdvancedScheduler) runAlgorithmChain(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	nodes []corev1.Node, policy *SchedulingPolicy) (*SchedulingDecision, error) {
+	log := log.FromContext(ctx)
+
+	var best *SchedulingDecision
+	var lastErr error
+	for stage, algorithm := range policy.AlgorithmChain() {
+		started := as.clock.Now()
+		decision, err := as.runAlgorithm(ctx, algorithm, wo, nodes, policy)
+		elapsed := as.clock.Since(started)
+		if err != nil {
+			as.algorithmStats.record(algorithm, AlgorithmOutcomeFailed, 0, elapsed)
+			log.V(1).Info("Scheduling algorithm failed, trying next", "algorithm", algorithm, "error", err.Error())
+			lastErr = err
+			continue
+		}
+		decision.Algorithm = algorithm
+		decision.Stage = stage
+		if decision.Score >= policy.MinScore {
+			as.algorithmStats.record(algorithm, AlgorithmOutcomeSelected, decision.Score, elapsed)
+			if stage > 0 {
+				log.Info("Fallback algorithm produced the decision",
+					"algorithm", algorithm, "stage", stage, "score", decision.Score)
+			}
+			return decision, nil
+		}
+
+		as.algorithmStats.record(algorithm, AlgorithmOutcomeBelowMinScore, decision.Score, elapsed)
+		log.V(1).Info("Scheduling algorithm below minimum score, trying next",
+			"algorithm", algorithm, "score", decision.Score, "minScore", policy.MinScore)
+		if best == nil || decision.Score > best.Score {
+			best = decision
+		}
+	}
+
+	if best != nil {
+		return nil, fmt.Errorf("no algorithm produced a node scoring at least %.2f (best %.2f from %s)",
+			policy.MinScore, best.Score, best.Algorithm)
+	}
+	return nil, lastErr
+}
+
+// runAlgorithm applies a single scheduling algorithm
+func (as *AdvancedScheduler) runAlgorithm(ctx context.Context, algorithm SchedulingAlgorithm,
+	wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node, policy *SchedulingPolicy) (*SchedulingDecision, error) {
+	switch algorithm {
+	case AlgorithmRoundRobin:
+		return as.scheduleRoundRobin(ctx, wo, nodes, policy)
+	case AlgorithmLeastLoaded:
+		return as.scheduleLeastLoaded(ctx, wo, nodes, policy)
+	case AlgorithmCostOptimized:
+		return as.scheduleCostOptimized(ctx, wo, nodes, policy)
+	case AlgorithmPowerOptimized:
+		return as.schedulePowerOptimized(ctx, wo, nodes, policy)
+	case AlgorithmBalanced:
+		return as.scheduleBalanced(ctx, wo, nodes, policy)
+	case AlgorithmPriorityBased:
+		return as.schedulePriorityBased(ctx, wo, nodes, policy)
+	default:
+		return as.scheduleBalanced(ctx, wo, nodes, policy)
+	}
+}
+
 // scheduleRoundRobin implements round-robin scheduling
 func (as *AdvancedScheduler) scheduleRoundRobin(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
 	nodes []corev1.Node, policy *SchedulingPolicy) (*SchedulingDecision, error) {
@@ -239,7 +384,7 @@ func (as *AdvancedScheduler) scheduleRoundRobin(ctx context.Context, wo *kcloudv
 
 	// Find the least recently used node
 	var selectedNode *corev1.Node
-	minLastUsed := time.Now()
+	minLastUsed := as.clock.Now()
 
 	for i := range nodes {
 		node := &nodes[i]
@@ -311,7 +456,8 @@ func (as *AdvancedScheduler) scheduleCostOptimized(ctx context.Context, wo *kclo
 
 	for i := range nodes {
 		node := &nodes[i]
-		cost := as.estimateNodeCost(wo, node)
+		// Spot nodes cost more the likelier they are to be reclaimed
+		cost := as.spotRiskCost(wo, node, as.estimateNodeCost(wo, node))
 
 		if cost < bestCost {
 			bestCost = cost
@@ -375,22 +521,31 @@ func (as *AdvancedScheduler) scheduleBalanced(ctx context.Context, wo *kcloudv1a
 	log := log.FromContext(ctx)
 
 	var bestNode *corev1.Node
+	var bestContributions map[string]float64
 	bestScore := -1.0
 
-	for i := range nodes {
-		node := &nodes[i]
-
-		// Calculate balanced score considering multiple factors
-		costScore := as.calculateCostScore(wo, node)
-		powerScore := as.calculatePowerScore(wo, node)
-		resourceScore := as.calculateResourceScore(wo, node)
+	// Weighted combination of all score factors, scored concurrently and normalized over
+	// the candidates so no factor's raw range outweighs the others
+	factors := make([]map[string]float64, len(nodes))
+	weights := make([]map[string]float64, len(nodes))
+	if err := as.forEachNode(ctx, len(nodes), func(i int) {
+		factors[i], weights[i] = as.scoreFactors(ctx, wo, &nodes[i], policy)
+	}); err != nil {
+		return nil, err
+	}
+	normalizeFactors(as.scoreNormalization(policy), factors)
 
-		// Weighted combination
-		balancedScore := (costScore*0.4 + powerScore*0.3 + resourceScore*0.3)
+	scores := make([]float64, len(nodes))
+	contributions := make([]map[string]float64, len(nodes))
+	for i := range nodes {
+		scores[i], contributions[i] = combineFactors(factors[i], weights[i])
+	}
 
-		if balancedScore > bestScore {
-			bestScore = balancedScore
-			bestNode = node
+	for i := range nodes {
+		if scores[i] > bestScore {
+			bestScore = scores[i]
+			bestNode = &nodes[i]
+			bestContributions = contributions[i]
 		}
 	}
 
@@ -403,9 +558,14 @@ func (as *AdvancedScheduler) scheduleBalanced(ctx context.Context, wo *kcloudv1a
 		return nil, err
 	}
 
+	// Report the balanced score and how each criterion contributed to it
+	decision.Score = bestScore
+	decision.Contributions = bestContributions
+
 	log.V(1).Info("Balanced scheduling selected node",
 		"node", bestNode.Name,
-		"balancedScore", bestScore)
+		"balancedScore", bestScore,
+		"contributions", bestContributions)
 
 	return decision, nil
 }
@@ -453,41 +613,156 @@ func (as *AdvancedScheduler) getDefaultPolicy() *SchedulingPolicy {
 	}
 }
 
-func (as *AdvancedScheduler) filterNodesByConstraints(nodes []corev1.Node, policy *SchedulingPolicy) ([]corev1.Node, error) {
+func (as *AdvancedScheduler) filterNodesByConstraints(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	nodes []corev1.Node, policy *SchedulingPolicy) ([]corev1.Node, error) {
+	log := log.FromContext(ctx)
 	var filteredNodes []corev1.Node
 
-	for _, node := range nodes {
-		if as.nodeMeetsPolicyConstraints(&node, policy) {
-			filteredNodes = append(filteredNodes, node)
+	passed := make([]bool, len(nodes))
+	if err := as.forEachNode(ctx, len(nodes), func(i int) {
+		ok, plugin, reason := as.runFilterPlugins(ctx, wo, &nodes[i], policy)
+		if !ok {
+			log.V(1).Info("Node filtered out", "node", nodes[i].Name, "plugin", plugin, "reason", reason)
 		}
+		passed[i] = ok
+	}); err != nil {
+		return nil, err
 	}
 
+	for i := range nodes {
+		if passed[i] {
+			filteredNodes = append(filteredNodes, nodes[i])
+		}
+	}
 	return filteredNodes, nil
 }
 
-func (as *AdvancedScheduler) nodeMeetsPolicyConstraints(node *corev1.Node, policy *SchedulingPolicy) bool {
-	// Check resource constraints
-	if policy.ResourceConstraints != nil {
-		if !as.nodeMeetsResourceConstraints(node, policy.ResourceConstraints) {
-			return false
+// runFilterPlugins runs every filter plugin and reports the first rejection
+func (as *AdvancedScheduler) runFilterPlugins(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	node *corev1.Node, policy *SchedulingPolicy) (bool, string, string) {
+	if reason := NodeUnschedulableReason(node); reason != "" {
+		return false, "schedulable", reason
+	}
+	for _, plugin := range as.plugins {
+		filter, ok := plugin.(FilterPlugin)
+		if !ok {
+			continue
+		}
+		if passed, reason := filter.Filter(ctx, wo, node, policy); !passed {
+			return false, plugin.Name(), reason
 		}
 	}
+	return true, "", ""
+}
 
-	// Check cost constraints
-	if policy.CostConstraints != nil {
-		if !as.nodeMeetsCostConstraints(node, policy.CostConstraints) {
-			return false
+// scoreFactors returns a node's score from each plugin and score expression with the
+// weight it carries, honouring policy weight overrides; factors with no weight are left out
+func (as *AdvancedScheduler) scoreFactors(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	node *corev1.Node, policy *SchedulingPolicy) (map[string]float64, map[string]float64) {
+	factors := map[string]float64{}
+	weights := map[string]float64{}
+
+	as.mutex.RLock()
+	defaults := as.pluginWeights
+	as.mutex.RUnlock()
+
+	for _, plugin := range as.plugins {
+		scorer, isScorer := plugin.(ScorePlugin)
+		partial, isPartial := plugin.(PartialScorePlugin)
+		if !isScorer && !isPartial {
+			continue
+		}
+
+		weight := defaults[plugin.Name()]
+		if override, exists := policy.PluginWeights[plugin.Name()]; exists {
+			weight = override
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		score := 0.0
+		if isPartial {
+			applicable := false
+			if score, applicable = partial.ScoreIfApplicable(ctx, wo, node); !applicable {
+				continue
+			}
+		} else {
+			score = scorer.Score(ctx, wo, node)
 		}
+		factors[plugin.Name()] = score
+		weights[plugin.Name()] = weight
 	}
 
-	// Check power constraints
-	if policy.PowerConstraints != nil {
-		if !as.nodeMeetsPowerConstraints(node, policy.PowerConstraints) {
-			return false
+	for _, expr := range policy.ScoreExpressions {
+		if expr.Weight <= 0 {
+			continue
 		}
+
+		score, err := EvaluateScoreExpression(expr.Expression, wo, node)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Score expression failed, scoring as zero",
+				"policy", policy.Name, "expression", expr.Name, "node", node.Name)
+		}
+
+		// Expressions sharing a name form one factor, their scores averaged by weight
+		name := "expr:" + expr.Name
+		total := weights[name] + expr.Weight
+		factors[name] = (factors[name]*weights[name] + score*expr.Weight) / total
+		weights[name] = total
 	}
+	return factors, weights
+}
 
-	return true
+// combineFactors combines factor scores into a weighted average and reports what each
+// factor contributed to it
+func combineFactors(factors, weights map[string]float64) (float64, map[string]float64) {
+	// Sum in name order so floating point rounding does not depend on map iteration
+	names := make([]string, 0, len(factors))
+	for name := range factors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	totalWeight := 0.0
+	for _, name := range names {
+		totalWeight += weights[name]
+	}
+
+	contributions := make(map[string]float64, len(factors))
+	if totalWeight == 0 {
+		return 0, contributions
+	}
+
+	totalScore := 0.0
+	for _, name := range names {
+		contributions[name] = factors[name] * weights[name] / totalWeight
+		totalScore += contributions[name]
+	}
+	return totalScore, contributions
+}
+
+// SetPluginWeights overrides default plugin weights operator-wide; policies may still override them
+func (as *AdvancedScheduler) SetPluginWeights(weights map[string]float64) error {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	merged := make(map[string]float64, len(as.pluginWeights))
+	for name, weight := range as.pluginWeights {
+		merged[name] = weight
+	}
+	for name, weight := range weights {
+		if _, exists := merged[name]; !exists {
+			return fmt.Errorf("unknown scheduler plugin %s", name)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight for plugin %s cannot be negative", name)
+		}
+		merged[name] = weight
+	}
+
+	as.pluginWeights = merged
+	return nil
 }
 
 func (as *AdvancedScheduler) nodeMeetsResourceConstraints(node *corev1.Node, constraints *ResourceConstraints) bool {
@@ -566,28 +841,13 @@ func (as *AdvancedScheduler) calculateNodeUtilization(node *corev1.Node) float64
 }
 
 func (as *AdvancedScheduler) estimateNodeCost(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
-	// Use the base scheduler's cost estimation
-	return as.estimateNodeCost(wo, node)
+	// Use the base scheduler's cost estimation, which includes the transfer to data in other zones
+	return as.Scheduler.estimateNodeCost(wo, *node)
 }
 
 func (as *AdvancedScheduler) estimateNodePower(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
 	// Use the base scheduler's power estimation
-	return as.estimateNodePower(wo, node)
-}
-
-func (as *AdvancedScheduler) calculateCostScore(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
-	// Use the base scheduler's cost score calculation
-	return as.calculateCostScore(wo, node)
-}
-
-func (as *AdvancedScheduler) calculatePowerScore(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
-	// Use the base scheduler's power score calculation
-	return as.calculatePowerScore(wo, node)
-}
-
-func (as *AdvancedScheduler) calculateResourceScore(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
-	// Use the base scheduler's resource score calculation
-	return as.calculateResourceScore(wo, node)
+	return as.Scheduler.estimateNodePower(wo, *node)
 }
 
 func (as *AdvancedScheduler) calculateNodePriority(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
@@ -607,44 +867,53 @@ func (as *AdvancedScheduler) getNodeLastUsedTime(nodeName string) time.Time {
 	as.mutex.RLock()
 	defer as.mutex.RUnlock()
 
-	// Find the most recent scheduling event for this node
-	for i := len(as.schedulingHistory) - 1; i >= 0; i-- {
-		if as.schedulingHistory[i].NodeName == nodeName {
-			return as.schedulingHistory[i].Timestamp
-		}
-	}
-
-	return time.Time{} // Zero time if never used
+	// Zero time if never used
+	lastUsed, _ := as.history.LastUsed(nodeName)
+	return lastUsed
 }
 
+// reserveResources holds capacity for the workload on the node. Reservations are keyed by
+// workload, so a replayed scheduling pass replaces the previous one instead of adding to it.
 func (as *AdvancedScheduler) reserveResources(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
 	nodeName string, policy *SchedulingPolicy) error {
+	accelerators, err := as.accelerators.Resources(wo.Spec.Resources.Accelerators)
+	if err != nil {
+		return fmt.Errorf("failed to reserve accelerators: %w", err)
+	}
+
 	as.mutex.Lock()
 	defer as.mutex.Unlock()
 
 	reservation := &ResourceReservation{
-		NodeName:       nodeName,
-		ReservedCPU:    resource.MustParse(wo.Spec.Resources.CPU),
-		ReservedMemory: resource.MustParse(wo.Spec.Resources.Memory),
-		ReservedGPU:    wo.Spec.Resources.GPU,
-		ReservedNPU:    wo.Spec.Resources.NPU,
-		WorkloadID:     wo.Name,
-		ExpiresAt:      time.Now().Add(time.Hour * 24), // 24-hour reservation
-		Priority:       wo.Spec.Priority,
+		NodeName:             nodeName,
+		ReservedCPU:          resource.MustParse(wo.Spec.Resources.CPU),
+		ReservedMemory:       resource.MustParse(wo.Spec.Resources.Memory),
+		ReservedGPU:          wo.Spec.Resources.GPU,
+		ReservedNPU:          wo.Spec.Resources.NPU,
+		ReservedAccelerators: accelerators,
+		WorkloadID:           wo.Name,
+		Namespace:            wo.Namespace,
+		Policy:               policy.Name,
+		ExpiresAt:            as.clock.Now().Add(time.Hour * 24), // 24-hour reservation
+		Priority:             wo.Spec.Priority,
 	}
 
 	as.resourceReservations[wo.Name] = reservation
+	if as.snapshot != nil {
+		as.snapshot.Reserve(wo.Name, nodeName, reservation.resources())
+	}
 	return nil
 }
 
-func (as *AdvancedScheduler) recordSchedulingEvent(ctx context.Context, workloadID, nodeName string,
+func (as *AdvancedScheduler) recordSchedulingEvent(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodeName string,
 	decision SchedulingDecision, duration time.Duration, success bool, reason string) {
 	as.mutex.Lock()
 	defer as.mutex.Unlock()
 
 	event := SchedulingEvent{
-		Timestamp:  time.Now(),
-		WorkloadID: workloadID,
+		Timestamp:  as.clock.Now(),
+		WorkloadID: wo.Name,
+		Namespace:  wo.Namespace,
 		NodeName:   nodeName,
 		Decision:   decision,
 		Duration:   duration,
@@ -652,11 +921,13 @@ func (as *AdvancedScheduler) recordSchedulingEvent(ctx context.Context, workload
 		Reason:     reason,
 	}
 
-	as.schedulingHistory = append(as.schedulingHistory, event)
+	as.history.Record(event)
 
-	// Keep only last 1000 events
-	if len(as.schedulingHistory) > 1000 {
-		as.schedulingHistory = as.schedulingHistory[1:]
+	// Persist the decision so history survives the in-memory cap
+	if as.journal != nil {
+		if _, err := as.journal.Append("scheduling", event); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to journal scheduling event", "workload", wo.Name)
+		}
 	}
 }
 
@@ -668,7 +939,7 @@ func (as *AdvancedScheduler) updateMetrics(decision SchedulingDecision, duration
 	as.metrics.SuccessfulSchedules++
 	as.metrics.AverageScore = (as.metrics.AverageScore*float64(as.metrics.SuccessfulSchedules-1) + decision.Score) / float64(as.metrics.SuccessfulSchedules)
 	as.metrics.AverageDuration = (as.metrics.AverageDuration*time.Duration(as.metrics.SuccessfulSchedules-1) + duration) / time.Duration(as.metrics.SuccessfulSchedules)
-	as.metrics.LastUpdated = time.Now()
+	as.metrics.LastUpdated = as.clock.Now()
 }
 
 func (as *AdvancedScheduler) getNodeCostPerHour(node *corev1.Node) float64 {
@@ -687,6 +958,11 @@ func (as *AdvancedScheduler) getNodeCostPerHour(node *corev1.Node) float64 {
 }
 
 func (as *AdvancedScheduler) getNodePowerUsage(node *corev1.Node) float64 {
+	if as.measuredPower != nil {
+		if watts, ok := as.measuredPower.NodePower(node.Name); ok {
+			return watts
+		}
+	}
 	// Simplified power calculation
 	if powerLabel, exists := node.Labels["power-usage"]; exists {
 		switch powerLabel {
@@ -716,19 +992,15 @@ func (as *AdvancedScheduler) GetSchedulingHistory(limit int) []SchedulingEvent {
 	as.mutex.RLock()
 	defer as.mutex.RUnlock()
 
-	if limit <= 0 || limit > len(as.schedulingHistory) {
-		limit = len(as.schedulingHistory)
-	}
+	return as.history.Recent(limit)
+}
 
-	// Return last 'limit' events
-	start := len(as.schedulingHistory) - limit
-	if start < 0 {
-		start = 0
-	}
+// QuerySchedulingHistory returns scheduling events by workload, node, algorithm or age
+func (as *AdvancedScheduler) QuerySchedulingHistory(query HistoryQuery) []SchedulingEvent {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
 
-	history := make([]SchedulingEvent, limit)
-	copy(history, as.schedulingHistory[start:])
-	return history
+	return as.history.Query(query)
 }
 
 // CleanupExpiredReservations removes expired resource reservations
@@ -736,10 +1008,13 @@ func (as *AdvancedScheduler) CleanupExpiredReservations() {
 	as.mutex.Lock()
 	defer as.mutex.Unlock()
 
-	now := time.Now()
+	now := as.clock.Now()
 	for workloadID, reservation := range as.resourceReservations {
 		if now.After(reservation.ExpiresAt) {
 			delete(as.resourceReservations, workloadID)
+			if as.snapshot != nil {
+				as.snapshot.Release(workloadID)
+			}
 		}
 	}
 }
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Outcomes of a single algorithm run within a policy's chain
+const (
+	AlgorithmOutcomeSelected      = "selected"
+	AlgorithmOutcomeBelowMinScore = "below_min_score"
+	AlgorithmOutcomeFailed        = "failed"
+)
+
+var (
+	algorithmRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kcloud_scheduler_algorithm_runs_total",
+		Help: "Scheduling algorithm runs by outcome",
+	}, []string{"algorithm", "outcome"})
+	algorithmScore = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kcloud_scheduler_algorithm_score",
+		Help:    "Score of the node chosen by each scheduling algorithm",
+		Buckets: prometheus.LinearBuckets(0, 0.1, 11), // 0.0 to 1.0 in 0.1 increments
+	}, []string{"algorithm"})
+	algorithmDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kcloud_scheduler_algorithm_duration_seconds",
+		Help:    "Time spent running each scheduling algorithm",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 2, 14), // 0.1ms to ~0.8s
+	}, []string{"algorithm"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(algorithmRuns, algorithmScore, algorithmDuration)
+}
+
+// AlgorithmStatistics summarizes how a scheduling algorithm has performed
+type AlgorithmStatistics struct {
+	Algorithm SchedulingAlgorithm `json:"algorithm"`
+	// Runs counts every time the algorithm was tried, as primary or fallback
+	Runs int64 `json:"runs"`
+	// Selections counts runs whose decision was used
+	Selections int64 `json:"selections"`
+	// BelowMinScore counts runs whose best node scored under the policy minimum
+	BelowMinScore int64 `json:"belowMinScore"`
+	// Failures counts runs that returned an error
+	Failures int64 `json:"failures"`
+	// FailureRate is Failures divided by Runs
+	FailureRate float64 `json:"failureRate"`
+	// AverageScore averages the scores of runs that produced a decision
+	AverageScore float64 `json:"averageScore"`
+	// AverageDuration averages the time spent per run
+	AverageDuration time.Duration `json:"averageDuration"`
+}
+
+// algorithmStatsRecorder accumulates per-algorithm statistics
+type algorithmStatsRecorder struct {
+	mu            sync.Mutex
+	stats         map[SchedulingAlgorithm]*AlgorithmStatistics
+	scoreTotal    map[SchedulingAlgorithm]float64
+	durationTotal map[SchedulingAlgorithm]time.Duration
+}
+
+// newAlgorithmStatsRecorder creates an empty recorder
+func newAlgorithmStatsRecorder() *algorithmStatsRecorder {
+	return &algorithmStatsRecorder{
+		stats:         make(map[SchedulingAlgorithm]*AlgorithmStatistics),
+		scoreTotal:    make(map[SchedulingAlgorithm]float64),
+		durationTotal: make(map[SchedulingAlgorithm]time.Duration),
+	}
+}
+
+// record accounts one algorithm run; score is ignored for failed runs
+func (r *algorithmStatsRecorder) record(algorithm SchedulingAlgorithm, outcome string, score float64, duration time.Duration) {
+	name := string(algorithm)
+	algorithmRuns.WithLabelValues(name, outcome).Inc()
+	algorithmDuration.WithLabelValues(name).Observe(duration.Seconds())
+	if outcome != AlgorithmOutcomeFailed {
+		algorithmScore.WithLabelValues(name).Observe(score)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[algorithm]
+	if !ok {
+		stats = &AlgorithmStatistics{Algorithm: algorithm}
+		r.stats[algorithm] = stats
+	}
+	stats.Runs++
+	r.durationTotal[algorithm] += duration
+
+	switch outcome {
+	case AlgorithmOutcomeSelected:
+		stats.Selections++
+	case AlgorithmOutcomeBelowMinScore:
+		stats.BelowMinScore++
+	case AlgorithmOutcomeFailed:
+		stats.Failures++
+	}
+	if outcome != AlgorithmOutcomeFailed {
+		r.scoreTotal[algorithm] += score
+	}
+}
+
+// snapshot returns the statistics of every algorithm that has run, ordered by name
+func (r *algorithmStatsRecorder) snapshot() []AlgorithmStatistics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]AlgorithmStatistics, 0, len(r.stats))
+	for algorithm, stats := range r.stats {
+		s := *stats
+		s.FailureRate = float64(s.Failures) / float64(s.Runs)
+		s.AverageDuration = r.durationTotal[algorithm] / time.Duration(s.Runs)
+		if scored := s.Runs - s.Failures; scored > 0 {
+			s.AverageScore = r.scoreTotal[algorithm] / float64(scored)
+		}
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Algorithm < result[j].Algorithm })
+	return result
+}
+
+// GetAlgorithmStatistics returns per-algorithm run counts, failure rates, scores and latency
+func (as *AdvancedScheduler) GetAlgorithmStatistics() []AlgorithmStatistics {
+	return as.algorithmStats.snapshot()
+}
+
+// GetAlgorithmStatistics returns the run counts, failure rate, scores and latency of the
+// scheduler's passes
+func (s *Scheduler) GetAlgorithmStatistics() []AlgorithmStatistics {
+	return s.algorithmStats.snapshot()
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// ScoreExpression is a CEL expression that contributes an extra score term to a policy.
+// The expression sees `node` and `workload` maps and must yield a bool, int or double;
+// numeric results are clamped to 0.0-1.0 and true counts as 1.0.
+//
+// Example: int(node.labels["firmware-version"]) >= 42
+type ScoreExpression struct {
+	Name       string
+	Expression string
+	Weight     float64
+}
+
+var (
+	celEnvOnce sync.Once
+	celEnv     *cel.Env
+	celEnvErr  error
+
+	// celPrograms caches compiled programs by expression source
+	celPrograms sync.Map
+)
+
+// scoreExpressionEnv returns the shared CEL environment for score expressions
+func scoreExpressionEnv() (*cel.Env, error) {
+	celEnvOnce.Do(func() {
+		celEnv, celEnvErr = cel.NewEnv(
+			ext.NativeTypes(reflect.TypeOf(&celNode{}), reflect.TypeOf(&celWorkload{}), ext.ParseStructTags(true)),
+			cel.Variable("node", cel.ObjectType("scheduler.celNode")),
+			cel.Variable("workload", cel.ObjectType("scheduler.celWorkload")),
+		)
+	})
+	return celEnv, celEnvErr
+}
+
+// CompileScoreExpression compiles and type-checks a score expression
+func CompileScoreExpression(expression string) (cel.Program, error) {
+	if cached, ok := celPrograms.Load(expression); ok {
+		return cached.(cel.Program), nil
+	}
+
+	env, err := scoreExpressionEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to compile expression %q: %w", expression, issues.Err())
+	}
+
+	switch ast.OutputType() {
+	case cel.BoolType, cel.IntType, cel.UintType, cel.DoubleType, cel.DynType:
+	default:
+		return nil, fmt.Errorf("expression %q must return bool, int or double, not %s", expression, ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build program for %q: %w", expression, err)
+	}
+
+	celPrograms.Store(expression, program)
+	return program, nil
+}
+
+// EvaluateScoreExpression evaluates a score expression for a workload on a node
+func EvaluateScoreExpression(expression string, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) (float64, error) {
+	program, err := CompileScoreExpression(expression)
+	if err != nil {
+		return 0, err
+	}
+
+	out, _, err := program.Eval(map[string]interface{}{
+		"node":     nodeActivation(node),
+		"workload": workloadActivation(wo),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to evaluate expression %q: %w", expression, err)
+	}
+
+	var score float64
+	switch v := out.Value().(type) {
+	case bool:
+		if v {
+			score = 1.0
+		}
+	case int64:
+		score = float64(v)
+	case uint64:
+		score = float64(v)
+	case float64:
+		score = v
+	default:
+		return 0, fmt.Errorf("expression %q returned unsupported type %T", expression, v)
+	}
+
+	return math.Max(0, math.Min(1, score)), nil
+}
+
+// celNode is the view of a node exposed to score expressions
+type celNode struct {
+	Name        string             `cel:"name"`
+	Labels      map[string]string  `cel:"labels"`
+	Annotations map[string]string  `cel:"annotations"`
+	Allocatable map[string]float64 `cel:"allocatable"`
+}
+
+// celWorkload is the view of a workload exposed to score expressions
+type celWorkload struct {
+	Name         string            `cel:"name"`
+	Namespace    string            `cel:"namespace"`
+	Labels       map[string]string `cel:"labels"`
+	Annotations  map[string]string `cel:"annotations"`
+	WorkloadType string            `cel:"workloadType"`
+	Priority     int64             `cel:"priority"`
+	CPU          string            `cel:"cpu"`
+	Memory       string            `cel:"memory"`
+	GPU          int64             `cel:"gpu"`
+	NPU          int64             `cel:"npu"`
+}
+
+// nodeActivation exposes node fields to CEL
+func nodeActivation(node *corev1.Node) *celNode {
+	allocatable := make(map[string]float64, len(node.Status.Allocatable))
+	for name, quantity := range node.Status.Allocatable {
+		allocatable[string(name)] = quantity.AsApproximateFloat64()
+	}
+
+	return &celNode{
+		Name:        node.Name,
+		Labels:      nonNil(node.Labels),
+		Annotations: nonNil(node.Annotations),
+		Allocatable: allocatable,
+	}
+}
+
+// workloadActivation exposes workload spec fields to CEL
+func workloadActivation(wo *kcloudv1alpha1.WorkloadOptimizer) *celWorkload {
+	return &celWorkload{
+		Name:         wo.Name,
+		Namespace:    wo.Namespace,
+		Labels:       nonNil(wo.Labels),
+		Annotations:  nonNil(wo.Annotations),
+		WorkloadType: wo.Spec.WorkloadType,
+		Priority:     int64(wo.Spec.Priority),
+		CPU:          wo.Spec.Resources.CPU,
+		Memory:       wo.Spec.Resources.Memory,
+		GPU:          int64(wo.Spec.Resources.GPU),
+		NPU:          int64(wo.Spec.Resources.NPU),
+	}
+}
+
+// nonNil returns an empty map for nil so lookups evaluate instead of erroring
+func nonNil(in map[string]string) map[string]string {
+	if in == nil {
+		return map[string]string{}
+	}
+	return in
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// DefaultConsolidationPlanTTL is how long a consolidation plan is served before it is recomputed
+const DefaultConsolidationPlanTTL = time.Minute
+
+// ConsolidationPlanner plans how to pack the cluster's workloads onto fewer nodes. Plans
+// are computed from the cluster snapshot and reused for a TTL, so frequent requests do not
+// each replan the cluster; nothing is evicted.
+type ConsolidationPlanner struct {
+	snapshot    *ClusterSnapshot
+	reader      client.Reader
+	costs       optimizer.PricingProvider
+	nodePricing optimizer.NodePricing
+	power       *optimizer.PowerCalculator
+	currency    *currency.Converter
+	ttl         time.Duration
+
+	// mu serializes planning, so concurrent requests share one plan
+	mu   sync.Mutex
+	plan *optimizer.ConsolidationPlan
+}
+
+// convertedPricing prices in the reporting currency
+type convertedPricing struct {
+	optimizer.PricingProvider
+	currency *currency.Converter
+}
+
+// CalculateCost converts the price of the resources into the reporting currency
+func (p convertedPricing) CalculateCost(cpuCores, memoryGB float64, gpuCount, npuCount int32) float64 {
+	return p.currency.FromUSD(p.PricingProvider.CalculateCost(cpuCores, memoryGB, gpuCount, npuCount))
+}
+
+// NewConsolidationPlanner creates a planner over the snapshot; reader is used to find
+// workloads whose do-not-disturb lease exempts their pods from migration
+func NewConsolidationPlanner(snapshot *ClusterSnapshot, reader client.Reader) *ConsolidationPlanner {
+	return &ConsolidationPlanner{
+		snapshot: snapshot,
+		reader:   reader,
+		costs:    optimizer.NewCostCalculator(),
+		power:    optimizer.NewPowerCalculator(),
+		ttl:      DefaultConsolidationPlanTTL,
+	}
+}
+
+// SetPricing sets the rates drained nodes' savings are priced at when their node price is unknown
+func (p *ConsolidationPlanner) SetPricing(costs optimizer.PricingProvider) {
+	p.costs = costs
+}
+
+// SetNodePricing sets the live node prices drained nodes' savings are based on, the ones the
+// scheduler estimates cost from; nil prices every node from its allocatable capacity
+func (p *ConsolidationPlanner) SetNodePricing(pricing optimizer.NodePricing) {
+	p.nodePricing = pricing
+}
+
+// SetCurrency sets the currency savings are reported in
+func (p *ConsolidationPlanner) SetCurrency(converter *currency.Converter) {
+	p.currency = converter
+}
+
+// Plan returns the minimal set of nodes able to host every pod, the ordered migrations
+// that empty the others and the estimated savings, replanning once the last plan is older
+// than the TTL
+func (p *ConsolidationPlanner) Plan(ctx context.Context) (*optimizer.ConsolidationPlan, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.plan != nil && time.Since(p.plan.GeneratedAt) < p.ttl {
+		return p.plan, nil
+	}
+	if !p.snapshot.HasSynced() {
+		return nil, fmt.Errorf("cluster snapshot has not synced yet")
+	}
+	exempt, err := exemptWorkloads(ctx, p.reader)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := optimizer.PlanConsolidation(p.snapshot.NodeUsage(func(namespace, workload string) bool {
+		return exempt[namespace+"/"+workload]
+	}), p.snapshot.PlacementFilter(), p.nodePrices(), convertedPricing{PricingProvider: p.costs, currency: p.currency},
+		p.power)
+	plan.GeneratedAt = time.Now().UTC()
+	plan.Currency = p.currency.Currency()
+	p.plan = plan
+	return plan, nil
+}
+
+// nodePrices looks up the live price of the snapshot's nodes in the reporting currency, or
+// returns nil without node pricing
+func (p *ConsolidationPlanner) nodePrices() optimizer.NodePriceLookup {
+	if p.nodePricing == nil {
+		return nil
+	}
+	nodes := p.snapshot.Nodes()
+	index := make(map[string]int, len(nodes))
+	for i := range nodes {
+		index[nodes[i].Name] = i
+	}
+	return func(name string) (float64, bool) {
+		i, ok := index[name]
+		if !ok {
+			return 0, false
+		}
+		price, ok := p.nodePricing.NodePrice(&nodes[i])
+		return p.currency.FromUSD(price), ok
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// Caps that constraint relaxation can widen
+const (
+	ConstraintCost   = "cost"
+	ConstraintPower  = "power"
+	ConstraintCarbon = "carbon"
+)
+
+// workloadCaps returns the workload's cost, power and carbon caps; zero means no cap
+func workloadCaps(wo *kcloudv1alpha1.WorkloadOptimizer) map[string]float64 {
+	caps := map[string]float64{}
+	if wo.Spec.CostConstraints != nil {
+		caps[ConstraintCost] = wo.Spec.CostConstraints.MaxCostPerHour
+	}
+	if wo.Spec.PowerConstraints != nil {
+		caps[ConstraintPower] = wo.Spec.PowerConstraints.MaxPowerUsage
+		if limit := wo.Spec.PowerConstraints.MaxCarbonPerHour; limit != nil {
+			caps[ConstraintCarbon] = *limit
+		}
+	}
+	return caps
+}
+
+// withinCaps reports whether the decision's estimates respect every positive cap
+func withinCaps(decision *SchedulingDecision, caps map[string]float64) bool {
+	if limit := caps[ConstraintCost]; limit > 0 && decision.EstimatedCost > limit {
+		return false
+	}
+	if limit := caps[ConstraintPower]; limit > 0 && decision.EstimatedPower > limit {
+		return false
+	}
+	if limit := caps[ConstraintCarbon]; limit > 0 && decision.EstimatedCarbon > limit {
+		return false
+	}
+	return true
+}
+
+// hasCaps reports whether the workload declares any positive cost, power or carbon cap
+func hasCaps(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	for _, limit := range workloadCaps(wo) {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// relaxConstraints keeps the candidates whose estimated cost, power and carbon fit the workload's
+// caps. If none fits and the workload opts into constraint relaxation, it widens the caps
+// step by step, in the declared order, and returns the candidates that fit the first
+// relaxed caps that admit any, together with the caps that had to be widened.
+func relaxConstraints(wo *kcloudv1alpha1.WorkloadOptimizer,
+	candidates []*SchedulingDecision) ([]*SchedulingDecision, []kcloudv1alpha1.RelaxedConstraint, error) {
+	var steps []kcloudv1alpha1.RelaxationStep
+	if wo.Spec.ConstraintRelaxation != nil {
+		steps = wo.Spec.ConstraintRelaxation.Steps
+	}
+
+	original := workloadCaps(wo)
+	caps := make(map[string]float64, len(original))
+	for constraint, limit := range original {
+		caps[constraint] = limit
+	}
+
+	var relaxed []kcloudv1alpha1.RelaxedConstraint
+	for step := 0; ; step++ {
+		var fitting []*SchedulingDecision
+		for _, candidate := range candidates {
+			if withinCaps(candidate, caps) {
+				fitting = append(fitting, candidate)
+			}
+		}
+		if len(fitting) > 0 {
+			return fitting, relaxed, nil
+		}
+		if step == len(steps) {
+			break
+		}
+
+		// Steps on a cap the workload does not declare have nothing to widen
+		next := steps[step]
+		limit := original[next.Constraint]
+		if limit <= 0 {
+			continue
+		}
+		caps[next.Constraint] += limit * float64(next.Percent) / 100
+		relaxed = recordRelaxation(relaxed, next.Constraint, limit, caps[next.Constraint])
+	}
+
+	if len(steps) == 0 {
+		return nil, nil, fmt.Errorf("%w: no node fits the cost, power and carbon caps", ErrNoSuitableNode)
+	}
+	return nil, nil, fmt.Errorf("%w: no node fits the cost, power and carbon caps after %d relaxation steps",
+		ErrNoSuitableNode, len(steps))
+}
+
+// recordRelaxation adds or updates the entry for a widened cap, keeping first-relaxed order
+func recordRelaxation(relaxed []kcloudv1alpha1.RelaxedConstraint, constraint string,
+	limit, relaxedLimit float64) []kcloudv1alpha1.RelaxedConstraint {
+	for i := range relaxed {
+		if relaxed[i].Constraint == constraint {
+			relaxed[i].RelaxedLimit = relaxedLimit
+			return relaxed
+		}
+	}
+	return append(relaxed, kcloudv1alpha1.RelaxedConstraint{
+		Constraint:   constraint,
+		Limit:        limit,
+		RelaxedLimit: relaxedLimit,
+	})
+}
+
+// describeRelaxation summarizes widened caps for decision reasons
+func describeRelaxation(relaxed []kcloudv1alpha1.RelaxedConstraint) string {
+	parts := make([]string, 0, len(relaxed))
+	for _, r := range relaxed {
+		parts = append(parts, fmt.Sprintf("%s cap relaxed from %.2f to %.2f", r.Constraint, r.Limit, r.RelaxedLimit))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// DefaultCPUGovernorInterval is how often the CPU frequency governors of nodes are requested
+const DefaultCPUGovernorInterval = 5 * time.Minute
+
+// CPUGovernorPublisher requests the CPU frequency governor recommended for their workloads on
+// nodes running only workloads whose recommendation is applied, for the node agent to switch
+// to. DaemonSet and mirror pods do not count. A node running workloads recommended different
+// governors gets the one with the highest frequency, and a node running any other pod keeps
+// its own governor.
+type CPUGovernorPublisher struct {
+	client   client.Client
+	interval time.Duration
+	// landed receives the nodes pods were bound to, so a pod that did not opt in lifts the
+	// node's request without waiting for the next interval
+	landed chan string
+}
+
+// NewCPUGovernorPublisher creates a publisher annotating nodes through the client
+func NewCPUGovernorPublisher(client client.Client, interval time.Duration) *CPUGovernorPublisher {
+	return &CPUGovernorPublisher{client: client, interval: interval, landed: make(chan string, 256)}
+}
+
+// WatchPods republishes as soon as a pod is bound to a node with a governor requested
+func (p *CPUGovernorPublisher) WatchPods(ctx context.Context, informers cache.Informers) error {
+	informer, err := informers.GetInformer(ctx, &corev1.Pod{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod informer: %w", err)
+	}
+	notify := func(pod *corev1.Pod) {
+		select {
+		case p.landed <- pod.Spec.NodeName:
+		default:
+			// The next interval catches up with the nodes dropped here
+		}
+	}
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok && pod.Spec.NodeName != "" {
+				notify(pod)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, okOld := oldObj.(*corev1.Pod)
+			newPod, okNew := newObj.(*corev1.Pod)
+			if okOld && okNew && oldPod.Spec.NodeName == "" && newPod.Spec.NodeName != "" {
+				notify(newPod)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch pods: %w", err)
+	}
+	return nil
+}
+
+// governed reports whether a governor is requested on the node
+func (p *CPUGovernorPublisher) governed(ctx context.Context, name string) bool {
+	var node corev1.Node
+	if err := p.client.Get(ctx, types.NamespacedName{Name: name}, &node); err != nil {
+		return false
+	}
+	_, ok := node.Annotations[optimizer.CPUGovernorAnnotation]
+	return ok
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// Publish annotates each node with the governor it should run and returns how many nodes
+// were changed
+func (p *CPUGovernorPublisher) Publish(ctx context.Context) (int, error) {
+	var workloads kcloudv1alpha1.WorkloadOptimizerList
+	if err := p.client.List(ctx, &workloads); err != nil {
+		return 0, fmt.Errorf("failed to list workload optimizers: %w", err)
+	}
+	applied := map[string]*kcloudv1alpha1.CPUFrequencyRecommendation{}
+	for i := range workloads.Items {
+		wo := &workloads.Items[i]
+		if recommendation := wo.Status.CPUFrequency; recommendation != nil && recommendation.Applied {
+			applied[wo.Namespace+"/"+wo.Name] = recommendation
+		}
+	}
+
+	var pods corev1.PodList
+	if err := p.client.List(ctx, &pods); err != nil {
+		return 0, fmt.Errorf("failed to list pods: %w", err)
+	}
+	governors := map[string]*kcloudv1alpha1.CPUFrequencyRecommendation{}
+	blocked := map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		node := pod.Spec.NodeName
+		if node == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed ||
+			podDaemon(pod) {
+			continue
+		}
+		if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		recommendation := applied[pod.Namespace+"/"+pod.Annotations[WorkloadOptimizerAnnotation]]
+		if recommendation == nil {
+			blocked[node] = true
+			continue
+		}
+		if current := governors[node]; current == nil || recommendation.FrequencyScale > current.FrequencyScale {
+			governors[node] = recommendation
+		}
+	}
+
+	var nodes corev1.NodeList
+	if err := p.client.List(ctx, &nodes); err != nil {
+		return 0, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	changed := 0
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		governor := ""
+		if recommendation := governors[node.Name]; recommendation != nil && !blocked[node.Name] {
+			governor = recommendation.Governor
+		}
+		current, ok := node.Annotations[optimizer.CPUGovernorAnnotation]
+		if current == governor && (ok || governor == "") {
+			continue
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		if governor == "" {
+			delete(node.Annotations, optimizer.CPUGovernorAnnotation)
+		} else {
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+			node.Annotations[optimizer.CPUGovernorAnnotation] = governor
+		}
+		if err := p.client.Patch(ctx, node, patch); err != nil {
+			return changed, fmt.Errorf("failed to annotate node %s: %w", node.Name, err)
+		}
+		log.FromContext(ctx).Info("Requested CPU governor", "node", node.Name, "governor", governor)
+		changed++
+	}
+	return changed, nil
+}
+
+// Start publishes the governors on each interval and whenever a pod lands on a governed node
+func (p *CPUGovernorPublisher) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("cpu-governor")
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := p.Publish(ctx); err != nil {
+			log.Error(err, "Failed to publish CPU governors")
+		}
+		if !p.wait(ctx, ticker.C) {
+			return nil
+		}
+	}
+}
+
+// wait blocks until the next interval or a pod is bound to a node with a governor
+// requested; false once the context is cancelled
+func (p *CPUGovernorPublisher) wait(ctx context.Context, tick <-chan time.Time) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-tick:
+			return true
+		case node := <-p.landed:
+			if p.governed(ctx, node) {
+				return true
+			}
+		}
+	}
+}
+
+// NeedLeaderElection lets only the leader annotate nodes
+func (p *CPUGovernorPublisher) NeedLeaderElection() bool {
+	return true
+}
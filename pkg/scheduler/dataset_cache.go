@@ -0,0 +1,174 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// DatasetCacheLabel names the shared dataset cache a workload and its pods read from
+	DatasetCacheLabel = "kcloud.io/dataset-cache"
+
+	// DatasetCacheSizeAnnotation is the size of the dataset cache, e.g. "500Gi"
+	DatasetCacheSizeAnnotation = "kcloud.io/dataset-cache-size"
+
+	// DefaultCacheTransferCostPerGB is the assumed cost of populating a cache on a new node
+	DefaultCacheTransferCostPerGB = 0.02
+)
+
+var (
+	datasetCachePlacements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kcloud_dataset_cache_placements_total",
+		Help: "Placements of workloads that declare a dataset cache, by whether they landed on a node holding it",
+	}, []string{"cache", "result"})
+	datasetCacheSavings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kcloud_dataset_cache_savings_dollars_total",
+		Help: "Estimated transfer cost avoided by placing workloads on nodes already holding their dataset cache",
+	}, []string{"cache"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(datasetCachePlacements, datasetCacheSavings)
+}
+
+// DatasetCacheStatistics summarizes placement coordination for one dataset cache
+type DatasetCacheStatistics struct {
+	Cache string `json:"cache"`
+	// Hits counts placements onto a node already holding the cache
+	Hits int64 `json:"hits"`
+	// Misses counts placements that had to populate the cache on another node
+	Misses int64 `json:"misses"`
+	// SavingsDollars is the estimated transfer cost avoided by hits
+	SavingsDollars float64 `json:"savingsDollars"`
+}
+
+// datasetCacheRecorder accumulates per-cache placement statistics
+type datasetCacheRecorder struct {
+	mu     sync.Mutex
+	stats  map[string]*DatasetCacheStatistics
+	placed map[string]string
+}
+
+// record counts a workload's placement and, for hits, the transfer cost it avoided.
+// Repeated decisions for the same workload and node are counted once.
+func (r *datasetCacheRecorder) record(workloadID, node, cache string, hit bool, savings float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stats == nil {
+		r.stats = make(map[string]*DatasetCacheStatistics)
+		r.placed = make(map[string]string)
+	}
+	if r.placed[workloadID] == node {
+		return
+	}
+	r.placed[workloadID] = node
+
+	stats, ok := r.stats[cache]
+	if !ok {
+		stats = &DatasetCacheStatistics{Cache: cache}
+		r.stats[cache] = stats
+	}
+
+	if !hit {
+		stats.Misses++
+		datasetCachePlacements.WithLabelValues(cache, "miss").Inc()
+		return
+	}
+	stats.Hits++
+	stats.SavingsDollars += savings
+	datasetCachePlacements.WithLabelValues(cache, "hit").Inc()
+	datasetCacheSavings.WithLabelValues(cache).Add(savings)
+}
+
+// snapshot returns the statistics ordered by cache name
+func (r *datasetCacheRecorder) snapshot() []DatasetCacheStatistics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]DatasetCacheStatistics, 0, len(r.stats))
+	for _, stats := range r.stats {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Cache < result[j].Cache })
+	return result
+}
+
+// datasetCache returns the dataset cache the workload declares, if any
+func datasetCache(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	return wo.Labels[DatasetCacheLabel]
+}
+
+// datasetCacheSizeGB returns the declared cache size in GB, or zero if unknown
+func datasetCacheSizeGB(wo *kcloudv1alpha1.WorkloadOptimizer) float64 {
+	size, err := resource.ParseQuantity(wo.Annotations[DatasetCacheSizeAnnotation])
+	if err != nil {
+		return 0
+	}
+	return float64(size.Value()) / (1 << 30)
+}
+
+// nodesHoldingCache returns the nodes running a pod that uses the dataset cache
+func (s *ClusterSnapshot) nodesHoldingCache(cache string) map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nodes := make(map[string]bool)
+	for _, pod := range s.pods {
+		if pod.labels[DatasetCacheLabel] == cache {
+			nodes[pod.nodeName] = true
+		}
+	}
+	return nodes
+}
+
+// cacheHoldingNodes returns the nodes holding the workload's dataset cache, or nil when it
+// declares none or no snapshot is available
+func (s *Scheduler) cacheHoldingNodes(wo *kcloudv1alpha1.WorkloadOptimizer) map[string]bool {
+	cache := datasetCache(wo)
+	if cache == "" || s.snapshot == nil {
+		return nil
+	}
+	return s.snapshot.nodesHoldingCache(cache)
+}
+
+// recordCachePlacement records whether the decision reuses the workload's dataset cache
+func (s *Scheduler) recordCachePlacement(wo *kcloudv1alpha1.WorkloadOptimizer, node string, hit bool) {
+	cache := datasetCache(wo)
+	if cache == "" {
+		return
+	}
+	s.cacheStats.record(wo.Namespace+"/"+wo.Name, node, cache, hit, datasetCacheSizeGB(wo)*s.cacheTransferCostPerGB)
+}
+
+// SetCacheTransferCost sets the cost per GB of populating a dataset cache on a new node
+func (s *Scheduler) SetCacheTransferCost(costPerGB float64) {
+	s.cacheTransferCostPerGB = costPerGB
+}
+
+// GetDatasetCacheStatistics returns placement coordination statistics per dataset cache
+func (s *Scheduler) GetDatasetCacheStatistics() []DatasetCacheStatistics {
+	return s.cacheStats.snapshot()
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// DeadlineScoreWeight is the share of a node's score given to meeting the workload's
+// deadline in deadline scheduling mode. Nodes that meet it score the full share, so among
+// them the cheaper ones still win; nodes that miss it lose at least half of it.
+const DeadlineScoreWeight = 0.5
+
+// SetDeadlineScheduling turns on earliest-deadline-first mode, in which nodes are weighed
+// against the deadlines of batch and training workloads
+func (s *Scheduler) SetDeadlineScheduling(enabled bool) {
+	s.deadlines = enabled
+}
+
+// deadlineWeight returns the share of the score given to the workload's deadline, zero
+// unless deadline scheduling is on and the workload declares how long it runs
+func (s *Scheduler) deadlineWeight(wo *kcloudv1alpha1.WorkloadOptimizer) float64 {
+	if !s.deadlines || wo.Spec.Deadline == nil || wo.Spec.Deadline.ExpectedDuration == nil {
+		return 0
+	}
+	return DeadlineScoreWeight
+}
+
+// deadlineAtRisk reports whether the workload's deadline is weighed and only nodes faster
+// than a typical node would meet it
+func (s *Scheduler) deadlineAtRisk(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	return s.deadlineWeight(wo) > 0 && optimizer.DeadlineAtRisk(wo, time.Now())
+}
+
+// deadlineFit rates how well the node meets the workload's deadline from now
+func deadlineFit(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) float64 {
+	return optimizer.DeadlineFit(wo, &node, time.Now())
+}
+
+// podDeadline returns the deadline the pod's workload must complete by
+func podDeadline(pod *corev1.Pod) (time.Time, bool) {
+	deadline, err := time.Parse(time.RFC3339, pod.Annotations[optimizer.DeadlineAnnotation])
+	return deadline, err == nil
+}
+
+// podPriority returns the pod's priority, zero when it has none
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
+
+// DeadlineLess orders pending pods earliest deadline first: higher priority pods go first
+// as with the default queue sort, then, within a priority, pods whose workload has the
+// earlier deadline, then pods with a deadline before those without, then the pod queued first
+func DeadlineLess(a *corev1.Pod, aQueued time.Time, b *corev1.Pod, bQueued time.Time) bool {
+	if pa, pb := podPriority(a), podPriority(b); pa != pb {
+		return pa > pb
+	}
+	da, aHas := podDeadline(a)
+	db, bHas := podDeadline(b)
+	if aHas != bHas {
+		return aHas
+	}
+	if aHas && !da.Equal(db) {
+		return da.Before(db)
+	}
+	return aQueued.Before(bQueued)
+}
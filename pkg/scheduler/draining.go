@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeDrainingAnnotation marks a node that is being drained; any non-empty value other than
+// "false" excludes the node from scheduling
+const NodeDrainingAnnotation = "kcloud.io/draining"
+
+// drainingTaintKeys are taints that node lifecycle controllers set before removing a node
+var drainingTaintKeys = []string{
+	// Cluster autoscaler scale-down
+	"ToBeDeletedByClusterAutoscaler",
+	// Karpenter disruption
+	"karpenter.sh/disrupted",
+	"karpenter.sh/disruption",
+}
+
+// NodeUnschedulableReason explains why a node is cordoned or draining, or returns an empty
+// string when workloads may be placed on it. Tolerations do not override these markers,
+// since the node is about to go away
+func NodeUnschedulableReason(node *corev1.Node) string {
+	if node.Spec.Unschedulable {
+		return "node is cordoned"
+	}
+	if value, ok := node.Annotations[NodeDrainingAnnotation]; ok && value != "" && value != "false" {
+		return fmt.Sprintf("node is annotated %s=%s", NodeDrainingAnnotation, value)
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range drainingTaintKeys {
+			if taint.Key == key {
+				return fmt.Sprintf("node is draining (taint %s)", key)
+			}
+		}
+	}
+	return ""
+}
+
+// SchedulableNodes returns the nodes that are neither cordoned nor draining
+func SchedulableNodes(nodes []corev1.Node) []corev1.Node {
+	schedulable := make([]corev1.Node, 0, len(nodes))
+	for i := range nodes {
+		if NodeUnschedulableReason(&nodes[i]) == "" {
+			schedulable = append(schedulable, nodes[i])
+		}
+	}
+	return schedulable
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// constraintCapsFilter names the cost, power and carbon caps applied under constraint relaxation
+const constraintCapsFilter = "ConstraintCaps"
+
+// Explanation describes how the scheduler would place a workload, node by node, in a
+// form suited to tools and user interfaces
+type Explanation struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// SelectedNode is the node the workload would be placed on, empty when none fits
+	SelectedNode string `json:"selectedNode,omitempty"`
+	// Reason explains the selection, or summarizes why every node was rejected
+	Reason string `json:"reason"`
+	// RelaxedConstraints lists the caps that would be widened to place the workload
+	RelaxedConstraints []kcloudv1alpha1.RelaxedConstraint `json:"relaxedConstraints,omitempty"`
+	// Nodes explains each node in the order given
+	Nodes []NodeExplanation `json:"nodes"`
+}
+
+// NodeExplanation reports the filter results and, for feasible nodes, the score breakdown
+type NodeExplanation struct {
+	Node     string         `json:"node"`
+	Feasible bool           `json:"feasible"`
+	Selected bool           `json:"selected,omitempty"`
+	Filters  []FilterResult `json:"filters"`
+	// Scores is set for nodes that pass every filter other than the constraint caps
+	Scores *CandidateScore `json:"scores,omitempty"`
+}
+
+// FilterResult is the outcome of one scheduling requirement on a node
+type FilterResult struct {
+	Filter string `json:"filter"`
+	Passed bool   `json:"passed"`
+	// Reason explains why the node failed the filter
+	Reason string `json:"reason,omitempty"`
+}
+
+// Explain evaluates every filter on every node and scores the nodes that pass, without
+// recording the outcome. Unlike ScheduleWorkload it does not stop at a node's first failed
+// filter, and it does not skip pools the infeasibility cache has ruled out.
+func (s *Scheduler) Explain(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node) (*Explanation, error) {
+	explanation := &Explanation{
+		Namespace: wo.Namespace,
+		Name:      wo.Name,
+		Nodes:     make([]NodeExplanation, len(nodes)),
+	}
+
+	decisions := make([]*SchedulingDecision, len(nodes))
+	var rejections []NodeRejection
+	for i, node := range nodes {
+		result := NodeExplanation{Node: node.Name, Feasible: true}
+		for _, filter := range nodeFilters {
+			reason := filter.check(s, wo, node)
+			result.Filters = append(result.Filters, FilterResult{Filter: filter.name, Passed: reason == "", Reason: reason})
+			if reason != "" && result.Feasible {
+				result.Feasible = false
+				rejections = append(rejections, NodeRejection{Node: node.Name, Reason: reason})
+			}
+		}
+		if result.Feasible {
+			decision, err := s.evaluateNode(ctx, wo, node)
+			if err != nil {
+				return nil, fmt.Errorf("failed to score node %s: %w", node.Name, err)
+			}
+			decisions[i] = decision
+		}
+		explanation.Nodes[i] = result
+	}
+
+	current := s.applyStickinessBonus(wo, decisions)
+	var candidates []*SchedulingDecision
+	for _, decision := range decisions {
+		if decision != nil {
+			candidates = append(candidates, decision)
+		}
+	}
+
+	fitting, relaxed, err := relaxConstraints(wo, candidates)
+	if err != nil {
+		fitting = nil
+	}
+	var selected *SchedulingDecision
+	if len(fitting) > 0 {
+		selected, _ = s.chooseDecision(wo, fitting, current)
+		explanation.SelectedNode = selected.SelectedNode
+		explanation.Reason = selected.Reason
+		explanation.RelaxedConstraints = relaxed
+		if len(relaxed) > 0 {
+			explanation.Reason += "; " + describeRelaxation(relaxed)
+		}
+	}
+
+	for i, decision := range decisions {
+		if decision == nil {
+			continue
+		}
+		scores := candidateScore(decision)
+		result := &explanation.Nodes[i]
+		result.Scores = &scores
+		result.Selected = decision == selected
+		if !hasCaps(wo) {
+			continue
+		}
+		if slices.Contains(fitting, decision) {
+			result.Filters = append(result.Filters, FilterResult{Filter: constraintCapsFilter, Passed: true})
+			continue
+		}
+		reason := "estimated cost, power or carbon exceeds the workload's caps"
+		result.Filters = append(result.Filters, FilterResult{Filter: constraintCapsFilter, Reason: reason})
+		result.Feasible = false
+		rejections = append(rejections, NodeRejection{Node: decision.SelectedNode, Reason: reason})
+	}
+
+	if selected == nil {
+		explanation.Reason = "no suitable node"
+		if len(rejections) > 0 {
+			explanation.Reason += ": " + SummarizeRejections(rejections)
+		}
+	}
+	return explanation, nil
+}
+
+// PreviewPlacement returns the node Explain selects for the workload, so simulations can
+// place hypothetical workloads without recording anything
+func (s *Scheduler) PreviewPlacement(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	nodes []corev1.Node) (optimizer.PlacementPreview, error) {
+	explanation, err := s.Explain(ctx, wo, nodes)
+	if err != nil {
+		return optimizer.PlacementPreview{}, err
+	}
+	return optimizer.PlacementPreview{Node: explanation.SelectedNode, Reason: explanation.Reason}, nil
+}
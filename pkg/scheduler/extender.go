@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// MaxExtenderPriority is the highest score an extender may return to kube-scheduler
+const MaxExtenderPriority int64 = 10
+
+// ExtenderArgs mirrors the kube-scheduler extender request payload
+type ExtenderArgs struct {
+	Pod       *corev1.Pod      `json:"pod"`
+	Nodes     *corev1.NodeList `json:"nodes,omitempty"`
+	NodeNames *[]string        `json:"nodenames,omitempty"`
+}
+
+// ExtenderFilterResult mirrors the kube-scheduler extender filter response
+type ExtenderFilterResult struct {
+	Nodes                      *corev1.NodeList  `json:"nodes,omitempty"`
+	NodeNames                  *[]string         `json:"nodenames,omitempty"`
+	FailedNodes                map[string]string `json:"failedNodes,omitempty"`
+	FailedAndUnresolvableNodes map[string]string `json:"failedAndUnresolvableNodes,omitempty"`
+	Error                      string            `json:"error,omitempty"`
+}
+
+// HostPriority is the score of a single node as returned to kube-scheduler
+type HostPriority struct {
+	Host  string `json:"host"`
+	Score int64  `json:"score"`
+}
+
+// HostPriorityList is the prioritize response returned to kube-scheduler
+type HostPriorityList []HostPriority
+
+// FilterNodes splits nodes into those that can run the workload and those that cannot
+func (s *Scheduler) FilterNodes(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node) ([]corev1.Node, map[string]string) {
+	log := log.FromContext(ctx)
+
+	feasible := make([]corev1.Node, 0, len(nodes))
+	failed := make(map[string]string)
+
+	for _, node := range nodes {
+		if !s.nodeMeetsRequirements(wo, node) {
+			failed[node.Name] = "node does not meet workload requirements"
+			continue
+		}
+		feasible = append(feasible, node)
+	}
+
+	log.V(1).Info("Extender filter completed",
+		"workload", wo.Name,
+		"feasible", len(feasible),
+		"failed", len(failed))
+
+	return feasible, failed
+}
+
+// PrioritizeNodes scores nodes on the extender scale using the operator's node evaluation
+func (s *Scheduler) PrioritizeNodes(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node) (HostPriorityList, error) {
+	priorities := make(HostPriorityList, 0, len(nodes))
+
+	for _, node := range nodes {
+		decision, err := s.evaluateNode(ctx, wo, node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate node %s: %w", node.Name, err)
+		}
+
+		priorities = append(priorities, HostPriority{Host: node.Name, Score: scaleScore(decision.Score, MaxExtenderPriority)})
+	}
+
+	return priorities, nil
+}
+
+// WorkloadFromPod builds a transient WorkloadOptimizer from a pod's own requests and annotations
+func WorkloadFromPod(pod *corev1.Pod) *kcloudv1alpha1.WorkloadOptimizer {
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	var gpu, npu int64
+	var migProfile string
+
+	for _, container := range pod.Spec.Containers {
+		if q, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			cpu.Add(q)
+		}
+		if q, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			memory.Add(q)
+		}
+		if q, ok := container.Resources.Limits["nvidia.com/gpu"]; ok {
+			gpu += q.Value()
+		}
+		if q, ok := container.Resources.Limits["npu.com/npu"]; ok {
+			npu += q.Value()
+		}
+		for name := range container.Resources.Limits {
+			if profile, ok := strings.CutPrefix(string(name), migResourcePrefix); ok {
+				migProfile = profile
+			}
+		}
+	}
+
+	wo := &kcloudv1alpha1.WorkloadOptimizer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		Spec: kcloudv1alpha1.WorkloadOptimizerSpec{
+			WorkloadType: pod.Annotations[WorkloadTypeAnnotation],
+			Resources: kcloudv1alpha1.ResourceRequirements{
+				CPU:         cpu.String(),
+				Memory:      memory.String(),
+				GPU:         int32(gpu),
+				GPUFraction: pod.Annotations[GPUFractionAnnotation],
+				GPUMemory:   pod.Annotations[GPUMemoryAnnotation],
+				MIGProfile:  migProfile,
+				NPU:         int32(npu),
+			},
+		},
+	}
+
+	if pod.Annotations["kcloud.io/prefer-spot-instances"] == "true" {
+		wo.Spec.CostConstraints = &kcloudv1alpha1.CostConstraints{PreferSpot: true}
+	}
+	if pod.Annotations["kcloud.io/prefer-green-energy"] == "true" {
+		wo.Spec.PowerConstraints = &kcloudv1alpha1.PowerConstraints{PreferGreen: true}
+	}
+
+	return wo
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+const (
+	// DefaultFragmentationInterval is how often fragmentation is measured
+	DefaultFragmentationInterval = 5 * time.Minute
+	// DefaultMaxDefragmentationMigrations bounds the migrations in one plan
+	DefaultMaxDefragmentationMigrations = 10
+)
+
+var (
+	fragmentationRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_cluster_fragmentation_ratio",
+		Help: "Share of free capacity the most common request shape cannot use, by resource",
+	}, []string{"resource"})
+	fragmentationUnusable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_cluster_fragmented_capacity",
+		Help: "Free capacity the most common request shape cannot use, in resource units",
+	}, []string{"resource"})
+	defragmentationProjected = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kcloud_defragmentation_projected_ratio",
+		Help: "Fragmentation ratio expected after applying the recommended migrations, by resource",
+	}, []string{"resource"})
+	defragmentationMigrations = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kcloud_defragmentation_recommended_migrations",
+		Help: "Pod migrations in the current defragmentation recommendation",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(fragmentationRatio, fragmentationUnusable,
+		defragmentationProjected, defragmentationMigrations)
+}
+
+// FragmentationMonitor periodically measures resource fragmentation from the cluster
+// snapshot and keeps a plan of migrations that would reduce it. The plan is a
+// recommendation; nothing is migrated.
+type FragmentationMonitor struct {
+	snapshot      *ClusterSnapshot
+	reader        client.Reader
+	interval      time.Duration
+	maxMigrations int
+
+	mu   sync.RWMutex
+	plan *optimizer.DefragmentationPlan
+}
+
+// NewFragmentationMonitor creates a monitor over the snapshot; reader is used to find
+// workloads whose do-not-disturb lease exempts their pods from migration
+func NewFragmentationMonitor(snapshot *ClusterSnapshot, reader client.Reader, interval time.Duration,
+	maxMigrations int) *FragmentationMonitor {
+	return &FragmentationMonitor{
+		snapshot:      snapshot,
+		reader:        reader,
+		interval:      interval,
+		maxMigrations: maxMigrations,
+	}
+}
+
+// Refresh measures fragmentation, plans migrations and updates the metrics
+func (m *FragmentationMonitor) Refresh(ctx context.Context) (*optimizer.DefragmentationPlan, error) {
+	exempt, err := exemptWorkloads(ctx, m.reader)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := optimizer.PlanDefragmentation(m.snapshot.NodeUsage(func(namespace, workload string) bool {
+		return exempt[namespace+"/"+workload]
+	}), m.maxMigrations, m.snapshot.PlacementFilter())
+	plan.GeneratedAt = time.Now().UTC()
+
+	for i, before := range plan.Before {
+		fragmentationRatio.WithLabelValues(string(before.Resource)).Set(before.Score)
+		fragmentationUnusable.WithLabelValues(string(before.Resource)).Set(before.Unusable.AsApproximateFloat64())
+		defragmentationProjected.WithLabelValues(string(before.Resource)).Set(plan.After[i].Score)
+	}
+	defragmentationMigrations.Set(float64(len(plan.Migrations)))
+
+	m.mu.Lock()
+	m.plan = plan
+	m.mu.Unlock()
+	return plan, nil
+}
+
+// exemptWorkloads returns the workloads, as namespace/name, under an active do-not-disturb lease
+func exemptWorkloads(ctx context.Context, reader client.Reader) (map[string]bool, error) {
+	var list kcloudv1alpha1.WorkloadOptimizerList
+	if err := reader.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list workloads: %w", err)
+	}
+
+	now := time.Now()
+	exempt := make(map[string]bool)
+	for i := range list.Items {
+		wo := &list.Items[i]
+		lease, err := optimizer.DoNotDisturbLease(wo)
+		// An unreadable lease is treated as active rather than risk disturbing the workload
+		if err != nil || lease.Active(now) {
+			exempt[wo.Namespace+"/"+wo.Name] = true
+		}
+	}
+	return exempt, nil
+}
+
+// DefragmentationPlan returns the latest plan, or nil before the first measurement
+func (m *FragmentationMonitor) DefragmentationPlan() *optimizer.DefragmentationPlan {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.plan
+}
+
+// Start measures fragmentation on each interval once the snapshot has synced
+func (m *FragmentationMonitor) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("fragmentation")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if !m.snapshot.HasSynced() {
+				continue
+			}
+			plan, err := m.Refresh(ctx)
+			if err != nil {
+				log.Error(err, "Failed to measure fragmentation")
+				continue
+			}
+			if len(plan.Migrations) > 0 {
+				log.Info("Defragmentation recommended",
+					"recommendation", plan.Recommendation(),
+					"migrations", len(plan.Migrations))
+			}
+		}
+	}
+}
+
+// NeedLeaderElection lets every replica report fragmentation
+func (m *FragmentationMonitor) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+const (
+	// PluginName is the name under which the scoring plugin is registered with kube-scheduler
+	PluginName = "KCloudCostPower"
+
+	// SchedulerName is the scheduler profile name pods use to opt into kcloud scheduling
+	SchedulerName = "kcloud-scheduler"
+
+	// WorkloadOptimizerAnnotation links a pod to the WorkloadOptimizer that manages it
+	WorkloadOptimizerAnnotation = "kcloud.io/workload-optimizer"
+
+	// WorkloadTypeAnnotation carries the workload type of the pod's WorkloadOptimizer
+	WorkloadTypeAnnotation = "kcloud.io/workload-type"
+)
+
+// NodePlugin adapts the scheduler's filter and score logic to per-node plugin calls,
+// shared by the scheduler extender and the scheduler-framework plugin
+type NodePlugin struct {
+	Scheduler *Scheduler
+	Client    client.Client
+}
+
+// NewNodePlugin creates a new node plugin backed by the given scheduler
+func NewNodePlugin(s *Scheduler, c client.Client) *NodePlugin {
+	return &NodePlugin{
+		Scheduler: s,
+		Client:    c,
+	}
+}
+
+// Filter reports whether the pod's workload, as returned by ResolveWorkload, can run on the
+// node and why not
+func (p *NodePlugin) Filter(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, pod *corev1.Pod,
+	node *corev1.Node) (bool, string, error) {
+	if !p.Scheduler.nodeMeetsRequirements(wo, *node) {
+		return false, "node does not meet workload requirements", nil
+	}
+
+	if wo.Spec.Resources.GPU > 0 {
+		inUse, err := p.gpusInUse(ctx, pod, node)
+		if err != nil {
+			return false, "", err
+		}
+		if fits, reason := checkGPUMode(node, int64(wo.Spec.Resources.GPU), inUse); !fits {
+			return false, reason, nil
+		}
+	}
+
+	if wo.Spec.Resources.GPUFraction != "" {
+		index, reason, err := p.checkGPUFraction(ctx, pod, node, wo)
+		if err != nil {
+			return false, "", err
+		}
+		if index < 0 {
+			return false, reason, nil
+		}
+	}
+	return true, "", nil
+}
+
+// Score returns the node's score for the pod's workload, as returned by ResolveWorkload, on
+// a 0..maxScore scale
+func (p *NodePlugin) Score(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, pod *corev1.Pod,
+	node *corev1.Node, maxScore int64) (int64, error) {
+	// A recurring run prefers the node reserved for it, as long as it still passes Filter
+	if reserved, ok := reservedNode(wo); ok && node.Name == reserved {
+		return maxScore, nil
+	}
+
+	decision, err := p.Scheduler.evaluateNode(ctx, wo, *node)
+	if err != nil {
+		return 0, fmt.Errorf("failed to evaluate node %s: %w", node.Name, err)
+	}
+
+	score := decision.Score
+	if wo.Spec.Resources.GPU > 0 {
+		inUse, err := p.gpusInUse(ctx, pod, node)
+		if err != nil {
+			return 0, err
+		}
+		packing := gpuPackingScore(node, int64(wo.Spec.Resources.GPU), inUse)
+		score = score*(1-gpuPackingWeight) + packing*gpuPackingWeight
+	}
+	if share, err := workloadGPUShare(wo); err == nil {
+		requested, err := p.requestedOnNode(ctx, pod, node)
+		if err != nil {
+			return 0, err
+		}
+		packing := gpuSharingScore(node, requested, share)
+		score = score*(1-gpuPackingWeight) + packing*gpuPackingWeight
+	}
+	return scaleScore(score, maxScore), nil
+}
+
+// ResolveWorkload returns the pod's WorkloadOptimizer, falling back to the pod's own requests.
+// Callers resolve it once per scheduling cycle and pass it to Filter, Score and AssignGPU.
+func (p *NodePlugin) ResolveWorkload(ctx context.Context, pod *corev1.Pod) (*kcloudv1alpha1.WorkloadOptimizer, error) {
+	name, exists := pod.Annotations[WorkloadOptimizerAnnotation]
+	if !exists || p.Client == nil {
+		return WorkloadFromPod(pod), nil
+	}
+
+	var wo kcloudv1alpha1.WorkloadOptimizer
+	key := types.NamespacedName{Namespace: pod.Namespace, Name: name}
+	if err := p.Client.Get(ctx, key, &wo); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.FromContext(ctx).V(1).Info("WorkloadOptimizer not found, scoring pod requests", "workloadOptimizer", key)
+			return WorkloadFromPod(pod), nil
+		}
+		return nil, fmt.Errorf("failed to get WorkloadOptimizer %s: %w", key, err)
+	}
+	// Pods admitted while the workload was packed hold a GPU share rather than a whole GPU
+	if fraction := pod.Annotations[GPUFractionAnnotation]; fraction != "" && wo.Spec.Resources.GPUFraction == "" {
+		wo.Spec.Resources.GPU = 0
+		wo.Spec.Resources.GPUFraction = fraction
+	}
+	return &wo, nil
+}
+
+// reservedNode returns the node precomputed for the workload's current recurring run
+func reservedNode(wo *kcloudv1alpha1.WorkloadOptimizer) (string, bool) {
+	placement := wo.Status.PrecomputedPlacement
+	if !optimizer.PlacementValid(placement, time.Now()) {
+		return "", false
+	}
+	return placement.NodeName, true
+}
+
+// scaleScore converts a 0.0-1.0 score to an integer score in 0..maxScore
+func scaleScore(score float64, maxScore int64) int64 {
+	scaled := int64(math.Round(score * float64(maxScore)))
+	if scaled < 0 {
+		return 0
+	}
+	if scaled > maxScore {
+		return maxScore
+	}
+	return scaled
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// GPUMemoryAnnotation carries the VRAM a pod needs on each GPU it uses
+	GPUMemoryAnnotation = "kcloud.io/gpu-memory"
+
+	// gpuMemoryLabel is published by GPU feature discovery with the VRAM of each card in MiB
+	gpuMemoryLabel = "nvidia.com/gpu.memory"
+
+	// gpuMemoryResourcePrefix prefixes the per-device resources the VRAM of fractional
+	// pods is accounted under
+	gpuMemoryResourcePrefix = "kcloud.io/gpu-memory-"
+)
+
+// gpuMemoryResource names the resource the VRAM of fractional pods on a physical GPU is
+// accounted under
+func gpuMemoryResource(index int) corev1.ResourceName {
+	return corev1.ResourceName(gpuMemoryResourcePrefix + strconv.Itoa(index))
+}
+
+// gpuCardMemory returns the VRAM of each of the node's GPUs in bytes, or zero when the
+// node does not publish it
+func gpuCardMemory(node *corev1.Node) int64 {
+	mib, err := strconv.ParseInt(node.Labels[gpuMemoryLabel], 10, 64)
+	if err != nil || mib < 0 {
+		return 0
+	}
+	return mib << 20
+}
+
+// workloadGPUMemory returns the VRAM the workload needs per GPU in bytes, zero when undeclared
+func workloadGPUMemory(wo *kcloudv1alpha1.WorkloadOptimizer) (int64, error) {
+	if wo.Spec.Resources.GPUMemory == "" {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(wo.Spec.Resources.GPUMemory)
+	if err != nil {
+		return 0, fmt.Errorf("invalid GPU memory %q: %w", wo.Spec.Resources.GPUMemory, err)
+	}
+	return q.Value(), nil
+}
+
+// MIGProfileMemory returns the VRAM of a MIG profile such as 1g.5gb in bytes, or zero
+// when the profile name is malformed
+func MIGProfileMemory(profile string) int64 {
+	_, memory, ok := strings.Cut(profile, "g.")
+	if !ok {
+		return 0
+	}
+	gb, err := strconv.ParseInt(strings.TrimSuffix(memory, "gb"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return gb << 30
+}
+
+// gpuMemoryReason explains why a card cannot hold the requested VRAM
+func gpuMemoryReason(node *corev1.Node, memory int64) string {
+	requested := resource.NewQuantity(memory, resource.BinarySI)
+	cardMemory := gpuCardMemory(node)
+	if cardMemory == 0 {
+		return fmt.Sprintf("node does not publish GPU memory, workload needs %s per GPU", requested)
+	}
+	return fmt.Sprintf("node GPUs have %s memory, workload needs %s per GPU",
+		resource.NewQuantity(cardMemory, resource.BinarySI), requested)
+}
+
+// checkGPUMemory reports whether the VRAM a workload needs per GPU fits the node's whole
+// GPUs or MIG instances. Time-sliced GPUs are rejected: the device plugin hands out
+// replicas without telling which card they share, so VRAM cannot be kept per card.
+// Fractional workloads are checked per card when a device is picked for them.
+func checkGPUMemory(node *corev1.Node, wo *kcloudv1alpha1.WorkloadOptimizer) (bool, string) {
+	memory, err := workloadGPUMemory(wo)
+	if err != nil {
+		return false, err.Error()
+	}
+	if memory == 0 {
+		return true, ""
+	}
+
+	switch {
+	case wo.Spec.Resources.MIGProfile != "":
+		if available := MIGProfileMemory(wo.Spec.Resources.MIGProfile); memory > available {
+			return false, fmt.Sprintf("MIG profile %s has %s memory, workload needs %s", wo.Spec.Resources.MIGProfile,
+				resource.NewQuantity(available, resource.BinarySI), resource.NewQuantity(memory, resource.BinarySI))
+		}
+	case wo.Spec.Resources.GPU > 0:
+		if memory > gpuCardMemory(node) {
+			return false, gpuMemoryReason(node, memory)
+		}
+		allocatable := node.Status.Allocatable["nvidia.com/gpu"]
+		if allocatable.Value() > physicalGPUs(node) {
+			return false, "node time-slices its GPUs, so GPU memory cannot be reserved per card"
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// GPUComputeModeLabel is published by the node agent with the node's GPU compute mode
+	GPUComputeModeLabel = "kcloud.io/gpu-compute-mode"
+
+	// GPUComputeModeDefault lets several processes share a GPU
+	GPUComputeModeDefault = "default"
+
+	// GPUComputeModeExclusive allows a single process per GPU
+	GPUComputeModeExclusive = "exclusive-process"
+
+	// PodNodeNameIndex indexes pods by the node they are bound to
+	PodNodeNameIndex = "spec.nodeName"
+
+	// gpuPackingWeight is the share of a GPU workload's node score given to packing
+	gpuPackingWeight = 0.2
+
+	// gpuCountLabel is published by GPU feature discovery with the physical device count,
+	// which differs from allocatable when devices are time-sliced
+	gpuCountLabel = "nvidia.com/gpu.count"
+)
+
+// NodeGPUComputeMode returns the node's GPU compute mode, defaulting to shared
+func NodeGPUComputeMode(node *corev1.Node) string {
+	if node.Labels[GPUComputeModeLabel] == GPUComputeModeExclusive {
+		return GPUComputeModeExclusive
+	}
+	return GPUComputeModeDefault
+}
+
+// ParseComputeMode maps nvidia-smi compute_mode output, one line per device, to a node
+// compute mode; a node is exclusive when any of its devices is
+func ParseComputeMode(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), "Exclusive_Process") {
+			return GPUComputeModeExclusive
+		}
+	}
+	return GPUComputeModeDefault
+}
+
+// physicalGPUs returns the number of physical GPUs on the node
+func physicalGPUs(node *corev1.Node) int64 {
+	if count, err := strconv.ParseInt(node.Labels[gpuCountLabel], 10, 64); err == nil {
+		return count
+	}
+	allocatable := node.Status.Allocatable["nvidia.com/gpu"]
+	return allocatable.Value()
+}
+
+// podGPUs returns the number of GPUs a pod's containers are limited to
+func podGPUs(pod *corev1.Pod) int64 {
+	var gpus int64
+	for _, container := range pod.Spec.Containers {
+		if q, ok := container.Resources.Limits["nvidia.com/gpu"]; ok {
+			gpus += q.Value()
+		}
+	}
+	return gpus
+}
+
+// checkGPUMode reports whether a workload needing requested GPUs fits a node that
+// already has inUse GPUs assigned. Exclusive-mode devices host a single process each,
+// so the workload needs whole devices that no other pod holds.
+func checkGPUMode(node *corev1.Node, requested, inUse int64) (bool, string) {
+	if requested == 0 || NodeGPUComputeMode(node) != GPUComputeModeExclusive {
+		return true, ""
+	}
+	if free := physicalGPUs(node) - inUse; requested > free {
+		return false, fmt.Sprintf("node has %d free exclusive-process GPUs, workload needs %d", free, requested)
+	}
+	return true, ""
+}
+
+// gpuPackingScore prefers packing GPU workloads onto shared GPUs that are already in
+// use, leaving idle and exclusive devices free for jobs that need them
+func gpuPackingScore(node *corev1.Node, requested, inUse int64) float64 {
+	physical := physicalGPUs(node)
+	if requested == 0 || physical == 0 {
+		return 0.5
+	}
+	if NodeGPUComputeMode(node) == GPUComputeModeExclusive {
+		return 0.25
+	}
+	return 0.5 + 0.5*math.Min(float64(inUse)/float64(physical), 1.0)
+}
+
+// IndexPodsByNode registers the pod index the node plugin uses to count GPUs in use
+func IndexPodsByNode(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &corev1.Pod{}, PodNodeNameIndex, func(obj client.Object) []string {
+		pod := obj.(*corev1.Pod)
+		if pod.Spec.NodeName == "" {
+			return nil
+		}
+		return []string{pod.Spec.NodeName}
+	})
+}
+
+// gpusInUse counts the GPUs held by running pods on the node, other than the pod being scheduled
+func (p *NodePlugin) gpusInUse(ctx context.Context, pod *corev1.Pod, node *corev1.Node) (int64, error) {
+	pods, err := p.podsOnNode(ctx, pod, node)
+	if err != nil {
+		return 0, err
+	}
+
+	var inUse int64
+	for i := range pods {
+		inUse += podGPUs(&pods[i])
+	}
+	return inUse, nil
+}
+
+// reservedGPUs counts the GPUs reserved on the node by workloads other than exclude
+func (as *AdvancedScheduler) reservedGPUs(nodeName, exclude string) int64 {
+	reserved := as.reservedOnNode(nodeName, exclude)["nvidia.com/gpu"]
+	return reserved.Value()
+}
+
+// gpuModePlugin keeps GPU workloads off exclusive-process devices that are already
+// taken and off cards without the VRAM they need, and packs them onto shared devices
+type gpuModePlugin struct {
+	as *AdvancedScheduler
+}
+
+func (p *gpuModePlugin) Name() string { return PluginGPUMode }
+
+func (p *gpuModePlugin) Filter(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, _ *SchedulingPolicy) (bool, string) {
+	if fits, reason := checkGPUMemory(node, wo); !fits {
+		return false, reason
+	}
+	return checkGPUMode(node, int64(wo.Spec.Resources.GPU), p.as.reservedGPUs(node.Name, wo.Name))
+}
+
+func (p *gpuModePlugin) Score(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
+	return gpuPackingScore(node, int64(wo.Spec.Resources.GPU), p.as.reservedGPUs(node.Name, wo.Name))
+}
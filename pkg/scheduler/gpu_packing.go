@@ -0,0 +1,286 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// GPUUtilizationAnnotation is published by the node agent on each node with the GPU
+	// utilization of the pods running there, as a JSON map of pod UID to percent
+	GPUUtilizationAnnotation = "kcloud.io/gpu-pod-utilization"
+
+	// GPUPackingAnnotation set to "false" on a WorkloadOptimizer keeps it on whole GPUs
+	GPUPackingAnnotation = "kcloud.io/gpu-packing"
+
+	// DefaultGPUPackingHeadroom is the utilization added to the measured peak when sizing a share
+	DefaultGPUPackingHeadroom = 0.1
+
+	// DefaultGPUPackingWindow is how far back utilization samples count toward the peak
+	DefaultGPUPackingWindow = time.Hour
+
+	// DefaultGPUPackingMinSamples is how many samples a workload needs before it is packed or unpacked
+	DefaultGPUPackingMinSamples = 10
+
+	// maxGPUUtilizationSamples caps the samples kept per workload
+	maxGPUUtilizationSamples = 1024
+)
+
+var (
+	gpuPackedWorkloads = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kcloud_gpu_packed_workloads",
+		Help: "Inference workloads packed onto time-shared GPUs",
+	})
+	gpuPackingReclaimed = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kcloud_gpu_packing_reclaimed_gpus",
+		Help: "GPUs freed per pod of each packed workload, summed over the packed workloads",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(gpuPackedWorkloads, gpuPackingReclaimed)
+}
+
+// podCgroupPattern finds the pod UID in a container's cgroup path, written with dashes by
+// the cgroupfs driver and with underscores by the systemd driver
+var podCgroupPattern = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+
+// ParseProcessUtilization maps nvidia-smi pmon output to the SM utilization percent of
+// each process, summed over the devices a process uses
+func ParseProcessUtilization(output string) map[int]float64 {
+	utilization := map[int]float64{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		// pmon prints "-" for processes that did not run in the sample period
+		sm, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil {
+			sm = 0
+		}
+		utilization[pid] += sm
+	}
+	return utilization
+}
+
+// PodUIDFromCgroup returns the UID of the pod owning a process given its /proc/<pid>/cgroup
+// content, or "" for processes outside pods
+func PodUIDFromCgroup(cgroup string) string {
+	match := podCgroupPattern.FindStringSubmatch(cgroup)
+	if match == nil {
+		return ""
+	}
+	return strings.ReplaceAll(match[1], "_", "-")
+}
+
+// nodeGPUUtilization returns the published GPU utilization percent per pod UID on the node
+func nodeGPUUtilization(node *corev1.Node) map[string]float64 {
+	if node == nil {
+		return nil
+	}
+	value, ok := node.Annotations[GPUUtilizationAnnotation]
+	if !ok {
+		return nil
+	}
+	var utilization map[string]float64
+	if err := json.Unmarshal([]byte(value), &utilization); err != nil {
+		return nil
+	}
+	return utilization
+}
+
+// WorkloadGPUUtilization returns the highest GPU utilization (0.0-1.0) among the workload's
+// pods the node agents reported, or false when none was reported
+func (s *ClusterSnapshot) WorkloadGPUUtilization(namespace, workload string) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byNode := map[string]map[string]float64{}
+	peak, found := 0.0, false
+	for uid, pod := range s.pods {
+		if pod.namespace != namespace || pod.workload != workload {
+			continue
+		}
+		utilization, ok := byNode[pod.nodeName]
+		if !ok {
+			utilization = nodeGPUUtilization(s.nodes[pod.nodeName])
+			byNode[pod.nodeName] = utilization
+		}
+		if percent, ok := utilization[string(uid)]; ok {
+			peak = math.Max(peak, percent/100)
+			found = true
+		}
+	}
+	return math.Min(peak, 1.0), found
+}
+
+// GPUPackable reports whether a workload may be packed onto a shared GPU: single-GPU
+// inference workloads that do not already request a GPU share and have not opted out
+func GPUPackable(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	return wo.Spec.WorkloadType == "inference" &&
+		wo.Spec.Resources.GPU == 1 &&
+		wo.Spec.Resources.GPUFraction == "" &&
+		wo.Spec.Resources.MIGProfile == "" &&
+		wo.Annotations[GPUPackingAnnotation] != "false"
+}
+
+// gpuUtilizationSample is a workload's measured GPU utilization at a point in time
+type gpuUtilizationSample struct {
+	timestamp   time.Time
+	utilization float64
+}
+
+// GPUPackingDecision is how a packable workload should hold its GPU
+type GPUPackingDecision struct {
+	// Peak is the highest utilization (0.0-1.0) in the window
+	Peak float64
+	// Fraction is the GPU share to request, "" to keep a whole GPU
+	Fraction string
+}
+
+// GPUPacker keeps a trailing window of GPU utilization per workload and decides which
+// low-utilization workloads can share a GPU. A workload is packed while its peak stays
+// below the threshold, with a share sized to the peak plus headroom.
+type GPUPacker struct {
+	// Threshold is the peak utilization (0.0-1.0) below which a workload is packed
+	Threshold float64
+	// Headroom is added to the peak when sizing the share
+	Headroom float64
+	// Window bounds the samples that make up the peak
+	Window time.Duration
+	// MinSamples is the number of samples required before deciding
+	MinSamples int
+
+	mu      sync.Mutex
+	clock   clock.PassiveClock
+	samples map[string][]gpuUtilizationSample
+	packed  map[string]float64
+}
+
+// NewGPUPacker creates a packer that packs workloads peaking below threshold
+func NewGPUPacker(threshold float64) *GPUPacker {
+	return &GPUPacker{
+		Threshold:  threshold,
+		Headroom:   DefaultGPUPackingHeadroom,
+		Window:     DefaultGPUPackingWindow,
+		MinSamples: DefaultGPUPackingMinSamples,
+		clock:      clock.RealClock{},
+		samples:    make(map[string][]gpuUtilizationSample),
+		packed:     make(map[string]float64),
+	}
+}
+
+// SetClock replaces the clock used to timestamp samples
+func (p *GPUPacker) SetClock(c clock.PassiveClock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = c
+}
+
+// Observe records a workload's measured GPU utilization
+func (p *GPUPacker) Observe(workloadID string, utilization float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	samples := p.trimLocked(workloadID)
+	samples = append(samples, gpuUtilizationSample{timestamp: p.clock.Now(), utilization: utilization})
+	if len(samples) > maxGPUUtilizationSamples {
+		samples = samples[len(samples)-maxGPUUtilizationSamples:]
+	}
+	p.samples[workloadID] = samples
+}
+
+// Decide returns how the workload should hold its GPU, or false while there are too few
+// samples in the window to decide
+func (p *GPUPacker) Decide(workloadID string) (GPUPackingDecision, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	samples := p.trimLocked(workloadID)
+	if len(samples) < p.MinSamples || len(samples) == 0 {
+		return GPUPackingDecision{}, false
+	}
+
+	decision := GPUPackingDecision{}
+	for _, sample := range samples {
+		decision.Peak = math.Max(decision.Peak, sample.utilization)
+	}
+	if decision.Peak < p.Threshold {
+		// Shares are sized in tenths of a GPU so small fluctuations do not resize them
+		if share := math.Ceil((decision.Peak+p.Headroom)*10) / 10; share < 1 {
+			decision.Fraction = strconv.FormatFloat(math.Max(share, 0.1), 'f', 1, 64)
+			p.packed[workloadID] = share
+			p.updateMetricsLocked()
+			return decision, true
+		}
+	}
+	delete(p.packed, workloadID)
+	p.updateMetricsLocked()
+	return decision, true
+}
+
+// Forget drops a workload's samples and packing
+func (p *GPUPacker) Forget(workloadID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.samples, workloadID)
+	delete(p.packed, workloadID)
+	p.updateMetricsLocked()
+}
+
+// trimLocked drops samples that fell out of the window
+func (p *GPUPacker) trimLocked(workloadID string) []gpuUtilizationSample {
+	samples := p.samples[workloadID]
+	cutoff := p.clock.Now().Add(-p.Window)
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].timestamp.After(cutoff) })
+	return samples[i:]
+}
+
+// updateMetricsLocked publishes the packed workload gauges
+func (p *GPUPacker) updateMetricsLocked() {
+	workloads := make([]string, 0, len(p.packed))
+	for workloadID := range p.packed {
+		workloads = append(workloads, workloadID)
+	}
+	sort.Strings(workloads)
+	reclaimed := 0.0
+	for _, workloadID := range workloads {
+		reclaimed += 1 - p.packed[workloadID]
+	}
+	gpuPackedWorkloads.Set(float64(len(workloads)))
+	gpuPackingReclaimed.Set(reclaimed)
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// GPUSharingLabel marks nodes whose GPUs may be split between fractional workloads
+	GPUSharingLabel = "kcloud.io/gpu-sharing"
+
+	// GPUSharingFractional is the GPUSharingLabel value that enables fractional placement
+	GPUSharingFractional = "fractional"
+
+	// GPUFractionAnnotation carries the share of a GPU a pod needs
+	GPUFractionAnnotation = "kcloud.io/gpu-fraction"
+
+	// GPUIndexAnnotation carries the physical GPU a fractional pod's share is accounted
+	// against, set at bind time
+	GPUIndexAnnotation = "kcloud.io/gpu-index"
+
+	// SharedGPUResource is the resource the NVIDIA device plugin advertises for the replicas
+	// of GPUs it time-slices or shares through MPS with renameByDefault set. Pods holding a
+	// GPU share request one replica, so the device plugin hands out the device.
+	SharedGPUResource corev1.ResourceName = "nvidia.com/gpu.shared"
+
+	// migResourcePrefix prefixes the resources the device plugin advertises per MIG profile
+	// under the mixed strategy
+	migResourcePrefix = "nvidia.com/mig-"
+
+	// gpuShareResourcePrefix prefixes the per-device resources fractional pods are accounted
+	// under, so node requests track how much of each physical GPU is taken
+	gpuShareResourcePrefix = "kcloud.io/gpu-share-"
+
+	// migComputeSlices is the number of compute slices of a MIG-capable GPU
+	migComputeSlices = 7
+)
+
+// MIGSlice is a node's inventory of one MIG profile
+type MIGSlice struct {
+	Profile     string `json:"profile"`
+	Allocatable int64  `json:"allocatable"`
+	Free        int64  `json:"free"`
+}
+
+// MIGResourceName returns the resource that advertises instances of a MIG profile
+func MIGResourceName(profile string) corev1.ResourceName {
+	return corev1.ResourceName(migResourcePrefix + profile)
+}
+
+// gpuShareResource names the resource fractional pods on a physical GPU are accounted under
+func gpuShareResource(index int) corev1.ResourceName {
+	return corev1.ResourceName(gpuShareResourcePrefix + strconv.Itoa(index))
+}
+
+// ParseGPUFraction returns a fractional GPU request in thousandths of a GPU
+func ParseGPUFraction(fraction string) (int64, error) {
+	q, err := resource.ParseQuantity(fraction)
+	if err != nil {
+		return 0, fmt.Errorf("invalid GPU fraction %q: %w", fraction, err)
+	}
+	milli := q.MilliValue()
+	if milli <= 0 || milli >= 1000 {
+		return 0, fmt.Errorf("GPU fraction %q must be between 0 and 1", fraction)
+	}
+	return milli, nil
+}
+
+// requestsGPU reports whether the workload needs whole GPUs, a share of one or a MIG instance
+func requestsGPU(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	r := wo.Spec.Resources
+	return r.GPU > 0 || r.GPUFraction != "" || r.MIGProfile != ""
+}
+
+// gpuEquivalent returns the workload's GPU request in physical GPUs, counting a MIG
+// instance by its share of the device's compute slices
+func gpuEquivalent(wo *kcloudv1alpha1.WorkloadOptimizer) float64 {
+	r := wo.Spec.Resources
+	gpus := float64(r.GPU)
+	if milli, err := ParseGPUFraction(r.GPUFraction); err == nil {
+		gpus += float64(milli) / 1000
+	}
+	if r.MIGProfile != "" {
+		if slices, err := strconv.Atoi(strings.SplitN(r.MIGProfile, "g.", 2)[0]); err == nil {
+			gpus += float64(slices) / migComputeSlices
+		}
+	}
+	return gpus
+}
+
+// NodeMIGInventory returns the node's MIG instances by profile, less those requested,
+// ordered by profile
+func NodeMIGInventory(node corev1.Node, requested corev1.ResourceList) []MIGSlice {
+	var inventory []MIGSlice
+	for name, allocatable := range node.Status.Allocatable {
+		profile, ok := strings.CutPrefix(string(name), migResourcePrefix)
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+		free := availableResource(node, requested, name)
+		inventory = append(inventory, MIGSlice{
+			Profile:     profile,
+			Allocatable: allocatable.Value(),
+			Free:        free.Value(),
+		})
+	}
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].Profile < inventory[j].Profile })
+	return inventory
+}
+
+// MIGInventory returns the MIG inventory of a node, or nil when the node is unknown
+func (s *ClusterSnapshot) MIGInventory(nodeName string) []MIGSlice {
+	s.mu.RLock()
+	node, ok := s.nodes[nodeName]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return NodeMIGInventory(*node, s.Requested(nodeName, ""))
+}
+
+// gpuShare is a claim on part of one physical GPU
+type gpuShare struct {
+	// milli is the compute share in thousandths of a GPU
+	milli int64
+	// memory is the claimed VRAM in bytes, zero when undeclared
+	memory int64
+}
+
+// workloadGPUShare returns the part of a GPU a fractional workload claims
+func workloadGPUShare(wo *kcloudv1alpha1.WorkloadOptimizer) (gpuShare, error) {
+	milli, err := ParseGPUFraction(wo.Spec.Resources.GPUFraction)
+	if err != nil {
+		return gpuShare{}, err
+	}
+	memory, err := workloadGPUMemory(wo)
+	if err != nil {
+		return gpuShare{}, err
+	}
+	return gpuShare{milli: milli, memory: memory}, nil
+}
+
+// podGPUShare returns the device a fractional pod is pinned to and its claim on it;
+// ok is false for pods that are not pinned
+func podGPUShare(pod *corev1.Pod) (int, gpuShare, bool) {
+	milli, err := ParseGPUFraction(pod.Annotations[GPUFractionAnnotation])
+	if err != nil {
+		return 0, gpuShare{}, false
+	}
+	index, err := strconv.Atoi(pod.Annotations[GPUIndexAnnotation])
+	if err != nil || index < 0 {
+		return 0, gpuShare{}, false
+	}
+	share := gpuShare{milli: milli}
+	if memory, err := resource.ParseQuantity(pod.Annotations[GPUMemoryAnnotation]); err == nil {
+		share.memory = memory.Value()
+	}
+	return index, share, true
+}
+
+// gpuShares returns the claims fractional pods hold on each physical GPU
+func gpuShares(node *corev1.Node, requested corev1.ResourceList) []gpuShare {
+	shares := make([]gpuShare, physicalGPUs(node))
+	for i := range shares {
+		if used, ok := requested[gpuShareResource(i)]; ok {
+			shares[i].milli = used.MilliValue()
+		}
+		if used, ok := requested[gpuMemoryResource(i)]; ok {
+			shares[i].memory = used.Value()
+		}
+	}
+	return shares
+}
+
+// fitGPUFraction picks the physical GPU a fractional workload's share is accounted against:
+// the fullest shared device with enough compute and VRAM left, otherwise an idle device.
+// Whole-GPU pods are assumed to hold idle devices. The node's device plugin must have a
+// shared replica free, since it is the device plugin that hands the pod a GPU. It returns
+// the device index, or -1 and the reason nothing fits.
+func fitGPUFraction(node *corev1.Node, requested corev1.ResourceList, share gpuShare) (int, string) {
+	if node.Labels[GPUSharingLabel] != GPUSharingFractional {
+		return -1, "node does not share GPUs between fractional workloads"
+	}
+	if replicas := availableResource(*node, requested, SharedGPUResource); replicas.Value() < 1 {
+		return -1, fmt.Sprintf("node has no %s replica free", SharedGPUResource)
+	}
+	if NodeGPUComputeMode(node) == GPUComputeModeExclusive {
+		return -1, "node GPUs run in exclusive-process mode"
+	}
+	cardMemory := gpuCardMemory(node)
+	if share.memory > 0 && share.memory > cardMemory {
+		return -1, gpuMemoryReason(node, share.memory)
+	}
+
+	shares := gpuShares(node, requested)
+	best := -1
+	idle := make([]int, 0, len(shares))
+	for i, used := range shares {
+		if used.milli == 0 && used.memory == 0 {
+			idle = append(idle, i)
+			continue
+		}
+		if used.milli+share.milli > 1000 {
+			continue
+		}
+		if share.memory > 0 && used.memory+share.memory > cardMemory {
+			continue
+		}
+		if best < 0 || used.milli > shares[best].milli {
+			best = i
+		}
+	}
+	if best >= 0 {
+		return best, ""
+	}
+
+	whole := requested["nvidia.com/gpu"]
+	if int64(len(idle)) > whole.Value() {
+		return idle[0], ""
+	}
+	if share.memory > 0 {
+		return -1, fmt.Sprintf("no GPU has %dm compute and %s memory free for a fractional workload",
+			share.milli, resource.NewQuantity(share.memory, resource.BinarySI))
+	}
+	return -1, fmt.Sprintf("no GPU has %dm free for a fractional workload", share.milli)
+}
+
+// gpuSharingScore prefers the devices fractional workloads already share, leaving idle
+// GPUs free for workloads that need whole devices
+func gpuSharingScore(node *corev1.Node, requested corev1.ResourceList, share gpuShare) float64 {
+	index, _ := fitGPUFraction(node, requested, share)
+	if index < 0 {
+		return 0
+	}
+	used := gpuShares(node, requested)[index]
+	return 0.5 + 0.5*float64(used.milli+share.milli)/1000
+}
+
+// podsOnNode returns the pods running on the node, other than the pod being scheduled
+func (p *NodePlugin) podsOnNode(ctx context.Context, pod *corev1.Pod, node *corev1.Node) ([]corev1.Pod, error) {
+	if p.Client == nil {
+		return nil, nil
+	}
+
+	var pods corev1.PodList
+	if err := p.Client.List(ctx, &pods, client.MatchingFields{PodNodeNameIndex: node.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", node.Name, err)
+	}
+
+	running := make([]corev1.Pod, 0, len(pods.Items))
+	for _, other := range pods.Items {
+		if other.UID == pod.UID || other.Status.Phase == corev1.PodSucceeded || other.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		running = append(running, other)
+	}
+	return running, nil
+}
+
+// requestedOnNode sums the requests of the pods running on the node, including the
+// device shares of fractional pods
+func (p *NodePlugin) requestedOnNode(ctx context.Context, pod *corev1.Pod, node *corev1.Node) (corev1.ResourceList, error) {
+	pods, err := p.podsOnNode(ctx, pod, node)
+	if err != nil {
+		return nil, err
+	}
+	requested := corev1.ResourceList{}
+	for i := range pods {
+		addResources(requested, podRequests(&pods[i]))
+	}
+	return requested, nil
+}
+
+// checkGPUFraction reports whether the pod's fractional workload fits a device on the node
+func (p *NodePlugin) checkGPUFraction(ctx context.Context, pod *corev1.Pod, node *corev1.Node,
+	wo *kcloudv1alpha1.WorkloadOptimizer) (int, string, error) {
+	share, err := workloadGPUShare(wo)
+	if err != nil {
+		return -1, err.Error(), nil
+	}
+	requested, err := p.requestedOnNode(ctx, pod, node)
+	if err != nil {
+		return -1, "", err
+	}
+	index, reason := fitGPUFraction(node, requested, share)
+	return index, reason, nil
+}
+
+// AssignGPU records the physical GPU a fractional pod's share is accounted against on the
+// node it is being bound to, so later pods see how full each device is. The device plugin
+// still allocates the replica the pod runs on. Pods without a GPU fraction are left alone.
+func (p *NodePlugin) AssignGPU(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, pod *corev1.Pod, nodeName string) error {
+	if pod.Annotations[GPUFractionAnnotation] == "" || p.Client == nil {
+		return nil
+	}
+
+	var node corev1.Node
+	if err := p.Client.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+
+	index, reason, err := p.checkGPUFraction(ctx, pod, &node, wo)
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		return fmt.Errorf("cannot pin pod %s/%s to a GPU on node %s: %s", pod.Namespace, pod.Name, nodeName, reason)
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	pinned := pod.DeepCopy()
+	pinned.Annotations[GPUIndexAnnotation] = strconv.Itoa(index)
+	if err := p.Client.Patch(ctx, pinned, patch); err != nil {
+		return fmt.Errorf("failed to pin pod %s/%s to GPU %d: %w", pod.Namespace, pod.Name, index, err)
+	}
+	return nil
+}
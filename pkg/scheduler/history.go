@@ -0,0 +1,266 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/retention"
+)
+
+// defaultHistoryLimit bounds the in-memory scheduling history
+const defaultHistoryLimit = 1000
+
+// HistoryStore keeps the scheduling events used for round-robin placement and reporting
+type HistoryStore interface {
+	// Record stores a scheduling event
+	Record(event SchedulingEvent)
+	// LastUsed returns the time a workload was last placed on the node
+	LastUsed(nodeName string) (time.Time, bool)
+	// Recent returns up to limit of the newest events, oldest first
+	Recent(limit int) []SchedulingEvent
+	// Query returns the events matching the query, oldest first
+	Query(query HistoryQuery) []SchedulingEvent
+}
+
+// HistoryQuery selects scheduling events; empty fields match everything
+type HistoryQuery struct {
+	WorkloadID string
+	NodeName   string
+	Algorithm  SchedulingAlgorithm
+	Since      time.Time
+	// Limit keeps only the newest matching events when positive
+	Limit int
+}
+
+// Matches reports whether the event satisfies the query filters
+func (q HistoryQuery) Matches(event SchedulingEvent) bool {
+	return (q.WorkloadID == "" || event.WorkloadID == q.WorkloadID) &&
+		(q.NodeName == "" || event.NodeName == q.NodeName) &&
+		(q.Algorithm == "" || event.Decision.Algorithm == q.Algorithm) &&
+		(q.Since.IsZero() || !event.Timestamp.Before(q.Since))
+}
+
+// HistorySchemaVersion is the layout version of persisted scheduling history; stores
+// refuse history written with a newer layout. Version 2 added the workload namespace and
+// version 3 the estimated cost and power.
+const HistorySchemaVersion = 3
+
+// HistoryRetention bounds how much scheduling history is kept
+type HistoryRetention struct {
+	// MaxEvents caps the number of events kept; zero means no count limit
+	MaxEvents int
+	// MaxAge drops events older than this; zero means no age limit
+	MaxAge time.Duration
+}
+
+// DefaultHistoryRetention returns the retention used when none is configured
+func DefaultHistoryRetention() HistoryRetention {
+	return HistoryRetention{MaxEvents: defaultHistoryLimit}
+}
+
+// MemoryHistoryStore is an in-memory HistoryStore indexed by workload, node and algorithm
+type MemoryHistoryStore struct {
+	mu        sync.RWMutex
+	events    []SchedulingEvent
+	base      uint64
+	retention HistoryRetention
+	now       func() time.Time
+
+	byWorkload  map[string][]uint64
+	byNode      map[string][]uint64
+	byAlgorithm map[SchedulingAlgorithm][]uint64
+}
+
+// NewMemoryHistoryStore creates a history store keeping at most limit events
+func NewMemoryHistoryStore(limit int) *MemoryHistoryStore {
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	return NewMemoryHistoryStoreWithRetention(HistoryRetention{MaxEvents: limit})
+}
+
+// NewMemoryHistoryStoreWithRetention creates a history store pruned by count and age
+func NewMemoryHistoryStoreWithRetention(retention HistoryRetention) *MemoryHistoryStore {
+	return &MemoryHistoryStore{
+		retention:   retention,
+		now:         time.Now,
+		byWorkload:  map[string][]uint64{},
+		byNode:      map[string][]uint64{},
+		byAlgorithm: map[SchedulingAlgorithm][]uint64{},
+	}
+}
+
+// Record stores a scheduling event and applies retention
+func (h *MemoryHistoryStore) Record(event SchedulingEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seq := h.base + uint64(len(h.events))
+	h.events = append(h.events, event)
+	h.byWorkload[event.WorkloadID] = append(h.byWorkload[event.WorkloadID], seq)
+	h.byNode[event.NodeName] = append(h.byNode[event.NodeName], seq)
+	h.byAlgorithm[event.Decision.Algorithm] = append(h.byAlgorithm[event.Decision.Algorithm], seq)
+	h.pruneLocked()
+}
+
+// Prune applies the age retention without recording a new event
+func (h *MemoryHistoryStore) Prune() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pruneLocked()
+}
+
+// pruneLocked drops the oldest events beyond the count limit or older than the age limit
+func (h *MemoryHistoryStore) pruneLocked() {
+	drop := 0
+	if h.retention.MaxEvents > 0 && len(h.events) > h.retention.MaxEvents {
+		drop = len(h.events) - h.retention.MaxEvents
+	}
+	if h.retention.MaxAge > 0 {
+		cutoff := h.now().Add(-h.retention.MaxAge)
+		for drop < len(h.events) && h.events[drop].Timestamp.Before(cutoff) {
+			drop++
+		}
+	}
+	if drop == 0 {
+		return
+	}
+
+	h.events = append([]SchedulingEvent(nil), h.events[drop:]...)
+	h.base += uint64(drop)
+	for key, seqs := range h.byWorkload {
+		if h.byWorkload[key] = trimSeqs(seqs, h.base); len(h.byWorkload[key]) == 0 {
+			delete(h.byWorkload, key)
+		}
+	}
+	for key, seqs := range h.byNode {
+		if h.byNode[key] = trimSeqs(seqs, h.base); len(h.byNode[key]) == 0 {
+			delete(h.byNode, key)
+		}
+	}
+	for key, seqs := range h.byAlgorithm {
+		if h.byAlgorithm[key] = trimSeqs(seqs, h.base); len(h.byAlgorithm[key]) == 0 {
+			delete(h.byAlgorithm, key)
+		}
+	}
+}
+
+// Purge removes the events selected by the filter and returns how many were removed
+func (h *MemoryHistoryStore) Purge(_ context.Context, filter retention.Filter) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	kept := make([]SchedulingEvent, 0, len(h.events))
+	for _, event := range h.events {
+		if !filter.Matches(event.Namespace, event.WorkloadID, event.Timestamp) {
+			kept = append(kept, event)
+		}
+	}
+	purged := len(h.events) - len(kept)
+	if purged == 0 {
+		return 0, nil
+	}
+
+	// Rebuild the indexes since sequence numbers of the kept events shift
+	h.events = kept
+	h.byWorkload = map[string][]uint64{}
+	h.byNode = map[string][]uint64{}
+	h.byAlgorithm = map[SchedulingAlgorithm][]uint64{}
+	for i, event := range kept {
+		seq := h.base + uint64(i)
+		h.byWorkload[event.WorkloadID] = append(h.byWorkload[event.WorkloadID], seq)
+		h.byNode[event.NodeName] = append(h.byNode[event.NodeName], seq)
+		h.byAlgorithm[event.Decision.Algorithm] = append(h.byAlgorithm[event.Decision.Algorithm], seq)
+	}
+	return purged, nil
+}
+
+// trimSeqs drops sequence numbers of pruned events
+func trimSeqs(seqs []uint64, base uint64) []uint64 {
+	i := 0
+	for i < len(seqs) && seqs[i] < base {
+		i++
+	}
+	return seqs[i:]
+}
+
+// LastUsed returns the time of the most recent event on the node
+func (h *MemoryHistoryStore) LastUsed(nodeName string) (time.Time, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seqs := h.byNode[nodeName]
+	if len(seqs) == 0 {
+		return time.Time{}, false
+	}
+	return h.events[seqs[len(seqs)-1]-h.base].Timestamp, true
+}
+
+// Recent returns the newest events; a non-positive limit returns all of them
+func (h *MemoryHistoryStore) Recent(limit int) []SchedulingEvent {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if limit <= 0 || limit > len(h.events) {
+		limit = len(h.events)
+	}
+	history := make([]SchedulingEvent, limit)
+	copy(history, h.events[len(h.events)-limit:])
+	return history
+}
+
+// Query returns matching events, using the narrowest index available
+func (h *MemoryHistoryStore) Query(query HistoryQuery) []SchedulingEvent {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var candidates []uint64
+	indexed := true
+	switch {
+	case query.WorkloadID != "":
+		candidates = h.byWorkload[query.WorkloadID]
+	case query.NodeName != "":
+		candidates = h.byNode[query.NodeName]
+	case query.Algorithm != "":
+		candidates = h.byAlgorithm[query.Algorithm]
+	default:
+		indexed = false
+	}
+
+	var matches []SchedulingEvent
+	if indexed {
+		for _, seq := range candidates {
+			if event := h.events[seq-h.base]; query.Matches(event) {
+				matches = append(matches, event)
+			}
+		}
+	} else {
+		for _, event := range h.events {
+			if query.Matches(event) {
+				matches = append(matches, event)
+			}
+		}
+	}
+
+	if query.Limit > 0 && len(matches) > query.Limit {
+		matches = matches[len(matches)-query.Limit:]
+	}
+	return matches
+}
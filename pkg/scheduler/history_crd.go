@@ -0,0 +1,305 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/retention"
+)
+
+const (
+	// Labels on SchedulingRecords that allow server-side selection
+	historyWorkloadLabel  = "kcloud.io/workload"
+	historyNamespaceLabel = "kcloud.io/workload-namespace"
+	historyNodeLabel      = "kcloud.io/node"
+	historyAlgorithmLabel = "kcloud.io/algorithm"
+
+	// HistorySchemaVersionAnnotation records the history layout a SchedulingRecord was written with
+	HistorySchemaVersionAnnotation = "kcloud.io/history-schema-version"
+
+	// crdHistoryQueueSize bounds events waiting to be persisted
+	crdHistoryQueueSize = 256
+)
+
+// +kubebuilder:rbac:groups=kcloud.io,resources=schedulingrecords,verbs=get;list;watch;create;delete;deletecollection
+
+// CRDHistoryStore persists scheduling events as SchedulingRecord objects so history
+// survives restarts. Queries are served from an indexed in-memory cache that is loaded
+// from the records on start; writes happen in the background so recording never blocks
+// scheduling on the API server.
+type CRDHistoryStore struct {
+	*MemoryHistoryStore
+	Client        client.Client
+	Namespace     string
+	Retention     HistoryRetention
+	PruneInterval time.Duration
+	queue         chan SchedulingEvent
+
+	// Purges applied so far, so events still queued when a purge ran are not persisted
+	purgeMu sync.Mutex
+	purges  []retention.Filter
+}
+
+// NewCRDHistoryStore creates a history store backed by SchedulingRecords in namespace
+func NewCRDHistoryStore(c client.Client, namespace string, retention HistoryRetention) *CRDHistoryStore {
+	return &CRDHistoryStore{
+		MemoryHistoryStore: NewMemoryHistoryStoreWithRetention(retention),
+		Client:             c,
+		Namespace:          namespace,
+		Retention:          retention,
+		PruneInterval:      10 * time.Minute,
+		queue:              make(chan SchedulingEvent, crdHistoryQueueSize),
+	}
+}
+
+// Record caches the event and queues it for persistence
+func (s *CRDHistoryStore) Record(event SchedulingEvent) {
+	s.MemoryHistoryStore.Record(event)
+	select {
+	case s.queue <- event:
+	default:
+		log.Log.WithName("scheduling-history").Info("History queue full, event not persisted",
+			"workload", event.WorkloadID)
+	}
+}
+
+// Start loads persisted history, then persists queued events and prunes expired records
+// until the context is cancelled
+func (s *CRDHistoryStore) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("scheduling-history")
+
+	if err := s.Load(ctx); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.PruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.queue:
+			if s.purged(event) {
+				continue
+			}
+			if err := s.Client.Create(ctx, s.toRecord(event)); err != nil {
+				log.Error(err, "Failed to persist scheduling event", "workload", event.WorkloadID)
+			}
+		case <-ticker.C:
+			if err := s.PruneRecords(ctx); err != nil {
+				log.Error(err, "Failed to prune scheduling records")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader write history
+func (s *CRDHistoryStore) NeedLeaderElection() bool {
+	return true
+}
+
+// Load fills the cache from persisted records, oldest first
+func (s *CRDHistoryStore) Load(ctx context.Context) error {
+	records, err := s.listRecords(ctx)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		version, err := RecordSchemaVersion(&record)
+		if err != nil {
+			return err
+		}
+		if version > HistorySchemaVersion {
+			return fmt.Errorf("scheduling record %s uses history schema %d, newer than supported %d",
+				record.Name, version, HistorySchemaVersion)
+		}
+		s.MemoryHistoryStore.Record(fromRecord(record))
+	}
+	return nil
+}
+
+// RecordSchemaVersion returns the history layout a record was written with; records
+// predating the annotation use version 1
+func RecordSchemaVersion(record *kcloudv1alpha1.SchedulingRecord) (int, error) {
+	value, ok := record.Annotations[HistorySchemaVersionAnnotation]
+	if !ok {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid history schema version on scheduling record %s: %w", record.Name, err)
+	}
+	return version, nil
+}
+
+// PruneRecords deletes records beyond the retention count or age
+func (s *CRDHistoryStore) PruneRecords(ctx context.Context) error {
+	s.MemoryHistoryStore.Prune()
+
+	records, err := s.listRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	drop := 0
+	if s.Retention.MaxEvents > 0 && len(records) > s.Retention.MaxEvents {
+		drop = len(records) - s.Retention.MaxEvents
+	}
+	if s.Retention.MaxAge > 0 {
+		cutoff := time.Now().Add(-s.Retention.MaxAge)
+		for drop < len(records) && records[drop].Spec.Timestamp.Time.Before(cutoff) {
+			drop++
+		}
+	}
+
+	for i := 0; i < drop; i++ {
+		if err := client.IgnoreNotFound(s.Client.Delete(ctx, &records[i])); err != nil {
+			return fmt.Errorf("failed to delete scheduling record %s: %w", records[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// Purge removes the events selected by the filter from the cache and deletes their records
+func (s *CRDHistoryStore) Purge(ctx context.Context, filter retention.Filter) (int, error) {
+	s.purgeMu.Lock()
+	s.purges = append(s.purges, filter)
+	s.purgeMu.Unlock()
+
+	if _, err := s.MemoryHistoryStore.Purge(ctx, filter); err != nil {
+		return 0, err
+	}
+
+	records, err := s.listRecords(ctx)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for i := range records {
+		spec := records[i].Spec
+		if !filter.Matches(spec.WorkloadNamespace, spec.WorkloadID, spec.Timestamp.Time) {
+			continue
+		}
+		if err := client.IgnoreNotFound(s.Client.Delete(ctx, &records[i])); err != nil {
+			return purged, fmt.Errorf("failed to delete scheduling record %s: %w", records[i].Name, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Name identifies the store in purge results
+func (s *CRDHistoryStore) Name() string {
+	return "scheduling-history"
+}
+
+// purged reports whether an earlier purge selected the event
+func (s *CRDHistoryStore) purged(event SchedulingEvent) bool {
+	s.purgeMu.Lock()
+	defer s.purgeMu.Unlock()
+	for _, filter := range s.purges {
+		if filter.Matches(event.Namespace, event.WorkloadID, event.Timestamp) {
+			return true
+		}
+	}
+	return false
+}
+
+// listRecords returns the persisted records sorted oldest first
+func (s *CRDHistoryStore) listRecords(ctx context.Context) ([]kcloudv1alpha1.SchedulingRecord, error) {
+	var list kcloudv1alpha1.SchedulingRecordList
+	if err := s.Client.List(ctx, &list, client.InNamespace(s.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list scheduling records: %w", err)
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		return list.Items[i].Spec.Timestamp.Before(&list.Items[j].Spec.Timestamp)
+	})
+	return list.Items, nil
+}
+
+// toRecord converts an event to a SchedulingRecord labeled for selection
+func (s *CRDHistoryStore) toRecord(event SchedulingEvent) *kcloudv1alpha1.SchedulingRecord {
+	labels := map[string]string{}
+	for key, value := range map[string]string{
+		historyWorkloadLabel:  event.WorkloadID,
+		historyNamespaceLabel: event.Namespace,
+		historyNodeLabel:      event.NodeName,
+		historyAlgorithmLabel: string(event.Decision.Algorithm),
+	} {
+		if value != "" && len(validation.IsValidLabelValue(value)) == 0 {
+			labels[key] = value
+		}
+	}
+
+	return &kcloudv1alpha1.SchedulingRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "sched-",
+			Namespace:    s.Namespace,
+			Labels:       labels,
+			Annotations: map[string]string{
+				HistorySchemaVersionAnnotation: strconv.Itoa(HistorySchemaVersion),
+			},
+		},
+		Spec: kcloudv1alpha1.SchedulingRecordSpec{
+			Timestamp:            metav1.NewTime(event.Timestamp),
+			WorkloadID:           event.WorkloadID,
+			WorkloadNamespace:    event.Namespace,
+			NodeName:             event.NodeName,
+			Algorithm:            string(event.Decision.Algorithm),
+			Stage:                int32(event.Decision.Stage),
+			Score:                event.Decision.Score,
+			EstimatedCost:        event.Decision.EstimatedCost,
+			EstimatedPower:       event.Decision.EstimatedPower,
+			DurationMilliseconds: event.Duration.Milliseconds(),
+			Success:              event.Success,
+			Reason:               event.Reason,
+		},
+	}
+}
+
+// fromRecord converts a SchedulingRecord back to an event
+func fromRecord(record kcloudv1alpha1.SchedulingRecord) SchedulingEvent {
+	return SchedulingEvent{
+		Timestamp:  record.Spec.Timestamp.Time,
+		WorkloadID: record.Spec.WorkloadID,
+		Namespace:  record.Spec.WorkloadNamespace,
+		NodeName:   record.Spec.NodeName,
+		Decision: SchedulingDecision{
+			SelectedNode:   record.Spec.NodeName,
+			Score:          record.Spec.Score,
+			EstimatedCost:  record.Spec.EstimatedCost,
+			EstimatedPower: record.Spec.EstimatedPower,
+			Algorithm:      SchedulingAlgorithm(record.Spec.Algorithm),
+			Stage:          int(record.Spec.Stage),
+		},
+		Duration: time.Duration(record.Spec.DurationMilliseconds) * time.Millisecond,
+		Success:  record.Spec.Success,
+		Reason:   record.Spec.Reason,
+	}
+}
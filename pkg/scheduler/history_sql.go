@@ -0,0 +1,364 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/retention"
+)
+
+// SQL dialects supported by SQLHistoryStore
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// sqlHistoryTimeout bounds each history statement
+const sqlHistoryTimeout = 5 * time.Second
+
+// SQLHistoryStore persists scheduling events in a SQLite or Postgres table indexed by
+// workload, node, algorithm and time. The database driver is not linked into the
+// operator; binaries that want this store import the driver themselves and pass an
+// opened *sql.DB.
+type SQLHistoryStore struct {
+	db        *sql.DB
+	dialect   string
+	retention HistoryRetention
+}
+
+// NewSQLHistoryStore creates the history table and indexes if needed
+func NewSQLHistoryStore(ctx context.Context, db *sql.DB, dialect string, retention HistoryRetention) (*SQLHistoryStore, error) {
+	if dialect != DialectSQLite && dialect != DialectPostgres {
+		return nil, fmt.Errorf("unsupported SQL dialect %s", dialect)
+	}
+
+	id := "INTEGER PRIMARY KEY AUTOINCREMENT"
+	if dialect == DialectPostgres {
+		id = "BIGSERIAL PRIMARY KEY"
+	}
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS scheduling_history_schema (version INTEGER NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS scheduling_history (
+			id ` + id + `,
+			ts BIGINT NOT NULL,
+			workload_id TEXT NOT NULL,
+			workload_namespace TEXT NOT NULL DEFAULT '',
+			node_name TEXT NOT NULL,
+			algorithm TEXT NOT NULL,
+			stage INTEGER NOT NULL,
+			score DOUBLE PRECISION NOT NULL,
+			estimated_cost DOUBLE PRECISION NOT NULL DEFAULT 0,
+			estimated_power DOUBLE PRECISION NOT NULL DEFAULT 0,
+			duration_ms BIGINT NOT NULL,
+			success BOOLEAN NOT NULL,
+			reason TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS scheduling_history_ts ON scheduling_history (ts)`,
+		`CREATE INDEX IF NOT EXISTS scheduling_history_workload ON scheduling_history (workload_id, ts)`,
+		`CREATE INDEX IF NOT EXISTS scheduling_history_node ON scheduling_history (node_name, ts)`,
+		`CREATE INDEX IF NOT EXISTS scheduling_history_algorithm ON scheduling_history (algorithm, ts)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return nil, fmt.Errorf("failed to create scheduling history schema: %w", err)
+		}
+	}
+
+	store := &SQLHistoryStore{db: db, dialect: dialect, retention: retention}
+	if err := store.checkSchemaVersion(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS scheduling_history_namespace
+		ON scheduling_history (workload_namespace, ts)`); err != nil {
+		return nil, fmt.Errorf("failed to create scheduling history schema: %w", err)
+	}
+	return store, nil
+}
+
+// SchemaVersion returns the history layout version recorded in the database
+func (s *SQLHistoryStore) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx, `SELECT MAX(version) FROM scheduling_history_schema`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read scheduling history schema version: %w", err)
+	}
+	return version, nil
+}
+
+// checkSchemaVersion stamps a new database with the current layout, migrates older ones
+// and rejects newer ones
+func (s *SQLHistoryStore) checkSchemaVersion(ctx context.Context) error {
+	var count int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM scheduling_history_schema`).Scan(&count); err != nil {
+		return fmt.Errorf("failed to read scheduling history schema version: %w", err)
+	}
+	if count == 0 {
+		if _, err := s.db.ExecContext(ctx, s.rebind(`INSERT INTO scheduling_history_schema (version) VALUES (?)`),
+			HistorySchemaVersion); err != nil {
+			return fmt.Errorf("failed to record scheduling history schema version: %w", err)
+		}
+		return nil
+	}
+
+	version, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version > HistorySchemaVersion {
+		return fmt.Errorf("scheduling history uses schema %d, newer than supported %d", version, HistorySchemaVersion)
+	}
+	if version < 2 {
+		if err := s.migrateToNamespaces(ctx); err != nil {
+			return err
+		}
+	}
+	if version < 3 {
+		if err := s.migrateToEstimates(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateToNamespaces adds the workload namespace column introduced by schema 2
+func (s *SQLHistoryStore) migrateToNamespaces(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to migrate scheduling history schema: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx,
+		`ALTER TABLE scheduling_history ADD COLUMN workload_namespace TEXT NOT NULL DEFAULT ''`); err != nil {
+		return fmt.Errorf("failed to migrate scheduling history schema: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO scheduling_history_schema (version) VALUES (?)`), 2); err != nil {
+		return fmt.Errorf("failed to record scheduling history schema version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to migrate scheduling history schema: %w", err)
+	}
+	return nil
+}
+
+// migrateToEstimates adds the estimated cost and power columns introduced by schema 3
+func (s *SQLHistoryStore) migrateToEstimates(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to migrate scheduling history schema: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, statement := range []string{
+		`ALTER TABLE scheduling_history ADD COLUMN estimated_cost DOUBLE PRECISION NOT NULL DEFAULT 0`,
+		`ALTER TABLE scheduling_history ADD COLUMN estimated_power DOUBLE PRECISION NOT NULL DEFAULT 0`,
+	} {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to migrate scheduling history schema: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO scheduling_history_schema (version) VALUES (?)`), 3); err != nil {
+		return fmt.Errorf("failed to record scheduling history schema version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to migrate scheduling history schema: %w", err)
+	}
+	return nil
+}
+
+// Record inserts the event and applies retention
+func (s *SQLHistoryStore) Record(event SchedulingEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqlHistoryTimeout)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, s.rebind(`INSERT INTO scheduling_history
+		(ts, workload_id, workload_namespace, node_name, algorithm, stage, score, estimated_cost, estimated_power,
+		duration_ms, success, reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		event.Timestamp.UnixNano(), event.WorkloadID, event.Namespace, event.NodeName, string(event.Decision.Algorithm),
+		event.Decision.Stage, event.Decision.Score, event.Decision.EstimatedCost, event.Decision.EstimatedPower,
+		event.Duration.Milliseconds(), event.Success, event.Reason)
+	if err != nil {
+		log.Log.WithName("scheduling-history").Error(err, "Failed to record scheduling event", "workload", event.WorkloadID)
+		return
+	}
+
+	if err := s.Prune(ctx); err != nil {
+		log.Log.WithName("scheduling-history").Error(err, "Failed to prune scheduling history")
+	}
+}
+
+// Prune deletes events beyond the retention count or age
+func (s *SQLHistoryStore) Prune(ctx context.Context) error {
+	if s.retention.MaxAge > 0 {
+		cutoff := time.Now().Add(-s.retention.MaxAge).UnixNano()
+		if _, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM scheduling_history WHERE ts < ?`), cutoff); err != nil {
+			return err
+		}
+	}
+	if s.retention.MaxEvents > 0 {
+		_, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM scheduling_history WHERE id <= (
+			SELECT id FROM scheduling_history ORDER BY id DESC LIMIT 1 OFFSET ?)`), s.retention.MaxEvents)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Purge deletes the events selected by the filter and returns how many were deleted.
+// Events without a namespace predate schema 2 and match a workload filter on the name alone.
+func (s *SQLHistoryStore) Purge(ctx context.Context, filter retention.Filter) (int, error) {
+	var conditions []string
+	var args []interface{}
+	switch {
+	case filter.Workload != "":
+		conditions = append(conditions, "workload_id = ?", "workload_namespace IN (?, '')")
+		args = append(args, filter.Workload, filter.Namespace)
+	case filter.Namespace != "":
+		conditions = append(conditions, "workload_namespace = ?")
+		args = append(args, filter.Namespace)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "ts >= ?")
+		args = append(args, filter.From.UnixNano())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "ts < ?")
+		args = append(args, filter.To.UnixNano())
+	}
+	if len(conditions) == 0 {
+		return 0, fmt.Errorf("refusing to purge all scheduling history")
+	}
+
+	result, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM scheduling_history WHERE `+
+		strings.Join(conditions, " AND ")), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge scheduling history: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count purged scheduling history: %w", err)
+	}
+	return int(purged), nil
+}
+
+// LastUsed returns the time of the most recent event on the node
+func (s *SQLHistoryStore) LastUsed(nodeName string) (time.Time, bool) {
+	events := s.Query(HistoryQuery{NodeName: nodeName, Limit: 1})
+	if len(events) == 0 {
+		return time.Time{}, false
+	}
+	return events[0].Timestamp, true
+}
+
+// Recent returns the newest events; a non-positive limit returns all of them
+func (s *SQLHistoryStore) Recent(limit int) []SchedulingEvent {
+	return s.Query(HistoryQuery{Limit: limit})
+}
+
+// Query returns matching events oldest first, using the table indexes
+func (s *SQLHistoryStore) Query(query HistoryQuery) []SchedulingEvent {
+	var conditions []string
+	var args []interface{}
+	if query.WorkloadID != "" {
+		conditions = append(conditions, "workload_id = ?")
+		args = append(args, query.WorkloadID)
+	}
+	if query.NodeName != "" {
+		conditions = append(conditions, "node_name = ?")
+		args = append(args, query.NodeName)
+	}
+	if query.Algorithm != "" {
+		conditions = append(conditions, "algorithm = ?")
+		args = append(args, string(query.Algorithm))
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "ts >= ?")
+		args = append(args, query.Since.UnixNano())
+	}
+
+	statement := `SELECT ts, workload_id, workload_namespace, node_name, algorithm, stage, score, estimated_cost,
+		estimated_power, duration_ms, success, reason
+		FROM scheduling_history`
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	statement += " ORDER BY id DESC"
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqlHistoryTimeout)
+	defer cancel()
+	rows, err := s.db.QueryContext(ctx, s.rebind(statement), args...)
+	if err != nil {
+		log.Log.WithName("scheduling-history").Error(err, "Failed to query scheduling history")
+		return nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []SchedulingEvent
+	for rows.Next() {
+		var event SchedulingEvent
+		var ts, durationMillis int64
+		var algorithm string
+		if err := rows.Scan(&ts, &event.WorkloadID, &event.Namespace, &event.NodeName, &algorithm, &event.Decision.Stage,
+			&event.Decision.Score, &event.Decision.EstimatedCost, &event.Decision.EstimatedPower, &durationMillis,
+			&event.Success, &event.Reason); err != nil {
+			log.Log.WithName("scheduling-history").Error(err, "Failed to read scheduling history")
+			return nil
+		}
+		event.Timestamp = time.Unix(0, ts)
+		event.Duration = time.Duration(durationMillis) * time.Millisecond
+		event.Decision.SelectedNode = event.NodeName
+		event.Decision.Algorithm = SchedulingAlgorithm(algorithm)
+		events = append(events, event)
+	}
+
+	// Rows come newest first so LIMIT keeps the newest; return them oldest first
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events
+}
+
+// rebind rewrites ? placeholders for dialects that number them
+func (s *SQLHistoryStore) rebind(statement string) string {
+	if s.dialect != DialectPostgres {
+		return statement
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range statement {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
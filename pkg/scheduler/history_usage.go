@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// UsageSampleAlgorithm marks history events that record a workload's usage rather than a
+// scheduling decision
+const UsageSampleAlgorithm SchedulingAlgorithm = "usage_sample"
+
+// HistoryUsageStore keeps workload usage baselines in a scheduling history store as usage
+// sample events, so they survive restarts when the store is persistent
+type HistoryUsageStore struct {
+	History HistoryStore
+}
+
+// RecordUsage records the sample as a usage sample event of the workload, identified by
+// namespace/name
+func (s HistoryUsageStore) RecordUsage(workloadID string, sample optimizer.UsageSample) {
+	namespace, name := splitWorkloadID(workloadID)
+	s.History.Record(SchedulingEvent{
+		Timestamp:  sample.Timestamp,
+		WorkloadID: name,
+		Namespace:  namespace,
+		Decision: SchedulingDecision{
+			EstimatedCost:  sample.Cost,
+			EstimatedPower: sample.Power,
+			Algorithm:      UsageSampleAlgorithm,
+		},
+		Success: true,
+	})
+}
+
+// UsageSamples returns the workload's usage sample events recorded at or after since
+func (s HistoryUsageStore) UsageSamples(workloadID string, since time.Time) []optimizer.UsageSample {
+	namespace, name := splitWorkloadID(workloadID)
+	var samples []optimizer.UsageSample
+	for _, event := range s.History.Query(HistoryQuery{
+		WorkloadID: name,
+		Algorithm:  UsageSampleAlgorithm,
+		Since:      since,
+	}) {
+		if event.Namespace != namespace {
+			continue
+		}
+		samples = append(samples, optimizer.UsageSample{
+			Timestamp: event.Timestamp,
+			Cost:      event.Decision.EstimatedCost,
+			Power:     event.Decision.EstimatedPower,
+		})
+	}
+	return samples
+}
+
+// splitWorkloadID splits a namespace/name workload ID
+func splitWorkloadID(workloadID string) (string, string) {
+	if namespace, name, ok := strings.Cut(workloadID, "/"); ok {
+		return namespace, name
+	}
+	return "", workloadID
+}
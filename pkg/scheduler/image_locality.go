@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// minImageLocalitySize is the cached image size below which locality earns nothing,
+	// since small images pull quickly anyway
+	minImageLocalitySize = 23 * 1024 * 1024
+
+	// maxImageLocalitySize is the cached size per image at which locality scores fully
+	maxImageLocalitySize = 1000 * 1024 * 1024
+)
+
+// DefaultImageLocalityWeights returns the default share of the node score given to image
+// locality per workload type; ML training and serving images are large, so cold starts cost most
+func DefaultImageLocalityWeights() map[string]float64 {
+	return map[string]float64{
+		"training":  0.1,
+		"serving":   0.15,
+		"inference": 0.15,
+		"batch":     0.05,
+	}
+}
+
+// SetImageLocalityWeights replaces the per workload type image locality weights; each is
+// the share of the node score, from 0 up to but excluding 1
+func (s *Scheduler) SetImageLocalityWeights(weights map[string]float64) error {
+	for workloadType, weight := range weights {
+		if weight < 0 || weight >= 1 {
+			return fmt.Errorf("image locality weight for %s must be in [0, 1)", workloadType)
+		}
+	}
+	s.imageLocalityWeights = weights
+	return nil
+}
+
+// ParseImageLocalityWeights parses weights written as "training=0.1,serving=0.2";
+// workload types that are omitted keep their default weight
+func ParseImageLocalityWeights(spec string) (map[string]float64, error) {
+	weights := DefaultImageLocalityWeights()
+	if strings.TrimSpace(spec) == "" {
+		return weights, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		workloadType, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return weights, fmt.Errorf("invalid image locality weight %q, expected type=value", pair)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return weights, fmt.Errorf("invalid image locality weight for %s: %w", workloadType, err)
+		}
+		weights[workloadType] = weight
+	}
+	return weights, nil
+}
+
+// imageLocalityWeight returns the image locality share for the workload, zero if it
+// declares no images
+func (s *Scheduler) imageLocalityWeight(wo *kcloudv1alpha1.WorkloadOptimizer) float64 {
+	if len(wo.Spec.Images) == 0 {
+		return 0
+	}
+	return s.imageLocalityWeights[wo.Spec.WorkloadType]
+}
+
+// calculateImageLocalityScore scores how much of the workload's image data the node already
+// holds in the range 0.0-1.0
+func (s *Scheduler) calculateImageLocalityScore(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) float64 {
+	if len(wo.Spec.Images) == 0 {
+		return 0
+	}
+
+	sizes := nodeImageSizes(node)
+	var cached int64
+	for _, image := range wo.Spec.Images {
+		cached += sizes[normalizeImageName(image)]
+	}
+
+	maxSize := int64(maxImageLocalitySize * len(wo.Spec.Images))
+	switch {
+	case cached <= minImageLocalitySize:
+		return 0
+	case cached >= maxSize:
+		return 1
+	default:
+		return float64(cached-minImageLocalitySize) / float64(maxSize-minImageLocalitySize)
+	}
+}
+
+// nodeImageSizes maps every name of each image cached on the node to its size
+func nodeImageSizes(node corev1.Node) map[string]int64 {
+	sizes := make(map[string]int64)
+	for _, image := range node.Status.Images {
+		for _, name := range image.Names {
+			sizes[normalizeImageName(name)] = image.SizeBytes
+		}
+	}
+	return sizes
+}
+
+// normalizeImageName expands an image reference to registry/repository:tag form so that
+// "pytorch/pytorch" and "docker.io/pytorch/pytorch:latest" compare equal; digests are kept
+func normalizeImageName(name string) string {
+	if !strings.Contains(name, "@") {
+		lastSlash := strings.LastIndex(name, "/")
+		if !strings.Contains(name[lastSlash+1:], ":") {
+			name += ":latest"
+		}
+	}
+
+	first, _, found := strings.Cut(name, "/")
+	if !found || !(strings.ContainsAny(first, ".:") || first == "localhost") {
+		if !found {
+			name = "library/" + name
+		}
+		name = "docker.io/" + name
+	}
+	return name
+}
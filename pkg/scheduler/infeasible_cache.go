@@ -0,0 +1,225 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// DefaultInfeasibleTTL bounds how long a pool is remembered as unable to fit a workload
+// shape when no invalidating event arrives
+const DefaultInfeasibleTTL = 10 * time.Minute
+
+// infeasibleKey identifies a workload shape on a node pool
+type infeasibleKey struct {
+	shape string
+	pool  string
+}
+
+// InfeasibleCache remembers which node pools cannot fit which workload shapes, so retries
+// of a queued workload skip pools that failed filtering until the pool changes
+type InfeasibleCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	clock   clock.PassiveClock
+	entries map[infeasibleKey]time.Time
+}
+
+// NewInfeasibleCache creates a cache whose entries expire after ttl
+func NewInfeasibleCache(ttl time.Duration) *InfeasibleCache {
+	return &InfeasibleCache{
+		ttl:     ttl,
+		clock:   clock.RealClock{},
+		entries: make(map[infeasibleKey]time.Time),
+	}
+}
+
+// SetClock replaces the clock used to expire entries
+func (c *InfeasibleCache) SetClock(clk clock.PassiveClock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clk
+}
+
+// Infeasible reports whether the pool is known not to fit the shape
+func (c *InfeasibleCache) Infeasible(shape, pool string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := infeasibleKey{shape: shape, pool: pool}
+	expires, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	if !c.clock.Now().Before(expires) {
+		delete(c.entries, key)
+		return false
+	}
+	return true
+}
+
+// MarkInfeasible records that no node in the pool fits the shape
+func (c *InfeasibleCache) MarkInfeasible(shape, pool string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[infeasibleKey{shape: shape, pool: pool}] = c.clock.Now().Add(c.ttl)
+}
+
+// InvalidatePool forgets every conclusion about the pool, e.g. after it was resized
+func (c *InfeasibleCache) InvalidatePool(pool string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.pool == pool {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateAll forgets every conclusion, e.g. after prices or pool definitions changed
+func (c *InfeasibleCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[infeasibleKey]time.Time)
+}
+
+// Len returns the number of cached conclusions
+func (c *InfeasibleCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// WorkloadShape summarizes the workload fields that decide whether a node can host it
+func WorkloadShape(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cpu=%s,memory=%s,gpu=%d,npu=%d",
+		wo.Spec.Resources.CPU, wo.Spec.Resources.Memory, wo.Spec.Resources.GPU, wo.Spec.Resources.NPU)
+	if wo.Spec.Resources.GPUFraction != "" {
+		fmt.Fprintf(&b, ",gpuFraction=%s", wo.Spec.Resources.GPUFraction)
+	}
+	if wo.Spec.Resources.MIGProfile != "" {
+		fmt.Fprintf(&b, ",mig=%s", wo.Spec.Resources.MIGProfile)
+	}
+	if wo.Spec.Resources.GPUMemory != "" {
+		fmt.Fprintf(&b, ",gpuMemory=%s", wo.Spec.Resources.GPUMemory)
+	}
+	if len(wo.Spec.Resources.Accelerators) > 0 {
+		accelerators := make([]string, 0, len(wo.Spec.Resources.Accelerators))
+		for _, accelerator := range wo.Spec.Resources.Accelerators {
+			accelerators = append(accelerators, fmt.Sprintf("%s=%d", accelerator.Type, accelerator.Count))
+		}
+		sort.Strings(accelerators)
+		fmt.Fprintf(&b, ",accelerators=%s", strings.Join(accelerators, "+"))
+	}
+
+	if wo.Spec.PowerConstraints != nil && wo.Spec.PowerConstraints.PreferGreen {
+		b.WriteString(",green")
+	}
+
+	if wo.Spec.PlacementPolicy != nil && len(wo.Spec.PlacementPolicy.NodeSelector) > 0 {
+		keys := make([]string, 0, len(wo.Spec.PlacementPolicy.NodeSelector))
+		for key := range wo.Spec.PlacementPolicy.NodeSelector {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, ",%s=%s", key, wo.Spec.PlacementPolicy.NodeSelector[key])
+		}
+	}
+
+	if tolerations := workloadTolerations(wo); len(tolerations) > 0 {
+		fmt.Fprintf(&b, ",tolerations=%s", tolerationsShape(tolerations))
+	}
+	return b.String()
+}
+
+// samePoolProfiles reports whether two profile lists describe the same pools
+func samePoolProfiles(a, b []kcloudv1alpha1.NodePowerProfile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !equality.Semantic.DeepEqual(a[i].Spec, b[i].Spec) {
+			return false
+		}
+	}
+	return true
+}
+
+// InvalidateOnNodeChanges drops cached conclusions for a node's pool when the node joins,
+// leaves, or changes readiness, labels (including price labels) or allocatable capacity
+func (s *Scheduler) InvalidateOnNodeChanges(ctx context.Context, informers cache.Informers) error {
+	informer, err := informers.GetInformer(ctx, &corev1.Node{})
+	if err != nil {
+		return fmt.Errorf("failed to get node informer: %w", err)
+	}
+
+	invalidate := func(obj interface{}) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		if node, ok := obj.(*corev1.Node); ok {
+			s.invalidateNodePool(*node)
+		}
+	}
+
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: invalidate,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, okOld := oldObj.(*corev1.Node)
+			newNode, okNew := newObj.(*corev1.Node)
+			if okOld && okNew && !s.nodeFeasibilityChanged(oldNode, newNode) {
+				return
+			}
+			// A relabeled node may have moved between pools
+			invalidate(oldObj)
+			invalidate(newObj)
+		},
+		DeleteFunc: invalidate,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch nodes: %w", err)
+	}
+	return nil
+}
+
+// nodeFeasibilityChanged reports whether an update can change which workloads fit the node
+func (s *Scheduler) nodeFeasibilityChanged(oldNode, newNode *corev1.Node) bool {
+	if !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+		!equality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable) ||
+		!equality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints) {
+		return true
+	}
+	if NodeUnschedulableReason(oldNode) != NodeUnschedulableReason(newNode) {
+		return true
+	}
+	return s.isNodeReady(*oldNode) != s.isNodeReady(*newNode)
+}
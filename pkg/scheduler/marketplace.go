@@ -0,0 +1,434 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/journal"
+)
+
+const (
+	// DefaultMarketplaceInterval is how often expired offers are settled
+	DefaultMarketplaceInterval = time.Minute
+	// DefaultTransferRetention is how long settled offers, and the transfers they carry, are kept
+	DefaultTransferRetention = 90 * 24 * time.Hour
+
+	// MarketplaceScoreWeight is the share of the node score given to marketplace claims on
+	// nodes that hold any
+	MarketplaceScoreWeight = 0.2
+)
+
+// CapacityTransfer records an internal charge from the claiming team to the offering team
+type CapacityTransfer struct {
+	Offer        string
+	FromTeam     string
+	ToTeam       string
+	NodeName     string
+	PricePerHour float64
+	Hours        float64
+	Amount       float64
+	SettledAt    time.Time
+}
+
+// +kubebuilder:rbac:groups=kcloud.io,resources=capacityoffers,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=kcloud.io,resources=capacityoffers/status,verbs=get;update
+
+// Marketplace lets teams trade unused reserved capacity at internal prices. Offers are
+// CapacityOffers in the offering team's namespace, and claims and the transfers they settle
+// into are recorded in their status, so the marketplace survives restarts and any replica
+// can serve it. The leader settles offers as they expire and prunes settled ones after the
+// retention.
+type Marketplace struct {
+	client    client.Client
+	journal   *journal.Journal
+	interval  time.Duration
+	retention time.Duration
+}
+
+// NewMarketplace creates a new capacity marketplace; transfers are also journaled when j is set
+func NewMarketplace(c client.Client, j *journal.Journal) *Marketplace {
+	return &Marketplace{
+		client:    c,
+		journal:   j,
+		interval:  DefaultMarketplaceInterval,
+		retention: DefaultTransferRetention,
+	}
+}
+
+// offerPhase returns the phase of an offer; offers are open until their status says otherwise
+func offerPhase(offer *kcloudv1alpha1.CapacityOffer) kcloudv1alpha1.CapacityOfferPhase {
+	if offer.Status.Phase == "" {
+		return kcloudv1alpha1.CapacityOfferOpen
+	}
+	return offer.Status.Phase
+}
+
+// offerExpired reports whether an offer's expiry has passed
+func offerExpired(offer *kcloudv1alpha1.CapacityOffer, now time.Time) bool {
+	return offer.Spec.ExpiresAt != nil && !now.Before(offer.Spec.ExpiresAt.Time)
+}
+
+// Offer lists capacity for other teams to claim. The offering team is the offer's
+// namespace, and a workload may only have one offer open or claimed at a time.
+func (m *Marketplace) Offer(ctx context.Context, offer *kcloudv1alpha1.CapacityOffer) error {
+	if offer.Namespace == "" {
+		return fmt.Errorf("offer must have a namespace")
+	}
+	if offer.Spec.Workload == "" {
+		return fmt.Errorf("offer must reference a workload")
+	}
+	if offer.Spec.NodeName == "" {
+		return fmt.Errorf("offer must reference a node")
+	}
+	if offer.Spec.PricePerHour < 0 {
+		return fmt.Errorf("offer price cannot be negative")
+	}
+
+	existing := &kcloudv1alpha1.CapacityOfferList{}
+	if err := m.client.List(ctx, existing, client.InNamespace(offer.Namespace)); err != nil {
+		return fmt.Errorf("failed to list capacity offers: %w", err)
+	}
+	for i := range existing.Items {
+		other := &existing.Items[i]
+		if other.Spec.Workload == offer.Spec.Workload && offerPhase(other) != kcloudv1alpha1.CapacityOfferSettled {
+			return fmt.Errorf("workload %s is already offered as %s", offer.Spec.Workload, other.Name)
+		}
+	}
+
+	if offer.Name == "" {
+		offer.GenerateName = offer.Spec.Workload + "-"
+	}
+	offer.Status = kcloudv1alpha1.CapacityOfferStatus{}
+	if err := m.client.Create(ctx, offer); err != nil {
+		return fmt.Errorf("failed to create capacity offer: %w", err)
+	}
+	return nil
+}
+
+// Claim assigns an open offer to a team. Claims are written with the offer's resource
+// version, so of two teams claiming the same offer only one succeeds.
+func (m *Marketplace) Claim(ctx context.Context, namespace, name, team string) (*kcloudv1alpha1.CapacityOffer, error) {
+	offer := &kcloudv1alpha1.CapacityOffer{}
+	if err := m.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, offer); err != nil {
+		return nil, fmt.Errorf("failed to get offer %s/%s: %w", namespace, name, err)
+	}
+	if offer.Namespace == team {
+		return nil, fmt.Errorf("team %s cannot claim its own offer", team)
+	}
+	if offerPhase(offer) != kcloudv1alpha1.CapacityOfferOpen {
+		return nil, fmt.Errorf("offer %s/%s is already claimed by %s", namespace, name, offer.Status.ClaimedBy)
+	}
+	now := time.Now()
+	if offerExpired(offer, now) {
+		return nil, fmt.Errorf("offer %s/%s has expired", namespace, name)
+	}
+
+	claimedAt := metav1.NewTime(now)
+	offer.Status = kcloudv1alpha1.CapacityOfferStatus{
+		Phase:     kcloudv1alpha1.CapacityOfferClaimed,
+		ClaimedBy: team,
+		ClaimedAt: &claimedAt,
+	}
+	if err := m.client.Status().Update(ctx, offer); err != nil {
+		return nil, fmt.Errorf("failed to claim offer %s/%s: %w", namespace, name, err)
+	}
+	return offer, nil
+}
+
+// Release ends an offer. An unclaimed offer is withdrawn; a claimed one settles its
+// internal transfer.
+func (m *Marketplace) Release(ctx context.Context, namespace, name string) (*CapacityTransfer, error) {
+	offer := &kcloudv1alpha1.CapacityOffer{}
+	if err := m.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, offer); err != nil {
+		return nil, fmt.Errorf("failed to get offer %s/%s: %w", namespace, name, err)
+	}
+
+	switch offerPhase(offer) {
+	case kcloudv1alpha1.CapacityOfferSettled:
+		return nil, fmt.Errorf("offer %s/%s is already settled", namespace, name)
+	case kcloudv1alpha1.CapacityOfferOpen:
+		if err := m.client.Delete(ctx, offer); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to withdraw offer %s/%s: %w", namespace, name, err)
+		}
+		return nil, nil
+	}
+
+	until := time.Now()
+	if offerExpired(offer, until) {
+		until = offer.Spec.ExpiresAt.Time
+	}
+	return m.settle(ctx, offer, until)
+}
+
+// ExpireOffers settles claimed offers whose expiry has passed, withdraws unclaimed ones and
+// deletes settled offers older than the retention
+func (m *Marketplace) ExpireOffers(ctx context.Context) ([]CapacityTransfer, error) {
+	offers := &kcloudv1alpha1.CapacityOfferList{}
+	if err := m.client.List(ctx, offers); err != nil {
+		return nil, fmt.Errorf("failed to list capacity offers: %w", err)
+	}
+
+	now := time.Now()
+	var settled []CapacityTransfer
+	for i := range offers.Items {
+		offer := &offers.Items[i]
+		switch offerPhase(offer) {
+		case kcloudv1alpha1.CapacityOfferSettled:
+			if offer.Status.SettledAt == nil || now.Sub(offer.Status.SettledAt.Time) < m.retention {
+				continue
+			}
+		case kcloudv1alpha1.CapacityOfferClaimed:
+			if !offerExpired(offer, now) {
+				continue
+			}
+			transfer, err := m.settle(ctx, offer, offer.Spec.ExpiresAt.Time)
+			if err != nil {
+				return settled, err
+			}
+			settled = append(settled, *transfer)
+			continue
+		default:
+			if !offerExpired(offer, now) {
+				continue
+			}
+		}
+
+		if err := m.client.Delete(ctx, offer); client.IgnoreNotFound(err) != nil {
+			return settled, fmt.Errorf("failed to delete offer %s/%s: %w", offer.Namespace, offer.Name, err)
+		}
+	}
+	return settled, nil
+}
+
+// OpenOffers returns unclaimed, unexpired offers ordered by price
+func (m *Marketplace) OpenOffers(ctx context.Context) ([]kcloudv1alpha1.CapacityOffer, error) {
+	offers := &kcloudv1alpha1.CapacityOfferList{}
+	if err := m.client.List(ctx, offers); err != nil {
+		return nil, fmt.Errorf("failed to list capacity offers: %w", err)
+	}
+
+	now := time.Now()
+	open := []kcloudv1alpha1.CapacityOffer{}
+	for _, offer := range offers.Items {
+		if offerPhase(&offer) != kcloudv1alpha1.CapacityOfferOpen || offerExpired(&offer, now) {
+			continue
+		}
+		open = append(open, offer)
+	}
+
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].Spec.PricePerHour < open[j].Spec.PricePerHour
+	})
+	return open, nil
+}
+
+// claimsOnNode reports whether team holds a claim on the node and whether other teams do
+func (m *Marketplace) claimsOnNode(ctx context.Context, team, nodeName string) (bool, bool, error) {
+	offers := &kcloudv1alpha1.CapacityOfferList{}
+	if err := m.client.List(ctx, offers); err != nil {
+		return false, false, fmt.Errorf("failed to list capacity offers: %w", err)
+	}
+
+	now := time.Now()
+	ownClaim, otherClaim := false, false
+	for i := range offers.Items {
+		offer := &offers.Items[i]
+		if offer.Spec.NodeName != nodeName || offerPhase(offer) != kcloudv1alpha1.CapacityOfferClaimed ||
+			offerExpired(offer, now) {
+			continue
+		}
+		if offer.Status.ClaimedBy == team {
+			ownClaim = true
+		} else {
+			otherClaim = true
+		}
+	}
+	return ownClaim, otherClaim, nil
+}
+
+// claimScore scores a node for a workload by the claims on it: 1 when the workload's team
+// holds one, 0 when only other teams do. Nodes without claims are not scored.
+func (m *Marketplace) claimScore(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodeName string) (float64, bool) {
+	// Teams map to namespaces
+	ownClaim, otherClaim, err := m.claimsOnNode(ctx, wo.Namespace, nodeName)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to read marketplace claims, ignoring them", "node", nodeName)
+		return 0, false
+	}
+	switch {
+	case ownClaim:
+		return 1.0, true
+	case otherClaim:
+		return 0.0, true
+	default:
+		return 0, false
+	}
+}
+
+// settle records the transfer for a claimed offer held until the given time
+func (m *Marketplace) settle(ctx context.Context, offer *kcloudv1alpha1.CapacityOffer, until time.Time) (*CapacityTransfer, error) {
+	hours := 0.0
+	if offer.Status.ClaimedAt != nil {
+		hours = max(until.Sub(offer.Status.ClaimedAt.Time).Hours(), 0)
+	}
+
+	settledAt := metav1.Now()
+	offer.Status.Phase = kcloudv1alpha1.CapacityOfferSettled
+	offer.Status.SettledAt = &settledAt
+	offer.Status.Hours = hours
+	offer.Status.Amount = hours * offer.Spec.PricePerHour
+	if err := m.client.Status().Update(ctx, offer); err != nil {
+		return nil, fmt.Errorf("failed to settle offer %s/%s: %w", offer.Namespace, offer.Name, err)
+	}
+
+	transfer := CapacityTransfer{
+		Offer:        offer.Namespace + "/" + offer.Name,
+		FromTeam:     offer.Status.ClaimedBy,
+		ToTeam:       offer.Namespace,
+		NodeName:     offer.Spec.NodeName,
+		PricePerHour: offer.Spec.PricePerHour,
+		Hours:        hours,
+		Amount:       offer.Status.Amount,
+		SettledAt:    settledAt.Time,
+	}
+	if m.journal != nil {
+		if _, err := m.journal.Append("capacity_transfer", transfer); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to journal capacity transfer", "offer", transfer.Offer)
+		}
+	}
+	return &transfer, nil
+}
+
+// Start settles expired offers every interval until the context is cancelled
+func (m *Marketplace) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("marketplace")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			settled, err := m.ExpireOffers(ctx)
+			if err != nil {
+				log.Error(err, "Failed to expire capacity offers")
+			}
+			for _, transfer := range settled {
+				log.Info("Settled expired capacity offer", "offer", transfer.Offer,
+					"from", transfer.FromTeam, "to", transfer.ToTeam, "amount", transfer.Amount)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection settles offers on the leader only, so each is settled once
+func (m *Marketplace) NeedLeaderElection() bool {
+	return true
+}
+
+// SetMarketplace enables claim-aware scheduling
+func (s *Scheduler) SetMarketplace(m *Marketplace) {
+	s.marketplace = m
+}
+
+// marketplaceFit scores a node by the marketplace claims on it, if it holds any
+func (s *Scheduler) marketplaceFit(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) (float64, bool) {
+	if s.marketplace == nil {
+		return 0, false
+	}
+	return s.marketplace.claimScore(ctx, wo, node.Name)
+}
+
+// SetMarketplace enables claim-aware scheduling
+func (as *AdvancedScheduler) SetMarketplace(m *Marketplace) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	as.marketplace = m
+}
+
+// OfferReservation lists a workload's reserved capacity on the marketplace on behalf of
+// team, which must be the namespace the reservation was made for
+func (as *AdvancedScheduler) OfferReservation(ctx context.Context, workloadID, team string, pricePerHour float64,
+	ttl time.Duration) (*kcloudv1alpha1.CapacityOffer, error) {
+	as.mutex.RLock()
+	reservation, exists := as.resourceReservations[workloadID]
+	market := as.marketplace
+	as.mutex.RUnlock()
+
+	if market == nil {
+		return nil, fmt.Errorf("capacity marketplace is not enabled")
+	}
+	if !exists {
+		return nil, fmt.Errorf("no reservation found for workload %s", workloadID)
+	}
+	if reservation.Namespace != team {
+		return nil, fmt.Errorf("workload %s is reserved by team %s, not %s", workloadID, reservation.Namespace, team)
+	}
+
+	expiresAt := reservation.ExpiresAt
+	if ttl > 0 && time.Now().Add(ttl).Before(expiresAt) {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	offer := &kcloudv1alpha1.CapacityOffer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: team},
+		Spec: kcloudv1alpha1.CapacityOfferSpec{
+			Workload:     workloadID,
+			NodeName:     reservation.NodeName,
+			CPU:          reservation.ReservedCPU.String(),
+			Memory:       reservation.ReservedMemory.String(),
+			GPU:          reservation.ReservedGPU,
+			NPU:          reservation.ReservedNPU,
+			PricePerHour: pricePerHour,
+			ExpiresAt:    &metav1.Time{Time: expiresAt},
+		},
+	}
+	if err := market.Offer(ctx, offer); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// marketplacePlugin steers workloads onto capacity their team has claimed and away from
+// capacity claimed by other teams; nodes without claims are left to the other plugins
+type marketplacePlugin struct {
+	as *AdvancedScheduler
+}
+
+func (p *marketplacePlugin) Name() string { return PluginMarketplace }
+
+func (p *marketplacePlugin) ScoreIfApplicable(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) (float64, bool) {
+	p.as.mutex.RLock()
+	market := p.as.marketplace
+	p.as.mutex.RUnlock()
+	if market == nil {
+		return 0, false
+	}
+	return market.claimScore(ctx, wo, node.Name)
+}
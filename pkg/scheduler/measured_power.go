@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+const (
+	// efficientWattsPerCore and inefficientWattsPerCore bound the measured draw per CPU core
+	// between which the power score falls from its full bonus to none
+	efficientWattsPerCore   = 3.0
+	inefficientWattsPerCore = 15.0
+
+	// efficientWattsPerGPU and inefficientWattsPerGPU bound the predicted draw per GPU the
+	// same way, for the power a placement is predicted to add
+	efficientWattsPerGPU   = 150.0
+	inefficientWattsPerGPU = 450.0
+
+	// maxMeasuredPowerBonus is the most the power score gains from a node's measured draw, as
+	// much as a high power-efficiency label
+	maxMeasuredPowerBonus = 0.2
+)
+
+// SetMeasuredPower sets the source of nodes' measured power, such as Kepler or BMCs; nodes it knows
+// are scored by their draw per core instead of their power-efficiency label. nil scores every
+// node from its labels.
+func (s *Scheduler) SetMeasuredPower(source optimizer.NodePowerSource) {
+	s.measuredPower = source
+}
+
+// measuredPowerBonus returns the power score bonus of the node's measured draw per allocatable
+// CPU core, and whether its power was measured
+func (s *Scheduler) measuredPowerBonus(node corev1.Node) (float64, bool) {
+	if s.measuredPower == nil {
+		return 0, false
+	}
+	watts, ok := s.measuredPower.NodePower(node.Name)
+	if !ok {
+		return 0, false
+	}
+	cores := node.Status.Allocatable.Cpu().AsApproximateFloat64()
+	if cores <= 0 {
+		return 0, false
+	}
+	share := (inefficientWattsPerCore - watts/cores) / (inefficientWattsPerCore - efficientWattsPerCore)
+	return maxMeasuredPowerBonus * math.Max(0, math.Min(1, share)), true
+}
+
+// SetPowerModels sets the power models fitted per node class; nodes whose class has one are
+// scored by the power the workload is predicted to add instead of their measured draw
+func (s *Scheduler) SetPowerModels(models *optimizer.NodePowerModels) {
+	s.powerModels = models
+}
+
+// modelPowerBonus returns the power score bonus of the power a replica of the workload is
+// predicted to add to the node, between the efficient and inefficient draw for its CPU
+// cores and GPUs, and whether the node's class has a model
+func (s *Scheduler) modelPowerBonus(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) (float64, bool) {
+	if s.powerModels == nil {
+		return 0, false
+	}
+	cpu := s.parseResourceQuantity(wo.Spec.Resources.CPU)
+	cores, gpus := cpu.AsApproximateFloat64(), float64(wo.Spec.Resources.GPU)
+	efficient := cores*efficientWattsPerCore + gpus*efficientWattsPerGPU
+	inefficient := cores*inefficientWattsPerCore + gpus*inefficientWattsPerGPU
+	if inefficient <= efficient {
+		return 0, false
+	}
+	watts, ok := s.powerModels.PlacementDelta(&node, cores, wo.Spec.Resources.GPU)
+	if !ok {
+		return 0, false
+	}
+	share := (inefficient - watts) / (inefficient - efficient)
+	return maxMeasuredPowerBonus * math.Max(0, math.Min(1, share)), true
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+const (
+	// spotScoreBonus is the most a spot node adds to the cost score of a workload preferring spot
+	spotScoreBonus = 0.3
+	// assumedSpotSaving is the discount off on-demand a spot node is assumed to have without a
+	// live quote
+	assumedSpotSaving = 0.3
+)
+
+// SetNodePricing sets the live node prices cost estimates are based on; nil estimates from
+// instance type alone
+func (s *Scheduler) SetNodePricing(pricing optimizer.NodePricing) {
+	s.nodePricing = pricing
+}
+
+// SetGPUModelPricing sets the per-model GPU rates that distinguish GPU nodes not priced
+// whole; nil treats every GPU model alike
+func (s *Scheduler) SetGPUModelPricing(pricing optimizer.GPUModelPricing) {
+	s.gpuModels = pricing
+}
+
+// SetCurrency sets the currency decisions report their estimated cost in, the one workloads'
+// cost constraints are written in
+func (s *Scheduler) SetCurrency(converter *currency.Converter) {
+	s.currency = converter
+}
+
+// SetSpotMarket sets the live spot prices and interruption risks workloads preferring spot
+// are weighed against. riskWeight is the share of a spot node's cost the cost-optimized
+// algorithm adds per unit of interruption risk.
+func (s *Scheduler) SetSpotMarket(market *optimizer.SpotMarket, riskWeight float64) error {
+	if riskWeight < 0 {
+		return fmt.Errorf("spot risk weight must not be negative, got %v", riskWeight)
+	}
+	s.spotMarket, s.spotRiskWeight = market, riskWeight
+	return nil
+}
+
+// pricedNodeCost returns the workload's share of the node's hourly price from the pricing,
+// false when the node is not priced
+func (s *Scheduler) pricedNodeCost(pricing optimizer.NodePricing, wo *kcloudv1alpha1.WorkloadOptimizer,
+	node *corev1.Node) (float64, bool) {
+	if pricing == nil {
+		return 0, false
+	}
+	cpu := s.parseResourceQuantity(wo.Spec.Resources.CPU)
+	memory := s.parseResourceQuantity(wo.Spec.Resources.Memory)
+	return optimizer.ReplicaNodeCost(pricing, node, cpu.AsApproximateFloat64(),
+		memory.AsApproximateFloat64()/(1<<30), wo.Spec.Resources.GPU)
+}
+
+// gpuModelAdjustment returns what the workload's GPUs, whole or shared, cost on the node over
+// the default GPU rate, negative for cheaper models
+func (s *Scheduler) gpuModelAdjustment(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
+	if s.gpuModels == nil {
+		return 0
+	}
+	return gpuEquivalent(wo) * s.gpuModels.GPUModelAdjustment(node, 1)
+}
+
+// spotQuote returns the live quote of a spot node for a workload preferring spot, false for
+// other workloads and nodes or without a quote
+func (s *Scheduler) spotQuote(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) (optimizer.SpotQuote, bool) {
+	if s.spotMarket == nil || wo.Spec.CostConstraints == nil || !wo.Spec.CostConstraints.PreferSpot ||
+		node.Labels["lifecycle"] != "spot" {
+		return optimizer.SpotQuote{}, false
+	}
+	return s.spotMarket.Quote(node)
+}
+
+// spotBonus returns the cost score bonus of a spot node quoted live for a workload preferring
+// spot, weighing its price and interruption risk. The full bonus goes to nodes whose
+// risk-adjusted cost saves at least as much over their on-demand price as the assumed spot
+// discount. Without an on-demand price, the full bonus shrinks with the risk adjustment.
+func (s *Scheduler) spotBonus(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) (float64, bool) {
+	if _, ok := s.spotQuote(wo, node); !ok {
+		return 0, false
+	}
+	spot, ok := s.pricedNodeCost(s.spotMarket, wo, node)
+	if !ok || spot <= 0 {
+		return 0, false
+	}
+	adjusted := s.spotRiskCost(wo, node, spot)
+	if onDemand, ok := s.pricedNodeCost(s.nodePricing, wo, node); ok && onDemand > 0 {
+		saving := 1 - adjusted/onDemand
+		return spotScoreBonus * math.Max(0, math.Min(1, saving/assumedSpotSaving)), true
+	}
+	return spotScoreBonus * spot / adjusted, true
+}
+
+// spotRiskCost returns the cost of running on a node raised by the risk of its spot capacity
+// being reclaimed, weighed by the spot risk weight
+func (s *Scheduler) spotRiskCost(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, cost float64) float64 {
+	if quote, ok := s.spotQuote(wo, node); ok {
+		return cost * (1 + s.spotRiskWeight*quote.InterruptionRisk)
+	}
+	return cost
+}
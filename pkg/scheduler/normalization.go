@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"math"
+)
+
+// ScoreNormalization selects how the balanced algorithm rescales each score factor over
+// the candidate nodes before weighting it. Factors are plugins and score expressions.
+// Normalizing keeps a factor with a wide raw range from outweighing the others: after
+// min-max or z-score normalization every factor spans the same range over the candidates,
+// so weights alone decide how much each factor counts. Normalized scores rank a node
+// against the other candidates rather than on an absolute scale. A factor on which all
+// candidates tie carries no ranking signal and keeps its clamped raw score, so a lone
+// candidate scores as it would without normalization.
+type ScoreNormalization string
+
+const (
+	// NormalizationNone uses factor scores as returned, clamped to 0.0-1.0
+	NormalizationNone ScoreNormalization = "none"
+
+	// NormalizationMinMax maps each factor linearly so the worst candidate scores 0.0 and
+	// the best 1.0
+	NormalizationMinMax ScoreNormalization = "min-max"
+
+	// NormalizationZScore standardizes each factor over the candidates and maps the
+	// standard score through a logistic curve, so the candidate mean scores 0.5. One
+	// outlier moves the others less than under min-max.
+	NormalizationZScore ScoreNormalization = "z-score"
+
+	// DefaultScoreNormalization is the strategy used when neither the scheduler nor the
+	// policy sets one
+	DefaultScoreNormalization = NormalizationMinMax
+)
+
+// ParseScoreNormalization validates a normalization strategy name; empty selects the default
+func ParseScoreNormalization(name string) (ScoreNormalization, error) {
+	switch strategy := ScoreNormalization(name); strategy {
+	case "":
+		return DefaultScoreNormalization, nil
+	case NormalizationNone, NormalizationMinMax, NormalizationZScore:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown score normalization %q, expected %s, %s or %s",
+			name, NormalizationNone, NormalizationMinMax, NormalizationZScore)
+	}
+}
+
+// NormalizeScores rescales one factor's scores over the candidate nodes to 0.0-1.0
+func NormalizeScores(strategy ScoreNormalization, values []float64) []float64 {
+	normalized := make([]float64, len(values))
+	if len(values) == 0 {
+		return normalized
+	}
+
+	lowest, highest, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, v := range values {
+		lowest = math.Min(lowest, v)
+		highest = math.Max(highest, v)
+		sum += v
+	}
+	if strategy == NormalizationNone || highest == lowest {
+		for i, v := range values {
+			normalized[i] = math.Max(0, math.Min(1, v))
+		}
+		return normalized
+	}
+
+	switch strategy {
+	case NormalizationZScore:
+		mean := sum / float64(len(values))
+		variance := 0.0
+		for _, v := range values {
+			variance += (v - mean) * (v - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(values)))
+		for i, v := range values {
+			normalized[i] = 1 / (1 + math.Exp(-(v-mean)/stddev))
+		}
+	default:
+		for i, v := range values {
+			normalized[i] = (v - lowest) / (highest - lowest)
+		}
+	}
+	return normalized
+}
+
+// normalizeFactors rescales every factor over the candidates in place; a factor missing
+// for a node, such as a plugin disabled by weight, stays missing
+func normalizeFactors(strategy ScoreNormalization, factors []map[string]float64) {
+	names := map[string]bool{}
+	for _, nodeFactors := range factors {
+		for name := range nodeFactors {
+			names[name] = true
+		}
+	}
+
+	for name := range names {
+		var nodes []int
+		var values []float64
+		for i, nodeFactors := range factors {
+			if v, ok := nodeFactors[name]; ok {
+				nodes = append(nodes, i)
+				values = append(values, v)
+			}
+		}
+		for j, v := range NormalizeScores(strategy, values) {
+			factors[nodes[j]][name] = v
+		}
+	}
+}
+
+// SetScoreNormalization sets the strategy the balanced algorithm uses for policies that
+// do not choose one
+func (as *AdvancedScheduler) SetScoreNormalization(strategy ScoreNormalization) error {
+	strategy, err := ParseScoreNormalization(string(strategy))
+	if err != nil {
+		return err
+	}
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	as.normalization = strategy
+	return nil
+}
+
+// scoreNormalization returns the strategy that applies under a policy
+func (as *AdvancedScheduler) scoreNormalization(policy *SchedulingPolicy) ScoreNormalization {
+	if policy.Normalization != "" {
+		return policy.Normalization
+	}
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+	return as.normalization
+}
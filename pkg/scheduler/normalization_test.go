@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/testutil"
+)
+
+// TestNormalizeScores checks each strategy's mapping of one factor over the candidates
+func TestNormalizeScores(t *testing.T) {
+	cases := []struct {
+		name     string
+		strategy scheduler.ScoreNormalization
+		values   []float64
+		want     []float64
+	}{
+		{"none clamps", scheduler.NormalizationNone, []float64{-1, 0.4, 7}, []float64{0, 0.4, 1}},
+		{"min-max spans the range", scheduler.NormalizationMinMax, []float64{10, 20, 60}, []float64{0, 0.2, 1}},
+		{"min-max ties keep raw score", scheduler.NormalizationMinMax, []float64{0.7, 0.7}, []float64{0.7, 0.7}},
+		{"min-max lone candidate keeps raw score", scheduler.NormalizationMinMax, []float64{3}, []float64{1}},
+		{"z-score centres the mean", scheduler.NormalizationZScore, []float64{1, 2, 3}, []float64{
+			1 / (1 + math.Exp(math.Sqrt(1.5))), 0.5, 1 / (1 + math.Exp(-math.Sqrt(1.5))),
+		}},
+		{"z-score ties keep raw score", scheduler.NormalizationZScore, []float64{0.2, 0.2, 0.2}, []float64{0.2, 0.2, 0.2}},
+		{"empty", scheduler.NormalizationMinMax, nil, []float64{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := scheduler.NormalizeScores(tc.strategy, tc.values)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tc.want[i]) > 1e-9 {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseScoreNormalization checks strategy names and the default
+func TestParseScoreNormalization(t *testing.T) {
+	if got, err := scheduler.ParseScoreNormalization(""); err != nil || got != scheduler.DefaultScoreNormalization {
+		t.Fatalf("empty name: got %q, %v", got, err)
+	}
+	for _, name := range []string{"none", "min-max", "z-score"} {
+		if got, err := scheduler.ParseScoreNormalization(name); err != nil || string(got) != name {
+			t.Fatalf("%s: got %q, %v", name, got, err)
+		}
+	}
+	if _, err := scheduler.ParseScoreNormalization("rank"); err == nil {
+		t.Fatal("unknown strategy was accepted")
+	}
+}
+
+// TestBalancedNormalization checks that a factor with a wide raw spread outweighs a heavier
+// weighted factor without normalization, and that normalization lets the weights decide
+func TestBalancedNormalization(t *testing.T) {
+	nodes := []corev1.Node{
+		testutil.NewNode("narrow-winner").WithAllocatable("8", "32Gi").
+			WithLabel("example.com/latency", "1.0").WithLabel("example.com/discount", "0.0").Build(),
+		testutil.NewNode("wide-winner").WithAllocatable("8", "32Gi").
+			WithLabel("example.com/latency", "0.9").WithLabel("example.com/discount", "0.5").Build(),
+	}
+	workload := testutil.NewWorkloadOptimizer("web", "default").WithResources("1", "2Gi").Build()
+
+	// Silence the built-in plugins so only the two expressions score
+	weights := map[string]float64{}
+	for _, name := range scheduler.RegisteredPlugins() {
+		weights[name] = 0
+	}
+
+	cases := []struct {
+		strategy scheduler.ScoreNormalization
+		want     string
+	}{
+		{scheduler.NormalizationNone, "wide-winner"},
+		{scheduler.NormalizationMinMax, "narrow-winner"},
+		{scheduler.NormalizationZScore, "narrow-winner"},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.strategy), func(t *testing.T) {
+			as := scheduler.NewAdvancedScheduler()
+			as.SetClock(testutil.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)))
+			as.SetHistoryStore(testutil.NewFakeHistoryStore())
+
+			policy := &scheduler.SchedulingPolicy{
+				Name:          "normalization",
+				Algorithm:     scheduler.AlgorithmBalanced,
+				PluginWeights: weights,
+				ScoreExpressions: []scheduler.ScoreExpression{
+					{Name: "latency", Expression: `double(node.labels["example.com/latency"])`, Weight: 2},
+					{Name: "discount", Expression: `double(node.labels["example.com/discount"])`, Weight: 1},
+				},
+				Normalization: tc.strategy,
+				Enabled:       true,
+			}
+
+			decision, err := as.ScheduleWithPolicy(context.Background(), workload.DeepCopy(), nodes, policy)
+			if err != nil {
+				t.Fatalf("scheduling failed: %v", err)
+			}
+			if decision.SelectedNode != tc.want {
+				t.Fatalf("selected %s (score %.3f, contributions %v), want %s",
+					decision.SelectedNode, decision.Score, decision.Contributions, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// OvercommitRatios maps a resource to the factor applied to its allocatable capacity
+type OvercommitRatios map[corev1.ResourceName]float64
+
+// OvercommitPolicy holds the overcommit ratios used when checking reservations against
+// allocatable capacity. Resources without a ratio are accounted strictly (1.0).
+type OvercommitPolicy struct {
+	// Default applies to workload types without their own ratios
+	Default OvercommitRatios
+	// ByWorkloadType overrides Default per workload type
+	ByWorkloadType map[string]OvercommitRatios
+}
+
+// Ratio returns the overcommit factor for a workload type and resource
+func (p OvercommitPolicy) Ratio(workloadType string, name corev1.ResourceName) float64 {
+	if ratios, ok := p.ByWorkloadType[workloadType]; ok {
+		if ratio, ok := ratios[name]; ok {
+			return ratio
+		}
+	}
+	if ratio, ok := p.Default[name]; ok {
+		return ratio
+	}
+	return 1.0
+}
+
+// Validate checks that every ratio is positive
+func (p OvercommitPolicy) Validate() error {
+	check := func(scope string, ratios OvercommitRatios) error {
+		for name, ratio := range ratios {
+			if ratio <= 0 {
+				return fmt.Errorf("overcommit ratio for %s %s must be positive", scope, name)
+			}
+		}
+		return nil
+	}
+	if err := check("default", p.Default); err != nil {
+		return err
+	}
+	for workloadType, ratios := range p.ByWorkloadType {
+		if err := check(workloadType, ratios); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseOvercommitPolicy parses ratios written as "batch:cpu=1.5,memory=1.2;inference:cpu=1.0",
+// where the workload type "*" sets the default ratios
+func ParseOvercommitPolicy(spec string) (OvercommitPolicy, error) {
+	policy := OvercommitPolicy{ByWorkloadType: map[string]OvercommitRatios{}}
+	if strings.TrimSpace(spec) == "" {
+		return policy, nil
+	}
+
+	for _, group := range strings.Split(spec, ";") {
+		workloadType, pairs, found := strings.Cut(strings.TrimSpace(group), ":")
+		if !found || workloadType == "" {
+			return policy, fmt.Errorf("invalid overcommit group %q, expected type:resource=ratio", group)
+		}
+
+		ratios := OvercommitRatios{}
+		for _, pair := range strings.Split(pairs, ",") {
+			name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if !found {
+				return policy, fmt.Errorf("invalid overcommit ratio %q, expected resource=ratio", pair)
+			}
+			ratio, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return policy, fmt.Errorf("invalid overcommit ratio for %s: %w", name, err)
+			}
+			ratios[resourceNameFor(name)] = ratio
+		}
+
+		if workloadType == "*" {
+			policy.Default = ratios
+		} else {
+			policy.ByWorkloadType[workloadType] = ratios
+		}
+	}
+	return policy, policy.Validate()
+}
+
+// resourceNameFor maps the short resource names used in configuration to resource names
+func resourceNameFor(name string) corev1.ResourceName {
+	switch name {
+	case "gpu":
+		return "nvidia.com/gpu"
+	case "npu":
+		return "npu.com/npu"
+	default:
+		return corev1.ResourceName(name)
+	}
+}
+
+// SetOvercommitPolicy replaces the overcommit ratios used by the reservation filter
+func (as *AdvancedScheduler) SetOvercommitPolicy(p OvercommitPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	as.overcommit = p
+	return nil
+}
+
+// reservedOnNode sums the resources reserved on the node by workloads other than exclude
+func (as *AdvancedScheduler) reservedOnNode(nodeName, exclude string) corev1.ResourceList {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	reserved := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.Quantity{},
+		corev1.ResourceMemory: resource.Quantity{},
+	}
+	var gpu, npu int64
+	for workloadID, reservation := range as.resourceReservations {
+		if workloadID == exclude || reservation.NodeName != nodeName {
+			continue
+		}
+		cpu := reserved[corev1.ResourceCPU]
+		cpu.Add(reservation.ReservedCPU)
+		reserved[corev1.ResourceCPU] = cpu
+		memory := reserved[corev1.ResourceMemory]
+		memory.Add(reservation.ReservedMemory)
+		reserved[corev1.ResourceMemory] = memory
+		gpu += int64(reservation.ReservedGPU)
+		npu += int64(reservation.ReservedNPU)
+		addResources(reserved, reservation.ReservedAccelerators)
+	}
+	reserved["nvidia.com/gpu"] = *resource.NewQuantity(gpu, resource.DecimalSI)
+	reserved["npu.com/npu"] = *resource.NewQuantity(npu, resource.DecimalSI)
+	return reserved
+}
+
+// reservationPlugin rejects nodes whose reservations plus the workload's request exceed
+// allocatable capacity scaled by the workload type's overcommit ratio. Standby headroom held
+// for other policies counts as reserved.
+type reservationPlugin struct {
+	as *AdvancedScheduler
+}
+
+func (p *reservationPlugin) Name() string { return PluginReservation }
+
+func (p *reservationPlugin) Filter(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, policy *SchedulingPolicy) (bool, string) {
+	requested, err := workloadRequests(wo)
+	if err != nil {
+		return false, err.Error()
+	}
+	accelerators, err := p.as.accelerators.Resources(wo.Spec.Resources.Accelerators)
+	if err != nil {
+		return false, err.Error()
+	}
+	addResources(requested, accelerators)
+
+	p.as.mutex.RLock()
+	overcommit := p.as.overcommit
+	p.as.mutex.RUnlock()
+
+	reserved := p.as.reservedOnNode(node.Name, wo.Name)
+	standby := p.as.standbyHeadroom(node, policy)
+	for name, request := range requested {
+		if request.IsZero() {
+			continue
+		}
+		allocatable := node.Status.Allocatable[name]
+		capacity := float64(allocatable.MilliValue()) * overcommit.Ratio(wo.Spec.WorkloadType, name)
+		used := reserved[name]
+		held := standby[name]
+		if float64(used.MilliValue()+request.MilliValue()+held.MilliValue()) > capacity {
+			if !held.IsZero() {
+				return false, fmt.Sprintf("reserved %s %s plus request %s plus standby headroom %s exceeds overcommitted capacity",
+					name, used.String(), request.String(), held.String())
+			}
+			return false, fmt.Sprintf("reserved %s %s plus request %s exceeds overcommitted capacity",
+				name, used.String(), request.String())
+		}
+	}
+	return true, ""
+}
+
+// SetOvercommitPolicy sets the overcommit ratios applied to allocatable capacity when
+// checking a workload against the requests and reservations already on a node
+func (s *Scheduler) SetOvercommitPolicy(p OvercommitPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	s.overcommit = p
+	return nil
+}
+
+// overcommittedAvailable returns the node's allocatable capacity scaled by the workload
+// type's overcommit ratio, less requested
+func (s *Scheduler) overcommittedAvailable(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node,
+	requested corev1.ResourceList, name corev1.ResourceName) resource.Quantity {
+	ratio := s.overcommit.Ratio(wo.Spec.WorkloadType, name)
+	if ratio == 1.0 {
+		return availableResource(node, requested, name)
+	}
+	allocatable := node.Status.Allocatable[name]
+	quantity := *resource.NewMilliQuantity(int64(float64(allocatable.MilliValue())*ratio), allocatable.Format)
+	if used, ok := requested[name]; ok {
+		quantity.Sub(used)
+	}
+	return quantity
+}
+
+// workloadRequests returns the workload's resource request as a resource list; an empty
+// CPU or memory value requests none
+func workloadRequests(wo *kcloudv1alpha1.WorkloadOptimizer) (corev1.ResourceList, error) {
+	cpu, err := parseRequest(wo.Spec.Resources.CPU)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CPU request: %w", err)
+	}
+	memory, err := parseRequest(wo.Spec.Resources.Memory)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory request: %w", err)
+	}
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU:    cpu,
+		corev1.ResourceMemory: memory,
+		"nvidia.com/gpu":      *resource.NewQuantity(int64(wo.Spec.Resources.GPU), resource.DecimalSI),
+		"npu.com/npu":         *resource.NewQuantity(int64(wo.Spec.Resources.NPU), resource.DecimalSI),
+	}
+	if wo.Spec.Resources.MIGProfile != "" {
+		requests[MIGResourceName(wo.Spec.Resources.MIGProfile)] = *resource.NewQuantity(1, resource.DecimalSI)
+	}
+	return requests, nil
+}
+
+// parseRequest parses a resource request, treating an empty value as zero
+func parseRequest(value string) (resource.Quantity, error) {
+	if value == "" {
+		return resource.Quantity{}, nil
+	}
+	return resource.ParseQuantity(value)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// DefaultScoringParallelism is the number of nodes scored concurrently
+const DefaultScoringParallelism = 16
+
+// SetParallelism sets how many nodes are scored concurrently; values below one score sequentially
+func (s *Scheduler) SetParallelism(workers int) {
+	s.parallelism = max(workers, 1)
+}
+
+// forEachNode runs score for every node index on a bounded worker pool and stops handing
+// out nodes once the context is cancelled. Callers write results into per-index slots and
+// reduce them afterwards, so the chosen node does not depend on goroutine scheduling.
+func (s *Scheduler) forEachNode(ctx context.Context, count int, score func(i int)) error {
+	workers := s.parallelism
+	if workers <= 0 {
+		workers = DefaultScoringParallelism
+	}
+	workqueue.ParallelizeUntil(ctx, workers, count, score)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("node scoring interrupted: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// ErrNoSuitableNode is returned when no node can host the workload
+var ErrNoSuitableNode = errors.New("no suitable node found for scheduling")
+
+// InsufficientCapacityError reports a failed placement together with its analysis
+type InsufficientCapacityError struct {
+	Analysis *kcloudv1alpha1.PlacementAnalysis
+}
+
+// Error implements the error interface
+func (e *InsufficientCapacityError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrNoSuitableNode, e.Analysis.Message)
+}
+
+// Unwrap lets callers match the error with errors.Is(err, ErrNoSuitableNode)
+func (e *InsufficientCapacityError) Unwrap() error {
+	return ErrNoSuitableNode
+}
+
+// analysisResources are the resources considered by placement analysis, in reporting order
+var analysisResources = []corev1.ResourceName{
+	corev1.ResourceCPU,
+	corev1.ResourceMemory,
+	"nvidia.com/gpu",
+	"npu.com/npu",
+}
+
+// relaxationStep is the granularity of suggested requests, so memory suggestions stay in whole MiB
+var relaxationStep = map[corev1.ResourceName]int64{
+	corev1.ResourceMemory: 1 << 20,
+}
+
+// SetPlacementAnalysis enables attaching a placement analysis to scheduling failures
+func (s *Scheduler) SetPlacementAnalysis(enabled bool) {
+	s.placementAnalysis = enabled
+}
+
+// memberRequests returns the per-member request of each analysis resource in base units,
+// millicores for CPU and bytes for memory
+func (s *Scheduler) memberRequests(wo *kcloudv1alpha1.WorkloadOptimizer) map[corev1.ResourceName]int64 {
+	cpu := s.parseResourceQuantity(wo.Spec.Resources.CPU)
+	memory := s.parseResourceQuantity(wo.Spec.Resources.Memory)
+	return map[corev1.ResourceName]int64{
+		corev1.ResourceCPU:    cpu.MilliValue(),
+		corev1.ResourceMemory: memory.Value(),
+		"nvidia.com/gpu":      int64(wo.Spec.Resources.GPU),
+		"npu.com/npu":         int64(wo.Spec.Resources.NPU),
+	}
+}
+
+// quantityValue converts a quantity to the base units used by memberRequests
+func quantityValue(name corev1.ResourceName, q resource.Quantity) int64 {
+	if name == corev1.ResourceCPU {
+		return q.MilliValue()
+	}
+	return q.Value()
+}
+
+// formatQuantity renders a base-unit amount of a resource for users
+func formatQuantity(name corev1.ResourceName, value int64) string {
+	switch name {
+	case corev1.ResourceCPU:
+		return resource.NewMilliQuantity(value, resource.DecimalSI).String()
+	case corev1.ResourceMemory:
+		return resource.NewQuantity(value, resource.BinarySI).String()
+	default:
+		return resource.NewQuantity(value, resource.DecimalSI).String()
+	}
+}
+
+// membersFitting counts how many members with the given requests fit across the free capacity
+func membersFitting(free []map[corev1.ResourceName]int64, requests map[corev1.ResourceName]int64, limit int64) int64 {
+	var total int64
+	for _, node := range free {
+		copies := limit
+		for name, request := range requests {
+			if request <= 0 {
+				continue
+			}
+			copies = min(copies, max(node[name], 0)/request)
+		}
+		total += copies
+		if total >= limit {
+			return limit
+		}
+	}
+	return total
+}
+
+// AnalyzePlacement explains how far a group of identical members of the workload is from
+// fitting the nodes: how many fit today, which resource runs out first, and the smallest
+// single-resource reduction of the per-member request that would let all of them fit
+func (s *Scheduler) AnalyzePlacement(wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node, members int32) *kcloudv1alpha1.PlacementAnalysis {
+	members = max(members, 1)
+	analysis := &kcloudv1alpha1.PlacementAnalysis{
+		AnalyzedAt: metav1.NewTime(time.Now()),
+		Members:    members,
+	}
+
+	// Free capacity of every node that passes the non-resource constraints
+	var free []map[corev1.ResourceName]int64
+	for _, node := range nodes {
+		if !s.nodeMeetsConstraints(wo, node) {
+			continue
+		}
+		if s.snapshot != nil {
+			if fits, _ := s.podAffinityFits(wo, node); !fits {
+				continue
+			}
+		}
+		requested := s.requestedOn(wo, node)
+		available := make(map[corev1.ResourceName]int64, len(analysisResources))
+		for _, name := range analysisResources {
+			available[name] = quantityValue(name, availableResource(node, requested, name))
+		}
+		free = append(free, available)
+	}
+	analysis.EligibleNodes = int32(len(free))
+
+	requests := s.memberRequests(wo)
+	limit := int64(members)
+	analysis.FittingMembers = int32(membersFitting(free, requests, limit))
+	if len(free) == 0 {
+		analysis.Message = "no node passes the readiness, taint, node selector, energy and pod affinity constraints"
+		return analysis
+	}
+	if analysis.FittingMembers >= members {
+		analysis.Message = fmt.Sprintf("all %d members fit the free capacity", members)
+		return analysis
+	}
+
+	// Prefer the relaxation that gives up the smallest share of the original request
+	bestShare := 1.0
+	for _, name := range analysisResources {
+		suggested, ok := largestFittingRequest(free, requests, name, limit)
+		if !ok {
+			continue
+		}
+		if share := 1 - float64(suggested)/float64(requests[name]); share < bestShare {
+			bestShare = share
+			analysis.Relaxation = &kcloudv1alpha1.ResourceRelaxation{
+				Resource:  string(name),
+				Requested: formatQuantity(name, requests[name]),
+				Suggested: formatQuantity(name, suggested),
+			}
+		}
+	}
+
+	// The bottleneck is the resource that alone admits the fewest members. When every resource
+	// fits on its own the requests are fragmented across nodes, and the resource worth relaxing
+	// is the bottleneck instead
+	bottleneckFit := limit
+	for _, name := range analysisResources {
+		if requests[name] <= 0 {
+			continue
+		}
+		fit := membersFitting(free, map[corev1.ResourceName]int64{name: requests[name]}, limit)
+		if fit < bottleneckFit {
+			analysis.Bottleneck = string(name)
+			bottleneckFit = fit
+		}
+	}
+	if analysis.Bottleneck == "" && analysis.Relaxation != nil {
+		analysis.Bottleneck = analysis.Relaxation.Resource
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "%d of %d members fit on %d eligible nodes", analysis.FittingMembers, members, len(free))
+	if analysis.Bottleneck != "" {
+		fmt.Fprintf(&message, "; %s is the bottleneck", analysis.Bottleneck)
+	}
+	if analysis.Relaxation != nil {
+		fmt.Fprintf(&message, "; reducing %s per member from %s to %s would fit all members",
+			analysis.Relaxation.Resource, analysis.Relaxation.Requested, analysis.Relaxation.Suggested)
+	} else if analysis.FittingMembers > 0 {
+		fmt.Fprintf(&message, "; no single resource reduction fits all members, consider %d members", analysis.FittingMembers)
+	}
+	analysis.Message = message.String()
+	return analysis
+}
+
+// largestFittingRequest searches for the largest per-member request of one resource, below the
+// current one, at which every member fits with the other requests unchanged
+func largestFittingRequest(free []map[corev1.ResourceName]int64, requests map[corev1.ResourceName]int64,
+	name corev1.ResourceName, limit int64) (int64, bool) {
+	step := relaxationStep[name]
+	if step == 0 {
+		step = 1
+	}
+	current := requests[name]
+	if current <= step {
+		return 0, false
+	}
+
+	relaxed := make(map[corev1.ResourceName]int64, len(requests))
+	for n, v := range requests {
+		relaxed[n] = v
+	}
+	fits := func(units int64) bool {
+		relaxed[name] = units * step
+		return membersFitting(free, relaxed, limit) >= limit
+	}
+
+	// Binary search over whole steps strictly below the current request
+	low, high := int64(1), (current-1)/step
+	if high < low || !fits(low) {
+		return 0, false
+	}
+	for low < high {
+		mid := low + (high-low+1)/2
+		if fits(mid) {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low * step, true
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// DefaultPlanInterval is how often consolidation plans are proposed
+const DefaultPlanInterval = time.Hour
+
+// PlanEmitter periodically proposes the consolidation plan as an OptimizationPlan awaiting
+// approval. A pending plan nobody approved yet is refreshed in place, or deleted once no
+// node can be drained; nothing is proposed while another plan is approved, applied or
+// rolled back.
+type PlanEmitter struct {
+	planner  *ConsolidationPlanner
+	client   client.Client
+	interval time.Duration
+}
+
+// NewPlanEmitter creates an emitter storing the planner's plans through the client
+func NewPlanEmitter(planner *ConsolidationPlanner, client client.Client, interval time.Duration) *PlanEmitter {
+	return &PlanEmitter{planner: planner, client: client, interval: interval}
+}
+
+// Emit plans a consolidation and stores it, returning the stored plan, or nil when no plan
+// was proposed
+func (e *PlanEmitter) Emit(ctx context.Context) (*kcloudv1alpha1.OptimizationPlan, error) {
+	var plans kcloudv1alpha1.OptimizationPlanList
+	if err := e.client.List(ctx, &plans); err != nil {
+		return nil, fmt.Errorf("failed to list optimization plans: %w", err)
+	}
+	var pending *kcloudv1alpha1.OptimizationPlan
+	for i := range plans.Items {
+		plan := &plans.Items[i]
+		if plan.Status.Phase != "" && plan.Status.Phase != kcloudv1alpha1.PlanPhasePending {
+			if plan.Status.Phase == kcloudv1alpha1.PlanPhaseApplying ||
+				plan.Status.Phase == kcloudv1alpha1.PlanPhaseRollingBack {
+				return nil, nil
+			}
+			continue
+		}
+		if plan.Spec.Approved {
+			return nil, nil
+		}
+		pending = plan
+	}
+
+	consolidation, err := e.planner.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(consolidation.DrainedNodes) == 0 {
+		if pending != nil {
+			if err := e.client.Delete(ctx, pending); client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to delete stale optimization plan %s: %w", pending.Name, err)
+			}
+		}
+		return nil, nil
+	}
+
+	spec := planSpec(consolidation)
+	if pending != nil {
+		// Keep the abort settings users may have tuned before approving
+		spec.MaxScoreRegression, spec.StepTimeout = pending.Spec.MaxScoreRegression, pending.Spec.StepTimeout
+		spec.MaxCostIncrease, spec.MaxLatencyIncrease = pending.Spec.MaxCostIncrease, pending.Spec.MaxLatencyIncrease
+		spec.CanaryFraction = pending.Spec.CanaryFraction
+		pending.Spec = spec
+		if err := e.client.Update(ctx, pending); err != nil {
+			return nil, fmt.Errorf("failed to refresh optimization plan %s: %w", pending.Name, err)
+		}
+		return pending, nil
+	}
+	plan := &kcloudv1alpha1.OptimizationPlan{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "consolidation-"},
+		Spec:       spec,
+	}
+	if err := e.client.Create(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to create optimization plan: %w", err)
+	}
+	return plan, nil
+}
+
+// planSpec converts a consolidation plan into the spec of an OptimizationPlan. Moves are
+// ordered by pod so replicas of one workload are adjacent and move in canary steps.
+func planSpec(consolidation *optimizer.ConsolidationPlan) kcloudv1alpha1.OptimizationPlanSpec {
+	spec := kcloudv1alpha1.OptimizationPlanSpec{
+		DrainNodes:             make([]string, 0, len(consolidation.DrainedNodes)),
+		Moves:                  make([]kcloudv1alpha1.PlanMove, 0, len(consolidation.Migrations)),
+		Currency:               string(consolidation.Currency),
+		ExpectedHourlySavings:  consolidation.HourlyCostSavings,
+		ExpectedMonthlySavings: consolidation.MonthlyCostSavings,
+		ExpectedPowerSavings:   consolidation.PowerSavings,
+		Confidence:             consolidation.Confidence,
+		GeneratedAt:            metav1.NewTime(consolidation.GeneratedAt),
+	}
+	for _, node := range consolidation.DrainedNodes {
+		spec.DrainNodes = append(spec.DrainNodes, node.Name)
+	}
+	for _, migration := range consolidation.Migrations {
+		spec.Moves = append(spec.Moves, kcloudv1alpha1.PlanMove{
+			Namespace: migration.Namespace,
+			Pod:       migration.Pod,
+			FromNode:  migration.FromNode,
+			ToNode:    migration.ToNode,
+		})
+	}
+	slices.SortStableFunc(spec.Moves, func(a, b kcloudv1alpha1.PlanMove) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Pod, b.Pod))
+	})
+	return spec
+}
+
+// Start proposes a plan on each interval once the snapshot has synced
+func (e *PlanEmitter) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("optimization-plan")
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if !e.planner.snapshot.HasSynced() {
+				continue
+			}
+			plan, err := e.Emit(ctx)
+			if err != nil {
+				log.Error(err, "Failed to propose optimization plan")
+				continue
+			}
+			if plan != nil {
+				log.Info("Optimization plan proposed", "plan", plan.Name, "drainNodes", plan.Spec.DrainNodes,
+					"moves", len(plan.Spec.Moves), "monthlySavings", plan.Spec.ExpectedMonthlySavings)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection lets only the leader write plans
+func (e *PlanEmitter) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// Plugin is a named scheduling criterion
+type Plugin interface {
+	Name() string
+}
+
+// FilterPlugin rejects nodes that must not host a workload under a policy
+type FilterPlugin interface {
+	Plugin
+	Filter(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, policy *SchedulingPolicy) (bool, string)
+}
+
+// ScorePlugin scores a node for a workload in the range 0.0-1.0
+type ScorePlugin interface {
+	Plugin
+	Score(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64
+}
+
+// PartialScorePlugin scores only the nodes it has an opinion on; the nodes it passes over
+// are scored by the other factors alone instead of being given a neutral score
+type PartialScorePlugin interface {
+	Plugin
+	ScoreIfApplicable(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) (float64, bool)
+}
+
+// PluginFactory builds a plugin bound to an advanced scheduler
+type PluginFactory func(as *AdvancedScheduler) Plugin
+
+// pluginRegistration holds a registered plugin factory and its default score weight
+type pluginRegistration struct {
+	factory       PluginFactory
+	defaultWeight float64
+}
+
+var (
+	pluginRegistry = map[string]pluginRegistration{}
+	registryMutex  sync.RWMutex
+)
+
+// RegisterPlugin makes a plugin available to every AdvancedScheduler created afterwards.
+// Downstream builds call it from an init function to compile in custom criteria.
+func RegisterPlugin(name string, defaultWeight float64, factory PluginFactory) error {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, exists := pluginRegistry[name]; exists {
+		return fmt.Errorf("scheduler plugin %s is already registered", name)
+	}
+	pluginRegistry[name] = pluginRegistration{factory: factory, defaultWeight: defaultWeight}
+	return nil
+}
+
+// MustRegisterPlugin registers a plugin and panics on duplicate names
+func MustRegisterPlugin(name string, defaultWeight float64, factory PluginFactory) {
+	if err := RegisterPlugin(name, defaultWeight, factory); err != nil {
+		panic(err)
+	}
+}
+
+// RegisteredPlugins returns the names of all registered plugins in sorted order
+func RegisteredPlugins() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(pluginRegistry))
+	for name := range pluginRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// instantiatePlugins builds every registered plugin for a scheduler
+func instantiatePlugins(as *AdvancedScheduler) ([]Plugin, map[string]float64) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(pluginRegistry))
+	for name := range pluginRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	plugins := make([]Plugin, 0, len(names))
+	weights := make(map[string]float64, len(names))
+	for _, name := range names {
+		registration := pluginRegistry[name]
+		plugins = append(plugins, registration.factory(as))
+		weights[name] = registration.defaultWeight
+	}
+	return plugins, weights
+}
+
+// Built-in plugin names
+const (
+	PluginCost        = "cost"
+	PluginPower       = "power"
+	PluginLoad        = "load"
+	PluginPriority    = "priority"
+	PluginAffinity    = "affinity"
+	PluginMarketplace = "marketplace"
+	PluginGPUMode     = "gpu-mode"
+	PluginReservation = "reservation"
+)
+
+func init() {
+	MustRegisterPlugin(PluginCost, 0.4, func(as *AdvancedScheduler) Plugin { return &costPlugin{as: as} })
+	MustRegisterPlugin(PluginPower, 0.3, func(as *AdvancedScheduler) Plugin { return &powerPlugin{as: as} })
+	MustRegisterPlugin(PluginLoad, 0.3, func(as *AdvancedScheduler) Plugin { return &loadPlugin{as: as} })
+	MustRegisterPlugin(PluginPriority, 0, func(as *AdvancedScheduler) Plugin { return &priorityPlugin{as: as} })
+	MustRegisterPlugin(PluginAffinity, 0, func(as *AdvancedScheduler) Plugin { return &affinityPlugin{as: as} })
+	MustRegisterPlugin(PluginMarketplace, MarketplaceScoreWeight, func(as *AdvancedScheduler) Plugin { return &marketplacePlugin{as: as} })
+	MustRegisterPlugin(PluginReservation, 0, func(as *AdvancedScheduler) Plugin { return &reservationPlugin{as: as} })
+	MustRegisterPlugin(PluginGPUMode, 0.1, func(as *AdvancedScheduler) Plugin { return &gpuModePlugin{as: as} })
+}
+
+// costPlugin scores by cost efficiency and enforces policy cost constraints
+type costPlugin struct {
+	as *AdvancedScheduler
+}
+
+func (p *costPlugin) Name() string { return PluginCost }
+
+func (p *costPlugin) Filter(_ context.Context, _ *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, policy *SchedulingPolicy) (bool, string) {
+	if policy.CostConstraints == nil || p.as.nodeMeetsCostConstraints(node, policy.CostConstraints) {
+		return true, ""
+	}
+	return false, "node violates policy cost constraints"
+}
+
+func (p *costPlugin) Score(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
+	return p.as.Scheduler.calculateCostScore(wo, *node)
+}
+
+// powerPlugin scores by power efficiency and enforces policy power constraints
+type powerPlugin struct {
+	as *AdvancedScheduler
+}
+
+func (p *powerPlugin) Name() string { return PluginPower }
+
+func (p *powerPlugin) Filter(_ context.Context, _ *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, policy *SchedulingPolicy) (bool, string) {
+	if policy.PowerConstraints == nil || p.as.nodeMeetsPowerConstraints(node, policy.PowerConstraints) {
+		return true, ""
+	}
+	return false, "node violates policy power constraints"
+}
+
+func (p *powerPlugin) Score(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
+	return p.as.Scheduler.calculatePowerScore(wo, *node)
+}
+
+// loadPlugin scores by resource headroom and enforces policy resource constraints
+type loadPlugin struct {
+	as *AdvancedScheduler
+}
+
+func (p *loadPlugin) Name() string { return PluginLoad }
+
+func (p *loadPlugin) Filter(_ context.Context, _ *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, policy *SchedulingPolicy) (bool, string) {
+	if policy.ResourceConstraints == nil || p.as.nodeMeetsResourceConstraints(node, policy.ResourceConstraints) {
+		return true, ""
+	}
+	return false, "node violates policy resource constraints"
+}
+
+func (p *loadPlugin) Score(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
+	return p.as.Scheduler.calculateResourceScore(wo, *node)
+}
+
+// priorityPlugin favours node tiers that match high-priority workloads
+type priorityPlugin struct {
+	as *AdvancedScheduler
+}
+
+func (p *priorityPlugin) Name() string { return PluginPriority }
+
+func (p *priorityPlugin) Score(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
+	// Workload priority is 0-100 and node tiers scale it by up to 1.2
+	return p.as.calculateNodePriority(wo, node) / 120.0
+}
+
+// affinityPlugin scores placement affinity rules and enforces taints, the node selector
+// and required pod (anti-)affinity
+type affinityPlugin struct {
+	as *AdvancedScheduler
+}
+
+func (p *affinityPlugin) Name() string { return PluginAffinity }
+
+func (p *affinityPlugin) Filter(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node, _ *SchedulingPolicy) (bool, string) {
+	if taint, found := untoleratedTaint(wo, node); found {
+		return false, taintReason(taint)
+	}
+	if wo.Spec.PlacementPolicy == nil {
+		return true, ""
+	}
+	for key, value := range wo.Spec.PlacementPolicy.NodeSelector {
+		if node.Labels[key] != value {
+			return false, fmt.Sprintf("node label %s does not match selector", key)
+		}
+	}
+	return p.as.podAffinityFits(wo, *node)
+}
+
+func (p *affinityPlugin) Score(_ context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
+	return p.as.Scheduler.calculatePlacementScore(wo, *node)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// podTermMatcher selects the running pods a pod affinity term refers to
+type podTermMatcher struct {
+	// key identifies the term's topology key, selector and namespaces
+	key           string
+	topologyKey   string
+	selector      labels.Selector
+	namespaces    map[string]bool
+	allNamespaces bool
+}
+
+// newPodTermMatcher compiles a term; an empty namespace list means the workload's namespace
+// and an empty namespace selector means every namespace
+func newPodTermMatcher(term corev1.PodAffinityTerm, namespace string) (*podTermMatcher, error) {
+	if term.TopologyKey == "" {
+		return nil, fmt.Errorf("pod affinity term has no topology key")
+	}
+	if term.NamespaceSelector != nil && (len(term.NamespaceSelector.MatchLabels) > 0 ||
+		len(term.NamespaceSelector.MatchExpressions) > 0) {
+		return nil, fmt.Errorf("pod affinity namespace selectors other than {} are not supported")
+	}
+
+	// A nil label selector matches no pods
+	selector := labels.Nothing()
+	selectorKey := "<none>"
+	if term.LabelSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(term.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pod affinity label selector: %w", err)
+		}
+		selectorKey = selector.String()
+	}
+
+	matcher := &podTermMatcher{
+		topologyKey:   term.TopologyKey,
+		selector:      selector,
+		namespaces:    make(map[string]bool),
+		allNamespaces: term.NamespaceSelector != nil && len(term.Namespaces) == 0,
+	}
+	for _, ns := range term.Namespaces {
+		matcher.namespaces[ns] = true
+	}
+	if len(term.Namespaces) == 0 && term.NamespaceSelector == nil {
+		matcher.namespaces[namespace] = true
+	}
+	namespaces := slices.Sorted(maps.Keys(matcher.namespaces))
+	matcher.key = fmt.Sprintf("%s|%s|%t|%s", matcher.topologyKey, selectorKey, matcher.allNamespaces,
+		strings.Join(namespaces, ","))
+	return matcher, nil
+}
+
+// selects reports whether the term refers to pods in the namespace with the labels
+func (m *podTermMatcher) selects(namespace string, podLabels map[string]string) bool {
+	return (m.allNamespaces || m.namespaces[namespace]) && m.selector.Matches(labels.Set(podLabels))
+}
+
+// termCounts are the running pods matching a term in each topology domain and in total
+type termCounts struct {
+	domains map[string]int
+	total   int
+}
+
+// affinityIndex caches term counts for one version of the snapshot, so a scheduling pass
+// scans the running pods once per term rather than once per node
+type affinityIndex struct {
+	mu      sync.Mutex
+	version uint64
+	terms   map[string]termCounts
+}
+
+// termCounts counts the running pods matching the term, indexed by topology domain
+func (s *ClusterSnapshot) termCounts(matcher *podTermMatcher) termCounts {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.affinity.mu.Lock()
+	defer s.affinity.mu.Unlock()
+	if s.affinity.terms == nil || s.affinity.version != s.version {
+		s.affinity.terms = make(map[string]termCounts)
+		s.affinity.version = s.version
+	}
+	if counts, ok := s.affinity.terms[matcher.key]; ok {
+		return counts
+	}
+
+	counts := termCounts{domains: make(map[string]int)}
+	for _, pod := range s.pods {
+		if !matcher.selects(pod.namespace, pod.labels) {
+			continue
+		}
+		counts.total++
+		podNode, ok := s.nodes[pod.nodeName]
+		if !ok {
+			continue
+		}
+		if domain, ok := podNode.Labels[matcher.topologyKey]; ok {
+			counts.domains[domain]++
+		}
+	}
+	s.affinity.terms[matcher.key] = counts
+	return counts
+}
+
+// countMatchingPods counts running pods that match the term in the node's topology domain
+func (s *ClusterSnapshot) countMatchingPods(node corev1.Node, matcher *podTermMatcher) int {
+	domain, ok := node.Labels[matcher.topologyKey]
+	if !ok {
+		return 0
+	}
+	return s.termCounts(matcher).domains[domain]
+}
+
+// podAffinityFits checks the workload's required pod affinity and anti-affinity terms
+// against pods running in each term's topology domain of the node. As upstream, an affinity
+// term no running pod matches is satisfied when it selects the workload itself, so the first
+// pod of a self-affine group can be placed.
+func (s *Scheduler) podAffinityFits(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) (bool, string) {
+	policy := wo.Spec.PlacementPolicy
+	if s.snapshot == nil || policy == nil {
+		return true, ""
+	}
+
+	if policy.PodAffinity != nil {
+		for _, term := range policy.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			matcher, err := newPodTermMatcher(term, wo.Namespace)
+			if err != nil {
+				return false, err.Error()
+			}
+			if s.snapshot.countMatchingPods(node, matcher) > 0 {
+				continue
+			}
+			if _, ok := node.Labels[matcher.topologyKey]; ok && s.snapshot.termCounts(matcher).total == 0 &&
+				matcher.selects(wo.Namespace, wo.Labels) {
+				continue
+			}
+			return false, fmt.Sprintf("no pod matches required pod affinity in topology %s", term.TopologyKey)
+		}
+	}
+
+	if policy.PodAntiAffinity != nil {
+		for _, term := range policy.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			matcher, err := newPodTermMatcher(term, wo.Namespace)
+			if err != nil {
+				return false, err.Error()
+			}
+			if s.snapshot.countMatchingPods(node, matcher) > 0 {
+				return false, fmt.Sprintf("pods match required pod anti-affinity in topology %s", term.TopologyKey)
+			}
+		}
+	}
+	return true, ""
+}
+
+// podAffinityScore scores the workload's preferred pod affinity and anti-affinity terms in
+// the range 0.0-1.0; ok is false when the workload has no preferred terms
+func (s *Scheduler) podAffinityScore(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) (score float64, ok bool) {
+	policy := wo.Spec.PlacementPolicy
+	if s.snapshot == nil || policy == nil {
+		return 0, false
+	}
+
+	var preferred, avoided []corev1.WeightedPodAffinityTerm
+	if policy.PodAffinity != nil {
+		preferred = policy.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	}
+	if policy.PodAntiAffinity != nil {
+		avoided = policy.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	}
+
+	totalWeight := 0.0
+	matchedWeight := 0.0
+	for _, weighted := range preferred {
+		matcher, err := newPodTermMatcher(weighted.PodAffinityTerm, wo.Namespace)
+		if err != nil {
+			continue
+		}
+		totalWeight += float64(weighted.Weight)
+		if s.snapshot.countMatchingPods(node, matcher) > 0 {
+			matchedWeight += float64(weighted.Weight)
+		}
+	}
+	for _, weighted := range avoided {
+		matcher, err := newPodTermMatcher(weighted.PodAffinityTerm, wo.Namespace)
+		if err != nil {
+			continue
+		}
+		totalWeight += float64(weighted.Weight)
+		if s.snapshot.countMatchingPods(node, matcher) == 0 {
+			matchedWeight += float64(weighted.Weight)
+		}
+	}
+
+	if totalWeight == 0 {
+		return 0, false
+	}
+	return matchedWeight / totalWeight, true
+}
@@ -43,8 +43,13 @@ type PolicyTemplate struct {
 	Name        string
 	Description string
 	Algorithm   SchedulingAlgorithm
-	Constraints *PolicyConstraints
-	Default     bool
+	// FallbackAlgorithms and MinScore configure the policy's algorithm chain
+	FallbackAlgorithms []SchedulingAlgorithm
+	MinScore           float64
+	// Normalization selects the balanced algorithm's score normalization
+	Normalization ScoreNormalization
+	Constraints   *PolicyConstraints
+	Default       bool
 }
 
 // PolicyConstraints defines constraints for a policy template
@@ -109,10 +114,13 @@ func (pm *PolicyManager) CreatePolicy(ctx context.Context, name string, template
 	}
 
 	policy := &SchedulingPolicy{
-		Name:      name,
-		Algorithm: template.Algorithm,
-		Enabled:   true,
-		Priority:  0,
+		Name:               name,
+		Algorithm:          template.Algorithm,
+		FallbackAlgorithms: template.FallbackAlgorithms,
+		MinScore:           template.MinScore,
+		Normalization:      template.Normalization,
+		Enabled:            true,
+		Priority:           0,
 	}
 
 	if template.Constraints != nil {
@@ -147,6 +155,15 @@ func (pm *PolicyManager) UpdatePolicy(ctx context.Context, name string, updates
 	if updates.Algorithm != "" {
 		policy.Algorithm = updates.Algorithm
 	}
+	if updates.FallbackAlgorithms != nil {
+		policy.FallbackAlgorithms = updates.FallbackAlgorithms
+	}
+	if updates.MinScore != 0 {
+		policy.MinScore = updates.MinScore
+	}
+	if updates.Normalization != "" {
+		policy.Normalization = updates.Normalization
+	}
 	if updates.ResourceConstraints != nil {
 		policy.ResourceConstraints = updates.ResourceConstraints
 	}
@@ -159,6 +176,9 @@ func (pm *PolicyManager) UpdatePolicy(ctx context.Context, name string, updates
 	if updates.TimeConstraints != nil {
 		policy.TimeConstraints = updates.TimeConstraints
 	}
+	if updates.StandbyReservations != nil {
+		policy.StandbyReservations = updates.StandbyReservations
+	}
 	if updates.Priority != 0 {
 		policy.Priority = updates.Priority
 	}
@@ -439,6 +459,22 @@ func (pm *PolicyManager) ValidatePolicy(policy *SchedulingPolicy) error {
 		return fmt.Errorf("invalid algorithm: %s", policy.Algorithm)
 	}
 
+	for _, algorithm := range policy.FallbackAlgorithms {
+		if !validAlgorithms[algorithm] {
+			return fmt.Errorf("invalid fallback algorithm: %s", algorithm)
+		}
+	}
+
+	if policy.MinScore < 0 || policy.MinScore > 1 {
+		return fmt.Errorf("minimum score must be between 0 and 1")
+	}
+
+	if policy.Normalization != "" {
+		if _, err := ParseScoreNormalization(string(policy.Normalization)); err != nil {
+			return err
+		}
+	}
+
 	// Validate constraints
 	if policy.ResourceConstraints != nil {
 		if err := pm.validateResourceConstraints(policy.ResourceConstraints); err != nil {
@@ -458,6 +494,39 @@ func (pm *PolicyManager) ValidatePolicy(policy *SchedulingPolicy) error {
 		}
 	}
 
+	registered := map[string]bool{}
+	for _, name := range RegisteredPlugins() {
+		registered[name] = true
+	}
+	for name, weight := range policy.PluginWeights {
+		if !registered[name] {
+			return fmt.Errorf("unknown scheduler plugin %s in weights", name)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight for plugin %s cannot be negative", name)
+		}
+	}
+
+	standby := map[string]bool{}
+	for _, reservation := range policy.StandbyReservations {
+		if err := reservation.Validate(); err != nil {
+			return err
+		}
+		if standby[reservation.Name] {
+			return fmt.Errorf("duplicate standby reservation %s", reservation.Name)
+		}
+		standby[reservation.Name] = true
+	}
+
+	for _, expr := range policy.ScoreExpressions {
+		if expr.Weight < 0 {
+			return fmt.Errorf("score expression %s weight cannot be negative", expr.Name)
+		}
+		if _, err := CompileScoreExpression(expr.Expression); err != nil {
+			return fmt.Errorf("invalid score expression %s: %w", expr.Name, err)
+		}
+	}
+
 	return nil
 }
 
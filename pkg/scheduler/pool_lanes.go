@@ -0,0 +1,264 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// Accelerator classes that separate scheduling lanes
+const (
+	LaneClassCPU = "cpu"
+	LaneClassGPU = "gpu"
+	LaneClassNPU = "npu"
+)
+
+var (
+	laneWaitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kcloud_scheduler_lane_wait_seconds",
+		Help:    "Time a scheduling pass waited for a free slot in a node pool lane",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 4, 10),
+	}, []string{"lane"})
+	laneDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kcloud_scheduler_lane_duration_seconds",
+		Help:    "Time spent filtering and scoring the nodes of a node pool lane",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 4, 10),
+	}, []string{"lane"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(laneWaitSeconds, laneDurationSeconds)
+}
+
+// schedulingLane serializes scheduling passes over one node pool, with its own slots and
+// infeasibility cache so contention in one pool does not delay the others
+type schedulingLane struct {
+	name       string
+	slots      chan struct{}
+	infeasible *InfeasibleCache
+}
+
+// acquire waits for a free slot in the lane
+func (l *schedulingLane) acquire(ctx context.Context) error {
+	start := time.Now()
+	select {
+	case l.slots <- struct{}{}:
+		laneWaitSeconds.WithLabelValues(l.name).Observe(time.Since(start).Seconds())
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for scheduling lane %s: %w", l.name, ctx.Err())
+	}
+}
+
+// release frees the slot taken by acquire
+func (l *schedulingLane) release() {
+	<-l.slots
+}
+
+// PoolLanes holds one scheduling lane per node pool
+type PoolLanes struct {
+	mu          sync.Mutex
+	concurrency int
+	ttl         time.Duration
+	lanes       map[string]*schedulingLane
+}
+
+// NewPoolLanes creates lanes that each allow concurrency scheduling passes at a time
+func NewPoolLanes(concurrency int) *PoolLanes {
+	return &PoolLanes{
+		concurrency: max(concurrency, 1),
+		ttl:         DefaultInfeasibleTTL,
+		lanes:       make(map[string]*schedulingLane),
+	}
+}
+
+// lane returns the named lane, creating it on first use
+func (p *PoolLanes) lane(name string) *schedulingLane {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l, ok := p.lanes[name]
+	if !ok {
+		l = &schedulingLane{
+			name:       name,
+			slots:      make(chan struct{}, p.concurrency),
+			infeasible: NewInfeasibleCache(p.ttl),
+		}
+		p.lanes[name] = l
+	}
+	return l
+}
+
+// Names returns the lanes created so far, sorted
+func (p *PoolLanes) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.lanes))
+	for name := range p.lanes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// invalidatePool forgets the conclusions of a lane's cache about a pool
+func (p *PoolLanes) invalidatePool(laneName, pool string) {
+	p.mu.Lock()
+	l, ok := p.lanes[laneName]
+	p.mu.Unlock()
+	if ok {
+		l.infeasible.InvalidatePool(pool)
+	}
+}
+
+// invalidateAll forgets the conclusions of every lane's cache
+func (p *PoolLanes) invalidateAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, l := range p.lanes {
+		l.infeasible.InvalidateAll()
+	}
+}
+
+// SetPoolLanes schedules each node pool on its own lane allowing concurrency passes at a
+// time; zero or less turns lanes off. Workloads consider lanes of their accelerator
+// class first, so CPU-only placements only wait on GPU or NPU pools when no CPU node fits.
+func (s *Scheduler) SetPoolLanes(concurrency int) {
+	if concurrency <= 0 {
+		s.lanes = nil
+		return
+	}
+	s.lanes = NewPoolLanes(concurrency)
+}
+
+// PoolLanes returns the scheduling lanes, or nil when lanes are off
+func (s *Scheduler) PoolLanes() *PoolLanes {
+	return s.lanes
+}
+
+// nodeLaneClass returns the accelerator class of a node
+func nodeLaneClass(node corev1.Node) string {
+	if gpu, ok := node.Status.Allocatable["nvidia.com/gpu"]; ok && !gpu.IsZero() {
+		return LaneClassGPU
+	}
+	if npu, ok := node.Status.Allocatable["npu.com/npu"]; ok && !npu.IsZero() {
+		return LaneClassNPU
+	}
+	return LaneClassCPU
+}
+
+// workloadLaneClass returns the accelerator class a workload must be placed in
+func workloadLaneClass(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	switch {
+	case requestsGPU(wo):
+		return LaneClassGPU
+	case wo.Spec.Resources.NPU > 0:
+		return LaneClassNPU
+	default:
+		return LaneClassCPU
+	}
+}
+
+// laneName names the lane of a node: its accelerator class, qualified by its pool if any
+func (s *Scheduler) laneName(node corev1.Node) string {
+	class := nodeLaneClass(node)
+	if pool := s.nodePool(node); pool != "" {
+		return class + "/" + pool
+	}
+	return class
+}
+
+// invalidateNodePool drops cached conclusions about the node's pool, in its lane as well
+// when lanes are on
+func (s *Scheduler) invalidateNodePool(node corev1.Node) {
+	pool := s.nodePool(node)
+	s.infeasible.InvalidatePool(pool)
+	if s.lanes != nil {
+		s.lanes.invalidatePool(s.laneName(node), pool)
+	}
+}
+
+// evaluateInLanes evaluates the nodes of every lane in the workload's accelerator class
+// concurrently, each lane under its own slot and infeasibility cache. When none of them
+// has a node the workload can run on, the other lanes are evaluated as well, since CPU
+// work may still fit on GPU or NPU nodes.
+func (s *Scheduler) evaluateInLanes(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	nodes []corev1.Node, eval *nodeEvaluation) error {
+	class := workloadLaneClass(wo)
+	preferred := make(map[string][]int)
+	others := make(map[string][]int)
+	for i, node := range nodes {
+		name := s.laneName(node)
+		if nodeLaneClass(node) == class {
+			preferred[name] = append(preferred[name], i)
+		} else {
+			others[name] = append(others[name], i)
+		}
+	}
+
+	if err := s.evaluateLanes(ctx, wo, nodes, preferred, eval); err != nil {
+		return err
+	}
+	for _, indices := range preferred {
+		for _, i := range indices {
+			if eval.meetsRequirements[i] && eval.decisions[i] != nil {
+				return nil
+			}
+		}
+	}
+	return s.evaluateLanes(ctx, wo, nodes, others, eval)
+}
+
+// evaluateLanes evaluates the given lanes concurrently and returns the first error
+func (s *Scheduler) evaluateLanes(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer,
+	nodes []corev1.Node, byLane map[string][]int, eval *nodeEvaluation) error {
+	var wg sync.WaitGroup
+	errs := make(chan error, len(byLane))
+	for name, indices := range byLane {
+		lane := s.lanes.lane(name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := lane.acquire(ctx); err != nil {
+				errs <- err
+				return
+			}
+			defer lane.release()
+
+			start := time.Now()
+			if err := s.evaluateNodes(ctx, wo, nodes, indices, lane.infeasible, eval); err != nil {
+				errs <- err
+				return
+			}
+			laneDurationSeconds.WithLabelValues(name).Observe(time.Since(start).Seconds())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
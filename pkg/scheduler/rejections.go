@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// NodeRejection records why a node cannot run a workload
+type NodeRejection struct {
+	Node   string
+	Reason string
+}
+
+// ExplainRejections returns why each node cannot run the workload, in node order. Nodes
+// that could run it are left out.
+func (s *Scheduler) ExplainRejections(wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node) []NodeRejection {
+	var rejections []NodeRejection
+	for _, node := range nodes {
+		if reason := s.rejectionReason(wo, node); reason != "" {
+			rejections = append(rejections, NodeRejection{Node: node.Name, Reason: reason})
+		}
+	}
+	return rejections
+}
+
+// nodeFilter is a named requirement a node must meet to run a workload. check returns why
+// the node fails it, or "" when it passes.
+type nodeFilter struct {
+	name  string
+	check func(s *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string
+}
+
+// nodeFilters lists the scheduling requirements in the order they are checked
+var nodeFilters = []nodeFilter{
+	{name: "NodeReady", check: func(s *Scheduler, _ *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		if !s.isNodeReady(node) {
+			return "node is not ready"
+		}
+		return ""
+	}},
+	{name: "NodeSchedulable", check: func(_ *Scheduler, _ *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		return NodeUnschedulableReason(&node)
+	}},
+	{name: "TaintToleration", check: func(_ *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		if taint, found := untoleratedTaint(wo, &node); found {
+			return fmt.Sprintf("untolerated taint %s", taint.ToString())
+		}
+		return ""
+	}},
+	{name: "NodeSelector", check: func(_ *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		if wo.Spec.PlacementPolicy == nil {
+			return ""
+		}
+		keys := make([]string, 0, len(wo.Spec.PlacementPolicy.NodeSelector))
+		for key := range wo.Spec.PlacementPolicy.NodeSelector {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if value := wo.Spec.PlacementPolicy.NodeSelector[key]; node.Labels[key] != value {
+				return fmt.Sprintf("node selector %s=%s does not match", key, value)
+			}
+		}
+		return ""
+	}},
+	{name: "RenewableEnergy", check: func(s *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		if !s.meetsRenewableFraction(wo, node) {
+			return "node pool renewable energy fraction is below the green energy minimum"
+		}
+		return ""
+	}},
+	{name: "NodeResources", check: func(s *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		return s.insufficientResources(wo, node, nil)
+	}},
+	{name: "RunningPods", check: func(s *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		if s.snapshot == nil {
+			return ""
+		}
+		if reason := s.insufficientResources(wo, node, s.snapshot.Requested(node.Name, wo.Name)); reason != "" {
+			return reason + " after running pods"
+		}
+		return ""
+	}},
+	{name: "StandbyHeadroom", check: func(s *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		if reason := s.insufficientResources(wo, node, s.requestedOn(wo, node)); reason != "" {
+			return reason + " after standby headroom"
+		}
+		return ""
+	}},
+	{name: "PodAffinity", check: func(s *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		if fits, reason := s.podAffinityFits(wo, node); !fits {
+			return reason
+		}
+		return ""
+	}},
+	{name: "SLA", check: func(s *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		return s.slaViolation(wo, node)
+	}},
+	{name: "Thermal", check: func(s *Scheduler, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+		return s.thermalViolation(wo, node)
+	}},
+}
+
+// rejectionReason returns the first requirement the node fails, or "" when it meets them all
+func (s *Scheduler) rejectionReason(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+	for _, filter := range nodeFilters {
+		if reason := filter.check(s, wo, node); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// SummarizeRejections counts the nodes rejected for each reason, most common first, such
+// as "2 insufficient cpu, 1 node is not ready". Details after a reason's first colon, such
+// as the quantities available on each node, are left out so that the reasons group.
+func SummarizeRejections(rejections []NodeRejection) string {
+	counts := make(map[string]int)
+	for _, rejection := range rejections {
+		reason, _, _ := strings.Cut(rejection.Reason, ":")
+		counts[reason]++
+	}
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%d %s", counts[reason], reason)
+	}
+	return strings.Join(parts, ", ")
+}
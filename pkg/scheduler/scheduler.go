@@ -17,15 +17,25 @@ limitations under the License.
 package scheduler
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
 	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
 )
 
 // Scheduler handles workload scheduling decisions
@@ -33,6 +43,80 @@ type Scheduler struct {
 	// Scheduling policies and preferences
 	preferSpotInstances bool
 	preferGreenEnergy   bool
+	weights             ScoreWeights
+
+	// Node pool energy profiles used for green energy placement, the green energy policies
+	// selecting pools by renewable fraction, the policies holding standby headroom and the
+	// labels of namespaces they select by
+	energyMu        sync.RWMutex
+	energyProfiles  []kcloudv1alpha1.NodePowerProfile
+	greenPools      []GreenPoolPolicy
+	standby         []StandbyPolicy
+	namespaceLabels map[string]labels.Set
+
+	// Pools known not to fit a workload shape
+	infeasible *InfeasibleCache
+
+	// Number of nodes scored concurrently
+	parallelism int
+
+	// Per node pool scheduling lanes, nil when pools share one lane
+	lanes *PoolLanes
+
+	// Informer-backed view of pods and reservations on each node
+	snapshot *ClusterSnapshot
+
+	// Share of the node score given to image locality, per workload type
+	imageLocalityWeights map[string]float64
+
+	// Rates charged for the transfer to a workload's data endpoints in other zones and regions
+	transferPricing TransferPricing
+
+	// Dataset cache placement coordination
+	cacheTransferCostPerGB float64
+	cacheStats             datasetCacheRecorder
+
+	// Runs, scores and latency of scheduling passes
+	algorithmStats *algorithmStatsRecorder
+
+	// Attach a placement analysis to scheduling failures
+	placementAnalysis bool
+
+	// Preference for the node a workload is already assigned to
+	stickinessBonus      float64
+	stickinessHysteresis float64
+
+	// Accelerator types requested by logical name, nil when none are registered
+	accelerators *optimizer.AcceleratorRegistry
+	// overcommit scales allocatable capacity per workload type and resource
+	overcommit OvercommitPolicy
+	// sla predicts whether inference workloads meet their SLA on a node; nil ignores SLAs
+	sla *optimizer.SLAModel
+	// nodePricing prices nodes from their cloud price list; nil estimates from instance type
+	nodePricing optimizer.NodePricing
+	// gpuModels prices GPUs by the model a node has; nil treats every model alike
+	gpuModels optimizer.GPUModelPricing
+	// spotMarket quotes live spot prices and interruption risk; nil uses a fixed spot discount
+	spotMarket     *optimizer.SpotMarket
+	spotRiskWeight float64
+	// currency converts reported cost estimates into the reporting currency; nil reports in
+	// US dollars
+	currency *currency.Converter
+	// deadlines weighs nodes against the deadlines of batch and training workloads
+	deadlines bool
+	// measuredPower reports what nodes were measured drawing; nil scores power from node
+	// labels
+	measuredPower optimizer.NodePowerSource
+	// powerModels predicts the power a placement adds from models fitted per node class; nil
+	// scores power from measured draw or labels
+	powerModels *optimizer.NodePowerModels
+	// thermal keeps heavy workloads off hot nodes and spreads training across racks;
+	// temperatures is nil when node temperatures are not measured
+	thermal       bool
+	temperatures  optimizer.NodeTemperatureSource
+	thermalLimits optimizer.ThermalLimits
+	// marketplace steers workloads onto capacity their team claimed; nil ignores claims
+	marketplace *Marketplace
 }
 
 // SchedulingDecision represents a scheduling decision
@@ -42,6 +126,51 @@ type SchedulingDecision struct {
 	Reason         string
 	EstimatedCost  float64
 	EstimatedPower float64
+	// EstimatedCarbon is the emission rate of the estimated power in the node's pool, in
+	// g CO2e per hour
+	EstimatedCarbon float64
+	// Contributions maps each scoring criterion to its weighted share of Score
+	Contributions map[string]float64
+	// Algorithm is the scheduling algorithm that produced the decision
+	Algorithm SchedulingAlgorithm
+	// Stage is the position of Algorithm in the policy's fallback chain, 0 for the primary
+	Stage int
+	// RelaxedConstraints lists the caps widened to find a node, if any
+	RelaxedConstraints []kcloudv1alpha1.RelaxedConstraint
+	// Candidates holds the highest scoring candidate nodes, best first, always including
+	// the selected node
+	Candidates []CandidateScore
+}
+
+// MaxCandidateScores is the number of candidate nodes a decision keeps scores for
+const MaxCandidateScores = 5
+
+// CandidateScore records how a candidate node scored during a scheduling pass
+type CandidateScore struct {
+	Node           string  `json:"node"`
+	Score          float64 `json:"score"`
+	EstimatedCost  float64 `json:"estimatedCost"`
+	EstimatedPower float64 `json:"estimatedPower"`
+	// Contributions maps each scoring criterion to its weighted share of Score
+	Contributions map[string]float64 `json:"contributions,omitempty"`
+}
+
+// ScoreWeights configures how node evaluation combines its criteria
+type ScoreWeights struct {
+	Resource  float64
+	Cost      float64
+	Power     float64
+	Placement float64
+}
+
+// DefaultScoreWeights returns the default node evaluation weights
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{
+		Resource:  0.4,
+		Cost:      0.3,
+		Power:     0.2,
+		Placement: 0.1,
+	}
 }
 
 // NewScheduler creates a new scheduler instance
@@ -49,38 +178,262 @@ func NewScheduler() *Scheduler {
 	return &Scheduler{
 		preferSpotInstances: true,
 		preferGreenEnergy:   true,
+		weights:             DefaultScoreWeights(),
+		infeasible:          NewInfeasibleCache(DefaultInfeasibleTTL),
+		parallelism:         DefaultScoringParallelism,
+
+		imageLocalityWeights:   DefaultImageLocalityWeights(),
+		cacheTransferCostPerGB: DefaultCacheTransferCostPerGB,
+		transferPricing:        DefaultTransferPricing(),
+		algorithmStats:         newAlgorithmStatsRecorder(),
+		stickinessBonus:        DefaultStickinessBonus,
+		stickinessHysteresis:   DefaultStickinessHysteresis,
 	}
 }
 
+// SetScoreWeights replaces the node evaluation weights
+func (s *Scheduler) SetScoreWeights(weights ScoreWeights) error {
+	total := weights.Resource + weights.Cost + weights.Power + weights.Placement
+	if weights.Resource < 0 || weights.Cost < 0 || weights.Power < 0 || weights.Placement < 0 {
+		return fmt.Errorf("score weights cannot be negative")
+	}
+	if total <= 0 {
+		return fmt.Errorf("at least one score weight must be positive")
+	}
+	s.weights = weights
+	return nil
+}
+
+// ParseScoreWeights parses weights written as "resource=0.4,cost=0.3,power=0.2,placement=0.1";
+// criteria that are omitted keep their default weight
+func ParseScoreWeights(spec string) (ScoreWeights, error) {
+	weights := DefaultScoreWeights()
+	if strings.TrimSpace(spec) == "" {
+		return weights, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return weights, fmt.Errorf("invalid weight %q, expected name=value", pair)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return weights, fmt.Errorf("invalid weight for %s: %w", name, err)
+		}
+
+		switch name {
+		case "resource":
+			weights.Resource = weight
+		case "cost":
+			weights.Cost = weight
+		case "power":
+			weights.Power = weight
+		case "placement":
+			weights.Placement = weight
+		default:
+			return weights, fmt.Errorf("unknown score criterion %s", name)
+		}
+	}
+	return weights, nil
+}
+
+// WorkloadScope selects the workloads a policy covers
+type WorkloadScope struct {
+	// NamespaceSelector and WorkloadSelector select the covered workloads; nil selects all
+	NamespaceSelector labels.Selector
+	WorkloadSelector  labels.Selector
+}
+
+// covers reports whether the scope covers a workload in a namespace with the given labels
+func (p *WorkloadScope) covers(wo *kcloudv1alpha1.WorkloadOptimizer, namespaceLabels labels.Set) bool {
+	if p.NamespaceSelector != nil && !p.NamespaceSelector.Matches(namespaceLabels) {
+		return false
+	}
+	return p.WorkloadSelector == nil || p.WorkloadSelector.Matches(labels.Set(wo.Labels))
+}
+
+// GreenPoolPolicy is the minimum renewable fraction a green energy policy requires of the
+// pools hosting the green workloads it covers
+type GreenPoolPolicy struct {
+	// Name names the policy
+	Name string
+	WorkloadScope
+	// MinRenewableFraction is the renewable fraction (0.0-1.0) a pool must reach
+	MinRenewableFraction float64
+}
+
+// SetNodePowerProfiles updates the node pool energy profiles, the green energy policies that
+// set the minimum renewable fraction a pool must reach to host the green workloads they
+// cover, and the labels of the namespaces those policies select
+func (s *Scheduler) SetNodePowerProfiles(profiles []kcloudv1alpha1.NodePowerProfile, policies []GreenPoolPolicy,
+	namespaceLabels map[string]labels.Set) {
+	s.energyMu.Lock()
+	defer s.energyMu.Unlock()
+
+	// Pool membership decides feasibility, so changes drop cached conclusions; the renewable
+	// fraction a workload needs is part of its shape
+	if !samePoolProfiles(s.energyProfiles, profiles) {
+		s.infeasible.InvalidateAll()
+		if s.lanes != nil {
+			s.lanes.invalidateAll()
+		}
+	}
+	s.energyProfiles = profiles
+	s.greenPools = policies
+	s.namespaceLabels = namespaceLabels
+}
+
+// minRenewableFraction returns the renewable fraction the pools hosting a green workload
+// must reach, the strictest of the green energy policies covering it
+func (s *Scheduler) minRenewableFraction(wo *kcloudv1alpha1.WorkloadOptimizer) float64 {
+	if wo.Spec.PowerConstraints == nil || !wo.Spec.PowerConstraints.PreferGreen {
+		return 0
+	}
+
+	s.energyMu.RLock()
+	defer s.energyMu.RUnlock()
+
+	minFraction := 0.0
+	for i := range s.greenPools {
+		if s.greenPools[i].covers(wo, s.namespaceLabels[wo.Namespace]) {
+			minFraction = math.Max(minFraction, s.greenPools[i].MinRenewableFraction)
+		}
+	}
+	return minFraction
+}
+
+// workloadShape returns the workload's shape, including the renewable fraction its pool
+// must reach
+func (s *Scheduler) workloadShape(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	shape := WorkloadShape(wo)
+	if minFraction := s.minRenewableFraction(wo); minFraction > 0 {
+		shape += fmt.Sprintf(",renewable>=%g", minFraction)
+	}
+	return shape
+}
+
+// InfeasiblePools returns the cache of pools known not to fit workload shapes
+func (s *Scheduler) InfeasiblePools() *InfeasibleCache {
+	return s.infeasible
+}
+
+// nodePool returns the name of the node's pool, empty for nodes outside any profiled pool
+func (s *Scheduler) nodePool(node corev1.Node) string {
+	if profile := s.nodeEnergyProfile(node); profile != nil {
+		return profile.Spec.PoolName
+	}
+	return ""
+}
+
+// nodeEnergyProfile returns the energy profile of the node's pool, if any
+func (s *Scheduler) nodeEnergyProfile(node corev1.Node) *kcloudv1alpha1.NodePowerProfile {
+	s.energyMu.RLock()
+	defer s.energyMu.RUnlock()
+	return optimizer.ProfileForNode(s.energyProfiles, node)
+}
+
 // ScheduleWorkload schedules a workload to the best available node
 func (s *Scheduler) ScheduleWorkload(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node) (*SchedulingDecision, error) {
+	started := time.Now()
+	decision, err := s.scheduleWorkload(ctx, wo, nodes)
+	elapsed := time.Since(started)
+	if err != nil {
+		s.algorithmStats.record(AlgorithmWeightedScore, AlgorithmOutcomeFailed, 0, elapsed)
+		return nil, err
+	}
+	decision.Algorithm = AlgorithmWeightedScore
+	s.algorithmStats.record(AlgorithmWeightedScore, AlgorithmOutcomeSelected, decision.Score, elapsed)
+	return decision, nil
+}
+
+// scheduleWorkload filters and scores the nodes and picks the best one
+func (s *Scheduler) scheduleWorkload(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node) (*SchedulingDecision, error) {
 	log := log.FromContext(ctx)
 
 	if len(nodes) == 0 {
 		return nil, fmt.Errorf("no available nodes for scheduling")
 	}
 
-	var bestDecision *SchedulingDecision
-	bestScore := -1.0
-
 	log.Info("Evaluating nodes for scheduling", "nodeCount", len(nodes))
+	if s.deadlineAtRisk(wo) {
+		log.Info("Deadline at risk, preferring faster nodes", "completeBy", wo.Spec.Deadline.CompleteBy)
+	}
 
-	for _, node := range nodes {
-		decision, err := s.evaluateNode(ctx, wo, node)
-		if err != nil {
-			log.Error(err, "Failed to evaluate node", "node", node.Name)
+	// Skip pools already known not to fit this shape and track which of the rest do
+	shape := s.workloadShape(wo)
+	eval := newNodeEvaluation(len(nodes))
+	if s.lanes != nil {
+		if err := s.evaluateInLanes(ctx, wo, nodes, eval); err != nil {
+			return nil, err
+		}
+	} else {
+		indices := make([]int, len(nodes))
+		for i := range indices {
+			indices[i] = i
+		}
+		if err := s.evaluateNodes(ctx, wo, nodes, indices, s.infeasible, eval); err != nil {
+			return nil, err
+		}
+	}
+
+	// Collect candidates in node order so ties resolve the same way as a sequential scan
+	current := s.applyStickinessBonus(wo, eval.decisions)
+	var candidates []*SchedulingDecision
+	type cachedPool struct {
+		cache *InfeasibleCache
+		pool  string
+	}
+	poolFits := make(map[cachedPool]bool)
+	skipped := 0
+	for i, decision := range eval.decisions {
+		if eval.caches[i] == nil {
+			continue
+		}
+		if eval.skipped[i] {
+			skipped++
+			continue
+		}
+		key := cachedPool{cache: eval.caches[i], pool: eval.pools[i]}
+		// Only shape checks are cached; running pods change without node events
+		if !eval.fitsShape[i] {
+			if _, seen := poolFits[key]; !seen {
+				poolFits[key] = false
+			}
 			continue
 		}
+		poolFits[key] = true
+		if eval.meetsRequirements[i] && decision != nil {
+			candidates = append(candidates, decision)
+		}
+	}
+
+	for key, fits := range poolFits {
+		if !fits {
+			key.cache.MarkInfeasible(shape, key.pool)
+		}
+	}
+	if skipped > 0 {
+		log.V(1).Info("Skipped nodes in pools that cannot fit the workload", "skippedNodes", skipped)
+	}
 
-		if decision.Score > bestScore {
-			bestScore = decision.Score
-			bestDecision = decision
+	if len(candidates) == 0 {
+		if s.placementAnalysis {
+			return nil, &InsufficientCapacityError{Analysis: s.AnalyzePlacement(wo, nodes, 1)}
 		}
+		return nil, ErrNoSuitableNode
 	}
 
-	if bestDecision == nil {
-		return nil, fmt.Errorf("no suitable node found for scheduling")
+	candidates, relaxed, err := relaxConstraints(wo, candidates)
+	if err != nil {
+		return nil, err
 	}
+	bestDecision := s.selectDecision(wo, candidates, current)
+	if len(relaxed) > 0 {
+		bestDecision.RelaxedConstraints = relaxed
+		bestDecision.Reason += "; " + describeRelaxation(relaxed)
+	}
+	bestDecision.Candidates = topCandidates(candidates, bestDecision, MaxCandidateScores)
 
 	log.Info("Scheduling decision made",
 		"selectedNode", bestDecision.SelectedNode,
@@ -90,6 +443,138 @@ func (s *Scheduler) ScheduleWorkload(ctx context.Context, wo *kcloudv1alpha1.Wor
 	return bestDecision, nil
 }
 
+// nodeEvaluation holds per-node results of a scheduling pass, indexed like its nodes
+type nodeEvaluation struct {
+	// caches holds the infeasibility cache a node was checked against, nil if not considered
+	caches            []*InfeasibleCache
+	pools             []string
+	skipped           []bool
+	fitsShape         []bool
+	meetsRequirements []bool
+	decisions         []*SchedulingDecision
+}
+
+// newNodeEvaluation allocates results for count nodes
+func newNodeEvaluation(count int) *nodeEvaluation {
+	return &nodeEvaluation{
+		caches:            make([]*InfeasibleCache, count),
+		pools:             make([]string, count),
+		skipped:           make([]bool, count),
+		fitsShape:         make([]bool, count),
+		meetsRequirements: make([]bool, count),
+		decisions:         make([]*SchedulingDecision, count),
+	}
+}
+
+// evaluateNodes filters and scores the nodes at the given indices, skipping pools the
+// infeasibility cache rules out for the workload's shape
+func (s *Scheduler) evaluateNodes(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, nodes []corev1.Node,
+	indices []int, infeasible *InfeasibleCache, eval *nodeEvaluation) error {
+	log := log.FromContext(ctx)
+	shape := s.workloadShape(wo)
+
+	return s.forEachNode(ctx, len(indices), func(n int) {
+		i := indices[n]
+		node := nodes[i]
+		eval.caches[i] = infeasible
+		eval.pools[i] = s.nodePool(node)
+		if infeasible.Infeasible(shape, eval.pools[i]) {
+			eval.skipped[i] = true
+			return
+		}
+		if !s.nodeFitsShape(wo, node) {
+			return
+		}
+		eval.fitsShape[i] = true
+		if !s.nodeFitsRunningPods(wo, node) || s.slaViolation(wo, node) != "" || s.thermalViolation(wo, node) != "" {
+			return
+		}
+		eval.meetsRequirements[i] = true
+
+		decision, err := s.evaluateNode(ctx, wo, node)
+		if err != nil {
+			log.Error(err, "Failed to evaluate node", "node", node.Name)
+			return
+		}
+		eval.decisions[i] = decision
+	})
+}
+
+// selectDecision picks the best candidate and records whether it reuses the workload's
+// dataset cache
+func (s *Scheduler) selectDecision(wo *kcloudv1alpha1.WorkloadOptimizer, candidates []*SchedulingDecision,
+	current *SchedulingDecision) *SchedulingDecision {
+	bestDecision, reusesCache := s.chooseDecision(wo, candidates, current)
+	s.recordCachePlacement(wo, bestDecision.SelectedNode, reusesCache)
+	return bestDecision
+}
+
+// chooseDecision picks the highest scoring candidate. Nodes already holding the workload's
+// dataset cache are preferred before spreading to others, and the assigned node is kept
+// unless the move is worth more than the stickiness hysteresis.
+func (s *Scheduler) chooseDecision(wo *kcloudv1alpha1.WorkloadOptimizer, candidates []*SchedulingDecision,
+	current *SchedulingDecision) (*SchedulingDecision, bool) {
+	cacheNodes := s.cacheHoldingNodes(wo)
+	var bestDecision, bestCacheDecision *SchedulingDecision
+	for _, decision := range candidates {
+		if bestDecision == nil || decision.Score > bestDecision.Score {
+			bestDecision = decision
+		}
+		if cacheNodes[decision.SelectedNode] && (bestCacheDecision == nil || decision.Score > bestCacheDecision.Score) {
+			bestCacheDecision = decision
+		}
+	}
+	if !slices.Contains(candidates, current) {
+		current = nil
+	}
+
+	if bestCacheDecision != nil {
+		bestDecision = bestCacheDecision
+	}
+	// Do not give up a dataset cache the best node holds to stay put
+	if s.keepAssignedNode(bestDecision, current) && (bestCacheDecision == nil || cacheNodes[current.SelectedNode]) {
+		bestDecision = current
+		bestDecision.Reason += "; kept on assigned node"
+	}
+	if bestCacheDecision != nil {
+		bestDecision.Reason += fmt.Sprintf("; reuses dataset cache %s", datasetCache(wo))
+	}
+	return bestDecision, bestCacheDecision != nil
+}
+
+// topCandidates returns the scores of the limit highest scoring candidates, best first.
+// The selected node replaces the last entry when dataset cache or stickiness preference
+// chose it over higher scoring nodes.
+func topCandidates(candidates []*SchedulingDecision, selected *SchedulingDecision, limit int) []CandidateScore {
+	ranked := slices.Clone(candidates)
+	slices.SortStableFunc(ranked, func(a, b *SchedulingDecision) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if len(ranked) > limit {
+		if !slices.Contains(ranked[:limit], selected) {
+			ranked[limit-1] = selected
+		}
+		ranked = ranked[:limit]
+	}
+
+	scores := make([]CandidateScore, 0, len(ranked))
+	for _, decision := range ranked {
+		scores = append(scores, candidateScore(decision))
+	}
+	return scores
+}
+
+// candidateScore copies the scores of a decision
+func candidateScore(decision *SchedulingDecision) CandidateScore {
+	return CandidateScore{
+		Node:           decision.SelectedNode,
+		Score:          decision.Score,
+		EstimatedCost:  decision.EstimatedCost,
+		EstimatedPower: decision.EstimatedPower,
+		Contributions:  maps.Clone(decision.Contributions),
+	}
+}
+
 // evaluateNode evaluates a single node for workload scheduling
 func (s *Scheduler) evaluateNode(ctx context.Context, wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) (*SchedulingDecision, error) {
 	log := log.FromContext(ctx)
@@ -116,18 +601,63 @@ func (s *Scheduler) evaluateNode(ctx context.Context, wo *kcloudv1alpha1.Workloa
 	placementScore := s.calculatePlacementScore(wo, node)
 
 	// Calculate final score (weighted average)
-	finalScore := (resourceScore*0.4 + costScore*0.3 + powerScore*0.2 + placementScore*0.1)
+	w := s.weights
+	total := w.Resource + w.Cost + w.Power + w.Placement
+	contributions := map[string]float64{
+		"resource":  resourceScore * w.Resource / total,
+		"cost":      costScore * w.Cost / total,
+		"power":     powerScore * w.Power / total,
+		"placement": placementScore * w.Placement / total,
+	}
+
+	// Give image locality its share of the score when the workload declares images
+	if imageWeight := s.imageLocalityWeight(wo); imageWeight > 0 {
+		for criterion := range contributions {
+			contributions[criterion] *= 1 - imageWeight
+		}
+		contributions["image_locality"] = s.calculateImageLocalityScore(wo, node) * imageWeight
+	}
 
-	// Estimate cost and power for this node
-	estimatedCost := s.estimateNodeCost(wo, node)
+	// Give meeting the deadline its share of the score in deadline scheduling mode
+	if deadlineWeight := s.deadlineWeight(wo); deadlineWeight > 0 {
+		for criterion := range contributions {
+			contributions[criterion] *= 1 - deadlineWeight
+		}
+		contributions["deadline"] = deadlineFit(wo, node) * deadlineWeight
+	}
+
+	// Give thermal headroom and rack spread their share of the score for heavy workloads
+	if fit, ok := s.thermalFit(wo, node); ok {
+		for criterion := range contributions {
+			contributions[criterion] *= 1 - ThermalScoreWeight
+		}
+		contributions["thermal"] = fit * ThermalScoreWeight
+	}
+
+	// Give marketplace claims their share of the score on nodes that hold any
+	if fit, ok := s.marketplaceFit(ctx, wo, node); ok {
+		for criterion := range contributions {
+			contributions[criterion] *= 1 - MarketplaceScoreWeight
+		}
+		contributions["marketplace"] = fit * MarketplaceScoreWeight
+	}
+
+	finalScore := contributions["resource"] + contributions["cost"] + contributions["power"] +
+		contributions["placement"] + contributions["image_locality"] + contributions["deadline"] +
+		contributions["thermal"] + contributions["marketplace"]
+
+	// Estimate cost and power for this node, reporting the cost in the workload's currency
+	estimatedCost := s.currency.FromUSD(s.estimateNodeCost(wo, node))
 	estimatedPower := s.estimateNodePower(wo, node)
 
 	decision := &SchedulingDecision{
-		SelectedNode:   node.Name,
-		Score:          finalScore,
-		Reason:         s.generateReason(resourceScore, costScore, powerScore, placementScore),
-		EstimatedCost:  estimatedCost,
-		EstimatedPower: estimatedPower,
+		SelectedNode:    node.Name,
+		Score:           finalScore,
+		Reason:          s.generateReason(resourceScore, costScore, powerScore, placementScore),
+		EstimatedCost:   estimatedCost,
+		EstimatedPower:  estimatedPower,
+		EstimatedCarbon: optimizer.CarbonGramsPerHour(optimizer.AccountCarbon(estimatedPower, s.nodeEnergyProfile(node)).CarbonFootprint),
+		Contributions:   contributions,
 	}
 
 	log.V(1).Info("Node evaluation completed",
@@ -143,11 +673,37 @@ func (s *Scheduler) evaluateNode(ctx context.Context, wo *kcloudv1alpha1.Workloa
 
 // nodeMeetsRequirements checks if a node meets the basic requirements
 func (s *Scheduler) nodeMeetsRequirements(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) bool {
+	return s.nodeFitsShape(wo, node) && s.nodeFitsRunningPods(wo, node) && s.slaViolation(wo, node) == "" &&
+		s.thermalViolation(wo, node) == ""
+}
+
+// nodeFitsShape checks the requirements that depend only on the node and the workload shape
+func (s *Scheduler) nodeFitsShape(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) bool {
+	if !s.nodeMeetsConstraints(wo, node) {
+		return false
+	}
+
+	// Check resource requirements
+	return s.hasSufficientResources(wo, node, nil)
+}
+
+// nodeMeetsConstraints checks the shape requirements other than resource capacity
+func (s *Scheduler) nodeMeetsConstraints(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) bool {
 	// Check if node is ready
 	if !s.isNodeReady(node) {
 		return false
 	}
 
+	// Never place on nodes that are cordoned or about to be removed
+	if NodeUnschedulableReason(&node) != "" {
+		return false
+	}
+
+	// Check that hard taints are tolerated
+	if _, found := untoleratedTaint(wo, &node); found {
+		return false
+	}
+
 	// Check node selector requirements
 	if wo.Spec.PlacementPolicy != nil && wo.Spec.PlacementPolicy.NodeSelector != nil {
 		for key, value := range wo.Spec.PlacementPolicy.NodeSelector {
@@ -157,8 +713,45 @@ func (s *Scheduler) nodeMeetsRequirements(wo *kcloudv1alpha1.WorkloadOptimizer,
 		}
 	}
 
-	// Check resource requirements
-	return s.hasSufficientResources(wo, node)
+	// Check green energy pool selection
+	return s.meetsRenewableFraction(wo, node)
+}
+
+// nodeFitsRunningPods checks the requirements that depend on pods already on the node and on
+// standby headroom
+func (s *Scheduler) nodeFitsRunningPods(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) bool {
+	if requested := s.requestedOn(wo, node); len(requested) > 0 && !s.hasSufficientResources(wo, node, requested) {
+		return false
+	}
+	if s.snapshot == nil {
+		return true
+	}
+	fits, _ := s.podAffinityFits(wo, node)
+	return fits
+}
+
+// requestedOn returns the resources the workload must leave to others on the node: the
+// requests of running pods and reservations, and standby headroom held for other policies
+func (s *Scheduler) requestedOn(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) corev1.ResourceList {
+	requested := s.standbyHeadroom(wo, &node)
+	if s.snapshot != nil {
+		addResources(requested, s.snapshot.Requested(node.Name, wo.Name))
+	}
+	return requested
+}
+
+// meetsRenewableFraction checks that green workloads land on pools with enough renewable energy
+func (s *Scheduler) meetsRenewableFraction(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) bool {
+	minFraction := s.minRenewableFraction(wo)
+	if minFraction <= 0 {
+		return true
+	}
+
+	profile := s.nodeEnergyProfile(node)
+	if profile == nil {
+		return false
+	}
+	return optimizer.RenewableFraction(profile.Spec.EnergyMix) >= minFraction
 }
 
 // isNodeReady checks if a node is in ready state
@@ -171,45 +764,85 @@ func (s *Scheduler) isNodeReady(node corev1.Node) bool {
 	return false
 }
 
-// hasSufficientResources checks if node has sufficient resources
-func (s *Scheduler) hasSufficientResources(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) bool {
+// hasSufficientResources checks if node has sufficient resources beyond those already requested
+func (s *Scheduler) hasSufficientResources(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node, requested corev1.ResourceList) bool {
+	return s.insufficientResources(wo, node, requested) == ""
+}
+
+// insufficientResources returns which of the workload's resources the node lacks beyond
+// those already requested, or "" when the workload fits
+func (s *Scheduler) insufficientResources(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node, requested corev1.ResourceList) string {
 	// Parse required resources
 	cpuReq := s.parseResourceQuantity(wo.Spec.Resources.CPU)
 	memoryReq := s.parseResourceQuantity(wo.Spec.Resources.Memory)
 
 	// Get available resources
-	cpuAvail := node.Status.Allocatable[corev1.ResourceCPU]
-	memoryAvail := node.Status.Allocatable[corev1.ResourceMemory]
+	cpuAvail := s.overcommittedAvailable(wo, node, requested, corev1.ResourceCPU)
+	memoryAvail := s.overcommittedAvailable(wo, node, requested, corev1.ResourceMemory)
 
 	// Check CPU
 	if cpuReq.Cmp(cpuAvail) > 0 {
-		return false
+		return insufficientReason(corev1.ResourceCPU, cpuReq, cpuAvail)
 	}
 
 	// Check Memory
 	if memoryReq.Cmp(memoryAvail) > 0 {
-		return false
+		return insufficientReason(corev1.ResourceMemory, memoryReq, memoryAvail)
 	}
 
 	// Check GPU if required
 	if wo.Spec.Resources.GPU > 0 {
-		gpuAvail := node.Status.Allocatable["nvidia.com/gpu"]
+		gpuAvail := s.overcommittedAvailable(wo, node, requested, "nvidia.com/gpu")
 		gpuReq := resource.MustParse(fmt.Sprintf("%d", wo.Spec.Resources.GPU))
 		if gpuReq.Cmp(gpuAvail) > 0 {
-			return false
+			return insufficientReason("nvidia.com/gpu", gpuReq, gpuAvail)
+		}
+	}
+
+	// Check the GPU share if required
+	if wo.Spec.Resources.GPUFraction != "" {
+		share, err := workloadGPUShare(wo)
+		if err != nil {
+			return err.Error()
+		}
+		if index, reason := fitGPUFraction(&node, requested, share); index < 0 {
+			return reason
+		}
+	}
+
+	// Check GPU memory of whole GPUs and MIG instances if required
+	if fits, reason := checkGPUMemory(&node, wo); !fits {
+		return reason
+	}
+
+	// Check the MIG instance if required
+	if wo.Spec.Resources.MIGProfile != "" {
+		name := MIGResourceName(wo.Spec.Resources.MIGProfile)
+		if migAvail := availableResource(node, requested, name); migAvail.Value() < 1 {
+			return insufficientReason(name, resource.MustParse("1"), migAvail)
 		}
 	}
 
 	// Check NPU if required
 	if wo.Spec.Resources.NPU > 0 {
-		npuAvail := node.Status.Allocatable["npu.com/npu"]
+		npuAvail := s.overcommittedAvailable(wo, node, requested, "npu.com/npu")
 		npuReq := resource.MustParse(fmt.Sprintf("%d", wo.Spec.Resources.NPU))
 		if npuReq.Cmp(npuAvail) > 0 {
-			return false
+			return insufficientReason("npu.com/npu", npuReq, npuAvail)
 		}
 	}
 
-	return true
+	// Check registered accelerators if required
+	if fits, reason := checkAccelerators(s.accelerators, wo, &node, requested); !fits {
+		return reason
+	}
+
+	return ""
+}
+
+// insufficientReason describes a resource the node has too little of
+func insufficientReason(name corev1.ResourceName, requested, available resource.Quantity) string {
+	return fmt.Sprintf("insufficient %s: %s requested, %s available", name, requested.String(), available.String())
 }
 
 // calculateResourceScore calculates resource availability score
@@ -238,9 +871,12 @@ func (s *Scheduler) calculateCostScore(wo *kcloudv1alpha1.WorkloadOptimizer, nod
 	// Prefer spot instances if configured
 	if s.preferSpotInstances && wo.Spec.CostConstraints != nil && wo.Spec.CostConstraints.PreferSpot {
 		if node.Labels["node.kubernetes.io/instance-type"] != "" {
-			// Check if it's a spot instance (simplified check)
-			if node.Labels["lifecycle"] == "spot" {
-				score += 0.3
+			// Check if it's a spot instance (simplified check), weighing its live price and
+			// interruption risk when quoted
+			if bonus, ok := s.spotBonus(wo, &node); ok {
+				score += bonus
+			} else if node.Labels["lifecycle"] == "spot" {
+				score += spotScoreBonus
 			}
 		}
 	}
@@ -255,7 +891,10 @@ func (s *Scheduler) calculateCostScore(wo *kcloudv1alpha1.WorkloadOptimizer, nod
 		}
 	}
 
-	return math.Min(1.0, score)
+	// Penalize moving data to endpoints in other zones and regions
+	score -= s.transferCostPenalty(wo, node)
+
+	return math.Max(0, math.Min(1.0, score))
 }
 
 // calculatePowerScore calculates power efficiency score
@@ -264,13 +903,21 @@ func (s *Scheduler) calculatePowerScore(wo *kcloudv1alpha1.WorkloadOptimizer, no
 
 	// Prefer green energy if configured
 	if s.preferGreenEnergy && wo.Spec.PowerConstraints != nil && wo.Spec.PowerConstraints.PreferGreen {
-		if node.Labels["energy-source"] == "renewable" {
+		if profile := s.nodeEnergyProfile(node); profile != nil {
+			// Scale the bonus by the pool's renewable fraction
+			score += 0.3 * optimizer.RenewableFraction(profile.Spec.EnergyMix)
+		} else if node.Labels["energy-source"] == "renewable" {
 			score += 0.3
 		}
 	}
 
-	// Prefer nodes with lower power consumption
-	if powerLabel, exists := node.Labels["power-efficiency"]; exists {
+	// Prefer nodes where the workload adds the least power, as predicted by the node's power
+	// model, else nodes measured drawing less, else those labeled efficient
+	if bonus, ok := s.modelPowerBonus(wo, node); ok {
+		score += bonus
+	} else if bonus, ok := s.measuredPowerBonus(node); ok {
+		score += bonus
+	} else if powerLabel, exists := node.Labels["power-efficiency"]; exists {
 		if powerLabel == "high" {
 			score += 0.2
 		} else if powerLabel == "medium" {
@@ -281,10 +928,32 @@ func (s *Scheduler) calculatePowerScore(wo *kcloudv1alpha1.WorkloadOptimizer, no
 	return math.Min(1.0, score)
 }
 
-// calculatePlacementScore calculates placement policy score
+// calculatePlacementScore calculates placement policy score from node affinity rules and
+// preferred pod affinity terms, less a penalty for untolerated PreferNoSchedule taints
 func (s *Scheduler) calculatePlacementScore(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) float64 {
+	nodeScore, hasNodeRules := s.nodeAffinityScore(wo, node)
+	podScore, hasPodTerms := s.podAffinityScore(wo, node)
+
+	var score float64
+	switch {
+	case hasNodeRules && hasPodTerms:
+		score = (nodeScore + podScore) / 2.0
+	case hasNodeRules:
+		score = nodeScore
+	case hasPodTerms:
+		score = podScore
+	default:
+		score = 0.5 // Neutral score if no affinity rules
+	}
+
+	penalty := preferNoSchedulePenalty * float64(untoleratedPreferNoSchedule(wo, &node))
+	return math.Max(0, score-penalty)
+}
+
+// nodeAffinityScore scores node affinity rules; ok is false when the workload has none
+func (s *Scheduler) nodeAffinityScore(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) (score float64, ok bool) {
 	if wo.Spec.PlacementPolicy == nil || len(wo.Spec.PlacementPolicy.Affinity) == 0 {
-		return 0.5 // Neutral score if no affinity rules
+		return 0, false
 	}
 
 	totalWeight := 0.0
@@ -304,10 +973,10 @@ func (s *Scheduler) calculatePlacementScore(wo *kcloudv1alpha1.WorkloadOptimizer
 	}
 
 	if totalWeight == 0 {
-		return 0.5
+		return 0, false
 	}
 
-	return totalScore / totalWeight
+	return totalScore / totalWeight, true
 }
 
 // estimateNodeCost estimates the cost for running workload on this node
@@ -315,9 +984,18 @@ func (s *Scheduler) estimateNodeCost(wo *kcloudv1alpha1.WorkloadOptimizer, node
 	// Base cost estimation (simplified)
 	baseCost := 10.0 // Base cost per hour
 
-	// Adjust based on node type
-	if instanceType, exists := node.Labels["node.kubernetes.io/instance-type"]; exists {
-		switch instanceType {
+	// Workloads preferring spot pay their share of a spot node's live spot price
+	if _, ok := s.spotQuote(wo, &node); ok {
+		if priced, ok := s.pricedNodeCost(s.spotMarket, wo, &node); ok {
+			return priced + s.accelerators.Cost(wo.Spec.Resources.Accelerators) + s.transferPricing.EgressCost(wo, &node)
+		}
+	}
+
+	// Charge the workload its share of the node's live price, or adjust based on node type
+	if priced, ok := s.pricedNodeCost(s.nodePricing, wo, &node); ok {
+		baseCost = priced
+	} else {
+		switch node.Labels["node.kubernetes.io/instance-type"] {
 		case "gpu-node":
 			baseCost += 20.0
 		case "cpu-intensive":
@@ -325,16 +1003,22 @@ func (s *Scheduler) estimateNodeCost(wo *kcloudv1alpha1.WorkloadOptimizer, node
 		case "memory-intensive":
 			baseCost += 8.0
 		}
+		// GPUs of pricier models cost more
+		baseCost += s.gpuModelAdjustment(wo, &node)
 	}
 
+	// Add the cost of registered accelerators
+	baseCost += s.accelerators.Cost(wo.Spec.Resources.Accelerators)
+
 	// Adjust based on spot instance preference
 	if wo.Spec.CostConstraints != nil && wo.Spec.CostConstraints.PreferSpot {
 		if node.Labels["lifecycle"] == "spot" {
-			baseCost *= 0.7 // 30% discount for spot instances
+			baseCost *= 1 - assumedSpotSaving
 		}
 	}
 
-	return baseCost
+	// Add the transfer to data in other zones and regions
+	return baseCost + s.transferPricing.EgressCost(wo, &node)
 }
 
 // estimateNodePower estimates the power consumption for running workload on this node
@@ -342,9 +1026,9 @@ func (s *Scheduler) estimateNodePower(wo *kcloudv1alpha1.WorkloadOptimizer, node
 	// Base power estimation (simplified)
 	basePower := 100.0 // Base power in Watts
 
-	// Add power for GPU
-	if wo.Spec.Resources.GPU > 0 {
-		basePower += float64(wo.Spec.Resources.GPU) * 300.0 // 300W per GPU
+	// Add power for GPU, whole or shared
+	if gpus := gpuEquivalent(wo); gpus > 0 {
+		basePower += gpus * 300.0 // 300W per GPU
 	}
 
 	// Add power for NPU
@@ -352,6 +1036,9 @@ func (s *Scheduler) estimateNodePower(wo *kcloudv1alpha1.WorkloadOptimizer, node
 		basePower += float64(wo.Spec.Resources.NPU) * 250.0 // 250W per NPU
 	}
 
+	// Add power for registered accelerators
+	basePower += s.accelerators.Power(wo.Spec.Resources.Accelerators)
+
 	// Adjust based on energy source
 	if wo.Spec.PowerConstraints != nil && wo.Spec.PowerConstraints.PreferGreen {
 		if node.Labels["energy-source"] == "renewable" {
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/testutil"
+)
+
+// benchmarkNodes builds a cluster of ready nodes with varied capacity and instance types
+func benchmarkNodes(count int) []corev1.Node {
+	instanceTypes := []string{"gpu-node", "cpu-intensive", "memory-intensive", "general"}
+	nodes := make([]corev1.Node, count)
+	for i := range nodes {
+		nodes[i] = testutil.NewNode(fmt.Sprintf("node-%05d", i)).
+			WithAllocatable(fmt.Sprintf("%d", 8+i%56), fmt.Sprintf("%dGi", 32+i%224)).
+			WithLabel("node.kubernetes.io/instance-type", instanceTypes[i%len(instanceTypes)]).
+			Build()
+	}
+	return nodes
+}
+
+// BenchmarkScheduleWorkload guards scheduling latency on large clusters
+func BenchmarkScheduleWorkload(b *testing.B) {
+	wo := testutil.NewWorkloadOptimizer("bench", "default").WithResources("4", "16Gi").Build()
+
+	for _, count := range []int{100, 1000, 5000} {
+		nodes := benchmarkNodes(count)
+
+		var sequential *scheduler.SchedulingDecision
+		for _, workers := range []int{1, scheduler.DefaultScoringParallelism} {
+			s := scheduler.NewScheduler()
+			s.SetParallelism(workers)
+
+			decision, err := s.ScheduleWorkload(context.Background(), wo, nodes)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if sequential == nil {
+				sequential = decision
+			} else if decision.SelectedNode != sequential.SelectedNode {
+				b.Fatalf("parallel scoring chose %s, sequential chose %s", decision.SelectedNode, sequential.SelectedNode)
+			}
+
+			b.Run(fmt.Sprintf("nodes=%d/workers=%d", count, workers), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := s.ScheduleWorkload(context.Background(), wo, nodes); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkScheduleBalanced covers plugin-based scoring in the advanced scheduler
+func BenchmarkScheduleBalanced(b *testing.B) {
+	wo := testutil.NewWorkloadOptimizer("bench", "default").WithResources("4", "16Gi").Build()
+	policy := &scheduler.SchedulingPolicy{Name: "bench", Algorithm: scheduler.AlgorithmBalanced, Enabled: true}
+
+	for _, count := range []int{1000, 5000} {
+		nodes := benchmarkNodes(count)
+		for _, workers := range []int{1, scheduler.DefaultScoringParallelism} {
+			as := scheduler.NewAdvancedScheduler()
+			as.SetParallelism(workers)
+
+			b.Run(fmt.Sprintf("nodes=%d/workers=%d", count, workers), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := as.ScheduleWithPolicy(context.Background(), wo, nodes, policy); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler_test
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/testutil"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden_decisions.json from the current scheduler")
+
+const goldenFile = "golden_decisions.json"
+
+// goldenAlgorithms lists every algorithm covered by the golden suite, in output order
+var goldenAlgorithms = []scheduler.SchedulingAlgorithm{
+	scheduler.AlgorithmRoundRobin,
+	scheduler.AlgorithmLeastLoaded,
+	scheduler.AlgorithmCostOptimized,
+	scheduler.AlgorithmPowerOptimized,
+	scheduler.AlgorithmBalanced,
+	scheduler.AlgorithmPriorityBased,
+}
+
+// goldenScenario is a canonical cluster and workload whose decisions are pinned in testdata
+type goldenScenario struct {
+	name     string
+	nodes    []corev1.Node
+	workload *kcloudv1alpha1.WorkloadOptimizer
+}
+
+// goldenDecision is the stable subset of a SchedulingDecision recorded in the golden file
+type goldenDecision struct {
+	SelectedNode string  `json:"selectedNode,omitempty"`
+	Score        float64 `json:"score,omitempty"`
+	Error        bool    `json:"error,omitempty"`
+}
+
+// mixedCluster is a small heterogeneous cluster shared by several scenarios. Callers hand
+// the schedulers ready nodes only, so the cluster holds no NotReady nodes
+func mixedCluster() []corev1.Node {
+	return []corev1.Node{
+		testutil.NewNode("cpu-small").WithAllocatable("4", "16Gi").
+			WithLabel("node.kubernetes.io/instance-type", "cpu-intensive").Build(),
+		testutil.NewNode("general-medium").WithAllocatable("16", "64Gi").
+			WithLabel("node.kubernetes.io/instance-type", "general").Build(),
+		testutil.NewNode("memory-large").WithAllocatable("32", "256Gi").
+			WithLabel("node.kubernetes.io/instance-type", "memory-intensive").Build(),
+		testutil.NewNode("gpu-a").WithAllocatable("32", "128Gi").WithGPU(4).
+			WithLabel("node.kubernetes.io/instance-type", "gpu-node").Build(),
+		testutil.NewNode("npu-a").WithAllocatable("16", "64Gi").WithNPU(2).
+			WithLabel("node.kubernetes.io/instance-type", "npu-node").Build(),
+	}
+}
+
+// goldenScenarios returns the canonical scenarios in output order
+func goldenScenarios() []goldenScenario {
+	return []goldenScenario{
+		{
+			name:     "small-serving",
+			nodes:    mixedCluster(),
+			workload: testutil.NewWorkloadOptimizer("web", "default").WithWorkloadType("serving").WithResources("1", "2Gi").Build(),
+		},
+		{
+			name:  "gpu-training",
+			nodes: mixedCluster(),
+			workload: testutil.NewWorkloadOptimizer("train", "ml").WithWorkloadType("training").
+				WithResources("8", "32Gi").WithGPU(2).WithPriority(80).Build(),
+		},
+		{
+			name:  "npu-inference",
+			nodes: mixedCluster(),
+			workload: testutil.NewWorkloadOptimizer("infer", "ml").WithWorkloadType("inference").
+				WithResources("4", "8Gi").WithNPU(1).Build(),
+		},
+		{
+			name:  "memory-heavy-batch",
+			nodes: mixedCluster(),
+			workload: testutil.NewWorkloadOptimizer("etl", "data").WithWorkloadType("batch").
+				WithResources("8", "192Gi").WithPriority(10).Build(),
+		},
+		{
+			name:  "cost-and-power-capped",
+			nodes: mixedCluster(),
+			workload: testutil.NewWorkloadOptimizer("capped", "default").WithResources("2", "4Gi").
+				WithMaxCost(5).WithMaxPower(300).Build(),
+		},
+		{
+			name:  "node-selector",
+			nodes: mixedCluster(),
+			workload: testutil.NewWorkloadOptimizer("pinned", "default").WithResources("2", "4Gi").
+				WithNodeSelector("node.kubernetes.io/instance-type", "general").Build(),
+		},
+		{
+			name:     "unschedulable",
+			nodes:    mixedCluster(),
+			workload: testutil.NewWorkloadOptimizer("huge", "default").WithResources("128", "1024Gi").Build(),
+		},
+		{
+			name:     "uniform-cluster",
+			nodes:    benchmarkNodes(12),
+			workload: testutil.NewWorkloadOptimizer("uniform", "default").WithResources("4", "16Gi").Build(),
+		},
+	}
+}
+
+// toGolden reduces a decision to its golden form, rounding scores so float noise does not churn the file
+func toGolden(decision *scheduler.SchedulingDecision, err error) goldenDecision {
+	if err != nil || decision == nil {
+		return goldenDecision{Error: true}
+	}
+	return goldenDecision{
+		SelectedNode: decision.SelectedNode,
+		Score:        math.Round(decision.Score*1e4) / 1e4,
+	}
+}
+
+// runGoldenScenarios schedules every scenario with the base scheduler and each algorithm
+func runGoldenScenarios() map[string]map[string]goldenDecision {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	results := make(map[string]map[string]goldenDecision)
+	for _, sc := range goldenScenarios() {
+		decisions := make(map[string]goldenDecision)
+
+		decision, err := scheduler.NewScheduler().ScheduleWorkload(ctx, sc.workload.DeepCopy(), sc.nodes)
+		decisions["default"] = toGolden(decision, err)
+
+		for _, algorithm := range goldenAlgorithms {
+			// A fresh scheduler per run keeps reservations and history from leaking between cases
+			as := scheduler.NewAdvancedScheduler()
+			as.SetClock(testutil.NewFakeClock(now))
+			as.SetHistoryStore(testutil.NewFakeHistoryStore())
+
+			policy := &scheduler.SchedulingPolicy{Name: "golden", Algorithm: algorithm, Enabled: true}
+			decision, err := as.ScheduleWithPolicy(ctx, sc.workload.DeepCopy(), sc.nodes, policy)
+			decisions[string(algorithm)] = toGolden(decision, err)
+		}
+		results[sc.name] = decisions
+	}
+	return results
+}
+
+// TestGoldenDecisions pins the node each algorithm picks for the canonical scenarios.
+// Scoring refactors must keep these decisions or regenerate them deliberately with
+// go test ./pkg/scheduler -run TestGoldenDecisions -update
+func TestGoldenDecisions(t *testing.T) {
+	got := runGoldenScenarios()
+	path := filepath.Join("testdata", goldenFile)
+
+	if *updateGolden {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatalf("failed to encode goldens: %v", err)
+		}
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatalf("failed to create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			t.Fatalf("failed to write goldens: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read goldens (run with -update to create them): %v", err)
+	}
+	var want map[string]map[string]goldenDecision
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("failed to decode goldens: %v", err)
+	}
+
+	for _, sc := range goldenScenarios() {
+		wantDecisions, ok := want[sc.name]
+		if !ok {
+			t.Errorf("scenario %s has no golden entry", sc.name)
+			continue
+		}
+		for name, g := range got[sc.name] {
+			w, ok := wantDecisions[name]
+			if !ok {
+				t.Errorf("%s/%s: missing golden entry, got %+v", sc.name, name, g)
+				continue
+			}
+			if w != g {
+				t.Errorf("%s/%s: decision changed: want %+v, got %+v", sc.name, name, w, g)
+			}
+		}
+	}
+	for name := range want {
+		if _, ok := got[name]; !ok {
+			t.Errorf("golden scenario %s no longer exists", name)
+		}
+	}
+}
+
+// TestGoldenDecisionsParallelism checks that parallel scoring reproduces the sequential goldens
+func TestGoldenDecisionsParallelism(t *testing.T) {
+	ctx := context.Background()
+	for _, sc := range goldenScenarios() {
+		sequential := scheduler.NewScheduler()
+		sequential.SetParallelism(1)
+		parallel := scheduler.NewScheduler()
+		parallel.SetParallelism(scheduler.DefaultScoringParallelism)
+
+		want := toGolden(sequential.ScheduleWorkload(ctx, sc.workload.DeepCopy(), sc.nodes))
+		got := toGolden(parallel.ScheduleWorkload(ctx, sc.workload.DeepCopy(), sc.nodes))
+		if want != got {
+			t.Errorf("%s: parallel scoring chose %+v, sequential chose %+v", sc.name, got, want)
+		}
+	}
+}
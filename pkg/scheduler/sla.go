@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// SetSLAModel sets the model that predicts whether inference workloads meet their SLA on a node
+func (s *Scheduler) SetSLAModel(model *optimizer.SLAModel) {
+	s.sla = model
+}
+
+// slaViolation returns why the workload is predicted to miss its SLA on the node even at
+// its most replicas, or "" when it meets it. The prediction is refined as latency is
+// observed, so it is checked with the running pods rather than cached with the shape.
+func (s *Scheduler) slaViolation(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+	if s.sla == nil || wo.Spec.SLA == nil {
+		return ""
+	}
+	if prediction := s.sla.Plan(wo, &node); !prediction.Met {
+		return prediction.Reason
+	}
+	return ""
+}
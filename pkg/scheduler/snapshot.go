@@ -0,0 +1,549 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// snapshotPod is the part of a pod the snapshot accounts for
+type snapshotPod struct {
+	nodeName  string
+	namespace string
+	name      string
+	labels    map[string]string
+	requests  corev1.ResourceList
+	// workload is the WorkloadOptimizer the pod belongs to, if any
+	workload string
+	// workloadType is the type of that workload, if any
+	workloadType string
+	// movable is false for DaemonSet, static and unowned pods
+	movable bool
+	// daemon is true for DaemonSet pods
+	daemon bool
+	// created is when the pod was created
+	created time.Time
+	// tolerations, nodeSelector and nodeAffinity restrict the nodes the pod may move to
+	tolerations  []corev1.Toleration
+	nodeSelector map[string]string
+	nodeAffinity *corev1.NodeSelector
+}
+
+// snapshotReservation is capacity held for a workload that has no pods yet
+type snapshotReservation struct {
+	nodeName  string
+	resources corev1.ResourceList
+}
+
+// ClusterSnapshot is a shared view of nodes, the resources pods request on each node and
+// scheduler reservations. Informer events keep it current, so scheduling reads fresh data
+// without listing nodes and pods on every reconcile.
+type ClusterSnapshot struct {
+	mu           sync.RWMutex
+	nodes        map[string]*corev1.Node
+	pods         map[types.UID]snapshotPod
+	requested    map[string]corev1.ResourceList
+	reservations map[string]snapshotReservation
+	synced       []toolscache.InformerSynced
+	// version changes whenever a node or pod does, invalidating indexes built from them
+	version uint64
+	// affinity indexes running pods by the topology domains of pod affinity terms
+	affinity affinityIndex
+}
+
+// NewClusterSnapshot creates an empty snapshot; call Watch to feed it
+func NewClusterSnapshot() *ClusterSnapshot {
+	return &ClusterSnapshot{
+		nodes:        make(map[string]*corev1.Node),
+		pods:         make(map[types.UID]snapshotPod),
+		requested:    make(map[string]corev1.ResourceList),
+		reservations: make(map[string]snapshotReservation),
+	}
+}
+
+// Watch subscribes the snapshot to node and pod informers
+func (s *ClusterSnapshot) Watch(ctx context.Context, informers cache.Informers) error {
+	nodeInformer, err := informers.GetInformer(ctx, &corev1.Node{})
+	if err != nil {
+		return fmt.Errorf("failed to get node informer: %w", err)
+	}
+	nodeRegistration, err := nodeInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { s.setNode(obj) },
+		UpdateFunc: func(_, obj interface{}) { s.setNode(obj) },
+		DeleteFunc: s.deleteNode,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch nodes: %w", err)
+	}
+
+	podInformer, err := informers.GetInformer(ctx, &corev1.Pod{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod informer: %w", err)
+	}
+	podRegistration, err := podInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { s.setPod(obj) },
+		UpdateFunc: func(_, obj interface{}) { s.setPod(obj) },
+		DeleteFunc: s.deletePod,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch pods: %w", err)
+	}
+
+	s.mu.Lock()
+	s.synced = []toolscache.InformerSynced{nodeRegistration.HasSynced, podRegistration.HasSynced}
+	s.mu.Unlock()
+	return nil
+}
+
+// HasSynced reports whether the snapshot has seen the initial node and pod lists
+func (s *ClusterSnapshot) HasSynced() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.synced) == 0 {
+		return false
+	}
+	for _, synced := range s.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// Nodes returns every node, ordered by name; the nodes are shared and must not be modified
+func (s *ClusterSnapshot) Nodes() []corev1.Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nodes := make([]corev1.Node, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		nodes = append(nodes, *node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes
+}
+
+// ReadyNodes returns the nodes whose Ready condition is true, ordered by name
+func (s *ClusterSnapshot) ReadyNodes() []corev1.Node {
+	var ready []corev1.Node
+	for _, node := range s.Nodes() {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				ready = append(ready, node)
+				break
+			}
+		}
+	}
+	return ready
+}
+
+// NodeUsage returns the ready nodes with the pods on each, ordered by node and pod name.
+// Pods of workloads exempt reports true for are not movable.
+func (s *ClusterSnapshot) NodeUsage(exempt func(namespace, workload string) bool) []optimizer.NodeUsage {
+	nodes := s.ReadyNodes()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	index := make(map[string]int, len(nodes))
+	usage := make([]optimizer.NodeUsage, len(nodes))
+	for i, node := range nodes {
+		index[node.Name] = i
+		usage[i] = optimizer.NodeUsage{Name: node.Name, Allocatable: node.Status.Allocatable}
+	}
+	for _, pod := range s.pods {
+		i, ok := index[pod.nodeName]
+		if !ok {
+			continue
+		}
+		movable := pod.movable && (pod.workload == "" || exempt == nil || !exempt(pod.namespace, pod.workload))
+		usage[i].Pods = append(usage[i].Pods, optimizer.PodUsage{
+			Namespace: pod.namespace,
+			Name:      pod.name,
+			Requests:  pod.requests,
+			Movable:   movable,
+			DaemonSet: pod.daemon,
+			Created:   pod.created,
+		})
+	}
+	for i := range usage {
+		sort.Slice(usage[i].Pods, func(a, b int) bool {
+			if usage[i].Pods[a].Namespace != usage[i].Pods[b].Namespace {
+				return usage[i].Pods[a].Namespace < usage[i].Pods[b].Namespace
+			}
+			return usage[i].Pods[a].Name < usage[i].Pods[b].Name
+		})
+	}
+	return usage
+}
+
+// PlacementFilter returns a filter admitting the nodes a pod of the snapshot may move to:
+// nodes that are not cordoned or draining, whose NoSchedule and NoExecute taints the pod
+// tolerates, and that match its nodeSelector and required node affinity. It captures the
+// snapshot as of the call.
+func (s *ClusterSnapshot) PlacementFilter() optimizer.PlacementFilter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nodes := make(map[string]*corev1.Node, len(s.nodes))
+	for name, node := range s.nodes {
+		nodes[name] = node
+	}
+	pods := make(map[types.NamespacedName]snapshotPod, len(s.pods))
+	for _, pod := range s.pods {
+		pods[types.NamespacedName{Namespace: pod.namespace, Name: pod.name}] = pod
+	}
+	return func(usage optimizer.PodUsage, nodeName string) bool {
+		node, ok := nodes[nodeName]
+		if !ok {
+			return false
+		}
+		pod, ok := pods[types.NamespacedName{Namespace: usage.Namespace, Name: usage.Name}]
+		return ok && podPlaceable(pod, node)
+	}
+}
+
+// podPlaceable applies the scheduler's node predicates to a running pod's own constraints
+func podPlaceable(pod snapshotPod, node *corev1.Node) bool {
+	if NodeUnschedulableReason(node) != "" {
+		return false
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectPreferNoSchedule && !tolerated(pod.tolerations, taint) {
+			return false
+		}
+	}
+	for key, value := range pod.nodeSelector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+	return pod.nodeAffinity == nil || nodeSelectorMatches(pod.nodeAffinity, node)
+}
+
+// nodeSelectorMatches reports whether the node matches any term of a required node affinity
+func nodeSelectorMatches(selector *corev1.NodeSelector, node *corev1.Node) bool {
+	for _, term := range selector.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorTermMatches reports whether the node satisfies every requirement of the term;
+// an empty term matches nothing
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, expression := range term.MatchExpressions {
+		requirement, err := labels.NewRequirement(expression.Key, nodeSelectorOperator(expression.Operator),
+			expression.Values)
+		if err != nil || !requirement.Matches(labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	// Only metadata.name may be matched as a field, with In or NotIn
+	for _, field := range term.MatchFields {
+		if field.Key != metav1.ObjectNameField {
+			return false
+		}
+		listed := slices.Contains(field.Values, node.Name)
+		switch field.Operator {
+		case corev1.NodeSelectorOpIn:
+			if !listed {
+				return false
+			}
+		case corev1.NodeSelectorOpNotIn:
+			if listed {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// nodeSelectorOperator maps a node selector operator to its label selector equivalent
+func nodeSelectorOperator(operator corev1.NodeSelectorOperator) selection.Operator {
+	switch operator {
+	case corev1.NodeSelectorOpIn:
+		return selection.In
+	case corev1.NodeSelectorOpNotIn:
+		return selection.NotIn
+	case corev1.NodeSelectorOpExists:
+		return selection.Exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return selection.DoesNotExist
+	case corev1.NodeSelectorOpGt:
+		return selection.GreaterThan
+	case corev1.NodeSelectorOpLt:
+		return selection.LessThan
+	default:
+		return selection.Operator(operator)
+	}
+}
+
+// Requested returns the resources requested on the node by running pods and held by
+// reservations of workloads other than exclude
+func (s *ClusterSnapshot) Requested(nodeName, exclude string) corev1.ResourceList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	total := corev1.ResourceList{}
+	addResources(total, s.requested[nodeName])
+	for workloadID, reservation := range s.reservations {
+		if workloadID != exclude && reservation.nodeName == nodeName {
+			addResources(total, reservation.resources)
+		}
+	}
+	return total
+}
+
+// Reserve holds resources on a node for a workload until Release
+func (s *ClusterSnapshot) Reserve(workloadID, nodeName string, resources corev1.ResourceList) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reservations[workloadID] = snapshotReservation{nodeName: nodeName, resources: resources.DeepCopy()}
+}
+
+// Reservation returns the node holding capacity for a workload, if any
+func (s *ClusterSnapshot) Reservation(workloadID string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	reservation, ok := s.reservations[workloadID]
+	return reservation.nodeName, ok
+}
+
+// Release drops a workload's reservation
+func (s *ClusterSnapshot) Release(workloadID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.reservations, workloadID)
+}
+
+// setNode records an added or updated node
+func (s *ClusterSnapshot) setNode(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes[node.Name] = node
+	s.version++
+}
+
+// deleteNode forgets a deleted node
+func (s *ClusterSnapshot) deleteNode(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.nodes, node.Name)
+	s.version++
+}
+
+// setPod accounts a pod's requests on its node; pending and finished pods hold nothing
+func (s *ClusterSnapshot) setPod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removePodLocked(pod.UID)
+	if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return
+	}
+
+	entry := snapshotPod{
+		nodeName:     pod.Spec.NodeName,
+		namespace:    pod.Namespace,
+		name:         pod.Name,
+		labels:       pod.Labels,
+		requests:     podRequests(pod),
+		workload:     pod.Annotations[WorkloadOptimizerAnnotation],
+		workloadType: pod.Annotations[WorkloadTypeAnnotation],
+		movable:      podMovable(pod),
+		daemon:       podDaemon(pod),
+		created:      pod.CreationTimestamp.Time,
+		tolerations:  pod.Spec.Tolerations,
+		nodeSelector: pod.Spec.NodeSelector,
+	}
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		entry.nodeAffinity = affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	}
+	s.pods[pod.UID] = entry
+	s.version++
+	requested, ok := s.requested[entry.nodeName]
+	if !ok {
+		requested = corev1.ResourceList{}
+		s.requested[entry.nodeName] = requested
+	}
+	addResources(requested, entry.requests)
+}
+
+// deletePod releases a deleted pod's requests
+func (s *ClusterSnapshot) deletePod(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removePodLocked(pod.UID)
+}
+
+// removePodLocked subtracts a known pod's requests from its node
+func (s *ClusterSnapshot) removePodLocked(uid types.UID) {
+	entry, ok := s.pods[uid]
+	if !ok {
+		return
+	}
+	delete(s.pods, uid)
+	s.version++
+
+	requested := s.requested[entry.nodeName]
+	for name, quantity := range entry.requests {
+		current := requested[name]
+		current.Sub(quantity)
+		requested[name] = current
+	}
+}
+
+// podMovable reports whether evicting the pod lets its controller recreate it elsewhere
+func podMovable(pod *corev1.Pod) bool {
+	if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+		return false
+	}
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && owner.Kind != "DaemonSet"
+}
+
+// podDaemon reports whether a DaemonSet controls the pod
+func podDaemon(pod *corev1.Pod) bool {
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && owner.Kind == "DaemonSet"
+}
+
+// podRequests returns the effective requests of a pod: the sum of its containers, or the
+// largest init container where that is higher, plus pod overhead and its GPU share
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	addResources(requests, pod.Spec.Overhead)
+	if index, share, ok := podGPUShare(pod); ok {
+		requests[gpuShareResource(index)] = *resource.NewMilliQuantity(share.milli, resource.DecimalSI)
+		if share.memory > 0 {
+			requests[gpuMemoryResource(index)] = *resource.NewQuantity(share.memory, resource.BinarySI)
+		}
+	}
+	return requests
+}
+
+// addResources adds every quantity in add to total
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		current, ok := total[name]
+		if !ok {
+			current = resource.Quantity{}
+		}
+		current.Add(quantity)
+		total[name] = current
+	}
+}
+
+// SetSnapshot makes scheduling account for pods and reservations already on each node
+func (s *Scheduler) SetSnapshot(snapshot *ClusterSnapshot) {
+	s.snapshot = snapshot
+}
+
+// Snapshot returns the cluster snapshot, or nil when scheduling uses allocatable capacity only
+func (s *Scheduler) Snapshot() *ClusterSnapshot {
+	return s.snapshot
+}
+
+// ReservePlacement holds one replica of the workload's requests on a node until
+// ReleasePlacement, so other placements leave room for it
+func (s *Scheduler) ReservePlacement(wo *kcloudv1alpha1.WorkloadOptimizer, nodeName string) error {
+	if s.snapshot == nil {
+		return nil
+	}
+	requests, err := workloadRequests(wo)
+	if err != nil {
+		return err
+	}
+	s.snapshot.Reserve(wo.Name, nodeName, requests)
+	return nil
+}
+
+// ReleasePlacement drops the capacity held for the workload
+func (s *Scheduler) ReleasePlacement(wo *kcloudv1alpha1.WorkloadOptimizer) {
+	if s.snapshot != nil {
+		s.snapshot.Release(wo.Name)
+	}
+}
+
+// availableResource returns the node's allocatable capacity left after requested
+func availableResource(node corev1.Node, requested corev1.ResourceList, name corev1.ResourceName) resource.Quantity {
+	quantity := node.Status.Allocatable[name].DeepCopy()
+	if used, ok := requested[name]; ok {
+		quantity.Sub(used)
+	}
+	return quantity
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// StandbyReservation keeps headroom free on a set of nodes for the workloads of the policy
+// that declares it, so latency-critical bursts can be placed at once instead of waiting for
+// the cluster to scale up
+type StandbyReservation struct {
+	Name string
+	// NodeSelector picks the nodes that keep the headroom; empty selects every node
+	NodeSelector map[string]string
+	// Headroom is kept free on each selected node, such as 2 nvidia.com/gpu
+	Headroom corev1.ResourceList
+}
+
+// Validate checks that the reservation is named and holds some headroom
+func (r StandbyReservation) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("standby reservation name cannot be empty")
+	}
+	if len(r.Headroom) == 0 {
+		return fmt.Errorf("standby reservation %s must hold some headroom", r.Name)
+	}
+	for name, quantity := range r.Headroom {
+		if quantity.Sign() <= 0 {
+			return fmt.Errorf("standby reservation %s headroom for %s must be positive", r.Name, name)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the reservation keeps headroom on the node
+func (r StandbyReservation) matches(node *corev1.Node) bool {
+	return labels.SelectorFromSet(r.NodeSelector).Matches(labels.Set(node.Labels))
+}
+
+// StandbyPolicy holds the standby reservations of a policy, whose headroom only the workloads
+// it covers may use
+type StandbyPolicy struct {
+	// Name names the policy
+	Name string
+	WorkloadScope
+	Reservations []StandbyReservation
+}
+
+// SetStandbyPolicies replaces the policies whose standby headroom workloads they do not cover
+// must leave free
+func (s *Scheduler) SetStandbyPolicies(policies []StandbyPolicy) error {
+	for _, policy := range policies {
+		for _, reservation := range policy.Reservations {
+			if err := reservation.Validate(); err != nil {
+				return fmt.Errorf("policy %s: %w", policy.Name, err)
+			}
+		}
+	}
+
+	s.energyMu.Lock()
+	defer s.energyMu.Unlock()
+	s.standby = policies
+	return nil
+}
+
+// standbyHeadroom returns the headroom held on the node by policies that do not cover the
+// workload. It is held on top of what pods already request, so covered workloads keep it
+// even when they run on the node.
+func (s *Scheduler) standbyHeadroom(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) corev1.ResourceList {
+	s.energyMu.RLock()
+	defer s.energyMu.RUnlock()
+
+	held := corev1.ResourceList{}
+	for i := range s.standby {
+		policy := &s.standby[i]
+		if policy.covers(wo, s.namespaceLabels[wo.Namespace]) {
+			continue
+		}
+		for _, reservation := range policy.Reservations {
+			if reservation.matches(node) {
+				addResources(held, reservation.Headroom)
+			}
+		}
+	}
+	return held
+}
+
+// SetStandbyPolicies replaces the policies whose standby reservations hold headroom that
+// workloads scheduled under any other policy cannot consume
+func (as *AdvancedScheduler) SetStandbyPolicies(policies ...*SchedulingPolicy) error {
+	standby := make(map[string][]StandbyReservation)
+	for _, policy := range policies {
+		if !policy.Enabled || len(policy.StandbyReservations) == 0 {
+			continue
+		}
+		for _, reservation := range policy.StandbyReservations {
+			if err := reservation.Validate(); err != nil {
+				return fmt.Errorf("policy %s: %w", policy.Name, err)
+			}
+		}
+		standby[policy.Name] = policy.StandbyReservations
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	as.standby = standby
+	return nil
+}
+
+// standbyHeadroom returns the headroom held on the node for policies other than the given
+// one. Capacity the owning policy's workloads have reserved on the node counts against its
+// headroom, so a burst placed there does not hold the same amount again.
+func (as *AdvancedScheduler) standbyHeadroom(node *corev1.Node, policy *SchedulingPolicy) corev1.ResourceList {
+	as.mutex.RLock()
+	defer as.mutex.RUnlock()
+
+	held := corev1.ResourceList{}
+	for owner, reservations := range as.standby {
+		if policy != nil && policy.Name == owner {
+			continue
+		}
+		headroom := corev1.ResourceList{}
+		for _, reservation := range reservations {
+			if reservation.matches(node) {
+				addResources(headroom, reservation.Headroom)
+			}
+		}
+		if len(headroom) == 0 {
+			continue
+		}
+
+		consumed := corev1.ResourceList{}
+		for _, reservation := range as.resourceReservations {
+			if reservation.Policy == owner && reservation.NodeName == node.Name {
+				addResources(consumed, reservation.resources())
+			}
+		}
+		for name, quantity := range headroom {
+			free := quantity.DeepCopy()
+			free.Sub(consumed[name])
+			if free.Sign() > 0 {
+				addResources(held, corev1.ResourceList{name: free})
+			}
+		}
+	}
+	return held
+}
+
+// StandbyPolicies returns the enabled policies that declare standby reservations, by name
+func (pm *PolicyManager) StandbyPolicies() []*SchedulingPolicy {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	var policies []*SchedulingPolicy
+	for _, policy := range pm.policies {
+		if policy.Enabled && len(policy.StandbyReservations) > 0 {
+			policies = append(policies, policy)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+	return policies
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"math"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultStickinessBonus is added to the score of the node a workload is already assigned to
+	DefaultStickinessBonus = 0.05
+	// DefaultStickinessHysteresis is how much a new node must outscore the assigned one to move the workload
+	DefaultStickinessHysteresis = 0.05
+)
+
+// SetStickiness configures how strongly re-optimization keeps workloads on their assigned
+// node; both values are score fractions in [0, 1) and zero disables the respective check
+func (s *Scheduler) SetStickiness(bonus, hysteresis float64) error {
+	if bonus < 0 || bonus >= 1 {
+		return fmt.Errorf("stickiness bonus must be in [0, 1)")
+	}
+	if hysteresis < 0 || hysteresis >= 1 {
+		return fmt.Errorf("stickiness hysteresis must be in [0, 1)")
+	}
+	s.stickinessBonus = bonus
+	s.stickinessHysteresis = hysteresis
+	return nil
+}
+
+// assignedNode returns the node the workload currently runs on, if any
+func assignedNode(wo *kcloudv1alpha1.WorkloadOptimizer) string {
+	if wo.Status.AssignedNode == nil {
+		return ""
+	}
+	return *wo.Status.AssignedNode
+}
+
+// applyStickinessBonus raises the score of the workload's assigned node and returns its decision
+func (s *Scheduler) applyStickinessBonus(wo *kcloudv1alpha1.WorkloadOptimizer, decisions []*SchedulingDecision) *SchedulingDecision {
+	current := assignedNode(wo)
+	if current == "" {
+		return nil
+	}
+
+	for _, decision := range decisions {
+		if decision == nil || decision.SelectedNode != current {
+			continue
+		}
+		if s.stickinessBonus > 0 {
+			boosted := math.Min(1, decision.Score+s.stickinessBonus)
+			if decision.Contributions == nil {
+				decision.Contributions = map[string]float64{}
+			}
+			decision.Contributions["stickiness"] = boosted - decision.Score
+			decision.Score = boosted
+		}
+		return decision
+	}
+	return nil
+}
+
+// keepAssignedNode reports whether the assigned node should be kept over the best candidate,
+// because the candidate does not beat it by more than the hysteresis threshold
+func (s *Scheduler) keepAssignedNode(best, current *SchedulingDecision) bool {
+	if best == nil || current == nil || best == current {
+		return false
+	}
+	return best.Score-current.Score <= s.stickinessHysteresis
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// preferNoSchedulePenalty is taken off the placement score for each untolerated PreferNoSchedule taint
+const preferNoSchedulePenalty = 0.25
+
+// workloadTolerations returns the tolerations declared in the placement policy
+func workloadTolerations(wo *kcloudv1alpha1.WorkloadOptimizer) []corev1.Toleration {
+	if wo.Spec.PlacementPolicy == nil {
+		return nil
+	}
+	return wo.Spec.PlacementPolicy.Tolerations
+}
+
+// tolerated reports whether any toleration tolerates the taint
+func tolerated(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// untoleratedTaint returns the first NoSchedule or NoExecute taint of the node that the
+// workload does not tolerate
+func untoleratedTaint(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) (*corev1.Taint, bool) {
+	tolerations := workloadTolerations(wo)
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !tolerated(tolerations, taint) {
+			return taint, true
+		}
+	}
+	return nil, false
+}
+
+// untoleratedPreferNoSchedule counts the node's PreferNoSchedule taints the workload does not tolerate
+func untoleratedPreferNoSchedule(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) int {
+	tolerations := workloadTolerations(wo)
+	count := 0
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule && !tolerated(tolerations, taint) {
+			count++
+		}
+	}
+	return count
+}
+
+// taintReason describes an untolerated taint for filter results
+func taintReason(taint *corev1.Taint) string {
+	return fmt.Sprintf("node taint %s=%s:%s is not tolerated", taint.Key, taint.Value, taint.Effect)
+}
+
+// tolerationsShape renders tolerations in a stable order for workload shapes
+func tolerationsShape(tolerations []corev1.Toleration) string {
+	parts := make([]string, 0, len(tolerations))
+	for _, t := range tolerations {
+		parts = append(parts, fmt.Sprintf("%s%s%s:%s", t.Key, t.Operator, t.Value, t.Effect))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// ThermalScoreWeight is the share of the score heavy workloads give to thermal headroom and,
+// for training, to spreading across racks
+const ThermalScoreWeight = 0.2
+
+// SetThermalScheduling keeps heavy workloads off nodes nearing their thermal limits and
+// spreads training workloads across racks. temperatures may be nil to only spread racks.
+func (s *Scheduler) SetThermalScheduling(temperatures optimizer.NodeTemperatureSource, limits optimizer.ThermalLimits) {
+	s.thermal = true
+	s.temperatures = temperatures
+	s.thermalLimits = limits
+}
+
+// thermallyHeavy reports whether the workload generates enough sustained heat to be kept
+// off hot nodes: training and batch workloads, and anything using GPUs
+func thermallyHeavy(wo *kcloudv1alpha1.WorkloadOptimizer) bool {
+	return wo.Spec.WorkloadType == "training" || wo.Spec.WorkloadType == "batch" || wo.Spec.Resources.GPU > 0
+}
+
+// thermalHeadroom returns how far the node is below its thermal limit, and whether its
+// temperature is measured
+func (s *Scheduler) thermalHeadroom(node corev1.Node) (float64, bool) {
+	if s.temperatures == nil {
+		return 0, false
+	}
+	temperature, ok := s.temperatures.NodeTemperature(node.Name)
+	if !ok {
+		return 0, false
+	}
+	headroom := s.thermalLimits.Headroom(temperature)
+	return headroom, !math.IsInf(headroom, 1)
+}
+
+// thermalViolation returns why a heavy workload cannot go to the node because it is within
+// the margin of its thermal limit, or "" when it can. Temperatures change by the minute, so
+// it is checked with the running pods rather than cached with the shape.
+func (s *Scheduler) thermalViolation(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) string {
+	if !s.thermal || !thermallyHeavy(wo) {
+		return ""
+	}
+	headroom, ok := s.thermalHeadroom(node)
+	if !ok || headroom >= s.thermalLimits.Margin {
+		return ""
+	}
+	return fmt.Sprintf("node is %.0f°C from its thermal limit", headroom)
+}
+
+// thermalFit rates the node for a heavy workload in the range 0.0-1.0, averaging its thermal
+// headroom, full from three margins below the limit, and for training how few training pods
+// its rack runs relative to the busiest rack. ok is false when neither applies.
+func (s *Scheduler) thermalFit(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) (fit float64, ok bool) {
+	if !s.thermal || !thermallyHeavy(wo) {
+		return 0, false
+	}
+
+	var total float64
+	var parts int
+	if headroom, measured := s.thermalHeadroom(node); measured && s.thermalLimits.Margin > 0 {
+		total += math.Max(0, math.Min(1, (headroom-s.thermalLimits.Margin)/(2*s.thermalLimits.Margin)))
+		parts++
+	}
+	if rack := optimizer.NodeRack(&node); rack != "" && wo.Spec.WorkloadType == "training" && s.snapshot != nil {
+		counts := s.snapshot.trainingPodsByRack()
+		busiest := 0
+		for _, count := range counts {
+			busiest = max(busiest, count)
+		}
+		if busiest > 0 {
+			total += 1 - float64(counts[rack])/float64(busiest)
+		} else {
+			total++
+		}
+		parts++
+	}
+	if parts == 0 {
+		return 0, false
+	}
+	return total / float64(parts), true
+}
+
+// trainingPodsByRack counts the running pods of training workloads in each rack
+func (s *ClusterSnapshot) trainingPodsByRack() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, pod := range s.pods {
+		if pod.workloadType != "training" {
+			continue
+		}
+		node, ok := s.nodes[pod.nodeName]
+		if !ok {
+			continue
+		}
+		if rack := optimizer.NodeRack(node); rack != "" {
+			counts[rack]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+const (
+	// CloudProviderLabel overrides the provider inferred from a node's provider ID
+	CloudProviderLabel = "kcloud.io/cloud-provider"
+
+	// DefaultTransferProvider prices nodes whose provider has no entry in the table
+	DefaultTransferProvider = "default"
+
+	zoneLabel   = "topology.kubernetes.io/zone"
+	regionLabel = "topology.kubernetes.io/region"
+)
+
+// TransferRates is what a provider charges per GB moved between zones and regions
+type TransferRates struct {
+	// CrossZonePerGB is the USD per GB between zones of one region, both directions included
+	CrossZonePerGB float64 `json:"crossZonePerGB"`
+	// CrossRegionPerGB is the USD per GB between regions
+	CrossRegionPerGB float64 `json:"crossRegionPerGB"`
+}
+
+// TransferPricing maps cloud providers to their data transfer rates
+type TransferPricing map[string]TransferRates
+
+// DefaultTransferPricing returns list prices of the major providers
+func DefaultTransferPricing() TransferPricing {
+	return TransferPricing{
+		"aws":                   {CrossZonePerGB: 0.02, CrossRegionPerGB: 0.02},
+		"gcp":                   {CrossZonePerGB: 0.01, CrossRegionPerGB: 0.02},
+		"azure":                 {CrossZonePerGB: 0, CrossRegionPerGB: 0.02},
+		DefaultTransferProvider: {CrossZonePerGB: 0.01, CrossRegionPerGB: 0.02},
+	}
+}
+
+// LoadTransferPricing reads a YAML or JSON table of transfer rates keyed by provider.
+// Providers missing from the file keep their default rates.
+func LoadTransferPricing(path string) (TransferPricing, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transfer pricing: %w", err)
+	}
+
+	var overrides TransferPricing
+	if err := yaml.UnmarshalStrict(raw, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse transfer pricing: %w", err)
+	}
+	pricing := DefaultTransferPricing()
+	for provider, rates := range overrides {
+		pricing[provider] = rates
+	}
+	return pricing, pricing.Validate()
+}
+
+// Validate checks that no rate is negative
+func (p TransferPricing) Validate() error {
+	for provider, rates := range p {
+		if rates.CrossZonePerGB < 0 || rates.CrossRegionPerGB < 0 {
+			return fmt.Errorf("transfer rates of provider %s must not be negative", provider)
+		}
+	}
+	return nil
+}
+
+// nodeProvider returns the cloud provider of a node from its label or provider ID
+func nodeProvider(node *corev1.Node) string {
+	if provider := node.Labels[CloudProviderLabel]; provider != "" {
+		return provider
+	}
+	scheme, _, found := strings.Cut(node.Spec.ProviderID, "://")
+	if !found {
+		return DefaultTransferProvider
+	}
+	switch scheme {
+	case "gce":
+		return "gcp"
+	default:
+		return scheme
+	}
+}
+
+// rates returns the transfer rates of a provider, falling back to the default entry
+func (p TransferPricing) rates(provider string) TransferRates {
+	if rates, ok := p[provider]; ok {
+		return rates
+	}
+	return p[DefaultTransferProvider]
+}
+
+// EgressCost estimates the hourly cost in USD of the workload's data transfer to and from
+// its endpoints when placed on the node. Nodes without a zone label are not charged.
+func (p TransferPricing) EgressCost(wo *kcloudv1alpha1.WorkloadOptimizer, node *corev1.Node) float64 {
+	if wo.Spec.PlacementPolicy == nil || len(wo.Spec.PlacementPolicy.DataEndpoints) == 0 {
+		return 0
+	}
+	zone := node.Labels[zoneLabel]
+	if zone == "" {
+		return 0
+	}
+	region := node.Labels[regionLabel]
+	rates := p.rates(nodeProvider(node))
+
+	cost := 0.0
+	for _, endpoint := range wo.Spec.PlacementPolicy.DataEndpoints {
+		switch {
+		case endpoint.Zone == zone:
+		case endpoint.Region != "" && region != "" && endpoint.Region != region:
+			cost += endpoint.TransferGBPerHour * rates.CrossRegionPerGB
+		default:
+			cost += endpoint.TransferGBPerHour * rates.CrossZonePerGB
+		}
+	}
+	return cost
+}
+
+// SetTransferPricing replaces the transfer rates charged for data moved between zones and
+// regions when placing workloads with data endpoints
+func (s *Scheduler) SetTransferPricing(pricing TransferPricing) error {
+	if err := pricing.Validate(); err != nil {
+		return err
+	}
+	s.transferPricing = pricing
+	return nil
+}
+
+// transferCostPenalty returns how much of the cost score a placement loses for the transfer
+// to its data endpoints: half the score times the transfer's share of the estimated cost
+func (s *Scheduler) transferCostPenalty(wo *kcloudv1alpha1.WorkloadOptimizer, node corev1.Node) float64 {
+	egress := s.transferPricing.EgressCost(wo, &node)
+	if egress <= 0 {
+		return 0
+	}
+	return 0.5 * egress / s.estimateNodeCost(wo, node)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// WorkloadOptimizerBuilder builds WorkloadOptimizer objects for tests
+type WorkloadOptimizerBuilder struct {
+	wo *kcloudv1alpha1.WorkloadOptimizer
+}
+
+// NewWorkloadOptimizer starts a training workload requesting 1 CPU and 1Gi of memory
+func NewWorkloadOptimizer(name, namespace string) *WorkloadOptimizerBuilder {
+	return &WorkloadOptimizerBuilder{wo: &kcloudv1alpha1.WorkloadOptimizer{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: kcloudv1alpha1.WorkloadOptimizerSpec{
+			WorkloadType: "training",
+			Priority:     5,
+			Resources:    kcloudv1alpha1.ResourceRequirements{CPU: "1", Memory: "1Gi"},
+		},
+	}}
+}
+
+// WithWorkloadType sets the workload type
+func (b *WorkloadOptimizerBuilder) WithWorkloadType(workloadType string) *WorkloadOptimizerBuilder {
+	b.wo.Spec.WorkloadType = workloadType
+	return b
+}
+
+// WithPriority sets the workload priority
+func (b *WorkloadOptimizerBuilder) WithPriority(priority int32) *WorkloadOptimizerBuilder {
+	b.wo.Spec.Priority = priority
+	return b
+}
+
+// WithResources sets the CPU and memory request
+func (b *WorkloadOptimizerBuilder) WithResources(cpu, memory string) *WorkloadOptimizerBuilder {
+	b.wo.Spec.Resources.CPU = cpu
+	b.wo.Spec.Resources.Memory = memory
+	return b
+}
+
+// WithGPU sets the GPU count
+func (b *WorkloadOptimizerBuilder) WithGPU(count int32) *WorkloadOptimizerBuilder {
+	b.wo.Spec.Resources.GPU = count
+	return b
+}
+
+// WithNPU sets the NPU count
+func (b *WorkloadOptimizerBuilder) WithNPU(count int32) *WorkloadOptimizerBuilder {
+	b.wo.Spec.Resources.NPU = count
+	return b
+}
+
+// WithMaxCost sets the hourly cost ceiling
+func (b *WorkloadOptimizerBuilder) WithMaxCost(maxCostPerHour float64) *WorkloadOptimizerBuilder {
+	if b.wo.Spec.CostConstraints == nil {
+		b.wo.Spec.CostConstraints = &kcloudv1alpha1.CostConstraints{}
+	}
+	b.wo.Spec.CostConstraints.MaxCostPerHour = maxCostPerHour
+	return b
+}
+
+// WithMaxPower sets the power ceiling in Watts
+func (b *WorkloadOptimizerBuilder) WithMaxPower(maxPowerUsage float64) *WorkloadOptimizerBuilder {
+	if b.wo.Spec.PowerConstraints == nil {
+		b.wo.Spec.PowerConstraints = &kcloudv1alpha1.PowerConstraints{}
+	}
+	b.wo.Spec.PowerConstraints.MaxPowerUsage = maxPowerUsage
+	return b
+}
+
+// WithNodeSelector adds a node selector term to the placement policy
+func (b *WorkloadOptimizerBuilder) WithNodeSelector(key, value string) *WorkloadOptimizerBuilder {
+	if b.wo.Spec.PlacementPolicy == nil {
+		b.wo.Spec.PlacementPolicy = &kcloudv1alpha1.PlacementPolicy{}
+	}
+	if b.wo.Spec.PlacementPolicy.NodeSelector == nil {
+		b.wo.Spec.PlacementPolicy.NodeSelector = map[string]string{}
+	}
+	b.wo.Spec.PlacementPolicy.NodeSelector[key] = value
+	return b
+}
+
+// Build returns a copy of the built workload
+func (b *WorkloadOptimizerBuilder) Build() *kcloudv1alpha1.WorkloadOptimizer {
+	return b.wo.DeepCopy()
+}
+
+// NodeBuilder builds Node objects for tests
+type NodeBuilder struct {
+	node *corev1.Node
+}
+
+// NewNode starts a ready node with 8 CPUs and 32Gi of memory allocatable
+func NewNode(name string) *NodeBuilder {
+	return &NodeBuilder{node: &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}}
+}
+
+// WithLabel sets a node label
+func (b *NodeBuilder) WithLabel(key, value string) *NodeBuilder {
+	b.node.Labels[key] = value
+	return b
+}
+
+// WithAllocatable sets the allocatable CPU and memory
+func (b *NodeBuilder) WithAllocatable(cpu, memory string) *NodeBuilder {
+	b.node.Status.Allocatable[corev1.ResourceCPU] = resource.MustParse(cpu)
+	b.node.Status.Allocatable[corev1.ResourceMemory] = resource.MustParse(memory)
+	return b
+}
+
+// WithGPU sets the allocatable GPU count
+func (b *NodeBuilder) WithGPU(count int64) *NodeBuilder {
+	b.node.Status.Allocatable["nvidia.com/gpu"] = *resource.NewQuantity(count, resource.DecimalSI)
+	return b
+}
+
+// WithNPU sets the allocatable NPU count
+func (b *NodeBuilder) WithNPU(count int64) *NodeBuilder {
+	b.node.Status.Allocatable["npu.com/npu"] = *resource.NewQuantity(count, resource.DecimalSI)
+	return b
+}
+
+// NotReady marks the node as not ready
+func (b *NodeBuilder) NotReady() *NodeBuilder {
+	b.node.Status.Conditions[0].Status = corev1.ConditionFalse
+	return b
+}
+
+// Build returns a copy of the built node
+func (b *NodeBuilder) Build() corev1.Node {
+	return *b.node.DeepCopy()
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+var _ scheduler.HistoryStore = &FakeHistoryStore{}
+
+// FakeHistoryStore is an unbounded HistoryStore that can be seeded with events
+type FakeHistoryStore struct {
+	mu     sync.RWMutex
+	events []scheduler.SchedulingEvent
+}
+
+// NewFakeHistoryStore creates an empty history store
+func NewFakeHistoryStore() *FakeHistoryStore {
+	return &FakeHistoryStore{}
+}
+
+// WithEvents seeds the store with events, oldest first
+func (f *FakeHistoryStore) WithEvents(events ...scheduler.SchedulingEvent) *FakeHistoryStore {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, events...)
+	return f
+}
+
+// WithNodeUsedAt seeds a successful placement on the node at t
+func (f *FakeHistoryStore) WithNodeUsedAt(nodeName string, t time.Time) *FakeHistoryStore {
+	return f.WithEvents(scheduler.SchedulingEvent{Timestamp: t, NodeName: nodeName, Success: true})
+}
+
+// Record stores a scheduling event
+func (f *FakeHistoryStore) Record(event scheduler.SchedulingEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+}
+
+// LastUsed returns the time of the most recent event on the node
+func (f *FakeHistoryStore) LastUsed(nodeName string) (time.Time, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for i := len(f.events) - 1; i >= 0; i-- {
+		if f.events[i].NodeName == nodeName {
+			return f.events[i].Timestamp, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Recent returns the newest events; a non-positive limit returns all of them
+func (f *FakeHistoryStore) Recent(limit int) []scheduler.SchedulingEvent {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if limit <= 0 || limit > len(f.events) {
+		limit = len(f.events)
+	}
+	return append([]scheduler.SchedulingEvent(nil), f.events[len(f.events)-limit:]...)
+}
+
+// Query returns the events matching the query, oldest first
+func (f *FakeHistoryStore) Query(query scheduler.HistoryQuery) []scheduler.SchedulingEvent {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var matches []scheduler.SchedulingEvent
+	for _, event := range f.events {
+		if query.Matches(event) {
+			matches = append(matches, event)
+		}
+	}
+	if query.Limit > 0 && len(matches) > query.Limit {
+		matches = matches[len(matches)-query.Limit:]
+	}
+	return matches
+}
+
+// Events returns every recorded event, oldest first
+func (f *FakeHistoryStore) Events() []scheduler.SchedulingEvent {
+	return f.Recent(0)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"sync"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+var (
+	_ optimizer.PricingProvider = &FakePricingProvider{}
+	_ optimizer.PowerEstimator  = &FakePowerEstimator{}
+)
+
+// ResourceRequest records the arguments a fake was called with
+type ResourceRequest struct {
+	CPUCores float64
+	MemoryGB float64
+	GPUCount int32
+	NPUCount int32
+}
+
+// FakePricingProvider prices requests from fixed per-unit rates and records every call
+type FakePricingProvider struct {
+	mu          sync.Mutex
+	cpuPrice    float64
+	memoryPrice float64
+	gpuPrice    float64
+	npuPrice    float64
+	basePrice   float64
+	fixed       *float64
+	calls       []ResourceRequest
+}
+
+// NewFakePricingProvider creates a pricing provider that charges nothing until configured
+func NewFakePricingProvider() *FakePricingProvider {
+	return &FakePricingProvider{}
+}
+
+// WithCPUPrice sets the hourly price per CPU core
+func (f *FakePricingProvider) WithCPUPrice(price float64) *FakePricingProvider {
+	f.cpuPrice = price
+	return f
+}
+
+// WithMemoryPrice sets the hourly price per GB of memory
+func (f *FakePricingProvider) WithMemoryPrice(price float64) *FakePricingProvider {
+	f.memoryPrice = price
+	return f
+}
+
+// WithGPUPrice sets the hourly price per GPU
+func (f *FakePricingProvider) WithGPUPrice(price float64) *FakePricingProvider {
+	f.gpuPrice = price
+	return f
+}
+
+// WithNPUPrice sets the hourly price per NPU
+func (f *FakePricingProvider) WithNPUPrice(price float64) *FakePricingProvider {
+	f.npuPrice = price
+	return f
+}
+
+// WithBasePrice sets the hourly price charged for every request
+func (f *FakePricingProvider) WithBasePrice(price float64) *FakePricingProvider {
+	f.basePrice = price
+	return f
+}
+
+// WithFixedCost makes every request cost exactly cost, ignoring the rates
+func (f *FakePricingProvider) WithFixedCost(cost float64) *FakePricingProvider {
+	f.fixed = &cost
+	return f
+}
+
+// CalculateCost returns the configured hourly cost of the request
+func (f *FakePricingProvider) CalculateCost(cpuCores, memoryGB float64, gpuCount, npuCount int32) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, ResourceRequest{CPUCores: cpuCores, MemoryGB: memoryGB, GPUCount: gpuCount, NPUCount: npuCount})
+	if f.fixed != nil {
+		return *f.fixed
+	}
+	return f.basePrice + cpuCores*f.cpuPrice + memoryGB*f.memoryPrice +
+		float64(gpuCount)*f.gpuPrice + float64(npuCount)*f.npuPrice
+}
+
+// Calls returns the requests priced so far
+func (f *FakePricingProvider) Calls() []ResourceRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ResourceRequest(nil), f.calls...)
+}
+
+// FakePowerEstimator estimates power from fixed per-unit draws and records every call
+type FakePowerEstimator struct {
+	mu          sync.Mutex
+	cpuWatts    float64
+	memoryWatts float64
+	gpuWatts    float64
+	npuWatts    float64
+	baseWatts   float64
+	fixed       *float64
+	calls       []ResourceRequest
+}
+
+// NewFakePowerEstimator creates a power estimator that reports zero until configured
+func NewFakePowerEstimator() *FakePowerEstimator {
+	return &FakePowerEstimator{}
+}
+
+// WithCPUWatts sets the draw per CPU core
+func (f *FakePowerEstimator) WithCPUWatts(watts float64) *FakePowerEstimator {
+	f.cpuWatts = watts
+	return f
+}
+
+// WithMemoryWatts sets the draw per GB of memory
+func (f *FakePowerEstimator) WithMemoryWatts(watts float64) *FakePowerEstimator {
+	f.memoryWatts = watts
+	return f
+}
+
+// WithGPUWatts sets the draw per GPU
+func (f *FakePowerEstimator) WithGPUWatts(watts float64) *FakePowerEstimator {
+	f.gpuWatts = watts
+	return f
+}
+
+// WithNPUWatts sets the draw per NPU
+func (f *FakePowerEstimator) WithNPUWatts(watts float64) *FakePowerEstimator {
+	f.npuWatts = watts
+	return f
+}
+
+// WithBaseWatts sets the draw added to every request
+func (f *FakePowerEstimator) WithBaseWatts(watts float64) *FakePowerEstimator {
+	f.baseWatts = watts
+	return f
+}
+
+// WithFixedPower makes every request draw exactly watts, ignoring the rates
+func (f *FakePowerEstimator) WithFixedPower(watts float64) *FakePowerEstimator {
+	f.fixed = &watts
+	return f
+}
+
+// CalculatePower returns the configured power draw of the request
+func (f *FakePowerEstimator) CalculatePower(cpuCores, memoryGB float64, gpuCount, npuCount int32) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, ResourceRequest{CPUCores: cpuCores, MemoryGB: memoryGB, GPUCount: gpuCount, NPUCount: npuCount})
+	if f.fixed != nil {
+		return *f.fixed
+	}
+	return f.baseWatts + cpuCores*f.cpuWatts + memoryGB*f.memoryWatts +
+		float64(gpuCount)*f.gpuWatts + float64(npuCount)*f.npuWatts
+}
+
+// Calls returns the requests estimated so far
+func (f *FakePowerEstimator) Calls() []ResourceRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ResourceRequest(nil), f.calls...)
+}
+
+// NewEngine creates an optimizer engine backed by the given fakes
+func NewEngine(pricing optimizer.PricingProvider, power optimizer.PowerEstimator) *optimizer.Engine {
+	return &optimizer.Engine{
+		CostCalculator:  pricing,
+		PowerCalculator: power,
+	}
+}
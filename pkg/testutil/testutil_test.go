@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
+)
+
+func TestEngineWithFakes(t *testing.T) {
+	pricing := NewFakePricingProvider().WithCPUPrice(1).WithGPUPrice(2)
+	power := NewFakePowerEstimator().WithFixedPower(150)
+	engine := NewEngine(pricing, power)
+
+	wo := NewWorkloadOptimizer("train", "default").WithResources("2", "4Gi").WithGPU(1).WithMaxCost(10).Build()
+	result := engine.Optimize(context.Background(), &optimizer.WorkloadState{WorkloadOptimizer: wo})
+
+	if result.EstimatedCost != 4 {
+		t.Errorf("expected cost 4, got %v", result.EstimatedCost)
+	}
+	if result.EstimatedPower != 150 {
+		t.Errorf("expected power 150, got %v", result.EstimatedPower)
+	}
+	if calls := pricing.Calls(); len(calls) != 1 || calls[0].GPUCount != 1 {
+		t.Errorf("unexpected pricing calls %+v", calls)
+	}
+}
+
+func TestRoundRobinWithFakeHistory(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+	history := NewFakeHistoryStore().
+		WithNodeUsedAt("node-a", now.Add(-time.Minute)).
+		WithNodeUsedAt("node-b", now.Add(-time.Hour))
+
+	as := scheduler.NewAdvancedScheduler()
+	as.SetClock(clock)
+	as.SetHistoryStore(history)
+
+	nodes := []corev1.Node{NewNode("node-a").Build(), NewNode("node-b").Build()}
+	policy := &scheduler.SchedulingPolicy{Name: "rr", Algorithm: scheduler.AlgorithmRoundRobin, Enabled: true}
+	decision, err := as.ScheduleWithPolicy(context.Background(), NewWorkloadOptimizer("web", "default").Build(), nodes, policy)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if decision.SelectedNode != "node-b" {
+		t.Errorf("expected least recently used node-b, got %s", decision.SelectedNode)
+	}
+
+	events := history.Events()
+	if last := events[len(events)-1]; !last.Timestamp.Equal(now) || last.NodeName != "node-b" {
+		t.Errorf("expected event on node-b at fake time, got %+v", last)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PodMutatingWebhookName is the name of the generated pod MutatingWebhookConfiguration
+	PodMutatingWebhookName = "kcloud-pod-mutator"
+
+	// OptimizationDisabledLabel lets individual pods opt out before reaching the webhook
+	OptimizationDisabledLabel = "kcloud.io/optimization-disabled"
+)
+
+// AdmissionScope limits which requests reach the pod mutating webhook, so that system
+// namespaces and unrelated workloads never enter the admission path
+type AdmissionScope struct {
+	// NamespaceLabel and NamespaceLabelValue select the namespaces that opted in;
+	// an empty label admits every namespace that is not excluded
+	NamespaceLabel      string
+	NamespaceLabelValue string
+	// ExcludedNamespaces never reach the webhook, even when labeled
+	ExcludedNamespaces []string
+	// AcceleratorPodsOnly restricts the webhook to pods requesting GPUs, NPUs or one of
+	// AcceleratorResources
+	AcceleratorPodsOnly bool
+	// AcceleratorResources are the extended resources of registered accelerator types
+	AcceleratorResources []string
+	// FailOpen admits pods unchanged when the webhook is unavailable
+	FailOpen bool
+}
+
+// DefaultAdmissionScope returns a scope admitting opted-in namespaces outside the control plane
+func DefaultAdmissionScope() AdmissionScope {
+	return AdmissionScope{
+		NamespaceLabel:      "kcloud.io/optimization",
+		NamespaceLabelValue: "enabled",
+		ExcludedNamespaces:  []string{"kube-system", "kube-public", "kube-node-lease"},
+		AcceleratorPodsOnly: true,
+		FailOpen:            true,
+	}
+}
+
+// ParseNamespaceLabel splits a "key=value" namespace selector flag
+func (s *AdmissionScope) ParseNamespaceLabel(selector string) error {
+	if selector == "" {
+		s.NamespaceLabel, s.NamespaceLabelValue = "", ""
+		return nil
+	}
+	key, value, found := strings.Cut(selector, "=")
+	if !found || key == "" {
+		return fmt.Errorf("invalid namespace label %q, expected key=value", selector)
+	}
+	s.NamespaceLabel, s.NamespaceLabelValue = key, value
+	return nil
+}
+
+// NamespaceSelector returns the selector for namespaces admitted by the scope
+func (s AdmissionScope) NamespaceSelector() *metav1.LabelSelector {
+	selector := &metav1.LabelSelector{}
+	if s.NamespaceLabel != "" {
+		selector.MatchLabels = map[string]string{s.NamespaceLabel: s.NamespaceLabelValue}
+	}
+	if len(s.ExcludedNamespaces) > 0 {
+		selector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+			Key:      "kubernetes.io/metadata.name",
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   s.ExcludedNamespaces,
+		}}
+	}
+	return selector
+}
+
+// ObjectSelector returns the selector skipping pods labeled as opted out
+func (s AdmissionScope) ObjectSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      OptimizationDisabledLabel,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   []string{"true"},
+		}},
+	}
+}
+
+// MatchConditions returns the CEL conditions evaluated by the API server before calling the webhook
+func (s AdmissionScope) MatchConditions() []admissionregistrationv1.MatchCondition {
+	if !s.AcceleratorPodsOnly {
+		return nil
+	}
+
+	requests := func(resource string) string {
+		return fmt.Sprintf("object.spec.containers.exists(c, has(c.resources.limits) && '%s' in c.resources.limits)", resource)
+	}
+	conditions := []string{requests("nvidia.com/gpu"), requests("npu.com/npu")}
+	for _, name := range s.AcceleratorResources {
+		conditions = append(conditions, requests(name))
+	}
+	return []admissionregistrationv1.MatchCondition{{
+		Name:       "accelerator-pods",
+		Expression: strings.Join(conditions, " || "),
+	}}
+}
+
+// PodMutatingWebhookConfiguration builds the pod MutatingWebhookConfiguration for the
+// webhook service, restricted to the scope
+func (s AdmissionScope) PodMutatingWebhookConfiguration(serviceName, serviceNamespace string,
+	caBundle []byte) *admissionregistrationv1.MutatingWebhookConfiguration {
+	path := "/mutate-v1-pod"
+	failurePolicy := admissionregistrationv1.Fail
+	if s.FailOpen {
+		failurePolicy = admissionregistrationv1.Ignore
+	}
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	reinvocation := admissionregistrationv1.NeverReinvocationPolicy
+	timeout := int32(5)
+
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+			Kind:       "MutatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: PodMutatingWebhookName,
+			Labels: map[string]string{
+				"app":                        "kcloud-operator",
+				"kcloud.io/component":        "webhook",
+				"kcloud.io/webhook-type":     "mutating",
+				"kcloud.io/webhook-resource": "pod",
+			},
+		},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name: "mpod.kcloud.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{
+					Name:      serviceName,
+					Namespace: serviceNamespace,
+					Path:      &path,
+				},
+				CABundle: caBundle,
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+				},
+			}},
+			NamespaceSelector:       s.NamespaceSelector(),
+			ObjectSelector:          s.ObjectSelector(),
+			MatchConditions:         s.MatchConditions(),
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			ReinvocationPolicy:      &reinvocation,
+			TimeoutSeconds:          &timeout,
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+}
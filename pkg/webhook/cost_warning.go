@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+)
+
+// costWarnings returns the admission warning giving the pod's estimated cost per hour when a
+// CostPolicy covers it, along with how much of the budget the policy's namespaces have used.
+// Pods of no WorkloadOptimizer are covered by policies that select no workloads. Failures
+// are logged and leave the warning out; they never fail admission.
+func (m *PodMutator) costWarnings(ctx context.Context, pod *corev1.Pod, wo *kcloudv1alpha1.WorkloadOptimizer) []string {
+	if m.Pricing == nil {
+		return nil
+	}
+	policies, err := optimizer.CoveringCostPolicies(ctx, m.Client, pod.Namespace, wo)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to find cost policies covering pod", "pod", pod.Name)
+		return nil
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+
+	cpu, memory, gpus, npus := optimizer.PodResources(pod)
+	data := map[string]any{"Cost": m.Currency.FromUSD(m.Pricing.CalculateCost(cpu, memory, gpus, npus))}
+	id := messages.AdmissionCostEstimate
+	locale := policies[0].Annotations[messages.LocaleAnnotation]
+	for _, policy := range policies {
+		if policy.Status.BudgetUtilization != nil {
+			id = messages.AdmissionCostEstimateBudget
+			locale = policy.Annotations[messages.LocaleAnnotation]
+			data["Policy"] = policy.Name
+			data["Utilization"] = *policy.Status.BudgetUtilization
+			data["Period"] = budgetPeriodNoun(policy)
+			break
+		}
+	}
+	return []string{m.Messages.Render(locale, id, data)}
+}
+
+// budgetPeriodNoun names the policy's budget period in messages
+func budgetPeriodNoun(policy *kcloudv1alpha1.CostPolicy) string {
+	if policy.Spec.BudgetPeriod != nil && policy.Spec.BudgetPeriod.Period == kcloudv1alpha1.BudgetPeriodWeekly {
+		return "week"
+	}
+	return "month"
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+)
+
+// planRollbackWeight is the weight of the preference for the nodes a rolled back plan
+// moved pods off, the highest the scheduler accepts
+const planRollbackWeight = 100
+
+// applyPlanRollback prefers the nodes that rolling back OptimizationPlans moved pods of the
+// pod's controller off, so the replacements of the pods a rollback evicts land back on them
+func (m *PodMutator) applyPlanRollback(ctx context.Context, pod *corev1.Pod) error {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil
+	}
+	var plans kcloudv1alpha1.OptimizationPlanList
+	if err := m.Client.List(ctx, &plans); err != nil {
+		return fmt.Errorf("failed to list optimization plans: %w", err)
+	}
+	var nodes []string
+	for _, plan := range plans.Items {
+		if plan.Status.Phase != kcloudv1alpha1.PlanPhaseRollingBack {
+			continue
+		}
+		for _, step := range plan.Status.Steps {
+			if step.OwnerUID != owner.UID {
+				continue
+			}
+			for _, node := range step.FromNodes {
+				if !slices.Contains(nodes, node) {
+					nodes = append(nodes, node)
+				}
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	affinity := pod.Spec.Affinity.NodeAffinity
+	affinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.PreferredSchedulingTerm{
+			Weight: planRollbackWeight,
+			Preference: corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{{
+					Key:      metav1.ObjectNameField,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   nodes,
+				}},
+			},
+		})
+	return nil
+}
@@ -22,20 +22,35 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/messages"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
 )
 
 // PodMutator mutates pods to apply optimization policies
 type PodMutator struct {
-	Client  client.Client
-	decoder admission.Decoder
+	Client client.Client
+	// Accelerators maps the accelerator types workloads request to extended resources
+	Accelerators *optimizer.AcceleratorRegistry
+	// Pricing estimates the cost of pods covered by a CostPolicy for admission warnings; nil
+	// disables the warnings
+	Pricing optimizer.PricingProvider
+	// Currency is the reporting currency of the warnings; nil is US dollars
+	Currency *currency.Converter
+	// Messages renders the warnings; nil uses the built-in English catalog
+	Messages *messages.Catalog
+	decoder  admission.Decoder
 }
 
 // NewPodMutator creates a new pod mutator
@@ -59,26 +74,32 @@ func (m *PodMutator) Handle(ctx context.Context, req admission.Request) admissio
 		"pod", pod.Name,
 		"namespace", pod.Namespace)
 
+	// Steer replacements of pods an optimization plan rollback evicted back to their nodes,
+	// whether or not the pod is optimized
+	originalPod := pod.DeepCopy()
+	if err := m.applyPlanRollback(ctx, pod); err != nil {
+		logger.Error(err, "Failed to apply optimization plan rollback", "pod", pod.Name)
+	}
+
 	// Check if pod should be optimized
 	if !m.shouldOptimizePod(pod) {
 		logger.V(1).Info("Pod does not need optimization", "pod", pod.Name)
-		return admission.Allowed("No optimization needed")
+		return allowed(req, originalPod, pod, "No optimization needed")
 	}
 
 	// Find applicable WorkloadOptimizer
 	wo, err := m.findApplicableWorkloadOptimizer(ctx, pod)
 	if err != nil {
 		logger.Error(err, "Failed to find applicable WorkloadOptimizer")
-		return admission.Allowed("No WorkloadOptimizer found")
+		return allowed(req, originalPod, pod, "No WorkloadOptimizer found").WithWarnings(m.costWarnings(ctx, pod, nil)...)
 	}
 
 	if wo == nil {
 		logger.V(1).Info("No applicable WorkloadOptimizer found", "pod", pod.Name)
-		return admission.Allowed("No applicable WorkloadOptimizer")
+		return allowed(req, originalPod, pod, "No applicable WorkloadOptimizer").WithWarnings(m.costWarnings(ctx, pod, nil)...)
 	}
 
 	// Apply optimization to pod
-	originalPod := pod.DeepCopy()
 	if err := m.applyOptimizationToPod(pod, wo); err != nil {
 		logger.Error(err, "Failed to apply optimization to pod")
 		return admission.Errored(500, err)
@@ -96,6 +117,18 @@ func (m *PodMutator) Handle(ctx context.Context, req admission.Request) admissio
 		return admission.Errored(500, err)
 	}
 
+	return admission.PatchResponseFromRaw(req.Object.Raw, patch).WithWarnings(m.costWarnings(ctx, pod, wo)...)
+}
+
+// allowed admits the pod unchanged with the message, or with the changes already made to it
+func allowed(req admission.Request, original, pod *corev1.Pod, message string) admission.Response {
+	if equality.Semantic.DeepEqual(original, pod) {
+		return admission.Allowed(message)
+	}
+	patch, err := createPatch(original, pod)
+	if err != nil {
+		return admission.Errored(500, err)
+	}
 	return admission.PatchResponseFromRaw(req.Object.Raw, patch)
 }
 
@@ -123,6 +156,11 @@ func (m *PodMutator) shouldOptimizePod(pod *corev1.Pod) bool {
 		return false
 	}
 
+	// Skip pods with optimization disabled label, normally filtered by the webhook object selector
+	if pod.Labels[OptimizationDisabledLabel] == "true" {
+		return false
+	}
+
 	// Skip pods with optimization disabled annotation
 	if pod.Annotations != nil {
 		if disabled, exists := pod.Annotations["kcloud.io/optimization-disabled"]; exists && disabled == "true" {
@@ -291,7 +329,25 @@ func (m *PodMutator) resourcesMatch(pod *corev1.Pod, wo *kcloudv1alpha1.Workload
 	gpuMatch := totalGPU == wo.Spec.Resources.GPU
 	npuMatch := totalNPU == wo.Spec.Resources.NPU
 
-	return cpuMatch && memoryMatch && gpuMatch && npuMatch
+	// Registered accelerators must be requested in full as well; repeated types add up
+	wanted := map[corev1.ResourceName]int64{}
+	for _, accelerator := range wo.Spec.Resources.Accelerators {
+		if t, ok := m.Accelerators.Lookup(accelerator.Type); ok {
+			wanted[corev1.ResourceName(t.ResourceName)] += int64(accelerator.Count)
+		}
+	}
+	acceleratorsMatch := true
+	for name, count := range wanted {
+		var total int64
+		for _, container := range pod.Spec.Containers {
+			if q, exists := container.Resources.Requests[name]; exists {
+				total += q.Value()
+			}
+		}
+		acceleratorsMatch = acceleratorsMatch && total == count
+	}
+
+	return cpuMatch && memoryMatch && gpuMatch && npuMatch && acceleratorsMatch
 }
 
 // labelsMatch checks if pod labels match WorkloadOptimizer selector
@@ -313,6 +369,29 @@ func (m *PodMutator) applyOptimizationToPod(pod *corev1.Pod, wo *kcloudv1alpha1.
 	pod.Annotations["kcloud.io/workload-optimizer"] = wo.Name
 	pod.Annotations["kcloud.io/workload-type"] = wo.Spec.WorkloadType
 
+	// Propagate the dataset cache label so later workloads can find nodes holding the cache
+	if cache, exists := wo.Labels["kcloud.io/dataset-cache"]; exists {
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		pod.Labels["kcloud.io/dataset-cache"] = cache
+	}
+
+	// Carry the deadline so the scheduling queue can order pods earliest deadline first
+	if wo.Spec.Deadline != nil {
+		pod.Annotations[optimizer.DeadlineAnnotation] = wo.Spec.Deadline.CompleteBy.UTC().Format(time.RFC3339)
+	}
+
+	// Hold time-shifted workloads until the operator lets them run
+	if optimizer.TimeShiftHolds(wo) && !hasSchedulingGate(pod, optimizer.TimeShiftGate) {
+		pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, corev1.PodSchedulingGate{Name: optimizer.TimeShiftGate})
+	}
+
+	// Propagate the submitting user so cost is attributed to them rather than the API service account
+	if submittedBy, exists := wo.Annotations["kcloud.io/submitted-by"]; exists {
+		pod.Annotations["kcloud.io/submitted-by"] = submittedBy
+	}
+
 	// Apply resource optimization
 	if err := m.applyResourceOptimization(pod, wo); err != nil {
 		logger.Error(err, "Failed to apply resource optimization")
@@ -346,6 +425,34 @@ func (m *PodMutator) applyOptimizationToPod(pod *corev1.Pod, wo *kcloudv1alpha1.
 
 // applyResourceOptimization applies resource-related optimizations
 func (m *PodMutator) applyResourceOptimization(pod *corev1.Pod, wo *kcloudv1alpha1.WorkloadOptimizer) error {
+	// A packed workload's new pods take a replica of a shared GPU instead of a whole card
+	resources := wo.Spec.Resources
+	if packing := wo.Status.GPUPacking; packing != nil && scheduler.GPUPackable(wo) {
+		resources.GPU = 0
+		resources.GPUFraction = packing.Fraction
+	}
+	accelerators, err := m.Accelerators.Resources(resources.Accelerators)
+	if err != nil {
+		return err
+	}
+
+	// Record the GPU share and memory so the scheduler places the pod on a device with room for it
+	if resources.GPUFraction != "" {
+		pod.Annotations[scheduler.GPUFractionAnnotation] = resources.GPUFraction
+	}
+	if resources.GPUMemory != "" {
+		pod.Annotations[scheduler.GPUMemoryAnnotation] = resources.GPUMemory
+	}
+
+	// An applied QoS recommendation lowers the requests below the limits; it is ignored once
+	// the resources it was computed from changed
+	cpuRequest, memoryRequest := resources.CPU, resources.Memory
+	if qos := wo.Status.QoS; qos != nil && qos.Applied &&
+		qos.Limits.CPU == resources.CPU && qos.Limits.Memory == resources.Memory {
+		cpuRequest, memoryRequest = qos.Requests.CPU, qos.Requests.Memory
+		pod.Annotations["kcloud.io/qos-class"] = qos.Class
+	}
+
 	// Set resource requests and limits based on WorkloadOptimizer
 	for i := range pod.Spec.Containers {
 		container := &pod.Spec.Containers[i]
@@ -358,31 +465,60 @@ func (m *PodMutator) applyResourceOptimization(pod *corev1.Pod, wo *kcloudv1alph
 		}
 
 		// Set CPU
-		container.Resources.Requests[corev1.ResourceCPU] = resource.MustParse(wo.Spec.Resources.CPU)
-		container.Resources.Limits[corev1.ResourceCPU] = resource.MustParse(wo.Spec.Resources.CPU)
+		container.Resources.Requests[corev1.ResourceCPU] = resource.MustParse(cpuRequest)
+		container.Resources.Limits[corev1.ResourceCPU] = resource.MustParse(resources.CPU)
 
 		// Set Memory
-		container.Resources.Requests[corev1.ResourceMemory] = resource.MustParse(wo.Spec.Resources.Memory)
-		container.Resources.Limits[corev1.ResourceMemory] = resource.MustParse(wo.Spec.Resources.Memory)
+		container.Resources.Requests[corev1.ResourceMemory] = resource.MustParse(memoryRequest)
+		container.Resources.Limits[corev1.ResourceMemory] = resource.MustParse(resources.Memory)
 
 		// Set GPU if specified
-		if wo.Spec.Resources.GPU > 0 {
-			gpuResource := resource.MustParse(strconv.FormatInt(int64(wo.Spec.Resources.GPU), 10))
+		if resources.GPU > 0 {
+			gpuResource := resource.MustParse(strconv.FormatInt(int64(resources.GPU), 10))
 			container.Resources.Requests["nvidia.com/gpu"] = gpuResource
 			container.Resources.Limits["nvidia.com/gpu"] = gpuResource
 		}
 
+		// The device plugin assigns pods holding a GPU share a time-sliced or MPS replica
+		if resources.GPUFraction != "" {
+			container.Resources.Requests[scheduler.SharedGPUResource] = resource.MustParse("1")
+			container.Resources.Limits[scheduler.SharedGPUResource] = resource.MustParse("1")
+		}
+
+		// Set the MIG instance if specified
+		if resources.MIGProfile != "" {
+			migResource := corev1.ResourceName("nvidia.com/mig-" + resources.MIGProfile)
+			container.Resources.Requests[migResource] = resource.MustParse("1")
+			container.Resources.Limits[migResource] = resource.MustParse("1")
+		}
+
 		// Set NPU if specified
-		if wo.Spec.Resources.NPU > 0 {
-			npuResource := resource.MustParse(strconv.FormatInt(int64(wo.Spec.Resources.NPU), 10))
+		if resources.NPU > 0 {
+			npuResource := resource.MustParse(strconv.FormatInt(int64(resources.NPU), 10))
 			container.Resources.Requests["npu.com/npu"] = npuResource
 			container.Resources.Limits["npu.com/npu"] = npuResource
 		}
+
+		// Set registered accelerators if specified
+		for name, quantity := range accelerators {
+			container.Resources.Requests[name] = quantity.DeepCopy()
+			container.Resources.Limits[name] = quantity.DeepCopy()
+		}
 	}
 
 	return nil
 }
 
+// hasSchedulingGate reports whether the pod carries the scheduling gate
+func hasSchedulingGate(pod *corev1.Pod, name string) bool {
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // applyNodeSelectionOptimization applies node selection optimizations
 func (m *PodMutator) applyNodeSelectionOptimization(pod *corev1.Pod, wo *kcloudv1alpha1.WorkloadOptimizer) error {
 	if wo.Spec.PlacementPolicy == nil {
@@ -429,6 +565,33 @@ func (m *PodMutator) applyNodeSelectionOptimization(pod *corev1.Pod, wo *kcloudv
 		}
 	}
 
+	// Apply pod affinity and anti-affinity unless the pod declares its own
+	if wo.Spec.PlacementPolicy.PodAffinity != nil || wo.Spec.PlacementPolicy.PodAntiAffinity != nil {
+		if pod.Spec.Affinity == nil {
+			pod.Spec.Affinity = &corev1.Affinity{}
+		}
+		if pod.Spec.Affinity.PodAffinity == nil && wo.Spec.PlacementPolicy.PodAffinity != nil {
+			pod.Spec.Affinity.PodAffinity = wo.Spec.PlacementPolicy.PodAffinity.DeepCopy()
+		}
+		if pod.Spec.Affinity.PodAntiAffinity == nil && wo.Spec.PlacementPolicy.PodAntiAffinity != nil {
+			pod.Spec.Affinity.PodAntiAffinity = wo.Spec.PlacementPolicy.PodAntiAffinity.DeepCopy()
+		}
+	}
+
+	// Apply tolerations the pod does not already carry
+	for _, toleration := range wo.Spec.PlacementPolicy.Tolerations {
+		present := false
+		for i := range pod.Spec.Tolerations {
+			if pod.Spec.Tolerations[i].MatchToleration(&toleration) {
+				present = true
+				break
+			}
+		}
+		if !present {
+			pod.Spec.Tolerations = append(pod.Spec.Tolerations, toleration)
+		}
+	}
+
 	return nil
 }
 
@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"time"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -32,16 +34,24 @@ import (
 
 // WebhookConfig manages webhook configuration
 type WebhookConfig struct {
-	Client  client.Client
-	Scheme  *runtime.Scheme
-	decoder admission.Decoder
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Scope limits which pods reach the pod mutating webhook
+	Scope AdmissionScope
+	// ServiceName is the Service fronting the webhook server
+	ServiceName string
+	// CABundle is the PEM bundle the API server uses to verify the webhook server
+	CABundle []byte
+	decoder  admission.Decoder
 }
 
 // NewWebhookConfig creates a new webhook configuration
 func NewWebhookConfig(client client.Client, scheme *runtime.Scheme) *WebhookConfig {
 	return &WebhookConfig{
-		Client: client,
-		Scheme: scheme,
+		Client:      client,
+		Scheme:      scheme,
+		Scope:       DefaultAdmissionScope(),
+		ServiceName: "k8s-workload-operator-webhook-service",
 	}
 }
 
@@ -97,33 +107,33 @@ func (wc *WebhookConfig) CreateWebhookConfiguration(ctx context.Context, namespa
 	return nil
 }
 
-// createPodMutatingWebhookConfig creates the Pod mutating webhook configuration
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;list;watch;create;update;patch
+
+// createPodMutatingWebhookConfig creates or updates the Pod mutating webhook configuration,
+// restricted to the configured admission scope
 func (wc *WebhookConfig) createPodMutatingWebhookConfig(ctx context.Context, namespace string) error {
 	log := log.FromContext(ctx)
 
-	webhookConfig := &metav1.ObjectMeta{
-		Name:      "kcloud-pod-mutator",
-		Namespace: namespace,
-		Labels: map[string]string{
-			"app":                        "kcloud-operator",
-			"kcloud.io/component":        "webhook",
-			"kcloud.io/webhook-type":     "mutating",
-			"kcloud.io/webhook-resource": "pod",
-		},
-		Annotations: map[string]string{
+	desired := wc.Scope.PodMutatingWebhookConfiguration(wc.ServiceName, namespace, wc.CABundle)
+	existing := &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: desired.Name}}
+	result, err := controllerutil.CreateOrUpdate(ctx, wc.Client, existing, func() error {
+		existing.Labels = desired.Labels
+		existing.Annotations = map[string]string{
 			"kcloud.io/description": "Mutates pods to apply optimization policies",
 			"kcloud.io/version":     "v1alpha1",
-		},
+		}
+		existing.Webhooks = desired.Webhooks
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	log.V(1).Info("Created Pod mutating webhook configuration",
-		"name", webhookConfig.Name,
-		"namespace", webhookConfig.Namespace)
-
-	// In a real implementation, this would create the actual MutatingWebhookConfiguration resource
-	// For now, we'll just log the configuration
-	log.V(1).Info("Pod mutating webhook configuration", "config", webhookConfig)
-
+	log.Info("Pod mutating webhook configuration applied",
+		"name", desired.Name,
+		"result", result,
+		"namespaceLabel", wc.Scope.NamespaceLabel,
+		"acceleratorPodsOnly", wc.Scope.AcceleratorPodsOnly)
 	return nil
 }
 
@@ -19,21 +19,34 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	kcloudv1alpha1 "github.com/KETI-Cloud-Platform/k8s-workload-operator/api/v1alpha1"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/currency"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/optimizer"
+	"github.com/KETI-Cloud-Platform/k8s-workload-operator/pkg/scheduler"
 )
 
+// migProfilePattern matches MIG profile names such as 1g.5gb
+var migProfilePattern = regexp.MustCompile(`^[1-7]g\.[0-9]+gb$`)
+
 // WorkloadOptimizerValidator validates WorkloadOptimizer resources
 type WorkloadOptimizerValidator struct {
-	Client  client.Client
-	decoder admission.Decoder
+	Client client.Client
+	// Accelerators lists the accelerator types workloads may request
+	Accelerators *optimizer.AcceleratorRegistry
+	// Currency is the reporting currency cost constraints are written in; nil is US dollars
+	Currency *currency.Converter
+	decoder  admission.Decoder
 }
 
 // NewWorkloadOptimizerValidator creates a new WorkloadOptimizer validator
@@ -93,9 +106,30 @@ func (v *WorkloadOptimizerValidator) validateWorkloadOptimizer(ctx context.Conte
 	// Validate placement policy
 	errors = append(errors, v.validatePlacementPolicy(wo)...)
 
+	// Validate do-not-disturb lease
+	errors = append(errors, v.validateDoNotDisturb(wo)...)
+
+	// Validate constraint relaxation
+	errors = append(errors, v.validateConstraintRelaxation(wo)...)
+
 	// Validate auto-scaling
 	errors = append(errors, v.validateAutoScaling(wo)...)
 
+	// Validate recurrence
+	errors = append(errors, v.validateRecurrence(wo)...)
+
+	// Validate optimization interval
+	errors = append(errors, v.validateOptimizationInterval(wo)...)
+
+	// Validate SLA targets
+	errors = append(errors, v.validateSLA(wo)...)
+
+	// Validate deadline
+	errors = append(errors, v.validateDeadline(wo)...)
+
+	// Validate time shifting
+	errors = append(errors, v.validateTimeShift(wo)...)
+
 	// Validate priority
 	errors = append(errors, v.validatePriority(wo)...)
 
@@ -188,6 +222,44 @@ func (v *WorkloadOptimizerValidator) validateResources(wo *kcloudv1alpha1.Worklo
 		errors = append(errors, "GPU resource cannot exceed 16")
 	}
 
+	// Validate GPU sharing; a workload asks for whole GPUs, a fraction of one or a MIG instance
+	if fraction := wo.Spec.Resources.GPUFraction; fraction != "" {
+		quantity, err := resource.ParseQuantity(fraction)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("invalid GPU fraction '%s': %v", fraction, err))
+		} else if quantity.MilliValue() <= 0 || quantity.MilliValue() >= 1000 {
+			errors = append(errors, "GPU fraction must be greater than 0 and less than 1")
+		}
+	}
+	if profile := wo.Spec.Resources.MIGProfile; profile != "" && !migProfilePattern.MatchString(profile) {
+		errors = append(errors, fmt.Sprintf("invalid MIG profile '%s', expected a profile such as 1g.5gb", profile))
+	}
+	gpuRequests := 0
+	for _, set := range []bool{wo.Spec.Resources.GPU > 0, wo.Spec.Resources.GPUFraction != "", wo.Spec.Resources.MIGProfile != ""} {
+		if set {
+			gpuRequests++
+		}
+	}
+	if gpuRequests > 1 {
+		errors = append(errors, "only one of gpu, gpuFraction and migProfile may be set")
+	}
+	requestsGPU := gpuRequests > 0
+	if gpuMemory := wo.Spec.Resources.GPUMemory; gpuMemory != "" {
+		quantity, err := resource.ParseQuantity(gpuMemory)
+		switch {
+		case err != nil:
+			errors = append(errors, fmt.Sprintf("invalid GPU memory format '%s': %v", gpuMemory, err))
+		case quantity.Sign() <= 0:
+			errors = append(errors, "GPU memory must be greater than 0")
+		case !requestsGPU:
+			errors = append(errors, "gpuMemory requires gpu, gpuFraction or migProfile")
+		case wo.Spec.Resources.MIGProfile != "" && migProfilePattern.MatchString(wo.Spec.Resources.MIGProfile) &&
+			quantity.Value() > scheduler.MIGProfileMemory(wo.Spec.Resources.MIGProfile):
+			errors = append(errors, fmt.Sprintf("GPU memory %s exceeds the memory of MIG profile %s",
+				gpuMemory, wo.Spec.Resources.MIGProfile))
+		}
+	}
+
 	// Validate NPU
 	if wo.Spec.Resources.NPU < 0 {
 		errors = append(errors, "NPU resource cannot be negative")
@@ -196,14 +268,31 @@ func (v *WorkloadOptimizerValidator) validateResources(wo *kcloudv1alpha1.Worklo
 		errors = append(errors, "NPU resource cannot exceed 16")
 	}
 
+	// Validate registered accelerators
+	acceleratorTypes := make(map[string]bool, len(wo.Spec.Resources.Accelerators))
+	for _, accelerator := range wo.Spec.Resources.Accelerators {
+		if acceleratorTypes[accelerator.Type] {
+			errors = append(errors, fmt.Sprintf("accelerator '%s' is listed more than once", accelerator.Type))
+		}
+		acceleratorTypes[accelerator.Type] = true
+		if _, ok := v.Accelerators.Lookup(accelerator.Type); !ok {
+			errors = append(errors, fmt.Sprintf("unknown accelerator type '%s', registered types: %s",
+				accelerator.Type, registeredAcceleratorTypes(v.Accelerators)))
+		}
+		if accelerator.Count < 1 {
+			errors = append(errors, fmt.Sprintf("accelerator '%s' count must be at least 1", accelerator.Type))
+		}
+	}
+	requestsAccelerator := requestsGPU || wo.Spec.Resources.NPU > 0 || len(wo.Spec.Resources.Accelerators) > 0
+
 	// Validate GPU/NPU combination for different workload types
 	switch wo.Spec.WorkloadType {
 	case "training":
-		if wo.Spec.Resources.GPU == 0 && wo.Spec.Resources.NPU == 0 {
+		if !requestsAccelerator {
 			errors = append(errors, "training workloads typically require GPU or NPU acceleration")
 		}
 	case "inference":
-		if wo.Spec.Resources.GPU == 0 && wo.Spec.Resources.NPU == 0 {
+		if !requestsAccelerator {
 			errors = append(errors, "inference workloads typically require GPU or NPU acceleration")
 		}
 	}
@@ -223,8 +312,10 @@ func (v *WorkloadOptimizerValidator) validateCostConstraints(wo *kcloudv1alpha1.
 	if wo.Spec.CostConstraints.MaxCostPerHour <= 0 {
 		errors = append(errors, "costConstraints.maxCostPerHour must be greater than 0")
 	}
-	if wo.Spec.CostConstraints.MaxCostPerHour > 10000 {
-		errors = append(errors, "costConstraints.maxCostPerHour cannot exceed $10,000/hour")
+	// The caps are US dollar amounts, converted into the reporting currency
+	money := v.Currency.Currency()
+	if maxCost := v.Currency.FromUSD(10000); wo.Spec.CostConstraints.MaxCostPerHour > maxCost {
+		errors = append(errors, fmt.Sprintf("costConstraints.maxCostPerHour cannot exceed %s/hour", money.Format(maxCost)))
 	}
 
 	// Validate budgetLimit
@@ -232,8 +323,8 @@ func (v *WorkloadOptimizerValidator) validateCostConstraints(wo *kcloudv1alpha1.
 		if *wo.Spec.CostConstraints.BudgetLimit <= 0 {
 			errors = append(errors, "costConstraints.budgetLimit must be greater than 0")
 		}
-		if *wo.Spec.CostConstraints.BudgetLimit > 1000000 {
-			errors = append(errors, "costConstraints.budgetLimit cannot exceed $1,000,000")
+		if maxBudget := v.Currency.FromUSD(1000000); *wo.Spec.CostConstraints.BudgetLimit > maxBudget {
+			errors = append(errors, fmt.Sprintf("costConstraints.budgetLimit cannot exceed %s", money.Format(maxBudget)))
 		}
 
 		// Validate budget vs hourly cost relationship
@@ -267,6 +358,14 @@ func (v *WorkloadOptimizerValidator) validatePowerConstraints(wo *kcloudv1alpha1
 		errors = append(errors, fmt.Sprintf("estimated power consumption (%.0fW) exceeds maxPowerUsage (%.0fW)", estimatedPower, wo.Spec.PowerConstraints.MaxPowerUsage))
 	}
 
+	// Validate carbon caps
+	if limit := wo.Spec.PowerConstraints.MaxCarbonPerHour; limit != nil && *limit <= 0 {
+		errors = append(errors, "powerConstraints.maxCarbonPerHour must be greater than 0")
+	}
+	if budget := wo.Spec.PowerConstraints.CarbonBudget; budget != nil && *budget <= 0 {
+		errors = append(errors, "powerConstraints.carbonBudget must be greater than 0")
+	}
+
 	return errors
 }
 
@@ -319,6 +418,263 @@ func (v *WorkloadOptimizerValidator) validatePlacementPolicy(wo *kcloudv1alpha1.
 		}
 	}
 
+	// Validate tolerations
+	for i, toleration := range wo.Spec.PlacementPolicy.Tolerations {
+		switch toleration.Operator {
+		case "", corev1.TolerationOpEqual:
+			if toleration.Key == "" {
+				errors = append(errors, fmt.Sprintf("placementPolicy.tolerations[%d].key is required with operator Equal", i))
+			}
+		case corev1.TolerationOpExists:
+			if toleration.Value != "" {
+				errors = append(errors, fmt.Sprintf("placementPolicy.tolerations[%d].value must be empty with operator Exists", i))
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("invalid toleration operator '%s' at index %d", toleration.Operator, i))
+		}
+
+		switch toleration.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			errors = append(errors, fmt.Sprintf("invalid toleration effect '%s' at index %d", toleration.Effect, i))
+		}
+	}
+
+	// Validate pod affinity and anti-affinity terms
+	if podAffinity := wo.Spec.PlacementPolicy.PodAffinity; podAffinity != nil {
+		errors = append(errors, validatePodAffinityTerms("placementPolicy.podAffinity",
+			podAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			podAffinity.PreferredDuringSchedulingIgnoredDuringExecution)...)
+	}
+	if podAntiAffinity := wo.Spec.PlacementPolicy.PodAntiAffinity; podAntiAffinity != nil {
+		errors = append(errors, validatePodAffinityTerms("placementPolicy.podAntiAffinity",
+			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)...)
+	}
+
+	// Validate data endpoints
+	endpoints := make(map[string]bool, len(wo.Spec.PlacementPolicy.DataEndpoints))
+	for i, endpoint := range wo.Spec.PlacementPolicy.DataEndpoints {
+		if endpoint.Name == "" {
+			errors = append(errors, fmt.Sprintf("placementPolicy.dataEndpoints[%d].name is required", i))
+		} else if endpoints[endpoint.Name] {
+			errors = append(errors, fmt.Sprintf("duplicate data endpoint '%s'", endpoint.Name))
+		}
+		endpoints[endpoint.Name] = true
+		if endpoint.Zone == "" {
+			errors = append(errors, fmt.Sprintf("placementPolicy.dataEndpoints[%d].zone is required", i))
+		}
+		if endpoint.TransferGBPerHour < 0 {
+			errors = append(errors, fmt.Sprintf("placementPolicy.dataEndpoints[%d].transferGBPerHour cannot be negative", i))
+		}
+	}
+
+	return errors
+}
+
+// validatePodAffinityTerms validates required and preferred pod (anti-)affinity terms
+func validatePodAffinityTerms(path string, required []corev1.PodAffinityTerm,
+	preferred []corev1.WeightedPodAffinityTerm) []string {
+	var errors []string
+
+	validateTerm := func(termPath string, term corev1.PodAffinityTerm) {
+		if term.TopologyKey == "" {
+			errors = append(errors, fmt.Sprintf("%s.topologyKey is required", termPath))
+		}
+		if term.LabelSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(term.LabelSelector); err != nil {
+				errors = append(errors, fmt.Sprintf("%s.labelSelector is invalid: %v", termPath, err))
+			}
+		}
+		if term.NamespaceSelector != nil && (len(term.NamespaceSelector.MatchLabels) > 0 ||
+			len(term.NamespaceSelector.MatchExpressions) > 0) {
+			errors = append(errors, fmt.Sprintf("%s.namespaceSelector only supports {} to match all namespaces", termPath))
+		}
+	}
+
+	for i, term := range required {
+		validateTerm(fmt.Sprintf("%s.requiredDuringSchedulingIgnoredDuringExecution[%d]", path, i), term)
+	}
+	for i, weighted := range preferred {
+		termPath := fmt.Sprintf("%s.preferredDuringSchedulingIgnoredDuringExecution[%d]", path, i)
+		if weighted.Weight < 1 || weighted.Weight > 100 {
+			errors = append(errors, fmt.Sprintf("%s.weight must be between 1 and 100", termPath))
+		}
+		validateTerm(termPath+".podAffinityTerm", weighted.PodAffinityTerm)
+	}
+
+	return errors
+}
+
+// validateDoNotDisturb validates the do-not-disturb lease from the spec or annotation
+func (v *WorkloadOptimizerValidator) validateDoNotDisturb(wo *kcloudv1alpha1.WorkloadOptimizer) []string {
+	var errors []string
+
+	lease, err := optimizer.DoNotDisturbLease(wo)
+	if err != nil {
+		return append(errors, err.Error())
+	}
+	if lease == nil {
+		return errors
+	}
+
+	if lease.Until.After(time.Now().Add(optimizer.MaxDoNotDisturbLease)) {
+		errors = append(errors, fmt.Sprintf("do-not-disturb lease may not extend more than %s ahead",
+			optimizer.MaxDoNotDisturbLease))
+	}
+	if len(lease.Reason) > 256 {
+		errors = append(errors, "do-not-disturb reason must be at most 256 characters")
+	}
+
+	return errors
+}
+
+// validateConstraintRelaxation validates relaxation steps and that they widen declared caps
+func (v *WorkloadOptimizerValidator) validateConstraintRelaxation(wo *kcloudv1alpha1.WorkloadOptimizer) []string {
+	var errors []string
+
+	relaxation := wo.Spec.ConstraintRelaxation
+	if relaxation == nil {
+		return errors
+	}
+	if len(relaxation.Steps) == 0 {
+		errors = append(errors, "constraintRelaxation requires at least one step")
+	}
+	if len(relaxation.Steps) > 10 {
+		errors = append(errors, "constraintRelaxation allows at most 10 steps")
+	}
+
+	for i, step := range relaxation.Steps {
+		switch step.Constraint {
+		case "cost":
+			if wo.Spec.CostConstraints == nil {
+				errors = append(errors, fmt.Sprintf("constraintRelaxation.steps[%d] relaxes cost but costConstraints is not set", i))
+			}
+		case "power":
+			if wo.Spec.PowerConstraints == nil {
+				errors = append(errors, fmt.Sprintf("constraintRelaxation.steps[%d] relaxes power but powerConstraints is not set", i))
+			}
+		case "carbon":
+			if wo.Spec.PowerConstraints == nil || wo.Spec.PowerConstraints.MaxCarbonPerHour == nil {
+				errors = append(errors, fmt.Sprintf("constraintRelaxation.steps[%d] relaxes carbon but powerConstraints.maxCarbonPerHour is not set", i))
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("constraintRelaxation.steps[%d].constraint must be cost, power or carbon", i))
+		}
+		if step.Percent < 1 || step.Percent > 100 {
+			errors = append(errors, fmt.Sprintf("constraintRelaxation.steps[%d].percent must be between 1 and 100", i))
+		}
+	}
+
+	return errors
+}
+
+// validateRecurrence validates the recurring run schedule
+func (v *WorkloadOptimizerValidator) validateRecurrence(wo *kcloudv1alpha1.WorkloadOptimizer) []string {
+	var errors []string
+
+	recurrence := wo.Spec.Recurrence
+	if recurrence == nil {
+		return errors
+	}
+
+	if recurrence.Interval.Duration < time.Minute {
+		errors = append(errors, "recurrence.interval must be at least 1m")
+	}
+
+	if recurrence.LeadTime != nil && recurrence.LeadTime.Duration >= recurrence.Interval.Duration {
+		errors = append(errors, "recurrence.leadTime must be shorter than recurrence.interval")
+	}
+
+	return errors
+}
+
+// validateOptimizationInterval validates how often the workload is re-optimized
+func (v *WorkloadOptimizerValidator) validateOptimizationInterval(wo *kcloudv1alpha1.WorkloadOptimizer) []string {
+	var errors []string
+
+	if wo.Spec.OptimizationInterval == "" {
+		return errors
+	}
+	if _, err := optimizer.ParseOptimizationInterval(wo.Spec.OptimizationInterval); err != nil {
+		errors = append(errors, fmt.Sprintf("optimizationInterval: %v", err))
+	}
+
+	return errors
+}
+
+// validateSLA validates the latency and throughput targets of inference workloads
+func (v *WorkloadOptimizerValidator) validateSLA(wo *kcloudv1alpha1.WorkloadOptimizer) []string {
+	var errors []string
+
+	sla := wo.Spec.SLA
+	if sla == nil {
+		return errors
+	}
+	if wo.Spec.WorkloadType != "inference" {
+		errors = append(errors, "sla is only supported for inference workloads")
+	}
+	if sla.LatencyP99 == nil && sla.Throughput == nil {
+		errors = append(errors, "sla must set latencyP99, throughput or both")
+	}
+	if sla.LatencyP99 != nil && sla.LatencyP99.Duration <= 0 {
+		errors = append(errors, "sla.latencyP99 must be greater than 0")
+	}
+	if sla.Throughput != nil && *sla.Throughput <= 0 {
+		errors = append(errors, "sla.throughput must be greater than 0")
+	}
+
+	return errors
+}
+
+// validateDeadline validates the deadline of batch and training workloads
+func (v *WorkloadOptimizerValidator) validateDeadline(wo *kcloudv1alpha1.WorkloadOptimizer) []string {
+	var errors []string
+
+	deadline := wo.Spec.Deadline
+	if deadline == nil {
+		return errors
+	}
+	if wo.Spec.WorkloadType != "batch" && wo.Spec.WorkloadType != "training" {
+		errors = append(errors, "deadline is only supported for batch and training workloads")
+	}
+	if deadline.CompleteBy.IsZero() {
+		errors = append(errors, "deadline.completeBy is required")
+	}
+	if deadline.ExpectedDuration != nil && deadline.ExpectedDuration.Duration <= 0 {
+		errors = append(errors, "deadline.expectedDuration must be greater than 0")
+	}
+
+	return errors
+}
+
+// validateTimeShift validates time shifting of batch and training workloads with deadlines
+func (v *WorkloadOptimizerValidator) validateTimeShift(wo *kcloudv1alpha1.WorkloadOptimizer) []string {
+	var errors []string
+
+	timeShift := wo.Spec.TimeShift
+	if timeShift == nil {
+		return errors
+	}
+	if wo.Spec.WorkloadType != "batch" && wo.Spec.WorkloadType != "training" {
+		errors = append(errors, "timeShift is only supported for batch and training workloads")
+	}
+	if wo.Spec.Deadline == nil {
+		errors = append(errors, "timeShift requires a deadline to resume before")
+	}
+	switch timeShift.Signal {
+	case "", kcloudv1alpha1.TimeShiftSignalCarbonIntensity, kcloudv1alpha1.TimeShiftSignalElectricityPrice:
+	default:
+		errors = append(errors, fmt.Sprintf("timeShift.signal must be %s or %s",
+			kcloudv1alpha1.TimeShiftSignalCarbonIntensity, kcloudv1alpha1.TimeShiftSignalElectricityPrice))
+	}
+	if timeShift.Threshold < 0 {
+		errors = append(errors, "timeShift.threshold must not be negative")
+	}
+	if timeShift.SafetyMargin != nil && timeShift.SafetyMargin.Duration < 0 {
+		errors = append(errors, "timeShift.safetyMargin must not be negative")
+	}
+
 	return errors
 }
 
@@ -457,11 +813,24 @@ func (v *WorkloadOptimizerValidator) estimatePowerFromResources(resources kcloud
 		cpuCores*cpuPowerPerCore +
 		memoryGB*memoryPowerPerGB +
 		float64(resources.GPU)*gpuPowerPerUnit +
-		float64(resources.NPU)*npuPowerPerUnit
+		float64(resources.NPU)*npuPowerPerUnit +
+		v.Accelerators.Power(resources.Accelerators)
 
 	return totalPower
 }
 
+// registeredAcceleratorTypes lists the registered accelerator type names for error messages
+func registeredAcceleratorTypes(registry *optimizer.AcceleratorRegistry) string {
+	var names []string
+	for _, t := range registry.Types() {
+		names = append(names, t.Name)
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
 // InjectDecoder injects the decoder
 func (v *WorkloadOptimizerValidator) InjectDecoder(d admission.Decoder) error {
 	v.decoder = d